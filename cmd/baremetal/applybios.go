@@ -0,0 +1,128 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	applyBIOSLong = `
+Apply the BIOS settings defined by the BIOSSettings documents of a phase to the
+selected baremetal hosts via Redfish. A BIOSSettings document names its host in
+spec.host and lists the desired BIOS attributes in spec.attributes:
+
+  apiVersion: airshipit.org/v1alpha1
+  kind: BIOSSettings
+  metadata:
+    name: node01-bios
+  spec:
+    host: node01
+    attributes:
+      ProcVirtualization: Enabled
+
+Only attributes whose value differs are changed. The hosts are then rebooted
+for the BIOS to apply the settings, and the settings are verified once the
+hosts are back, for at most --verify-timeout. With --no-reboot the settings
+stay pending until the next reboot. Hosts without BIOS settings are skipped.
+The outcome on every host is printed in the format given by --output.
+`
+
+	applyBIOSExample = `
+# Apply the BIOS settings of node01
+airshipctl baremetal apply-bios --name node01
+
+# Stage the BIOS settings of all worker hosts, to be applied on their next reboot
+airshipctl baremetal apply-bios -l airshipit.org/k8s-role=worker --no-reboot
+`
+)
+
+// NewApplyBIOSCommand provides a command to apply the BIOS settings of baremetal hosts defined in documents.
+func NewApplyBIOSCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var maxWorkers int
+	var noReboot bool
+	var verifyTimeout time.Duration
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "apply-bios",
+		Short:   "Apply the BIOS settings of baremetal hosts defined in documents",
+		Long:    applyBIOSLong[1:],
+		Example: applyBIOSExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(output, remote.OutputTable, remote.OutputJSON,
+				remote.OutputYAML); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
+
+			hostSettings, err := remote.BIOSSettings(rootSettings, phase)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			opts := remote.DefaultBIOSOptions()
+			opts.Reboot = !noReboot
+			opts.VerifyTimeout = verifyTimeout
+
+			results := m.ApplyBIOSSettings(maxWorkers, hostSettings, opts)
+			if err = remote.PrintBIOSResults(cmd.OutOrStdout(), results, opts, output); err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, result := range results {
+				if result.Err != nil {
+					failed = append(failed, result.HostName)
+				}
+			}
+
+			if len(failed) > 0 {
+				return remote.ErrHostActionFailed{Hosts: failed, Total: len(results)}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.BoolVar(&noReboot, "no-reboot", false, "stage the settings without rebooting the hosts to apply them")
+	flags.DurationVar(&verifyTimeout, "verify-timeout", remote.DefaultBIOSVerifyTimeout,
+		"how long to wait for the settings to take effect after the reboot of a host")
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagReportOutputDescription)
+
+	return cmd
+}
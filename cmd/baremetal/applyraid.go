@@ -0,0 +1,128 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	applyRAIDLong = `
+Create the RAID volumes defined by the RAIDConfig documents of a phase on the
+storage controllers of the selected baremetal hosts via Redfish, before they are
+provisioned. A RAIDConfig document names its host in spec.host, optionally the
+storage controller in spec.controller, and lists the volumes in spec.volumes:
+
+  apiVersion: airshipit.org/v1alpha1
+  kind: RAIDConfig
+  metadata:
+    name: node01-raid
+  spec:
+    host: node01
+    controller: RAID.Integrated.1-1
+    volumes:
+      - name: os
+        raidType: RAID1
+        drives:
+          - Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1
+          - Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1
+
+Volumes are matched by name. Volumes that exist with the same RAID type and
+drives are left alone, so the command can be run again safely. A volume that
+exists with another RAID type or other drives fails the host without changing
+it. With --prune, volumes not declared are deleted. Some BMCs only create the
+volumes on the next reboot of the host. Hosts without RAID config are skipped.
+The outcome on every host is printed in the format given by --output.
+`
+
+	applyRAIDExample = `
+# Create the RAID volumes of node01
+airshipctl baremetal apply-raid --name node01
+
+# Show the RAID volumes that would be created and deleted on all worker hosts
+airshipctl baremetal apply-raid -l airshipit.org/k8s-role=worker --prune --dry-run
+`
+)
+
+// NewApplyRAIDCommand provides a command to create the RAID volumes of baremetal hosts defined in documents.
+func NewApplyRAIDCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var maxWorkers int
+	var opts remote.RAIDOptions
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "apply-raid",
+		Short:   "Create the RAID volumes of baremetal hosts defined in documents",
+		Long:    applyRAIDLong[1:],
+		Example: applyRAIDExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(output, remote.OutputTable, remote.OutputJSON,
+				remote.OutputYAML); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
+
+			configs, err := remote.RAIDConfigs(rootSettings, phase)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			results := m.ApplyRAIDConfigs(maxWorkers, configs, opts)
+			if err = remote.PrintRAIDResults(cmd.OutOrStdout(), results, opts, output); err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, result := range results {
+				if result.Err != nil {
+					failed = append(failed, result.HostName)
+				}
+			}
+
+			if len(failed) > 0 {
+				return remote.ErrHostActionFailed{Hosts: failed, Total: len(results)}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.BoolVar(&opts.Prune, "prune", false, "delete the volumes of the storage controller that are not declared")
+	flags.BoolVar(&opts.DryRun, "dry-run", false,
+		"show the volumes that would be created and deleted without changing them")
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagReportOutputDescription)
+
+	return cmd
+}
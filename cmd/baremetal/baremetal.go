@@ -16,6 +16,7 @@ package baremetal
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/log"
@@ -31,8 +32,30 @@ const (
 	flagNameShort       = "n"
 	flagNameDescription = "Name to filter desired baremetal host document"
 
+	flagNamespace            = "namespace"
+	flagNamespaceDescription = "Namespace to filter desired baremetal host documents"
+
+	flagAll            = "all"
+	flagAllDescription = "Select all baremetal hosts. Actions that disrupt hosts, e.g. poweroff, require this flag" +
+		" when no other selector is given"
+
 	flagPhase            = "phase"
 	flagPhaseDescription = "airshipctl phase that contains the desired baremetal host document(s)"
+
+	flagMaxWorkers            = "max-workers"
+	flagMaxWorkersDescription = "maximum number of hosts to perform the action on in parallel, 0 for all hosts at once"
+	defaultMaxWorkers         = 10
+
+	flagOutput            = "output"
+	flagOutputShort       = "o"
+	flagOutputDescription = `output format, "table" or "json"`
+	// flagReportOutputDescription describes the output flag of the commands reporting per host results that can
+	// also be printed as YAML
+	flagReportOutputDescription = `output format, "table", "json" or "yaml"`
+
+	flagDryRun            = "dry-run"
+	flagDryRunDescription = "only verify that the BMCs can perform the action and print the operations it would" +
+		" perform, without changing the hosts"
 )
 
 // NewBaremetalCommand creates a new command for interacting with baremetal using airshipctl.
@@ -41,19 +64,45 @@ func NewBaremetalCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Co
 		Use:   "baremetal",
 		Short: "Perform actions on baremetal hosts",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			log.Init(rootSettings.Debug, cmd.OutOrStderr())
-
 			// Load or Initialize airship Config
 			rootSettings.InitConfig()
+			log.Init(rootSettings.Debug, cmd.OutOrStderr())
 		},
 	}
 
+	applyBIOSCmd := NewApplyBIOSCommand(rootSettings)
+	baremetalRootCmd.AddCommand(applyBIOSCmd)
+
+	applyRAIDCmd := NewApplyRAIDCommand(rootSettings)
+	baremetalRootCmd.AddCommand(applyRAIDCmd)
+
+	bootSourceCmd := NewBootSourceCommand(rootSettings)
+	baremetalRootCmd.AddCommand(bootSourceCmd)
+
+	consoleCmd := NewConsoleCommand(rootSettings)
+	baremetalRootCmd.AddCommand(consoleCmd)
+
+	consoleLogCmd := NewConsoleLogCommand(rootSettings)
+	baremetalRootCmd.AddCommand(consoleLogCmd)
+
 	ejectMediaCmd := NewEjectMediaCommand(rootSettings)
 	baremetalRootCmd.AddCommand(ejectMediaCmd)
 
+	healthCheckCmd := NewHealthCheckCommand(rootSettings)
+	baremetalRootCmd.AddCommand(healthCheckCmd)
+
+	insertMediaCmd := NewInsertMediaCommand(rootSettings)
+	baremetalRootCmd.AddCommand(insertMediaCmd)
+
+	inventoryCmd := NewInventoryCommand(rootSettings)
+	baremetalRootCmd.AddCommand(inventoryCmd)
+
 	isoGenCmd := NewISOGenCommand(rootSettings)
 	baremetalRootCmd.AddCommand(isoGenCmd)
 
+	listHostsCmd := NewListHostsCommand(rootSettings)
+	baremetalRootCmd.AddCommand(listHostsCmd)
+
 	powerOffCmd := NewPowerOffCommand(rootSettings)
 	baremetalRootCmd.AddCommand(powerOffCmd)
 
@@ -69,6 +118,9 @@ func NewBaremetalCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Co
 	remoteDirectCmd := NewRemoteDirectCommand(rootSettings)
 	baremetalRootCmd.AddCommand(remoteDirectCmd)
 
+	secureBootCmd := NewSecureBootCommand(rootSettings)
+	baremetalRootCmd.AddCommand(secureBootCmd)
+
 	return baremetalRootCmd
 }
 
@@ -86,3 +138,96 @@ func GetHostSelections(name string, labels string) []remote.HostSelector {
 
 	return selectors
 }
+
+// hostSelection holds the values of the flags that select the baremetal hosts a command acts on.
+type hostSelection struct {
+	name      string
+	labels    string
+	namespace string
+	all       bool
+}
+
+// addFlags adds the host selection flags to flags.
+func (s *hostSelection) addFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&s.labels, flagLabel, flagLabelShort, "", flagLabelDescription)
+	flags.StringVarP(&s.name, flagName, flagNameShort, "", flagNameDescription)
+	flags.StringVar(&s.namespace, flagNamespace, "", flagNamespaceDescription)
+	flags.BoolVar(&s.all, flagAll, false, flagAllDescription)
+}
+
+// selectors builds the list of selectors that can be passed to a manager from the host selection flags. Hosts must
+// match all given selectors. If none is given all hosts are selected, unless explicit is set: actions that disrupt
+// hosts require them to be selected explicitly, by the other selectors or --all.
+func (s hostSelection) selectors(explicit bool) ([]remote.HostSelector, error) {
+	selectors := GetHostSelections(s.name, s.labels)
+	if s.namespace != "" {
+		selectors = append(selectors, remote.ByNamespace(s.namespace))
+	}
+
+	if len(selectors) == 0 {
+		if explicit && !s.all {
+			return nil, remote.ErrNoHostSelector{}
+		}
+		selectors = append(selectors, remote.All())
+	}
+
+	return selectors, nil
+}
+
+// actionFlags holds the values of the flags shared by the commands that change the state of hosts.
+type actionFlags struct {
+	maxWorkers int
+	dryRun     bool
+	output     string
+}
+
+// addFlags adds the action flags to flags.
+func (f *actionFlags) addFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&f.maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.BoolVar(&f.dryRun, flagDryRun, false, flagDryRunDescription)
+	flags.StringVarP(&f.output, flagOutput, flagOutputShort, remote.OutputTable, flagReportOutputDescription)
+}
+
+// checkOutputFormat returns remote.ErrInvalidOutputFormat unless format is one of formats.
+func checkOutputFormat(format string, formats ...string) error {
+	for _, f := range formats {
+		if format == f {
+			return nil
+		}
+	}
+	return remote.ErrInvalidOutputFormat{Format: format, Formats: formats}
+}
+
+// runHostAction performs action on the hosts of m, on at most f.maxWorkers hosts in parallel. The outcome on every
+// host, described as operation, is written in the format of f.output once the action finished on all of them. With
+// f.dryRun the hosts are left untouched, see remote.Manager.DryRun; the operations that would be performed go to
+// stderr when the outcomes are written as JSON or YAML, so that stdout stays valid JSON or YAML.
+func runHostAction(cmd *cobra.Command, m *remote.Manager, f actionFlags, operation string,
+	action remote.HostAction) error {
+	if f.dryRun {
+		out := cmd.OutOrStdout()
+		if f.output != remote.OutputTable {
+			out = cmd.ErrOrStderr()
+		}
+		m.DryRun(out)
+		operation += " (dry run)"
+	}
+
+	results := m.RunAction(f.maxWorkers, action)
+	if err := remote.PrintResults(cmd.OutOrStdout(), operation, results, f.output); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.HostName)
+		}
+	}
+
+	if len(failed) > 0 {
+		return remote.ErrHostActionFailed{Hosts: failed, Total: len(results)}
+	}
+
+	return nil
+}
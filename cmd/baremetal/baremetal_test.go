@@ -20,6 +20,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"opendev.org/airship/airshipctl/cmd/baremetal"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -30,21 +32,123 @@ func TestBaremetal(t *testing.T) {
 			CmdLine: "-h",
 			Cmd:     baremetal.NewBaremetalCommand(nil),
 		},
+		{
+			Name:    "baremetal-apply-bios-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewApplyBIOSCommand(nil),
+		},
+		{
+			Name:    "baremetal-apply-bios-without-selector",
+			CmdLine: "",
+			Cmd:     baremetal.NewApplyBIOSCommand(nil),
+			Error:   remote.ErrNoHostSelector{},
+		},
+		{
+			Name:    "baremetal-apply-raid-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewApplyRAIDCommand(nil),
+		},
+		{
+			Name:    "baremetal-apply-raid-without-selector",
+			CmdLine: "--prune",
+			Cmd:     baremetal.NewApplyRAIDCommand(nil),
+			Error:   remote.ErrNoHostSelector{},
+		},
+		{
+			Name:    "baremetal-bootsource-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBootSourceCommand(nil),
+		},
+		{
+			Name:    "baremetal-bootsource-invalid-source",
+			CmdLine: "floppy",
+			Cmd:     baremetal.NewBootSourceCommand(nil),
+			Error:   boot.ErrUnknownSource{Source: "floppy"},
+		},
+		{
+			Name:    "baremetal-console-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewConsoleCommand(nil),
+		},
+		{
+			Name:    "baremetal-console-log-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewConsoleLogCommand(nil),
+		},
 		{
 			Name:    "baremetal-ejectmedia-with-help",
 			CmdLine: "-h",
 			Cmd:     baremetal.NewEjectMediaCommand(nil),
 		},
+		{
+			Name:    "baremetal-healthcheck-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewHealthCheckCommand(nil),
+		},
+		{
+			Name:    "baremetal-healthcheck-invalid-output",
+			CmdLine: "-o csv",
+			Cmd:     baremetal.NewHealthCheckCommand(nil),
+			Error: remote.ErrInvalidOutputFormat{
+				Format:  "csv",
+				Formats: []string{remote.OutputTable, remote.OutputJSON},
+			},
+		},
+		{
+			Name:    "baremetal-insertmedia-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewInsertMediaCommand(nil),
+		},
+		{
+			Name:    "baremetal-insertmedia-invalid-url",
+			CmdLine: "/srv/images/ephemeral.iso --all",
+			Cmd:     baremetal.NewInsertMediaCommand(nil),
+			Error:   remote.ErrInvalidISOURL{URL: "/srv/images/ephemeral.iso"},
+		},
+		{
+			Name:    "baremetal-inventory-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewInventoryCommand(nil),
+		},
+		{
+			Name:    "baremetal-inventory-invalid-output",
+			CmdLine: "-o table",
+			Cmd:     baremetal.NewInventoryCommand(nil),
+			Error: remote.ErrInvalidOutputFormat{
+				Format:  "table",
+				Formats: []string{remote.OutputJSON, remote.OutputCSV},
+			},
+		},
 		{
 			Name:    "baremetal-isogen-with-help",
 			CmdLine: "-h",
 			Cmd:     baremetal.NewISOGenCommand(nil),
 		},
+		{
+			Name:    "baremetal-list-hosts-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewListHostsCommand(nil),
+		},
 		{
 			Name:    "baremetal-poweroff-with-help",
 			CmdLine: "-h",
 			Cmd:     baremetal.NewPowerOffCommand(nil),
 		},
+		{
+			Name:    "baremetal-poweroff-without-selector",
+			CmdLine: "",
+			Cmd:     baremetal.NewPowerOffCommand(nil),
+			Error:   remote.ErrNoHostSelector{},
+		},
+		{
+			Name:    "baremetal-poweroff-invalid-output",
+			CmdLine: "--all -o csv",
+			Cmd:     baremetal.NewPowerOffCommand(nil),
+			Error: remote.ErrInvalidOutputFormat{
+				Format:  "csv",
+				Formats: []string{remote.OutputTable, remote.OutputJSON, remote.OutputYAML},
+			},
+		},
 		{
 			Name:    "baremetal-poweron-with-help",
 			CmdLine: "-h",
@@ -55,6 +159,15 @@ func TestBaremetal(t *testing.T) {
 			CmdLine: "-h",
 			Cmd:     baremetal.NewPowerStatusCommand(nil),
 		},
+		{
+			Name:    "baremetal-powerstatus-invalid-output",
+			CmdLine: "-o csv",
+			Cmd:     baremetal.NewPowerStatusCommand(nil),
+			Error: remote.ErrInvalidOutputFormat{
+				Format:  "csv",
+				Formats: []string{remote.OutputTable, remote.OutputJSON, remote.OutputYAML},
+			},
+		},
 		{
 			Name:    "baremetal-reboot-with-help",
 			CmdLine: "-h",
@@ -65,6 +178,22 @@ func TestBaremetal(t *testing.T) {
 			CmdLine: "-h",
 			Cmd:     baremetal.NewRemoteDirectCommand(nil),
 		},
+		{
+			Name:    "baremetal-secureboot-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewSecureBootCommand(nil),
+		},
+		{
+			Name:    "baremetal-secureboot-enable-with-help",
+			CmdLine: "enable -h",
+			Cmd:     baremetal.NewSecureBootCommand(nil),
+		},
+		{
+			Name:    "baremetal-secureboot-disable-without-selector",
+			CmdLine: "disable",
+			Cmd:     baremetal.NewSecureBootCommand(nil),
+			Error:   remote.ErrNoHostSelector{},
+		},
 	}
 
 	for _, tt := range tests {
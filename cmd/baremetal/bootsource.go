@@ -0,0 +1,92 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+const (
+	bootSourceLong = `
+Set the device a baremetal host boots from, one of "cd", "disk" or "pxe". The
+boot source is only used for the next boot unless --persistent is given, after
+which the host returns to its default boot order.
+`
+
+	bootSourceExample = `
+# Boot node01 from its disk from now on, e.g. once an ephemeral node was installed
+airshipctl baremetal bootsource disk --name node01 --persistent
+
+# Boot all hosts of the bootstrap phase from the network once
+airshipctl baremetal bootsource pxe --all
+`
+)
+
+// NewBootSourceCommand provides a command to set the boot source of baremetal hosts.
+func NewBootSourceCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var opts actionFlags
+	var persistent bool
+
+	cmd := &cobra.Command{
+		Use:     "bootsource SOURCE",
+		Short:   "Set the device a baremetal host boots from",
+		Long:    bootSourceLong[1:],
+		Example: bootSourceExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(opts.output, remote.OutputTable, remote.OutputJSON, remote.OutputYAML); err != nil {
+				return err
+			}
+
+			source, err := boot.ParseSource(args[0])
+			if err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			operation := fmt.Sprintf("set boot source to '%s'", source)
+			return runHostAction(cmd, m, opts, operation, func(ctx context.Context, client remote.Client) error {
+				return client.SetBootSource(ctx, source, persistent)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	opts.addFlags(flags)
+	flags.BoolVar(&persistent, "persistent", false, "keep booting from the source instead of using it only once")
+
+	return cmd
+}
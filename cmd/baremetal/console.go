@@ -0,0 +1,101 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	consoleLong = `
+Print the URL of the graphical console (KVM) of a baremetal host in the web UI
+of its BMC, or open it in the default browser with --open. The URL points to the
+virtual console page of iDRAC and iLO BMCs, and to the home page of the web UI
+of other Redfish BMCs. The BMC asks for its credentials when the URL is opened.
+`
+
+	consoleExample = `
+# Print the console URL of node01
+airshipctl baremetal console node01
+
+# Open the console of node01 in the browser
+airshipctl baremetal console node01 --open
+`
+)
+
+// NewConsoleCommand provides a command to print or open the URL of the graphical console of a baremetal host.
+func NewConsoleCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var phase string
+	var open bool
+
+	cmd := &cobra.Command{
+		Use:     "console HOST",
+		Short:   "Print or open the URL of the graphical console of a baremetal host",
+		Long:    consoleLong[1:],
+		Example: consoleExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := remote.NewManager(rootSettings, phase, remote.ByName(args[0]))
+			if err != nil {
+				return err
+			}
+
+			action := remote.ConsoleURL(func(url string) error {
+				fmt.Fprintln(cmd.OutOrStdout(), url)
+				if open {
+					return openBrowser(url)
+				}
+				return nil
+			})
+			for _, result := range m.RunAction(1, action) {
+				if result.Err != nil {
+					return result.Err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.BoolVar(&open, "open", false, "open the console in the default browser")
+
+	return cmd
+}
+
+// openBrowser opens url in the default browser of the desktop.
+func openBrowser(url string) error {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler"}
+	default:
+		name = "xdg-open"
+	}
+
+	return exec.Command(name, append(args, url)...).Start()
+}
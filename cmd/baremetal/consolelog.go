@@ -0,0 +1,80 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	consoleLogLong = `
+Print the serial console output of a baremetal host, e.g. to find out why the
+ephemeral host failed to boot. BMCs that buffer the console, such as OpenBMC,
+return the buffered output right away. IPMI BMCs don't buffer it, so the console
+is followed over Serial-over-LAN for --duration, or until interrupted if it is
+0, and only the output produced meanwhile is printed.
+`
+
+	consoleLogExample = `
+# Print the serial console log of node01
+airshipctl baremetal console-log node01
+
+# Follow the console of the ephemeral host, managed over IPMI, while it boots
+airshipctl baremetal console-log ephemeral-0 --duration 0
+`
+
+	defaultConsoleLogDuration = time.Minute
+)
+
+// NewConsoleLogCommand provides a command to print the serial console output of a baremetal host.
+func NewConsoleLogCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var phase string
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:     "console-log HOST",
+		Short:   "Print the serial console output of a baremetal host",
+		Long:    consoleLogLong[1:],
+		Example: consoleLogExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := remote.NewManager(rootSettings, phase, remote.ByName(args[0]))
+			if err != nil {
+				return err
+			}
+
+			for _, result := range m.RunAction(1, remote.ConsoleLog(cmd.OutOrStdout(), duration)) {
+				if result.Err != nil {
+					return result.Err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.DurationVar(&duration, "duration", defaultConsoleLogDuration,
+		"how long to follow the console of hosts whose BMC doesn't buffer it, 0 to follow it until interrupted")
+
+	return cmd
+}
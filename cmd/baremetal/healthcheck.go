@@ -0,0 +1,105 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	healthCheckLong = `
+Validate that the selected baremetal hosts are ready to be deployed by running
+a series of read-only checks against their BMCs, and report whether each check
+passed, failed or was skipped:
+
+  bmc-reachable  the BMC answers Redfish requests
+  credentials    the BMC accepts the credentials of the host
+  power-state    the power state of the host can be read
+  virtual-media  the BMC can insert an ISO as virtual CD or DVD
+
+The other checks of a host are skipped when the BMC is unreachable or rejects
+the credentials, and so are the checks its management type can't run, e.g.
+bmc-reachable over IPMI. The command fails if any check failed.
+`
+
+	healthCheckExample = `
+# Check all hosts of the bootstrap phase
+airshipctl baremetal healthcheck
+
+# Check the worker hosts of the initinfra phase and report the results as JSON
+airshipctl baremetal healthcheck --phase initinfra -l airshipit.org/k8s-role=worker -o json
+`
+)
+
+// NewHealthCheckCommand provides a command to check that baremetal hosts are ready to be deployed.
+func NewHealthCheckCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var maxWorkers int
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "healthcheck",
+		Short:   "Check that the BMCs of baremetal hosts are ready for deployment",
+		Long:    healthCheckLong[1:],
+		Example: healthCheckExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(output, remote.OutputTable, remote.OutputJSON); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(false)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			results := m.HealthCheck(maxWorkers)
+			if err = remote.PrintHealth(cmd.OutOrStdout(), results, output); err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, result := range results {
+				if !result.Healthy() {
+					failed = append(failed, result.HostName)
+				}
+			}
+
+			if len(failed) > 0 {
+				return remote.ErrHostActionFailed{Hosts: failed, Total: len(results)}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagOutputDescription)
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	insertMediaLong = `
+Attach an ISO image served over HTTP or HTTPS to the virtual CD of baremetal
+hosts and make them boot from it once, on their next boot. The hosts are only
+rebooted into the image when --reboot is given.
+`
+
+	insertMediaExample = `
+# Re-provision node01 from a new ephemeral image
+airshipctl baremetal insertmedia http://10.23.24.1:8099/ephemeral.iso --name node01 --reboot
+
+# Attach an image to all worker hosts, to be booted on their next reboot
+airshipctl baremetal insertmedia https://images.example.com/worker.iso -l airshipit.org/k8s-role=worker
+
+# Check that node01 can take the image without changing the host
+airshipctl baremetal insertmedia http://10.23.24.1:8099/ephemeral.iso --name node01 --reboot --dry-run
+`
+)
+
+// NewInsertMediaCommand provides a command to attach an ISO image to the virtual CD of baremetal hosts.
+func NewInsertMediaCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var opts actionFlags
+	var reboot bool
+
+	cmd := &cobra.Command{
+		Use:     "insertmedia ISO_URL",
+		Short:   "Attach an ISO image to the virtual CD of a baremetal host",
+		Long:    insertMediaLong[1:],
+		Example: insertMediaExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(opts.output, remote.OutputTable, remote.OutputJSON, remote.OutputYAML); err != nil {
+				return err
+			}
+
+			attachISO, err := remote.AttachISO(args[0])
+			if err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			operation := fmt.Sprintf("insert media '%s'", args[0])
+			if reboot {
+				operation += " and reboot"
+			}
+			return runHostAction(cmd, m, opts, operation, func(ctx context.Context, client remote.Client) error {
+				if err := attachISO(ctx, client); err != nil {
+					return err
+				}
+
+				if !reboot {
+					return nil
+				}
+				return client.RebootSystem(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	opts.addFlags(flags)
+	flags.BoolVar(&reboot, "reboot", false, "reboot the host to boot from the inserted media")
+
+	return cmd
+}
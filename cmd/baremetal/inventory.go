@@ -0,0 +1,102 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	inventoryLong = `
+Collect the manufacturer, model, serial number and BIOS version of the selected
+baremetal hosts, with the versions of their firmware components and their NIC
+and disk inventory, from their BMCs via Redfish. The inventories are printed as
+a single JSON or CSV report. Hosts whose inventory could not be retrieved are
+left out of the report and listed on stderr.
+`
+
+	inventoryExample = `
+# Report the hardware of all hosts of the bootstrap phase as JSON
+airshipctl baremetal inventory
+
+# Audit the firmware of the worker hosts of the initinfra phase in a spreadsheet
+airshipctl baremetal inventory --phase initinfra -l airshipit.org/k8s-role=worker -o csv > workers.csv
+`
+)
+
+// NewInventoryCommand provides a command to report the hardware inventory of baremetal hosts.
+func NewInventoryCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var maxWorkers int
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "inventory",
+		Short:   "Report the firmware and hardware inventory of baremetal hosts",
+		Long:    inventoryLong[1:],
+		Example: inventoryExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(output, remote.OutputJSON, remote.OutputCSV); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(false)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			inventories := m.HardwareInventory(maxWorkers)
+			if err = remote.PrintInventory(cmd.OutOrStdout(), inventories, output); err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, inventory := range inventories {
+				if inventory.Err != nil {
+					failed = append(failed, inventory.HostName)
+					fmt.Fprintf(cmd.ErrOrStderr(), "Host '%s' inventory could not be retrieved: %v\n",
+						inventory.HostName, inventory.Err)
+				}
+			}
+
+			if len(failed) > 0 {
+				return remote.ErrHostActionFailed{Hosts: failed, Total: len(inventories)}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.StringVarP(&output, "output", "o", remote.OutputJSON, `output format, "json" or "csv"`)
+
+	return cmd
+}
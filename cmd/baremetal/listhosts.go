@@ -0,0 +1,73 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	listHostsLong = `
+List the baremetal hosts defined by the BareMetalHost documents of a phase,
+with their BMC address, role and labels. The role of a host is taken from its
+"airshipit.org/k8s-role" label, the ephemeral host is reported as "ephemeral".
+`
+
+	listHostsExample = `
+# List all hosts of the bootstrap phase
+airshipctl baremetal list-hosts
+
+# List the worker hosts of the initinfra phase as JSON
+airshipctl baremetal list-hosts --phase initinfra -l airshipit.org/k8s-role=worker -o json
+`
+)
+
+// NewListHostsCommand provides a command to list the baremetal hosts defined in the documents of a phase.
+func NewListHostsCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var labels string
+	var name string
+	var namespace string
+	var phase string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "list-hosts",
+		Short:   "List baremetal hosts defined in the documents of a phase",
+		Long:    listHostsLong[1:],
+		Example: listHostsExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hosts, err := remote.ListHosts(rootSettings, phase, name, labels, namespace)
+			if err != nil {
+				return err
+			}
+
+			return remote.PrintHosts(cmd.OutOrStdout(), hosts, output)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
+	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.StringVar(&namespace, flagNamespace, "", flagNamespaceDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagOutputDescription)
+
+	return cmd
+}
@@ -15,8 +15,6 @@
 package baremetal
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/config"
@@ -24,31 +22,56 @@ import (
 	"opendev.org/airship/airshipctl/pkg/remote"
 )
 
+const powerStatusLong = `
+Retrieve the power status of the selected baremetal hosts. The BMCs are queried
+in parallel, at most --max-workers at a time. The power status of every host is
+printed in the format given by --output. If the power status of any host could
+not be retrieved, airshipctl exits with code 3 after printing the status of all
+hosts.
+`
+
 // NewPowerStatusCommand provides a command to retrieve the power status of a baremetal host.
 func NewPowerStatusCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var selection hostSelection
 	var phase string
+	var maxWorkers int
+	var output string
 
 	cmd := &cobra.Command{
 		Use:   "powerstatus",
 		Short: "Retrieve the power status of a baremetal host",
+		Long:  powerStatusLong[1:],
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
+			if err := checkOutputFormat(output, remote.OutputTable, remote.OutputJSON,
+				remote.OutputYAML); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(false)
+			if err != nil {
+				return err
+			}
+
 			m, err := remote.NewManager(rootSettings, phase, selectors...)
 			if err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				powerStatus, err := host.SystemPowerStatus(host.Context)
-				if err != nil {
-					return err
+			statuses := m.PowerStatus(maxWorkers)
+			if err = remote.PrintPowerStatus(cmd.OutOrStdout(), statuses, output); err != nil {
+				return err
+			}
+
+			var unreachable []string
+			for _, status := range statuses {
+				if status.Err != nil {
+					unreachable = append(unreachable, status.HostName)
 				}
+			}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Host '%s' has power status: '%s'\n",
-					host.HostName, powerStatus)
+			if len(unreachable) > 0 {
+				return remote.ErrHostsUnreachable{Hosts: unreachable}
 			}
 
 			return nil
@@ -56,9 +79,10 @@ func NewPowerStatusCommand(rootSettings *environment.AirshipCTLSettings) *cobra.
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	selection.addFlags(flags)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagReportOutputDescription)
 
 	return cmd
 }
@@ -15,7 +15,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 package baremetal
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/spf13/cobra"
 
@@ -26,37 +26,39 @@ import (
 
 // NewRebootCommand provides a command with the capability to reboot baremetal hosts.
 func NewRebootCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var selection hostSelection
 	var phase string
+	var opts actionFlags
 
 	cmd := &cobra.Command{
 		Use:   "reboot",
 		Short: "Reboot a host",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
-			if err != nil {
+			if err := checkOutputFormat(opts.output, remote.OutputTable, remote.OutputJSON, remote.OutputYAML); err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				if err := host.RebootSystem(host.Context); err != nil {
-					return err
-				}
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Rebooted host '%s'.\n", host.HostName)
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
 			}
 
-			return nil
+			return runHostAction(cmd, m, opts, "reboot", func(ctx context.Context, client remote.Client) error {
+				return client.RebootSystem(ctx)
+			})
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	selection.addFlags(flags)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	opts.addFlags(flags)
 
 	return cmd
 }
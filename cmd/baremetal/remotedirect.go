@@ -15,19 +15,47 @@
 package baremetal
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/remote"
 )
 
+const (
+	remoteDirectLong = `
+Boot the ephemeral host from the ISO image configured in the bootstrap options
+of the current context. With --wait the command only returns once the host
+joined the cluster of the current kubeconfig context as a Ready node, or fails
+after --wait-timeout. The node is expected to be named after the baremetal host
+document unless --node-name is given.
+
+Warnings and failures the BMC of the host records meanwhile, e.g. in its event
+log or as failed tasks, are printed as they occur. A failure aborts the command
+instead of waiting for the timeout. Events are not available over IPMI.
+`
+
+	remoteDirectExample = `
+# Bootstrap the ephemeral host and block until it is a Ready node
+airshipctl baremetal remotedirect --wait --wait-timeout 45m
+`
+)
+
 // NewRemoteDirectCommand provides a command with the capability to perform remote direct operations.
 func NewRemoteDirectCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var wait bool
+	var waitTimeout time.Duration
+	var nodeName string
+
 	cmd := &cobra.Command{
-		Use:   "remotedirect",
-		Short: "Bootstrap the ephemeral host",
+		Use:     "remotedirect",
+		Short:   "Bootstrap the ephemeral host",
+		Long:    remoteDirectLong[1:],
+		Example: remoteDirectExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager, err := remote.NewManager(rootSettings,
 				config.BootstrapPhase,
@@ -41,9 +69,37 @@ func NewRemoteDirectCommand(rootSettings *environment.AirshipCTLSettings) *cobra
 			}
 
 			ephemeralHost := manager.Hosts[0]
-			return ephemeralHost.DoRemoteDirect(rootSettings)
+			monitor := ephemeralHost.MonitorEvents(cmd.OutOrStdout())
+			if err = ephemeralHost.DoRemoteDirect(rootSettings); err != nil {
+				// The events the BMC recorded usually tell the cause of the failure
+				monitor.Poll() //nolint:errcheck
+				return err
+			}
+			if err = monitor.Poll(); err != nil || !wait {
+				return err
+			}
+
+			kclient, err := client.DefaultClient(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			if nodeName == "" {
+				nodeName = ephemeralHost.HostName
+			}
+			waiter := remote.NewProvisionWaiter(kclient.ClientSet(), waitTimeout)
+			waiter.Out = cmd.OutOrStdout()
+			waiter.Check = monitor.Poll
+			return waiter.WaitForNode(nodeName)
 		},
 	}
 
+	flags := cmd.Flags()
+	flags.BoolVar(&wait, "wait", false, "wait for the ephemeral host to become a Ready node of the cluster")
+	flags.DurationVar(&waitTimeout, "wait-timeout", remote.DefaultProvisionTimeout,
+		"how long to wait for the ephemeral host with --wait")
+	flags.StringVar(&nodeName, "node-name", "",
+		"name of the node of the ephemeral host, defaults to the name of its baremetal host document")
+
 	return cmd
 }
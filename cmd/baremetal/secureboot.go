@@ -0,0 +1,162 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	secureBootLong = `
+Query and change the UEFI Secure Boot state of baremetal hosts through Redfish.
+Changes to Secure Boot only take effect on the next boot of a host, "enable"
+and "disable" reboot the hosts that are powered on when --reboot is given.
+Secure Boot is not available over IPMI.
+`
+
+	secureBootExample = `
+# Show the Secure Boot state of all hosts of the bootstrap phase
+airshipctl baremetal secureboot status
+
+# Enable Secure Boot on the worker hosts and reboot them to apply it
+airshipctl baremetal secureboot enable -l airshipit.org/k8s-role=worker --reboot
+
+# Disable Secure Boot on node01, e.g. before booting an unsigned provisioning image
+airshipctl baremetal secureboot disable --name node01
+`
+)
+
+// NewSecureBootCommand provides a command to query and change the UEFI Secure Boot state of baremetal hosts.
+func NewSecureBootCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "secureboot",
+		Short:   "Manage UEFI Secure Boot of baremetal hosts",
+		Long:    secureBootLong[1:],
+		Example: secureBootExample,
+	}
+
+	cmd.AddCommand(newSecureBootStatusCommand(rootSettings))
+	cmd.AddCommand(newSetSecureBootCommand(rootSettings, true))
+	cmd.AddCommand(newSetSecureBootCommand(rootSettings, false))
+
+	return cmd
+}
+
+// newSecureBootStatusCommand provides a command to retrieve the Secure Boot state of baremetal hosts.
+func newSecureBootStatusCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var maxWorkers int
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Retrieve the Secure Boot state of baremetal hosts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(output, remote.OutputTable, remote.OutputJSON); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(false)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			states := m.SecureBootStatus(maxWorkers)
+			if err = remote.PrintSecureBoot(cmd.OutOrStdout(), states, output); err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, state := range states {
+				if state.Err != nil {
+					failed = append(failed, state.HostName)
+				}
+			}
+
+			if len(failed) > 0 {
+				return remote.ErrHostActionFailed{Hosts: failed, Total: len(states)}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.IntVar(&maxWorkers, flagMaxWorkers, defaultMaxWorkers, flagMaxWorkersDescription)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, remote.OutputTable, flagOutputDescription)
+
+	return cmd
+}
+
+// newSetSecureBootCommand provides a command to enable or disable Secure Boot on baremetal hosts.
+func newSetSecureBootCommand(rootSettings *environment.AirshipCTLSettings, enabled bool) *cobra.Command {
+	var selection hostSelection
+	var phase string
+	var opts actionFlags
+	var reboot bool
+
+	use, short, operation := "disable", "Disable Secure Boot on baremetal hosts", "disable Secure Boot"
+	if enabled {
+		use, short, operation = "enable", "Enable Secure Boot on baremetal hosts", "enable Secure Boot"
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutputFormat(opts.output, remote.OutputTable, remote.OutputJSON, remote.OutputYAML); err != nil {
+				return err
+			}
+
+			selectors, err := selection.selectors(true)
+			if err != nil {
+				return err
+			}
+
+			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			if err != nil {
+				return err
+			}
+
+			description := operation
+			if reboot {
+				description += " and reboot"
+			}
+			return runHostAction(cmd, m, opts, description, remote.SetSecureBoot(enabled, reboot))
+		},
+	}
+
+	flags := cmd.Flags()
+	selection.addFlags(flags)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	opts.addFlags(flags)
+	flags.BoolVar(&reboot, "reboot", false, "reboot the hosts that are powered on to apply the change")
+
+	return cmd
+}
@@ -0,0 +1,141 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/phase/apply"
+)
+
+const (
+	addonsLong = `
+Deploy the addons of a new workload cluster, e.g. its CNI, metrics server and
+storage provisioner. The command waits for the control plane of the cluster
+to be ready, retrieves the kubeconfig Cluster API generated for it and applies
+the addons phase of the current context to the cluster with that kubeconfig.
+It then waits for the nodes of the cluster to be Ready, which they are not
+until a CNI is deployed.
+
+Run it right after the phase provisioning the control plane, so that the
+cluster doesn't sit NotReady waiting for manual steps.
+`
+
+	addonsExample = `
+# Deploy the addons of target-cluster once its control plane is up
+airshipctl phase apply controlplane
+airshipctl cluster addons target-cluster -n target-infra
+
+# Deploy the cni phase and wait for the resources it applies to be ready
+airshipctl cluster addons target-cluster --phase cni --wait
+`
+)
+
+// NewAddonsCommand creates a command deploying the addons of a new workload
+// cluster
+func NewAddonsCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace    string
+		phase        string
+		timeout      time.Duration
+		nodesTimeout time.Duration
+		wait         bool
+		waitTimeout  time.Duration
+	)
+	addonsCmd := &cobra.Command{
+		Use:     "addons CLUSTER_NAME",
+		Short:   "Deploy the addons of a new workload cluster",
+		Long:    addonsLong[1:],
+		Example: addonsExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if namespace == "" {
+				var err error
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			applyOptions := apply.NewOptions(rootSettings)
+			applyOptions.PhaseName = phase
+			applyOptions.Validate = true
+			applyOptions.Retries = client.DefaultRetryOptions().Retries
+			applyOptions.Wait = wait
+			applyOptions.WaitTimeout = waitTimeout
+			applyOptions.ProgressOut = out
+			applyOptions.CreateNamespaces = rootSettings.CreateNamespaces
+			bootstrapper := cluster.NewAddonsBootstrapper(kclient, timeout, addonsApplier(applyOptions, factory, out))
+			bootstrapper.NodesTimeout = nodesTimeout
+			bootstrapper.Out = out
+			return bootstrapper.Bootstrap(namespace, args[0])
+		},
+	}
+
+	flags := addonsCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.StringVar(&phase, "phase", cluster.DefaultAddonsPhase,
+		"phase of the current context deploying the addons")
+	flags.DurationVar(&timeout, "timeout", 30*time.Minute,
+		"how long to wait for the control plane to be ready")
+	flags.DurationVar(&nodesTimeout, "nodes-timeout", 10*time.Minute,
+		"how long to wait for the nodes to be Ready once the addons are applied, 0 to not wait")
+	flags.BoolVar(&wait, "wait", false,
+		"wait until all applied resources are reconciled and ready")
+	flags.DurationVar(&waitTimeout, "wait-timeout", 5*time.Minute,
+		"how long to wait for each resource with --wait")
+	completion.SetFlagNames(addonsCmd, "phase", completion.PhaseNames)
+	return addonsCmd
+}
+
+// addonsApplier returns a function applying the phase of applyOptions to the
+// cluster of a kubeconfig file, the cluster being its current context as in
+// the kubeconfig Cluster API generates
+func addonsApplier(applyOptions *apply.Options, factory client.Factory,
+	out io.Writer) func(kubeconfigPath string) error {
+	return func(kubeconfigPath string) error {
+		settings := *applyOptions.RootSettings
+		settings.KubeConfigPath = kubeconfigPath
+		settings.KubeContext = ""
+		workloadClient, err := factory(&settings)
+		if err != nil {
+			return err
+		}
+
+		applyOptions.RootSettings = &settings
+		applyOptions.Client = workloadClient
+		result, err := applyOptions.Run()
+		if result == nil {
+			return err
+		}
+		if printErr := result.Print(out, kubectl.OutputTable); err == nil {
+			err = printErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	checkLong = `
+Check the health of the cluster of the current context and print a pass or
+fail result for every object checked. The built-in checks are:
+
+  nodes-ready       every node reports the Ready condition
+  core-pods         the pods of kube-system completed or run with all their
+                    containers ready
+  capi-conditions   the conditions of the Clusters and Machines are all True
+  dns               a pod running the image given with --dns-image resolves
+                    the kubernetes service
+
+Additional checks are declared by ClusterCheck documents of the manifest of
+the current context, labelled airshipit.org/deploy-k8s: "false" so that they
+are not deployed. Every resource a ClusterCheck selects must match its
+condition, a JSONPath filter:
+
+  apiVersion: airshipit.org/v1alpha1
+  kind: ClusterCheck
+  metadata:
+    name: ingress-available
+    labels:
+      airshipit.org/deploy-k8s: "false"
+  spec:
+    apiVersion: apps/v1
+    kind: Deployment
+    namespace: ingress
+    labelSelector: app=ingress
+    condition: '@.status.readyReplicas==@.spec.replicas'
+    message: not all replicas are ready
+
+The command fails if any check failed.
+`
+
+	checkExample = `
+# Run all checks
+airshipctl cluster check
+
+# Run the checks without the DNS one, as JSON
+airshipctl cluster check --skip dns -o json
+`
+)
+
+// NewCheckCommand creates a command checking the health of a cluster
+func NewCheckCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		phaseName string
+		output    string
+		skip      []string
+		dnsImage  string
+		timeout   time.Duration
+	)
+	checkCmd := &cobra.Command{
+		Use:     "check",
+		Short:   "Check the health of the cluster",
+		Long:    checkLong[1:],
+		Example: checkExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != cluster.OutputTable && output != cluster.OutputJSON {
+				return cluster.ErrInvalidOutputFormat{Format: output}
+			}
+			kustomizePath, err := rootSettings.CurrentContextEntryPoint(phaseName)
+			if err != nil {
+				return err
+			}
+			b, err := document.NewBundleByPath(kustomizePath)
+			if err != nil {
+				return err
+			}
+			docs, err := b.Select(document.NewClusterCheckSelector())
+			if err != nil {
+				return err
+			}
+			documentChecks, err := cluster.DocumentChecks(docs)
+			if err != nil {
+				return err
+			}
+
+			skipped := map[string]bool{}
+			for _, name := range skip {
+				skipped[name] = true
+			}
+			var checks []cluster.ClusterCheck
+			for _, check := range append(cluster.BuiltinChecks(dnsImage, timeout), documentChecks...) {
+				if !skipped[check.Name()] {
+					checks = append(checks, check)
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			report := cluster.RunChecks(kclient, checks)
+			if err = report.Print(cmd.OutOrStdout(), output); err != nil {
+				return err
+			}
+			return report.Err()
+		},
+	}
+
+	flags := checkCmd.Flags()
+	flags.StringVar(&phaseName, "phase", "",
+		"read the ClusterCheck documents of the given phase only")
+	flags.StringVarP(&output, "output", "o", cluster.OutputTable,
+		`output format, "table" or "json"`)
+	flags.StringSliceVar(&skip, "skip", nil,
+		"names of checks not to run")
+	flags.StringVar(&dnsImage, "dns-image", "busybox:1.31",
+		"image providing nslookup, run by the dns check")
+	flags.DurationVar(&timeout, "timeout", 2*time.Minute,
+		"how long to wait for the pod of the dns check to complete")
+	completion.SetFlagNames(checkCmd, "phase", completion.PhaseNames)
+	return checkCmd
+}
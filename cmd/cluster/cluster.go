@@ -18,6 +18,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/log"
 )
 
@@ -36,15 +37,32 @@ func NewClusterCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comm
 		Short: "Manage Kubernetes clusters",
 		Long:  clusterLong[1:],
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			log.Init(rootSettings.Debug, cmd.OutOrStderr())
-
 			// Load or Initialize airship Config
 			rootSettings.InitConfig()
+			log.Init(rootSettings.Debug, cmd.OutOrStderr())
 		},
 	}
 
 	clusterRootCmd.AddCommand(NewInitCommand(rootSettings))
-	clusterRootCmd.AddCommand(NewMoveCommand(rootSettings))
+	clusterRootCmd.AddCommand(NewMoveCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewAddonsCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewCheckCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewDeleteCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewDiffCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewEtcdCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewGetKubeconfigCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewListCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewMachineHealthCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewPortForwardCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewRestoreCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewRotateCertsCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewScaleCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewStatusCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewUpgradeCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewUpgradeNodesCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewUpgradeProvidersCommand(rootSettings))
+	clusterRootCmd.AddCommand(NewWaitCommand(rootSettings, client.DefaultClient))
+	clusterRootCmd.AddCommand(NewWatchCommand(rootSettings, client.DefaultClient))
 
 	return clusterRootCmd
 }
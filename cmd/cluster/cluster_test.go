@@ -15,10 +15,13 @@
 package cluster_test
 
 import (
+	"fmt"
 	"testing"
 
 	"opendev.org/airship/airshipctl/cmd/cluster"
+	pkgcluster "opendev.org/airship/airshipctl/pkg/cluster"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -40,6 +43,183 @@ func TestNewClusterCommand(t *testing.T) {
 			CmdLine: "--help",
 			Cmd:     cluster.NewInitCommand(fakeRootSettings),
 		},
+		{
+			Name:    "cluster-addons-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewAddonsCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-check-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewCheckCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-check-cmd-invalid-output",
+			CmdLine: "-o yaml",
+			Cmd:     cluster.NewCheckCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrInvalidOutputFormat{Format: "yaml"},
+		},
+		{
+			Name:    "cluster-delete-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewDeleteCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-delete-cmd-without-confirm",
+			CmdLine: "target-cluster",
+			Cmd:     cluster.NewDeleteCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrDeleteNotConfirmed{Cluster: "target-cluster"},
+		},
+		{
+			Name:    "cluster-diff-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewDiffCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-etcd-backup-cmd-with-help",
+			CmdLine: "backup --help",
+			Cmd:     cluster.NewEtcdCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-etcd-restore-cmd-with-help",
+			CmdLine: "restore --help",
+			Cmd:     cluster.NewEtcdCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-etcd-restore-cmd-without-confirm",
+			CmdLine: "restore target-cluster snapshot.db",
+			Cmd:     cluster.NewEtcdCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrEtcdRestoreNotConfirmed{Cluster: "target-cluster"},
+		},
+		{
+			Name:    "cluster-get-kubeconfig-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewGetKubeconfigCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-get-kubeconfig-cmd-without-cluster",
+			CmdLine: "",
+			Cmd:     cluster.NewGetKubeconfigCommand(fakeRootSettings, client.DefaultClient),
+			Error:   fmt.Errorf("accepts %d arg(s), received %d", 1, 0),
+		},
+		{
+			Name:    "cluster-list-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewListCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-list-cmd-invalid-output",
+			CmdLine: "-o wide",
+			Cmd:     cluster.NewListCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrInvalidOutputFormat{Format: "wide"},
+		},
+		{
+			Name:    "cluster-machine-health-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewMachineHealthCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-machine-health-cmd-invalid-output",
+			CmdLine: "-o wide",
+			Cmd:     cluster.NewMachineHealthCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrInvalidOutputFormat{Format: "wide"},
+		},
+		{
+			Name:    "cluster-move-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewMoveCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-port-forward-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewPortForwardCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-restore-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewRestoreCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-restore-cmd-without-archive",
+			CmdLine: "",
+			Cmd:     cluster.NewRestoreCommand(fakeRootSettings, client.DefaultClient),
+			Error:   fmt.Errorf("accepts %d arg(s), received %d", 1, 0),
+		},
+		{
+			Name:    "cluster-rotate-certs-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewRotateCertsCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-rotate-certs-cmd-without-cluster",
+			CmdLine: "",
+			Cmd:     cluster.NewRotateCertsCommand(fakeRootSettings, client.DefaultClient),
+			Error:   fmt.Errorf("accepts %d arg(s), received %d", 1, 0),
+		},
+		{
+			Name:    "cluster-scale-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewScaleCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-scale-cmd-without-replicas",
+			CmdLine: "--machinedeployment workers",
+			Cmd:     cluster.NewScaleCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrReplicasRequired{},
+		},
+		{
+			Name:    "cluster-status-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewStatusCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-status-cmd-invalid-output",
+			CmdLine: "-o yaml",
+			Cmd:     cluster.NewStatusCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrInvalidOutputFormat{Format: "yaml"},
+		},
+		{
+			Name:    "cluster-upgrade-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewUpgradeCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-upgrade-pause-cmd-with-help",
+			CmdLine: "pause --help",
+			Cmd:     cluster.NewUpgradeCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-upgrade-nodes-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewUpgradeNodesCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-upgrade-providers-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewUpgradeProvidersCommand(fakeRootSettings),
+		},
+		{
+			Name:    "cluster-wait-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewWaitCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-wait-cmd-without-cluster",
+			CmdLine: "",
+			Cmd:     cluster.NewWaitCommand(fakeRootSettings, client.DefaultClient),
+			Error:   fmt.Errorf("accepts %d arg(s), received %d", 1, 0),
+		},
+		{
+			Name:    "cluster-watch-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     cluster.NewWatchCommand(fakeRootSettings, client.DefaultClient),
+		},
+		{
+			Name:    "cluster-watch-cmd-without-targets",
+			CmdLine: "",
+			Cmd:     cluster.NewWatchCommand(fakeRootSettings, client.DefaultClient),
+			Error:   pkgcluster.ErrNoWatchTargets{},
+		},
 	}
 	for _, testcase := range tests {
 		testutil.RunTest(t, testcase)
@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	deleteLong = `
+Delete a workload cluster from the management cluster. The Cluster API
+resources of the cluster are removed in order: its MachineDeployments first,
+then its control plane, then the Cluster itself. Each step waits for the
+Machines to be gone and for the BareMetalHosts they ran on to be
+deprovisioned before the next one starts.
+
+Deleting a cluster destroys its nodes and everything running on them, so the
+command refuses to run unless --confirm is given.
+`
+
+	deleteExample = `
+# Delete the target-cluster cluster
+airshipctl cluster delete target-cluster -n target-infra --confirm
+`
+)
+
+// deleteOptions holds the flags of the delete command
+type deleteOptions struct {
+	namespace string
+	confirm   bool
+	timeout   time.Duration
+}
+
+// NewDeleteCommand creates a command tearing down a workload cluster
+func NewDeleteCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := &deleteOptions{}
+	deleteCmd := &cobra.Command{
+		Use:     "delete CLUSTER_NAME",
+		Short:   "Delete a workload cluster",
+		Long:    deleteLong[1:],
+		Example: deleteExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !o.confirm {
+				return cluster.ErrDeleteNotConfirmed{Cluster: args[0]}
+			}
+			if o.namespace == "" {
+				var err error
+				if o.namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			deleter := cluster.NewDeleter(kclient, o.timeout)
+			deleter.Out = cmd.OutOrStdout()
+			return deleter.Delete(o.namespace, args[0])
+		},
+	}
+
+	flags := deleteCmd.Flags()
+	flags.StringVarP(&o.namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.BoolVar(&o.confirm, "confirm", false,
+		"confirm that the cluster and its nodes are to be destroyed")
+	flags.DurationVar(&o.timeout, "timeout", 60*time.Minute,
+		"how long to wait for the cluster to be torn down")
+	return deleteCmd
+}
@@ -0,0 +1,131 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	diffLong = `
+Compare the documents of the current context with the live objects in the
+cluster and show the resources that are missing (+) or have fields that
+differ (~). Only fields set in the documents are compared, values defaulted by
+the cluster are ignored. Secret data is redacted.
+
+The command exits with a non-zero status when drift is found.
+`
+
+	diffExample = `
+# Compare all documents deployed to the cluster
+airshipctl cluster diff
+
+# Compare the documents of the initinfra phase only
+airshipctl cluster diff --phase initinfra
+`
+)
+
+// NewDiffCommand creates a command showing differences between the
+// documents and the live cluster
+func NewDiffCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var phaseName string
+	diffCmd := &cobra.Command{
+		Use:     "diff",
+		Short:   "Show differences between documents and the live cluster",
+		Long:    diffLong[1:],
+		Example: diffExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kustomizePath, err := rootSettings.CurrentContextEntryPoint(phaseName)
+			if err != nil {
+				return err
+			}
+			b, err := document.NewBundleByPath(kustomizePath)
+			if err != nil {
+				return err
+			}
+			docs, err := b.Select(document.NewDeployToK8sSelector())
+			if err != nil {
+				return err
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			namespace, err := currentNamespace(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			diffs, err := cluster.NewDiffer(kclient).Diff(docs, namespace)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(diffs) == 0 {
+				fmt.Fprintln(out, "No differences found.")
+				return nil
+			}
+			for _, diff := range diffs {
+				fmt.Fprintln(out, diff)
+				for _, field := range diff.Fields {
+					fmt.Fprintf(out, "  %s\n", field)
+				}
+			}
+			return cluster.ErrDriftDetected{Resources: len(diffs)}
+		},
+	}
+
+	diffCmd.Flags().StringVar(&phaseName, "phase", "",
+		"compare the documents of the given phase only")
+	completion.SetFlagNames(diffCmd, "phase", completion.PhaseNames)
+	return diffCmd
+}
+
+// currentNamespace returns the namespace of the kubeconfig context the cluster
+// client connects to, i.e. the context selected by --context or the one of
+// the current context
+func currentNamespace(rootSettings *environment.AirshipCTLSettings) (string, error) {
+	var kubeContext *clientcmdapi.Context
+	if name := rootSettings.KubeContext; name != "" {
+		var found bool
+		if kubeContext, found = rootSettings.Config.KubeConfig().Contexts[name]; !found {
+			return "", config.ErrMissingConfig{What: fmt.Sprintf("Context with name '%s'", name)}
+		}
+	} else {
+		context, err := rootSettings.Config.GetCurrentContext()
+		if err != nil {
+			return "", err
+		}
+		kubeContext = context.KubeContext()
+	}
+	if kubeContext != nil && kubeContext.Namespace != "" {
+		return kubeContext.Namespace, nil
+	}
+	return metav1.NamespaceDefault, nil
+}
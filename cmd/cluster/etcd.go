@@ -0,0 +1,186 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	etcdLong = `
+Take and restore snapshots of the etcd cluster of a workload cluster. The
+commands reach the workload cluster with the kubeconfig Cluster API generated
+for it and run etcdctl in the etcd static pods of its control plane nodes.
+`
+
+	etcdBackupLong = `
+Save a snapshot of the etcd cluster of a workload cluster. The snapshot is
+taken on the first running etcd member and saved to the directory given with
+--dir, or etcdBackupDir of the airshipctl configuration, named after the
+cluster and the time it was taken.
+
+If a passphrase file is given, the snapshot is encrypted with a key derived
+from the first line of that file, otherwise it is not encrypted. A passphrase
+can be generated with "airshipctl secret generate masterpassphrase".
+`
+
+	etcdBackupExample = `
+# Save an encrypted snapshot of etcd of target-cluster to /var/backups/etcd
+airshipctl cluster etcd backup target-cluster -n target-infra --dir /var/backups/etcd \
+  --passphrase-file $HOME/.airship-passphrase
+`
+
+	etcdRestoreLong = `
+Restore the etcd cluster of a workload cluster from a snapshot saved by
+"airshipctl cluster etcd backup". A pod is started on each control plane node
+to restore the snapshot for its etcd member. etcd and the API server are
+stopped on every node while their data is replaced, the data they had is kept
+next to the etcd data directory with an .airshipctl-previous suffix.
+
+Restoring etcd discards every change made to the cluster since the snapshot
+was taken, so the command refuses to run unless --confirm is given. Encrypted
+snapshots require the passphrase file used to create them.
+`
+
+	etcdRestoreExample = `
+# Restore etcd of target-cluster from an encrypted snapshot
+airshipctl cluster etcd restore target-cluster target-cluster-etcd-20200601T120000Z.db -n target-infra \
+  --passphrase-file $HOME/.airship-passphrase --confirm
+`
+)
+
+// etcdOptions holds the flags shared by the etcd commands
+type etcdOptions struct {
+	namespace      string
+	passphraseFile string
+	timeout        time.Duration
+}
+
+// NewEtcdCommand creates a command taking and restoring snapshots of the
+// etcd cluster of a workload cluster
+func NewEtcdCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	etcdCmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Back up and restore etcd of a workload cluster",
+		Long:  etcdLong[1:],
+	}
+	etcdCmd.AddCommand(newEtcdBackupCommand(rootSettings, factory))
+	etcdCmd.AddCommand(newEtcdRestoreCommand(rootSettings, factory))
+	return etcdCmd
+}
+
+func newEtcdBackupCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := &etcdOptions{}
+	var dir string
+	backupCmd := &cobra.Command{
+		Use:     "backup CLUSTER_NAME",
+		Short:   "Save a snapshot of etcd of a workload cluster",
+		Long:    etcdBackupLong[1:],
+		Example: etcdBackupExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := secret.ReadPassphraseFile(o.passphraseFile)
+			if err != nil {
+				return err
+			}
+			manager, err := o.manager(rootSettings, factory, args[0])
+			if err != nil {
+				return err
+			}
+			manager.Out = cmd.OutOrStdout()
+			if dir == "" {
+				dir = rootSettings.Config.EtcdBackupDir
+			}
+			if dir == "" {
+				dir = "."
+			}
+			name := fmt.Sprintf("%s-etcd-%s.db", args[0], time.Now().UTC().Format("20060102T150405Z"))
+			return manager.Snapshot(filepath.Join(dir, name), passphrase)
+		},
+	}
+
+	o.addFlags(backupCmd)
+	backupCmd.Flags().StringVar(&dir, "dir", "",
+		"directory to save the snapshot to, overrides etcdBackupDir of the airshipctl configuration. "+
+			"The current directory is used if unset")
+	return backupCmd
+}
+
+func newEtcdRestoreCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := &etcdOptions{}
+	var confirm bool
+	restoreCmd := &cobra.Command{
+		Use:     "restore CLUSTER_NAME SNAPSHOT",
+		Short:   "Restore etcd of a workload cluster from a snapshot",
+		Long:    etcdRestoreLong[1:],
+		Example: etcdRestoreExample,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !confirm {
+				return cluster.ErrEtcdRestoreNotConfirmed{Cluster: args[0]}
+			}
+			passphrase, err := secret.ReadPassphraseFile(o.passphraseFile)
+			if err != nil {
+				return err
+			}
+			manager, err := o.manager(rootSettings, factory, args[0])
+			if err != nil {
+				return err
+			}
+			manager.Out = cmd.OutOrStdout()
+			return manager.Restore(args[1], passphrase)
+		},
+	}
+
+	o.addFlags(restoreCmd)
+	restoreCmd.Flags().DurationVar(&o.timeout, "timeout", 10*time.Minute,
+		"how long to wait for etcd to be restored on all control plane nodes")
+	restoreCmd.Flags().BoolVar(&confirm, "confirm", false,
+		"confirm that the changes made to the cluster since the snapshot are to be discarded")
+	return restoreCmd
+}
+
+func (o *etcdOptions) addFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVarP(&o.namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.StringVar(&o.passphraseFile, "passphrase-file", "",
+		"file containing the passphrase the snapshot is encrypted with")
+}
+
+// manager returns the EtcdManager of the workload cluster clusterName
+func (o *etcdOptions) manager(rootSettings *environment.AirshipCTLSettings, factory client.Factory,
+	clusterName string) (*cluster.EtcdManager, error) {
+	var err error
+	if o.namespace == "" {
+		if o.namespace, err = currentNamespace(rootSettings); err != nil {
+			return nil, err
+		}
+	}
+	kclient, err := factory(rootSettings)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.NewEtcdManager(kclient, o.namespace, clusterName, o.timeout)
+}
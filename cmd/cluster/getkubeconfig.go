@@ -0,0 +1,140 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	getKubeconfigLong = `
+Retrieve the admin kubeconfig Cluster API generated for a workload cluster
+from its Secret in the management cluster, and print it.
+
+With --merge the contexts of the kubeconfig are created in the airshipctl
+config instead, along with the clusters and users they refer to, and bound to
+the given manifest. Contexts that already exist in the airshipctl config are
+left untouched.
+`
+
+	getKubeconfigExample = `
+# Print the kubeconfig of the target-cluster cluster
+airshipctl cluster get-kubeconfig target-cluster -n target-infra
+
+# Save the kubeconfig to a file
+airshipctl cluster get-kubeconfig target-cluster -n target-infra > target-cluster.kubeconfig
+
+# Create airshipctl contexts for the cluster, bound to the "dev" manifest
+airshipctl cluster get-kubeconfig target-cluster -n target-infra --merge --manifest dev
+`
+)
+
+// getKubeconfigOptions holds the flags of the get-kubeconfig command
+type getKubeconfigOptions struct {
+	namespace string
+	merge     bool
+	manifest  string
+}
+
+// NewGetKubeconfigCommand creates a command retrieving the kubeconfig of a
+// workload cluster from the management cluster
+func NewGetKubeconfigCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := &getKubeconfigOptions{}
+	getKubeconfigCmd := &cobra.Command{
+		Use:     "get-kubeconfig CLUSTER_NAME",
+		Short:   "Retrieve the kubeconfig of a workload cluster",
+		Long:    getKubeconfigLong[1:],
+		Example: getKubeconfigExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.namespace == "" {
+				var err error
+				if o.namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			kubeconfig, err := cluster.GetKubeconfig(kclient, o.namespace, args[0])
+			if err != nil {
+				return err
+			}
+
+			if !o.merge {
+				_, err = cmd.OutOrStdout().Write(kubeconfig)
+				return err
+			}
+			return mergeKubeconfig(rootSettings.Config, kubeconfig, o.manifest, cmd.OutOrStdout())
+		},
+	}
+
+	flags := getKubeconfigCmd.Flags()
+	flags.StringVarP(&o.namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.BoolVar(&o.merge, "merge", false,
+		"create airshipctl contexts for the cluster instead of printing the kubeconfig")
+	flags.StringVar(&o.manifest, "manifest", config.AirshipDefaultManifest,
+		"manifest to bind the merged contexts to")
+
+	completion.SetFlagNames(getKubeconfigCmd, "manifest", completion.ManifestNames)
+	return getKubeconfigCmd
+}
+
+// mergeKubeconfig imports all contexts of kubeconfig into airconfig
+func mergeKubeconfig(airconfig *config.Config, kubeconfig []byte, manifest string, out io.Writer) error {
+	kubeConfig, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(kubeConfig.Contexts))
+	for name := range kubeConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	imported := 0
+	for _, name := range names {
+		ok, err := airconfig.ImportContext(kubeConfig, name, manifest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(out, "Context %q already exists, skipping.\n", name)
+			continue
+		}
+		imported++
+		fmt.Fprintf(out, "Context %q created with manifest %q.\n", name, manifest)
+	}
+
+	if imported == 0 {
+		return nil
+	}
+	return airconfig.PersistConfig()
+}
@@ -0,0 +1,117 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/cmd/cluster"
+	pkgcluster "opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const workloadKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://10.23.25.102:6443
+  name: target-cluster
+contexts:
+- context:
+    cluster: target-cluster
+    user: target-cluster-admin
+  name: target-cluster-admin@target-cluster
+users:
+- name: target-cluster-admin
+  user:
+    username: admin
+`
+
+func kubeconfigFactory(*environment.AirshipCTLSettings) (client.Interface, error) {
+	return fake.NewClient(fake.WithTypedObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "target-cluster-kubeconfig",
+			Namespace: "target-infra",
+		},
+		Data: map[string][]byte{"value": []byte(workloadKubeconfig)},
+	})), nil
+}
+
+func TestGetKubeconfigCommand(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedOut      string
+		expectedContexts []string
+		expectedErr      error
+	}{
+		{
+			name:        "Print",
+			args:        []string{"target-cluster", "-n", "target-infra"},
+			expectedOut: workloadKubeconfig,
+		},
+		{
+			name:             "Merge",
+			args:             []string{"target-cluster", "-n", "target-infra", "--merge", "--manifest", "dev"},
+			expectedOut:      "Context \"target-cluster-admin@target-cluster\" created with manifest \"dev\".\n",
+			expectedContexts: []string{"target-cluster-admin@target-cluster"},
+		},
+		{
+			name:        "NotFound",
+			args:        []string{"other-cluster", "-n", "target-infra"},
+			expectedErr: pkgcluster.ErrKubeconfigNotFound{Cluster: "other-cluster", Namespace: "target-infra"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conf, cleanupConfig := testutil.InitConfig(t)
+			defer cleanupConfig(t)
+			conf.Manifests["dev"] = testutil.DummyManifest()
+			numContexts := len(conf.Contexts)
+
+			out := &bytes.Buffer{}
+			settings := &environment.AirshipCTLSettings{Config: conf}
+			getKubeconfigCmd := cluster.NewGetKubeconfigCommand(settings, kubeconfigFactory)
+			getKubeconfigCmd.SetArgs(tt.args)
+			getKubeconfigCmd.SetOut(out)
+
+			err := getKubeconfigCmd.Execute()
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOut, out.String())
+
+			assert.Len(t, conf.Contexts, numContexts+len(tt.expectedContexts))
+			for _, name := range tt.expectedContexts {
+				context, err := conf.GetContext(name)
+				require.NoError(t, err)
+				assert.Equal(t, "dev", context.Manifest)
+			}
+		})
+	}
+}
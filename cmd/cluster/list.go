@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	listLong = `
+List the Clusters of the management clusters of all contexts of the airship
+configuration, one row per Cluster with the context it was found in, its
+phase, the Kubernetes version of its control plane and its ready nodes.
+
+Every context is queried even if some of them can't be reached, those are
+listed after the clusters and the command fails once the list is printed.
+`
+
+	listExample = `
+# List the clusters of all sites
+airshipctl cluster list
+
+# List the clusters of the given contexts only, as JSON
+airshipctl cluster list --contexts site-a,site-b -o json
+`
+)
+
+// NewListCommand creates a command listing the clusters of all contexts
+func NewListCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		contexts []string
+		output   string
+	)
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the clusters of the management clusters of all contexts",
+		Long:    listLong[1:],
+		Example: listExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != cluster.OutputTable && output != cluster.OutputJSON {
+				return cluster.ErrInvalidOutputFormat{Format: output}
+			}
+			if len(contexts) == 0 {
+				for name := range rootSettings.Config.Contexts {
+					contexts = append(contexts, name)
+				}
+				sort.Strings(contexts)
+			}
+
+			list := cluster.ListClusters(contexts, func(context string) (client.ResourceAccessor, error) {
+				if _, err := rootSettings.Config.GetContext(context); err != nil {
+					return nil, err
+				}
+				settings := *rootSettings
+				settings.KubeContext = context
+				return factory(&settings)
+			})
+			if err := list.Print(cmd.OutOrStdout(), output); err != nil {
+				return err
+			}
+			return list.Err()
+		},
+	}
+
+	flags := listCmd.Flags()
+	flags.StringSliceVar(&contexts, "contexts", nil,
+		"contexts to list the clusters of, all contexts of the airship configuration by default")
+	flags.StringVarP(&output, "output", "o", cluster.OutputTable,
+		`output format, "table" or "json"`)
+	return listCmd
+}
@@ -0,0 +1,96 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	machineHealthLong = `
+Summarize the health of the Machines of the management cluster: the
+MachineHealthChecks with their number of healthy Machines and whether they are
+allowed to remediate, the Machines that failed, are being deleted or were
+found unhealthy, along with the BareMetalHosts they run on, and the events of
+the remediations performed by the MachineHealthCheck controller.
+
+A Machine that keeps being reprovisioned shows up in the remediations with a
+growing count, the reason it was found unhealthy and the error reported by
+its BareMetalHost.
+`
+
+	machineHealthExample = `
+# Show the health of the Machines of the namespace of the current context
+airshipctl cluster machine-health
+
+# Show the health of the Machines of all namespaces as JSON
+airshipctl cluster machine-health --all-namespaces -o json
+`
+)
+
+// NewMachineHealthCommand creates a command reporting the health and
+// remediations of Machines
+func NewMachineHealthCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		output        string
+	)
+	machineHealthCmd := &cobra.Command{
+		Use:     "machine-health",
+		Short:   "Show unhealthy Machines and their remediations",
+		Long:    machineHealthLong[1:],
+		Example: machineHealthExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != cluster.OutputTable && output != cluster.OutputJSON {
+				return cluster.ErrInvalidOutputFormat{Format: output}
+			}
+
+			var err error
+			switch {
+			case allNamespaces:
+				namespace = ""
+			case namespace == "":
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			report, err := cluster.GetMachineHealth(kclient, namespace)
+			if err != nil {
+				return err
+			}
+			return report.Print(cmd.OutOrStdout(), output)
+		},
+	}
+
+	flags := machineHealthCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the Machines, the namespace of the current context by default")
+	flags.BoolVarP(&allNamespaces, "all-namespaces", "A", false,
+		"show the Machines of all namespaces")
+	flags.StringVarP(&output, "output", "o", cluster.OutputTable,
+		`output format, "table" or "json"`)
+	return machineHealthCmd
+}
@@ -21,6 +21,7 @@ import (
 
 	clusterctlcmd "opendev.org/airship/airshipctl/pkg/clusterctl/cmd"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
 )
 
 const (
@@ -28,18 +29,36 @@ const (
 Move Cluster API objects, provider specific objects and all dependencies to the target cluster.
 
 Note: The destination cluster MUST have the required provider components installed.
+
+Before moving, the command checks that the namespace has Cluster objects and runs preflight checks: the target
+cluster must serve the provider CRDs, and the BareMetalHost CRD if there are hosts to move, in the versions the
+objects are stored in, and no Cluster, Machine, MachineDeployment or BareMetalHost may still be reconciled. If any
+check fails, the problems found are reported along with the action resolving each of them, and nothing is moved.
+After moving, the Cluster, Machine and BareMetalHost objects found in both clusters are reported, and the
+command fails if any of them did not arrive in the target cluster.
+
+With --backup, the Cluster API and metal3 objects of the namespace are saved
+to an archive before anything is moved, see airshipctl cluster restore.
 `
 
 	moveExample = `
 Move Cluster API objects, provider specific objects and all dependencies to the target cluster.
 
   airshipctl cluster move --target-context <context name>
+
+Move the objects of a namespace other than the one of the Clusterctl document.
+
+  airshipctl cluster move --target-context <context name> --namespace <namespace>
+
+Back up the objects to move first.
+
+  airshipctl cluster move --target-context <context name> --backup <archive>
 `
 )
 
 // NewMoveCommand creates a command to move capi and bmo resources to the target cluster
-func NewMoveCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var toKubeconfigContext string
+func NewMoveCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var toKubeconfigContext, namespace, backupPath string
 	moveCmd := &cobra.Command{
 		Use:     "move",
 		Short:   "Move Cluster API objects, provider specific objects and all dependencies to the target cluster",
@@ -50,11 +69,45 @@ func NewMoveCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command
 			if err != nil {
 				return err
 			}
-			return command.Move(toKubeconfigContext)
+			if backupPath != "" {
+				if err = backupMoved(rootSettings, factory, command, namespace, backupPath, cmd); err != nil {
+					return err
+				}
+			}
+			report, err := command.Move(toKubeconfigContext, namespace)
+			if report != nil {
+				if printErr := report.Print(cmd.OutOrStdout()); printErr != nil {
+					return printErr
+				}
+			}
+			return err
 		},
 	}
 
 	moveCmd.Flags().StringVar(&toKubeconfigContext, "target-context", "",
 		"Context to be used within the kubeconfig file for the target cluster. If empty, current context will be used.")
+	moveCmd.Flags().StringVarP(&namespace, "namespace", "n", "",
+		"Namespace of the objects to move. If empty, the namespace of the Clusterctl document is used, "+
+			"falling back to the current namespace of the ephemeral cluster.")
+	moveCmd.Flags().StringVar(&backupPath, "backup", "",
+		"Path of an archive to back up the objects of the namespace to before moving them.")
 	return moveCmd
 }
+
+// backupMoved backs up the objects of the namespace to move from the
+// ephemeral cluster
+func backupMoved(rootSettings *environment.AirshipCTLSettings, factory client.Factory,
+	command *clusterctlcmd.Command, namespace, path string, cmd *cobra.Command) error {
+	namespace = command.MoveNamespace(namespace)
+	if namespace == "" {
+		var err error
+		if namespace, err = currentNamespace(rootSettings); err != nil {
+			return err
+		}
+	}
+	kclient, err := factory(rootSettings)
+	if err != nil {
+		return err
+	}
+	return takeBackup(kclient, namespace, path, cmd.OutOrStdout())
+}
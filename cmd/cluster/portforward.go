@@ -0,0 +1,99 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	portForwardLong = `
+Forward one or more local ports to a pod of the cluster, for example to reach
+in-cluster services such as the ISO server or the Ironic API while debugging a
+bootstrap. The target is a pod name, or TYPE/NAME where TYPE is pod, service or
+deployment. Services and deployments are forwarded to one of their running pods,
+remote ports of services are service ports.
+
+Ports are given as LOCAL_PORT:REMOTE_PORT, or as a single port used on both
+sides. Forwarding runs until the command is interrupted.
+`
+
+	portForwardExample = `
+# Listen on port 6385 locally, forwarding to port 6385 of the ironic service
+airshipctl cluster port-forward svc/ironic 6385 --namespace metal3
+
+# Listen on port 8080 locally, forwarding to port 80 of a pod
+airshipctl cluster port-forward pod/iso-server 8080:80
+
+# Listen on all addresses, forwarding to a pod of the ironic deployment
+airshipctl cluster port-forward deploy/ironic 6385 --namespace metal3 --address 0.0.0.0
+`
+)
+
+// NewPortForwardCommand creates a command forwarding local ports to a pod
+// of the cluster
+func NewPortForwardCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace string
+		addresses []string
+	)
+	portForwardCmd := &cobra.Command{
+		Use:     "port-forward TARGET PORT...",
+		Short:   "Forward local ports to a pod of the cluster",
+		Long:    portForwardLong[1:],
+		Example: portForwardExample,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if namespace == "" {
+				var err error
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			pf := client.NewPortForwarder(kclient, namespace, args[0], args[1:])
+			pf.Addresses = addresses
+			pf.Out = cmd.OutOrStdout()
+			pf.ErrOut = cmd.ErrOrStderr()
+
+			stopCh := make(chan struct{})
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt)
+			defer signal.Stop(signals)
+			go func() {
+				<-signals
+				close(stopCh)
+			}()
+			return pf.ForwardPorts(stopCh, nil)
+		},
+	}
+
+	flags := portForwardCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the target, the namespace of the current context by default")
+	flags.StringSliceVar(&addresses, "address", []string{"localhost"},
+		"addresses to listen on, comma separated")
+	return portForwardCmd
+}
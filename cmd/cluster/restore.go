@@ -0,0 +1,84 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	restoreLong = `
+Restore the Cluster API and metal3 objects of a backup taken with the --backup
+flag of the move or upgrade commands to the cluster of the current context,
+e.g. to recover the management cluster after a failed pivot.
+
+Owners are created before the objects they own, whose owner references are
+updated with the new owners. Clusters and BareMetalHosts are created paused
+and resumed once all objects are restored, so that no controller acts on a
+cluster which isn't restored completely. Objects which already exist in the
+cluster are left as they are.
+`
+
+	restoreExample = `
+# Back up the objects of the target-infra namespace before moving them
+airshipctl cluster move --target-context target-cluster-admin@target-cluster --backup target-infra.tar.gz
+
+# Restore them to the ephemeral cluster if the move failed
+airshipctl cluster restore target-infra.tar.gz
+`
+)
+
+// NewRestoreCommand creates a command restoring the objects of a backup
+func NewRestoreCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "restore ARCHIVE",
+		Short:   "Restore Cluster API objects from a backup",
+		Long:    restoreLong[1:],
+		Example: restoreExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := cluster.LoadBackup(args[0])
+			if err != nil {
+				return err
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			return b.Restore(kclient.DynamicClient(), kclient.RESTMapper(), cmd.OutOrStdout())
+		},
+	}
+}
+
+// takeBackup saves the Cluster API and metal3 objects of namespace to path,
+// before an operation changing them
+func takeBackup(kclient client.Interface, namespace, path string, out io.Writer) error {
+	b, err := cluster.TakeBackup(kclient, namespace)
+	if err != nil {
+		return err
+	}
+	if err = b.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d objects of namespace %s backed up to %s\n", len(b.Objects), namespace, path)
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	rotateCertsLong = `
+Rotate the certificates kubeadm generated on the control plane of a workload
+cluster. The command sets spec.upgradeAfter of the KubeadmControlPlane of the
+cluster, so that Cluster API replaces its machines one at a time with new
+ones whose certificates are valid from the time they joined the cluster, and
+blocks until the rollout is complete.
+
+The expiry date of the certificate served by the API server is printed
+before and after the rollout, the command fails if it didn't change. The
+kubelets of worker nodes renew their client certificates by themselves.
+`
+
+	rotateCertsExample = `
+# Rotate the control plane certificates of target-cluster
+airshipctl cluster rotate-certs target-cluster -n target-infra --timeout 90m
+`
+)
+
+// NewRotateCertsCommand creates a command rotating the control plane
+// certificates of a workload cluster
+func NewRotateCertsCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace string
+		timeout   time.Duration
+	)
+	rotateCertsCmd := &cobra.Command{
+		Use:     "rotate-certs CLUSTER_NAME",
+		Short:   "Rotate the control plane certificates of a workload cluster",
+		Long:    rotateCertsLong[1:],
+		Example: rotateCertsExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if namespace == "" {
+				var err error
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			rotator := cluster.NewCertRotator(kclient, timeout)
+			rotator.Out = cmd.OutOrStdout()
+			return rotator.Rotate(namespace, args[0])
+		},
+	}
+
+	flags := rotateCertsCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.DurationVar(&timeout, "timeout", 60*time.Minute,
+		"how long to wait for the machines of the control plane to be replaced")
+	return rotateCertsCmd
+}
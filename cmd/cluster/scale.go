@@ -0,0 +1,119 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	scaleLong = `
+Change the number of worker nodes of a cluster and wait for all of them to be
+ready. The MachineDeployment given with --machinedeployment is scaled to the
+number given with --replicas. Without --machinedeployment, the
+MachineDeployments of the documents of the current context are scaled to the
+replicas of their documents.
+
+The Machines of the MachineDeployments are followed until there are as many
+as requested and all of them run a ready node, along with the BareMetalHosts
+they are provisioned on.
+`
+
+	scaleExample = `
+# Scale the workers MachineDeployment to 3 nodes
+airshipctl cluster scale --machinedeployment workers --replicas 3 -n target-infra
+
+# Scale the MachineDeployments to the replicas of the workers phase
+airshipctl cluster scale --phase workers --timeout 60m
+`
+)
+
+// scaleOptions holds the flags of the scale command
+type scaleOptions struct {
+	machineDeployment string
+	replicas          int64
+	namespace         string
+	phaseName         string
+	timeout           time.Duration
+}
+
+// NewScaleCommand creates a command scaling the worker nodes of a cluster
+func NewScaleCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := &scaleOptions{}
+	scaleCmd := &cobra.Command{
+		Use:     "scale",
+		Short:   "Scale the worker nodes of a cluster",
+		Long:    scaleLong[1:],
+		Example: scaleExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var targets []cluster.ScaleTarget
+			if o.machineDeployment != "" {
+				if !cmd.Flags().Changed("replicas") {
+					return cluster.ErrReplicasRequired{}
+				}
+				namespace := o.namespace
+				if namespace == "" {
+					var err error
+					if namespace, err = currentNamespace(rootSettings); err != nil {
+						return err
+					}
+				}
+				targets = append(targets, cluster.ScaleTarget{
+					Namespace: namespace,
+					Name:      o.machineDeployment,
+					Replicas:  o.replicas,
+				})
+			} else {
+				docs, namespace, err := deployedDocuments(rootSettings, o.phaseName)
+				if err != nil {
+					return err
+				}
+				if targets, err = cluster.ScaleTargetsFromDocuments(docs, namespace); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			scaler := cluster.NewScaler(kclient, o.timeout)
+			scaler.Out = cmd.OutOrStdout()
+			return scaler.Scale(targets)
+		},
+	}
+
+	flags := scaleCmd.Flags()
+	flags.StringVar(&o.machineDeployment, "machinedeployment", "",
+		"name of the MachineDeployment to scale")
+	flags.Int64Var(&o.replicas, "replicas", 0,
+		"number of nodes of the MachineDeployment")
+	flags.StringVarP(&o.namespace, "namespace", "n", "",
+		"namespace of the MachineDeployment, the namespace of the current context by default")
+	flags.StringVar(&o.phaseName, "phase", "",
+		"scale the MachineDeployments of the given phase only")
+	flags.DurationVar(&o.timeout, "timeout", 30*time.Minute,
+		"how long to wait for the nodes to be ready")
+	completion.SetFlagNames(scaleCmd, "phase", completion.PhaseNames)
+	return scaleCmd
+}
@@ -0,0 +1,93 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	statusLong = `
+Show the provisioning progress of the clusters managed by the current
+context. Cluster, Machine, KubeadmControlPlane and BareMetalHost resources
+are rolled up into a table of clusters followed by a table of their nodes,
+where every Machine is shown with the BareMetalHost it runs on.
+BareMetalHosts that no Machine runs on are listed last.
+
+Use --output json to feed the status to dashboards.
+`
+
+	statusExample = `
+# Show the clusters of the namespace of the current context
+airshipctl cluster status
+
+# Show the clusters of all namespaces as JSON
+airshipctl cluster status --all-namespaces -o json
+`
+)
+
+// NewStatusCommand creates a command showing the provisioning progress of clusters
+func NewStatusCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		output        string
+	)
+	statusCmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show the provisioning progress of clusters and their nodes",
+		Long:    statusLong[1:],
+		Example: statusExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != cluster.OutputTable && output != cluster.OutputJSON {
+				return cluster.ErrInvalidOutputFormat{Format: output}
+			}
+
+			var err error
+			switch {
+			case allNamespaces:
+				namespace = ""
+			case namespace == "":
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			status, err := cluster.GetProvisioningStatus(kclient, namespace)
+			if err != nil {
+				return err
+			}
+			return status.Print(cmd.OutOrStdout(), output)
+		},
+	}
+
+	flags := statusCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the clusters, the namespace of the current context by default")
+	flags.BoolVarP(&allNamespaces, "all-namespaces", "A", false,
+		"show the clusters of all namespaces")
+	flags.StringVarP(&output, "output", "o", cluster.OutputTable,
+		`output format, "table" or "json"`)
+	return statusCmd
+}
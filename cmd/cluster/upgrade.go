@@ -0,0 +1,170 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	upgradeLong = `
+Upgrade the control plane of a cluster to the Kubernetes version and machine
+template of the KubeadmControlPlane documents of the current context. The
+KubeadmControlPlanes are patched, new machine templates they refer to are
+created from the documents, and the rolling upgrade is followed until all
+control plane machines run the new version and are ready.
+
+A rolling upgrade can be paused and resumed with the pause and resume
+subcommands. Running the upgrade again follows a resumed rollout to its end.
+
+With --backup, the Cluster API and metal3 objects of the namespace are saved
+to an archive before the upgrade starts, see airshipctl cluster restore.
+`
+
+	upgradeExample = `
+# Upgrade the control plane to the version of the documents
+airshipctl cluster upgrade
+
+# Upgrade with the documents of the controlplane phase, waiting up to an hour
+airshipctl cluster upgrade --phase controlplane --timeout 60m
+
+# Back up the Cluster API objects before upgrading
+airshipctl cluster upgrade --backup target-infra.tar.gz
+`
+
+	upgradePauseLong = `
+Pause the rolling upgrade of the KubeadmControlPlanes of the documents of the
+current context. No control plane machine is created or deleted until the
+upgrade is resumed.
+`
+
+	upgradeResumeLong = `
+Resume the paused rolling upgrade of the KubeadmControlPlanes of the documents
+of the current context.
+`
+)
+
+// NewUpgradeCommand creates a command upgrading the control plane of a cluster
+func NewUpgradeCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		phaseName  string
+		backupPath string
+		timeout    time.Duration
+	)
+	upgradeCmd := &cobra.Command{
+		Use:     "upgrade",
+		Short:   "Roll the control plane out to the version of the documents",
+		Long:    upgradeLong[1:],
+		Example: upgradeExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, namespace, err := deployedDocuments(rootSettings, phaseName)
+			if err != nil {
+				return err
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			if backupPath != "" {
+				if err = takeBackup(kclient, namespace, backupPath, cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+
+			upgrader := cluster.NewUpgrader(kclient, timeout)
+			upgrader.Out = cmd.OutOrStdout()
+			return upgrader.Upgrade(docs, namespace)
+		},
+	}
+
+	flags := upgradeCmd.Flags()
+	flags.StringVar(&phaseName, "phase", "",
+		"use the documents of the given phase only")
+	flags.DurationVar(&timeout, "timeout", 30*time.Minute,
+		"how long to wait for the rolling upgrade to complete")
+	flags.StringVar(&backupPath, "backup", "",
+		"path of an archive to back up the Cluster API objects of the namespace to before upgrading")
+	completion.SetFlagNames(upgradeCmd, "phase", completion.PhaseNames)
+
+	upgradeCmd.AddCommand(newUpgradePauseCommand(rootSettings, factory,
+		"pause", "Pause the rolling upgrade of the control plane", upgradePauseLong, true))
+	upgradeCmd.AddCommand(newUpgradePauseCommand(rootSettings, factory,
+		"resume", "Resume the rolling upgrade of the control plane", upgradeResumeLong, false))
+	return upgradeCmd
+}
+
+// newUpgradePauseCommand creates the command pausing, or resuming, the
+// rolling upgrade of the control plane
+func newUpgradePauseCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory,
+	use, short, long string, paused bool) *cobra.Command {
+	var phaseName string
+	pauseCmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long[1:],
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, namespace, err := deployedDocuments(rootSettings, phaseName)
+			if err != nil {
+				return err
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			upgrader := cluster.NewUpgrader(kclient, 0)
+			upgrader.Out = cmd.OutOrStdout()
+			return upgrader.SetPaused(docs, namespace, paused)
+		},
+	}
+
+	pauseCmd.Flags().StringVar(&phaseName, "phase", "",
+		"use the documents of the given phase only")
+	completion.SetFlagNames(pauseCmd, "phase", completion.PhaseNames)
+	return pauseCmd
+}
+
+// deployedDocuments returns the documents of the phase deployed to the
+// cluster, along with the namespace of the current context
+func deployedDocuments(rootSettings *environment.AirshipCTLSettings,
+	phaseName string) ([]document.Document, string, error) {
+	kustomizePath, err := rootSettings.CurrentContextEntryPoint(phaseName)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := document.NewBundleByPath(kustomizePath)
+	if err != nil {
+		return nil, "", err
+	}
+	docs, err := b.Select(document.NewDeployToK8sSelector())
+	if err != nil {
+		return nil, "", err
+	}
+	namespace, err := currentNamespace(rootSettings)
+	if err != nil {
+		return nil, "", err
+	}
+	return docs, namespace, nil
+}
@@ -0,0 +1,105 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	upgradeNodesLong = `
+Roll the worker nodes of a cluster out to the machine templates of the
+MachineDeployment documents of the current context, e.g. to templates
+writing a new OS image. Machine templates the documents refer to are created
+from the documents, then the MachineDeployments are patched and rolled out
+one after the other: Cluster API creates a batch of machines with the new
+template, then drains and deletes as many machines with the old one, until
+all machines are replaced.
+
+With --pause-between-batches, a MachineDeployment is paused once a batch was
+replaced and the command stops, so that the new nodes can be checked before
+going further. Running the command again resumes the rollout for the next
+batch.
+`
+
+	upgradeNodesExample = `
+# Replace the worker nodes two at a time
+airshipctl cluster upgrade-nodes --batch-size 2
+
+# Replace one node, check it, then continue with the next one
+airshipctl cluster upgrade-nodes --batch-size 1 --pause-between-batches
+`
+)
+
+// NewUpgradeNodesCommand creates a command rolling worker nodes out to new
+// machine templates
+func NewUpgradeNodesCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		phaseName  string
+		backupPath string
+		batchSize  int64
+		pause      bool
+		timeout    time.Duration
+	)
+	upgradeNodesCmd := &cobra.Command{
+		Use:     "upgrade-nodes",
+		Short:   "Roll worker nodes out to the machine templates of the documents",
+		Long:    upgradeNodesLong[1:],
+		Example: upgradeNodesExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, namespace, err := deployedDocuments(rootSettings, phaseName)
+			if err != nil {
+				return err
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			if backupPath != "" {
+				if err = takeBackup(kclient, namespace, backupPath, cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+
+			upgrader := cluster.NewNodeUpgrader(kclient, timeout)
+			upgrader.BatchSize = batchSize
+			upgrader.PauseBetweenBatches = pause
+			upgrader.Out = cmd.OutOrStdout()
+			return upgrader.Upgrade(docs, namespace)
+		},
+	}
+
+	flags := upgradeNodesCmd.Flags()
+	flags.StringVar(&phaseName, "phase", "",
+		"use the documents of the given phase only")
+	flags.Int64Var(&batchSize, "batch-size", 1,
+		"number of machines replaced at once, all machines of a MachineDeployment if 0")
+	flags.BoolVar(&pause, "pause-between-batches", false,
+		"pause the rollout once a batch of machines was replaced")
+	flags.DurationVar(&timeout, "timeout", 60*time.Minute,
+		"how long to wait for the worker nodes to be replaced")
+	flags.StringVar(&backupPath, "backup", "",
+		"path of an archive to back up the Cluster API objects of the namespace to before upgrading")
+	completion.SetFlagNames(upgradeNodesCmd, "phase", completion.PhaseNames)
+	return upgradeNodesCmd
+}
@@ -0,0 +1,96 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+
+	clusterctlclient "opendev.org/airship/airshipctl/pkg/clusterctl/client"
+	clusterctlcmd "opendev.org/airship/airshipctl/pkg/clusterctl/cmd"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+const (
+	upgradeProvidersLong = `
+Upgrade the cluster-api providers of the management cluster to the versions of
+the Clusterctl document, the same document cluster init deploys them from.
+
+The version of a provider is the one init-options initialize it with, else the
+version it is pinned to, else the latest version of its airshipctl repository.
+Providers the document defines no version for are left untouched, and older
+versions than the installed ones are refused.
+`
+
+	upgradeProvidersPlanLong = `
+Show the providers installed in the management cluster, grouped by the core
+provider they are managed with, along with the versions of the Clusterctl
+document they would be upgraded to.
+`
+
+	upgradeProvidersApplyLong = `
+Upgrade the providers installed in the management cluster to the versions of
+the Clusterctl document, and show the plan that was applied.
+`
+
+	upgradeProvidersExample = `
+# Show which providers would be upgraded
+airshipctl cluster upgrade-providers plan
+
+# Upgrade the providers to the versions of the Clusterctl document
+airshipctl cluster upgrade-providers apply
+`
+)
+
+// NewUpgradeProvidersCommand creates a command upgrading the cluster-api
+// providers of the management cluster
+func NewUpgradeProvidersCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	upgradeProvidersCmd := &cobra.Command{
+		Use:     "upgrade-providers",
+		Short:   "Upgrade cluster-api providers to the versions of the Clusterctl document",
+		Long:    upgradeProvidersLong[1:],
+		Example: upgradeProvidersExample,
+	}
+
+	upgradeProvidersCmd.AddCommand(newUpgradeProvidersSubcommand(rootSettings, "plan",
+		"Show the provider upgrades of the management cluster", upgradeProvidersPlanLong,
+		(*clusterctlcmd.Command).PlanUpgrade))
+	upgradeProvidersCmd.AddCommand(newUpgradeProvidersSubcommand(rootSettings, "apply",
+		"Upgrade the providers of the management cluster", upgradeProvidersApplyLong,
+		(*clusterctlcmd.Command).ApplyUpgrade))
+	return upgradeProvidersCmd
+}
+
+// newUpgradeProvidersSubcommand creates the command running upgrade, planning
+// or applying the upgrade of the providers, and printing the resulting plans
+func newUpgradeProvidersSubcommand(rootSettings *environment.AirshipCTLSettings, use, short, long string,
+	upgrade func(*clusterctlcmd.Command) ([]clusterctlclient.UpgradePlan, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long[1:],
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command, err := clusterctlcmd.NewCommand(rootSettings)
+			if err != nil {
+				return err
+			}
+			plans, err := upgrade(command)
+			if err != nil {
+				return err
+			}
+			return clusterctlclient.PrintUpgradePlans(cmd.OutOrStdout(), plans)
+		},
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	waitLong = `
+Wait for the control plane of a workload cluster to be ready. The command
+blocks until the Cluster reports its control plane initialized, Cluster API
+generated its kubeconfig Secret and the API server of the cluster answers.
+
+Run it after the phase provisioning the control plane, before the phases
+that use the new cluster, e.g. before moving the Cluster API objects to it.
+`
+
+	waitExample = `
+# Wait for the control plane of target-cluster after the controlplane phase
+airshipctl phase apply controlplane
+airshipctl cluster wait target-cluster -n target-infra --timeout 60m
+airshipctl cluster move --target-context target-cluster-admin@target-cluster
+`
+)
+
+// NewWaitCommand creates a command waiting for the control plane of a
+// workload cluster to be ready
+func NewWaitCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace string
+		timeout   time.Duration
+	)
+	waitCmd := &cobra.Command{
+		Use:     "wait CLUSTER_NAME",
+		Short:   "Wait for the control plane of a workload cluster to be ready",
+		Long:    waitLong[1:],
+		Example: waitExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if namespace == "" {
+				var err error
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			waiter := cluster.NewControlPlaneWaiter(kclient, timeout)
+			waiter.Out = cmd.OutOrStdout()
+			return waiter.WaitForControlPlane(namespace, args[0])
+		},
+	}
+
+	flags := waitCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of the cluster, the namespace of the current context by default")
+	flags.DurationVar(&timeout, "timeout", 30*time.Minute,
+		"how long to wait for the control plane to be ready")
+	return waitCmd
+}
@@ -0,0 +1,125 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	watchLong = `
+Follow the status conditions of resources and print every transition until all
+of them have the expected condition. Resources are given as KIND/NAME or
+KIND/NAMESPACE/NAME, where KIND is Kind or Kind.group, or are all the documents
+of a phase with --phase.
+
+The command exits with a non-zero status if the condition isn't reached before
+the timeout, so it can be used as a deployment gate in CI.
+`
+
+	watchExample = `
+# Wait for a BareMetalHost to be provisioned
+airshipctl cluster watch BareMetalHost.metal3.io/metal3/node01 --for Provisioned --timeout 60m
+
+# Wait for all resources of the initinfra phase to be Ready
+airshipctl cluster watch --phase initinfra
+
+# Wait for a deployment to stop progressing
+airshipctl cluster watch deployment/ironic -n metal3 --for Progressing=False
+`
+)
+
+// NewWatchCommand creates a command following the conditions of resources
+func NewWatchCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		namespace string
+		phaseName string
+		condition string
+		timeout   time.Duration
+	)
+	watchCmd := &cobra.Command{
+		Use:     "watch [RESOURCE...]",
+		Short:   "Follow the conditions of resources until they are reached",
+		Long:    watchLong[1:],
+		Example: watchExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := cluster.ParseWatchCondition(condition)
+			if err != nil {
+				return err
+			}
+
+			var targets []cluster.WatchTarget
+			for _, arg := range args {
+				t, parseErr := cluster.ParseWatchTarget(arg)
+				if parseErr != nil {
+					return parseErr
+				}
+				targets = append(targets, t)
+			}
+			if phaseName != "" {
+				kustomizePath, pathErr := rootSettings.CurrentContextEntryPoint(phaseName)
+				if pathErr != nil {
+					return pathErr
+				}
+				b, bundleErr := document.NewBundleByPath(kustomizePath)
+				if bundleErr != nil {
+					return bundleErr
+				}
+				docs, selectErr := b.Select(document.NewDeployToK8sSelector())
+				if selectErr != nil {
+					return selectErr
+				}
+				targets = append(targets, cluster.WatchTargetsFromDocuments(docs)...)
+			}
+			if len(targets) == 0 {
+				return cluster.ErrNoWatchTargets{}
+			}
+
+			if namespace == "" {
+				if namespace, err = currentNamespace(rootSettings); err != nil {
+					return err
+				}
+			}
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+
+			watcher := cluster.NewWatcher(kclient, target, timeout)
+			watcher.Out = cmd.OutOrStdout()
+			return watcher.Watch(targets, namespace)
+		},
+	}
+
+	flags := watchCmd.Flags()
+	flags.StringVarP(&namespace, "namespace", "n", "",
+		"namespace of resources given without one, the namespace of the current context by default")
+	flags.StringVar(&phaseName, "phase", "",
+		"watch all the documents of the given phase")
+	flags.StringVar(&condition, "for", "Ready",
+		`condition to wait for as Type[=Status], the status defaults to "True"`)
+	flags.DurationVar(&timeout, "timeout", 10*time.Minute,
+		"how long to wait for all resources to reach the condition")
+	completion.SetFlagNames(watchCmd, "phase", completion.PhaseNames)
+	return watchCmd
+}
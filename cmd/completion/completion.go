@@ -17,13 +17,20 @@ package completion
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
 	completionLong = `
-Generate completion script for airshipctl for the specified shell (bash or zsh).
+Generate completion script for airshipctl for the specified shell (bash, zsh,
+fish or powershell).
+
+The bash, zsh and fish scripts complete context, manifest and phase names with
+the ones found in the airshipctl config at the time of completion.
 `
 
 	completionExample = `
@@ -32,13 +39,18 @@ airshipctl completion bash > $HOME/.airship_completions
 
 # Apply completions to the current shell
 source <(airshipctl completion bash)
+
+# Install fish completions
+airshipctl completion fish > ~/.config/fish/completions/airshipctl.fish
 `
 )
 
 var (
 	completionShells = map[string]func(cmd *cobra.Command) error{
-		"bash": runCompletionBash,
-		"zsh":  runCompletionZsh,
+		"bash":       runCompletionBash,
+		"fish":       runCompletionFish,
+		"powershell": runCompletionPowerShell,
+		"zsh":        runCompletionZsh,
 	}
 )
 
@@ -48,10 +60,11 @@ func NewCompletionCommand() *cobra.Command {
 	for s := range completionShells {
 		shells = append(shells, s)
 	}
+	sort.Strings(shells)
 
 	cmd := &cobra.Command{
 		Use:       "completion SHELL",
-		Short:     "Generate completion script for the specified shell (bash or zsh)",
+		Short:     "Generate completion script for the specified shell (bash, zsh, fish or powershell)",
 		Long:      completionLong[1:],
 		Example:   completionExample,
 		Args:      cobra.ExactArgs(1),
@@ -72,9 +85,65 @@ func runCompletion(cmd *cobra.Command, args []string) error {
 }
 
 func runCompletionBash(cmd *cobra.Command) error {
+	prepareBashCompletion(cmd.Root())
 	return cmd.Root().GenBashCompletion(cmd.OutOrStdout())
 }
 
+func runCompletionPowerShell(cmd *cobra.Command) error {
+	// NOTE: the powershell generator of cobra only supports static
+	// completion of commands and flags
+	return cmd.Root().GenPowerShellCompletion(cmd.OutOrStdout())
+}
+
+// prepareBashCompletion adds the bash functions completing names from the
+// airshipctl config to root and points the annotated commands and flags to
+// them
+func prepareBashCompletion(root *cobra.Command) {
+	rootName := root.Name()
+	getNames := fmt.Sprintf("__%s_get_names", rootName)
+
+	cases := &strings.Builder{}
+	walkCommands(root, func(c *cobra.Command) {
+		c.Flags().VisitAll(func(flag *pflag.Flag) {
+			if kind, ok := flag.Annotations[namesAnnotation]; ok {
+				c.Flags().SetAnnotation( //nolint:errcheck
+					flag.Name, cobra.BashCompCustom, []string{getNames + " " + kind[0]})
+			}
+		})
+		if kind, ok := c.Annotations[namesAnnotation]; ok {
+			commandName := strings.Replace(c.CommandPath(), " ", "_", -1)
+			fmt.Fprintf(cases, "        %s)\n            %s %s\n            return\n            ;;\n",
+				commandName, getNames, kind)
+		}
+	})
+
+	root.BashCompletionFunction = fmt.Sprintf(`%[1]s()
+{
+    local names
+    if names=$(%[2]s %[3]s "$1" 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${names[*]}" -- "$cur" ) )
+    fi
+}
+
+__%[2]s_custom_func() {
+    case ${last_command} in
+%[4]s        *)
+            ;;
+    esac
+}
+`, getNames, rootName, NamesCommand, cases.String())
+}
+
+// walkCommands calls fn for cmd and all of its available subcommands
+func walkCommands(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() {
+			walkCommands(c, fn)
+		}
+	}
+}
+
 func runCompletionZsh(cmd *cobra.Command) error {
 	out := cmd.OutOrStdout()
 	zshInitialization := `#compdef airshipctl
@@ -217,6 +286,7 @@ __airshipctl_convert_bash_to_zsh() {
 	}
 
 	buf := new(bytes.Buffer)
+	prepareBashCompletion(cmd.Root())
 	if err := cmd.Root().GenBashCompletion(buf); err != nil {
 		return err
 	}
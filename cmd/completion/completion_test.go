@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -36,10 +37,20 @@ func TestCompletion(t *testing.T) {
 			Cmd:     completion.NewCompletionCommand(),
 		},
 		{
-			Name:    "completion-unknown-shell",
+			Name:    "completion-fish",
 			CmdLine: "fish",
 			Cmd:     completion.NewCompletionCommand(),
-			Error:   errors.New("unsupported shell type \"fish\""),
+		},
+		{
+			Name:    "completion-powershell",
+			CmdLine: "powershell",
+			Cmd:     completion.NewCompletionCommand(),
+		},
+		{
+			Name:    "completion-unknown-shell",
+			CmdLine: "tcsh",
+			Cmd:     completion.NewCompletionCommand(),
+			Error:   errors.New("unsupported shell type \"tcsh\""),
 		},
 		{
 			Name:    "completion-cmd-too-many-args",
@@ -59,3 +70,30 @@ func TestCompletion(t *testing.T) {
 		testutil.RunTest(t, tt)
 	}
 }
+
+func TestNames(t *testing.T) {
+	settings := &environment.AirshipCTLSettings{Config: testutil.DummyConfig()}
+
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "names-contexts",
+			CmdLine: completion.ContextNames,
+			Cmd:     completion.NewNamesCommand(settings),
+		},
+		{
+			Name:    "names-manifests",
+			CmdLine: completion.ManifestNames,
+			Cmd:     completion.NewNamesCommand(settings),
+		},
+		{
+			Name:    "names-unknown-kind",
+			CmdLine: "clusters",
+			Cmd:     completion.NewNamesCommand(settings),
+			Error:   errors.New("unsupported kind of names \"clusters\""),
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
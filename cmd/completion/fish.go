@@ -0,0 +1,120 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package completion
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const fishHelpers = `# fish completion for %[1]s
+
+# __%[1]s_command_words prints the words of the command line which are not
+# flags
+function __%[1]s_command_words
+    for word in (commandline -opc)
+        if not string match -q -- '-*' $word
+            echo $word
+        end
+    end
+end
+
+# __%[1]s_using_command succeeds if the command line consists of exactly the
+# given command
+function __%[1]s_using_command
+    set -l words (__%[1]s_command_words)
+    test "$words" = "$argv"
+end
+
+# __%[1]s_seen_command succeeds if the command line starts with the given
+# command
+function __%[1]s_seen_command
+    set -l words (__%[1]s_command_words)
+    test (count $words) -ge (count $argv); and test "$words[1..(count $argv)]" = "$argv"
+end
+
+complete -c %[1]s -f
+`
+
+func runCompletionFish(cmd *cobra.Command) error {
+	return genFishCompletion(cmd.Root(), cmd.OutOrStdout())
+}
+
+// genFishCompletion writes a fish completion script for root and its
+// subcommands to out
+func genFishCompletion(root *cobra.Command, out io.Writer) error {
+	rootName := root.Name()
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, fishHelpers, rootName)
+
+	walkCommands(root, func(c *cobra.Command) {
+		path := c.CommandPath()
+		cmdBuf := &strings.Builder{}
+
+		for _, sub := range c.Commands() {
+			if !sub.IsAvailableCommand() {
+				continue
+			}
+			fmt.Fprintf(cmdBuf, "complete -c %s -n '__%s_using_command %s' -a %s -d %s\n",
+				rootName, rootName, path, sub.Name(), fishQuote(sub.Short))
+		}
+
+		if kind, ok := c.Annotations[namesAnnotation]; ok {
+			fmt.Fprintf(cmdBuf, "complete -c %s -n '__%s_using_command %s' -a '(%s %s %s 2>/dev/null)'\n",
+				rootName, rootName, path, rootName, NamesCommand, kind)
+		} else if len(c.ValidArgs) > 0 {
+			validArgs := append([]string{}, c.ValidArgs...)
+			sort.Strings(validArgs)
+			fmt.Fprintf(cmdBuf, "complete -c %s -n '__%s_using_command %s' -a %s\n",
+				rootName, rootName, path, fishQuote(strings.Join(validArgs, " ")))
+		}
+
+		// Inherited flags are completed by the ancestor defining them
+		c.NonInheritedFlags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Hidden {
+				return
+			}
+			line := fmt.Sprintf("complete -c %s -n '__%s_seen_command %s' -l %s", rootName, rootName, path, flag.Name)
+			if flag.Shorthand != "" {
+				line += " -s " + flag.Shorthand
+			}
+			if flag.Value.Type() != "bool" {
+				line += " -r"
+			}
+			if kind, ok := flag.Annotations[namesAnnotation]; ok {
+				line += fmt.Sprintf(" -a '(%s %s %s 2>/dev/null)'", rootName, NamesCommand, kind[0])
+			}
+			fmt.Fprintf(cmdBuf, "%s -d %s\n", line, fishQuote(flag.Usage))
+		})
+
+		if cmdBuf.Len() > 0 {
+			fmt.Fprintf(buf, "\n# %s\n%s", path, cmdBuf.String())
+		}
+	})
+
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
+
+// fishQuote quotes s as a single quoted fish string
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
@@ -0,0 +1,118 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package completion
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Kinds of names completed dynamically from the airshipctl config
+const (
+	ContextNames  = "contexts"
+	ManifestNames = "manifests"
+	PhaseNames    = "phases"
+)
+
+const (
+	// NamesCommand is the name of the hidden command printing the names
+	// used for dynamic completion
+	NamesCommand = "__names"
+
+	// namesAnnotation marks commands and flags completing to names from
+	// the airshipctl config
+	namesAnnotation = "airshipctl_completion_names"
+)
+
+// SetArgNames makes the arguments of cmd complete to the names of the given
+// kind
+func SetArgNames(cmd *cobra.Command, kind string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[namesAnnotation] = kind
+}
+
+// SetFlagNames makes the value of the flag of cmd complete to the names of
+// the given kind
+func SetFlagNames(cmd *cobra.Command, flag, kind string) {
+	// Errors are only returned for flags that don't exist
+	cmd.Flags().SetAnnotation(flag, namesAnnotation, []string{kind}) //nolint:errcheck
+}
+
+// NewNamesCommand creates a hidden command that prints the names of the given
+// kind from the airshipctl config, one per line. It is called by the
+// completion scripts.
+func NewNamesCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	return &cobra.Command{
+		Use:       NamesCommand + " KIND",
+		Hidden:    true,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{ContextNames, ManifestNames, PhaseNames},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rootSettings.Config == nil {
+				rootSettings.InitConfig()
+			}
+			names, err := listNames(rootSettings.Config, args[0])
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				fmt.Fprintln(cmd.OutOrStdout(), name)
+			}
+			return nil
+		},
+	}
+}
+
+func listNames(airconfig *config.Config, kind string) ([]string, error) {
+	var names []string
+	switch kind {
+	case ContextNames:
+		for name := range airconfig.Contexts {
+			names = append(names, name)
+		}
+	case ManifestNames:
+		for name := range airconfig.Manifests {
+			names = append(names, name)
+		}
+	case PhaseNames:
+		// Phases are the directories next to each other in the documents
+		// of the current context's cluster type
+		entryPoint, err := airconfig.CurrentContextEntryPoint("")
+		if err != nil {
+			return nil, err
+		}
+		entries, err := ioutil.ReadDir(entryPoint)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind of names %q", kind)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,89 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	backupLong = `
+Save the airshipctl config file and the kubeconfig file it manages to a
+gzipped tarball. If a passphrase file is given, the tarball is encrypted with
+a key derived from the first line of that file, otherwise it is not encrypted.
+The key files of encryption configs are never used for backups.
+
+The backup can be restored with "airshipctl config restore".
+`
+
+	backupExample = `
+# Save the airshipctl configuration to a tarball
+airshipctl config backup airship-config.tgz
+
+# Save an encrypted backup
+airshipctl config backup airship-config.tgz.enc --passphrase-file $HOME/.airship-passphrase
+`
+)
+
+// NewBackupCommand creates a command that saves the airshipctl config file
+// and its kubeconfig file to a tarball.
+func NewBackupCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var passphraseFile string
+	cmd := &cobra.Command{
+		Use:     "backup <file>",
+		Short:   "Save the airshipctl config and kubeconfig to a tarball",
+		Long:    backupLong[1:],
+		Example: backupExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := secret.ReadPassphraseFile(passphraseFile)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(args[0], os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			if err = rootSettings.Config.Backup(f, passphrase); err != nil {
+				f.Close() //nolint:errcheck
+				return err
+			}
+			if err = f.Close(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Airship config saved to %q.\n", args[0])
+			return nil
+		},
+	}
+
+	addPassphraseFileFlag(cmd, &passphraseFile)
+	return cmd
+}
+
+func addPassphraseFileFlag(cmd *cobra.Command, passphraseFile *string) {
+	cmd.Flags().StringVar(
+		passphraseFile,
+		"passphrase-file",
+		"",
+		"file containing the passphrase of an encrypted backup")
+}
@@ -28,10 +28,9 @@ func NewConfigCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comma
 		DisableFlagsInUseLine: true,
 		Short:                 "Manage the airshipctl config file",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			log.Init(rootSettings.Debug, cmd.OutOrStderr())
-
 			// Load or Initialize airship Config
 			rootSettings.InitConfig()
+			log.Init(rootSettings.Debug, cmd.OutOrStderr())
 		},
 	}
 	configRootCmd.AddCommand(NewSetClusterCommand(rootSettings))
@@ -40,9 +39,14 @@ func NewConfigCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comma
 	configRootCmd.AddCommand(NewGetContextCommand(rootSettings))
 	configRootCmd.AddCommand(NewInitCommand(rootSettings))
 	configRootCmd.AddCommand(NewSetAuthInfoCommand(rootSettings))
+	configRootCmd.AddCommand(NewSetEncryptionConfigCommand(rootSettings))
 	configRootCmd.AddCommand(NewGetAuthInfoCommand(rootSettings))
 	configRootCmd.AddCommand(NewUseContextCommand(rootSettings))
 	configRootCmd.AddCommand(NewImportCommand(rootSettings))
+	configRootCmd.AddCommand(NewDiscoverCommand(rootSettings))
+	configRootCmd.AddCommand(NewBackupCommand(rootSettings))
+	configRootCmd.AddCommand(NewRestoreCommand(rootSettings))
+	configRootCmd.AddCommand(NewDiffCommand(rootSettings))
 
 	return configRootCmd
 }
@@ -0,0 +1,97 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+const (
+	diffLong = `
+Compare two airshipctl config files and list the clusters, contexts, users,
+manifests and other settings that were added (+), removed (-) or modified (~).
+Secrets such as repository passwords are redacted.
+
+Without arguments the airshipctl config file is compared with the backup kept
+when it was last migrated. With a single argument the given file is compared
+with the airshipctl config file.
+`
+
+	diffExample = `
+# Compare the airshipctl config with its backup
+airshipctl config diff
+
+# Review a generated config before adopting it
+airshipctl config diff $HOME/generated-config
+
+# Compare two config files
+airshipctl config diff old-config new-config
+`
+)
+
+// NewDiffCommand creates a command that lists the differences between two
+// airshipctl config files.
+func NewDiffCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diff [OLD_CONFIG] [NEW_CONFIG]",
+		Short:   "Show differences between airshipctl config files",
+		Long:    diffLong[1:],
+		Example: diffExample,
+		Args:    cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current := rootSettings.Config.LoadedConfigPath()
+			oldPath, newPath := current+config.AirshipConfigBackupSuffix, current
+			switch len(args) {
+			case 1:
+				oldPath, newPath = current, args[0]
+			case 2:
+				oldPath, newPath = args[0], args[1]
+			}
+
+			oldData, err := ioutil.ReadFile(oldPath)
+			if err != nil {
+				return err
+			}
+			newData, err := ioutil.ReadFile(newPath)
+			if err != nil {
+				return err
+			}
+
+			diffs, err := config.DiffConfigs(oldData, newData)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(diffs) == 0 {
+				fmt.Fprintln(out, "No differences found.")
+				return nil
+			}
+			fmt.Fprintf(out, "--- %s\n+++ %s\n", oldPath, newPath)
+			for _, diff := range diffs {
+				fmt.Fprintln(out, diff)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
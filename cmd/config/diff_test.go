@@ -0,0 +1,68 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	cmd "opendev.org/airship/airshipctl/cmd/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	diffOldConfig = "testdata/config-diff-old.yaml"
+	diffNewConfig = "testdata/config-diff-new.yaml"
+)
+
+func TestConfigDiff(t *testing.T) {
+	conf := testutil.DummyConfig()
+	conf.SetLoadedConfigPath(diffOldConfig)
+	settings := &environment.AirshipCTLSettings{Config: conf}
+
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "config-diff-with-help",
+			CmdLine: "--help",
+			Cmd:     cmd.NewDiffCommand(nil),
+		},
+		{
+			Name:    "config-diff-two-files",
+			CmdLine: diffOldConfig + " " + diffNewConfig,
+			Cmd:     cmd.NewDiffCommand(settings),
+		},
+		{
+			Name:    "config-diff-with-current",
+			CmdLine: diffNewConfig,
+			Cmd:     cmd.NewDiffCommand(settings),
+		},
+		{
+			Name:    "config-diff-no-differences",
+			CmdLine: diffOldConfig + " " + diffOldConfig,
+			Cmd:     cmd.NewDiffCommand(settings),
+		},
+		{
+			Name:    "config-diff-no-backup",
+			CmdLine: "",
+			Cmd:     cmd.NewDiffCommand(settings),
+			Error:   errors.New("open testdata/config-diff-old.yaml.bak: no such file or directory"),
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
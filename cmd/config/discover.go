@@ -0,0 +1,188 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+const (
+	discoverLong = `
+Discover the contexts of an existing kubeconfig and create airshipctl contexts
+for them, bound to the given manifest. The clusters and users referred to by
+the chosen contexts are imported as well.
+
+Unless --import-context or --all is given, the contexts found are listed and
+the ones to import are read from standard input. Contexts that already exist
+in the airshipctl config are left untouched.
+`
+
+	discoverExample = `
+# Choose contexts to import from the default kubeconfig
+airshipctl config discover
+
+# Import the "kind-kind" context and bind it to the "dev" manifest
+airshipctl config discover --import-context kind-kind --manifest dev
+
+# Import all contexts of a given kubeconfig
+airshipctl config discover --kubeconfig-file $HOME/.kube/other-config --all
+`
+)
+
+// discoverOptions holds the flags of the discover command
+type discoverOptions struct {
+	kubeConfigPath string
+	contexts       []string
+	all            bool
+	manifest       string
+}
+
+// NewDiscoverCommand creates a command that creates airshipctl contexts from
+// the contexts of an existing kubeconfig.
+func NewDiscoverCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	o := &discoverOptions{}
+	cmd := &cobra.Command{
+		Use:     "discover",
+		Short:   "Create contexts from an existing kubeconfig",
+		Long:    discoverLong[1:],
+		Example: discoverExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiscover(o, rootSettings.Config, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(
+		&o.kubeConfigPath,
+		"kubeconfig-file",
+		"",
+		"kubeconfig to discover contexts in, defaults to the one used by kubectl")
+	flags.StringSliceVar(
+		&o.contexts,
+		"import-context",
+		nil,
+		"name of a context to import, may be repeated")
+	flags.BoolVar(
+		&o.all,
+		"all",
+		false,
+		"import all discovered contexts")
+	flags.StringVar(
+		&o.manifest,
+		"manifest",
+		config.AirshipDefaultManifest,
+		"manifest to bind the imported contexts to")
+
+	completion.SetFlagNames(cmd, "manifest", completion.ManifestNames)
+	return cmd
+}
+
+func runDiscover(o *discoverOptions, airconfig *config.Config, in io.Reader, out io.Writer) error {
+	if o.all && len(o.contexts) > 0 {
+		return config.ErrConflictingDiscoverOptions{}
+	}
+
+	kubeConfig, err := config.DiscoverKubeConfig(o.kubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	available := make([]string, 0, len(kubeConfig.Contexts))
+	for name := range kubeConfig.Contexts {
+		available = append(available, name)
+	}
+	sort.Strings(available)
+	if len(available) == 0 {
+		fmt.Fprintln(out, "No contexts found in kubeconfig.")
+		return nil
+	}
+
+	selected := o.contexts
+	switch {
+	case o.all:
+		selected = available
+	case len(selected) == 0:
+		selected, err = promptContexts(available, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	imported := 0
+	for _, name := range selected {
+		ok, err := airconfig.ImportContext(kubeConfig, name, o.manifest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(out, "Context %q already exists, skipping.\n", name)
+			continue
+		}
+		imported++
+		fmt.Fprintf(out, "Context %q created with manifest %q.\n", name, o.manifest)
+	}
+
+	if imported == 0 {
+		return nil
+	}
+	return airconfig.PersistConfig()
+}
+
+// promptContexts lists the available contexts and reads the numbers of the
+// ones to import from in
+func promptContexts(available []string, in io.Reader, out io.Writer) ([]string, error) {
+	fmt.Fprintln(out, "Contexts found in kubeconfig:")
+	for i, name := range available {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, name)
+	}
+	fmt.Fprint(out, "Select contexts to import (comma separated numbers, \"all\", or empty for none): ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	fmt.Fprintln(out)
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil
+	}
+	if line == "all" {
+		return available, nil
+	}
+
+	var selected []string
+	for _, field := range strings.Split(line, ",") {
+		field = strings.TrimSpace(field)
+		index, err := strconv.Atoi(field)
+		if err != nil || index < 1 || index > len(available) {
+			return nil, config.ErrInvalidContextSelection{Selection: field}
+		}
+		selected = append(selected, available[index-1])
+	}
+	return selected, nil
+}
@@ -0,0 +1,157 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmd "opendev.org/airship/airshipctl/cmd/config"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const discoverKubeConfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://10.0.0.1:6443
+  name: kind-kind
+- cluster:
+    server: https://10.0.0.2:6443
+  name: prod
+contexts:
+- context:
+    cluster: kind-kind
+    user: kind-kind
+  name: kind-kind
+- context:
+    cluster: prod
+    user: prod-admin
+  name: prod-admin@prod
+users:
+- name: kind-kind
+  user:
+    username: admin
+- name: prod-admin
+  user:
+    username: admin
+`
+
+func TestConfigDiscover(t *testing.T) {
+	settings := &environment.AirshipCTLSettings{Config: testutil.DummyConfig()}
+
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "config-discover-with-help",
+			CmdLine: "--help",
+			Cmd:     cmd.NewDiscoverCommand(nil),
+		},
+		{
+			Name:    "config-discover-conflicting-flags",
+			CmdLine: "--all --import-context foo",
+			Cmd:     cmd.NewDiscoverCommand(settings),
+			Error:   config.ErrConflictingDiscoverOptions{},
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	kubeDir, cleanup := testutil.TempDir(t, "airship-discover-tests")
+	defer cleanup(t)
+	kubeConfigPath := filepath.Join(kubeDir, "config")
+	require.NoError(t, ioutil.WriteFile(kubeConfigPath, []byte(discoverKubeConfig), 0600))
+
+	tests := []struct {
+		name             string
+		args             []string
+		input            string
+		expectedContexts []string
+		expectedErr      error
+	}{
+		{
+			name:             "ByFlag",
+			args:             []string{"--import-context", "kind-kind"},
+			expectedContexts: []string{"kind-kind"},
+		},
+		{
+			name:             "All",
+			args:             []string{"--all"},
+			expectedContexts: []string{"kind-kind", "prod-admin@prod"},
+		},
+		{
+			name:             "Interactive",
+			input:            "2\n",
+			expectedContexts: []string{"prod-admin@prod"},
+		},
+		{
+			name:  "InteractiveNone",
+			input: "\n",
+		},
+		{
+			name:        "InteractiveInvalid",
+			input:       "3\n",
+			expectedErr: config.ErrInvalidContextSelection{Selection: "3"},
+		},
+		{
+			name:        "UnknownContext",
+			args:        []string{"--import-context", "foo"},
+			expectedErr: config.ErrMissingConfig{What: "Context with name 'foo'"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conf, cleanupConfig := testutil.InitConfig(t)
+			defer cleanupConfig(t)
+			conf.Manifests["dev"] = testutil.DummyManifest()
+			numContexts := len(conf.Contexts)
+
+			settings := &environment.AirshipCTLSettings{Config: conf}
+			discoverCmd := cmd.NewDiscoverCommand(settings)
+			discoverCmd.SetArgs(append([]string{"--kubeconfig-file", kubeConfigPath, "--manifest", "dev"},
+				tt.args...))
+			discoverCmd.SetIn(strings.NewReader(tt.input))
+			discoverCmd.SetOut(bytes.NewBuffer(nil))
+
+			err := discoverCmd.Execute()
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Len(t, conf.Contexts, numContexts+len(tt.expectedContexts))
+			for _, name := range tt.expectedContexts {
+				context, err := conf.GetContext(name)
+				require.NoError(t, err)
+				assert.Equal(t, "dev", context.Manifest)
+			}
+		})
+	}
+}
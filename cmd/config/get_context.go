@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
 )
@@ -82,6 +83,7 @@ func NewGetContextCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 	}
 
 	addGetContextFlags(o, cmd)
+	completion.SetArgNames(cmd, completion.ContextNames)
 	return cmd
 }
 
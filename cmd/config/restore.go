@@ -0,0 +1,76 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	restoreLong = `
+Replace the airshipctl config file and the kubeconfig file it manages with the
+ones saved by "airshipctl config backup". Encrypted backups require the
+passphrase file used to create them.
+`
+
+	restoreExample = `
+# Restore the airshipctl configuration from a tarball
+airshipctl config restore airship-config.tgz
+
+# Restore an encrypted backup
+airshipctl config restore airship-config.tgz.enc --passphrase-file $HOME/.airship-passphrase
+`
+)
+
+// NewRestoreCommand creates a command that restores the airshipctl config
+// file and its kubeconfig file from a backup.
+func NewRestoreCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var passphraseFile string
+	cmd := &cobra.Command{
+		Use:     "restore <file>",
+		Short:   "Restore the airshipctl config and kubeconfig from a backup",
+		Long:    restoreLong[1:],
+		Example: restoreExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := secret.ReadPassphraseFile(passphraseFile)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err = rootSettings.Config.Restore(f, passphrase); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Airship config restored from %q.\n", args[0])
+			return nil
+		},
+	}
+
+	addPassphraseFileFlag(cmd, &passphraseFile)
+	return cmd
+}
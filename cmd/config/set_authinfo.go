@@ -30,6 +30,12 @@ const (
 Create or modify a user credential in the airshipctl config file.
 
 Note that specifying more than one authentication method is an error.
+
+Users of clusters fronted by an identity provider can be defined with an auth
+provider plugin, such as oidc, or with an exec credential plugin. The
+arguments of an auth provider and the environment of an exec plugin are given
+as key=value pairs, key- removes a previously set key. Setting an auth provider
+removes an existing exec plugin of the user credential and vice versa.
 `
 
 	setAuthInfoExample = `
@@ -51,6 +57,20 @@ airshipctl config set-credentials admin \
 airshipctl config set-credentials admin \
   --client-certificate=$HOME/.kube/admin.crt \
   --embed-certs
+
+# Authenticate the oidc user with an OpenID Connect identity provider
+airshipctl config set-credentials oidc \
+  --auth-provider=oidc \
+  --auth-provider-arg=idp-issuer-url=https://dex.example.com \
+  --auth-provider-arg=client-id=airshipctl \
+  --auth-provider-arg=refresh-token=ChlxY2VhZ
+
+# Obtain the credentials of the admin user from an exec plugin
+airshipctl config set-credentials admin \
+  --exec-command=kubectl \
+  --exec-arg=oidc-login \
+  --exec-arg=get-token \
+  --exec-env=HTTPS_PROXY=http://proxy.example.com:3128
 `
 )
 
@@ -121,4 +141,40 @@ func addSetAuthInfoFlags(o *config.AuthInfoOptions, cmd *cobra.Command) {
 		"embed-certs",
 		false,
 		"if set, embed the client certificate/key into the credential")
+
+	flags.StringVar(
+		&o.AuthProvider,
+		"auth-provider",
+		"",
+		"auth provider plugin for the credential; mutually exclusive with exec-command flag.")
+
+	flags.StringSliceVar(
+		&o.AuthProviderArgs,
+		"auth-provider-arg",
+		nil,
+		"key=value argument of the auth provider, key- removes the argument")
+
+	flags.StringVar(
+		&o.ExecCommand,
+		"exec-command",
+		"",
+		"command of the exec credential plugin; mutually exclusive with auth-provider flag.")
+
+	flags.StringVar(
+		&o.ExecAPIVersion,
+		"exec-api-version",
+		"",
+		"API version of the exec credential plugin, defaults to "+config.DefaultExecAPIVersion)
+
+	flags.StringSliceVar(
+		&o.ExecArgs,
+		"exec-arg",
+		nil,
+		"argument of the exec credential plugin, replaces all previously set arguments")
+
+	flags.StringSliceVar(
+		&o.ExecEnv,
+		"exec-env",
+		nil,
+		"key=value environment variable of the exec credential plugin, key- removes the variable")
 }
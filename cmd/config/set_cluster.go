@@ -22,16 +22,26 @@ import (
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/log"
 )
 
 const (
+	// caSecretKey is the key of the certificate authority in Secrets, as
+	// used by cluster-api for cluster CA secrets
+	caSecretKey = "tls.crt"
+
 	setClusterLong = `
 Create or modify a cluster in the airshipctl config files.
 
 Since a cluster can be either "ephemeral" or "target", you must specify
 cluster-type when managing clusters.
+
+Certificate authority data can be embedded into the kubeconfig from a file,
+from base64 encoded data given on the command line, or from the "tls.crt" key
+of a Secret found in the documents of the given phase. Only one of these
+sources can be given. The data must contain a PEM encoded certificate.
 `
 
 	setClusterExample = `
@@ -46,6 +56,13 @@ airshipctl config set-cluster exampleCluster \
   --client-certificate-authority=$HOME/.airship/ca/kubernetes.ca.crt \
   --embed-certs
 
+# Embed certificate authority data from the exampleCluster-ca Secret
+# of the initinfra phase
+airshipctl config set-cluster exampleCluster \
+  --cluster-type=target \
+  --insecure-skip-tls-verify=false \
+  --certificate-authority-secret=exampleCluster-ca
+
 # Disable certificate checking for the target exampleCluster
 airshipctl config set-cluster exampleCluster
   --cluster-type=target \
@@ -62,7 +79,7 @@ airshipctl config set-cluster exampleCluster \
 // NewSetClusterCommand creates a command for creating and modifying clusters
 // in the airshipctl config file.
 func NewSetClusterCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	o := &config.ClusterOptions{}
+	o := &setClusterOptions{}
 	cmd := &cobra.Command{
 		Use:     "set-cluster NAME",
 		Short:   "Manage clusters",
@@ -71,7 +88,17 @@ func NewSetClusterCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			o.Name = args[0]
-			modified, err := config.RunSetCluster(o, rootSettings.Config, true)
+			if o.caSecret != "" {
+				if o.CertificateAuthority != "" || o.CertificateAuthorityData != "" {
+					return config.ErrConflictingCertificateAuthoritySecret{}
+				}
+				caData, err := caDataFromSecret(rootSettings, o.phase, o.caSecret)
+				if err != nil {
+					return err
+				}
+				o.CertificateAuthorityData = caData
+			}
+			modified, err := config.RunSetCluster(&o.ClusterOptions, rootSettings.Config, true)
 			if err != nil {
 				return err
 			}
@@ -90,7 +117,36 @@ func NewSetClusterCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 	return cmd
 }
 
-func addSetClusterFlags(o *config.ClusterOptions, cmd *cobra.Command) {
+// setClusterOptions extends config.ClusterOptions with the options used to
+// look up certificate authority data in the documents of a phase
+type setClusterOptions struct {
+	config.ClusterOptions
+	caSecret string
+	phase    string
+}
+
+// caDataFromSecret returns the certificate authority stored in the
+// caSecretKey of the Secret named secretName in the documents of the phase
+func caDataFromSecret(rootSettings *environment.AirshipCTLSettings, phase, secretName string) (string, error) {
+	entrypoint, err := rootSettings.CurrentContextEntryPoint(phase)
+	if err != nil {
+		return "", err
+	}
+
+	bundle, err := document.NewBundleByPath(entrypoint)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := bundle.SelectOne(document.NewSelector().ByKind(document.SecretKind).ByName(secretName))
+	if err != nil {
+		return "", err
+	}
+
+	return document.GetSecretDataKey(doc, caSecretKey)
+}
+
+func addSetClusterFlags(o *setClusterOptions, cmd *cobra.Command) {
 	flags := cmd.Flags()
 
 	flags.StringVar(
@@ -127,4 +183,22 @@ func addSetClusterFlags(o *config.ClusterOptions, cmd *cobra.Command) {
 		"embed-certs",
 		false,
 		"if set, embed the client certificate/key into the cluster")
+
+	flags.StringVar(
+		&o.CertificateAuthorityData,
+		"certificate-authority-data",
+		"",
+		"base64 encoded certificate authority data to embed into the cluster")
+
+	flags.StringVar(
+		&o.caSecret,
+		"certificate-authority-secret",
+		"",
+		"name of the Secret holding the certificate authority data to embed into the cluster")
+
+	flags.StringVar(
+		&o.phase,
+		"phase",
+		config.InitinfraPhase,
+		"phase whose documents contain the certificate authority secret")
 }
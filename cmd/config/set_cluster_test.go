@@ -65,6 +65,13 @@ func TestConfigSetCluster(t *testing.T) {
 			Cmd:     cmd.NewSetClusterCommand(nil),
 			Error:   fmt.Errorf("accepts at most %d arg(s), received %d", 1, 2),
 		},
+		{
+			Name: "config-cmd-set-cluster-ca-secret-and-data",
+			CmdLine: "--cluster-type=target --insecure-skip-tls-verify=false --certificate-authority-data=Y2E= " +
+				"--certificate-authority-secret=exampleCluster-ca exampleCluster",
+			Cmd:   cmd.NewSetClusterCommand(nil),
+			Error: config.ErrConflictingCertificateAuthoritySecret{},
+		},
 	}
 
 	for _, tt := range cmdTests {
@@ -149,6 +156,32 @@ func TestSetClusterWithCAFileData(t *testing.T) {
 	test.run(t)
 }
 
+func TestSetClusterWithCAData(t *testing.T) {
+	given, cleanupGiven := testutil.InitConfig(t)
+	defer cleanupGiven(t)
+
+	caData, err := ioutil.ReadFile("../../pkg/config/testdata/ca.crt")
+	require.NoError(t, err)
+
+	test := setClusterTest{
+		description: "Testing 'airshipctl config set-cluster' with certificate authority data",
+		givenConfig: given,
+		args:        []string{testCluster},
+		flags: []string{
+			"--cluster-type=target",
+			"--certificate-authority-data=" + string(caData),
+			"--insecure-skip-tls-verify=false",
+		},
+		expectedOutput: fmt.Sprintf("Cluster %q of type %q created.\n", testCluster, config.Target),
+	}
+	test.run(t)
+
+	cluster, err := given.GetCluster(testCluster, config.Target)
+	require.NoError(t, err)
+	assert.Contains(t, string(cluster.KubeCluster().CertificateAuthorityData), "-----BEGIN CERTIFICATE-----")
+	assert.False(t, cluster.KubeCluster().InsecureSkipTLSVerify)
+}
+
 func TestSetCluster(t *testing.T) {
 	given, cleanupGiven := testutil.InitConfig(t)
 	defer cleanupGiven(t)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
 )
@@ -81,6 +82,8 @@ func NewSetContextCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 	}
 
 	addSetContextFlags(o, cmd)
+	completion.SetArgNames(cmd, completion.ContextNames)
+	completion.SetFlagNames(cmd, "manifest", completion.ManifestNames)
 	return cmd
 }
 
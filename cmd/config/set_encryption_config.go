@@ -0,0 +1,113 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+const (
+	setEncryptionConfigLong = `
+Create or modify an encryption configuration in the airshipctl config file.
+
+An encryption configuration lists the key providers used to encrypt and
+decrypt the secrets of the manifests referring to it: key files, KMS keys
+and SOPS recipients. The --kms-uri and --sops-recipient flags replace the
+previously set lists.
+`
+
+	setEncryptionConfigExample = `
+# Create an encryption configuration using a key pair on disk
+airshipctl config set-encryption-config exampleConfig \
+  --encryption-key-path=$HOME/.airship/keys/public.key \
+  --decryption-key-path=$HOME/.airship/keys/private.key
+
+# Use a KMS key and an age recipient for the secrets of the default manifest
+airshipctl config set-encryption-config exampleConfig \
+  --kms-uri=gcpkms://projects/airship/locations/global/keyRings/site/cryptoKeys/secrets \
+  --sops-recipient=age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p \
+  --manifest=default
+`
+)
+
+// NewSetEncryptionConfigCommand creates a command for creating and modifying
+// encryption configurations in the airshipctl config file.
+func NewSetEncryptionConfigCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	o := &config.EncryptionConfigOptions{}
+	cmd := &cobra.Command{
+		Use:     "set-encryption-config NAME",
+		Short:   "Manage encryption configs",
+		Long:    setEncryptionConfigLong[1:],
+		Example: setEncryptionConfigExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Name = args[0]
+			modified, err := config.RunSetEncryptionConfig(o, rootSettings.Config, true)
+			if err != nil {
+				return err
+			}
+			if modified {
+				fmt.Fprintf(cmd.OutOrStdout(), "Encryption config %q modified.\n", o.Name)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Encryption config %q created.\n", o.Name)
+			}
+			return nil
+		},
+	}
+
+	addSetEncryptionConfigFlags(o, cmd)
+	completion.SetFlagNames(cmd, "manifest", completion.ManifestNames)
+	return cmd
+}
+
+func addSetEncryptionConfigFlags(o *config.EncryptionConfigOptions, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&o.EncryptionKeyPath,
+		"encryption-key-path",
+		"",
+		"path to the public key used to encrypt secrets")
+
+	flags.StringVar(
+		&o.DecryptionKeyPath,
+		"decryption-key-path",
+		"",
+		"path to the private key used to decrypt secrets")
+
+	flags.StringSliceVar(
+		&o.KMSURIs,
+		"kms-uri",
+		nil,
+		"URI of a KMS key used for secrets, may be repeated")
+
+	flags.StringSliceVar(
+		&o.SOPSRecipients,
+		"sops-recipient",
+		nil,
+		"age or PGP recipient secrets are encrypted for with SOPS, may be repeated")
+
+	flags.StringSliceVar(
+		&o.Manifests,
+		"manifest",
+		nil,
+		"name of a manifest that will use the encryption config, may be repeated")
+}
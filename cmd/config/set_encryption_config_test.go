@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmd "opendev.org/airship/airshipctl/cmd/config"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestConfigSetEncryptionConfig(t *testing.T) {
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "config-cmd-set-encryption-config-with-help",
+			CmdLine: "--help",
+			Cmd:     cmd.NewSetEncryptionConfigCommand(nil),
+		},
+		{
+			Name:    "config-cmd-set-encryption-config-too-few-args",
+			CmdLine: "",
+			Cmd:     cmd.NewSetEncryptionConfigCommand(nil),
+			Error:   fmt.Errorf("accepts %d arg(s), received %d", 1, 0),
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
+
+func TestSetEncryptionConfig(t *testing.T) {
+	given, cleanupGiven := testutil.InitConfig(t)
+	defer cleanupGiven(t)
+	given.Manifests[testManifest] = config.NewManifest()
+
+	settings := &environment.AirshipCTLSettings{Config: given}
+	run := func(args ...string) (string, error) {
+		buf := &bytes.Buffer{}
+		command := cmd.NewSetEncryptionConfigCommand(settings)
+		command.SetOut(buf)
+		command.SetArgs(args)
+		err := command.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("keys",
+		"--decryption-key-path=/tmp/private.key",
+		"--kms-uri=gcpkms://projects/airship/cryptoKeys/secrets",
+		"--manifest="+testManifest)
+	require.NoError(t, err)
+	assert.Equal(t, "Encryption config \"keys\" created.\n", out)
+
+	out, err = run("keys", "--sops-recipient=age1recipient")
+	require.NoError(t, err)
+	assert.Equal(t, "Encryption config \"keys\" modified.\n", out)
+
+	expected := &config.EncryptionConfig{
+		DecryptionKeyPath: "/tmp/private.key",
+		KMSURIs:           []string{"gcpkms://projects/airship/cryptoKeys/secrets"},
+		SOPSRecipients:    []string{"age1recipient"},
+	}
+	assert.Equal(t, expected, given.EncryptionConfigs["keys"])
+	assert.Equal(t, "keys", given.Manifests[testManifest].EncryptionConfig)
+
+	_, err = run("keys", "--kms-uri=not-a-uri")
+	assert.Equal(t, config.ErrInvalidKMSURI{URI: "not-a-uri"}, err)
+
+	_, err = run("keys", "--manifest=unknown")
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
 )
@@ -57,5 +58,6 @@ func NewUseContextCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 		},
 	}
 
+	completion.SetArgNames(cmd, completion.ContextNames)
 	return cmd
 }
@@ -27,10 +27,9 @@ func NewDocumentCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Com
 		Use:   "document",
 		Short: "Manage deployment documents",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			log.Init(rootSettings.Debug, cmd.OutOrStderr())
-
 			// Load or Initialize airship Config
 			rootSettings.InitConfig()
+			log.Init(rootSettings.Debug, cmd.OutOrStderr())
 		},
 	}
 
@@ -17,26 +17,98 @@ limitations under the License.
 package phase
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
 	"opendev.org/airship/airshipctl/pkg/phase/apply"
 )
 
 const (
 	applyLong = `
-Apply specific phase to kubernetes cluster such as control-plane, workloads, initinfra
+Apply specific phase to kubernetes cluster such as control-plane, workloads, initinfra.
+
+Every applied resource is labeled with airshipit.org/deployment=PHASE_NAME. When
+--prune is given, resources carrying the label of the phase that are no longer
+part of its documents are listed and deleted. Combine --prune with --dry-run to
+only preview what would be deleted. Namespaces, PersistentVolumes and
+BareMetalHosts are never pruned, more resources can be protected with
+--prune-protect. Protected resources are reported instead of being deleted.
+
+Before anything is applied, documents are validated against the OpenAPI schema
+of the cluster. Unknown fields and fields of the wrong type are reported for
+every document, use --validate=false to skip the validation.
+
+The state of the resources is saved before they are applied, so that a failed
+apply can be undone with "airshipctl phase rollback", or right away with
+--rollback-on-failure.
+
+With --dry-run=server the documents are sent to the cluster without being
+persisted, so that validation and admission webhook rejections are reported
+before the real deployment.
+
+Documents are applied in dependency order. A document depends on the documents
+listed in its airshipit.org/depends-on annotation, as Kind/name or
+Kind/namespace/name, on its Namespace and on the CustomResourceDefinition of its
+kind. Dependencies have to be ready before their dependents are applied, and
+dependents of documents that fail are skipped. With --max-parallel, documents
+that don't depend on each other are applied concurrently.
+
+With --create-namespaces, the namespaces documents are put in that neither exist
+in the cluster nor are part of the phase are created before anything is
+applied. They are labeled with the phase like the applied resources.
+
+Warning events of the applied resources and of the objects they create, such as
+FailedScheduling of pods or image pull back-offs, are printed while the phase
+is applied and, with --wait, until the resources are ready.
 `
 	applyExample = `
 # Apply initinfra phase to a cluster
 airshipctl phase apply initinfra
+
+# Preview the resources removed from the initinfra phase
+airshipctl phase apply initinfra --prune --dry-run
+
+# Check that the cluster, including its admission webhooks, accepts the phase
+airshipctl phase apply initinfra --dry-run=server
+
+# Prune the initinfra phase but never delete the ironic-credentials Secret
+airshipctl phase apply initinfra --prune --prune-protect Secret/metal3/ironic-credentials
+
+# Apply the workloads phase to a fresh cluster, creating the namespaces it uses
+airshipctl phase apply workloads --create-namespaces
+
+# Apply a phase containing large CRDs on the server side
+airshipctl phase apply initinfra --server-side
+
+# Apply initinfra phase and wait up to 10 minutes for each resource to be ready
+airshipctl phase apply initinfra --wait --wait-timeout 10m
+
+# Wait up to an hour for BareMetalHosts to be provisioned, 5 minutes for the rest
+airshipctl phase apply initinfra --wait --kind-wait-timeout BareMetalHost.metal3.io=60m
+
+# Apply up to 10 documents of the workloads phase at the same time
+airshipctl phase apply workloads --max-parallel 10
+
+# Apply initinfra phase and report the action taken on every resource as JSON
+airshipctl phase apply initinfra -o json
+
+# Apply initinfra phase and save a summary for the CI pipeline
+airshipctl phase apply initinfra --report-file apply-report.json
 `
 )
 
 // NewApplyCommand creates a command to apply phase to k8s cluster.
 func NewApplyCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
 	i := apply.NewOptions(rootSettings)
+	var output, reportFile string
 
 	applyCmd := &cobra.Command{
 		Use:     "apply PHASE_NAME",
@@ -46,31 +118,206 @@ func NewApplyCommand(rootSettings *environment.AirshipCTLSettings, factory clien
 		Example: applyExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			i.PhaseName = args[0]
+			switch output {
+			case kubectl.OutputTable:
+			case kubectl.OutputJSON:
+				// Keep stdout parsable while waiting for resources
+				i.ProgressOut = cmd.ErrOrStderr()
+			default:
+				return kubectl.ErrInvalidOutputFormat{Format: output}
+			}
+			// The selected profile decides unless --dry-run is given
+			if !cmd.Flags().Changed("dry-run") {
+				i.DryRun = rootSettings.DryRun
+			}
+			if !cmd.Flags().Changed("create-namespaces") {
+				i.CreateNamespaces = rootSettings.CreateNamespaces
+			}
 			client, err := factory(rootSettings)
 			if err != nil {
 				return err
 			}
 			i.Client = client
 
-			return i.Run()
+			start := time.Now()
+			result, err := i.Run()
+			if reportFile != "" {
+				if reportErr := apply.NewReport(i.PhaseName, start, result, err).Write(reportFile); err == nil {
+					err = reportErr
+				}
+			}
+			if result == nil {
+				return err
+			}
+			if printErr := result.Print(cmd.OutOrStdout(), output); err == nil {
+				err = printErr
+			}
+			return err
 		},
 	}
 	addApplyFlags(i, applyCmd)
+	applyCmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		kubectl.OutputTable,
+		`format of the report of the action taken on every resource, "table" or "json"`)
+	applyCmd.Flags().StringVar(
+		&reportFile,
+		"report-file",
+		"",
+		"path of a file to write a JSON summary of the apply to, including the resources that were"+
+			" created, changed, pruned or failed and timings. It is written even if the apply fails")
+	completion.SetArgNames(applyCmd, completion.PhaseNames)
 	return applyCmd
 }
 
 func addApplyFlags(i *apply.Options, cmd *cobra.Command) {
 	flags := cmd.Flags()
-	flags.BoolVar(
-		&i.DryRun,
+	flags.Var(
+		&dryRunValue{options: i, strategy: kubectl.DryRunNone},
 		"dry-run",
-		false,
-		"don't deliver documents to the cluster, simulate the changes instead")
+		`don't deliver documents to the cluster, simulate the changes instead. Must be "none",`+
+			` "client" or "server". With "server", documents are validated by the cluster`+
+			` including admission webhooks but not persisted`)
+	flags.Lookup("dry-run").NoOptDefVal = kubectl.DryRunClient
 
 	flags.BoolVar(
 		&i.Prune,
 		"prune",
 		false,
-		`if set to true, command will delete all kubernetes resources that are not`+
-			` defined in airship documents and have airshipit.org/deployed=apply label`)
+		`if set to true, command will delete all kubernetes resources that were applied`+
+			` with this phase and are no longer defined in airship documents`)
+
+	flags.StringArrayVar(
+		&i.PruneProtections,
+		"prune-protect",
+		nil,
+		"resources that are never pruned in addition to Namespaces, PersistentVolumes and BareMetalHosts,"+
+			" as Kind[.group], Kind[.group]/name or Kind[.group]/namespace/name. Can be repeated")
+
+	flags.BoolVar(
+		&i.CreateNamespaces,
+		"create-namespaces",
+		false,
+		"create the namespaces documents are put in if they are missing from the cluster and the phase."+
+			" Created namespaces are labeled with the phase. Defaults to createNamespaces of the profile")
+
+	flags.BoolVar(
+		&i.ServerSide,
+		"server-side",
+		false,
+		"apply documents on the server instead of the client, required for resources too large"+
+			" for the last-applied-configuration annotation. Such resources are pruned through"+
+			" the deployment label of the phase")
+
+	flags.StringVar(
+		&i.FieldManager,
+		"field-manager",
+		"airshipctl",
+		"name of the manager used to track field ownership with --server-side")
+
+	flags.BoolVar(
+		&i.ForceConflicts,
+		"force-conflicts",
+		false,
+		"take ownership of fields managed by others when applying with --server-side")
+
+	flags.BoolVar(
+		&i.Validate,
+		"validate",
+		true,
+		"validate documents against the OpenAPI schema of the cluster before applying them")
+
+	flags.BoolVar(
+		&i.RollbackOnFailure,
+		"rollback-on-failure",
+		false,
+		"restore the resources of the phase to their state before the apply if it fails")
+
+	flags.BoolVar(
+		&i.Wait,
+		"wait",
+		false,
+		"wait until all applied resources are reconciled and ready")
+
+	flags.DurationVar(
+		&i.WaitTimeout,
+		"wait-timeout",
+		5*time.Minute,
+		"how long to wait for each resource with --wait, can be overridden per document"+
+			" with the airshipit.org/wait-timeout annotation")
+
+	flags.Var(
+		&kindTimeoutsValue{options: i},
+		"kind-wait-timeout",
+		"how long to wait for all resources of a kind with --wait, as Kind[.group]=duration."+
+			" Takes precedence over --wait-timeout but not over the annotation. Can be repeated")
+
+	flags.IntVar(
+		&i.MaxParallel,
+		"max-parallel",
+		1,
+		"how many documents that don't depend on each other are applied at the same time")
+
+	flags.IntVar(
+		&i.Retries,
+		"retries",
+		client.DefaultRetryOptions().Retries,
+		"how many times requests failing with conflicts, throttling or network errors are retried")
+}
+
+// dryRunValue is the value of the --dry-run flag, it sets the dry run
+// options of the phase apply according to the given strategy
+type dryRunValue struct {
+	options  *apply.Options
+	strategy string
+}
+
+func (v *dryRunValue) Set(strategy string) error {
+	switch strategy {
+	case kubectl.DryRunNone, kubectl.DryRunClient, kubectl.DryRunServer:
+	default:
+		return kubectl.ErrInvalidDryRunStrategy{Strategy: strategy}
+	}
+	v.strategy = strategy
+	v.options.DryRun = strategy == kubectl.DryRunClient
+	v.options.ServerDryRun = strategy == kubectl.DryRunServer
+	return nil
+}
+
+func (v *dryRunValue) String() string {
+	return v.strategy
+}
+
+func (v *dryRunValue) Type() string {
+	return "string"
+}
+
+// kindTimeoutsValue is the value of the --kind-wait-timeout flag, every
+// occurrence sets the wait timeout of one kind
+type kindTimeoutsValue struct {
+	options *apply.Options
+	values  []string
+}
+
+func (v *kindTimeoutsValue) Set(value string) error {
+	gk, timeout, err := status.ParseKindTimeout(value)
+	if err != nil {
+		return err
+	}
+	if v.options.KindWaitTimeouts == nil {
+		v.options.KindWaitTimeouts = map[schema.GroupKind]time.Duration{}
+	}
+	v.options.KindWaitTimeouts[gk] = timeout
+	v.values = append(v.values, value)
+	return nil
+}
+
+func (v *kindTimeoutsValue) String() string {
+	return strings.Join(v.values, ",")
+}
+
+func (v *kindTimeoutsValue) Type() string {
+	return "stringArray"
 }
@@ -15,12 +15,15 @@
 package phase_test
 
 import (
+	"fmt"
 	"testing"
 
 	"opendev.org/airship/airshipctl/cmd/phase"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -40,6 +43,26 @@ func TestNewApplyCommand(t *testing.T) {
 			CmdLine: "--help",
 			Cmd:     phase.NewApplyCommand(fakeRootSettings, testClientFactory),
 		},
+		{
+			Name:    "phase-apply-cmd-invalid-dry-run",
+			CmdLine: "initinfra --dry-run=local",
+			Cmd:     phase.NewApplyCommand(fakeRootSettings, testClientFactory),
+			Error: fmt.Errorf("invalid argument %q for %q flag: %v",
+				"local", "--dry-run", kubectl.ErrInvalidDryRunStrategy{Strategy: "local"}),
+		},
+		{
+			Name:    "phase-apply-cmd-invalid-kind-wait-timeout",
+			CmdLine: "initinfra --kind-wait-timeout BareMetalHost=forever",
+			Cmd:     phase.NewApplyCommand(fakeRootSettings, testClientFactory),
+			Error: fmt.Errorf("invalid argument %q for %q flag: %v", "BareMetalHost=forever",
+				"--kind-wait-timeout", status.ErrInvalidKindTimeout{Value: "BareMetalHost=forever"}),
+		},
+		{
+			Name:    "phase-apply-cmd-invalid-output",
+			CmdLine: "initinfra -o yaml",
+			Cmd:     phase.NewApplyCommand(fakeRootSettings, testClientFactory),
+			Error:   kubectl.ErrInvalidOutputFormat{Format: "yaml"},
+		},
 	}
 	for _, testcase := range tests {
 		testutil.RunTest(t, testcase)
@@ -0,0 +1,75 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	listLong = `
+List the phases declared by the Phase documents of the current context, with
+their executor, the context of the cluster they are run against, the entry
+point of their documents and the PhasePlans referencing them.
+`
+
+	listExample = `
+# List all phases
+airshipctl phase list
+
+# List the phases of the deploy plan run against the target cluster, as YAML
+airshipctl phase list --plan deploy --cluster target-cluster -o yaml
+`
+)
+
+// NewListCommand creates a command listing the phases declared by Phase
+// documents
+func NewListCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var (
+		filter phase.ListFilter
+		output string
+	)
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the phases declared by Phase documents",
+		Long:    listLong[1:],
+		Example: listExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != phase.OutputTable && output != phase.OutputYAML {
+				return phase.ErrInvalidOutputFormat{Format: output}
+			}
+			list, err := phase.NewPhaseList(rootSettings, filter)
+			if err != nil {
+				return err
+			}
+			return list.Print(cmd.OutOrStdout(), output)
+		},
+	}
+
+	flags := listCmd.Flags()
+	flags.StringVar(&filter.Cluster, "cluster", "",
+		"list only the phases run against the cluster of the given context")
+	flags.StringVar(&filter.Plan, "plan", "",
+		"list only the phases referenced by the given PhasePlan")
+	flags.StringVarP(&output, "output", "o", phase.OutputTable,
+		`output format, "table" or "yaml"`)
+	completion.SetFlagNames(listCmd, "cluster", completion.ContextNames)
+	return listCmd
+}
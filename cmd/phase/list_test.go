@@ -0,0 +1,55 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"testing"
+
+	"opendev.org/airship/airshipctl/cmd/phase"
+	pkgphase "opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestNewListCommand(t *testing.T) {
+	settings, cleanupCfg := makeRenderSettings(t)
+	defer cleanupCfg(t)
+
+	tests := []*testutil.CmdTest{
+		{
+			Name:    "phase-list-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     phase.NewListCommand(settings),
+		},
+		{
+			Name:    "phase-list-cmd",
+			CmdLine: "",
+			Cmd:     phase.NewListCommand(settings),
+		},
+		{
+			Name:    "phase-list-cmd-yaml",
+			CmdLine: "--plan deploy -o yaml",
+			Cmd:     phase.NewListCommand(settings),
+		},
+		{
+			Name:    "phase-list-cmd-invalid-output",
+			CmdLine: "-o json",
+			Cmd:     phase.NewListCommand(settings),
+			Error:   pkgphase.ErrInvalidOutputFormat{Format: "json"},
+		},
+	}
+	for _, testcase := range tests {
+		testutil.RunTest(t, testcase)
+	}
+}
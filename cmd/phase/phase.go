@@ -36,15 +36,20 @@ func NewPhaseCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comman
 		Short: "Manage phases",
 		Long:  clusterLong[1:],
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			log.Init(rootSettings.Debug, cmd.OutOrStderr())
-
 			// Load or Initialize airship Config
 			rootSettings.InitConfig()
+			log.Init(rootSettings.Debug, cmd.OutOrStderr())
 		},
 	}
 
 	phaseRootCmd.AddCommand(NewApplyCommand(rootSettings, client.DefaultClient))
+	phaseRootCmd.AddCommand(NewListCommand(rootSettings))
 	phaseRootCmd.AddCommand(NewRenderCommand(rootSettings))
+	phaseRootCmd.AddCommand(NewRollbackCommand(rootSettings, client.DefaultClient))
+	phaseRootCmd.AddCommand(NewRunCommand(rootSettings, client.DefaultClient))
+	phaseRootCmd.AddCommand(NewStatusCommand(rootSettings, client.DefaultClient))
+	phaseRootCmd.AddCommand(NewTreeCommand(rootSettings))
+	phaseRootCmd.AddCommand(NewValidateCommand(rootSettings, client.DefaultClient))
 
 	return phaseRootCmd
 }
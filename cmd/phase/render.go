@@ -15,13 +15,23 @@
 package phase
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
+	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
 	"opendev.org/airship/airshipctl/pkg/phase/render"
 )
 
 const (
+	renderLong = `
+Render the documents of a phase. The documents of a phase declared by a Phase
+document are built from its documentEntryPoint, the ones of other phases from
+the directory named after the phase.
+`
+
 	renderExample = `
 # Get all 'initinfra' phase documents containing labels "app=helm" and
 # "service=tiller"
@@ -30,27 +40,51 @@ airshipctl phase render initinfra -l app=helm,service=tiller
 # Get all documents containing labels "app=helm" and "service=tiller"
 # and kind 'Deployment'
 airshipctl phase render initinfra -l app=helm,service=tiller -k Deployment
+
+# Write all 'initinfra' phase documents to a file
+airshipctl phase render initinfra --output-file initinfra.yaml
 `
 )
 
 // NewRenderCommand create a new command for document rendering
 func NewRenderCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
 	renderSettings := &render.Settings{AirshipCTLSettings: rootSettings}
+	var outputFile string
 	renderCmd := &cobra.Command{
 		Use:     "render PHASE_NAME",
 		Short:   "Render phase documents from model",
+		Long:    renderLong[1:],
 		Example: renderExample,
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			path, err := renderSettings.Config.CurrentContextEntryPoint(args[0])
+			path, err := phase.DocumentEntryPoint(renderSettings.AirshipCTLSettings, args[0])
 			if err != nil {
 				return err
 			}
-			return renderSettings.Render(path, cmd.OutOrStdout())
+			if outputFile == "" {
+				return renderSettings.Render(path, cmd.OutOrStdout())
+			}
+
+			// Documents may hold secrets
+			f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			err = renderSettings.Render(path, f)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			return err
 		},
 	}
 
 	addRenderFlags(renderSettings, renderCmd)
+	renderCmd.Flags().StringVar(
+		&outputFile,
+		"output-file",
+		"",
+		"path of a file to write the documents to instead of stdout")
+	completion.SetArgNames(renderCmd, completion.PhaseNames)
 	return renderCmd
 }
 
@@ -15,8 +15,13 @@
 package phase_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/cmd/phase"
@@ -25,9 +30,9 @@ import (
 	"opendev.org/airship/airshipctl/testutil"
 )
 
-func TestRender(t *testing.T) {
+func makeRenderSettings(t *testing.T) (*environment.AirshipCTLSettings, func(*testing.T)) {
+	t.Helper()
 	cfg, cleanupCfg := testutil.InitConfig(t)
-	defer cleanupCfg(t)
 	cfg.CurrentContext = "def_ephemeral"
 	cfg.Manifests["test"] = &config.Manifest{
 		TargetPath:            "testdata",
@@ -41,7 +46,12 @@ func TestRender(t *testing.T) {
 	ctx, err := cfg.GetContext("def_ephemeral")
 	require.NoError(t, err)
 	ctx.Manifest = "test"
-	settings := &environment.AirshipCTLSettings{Config: cfg}
+	return &environment.AirshipCTLSettings{Config: cfg}, cleanupCfg
+}
+
+func TestRender(t *testing.T) {
+	settings, cleanupCfg := makeRenderSettings(t)
+	defer cleanupCfg(t)
 
 	tests := []*testutil.CmdTest{
 		{
@@ -54,8 +64,35 @@ func TestRender(t *testing.T) {
 			CmdLine: "initinfra -l app=helm,name=tiller",
 			Cmd:     phase.NewRenderCommand(settings),
 		},
+		{
+			Name:    "render-declared-phase",
+			CmdLine: "tiller -k Deployment",
+			Cmd:     phase.NewRenderCommand(settings),
+		},
 	}
 	for _, tt := range tests {
 		testutil.RunTest(t, tt)
 	}
 }
+
+func TestRenderOutputFile(t *testing.T) {
+	settings, cleanupCfg := makeRenderSettings(t)
+	defer cleanupCfg(t)
+	dir, cleanupDir := testutil.TempDir(t, "airshipctl-render-test")
+	defer cleanupDir(t)
+	outputFile := filepath.Join(dir, "initinfra.yaml")
+
+	out := &bytes.Buffer{}
+	cmd := phase.NewRenderCommand(settings)
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"initinfra", "--output-file", outputFile})
+	require.NoError(t, cmd.Execute())
+	assert.Empty(t, out.String())
+
+	info, err := os.Stat(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	rendered, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), "name: tiller-deploy")
+}
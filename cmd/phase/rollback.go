@@ -0,0 +1,97 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/pkg/phase/rollback"
+)
+
+const (
+	rollbackLong = `
+Restore the resources of a phase to their state before it was last applied.
+
+Before a phase is applied, the live state of its resources is saved next to the
+airship config. Rolling back deletes the resources created by the apply and
+reverts the others to the saved state, recreating them if they were deleted.
+CustomResourceDefinitions and resources deleted by --prune are not restored.
+
+Phases declared by a Phase document are rolled back by their executor. With
+--delete, the KubernetesApply executor deletes the resources of the phase
+labeled with its name instead, the resources depending on others first. The
+Clusterctl executor doesn't support rollback.
+`
+
+	rollbackExample = `
+# Undo the last apply of the initinfra phase
+airshipctl phase rollback initinfra
+
+# Delete the resources deployed by the initinfra phase
+airshipctl phase rollback initinfra --delete
+`
+)
+
+// NewRollbackCommand creates a command to roll back the last apply of a
+// phase
+func NewRollbackCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	o := rollback.NewOptions(rootSettings)
+	var options ifc.RollbackOptions
+
+	rollbackCmd := &cobra.Command{
+		Use:     "rollback PHASE_NAME",
+		Short:   "Roll back the last apply of a phase",
+		Long:    rollbackLong[1:],
+		Args:    cobra.ExactArgs(1),
+		Example: rollbackExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Phases which are not declared can only be restored from their
+			// snapshot
+			_, err := phase.Get(rootSettings, args[0])
+			switch err.(type) {
+			case nil:
+				engine := phase.NewEngine(rootSettings, factory)
+				engine.Out = cmd.OutOrStdout()
+				return engine.Rollback(args[0], options)
+			case phase.ErrPhaseNotFound:
+				if options.Delete {
+					return err
+				}
+			default:
+				return err
+			}
+
+			o.PhaseName = args[0]
+			kclient, err := factory(rootSettings)
+			if err != nil {
+				return err
+			}
+			o.Client = kclient
+			return o.Run()
+		},
+	}
+	rollbackCmd.Flags().BoolVar(
+		&options.Delete,
+		"delete",
+		false,
+		"delete the resources deployed by a phase declared by a Phase document instead of restoring them")
+	completion.SetArgNames(rollbackCmd, completion.PhaseNames)
+	return rollbackCmd
+}
@@ -0,0 +1,176 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/events"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+const (
+	runLong = `
+Run a phase declared by a Phase document of the current context.
+
+Phase documents are read from the phases directory next to the documents of the
+other phases of the cluster type of the current context. Each of them names the
+executor running the phase, the entry point of its documents and the context of
+the cluster it is run against, e.g.
+
+  apiVersion: airshipit.org/v1alpha1
+  kind: Phase
+  metadata:
+    name: initinfra
+  config:
+    executor: KubernetesApply
+    documentEntryPoint: initinfra
+    cluster: ephemeral-cluster
+    executorConfig:
+      wait: true
+      waitTimeout: 10m
+
+The KubernetesApply executor applies the documents of the phase like
+"airshipctl phase apply" does, its executorConfig accepts prune, serverSide,
+wait, waitTimeout and maxParallel. The Clusterctl executor runs the init or
+move action of clusterctl with the Clusterctl document of the phase, its
+executorConfig accepts action, targetContext (required by move) and namespace.
+The GenericContainer executor runs a container image as a KRM function, giving
+it the documents of the phase as a ResourceList on its standard input and
+writing the items of the ResourceList it outputs, its executorConfig accepts
+image (required), driver, cmd, env, mounts and config, the functionConfig of
+the ResourceList.
+
+Instead of the context of its cluster, a phase can name its clusterType,
+ephemeral or target, to be run against the context of the cluster of that type
+named like the cluster of the current context. With workloadCluster, a phase
+is run against a workload cluster deployed by Cluster API, e.g.
+
+    workloadCluster:
+      name: workload-cluster
+      namespace: target-infra
+      managementCluster: target-cluster
+
+The kubeconfig Cluster API generated for the cluster is read from its Secret in
+the management cluster, the cluster of the current context by default, each
+time the phase is run.
+
+Phases can list the phases which have to be run before them in dependsOn. With
+--with-dependencies, these phases and the ones they depend on are run first, in
+an order satisfying all dependencies.
+
+Once the executor completes, the phase is only reported as run when all the
+waitConditions of the phase are met, e.g.
+
+  config:
+    waitTimeout: 5m
+    waitConditions:
+      - apiVersion: apps/v1
+        kind: Deployment
+        namespace: kube-system
+        name: coredns
+      - apiVersion: v1
+        kind: ConfigMap
+        name: cluster-info
+        jsonPath: '{.data.phase}'
+        value: ready
+
+A condition without jsonPath is met when the resource is Current, one with
+jsonPath when the expression yields a value, equal to value if given. The run
+fails when the conditions are not all met within waitTimeout (10m by default).
+
+With --event-log, every event of the phases run (started, resourceApplied,
+warning, waiting, error and completed) is appended to a file as a JSON object,
+e.g. for CI systems to follow their progress.
+
+With --dry-run, the phases are not run: the executor, context and documents of
+every phase are reported, the documents of KubernetesApply phases are applied
+with a server side dry run, the other executors report what they would do, and
+the wait conditions are listed instead of waited for.
+`
+
+	runExample = `
+# Run the initinfra phase
+airshipctl phase run initinfra
+
+# Run the controlplane phase after the phases it depends on
+airshipctl phase run controlplane --with-dependencies
+
+# Run the initinfra phase, recording its events to a file
+airshipctl phase run initinfra --event-log events.json
+
+# Report what running the controlplane phase and its dependencies would do
+airshipctl phase run controlplane --with-dependencies --dry-run
+`
+)
+
+// NewRunCommand creates a command running a phase declared by a Phase
+// document
+func NewRunCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var withDependencies bool
+	var eventLog string
+	var dryRun bool
+	runCmd := &cobra.Command{
+		Use:     "run PHASE_NAME",
+		Short:   "Run a phase declared by a Phase document",
+		Long:    runLong[1:],
+		Args:    cobra.ExactArgs(1),
+		Example: runExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := phase.NewEngine(rootSettings, factory)
+			engine.Out = cmd.OutOrStdout()
+			// The selected profile decides unless --dry-run is given
+			engine.DryRun = dryRun
+			if !cmd.Flags().Changed("dry-run") {
+				engine.DryRun = rootSettings.DryRun
+			}
+			if eventLog != "" {
+				f, err := os.OpenFile(eventLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				engine.Processors = []ifc.EventProcessor{events.NewJSONWriter(f)}
+			}
+			if withDependencies {
+				return engine.RunWithDependencies(args[0])
+			}
+			return engine.Run(args[0])
+		},
+	}
+	runCmd.Flags().BoolVar(
+		&withDependencies,
+		"with-dependencies",
+		false,
+		"run the phases the phase depends on before it")
+	runCmd.Flags().StringVar(
+		&eventLog,
+		"event-log",
+		"",
+		"append the events of the phases run to this file, as one JSON object per line")
+	runCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"report what running the phases would do without changing the clusters")
+	completion.SetArgNames(runCmd, completion.PhaseNames)
+	return runCmd
+}
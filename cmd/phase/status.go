@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	statusLong = `
+Show which phases declared by the Phase documents of the current context are
+deployed to their clusters.
+
+The documents of phases run by the KubernetesApply executor are looked up in
+the cluster of the phase. A resource is present if it is labeled with the name
+of the phase and fully reconciled, degraded if it is labeled with the name of
+the phase but not reconciled yet or failed, and missing otherwise. A phase is
+Deployed when all its resources are present, Partial or NotDeployed when some
+or all are missing and Degraded when some are degraded. The state of phases
+run by other executors is Unknown, the one of phases whose cluster can't be
+reached is Error.
+
+The YAML output also has the state of every resource.
+`
+
+	statusExample = `
+# Show the state of all phases
+airshipctl phase status
+
+# Show the state of every resource of the phases of the deploy plan
+airshipctl phase status --plan deploy -o yaml
+`
+)
+
+// NewStatusCommand creates a command showing the state of phases in their
+// clusters
+func NewStatusCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var (
+		filter phase.ListFilter
+		output string
+	)
+	statusCmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show which phases are deployed to their clusters",
+		Long:    statusLong[1:],
+		Example: statusExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != phase.OutputTable && output != phase.OutputYAML {
+				return phase.ErrInvalidOutputFormat{Format: output}
+			}
+			engine := phase.NewEngine(rootSettings, factory)
+			engine.Out = cmd.OutOrStdout()
+			list, err := engine.Status(filter)
+			if err != nil {
+				return err
+			}
+			return list.Print(cmd.OutOrStdout(), output)
+		},
+	}
+
+	flags := statusCmd.Flags()
+	flags.StringVar(&filter.Cluster, "cluster", "",
+		"show only the phases run against the cluster of the given context")
+	flags.StringVar(&filter.Plan, "plan", "",
+		"show only the phases referenced by the given PhasePlan")
+	flags.StringVarP(&output, "output", "o", phase.OutputTable,
+		`output format, "table" or "yaml"`)
+	completion.SetFlagNames(statusCmd, "cluster", completion.ContextNames)
+	return statusCmd
+}
@@ -0,0 +1,70 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	treeLong = `
+Print a phase declared by a Phase document of the current context with the
+documents it deploys, as an indented tree. The kustomization of the
+documentEntryPoint of the phase is followed through the resources, generators
+and transformers it includes, down to the document files. Document paths are
+relative to the target path of the manifest of the current context.
+
+See "airshipctl plan tree" for the trees of whole plans.
+`
+
+	treeExample = `
+# Print the documents of the initinfra phase as a tree
+airshipctl phase tree initinfra
+
+# Print them as a graphviz graph
+airshipctl phase tree initinfra -o dot
+`
+)
+
+// NewTreeCommand creates a command printing a phase and its documents as a
+// tree
+func NewTreeCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var output string
+	treeCmd := &cobra.Command{
+		Use:     "tree PHASE_NAME",
+		Short:   "Print a phase and its documents as a tree",
+		Long:    treeLong[1:],
+		Example: treeExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != phase.OutputTree && output != phase.OutputDOT {
+				return phase.ErrInvalidOutputFormat{Format: output, Formats: []string{phase.OutputTree, phase.OutputDOT}}
+			}
+			tree, err := phase.NewPhaseTree(rootSettings, args[0])
+			if err != nil {
+				return err
+			}
+			return tree.Print(cmd.OutOrStdout(), output)
+		},
+	}
+	treeCmd.Flags().StringVarP(&output, "output", "o", phase.OutputTree,
+		`output format, "tree" or "dot"`)
+	completion.SetArgNames(treeCmd, completion.PhaseNames)
+	return treeCmd
+}
@@ -0,0 +1,58 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"testing"
+
+	"opendev.org/airship/airshipctl/cmd/phase"
+	pkgphase "opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestNewTreeCommand(t *testing.T) {
+	settings, cleanupCfg := makeRenderSettings(t)
+	defer cleanupCfg(t)
+
+	tests := []*testutil.CmdTest{
+		{
+			Name:    "phase-tree-cmd-with-help",
+			CmdLine: "--help",
+			Cmd:     phase.NewTreeCommand(settings),
+		},
+		{
+			Name:    "phase-tree-cmd",
+			CmdLine: "tiller",
+			Cmd:     phase.NewTreeCommand(settings),
+		},
+		{
+			Name:    "phase-tree-cmd-dot",
+			CmdLine: "tiller -o dot",
+			Cmd:     phase.NewTreeCommand(settings),
+		},
+		{
+			Name:    "phase-tree-cmd-invalid-output",
+			CmdLine: "tiller -o json",
+			Cmd:     phase.NewTreeCommand(settings),
+			Error: pkgphase.ErrInvalidOutputFormat{
+				Format:  "json",
+				Formats: []string{pkgphase.OutputTree, pkgphase.OutputDOT},
+			},
+		},
+	}
+	for _, testcase := range tests {
+		testutil.RunTest(t, testcase)
+	}
+}
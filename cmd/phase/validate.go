@@ -0,0 +1,80 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/cmd/completion"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	validateLong = `
+Validate a phase declared by a Phase document without running it.
+
+The documents of the phase are built from its documentEntryPoint and checked
+against the expectations of its executor, e.g. the KubernetesApply executor
+expects documents to deploy whose dependencies can be ordered and the
+Clusterctl executor expects a Clusterctl document. The executorConfig of the
+phase must only have fields known to its executor, and the contexts it
+references must exist.
+
+The documents deployed to the cluster of the phase are also checked against the
+OpenAPI schema of that cluster, which is downloaded from it. Nothing is changed
+in the cluster, use --schema=false to validate the phase without reaching it.
+`
+
+	validateExample = `
+# Validate the initinfra phase
+airshipctl phase validate initinfra
+
+# Validate the initinfra phase without reaching its cluster
+airshipctl phase validate initinfra --schema=false
+`
+)
+
+// NewValidateCommand creates a command validating a phase declared by a
+// Phase document
+func NewValidateCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var schema bool
+	validateCmd := &cobra.Command{
+		Use:     "validate PHASE_NAME",
+		Short:   "Validate a phase declared by a Phase document",
+		Long:    validateLong[1:],
+		Args:    cobra.ExactArgs(1),
+		Example: validateExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := phase.NewEngine(rootSettings, factory)
+			engine.Out = cmd.OutOrStdout()
+			if err := engine.Validate(args[0], schema); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Phase %s is valid\n", args[0])
+			return nil
+		},
+	}
+	validateCmd.Flags().BoolVar(
+		&schema,
+		"schema",
+		true,
+		"validate the documents against the OpenAPI schema of the cluster of the phase")
+	completion.SetArgNames(validateCmd, completion.PhaseNames)
+	return validateCmd
+}
@@ -0,0 +1,141 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package plan
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/events"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+const (
+	runLong = `
+Run the phases of a plan declared by a PhasePlan document of the current
+context. PhasePlan documents are read from the phases directory, next to the
+Phase documents, and list groups of phases, e.g.
+
+  apiVersion: airshipit.org/v1alpha1
+  kind: PhasePlan
+  metadata:
+    name: deploy
+  phaseGroups:
+    - name: ephemeral
+      phases:
+        - name: initinfra
+        - name: controlplane
+    - name: target
+      phases:
+        - name: target-initinfra
+
+The groups are run in order, and so are the phases of each group, except
+that phases are run after the phases of their group they depend on. Phases
+can't depend on phases of later groups. The plan stops at the first phase
+failing and reports it, the phases after it are not run.
+
+With --max-parallel above 1, the phases of a group which don't depend on each
+other, directly or not, are run at the same time, up to that many at once. A
+phase starts as soon as the phases of its group it depends on completed, and
+every line of its output is prefixed by its name. Once a phase failed, the
+phases running are waited for and no other phase is started.
+
+Every phase completing is recorded in a checkpoint of the plan, in the
+checkpoints directory next to the airship config. With --resume, the phases
+the previous run of the plan in the current context completed are skipped, so
+that a failed run can be resumed without running them again. The checkpoint is
+removed once the plan completed.
+
+With --event-log, the events of the phases run are appended to a file as JSON
+objects, one per line.
+
+With --dry-run, every phase of the plan is reported as "airshipctl phase run
+--dry-run" does, without changing the clusters, and the checkpoint of the plan
+is neither written nor removed.
+`
+
+	runExample = `
+# Run the deploy plan
+airshipctl plan run deploy
+
+# Run the phases of the deploy plan the previous run didn't complete
+airshipctl plan run deploy --resume
+
+# Run up to 4 phases of every group of the deploy plan at the same time
+airshipctl plan run deploy --max-parallel 4
+
+# Report what running the deploy plan would do
+airshipctl plan run deploy --dry-run
+`
+)
+
+// NewRunCommand creates a command running the phases of a plan
+func NewRunCommand(rootSettings *environment.AirshipCTLSettings, factory client.Factory) *cobra.Command {
+	var resume bool
+	var eventLog string
+	var maxParallel int
+	var dryRun bool
+	runCmd := &cobra.Command{
+		Use:     "run PLAN_NAME",
+		Short:   "Run the phases of a plan",
+		Long:    runLong[1:],
+		Args:    cobra.ExactArgs(1),
+		Example: runExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := phase.NewEngine(rootSettings, factory)
+			engine.Out = cmd.OutOrStdout()
+			engine.MaxParallel = maxParallel
+			// The selected profile decides unless --dry-run is given
+			engine.DryRun = dryRun
+			if !cmd.Flags().Changed("dry-run") {
+				engine.DryRun = rootSettings.DryRun
+			}
+			if eventLog != "" {
+				f, err := os.OpenFile(eventLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				engine.Processors = []ifc.EventProcessor{events.NewJSONWriter(f)}
+			}
+			return engine.RunPlan(args[0], resume)
+		},
+	}
+	runCmd.Flags().BoolVar(
+		&resume,
+		"resume",
+		false,
+		"skip the phases completed by the previous run of the plan")
+	runCmd.Flags().IntVar(
+		&maxParallel,
+		"max-parallel",
+		1,
+		"maximum number of phases of a group run at the same time")
+	runCmd.Flags().StringVar(
+		&eventLog,
+		"event-log",
+		"",
+		"append the events of the phases run to this file, as one JSON object per line")
+	runCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"report what running the phases would do without changing the clusters")
+	return runCmd
+}
@@ -0,0 +1,75 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package plan
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	treeLong = `
+Print the plans of the current context as an indented tree of their phase
+groups, their phases and the documents of the phases. Under every phase, the
+kustomization of its documentEntryPoint is followed through the resources,
+generators and transformers it includes, down to the document files. Document
+paths are relative to the target path of the manifest of the current context.
+
+Without PLAN_NAME, all plans are printed, followed by the phases which are not
+part of any plan. With -o dot, the tree is printed as a graphviz graph where
+phases and documents shared by several phases appear once, and dashed edges
+point to the phases a phase depends on.
+`
+
+	treeExample = `
+# Print the tree of the deploy plan
+airshipctl plan tree deploy
+
+# Render the tree of all plans as an image
+airshipctl plan tree -o dot | dot -Tsvg > plans.svg
+`
+)
+
+// NewTreeCommand creates a command printing the plans, their phases and the
+// documents of the phases as a tree
+func NewTreeCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var output string
+	treeCmd := &cobra.Command{
+		Use:     "tree [PLAN_NAME]",
+		Short:   "Print plans, their phases and documents as a tree",
+		Long:    treeLong[1:],
+		Example: treeExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != phase.OutputTree && output != phase.OutputDOT {
+				return phase.ErrInvalidOutputFormat{Format: output, Formats: []string{phase.OutputTree, phase.OutputDOT}}
+			}
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			tree, err := phase.NewTree(rootSettings, name)
+			if err != nil {
+				return err
+			}
+			return tree.Print(cmd.OutOrStdout(), output)
+		},
+	}
+	treeCmd.Flags().StringVarP(&output, "output", "o", phase.OutputTree,
+		`output format, "tree" or "dot"`)
+	return treeCmd
+}
@@ -28,6 +28,7 @@ import (
 	"opendev.org/airship/airshipctl/cmd/config"
 	"opendev.org/airship/airshipctl/cmd/document"
 	"opendev.org/airship/airshipctl/cmd/phase"
+	"opendev.org/airship/airshipctl/cmd/plan"
 	"opendev.org/airship/airshipctl/cmd/secret"
 	"opendev.org/airship/airshipctl/pkg/environment"
 )
@@ -62,10 +63,12 @@ func AddDefaultAirshipCTLCommands(cmd *cobra.Command, settings *environment.Airs
 	cmd.AddCommand(baremetal.NewBaremetalCommand(settings))
 	cmd.AddCommand(cluster.NewClusterCommand(settings))
 	cmd.AddCommand(completion.NewCompletionCommand())
+	cmd.AddCommand(completion.NewNamesCommand(settings))
 	cmd.AddCommand(document.NewDocumentCommand(settings))
 	cmd.AddCommand(config.NewConfigCommand(settings))
 	cmd.AddCommand(secret.NewSecretCommand(settings))
 	cmd.AddCommand(phase.NewPhaseCommand(settings))
+	cmd.AddCommand(plan.NewPlanCommand(settings))
 
 	return cmd
 }
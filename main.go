@@ -19,6 +19,8 @@ import (
 	"os"
 
 	"opendev.org/airship/airshipctl/cmd"
+	aerror "opendev.org/airship/airshipctl/pkg/errors"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 )
 
 func main() {
@@ -28,8 +30,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	err = rootCmd.Execute()
+	// Log out of the BMCs before exiting, they limit the number of open sessions
+	redfish.CloseSessions()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(aerror.ExitCode(err))
 	}
 }
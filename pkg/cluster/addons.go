@@ -0,0 +1,166 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// DefaultAddonsPhase is the phase deploying the addons of a workload
+// cluster, e.g. its CNI, metrics server and storage provisioner
+const DefaultAddonsPhase = "addons"
+
+// AddonsBootstrapper deploys the addons of a new workload cluster as soon as
+// its API server answers, so that its nodes don't stay NotReady until the
+// CNI is deployed by hand
+type AddonsBootstrapper struct {
+	Waiter *ControlPlaneWaiter
+	// Apply deploys the addons to the cluster of the kubeconfig file at
+	// kubeconfigPath
+	Apply func(kubeconfigPath string) error
+	// NodesTimeout is how long to wait for the nodes of the cluster to be
+	// Ready once the addons are applied, they aren't waited for if it is 0
+	NodesTimeout time.Duration
+	PollInterval time.Duration
+	// Out receives the progress of the bootstrap
+	Out io.Writer
+	// ClientSet returns a clientset of the cluster of kubeconfig
+	ClientSet func(kubeconfig []byte) (kubernetes.Interface, error)
+}
+
+// NewAddonsBootstrapper returns an AddonsBootstrapper waiting up to timeout
+// for the control plane, reporting to stdout
+func NewAddonsBootstrapper(c client.Interface, timeout time.Duration,
+	apply func(kubeconfigPath string) error) *AddonsBootstrapper {
+	return &AddonsBootstrapper{
+		Waiter:       NewControlPlaneWaiter(c, timeout),
+		Apply:        apply,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+		ClientSet:    kubeconfigClientSet,
+	}
+}
+
+// Bootstrap waits for the control plane of the Cluster name in namespace,
+// applies the addons with the kubeconfig Cluster API generated for it and
+// then waits for its nodes to be Ready
+func (b *AddonsBootstrapper) Bootstrap(namespace, name string) error {
+	b.Waiter.Out = b.Out
+	if err := b.Waiter.WaitForControlPlane(namespace, name); err != nil {
+		return err
+	}
+
+	kubeconfig, err := GetKubeconfig(b.Waiter.Client, namespace, name)
+	if err != nil {
+		return err
+	}
+	if err = b.apply(kubeconfig); err != nil {
+		return err
+	}
+
+	if b.NodesTimeout == 0 {
+		return nil
+	}
+	return b.waitForNodes(WatchTarget{GVK: ClusterGVK, Namespace: namespace, Name: name}, kubeconfig)
+}
+
+// apply writes kubeconfig to a temporary file, readable by the current user
+// only, for the time the addons are applied
+func (b *AddonsBootstrapper) apply(kubeconfig []byte) error {
+	file, err := ioutil.TempFile("", "airshipctl-kubeconfig-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(kubeconfig)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return b.Apply(file.Name())
+}
+
+// waitForNodes blocks until the cluster has nodes and all of them are Ready
+func (b *AddonsBootstrapper) waitForNodes(target WatchTarget, kubeconfig []byte) error {
+	clientSet, err := b.ClientSet(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var last string
+	for {
+		nodes, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil && !client.IsTransient(err) {
+			return err
+		}
+		var ready, total int
+		if err == nil {
+			ready, total = readyNodes(nodes.Items)
+		}
+
+		elapsed := time.Since(start)
+		state := fmt.Sprintf("%d/%d nodes ready", ready, total)
+		if state != last {
+			fmt.Fprintf(b.Out, "[%s] %s: %s\n", elapsed.Round(time.Second), target, state)
+			last = state
+		}
+
+		switch {
+		case total > 0 && ready == total:
+			return nil
+		case elapsed >= b.NodesTimeout:
+			return ErrNodesNotReady{Cluster: target.String(), Ready: ready, Total: total}
+		}
+		time.Sleep(b.PollInterval)
+	}
+}
+
+// readyNodes counts the nodes whose Ready condition is true
+func readyNodes(nodes []corev1.Node) (ready, total int) {
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready++
+				break
+			}
+		}
+	}
+	return ready, len(nodes)
+}
+
+// kubeconfigClientSet returns a clientset of the cluster of kubeconfig
+func kubeconfigClientSet(kubeconfig []byte) (kubernetes.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
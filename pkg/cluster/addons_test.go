@@ -0,0 +1,143 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func addonsNode(name string, ready corev1.ConditionStatus) runtime.Object {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestBootstrapAddons(t *testing.T) {
+	secret := kubeconfigSecret("target-cluster-kubeconfig", map[string][]byte{"value": []byte("apiVersion: v1\n")})
+	applyErr := errors.New("apply failed")
+
+	tests := []struct {
+		name          string
+		initialized   bool
+		nodes         []runtime.Object
+		nodesTimeout  time.Duration
+		applyErr      error
+		expectApplied bool
+		expectedOut   string
+		expectedErr   error
+	}{
+		{
+			name:          "nodes-ready",
+			initialized:   true,
+			nodes:         []runtime.Object{addonsNode("node01", corev1.ConditionTrue)},
+			nodesTimeout:  time.Minute,
+			expectApplied: true,
+			expectedOut: "[0s] " + targetClusterName + ": control plane is ready\n" +
+				"[0s] " + targetClusterName + ": 1/1 nodes ready\n",
+		},
+		{
+			name:        "nodes-not-ready",
+			initialized: true,
+			nodes: []runtime.Object{
+				addonsNode("node01", corev1.ConditionTrue),
+				addonsNode("node02", corev1.ConditionFalse),
+			},
+			expectApplied: true,
+			nodesTimeout:  time.Nanosecond,
+			expectedOut: "[0s] " + targetClusterName + ": control plane is ready\n" +
+				"[0s] " + targetClusterName + ": 1/2 nodes ready\n",
+			expectedErr: cluster.ErrNodesNotReady{Cluster: targetClusterName, Ready: 1, Total: 2},
+		},
+		{
+			name:          "nodes-not-waited",
+			initialized:   true,
+			expectApplied: true,
+			expectedOut:   "[0s] " + targetClusterName + ": control plane is ready\n",
+		},
+		{
+			name:          "apply-failed",
+			initialized:   true,
+			nodesTimeout:  time.Minute,
+			applyErr:      applyErr,
+			expectApplied: true,
+			expectedOut:   "[0s] " + targetClusterName + ": control plane is ready\n",
+			expectedErr:   applyErr,
+		},
+		{
+			name:        "control-plane-not-ready",
+			expectedOut: "[0s] " + targetClusterName + ": waiting for the control plane to be initialized\n",
+			expectedErr: cluster.ErrControlPlaneTimeout{
+				Cluster: targetClusterName,
+				State:   "waiting for the control plane to be initialized",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			var applied bool
+			bootstrapper := &cluster.AddonsBootstrapper{
+				Waiter: &cluster.ControlPlaneWaiter{
+					Client: fake.NewClient(
+						fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.KubeadmControlPlaneGVK)),
+						fake.WithDynamicObjects(waitedCluster(tt.initialized)),
+						fake.WithTypedObjects(secret)),
+					PollInterval: time.Millisecond,
+					Reachable:    func([]byte) error { return nil },
+				},
+				Apply: func(kubeconfigPath string) error {
+					applied = true
+					info, err := os.Stat(kubeconfigPath)
+					require.NoError(t, err)
+					assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+					kubeconfig, err := ioutil.ReadFile(kubeconfigPath)
+					require.NoError(t, err)
+					assert.Equal(t, "apiVersion: v1\n", string(kubeconfig))
+					return tt.applyErr
+				},
+				NodesTimeout: tt.nodesTimeout,
+				PollInterval: time.Millisecond,
+				Out:          out,
+				ClientSet: func(kubeconfig []byte) (kubernetes.Interface, error) {
+					return kubefake.NewSimpleClientset(tt.nodes...), nil
+				},
+			}
+			assert.Equal(t, tt.expectedErr, bootstrapper.Bootstrap("target-infra", "target-cluster"))
+			assert.Equal(t, tt.expectApplied, applied)
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,424 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	// clusterctlLabel is set by clusterctl on the CRDs of the providers it installs
+	clusterctlLabel = "clusterctl.cluster.x-k8s.io"
+	// bmhPausedAnnotation stops the baremetal operator from acting on a BareMetalHost
+	bmhPausedAnnotation = "baremetalhost.metal3.io/paused"
+	// metal3Group is the API group of BareMetalHosts, whose CRD isn't installed by clusterctl
+	metal3Group = "metal3.io"
+)
+
+// backupKinds are the core kinds backed up along with the custom resources
+// of the providers, the credentials and certificates of the clusters are
+// stored in them
+var backupKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "ConfigMap"},
+}
+
+// backupServerFields are set by the API server and can't be restored. The
+// uid is kept to link objects to their owners when they are restored.
+var backupServerFields = [][]string{
+	{"metadata", "creationTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "selfLink"},
+}
+
+// Backup is a copy of the Cluster API and metal3 objects of a namespace,
+// along with its Secrets and ConfigMaps, from which the clusters they
+// describe can be recovered
+type Backup struct {
+	Namespace string
+	Objects   []*unstructured.Unstructured
+}
+
+// TakeBackup copies the objects of namespace of the kinds of the CRDs
+// installed by clusterctl and of the metal3.io group, in their storage
+// versions, and the Secrets and ConfigMaps of namespace. Objects keep their
+// status and owner references.
+func TakeBackup(c client.Interface, namespace string) (*Backup, error) {
+	crds, err := c.ApiextensionsClientSet().ApiextensionsV1().CustomResourceDefinitions().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	gvks := backupGVKs(crds.Items)
+
+	b := &Backup{Namespace: namespace}
+	for _, gvk := range gvks {
+		list, err := c.List(gvk, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetKind() == "Secret" && isServiceAccountToken(obj) {
+				continue
+			}
+			for _, field := range backupServerFields {
+				unstructured.RemoveNestedField(obj.Object, field...)
+			}
+			obj.SetGroupVersionKind(gvk)
+			b.Objects = append(b.Objects, obj)
+		}
+	}
+	return b, nil
+}
+
+// backupGVKs returns the kinds backed up, those of namespaced CRDs in their
+// storage version followed by backupKinds
+func backupGVKs(crds []apiextensionsv1.CustomResourceDefinition) []schema.GroupVersionKind {
+	var gvks []schema.GroupVersionKind
+	for _, crd := range crds {
+		if _, managed := crd.Labels[clusterctlLabel]; !managed && crd.Spec.Group != metal3Group {
+			continue
+		}
+		if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Storage {
+				gvks = append(gvks, schema.GroupVersionKind{
+					Group:   crd.Spec.Group,
+					Version: version.Name,
+					Kind:    crd.Spec.Names.Kind,
+				})
+			}
+		}
+	}
+	sort.Slice(gvks, func(i, j int) bool { return gvks[i].String() < gvks[j].String() })
+	return append(gvks, backupKinds...)
+}
+
+// isServiceAccountToken reports whether secret is a token of a service
+// account, which is created again by the cluster it is restored to
+func isServiceAccountToken(secret *unstructured.Unstructured) bool {
+	secretType, _, _ := unstructured.NestedString(secret.Object, "type")
+	return secretType == "kubernetes.io/service-account-token"
+}
+
+// Save writes the backup to path as a gzipped tar archive holding a YAML
+// file per object. Backups contain secrets, so the archive is only readable
+// by its owner.
+func (b *Backup) Save(archivePath string) error {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, obj := range b.Objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name: path.Join(b.Namespace, obj.GetKind(), obj.GetName()+".yaml"),
+			Mode: 0600,
+			Size: int64(len(data)),
+		}
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err = tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// LoadBackup reads a backup saved with Save
+func LoadBackup(archivePath string) (*Backup, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, ErrInvalidBackup{Path: archivePath, Err: err}
+	}
+	b := &Backup{}
+	tr := tar.NewReader(gz)
+	for {
+		_, err = tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidBackup{Path: archivePath, Err: err}
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal(data, &obj.Object); err != nil {
+			return nil, ErrInvalidBackup{Path: archivePath, Err: err}
+		}
+		b.Namespace = obj.GetNamespace()
+		b.Objects = append(b.Objects, obj)
+	}
+	return b, nil
+}
+
+// Restore creates the objects of the backup in the cluster of c, owners
+// before the objects they own, whose owner references are updated with the
+// uids of the restored owners. Clusters and BareMetalHosts are created
+// paused, so that no controller acts on a cluster before all of its objects
+// are back, and are resumed at the end. Objects which already exist are
+// left as they are. Every object is tried, except the ones owned by objects
+// that couldn't be restored, and the failures are reported with
+// ErrRestoreFailed.
+func (b *Backup) Restore(c dynamic.Interface, mapper meta.RESTMapper, out io.Writer) error {
+	uids := make(map[types.UID]types.UID, len(b.Objects))
+	var paused []*unstructured.Unstructured
+	restoreErr := ErrRestoreFailed{}
+	for _, obj := range restoreOrder(b.Objects) {
+		target := targetOf(obj)
+		if !ownersRestored(obj, b.Objects, uids) {
+			fmt.Fprintf(out, "%s: skipped, an owner couldn't be restored\n", target)
+			restoreErr.Objects = append(restoreErr.Objects, target.String())
+			continue
+		}
+		restored, created, err := restoreBackupObject(c, mapper, obj, uids)
+		if err != nil {
+			fmt.Fprintf(out, "%s: failed to restore: %v\n", target, err)
+			restoreErr.Objects = append(restoreErr.Objects, target.String())
+			continue
+		}
+		uids[obj.GetUID()] = restored.GetUID()
+		if !created {
+			fmt.Fprintf(out, "%s: already exists, left as it is\n", target)
+			continue
+		}
+		if !isPaused(obj) && isPaused(restored) {
+			paused = append(paused, restored)
+		}
+		fmt.Fprintf(out, "%s: restored\n", target)
+	}
+
+	for _, obj := range paused {
+		target := targetOf(obj)
+		if err := resumeRestored(c, mapper, obj); err != nil {
+			fmt.Fprintf(out, "%s: failed to resume: %v\n", target, err)
+			restoreErr.Objects = append(restoreErr.Objects, target.String())
+			continue
+		}
+		fmt.Fprintf(out, "%s: resumed\n", target)
+	}
+
+	if len(restoreErr.Objects) > 0 {
+		return restoreErr
+	}
+	return nil
+}
+
+// restoreOrder sorts objects so that the owners found among them come
+// before the objects they own
+func restoreOrder(objects []*unstructured.Unstructured) []*unstructured.Unstructured {
+	backedUp := make(map[types.UID]bool, len(objects))
+	for _, obj := range objects {
+		backedUp[obj.GetUID()] = true
+	}
+
+	ordered := make([]*unstructured.Unstructured, 0, len(objects))
+	placed := make(map[types.UID]bool, len(objects))
+	remaining := objects
+	for len(remaining) > 0 {
+		var next []*unstructured.Unstructured
+		for _, obj := range remaining {
+			ready := true
+			for _, ref := range obj.GetOwnerReferences() {
+				if backedUp[ref.UID] && !placed[ref.UID] {
+					ready = false
+				}
+			}
+			if ready {
+				ordered = append(ordered, obj)
+				placed[obj.GetUID()] = true
+			} else {
+				next = append(next, obj)
+			}
+		}
+		if len(next) == len(remaining) {
+			// Owner references loop, which the API server doesn't prevent
+			return append(ordered, next...)
+		}
+		remaining = next
+	}
+	return ordered
+}
+
+// ownersRestored reports whether the owners of obj which are part of the
+// backup were restored. An owner reference left with the uid of the backup
+// would make the garbage collector delete obj.
+func ownersRestored(obj *unstructured.Unstructured, objects []*unstructured.Unstructured,
+	uids map[types.UID]types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, restored := uids[ref.UID]; restored {
+			continue
+		}
+		for _, owner := range objects {
+			if owner.GetUID() == ref.UID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// restoreBackupObject creates obj with the owner references and pause
+// applied, and its status. The live object is returned if it exists.
+func restoreBackupObject(c dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured,
+	uids map[types.UID]types.UID) (*unstructured.Unstructured, bool, error) {
+	resource, err := dynamicResource(c, mapper, obj.GroupVersionKind(), obj.GetNamespace())
+	if err != nil {
+		return nil, false, err
+	}
+
+	restored := obj.DeepCopy()
+	restored.SetUID("")
+	refs := restored.GetOwnerReferences()
+	for i := range refs {
+		if uid, ok := uids[refs[i].UID]; ok {
+			refs[i].UID = uid
+		}
+	}
+	restored.SetOwnerReferences(refs)
+	setRestorePaused(restored, true)
+
+	created, err := resource.Create(restored, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		live, err := resource.Get(obj.GetName(), metav1.GetOptions{})
+		return live, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	status, hasStatus, _ := unstructured.NestedFieldCopy(obj.Object, "status")
+	if !hasStatus {
+		return created, true, nil
+	}
+	// The status of kinds with a status subresource is ignored on creation
+	if err = unstructured.SetNestedField(created.Object, status, "status"); err != nil {
+		return nil, false, err
+	}
+	updated, err := resource.UpdateStatus(created, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		return created, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return updated, true, nil
+}
+
+// resumeRestored lifts the pause of a restored Cluster or BareMetalHost
+func resumeRestored(c dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) error {
+	resource, err := dynamicResource(c, mapper, obj.GroupVersionKind(), obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+	live, err := resource.Get(obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	setRestorePaused(live, false)
+	_, err = resource.Update(live, metav1.UpdateOptions{})
+	return err
+}
+
+// setRestorePaused pauses, or resumes, the reconciliation of Clusters and
+// BareMetalHosts, other kinds are left unchanged
+func setRestorePaused(obj *unstructured.Unstructured, paused bool) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case ClusterGVK.GroupKind():
+		if paused {
+			//nolint:errcheck
+			unstructured.SetNestedField(obj.Object, true, "spec", "paused")
+		} else {
+			unstructured.RemoveNestedField(obj.Object, "spec", "paused")
+		}
+	case BareMetalHostGVK.GroupKind():
+		annotations := obj.GetAnnotations()
+		if paused {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[bmhPausedAnnotation] = "true"
+		} else {
+			delete(annotations, bmhPausedAnnotation)
+		}
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// isPaused reports whether obj is a Cluster or a BareMetalHost paused the
+// way setRestorePaused pauses them
+func isPaused(obj *unstructured.Unstructured) bool {
+	switch obj.GroupVersionKind().GroupKind() {
+	case ClusterGVK.GroupKind():
+		paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+		return paused
+	case BareMetalHostGVK.GroupKind():
+		_, paused := obj.GetAnnotations()[bmhPausedAnnotation]
+		return paused
+	}
+	return false
+}
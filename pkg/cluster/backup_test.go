@@ -0,0 +1,227 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+var (
+	secretGVK    = schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+)
+
+func backupCRD(name string, gvk schema.GroupVersionKind,
+	labels map[string]string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gvk.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: gvk.Kind},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: gvk.Version, Served: true, Storage: true},
+			},
+		},
+	}
+}
+
+// backedUpObjects are a Cluster owning a Machine, a BareMetalHost, the
+// kubeconfig Secret of the cluster and the token of a service account
+func backedUpObjects() []runtime.Object {
+	c := deletedCluster()
+	c.SetUID("cluster-uid")
+	c.Object["status"] = map[string]interface{}{"infrastructureReady": true}
+	c.SetResourceVersion("42")
+
+	machine := clusterControlPlaneMachine("target-cp-abcde")
+	machine.SetUID("machine-uid")
+	machine.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "cluster.x-k8s.io/v1alpha3",
+		Kind:       "Cluster",
+		Name:       "target-cluster",
+		UID:        "cluster-uid",
+	}})
+
+	host := workerHost("node01", "target-cp-abcde", "provisioned")
+	host.SetUID("host-uid")
+
+	return []runtime.Object{c, machine, host,
+		backupSecret("target-cluster-kubeconfig", "Opaque"),
+		backupSecret("default-token-abcde", "kubernetes.io/service-account-token")}
+}
+
+func backupSecret(name, secretType string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "target-infra"},
+		"type":       secretType,
+		"data":       map[string]interface{}{"value": "YXBpVmVyc2lvbjogdjEK"},
+	}}
+}
+
+func backupClient() *fake.Client {
+	clusterctlLabels := map[string]string{"clusterctl.cluster.x-k8s.io": ""}
+	return fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK, cluster.BareMetalHostGVK,
+			secretGVK, configMapGVK)),
+		fake.WithCRDs(
+			backupCRD("clusters.cluster.x-k8s.io", cluster.ClusterGVK, clusterctlLabels),
+			backupCRD("machines.cluster.x-k8s.io", cluster.MachineGVK, clusterctlLabels),
+			backupCRD("baremetalhosts.metal3.io", cluster.BareMetalHostGVK, nil),
+			backupCRD("foos.example.com", schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"},
+				nil)),
+		fake.WithDynamicObjects(backedUpObjects()...))
+}
+
+// assignUIDs makes the fake client set a uid on the objects it creates, the
+// way the API server does
+func assignUIDs(c *dynamicfake.FakeDynamicClient) {
+	objects := c.ReactionChain[len(c.ReactionChain)-1]
+	c.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		obj := create.GetObject().(*unstructured.Unstructured)
+		obj.SetUID(types.UID("new-" + obj.GetName()))
+		return objects.React(k8stesting.NewCreateAction(create.GetResource(), create.GetNamespace(), obj))
+	})
+}
+
+func backupNames(b *cluster.Backup) []string {
+	var names []string
+	for _, obj := range b.Objects {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	return names
+}
+
+func TestBackupRestore(t *testing.T) {
+	b, err := cluster.TakeBackup(backupClient(), "target-infra")
+	require.NoError(t, err)
+	assert.Equal(t, "target-infra", b.Namespace)
+	assert.Equal(t, []string{
+		"Cluster/target-cluster",
+		"Machine/target-cp-abcde",
+		"BareMetalHost/node01",
+		"Secret/target-cluster-kubeconfig",
+	}, backupNames(b))
+	assert.Equal(t, types.UID("cluster-uid"), b.Objects[0].GetUID())
+	assert.Empty(t, b.Objects[0].GetResourceVersion())
+
+	dir, err := ioutil.TempDir("", "airshipctl-backup-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "backups", "target-infra.tar.gz")
+	require.NoError(t, b.Save(archive))
+	info, err := os.Stat(archive)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := cluster.LoadBackup(archive)
+	require.NoError(t, err)
+	assert.Equal(t, b, loaded)
+
+	// Restore the objects in reverse order, owners still have to come first
+	for i, j := 0, len(loaded.Objects)-1; i < j; i, j = i+1, j-1 {
+		loaded.Objects[i], loaded.Objects[j] = loaded.Objects[j], loaded.Objects[i]
+	}
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	assignUIDs(c)
+	out := &bytes.Buffer{}
+	require.NoError(t, loaded.Restore(c, provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK,
+		cluster.BareMetalHostGVK, secretGVK, configMapGVK), out))
+	assert.Equal(t,
+		"Secret/target-cluster-kubeconfig in namespace target-infra: restored\n"+
+			"BareMetalHost/node01 in namespace target-infra: restored\n"+
+			"Cluster/target-cluster in namespace target-infra: restored\n"+
+			"Machine/target-cp-abcde in namespace target-infra: restored\n"+
+			"BareMetalHost/node01 in namespace target-infra: resumed\n"+
+			"Cluster/target-cluster in namespace target-infra: resumed\n",
+		out.String())
+
+	machine, err := c.Resource(machinesGVR).Namespace("target-infra").Get("target-cp-abcde", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, machine.GetOwnerReferences(), 1)
+	assert.Equal(t, types.UID("new-target-cluster"), machine.GetOwnerReferences()[0].UID)
+
+	restored, err := c.Resource(schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1alpha3",
+		Resource: "clusters"}).Namespace("target-infra").Get("target-cluster", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, paused, err := unstructured.NestedBool(restored.Object, "spec", "paused")
+	require.NoError(t, err)
+	assert.False(t, paused)
+	ready, _, err := unstructured.NestedBool(restored.Object, "status", "infrastructureReady")
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	host, err := c.Resource(hostsGVR).Namespace("target-infra").Get("node01", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, host.GetAnnotations(), "baremetalhost.metal3.io/paused")
+	state, _, err := unstructured.NestedString(host.Object, "status", "provisioning", "state")
+	require.NoError(t, err)
+	assert.Equal(t, "provisioned", state)
+}
+
+func TestRestoreSkipsObjectsOfFailedOwners(t *testing.T) {
+	b, err := cluster.TakeBackup(backupClient(), "target-infra")
+	require.NoError(t, err)
+
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.PrependReactor("create", "clusters", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("admission denied")
+	})
+	out := &bytes.Buffer{}
+	err = b.Restore(c, provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK, cluster.BareMetalHostGVK,
+		secretGVK, configMapGVK), out)
+	assert.Equal(t, cluster.ErrRestoreFailed{Objects: []string{
+		"Cluster/target-cluster in namespace target-infra",
+		"Machine/target-cp-abcde in namespace target-infra",
+	}}, err)
+	assert.Contains(t, out.String(),
+		"Machine/target-cp-abcde in namespace target-infra: skipped, an owner couldn't be restored\n")
+
+	_, err = c.Resource(machinesGVR).Namespace("target-infra").Get("target-cp-abcde", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func TestLoadBackupInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-backup-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "backup.tar.gz")
+	require.NoError(t, ioutil.WriteFile(archive, []byte("not an archive"), 0600))
+
+	_, err = cluster.LoadBackup(archive)
+	assert.IsType(t, cluster.ErrInvalidBackup{}, err)
+}
@@ -0,0 +1,254 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// CertRotator renews the certificates kubeadm generated on the control plane
+// of a workload cluster by rolling out all of its machines, the new ones
+// join the cluster with certificates valid from the time they were created
+type CertRotator struct {
+	Client       dynamic.Interface
+	Mapper       meta.RESTMapper
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives the progress of the rollout and the expiry dates of the
+	// certificates
+	Out io.Writer
+	// Kubeconfig returns the admin kubeconfig of a cluster, read from the
+	// Secret Cluster API generated for it unless set otherwise
+	Kubeconfig func(namespace, clusterName string) ([]byte, error)
+	// CertificateExpiry returns when the serving certificate of the API
+	// server of kubeconfig expires, it connects to the server unless set
+	// otherwise
+	CertificateExpiry func(kubeconfig []byte) (time.Time, error)
+}
+
+// NewCertRotator returns a CertRotator reporting to stdout
+func NewCertRotator(c client.Interface, timeout time.Duration) *CertRotator {
+	return &CertRotator{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+		Kubeconfig: func(namespace, clusterName string) ([]byte, error) {
+			return GetKubeconfig(c, namespace, clusterName)
+		},
+		CertificateExpiry: servingCertificateExpiry,
+	}
+}
+
+// Rotate renews the control plane certificates of the Cluster name in
+// namespace. It sets spec.upgradeAfter of the KubeadmControlPlane of the
+// cluster to the current time, so that all machines created before are
+// replaced, and blocks until the rollout is complete. The expiry of the
+// serving certificate of the API server is read before and after the
+// rollout, an ErrCertificatesNotRotated is returned if it didn't move.
+//
+// Running Rotate again replaces all machines again, including the ones of
+// an earlier rotation that timed out.
+func (r *CertRotator) Rotate(namespace, name string) error {
+	target := WatchTarget{GVK: ClusterGVK, Namespace: namespace, Name: name}
+	clusters, err := dynamicResource(r.Client, r.Mapper, ClusterGVK, namespace)
+	if err != nil {
+		return err
+	}
+	cluster, err := clusters.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	targets := controlPlaneTarget(cluster)
+	if len(targets) == 0 || targets[0].GVK.GroupKind() != KubeadmControlPlaneGVK.GroupKind() {
+		return ErrNoKubeadmControlPlane{Cluster: target.String()}
+	}
+	controlPlane := targets[0]
+
+	before, err := r.apiServerExpiry(namespace, name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(r.Out, "%s: API server certificate expires %s\n", target, before.Format(time.RFC3339))
+
+	start := metav1.NewTime(time.Now().Truncate(time.Second))
+	if err = r.requestRollout(controlPlane, start); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.Out, "%s: replacing the machines created before %s\n", controlPlane,
+		start.UTC().Format(time.RFC3339))
+	if err = r.waitForRollout(controlPlane, start); err != nil {
+		return err
+	}
+
+	after, err := r.apiServerExpiry(namespace, name)
+	if err != nil {
+		return err
+	}
+	if !after.After(before) {
+		return ErrCertificatesNotRotated{Cluster: target.String(), Expiry: after}
+	}
+	fmt.Fprintf(r.Out, "%s: certificates rotated, API server certificate expires %s (was %s)\n", target,
+		after.Format(time.RFC3339), before.Format(time.RFC3339))
+	return nil
+}
+
+// requestRollout sets spec.upgradeAfter of the KubeadmControlPlane of
+// controlPlane to start
+func (r *CertRotator) requestRollout(controlPlane WatchTarget, start metav1.Time) error {
+	resource, err := dynamicResource(r.Client, r.Mapper, controlPlane.GVK, controlPlane.Namespace)
+	if err != nil {
+		return err
+	}
+	live, err := resource.Get(controlPlane.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, isPaused := live.GetAnnotations()[PausedAnnotation]; isPaused {
+		return ErrCertRotationFailed{Resource: controlPlane.String(), Reason: "the control plane is paused"}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"upgradeAfter": start}})
+	if err != nil {
+		return err
+	}
+	_, err = resource.Patch(controlPlane.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// waitForRollout blocks until all machines of controlPlane were created
+// after start and the KubeadmControlPlane reports all replicas updated and
+// ready
+func (r *CertRotator) waitForRollout(controlPlane WatchTarget, start metav1.Time) error {
+	resource, err := dynamicResource(r.Client, r.Mapper, controlPlane.GVK, controlPlane.Namespace)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(r.Timeout)
+	var last string
+	for {
+		live, err := resource.Get(controlPlane.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if message, _, _ := unstructured.NestedString(live.Object, "status", "failureMessage"); message != "" {
+			reason, _, _ := unstructured.NestedString(live.Object, "status", "failureReason")
+			return ErrCertRotationFailed{Resource: controlPlane.String(),
+				Reason: fmt.Sprintf("%s (%s)", message, reason)}
+		}
+
+		desired, _, _ := unstructured.NestedInt64(live.Object, "spec", "replicas")
+		replicas, _, _ := unstructured.NestedInt64(live.Object, "status", "replicas")
+		updated, _, _ := unstructured.NestedInt64(live.Object, "status", "updatedReplicas")
+		ready, _, _ := unstructured.NestedInt64(live.Object, "status", "readyReplicas")
+		replaced, err := r.replacedMachines(live, start)
+		if err != nil {
+			return err
+		}
+
+		progress := fmt.Sprintf("%d/%d machines replaced, %d updated, %d ready", replaced, desired, updated, ready)
+		if progress != last {
+			fmt.Fprintf(r.Out, "%s: %s\n", controlPlane, progress)
+			last = progress
+		}
+
+		switch {
+		case int64(replaced) == desired && replicas == desired && updated == desired && ready == desired:
+			return nil
+		case time.Now().After(deadline):
+			return ErrCertRotationTimeout{Resource: controlPlane.String(), Progress: progress}
+		}
+		time.Sleep(r.PollInterval)
+	}
+}
+
+// replacedMachines returns the number of Machines owned by kcp created at or
+// after start, the ones whose certificates were generated by the rotation
+func (r *CertRotator) replacedMachines(kcp *unstructured.Unstructured, start metav1.Time) (int, error) {
+	resource, err := dynamicResource(r.Client, r.Mapper, MachineGVK, kcp.GetNamespace())
+	if err != nil {
+		return 0, err
+	}
+	machines, err := resource.List(metav1.ListOptions{LabelSelector: controlPlaneLabel})
+	if err != nil {
+		return 0, err
+	}
+
+	replaced := 0
+	for _, machine := range machines.Items {
+		created := machine.GetCreationTimestamp()
+		if ownedBy(machine, kcp) && machine.GetDeletionTimestamp() == nil && !created.Before(&start) {
+			replaced++
+		}
+	}
+	return replaced, nil
+}
+
+// apiServerExpiry returns when the serving certificate of the API server of
+// the cluster called name expires
+func (r *CertRotator) apiServerExpiry(namespace, name string) (time.Time, error) {
+	kubeconfig, err := r.Kubeconfig(namespace, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.CertificateExpiry(kubeconfig)
+}
+
+// servingCertificateExpiry connects to the API server of kubeconfig and
+// returns when the certificate it serves expires
+func servingCertificateExpiry(kubeconfig []byte) (time.Time, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return time.Time{}, err
+	}
+	server, err := url.Parse(config.Host)
+	if err != nil {
+		return time.Time{}, err
+	}
+	address := server.Host
+	if server.Port() == "" {
+		address = net.JoinHostPort(server.Hostname(), "443")
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: reachableTimeout}, "tcp", address, tlsConfig)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].NotAfter, nil
+}
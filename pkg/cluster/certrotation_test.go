@@ -0,0 +1,189 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+var (
+	oldExpiry = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	newExpiry = time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func rotatedCluster() *unstructured.Unstructured {
+	return provisioningObject(cluster.ClusterGVK, "target-cluster",
+		map[string]interface{}{
+			"controlPlaneRef": map[string]interface{}{
+				"apiVersion": "controlplane.cluster.x-k8s.io/v1alpha3",
+				"kind":       "KubeadmControlPlane",
+				"name":       "cluster-controlplane",
+			},
+		}, map[string]interface{}{}, nil)
+}
+
+// rotatedMachine returns a control plane machine created at created, the
+// machines created after the rotation started are the ones replaced
+func rotatedMachine(name string, created time.Time) *unstructured.Unstructured {
+	machine := controlPlaneMachine(name, "v1.17.1")
+	machine.SetCreationTimestamp(metav1.NewTime(created))
+	return machine
+}
+
+func newTestCertRotator(expiries []time.Time, objects ...runtime.Object) (*cluster.CertRotator, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.KubeadmControlPlaneGVK, cluster.MachineGVK)),
+		fake.WithDynamicObjects(objects...))
+	return &cluster.CertRotator{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Out:          out,
+		Kubeconfig: func(namespace, clusterName string) ([]byte, error) {
+			return []byte("apiVersion: v1\n"), nil
+		},
+		CertificateExpiry: func(kubeconfig []byte) (time.Time, error) {
+			expiry := expiries[0]
+			if len(expiries) > 1 {
+				expiries = expiries[1:]
+			}
+			return expiry, nil
+		},
+	}, out
+}
+
+func TestRotate(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	rotator, out := newTestCertRotator([]time.Time{oldExpiry, newExpiry},
+		rotatedCluster(),
+		liveControlPlane(false, rolledOut()),
+		rotatedMachine("cp-4", later),
+		rotatedMachine("cp-5", later),
+		rotatedMachine("cp-6", later),
+	)
+
+	require.NoError(t, rotator.Rotate("target-infra", "target-cluster"))
+	assert.Contains(t, out.String(), ""+
+		"Cluster/target-cluster in namespace target-infra: API server certificate expires 2021-06-01T12:00:00Z\n"+
+		controlPlaneTarget+": replacing the machines created before ")
+	assert.Contains(t, out.String(), "\n"+
+		controlPlaneTarget+": 3/3 machines replaced, 3 updated, 3 ready\n"+
+		"Cluster/target-cluster in namespace target-infra: certificates rotated, "+
+		"API server certificate expires 2022-09-01T12:00:00Z (was 2021-06-01T12:00:00Z)\n")
+
+	kcp, err := rotator.Client.Resource(schema.GroupVersionResource{
+		Group:    "controlplane.cluster.x-k8s.io",
+		Version:  "v1alpha3",
+		Resource: "kubeadmcontrolplanes",
+	}).Namespace("target-infra").Get("cluster-controlplane", metav1.GetOptions{})
+	require.NoError(t, err)
+	upgradeAfter, _, _ := unstructured.NestedString(kcp.Object, "spec", "upgradeAfter")
+	assert.NotEmpty(t, upgradeAfter)
+}
+
+func TestRotateErrors(t *testing.T) {
+	earlier := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	noControlPlane := provisioningObject(cluster.ClusterGVK, "target-cluster",
+		map[string]interface{}{}, map[string]interface{}{}, nil)
+	failed := rolledOut()
+	failed["failureReason"] = "UpgradeFailed"
+	failed["failureMessage"] = "machine cp-4 could not be created"
+
+	tests := []struct {
+		name        string
+		expiries    []time.Time
+		objects     []runtime.Object
+		expectedErr error
+	}{
+		{
+			name:     "no-kubeadm-control-plane",
+			expiries: []time.Time{oldExpiry},
+			objects:  []runtime.Object{noControlPlane},
+			expectedErr: cluster.ErrNoKubeadmControlPlane{
+				Cluster: "Cluster/target-cluster in namespace target-infra",
+			},
+		},
+		{
+			name:     "paused",
+			expiries: []time.Time{oldExpiry},
+			objects:  []runtime.Object{rotatedCluster(), liveControlPlane(true, rolledOut())},
+			expectedErr: cluster.ErrCertRotationFailed{
+				Resource: controlPlaneTarget,
+				Reason:   "the control plane is paused",
+			},
+		},
+		{
+			name:     "failed",
+			expiries: []time.Time{oldExpiry},
+			objects:  []runtime.Object{rotatedCluster(), liveControlPlane(false, failed)},
+			expectedErr: cluster.ErrCertRotationFailed{
+				Resource: controlPlaneTarget,
+				Reason:   "machine cp-4 could not be created (UpgradeFailed)",
+			},
+		},
+		{
+			name:     "timeout",
+			expiries: []time.Time{oldExpiry},
+			objects: []runtime.Object{
+				rotatedCluster(),
+				liveControlPlane(false, rolledOut()),
+				rotatedMachine("cp-1", earlier),
+				rotatedMachine("cp-4", later),
+			},
+			expectedErr: cluster.ErrCertRotationTimeout{
+				Resource: controlPlaneTarget,
+				Progress: "1/3 machines replaced, 3 updated, 3 ready",
+			},
+		},
+		{
+			name:     "not-rotated",
+			expiries: []time.Time{oldExpiry},
+			objects: []runtime.Object{
+				rotatedCluster(),
+				liveControlPlane(false, rolledOut()),
+				rotatedMachine("cp-4", later),
+				rotatedMachine("cp-5", later),
+				rotatedMachine("cp-6", later),
+			},
+			expectedErr: cluster.ErrCertificatesNotRotated{
+				Cluster: "Cluster/target-cluster in namespace target-infra",
+				Expiry:  oldExpiry,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rotator, _ := newTestCertRotator(tt.expiries, tt.objects...)
+			assert.Equal(t, tt.expectedErr, rotator.Rotate("target-infra", "target-cluster"))
+		})
+	}
+}
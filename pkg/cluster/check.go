@@ -0,0 +1,460 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Names of the built-in checks
+const (
+	CheckNodesReady     = "nodes-ready"
+	CheckCorePods       = "core-pods"
+	CheckCAPIConditions = "capi-conditions"
+	CheckDNS            = "dns"
+)
+
+// coreNamespace holds the pods checked by the core-pods check
+const coreNamespace = "kube-system"
+
+// ClusterCheck checks one aspect of the health of a cluster
+type ClusterCheck interface {
+	// Name identifies the check in reports
+	Name() string
+	// Run returns a result per object checked. An error means the check
+	// couldn't run at all.
+	Run(c client.Interface) ([]CheckResult, error)
+}
+
+// CheckResult is the outcome of a check for one object
+type CheckResult struct {
+	Check  string `json:"check"`
+	Object string `json:"object"`
+	Passed bool   `json:"passed"`
+	// Message explains why the check failed
+	Message string `json:"message,omitempty"`
+}
+
+// CheckReport is the outcome of the checks run against a cluster
+type CheckReport struct {
+	Passed  bool          `json:"passed"`
+	Results []CheckResult `json:"results"`
+}
+
+// RunChecks runs checks in order against the cluster of c. A check which
+// can't run is reported as failed, the others run regardless.
+func RunChecks(c client.Interface, checks []ClusterCheck) *CheckReport {
+	report := &CheckReport{Passed: true}
+	for _, check := range checks {
+		results, err := check.Run(c)
+		if err != nil {
+			results = []CheckResult{{Check: check.Name(), Object: "-", Message: err.Error()}}
+		}
+		for _, result := range results {
+			report.Passed = report.Passed && result.Passed
+		}
+		report.Results = append(report.Results, results...)
+	}
+	return report
+}
+
+// Err returns an ErrClusterChecksFailed if any check failed
+func (r *CheckReport) Err() error {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return ErrClusterChecksFailed{Failed: failed}
+}
+
+// Print writes the report to w as a table or as JSON
+func (r *CheckReport) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return r.printTable(w)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(r)
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (r *CheckReport) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "CHECK\tOBJECT\tRESULT\tMESSAGE")
+	for _, result := range r.Results {
+		outcome := "FAIL"
+		if result.Passed {
+			outcome = "PASS"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Check, result.Object, outcome, valueOrDash(result.Message))
+	}
+	return tw.Flush()
+}
+
+// BuiltinChecks returns the checks run against every cluster: nodes Ready,
+// pods of kube-system healthy, Cluster API objects ready and in-cluster DNS
+// resolving
+func BuiltinChecks(dnsImage string, timeout time.Duration) []ClusterCheck {
+	return []ClusterCheck{
+		nodesReadyCheck{},
+		corePodsCheck{},
+		capiConditionsCheck{},
+		&DNSCheck{
+			Image:        dnsImage,
+			Host:         "kubernetes.default.svc.cluster.local",
+			Timeout:      timeout,
+			PollInterval: status.DefaultPollInterval,
+		},
+	}
+}
+
+// nodesReadyCheck passes for nodes whose Ready condition is True
+type nodesReadyCheck struct{}
+
+func (nodesReadyCheck) Name() string { return CheckNodesReady }
+
+func (nodesReadyCheck) Run(c client.Interface) ([]CheckResult, error) {
+	nodes, err := c.ClientSet().CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) == 0 {
+		return []CheckResult{{Check: CheckNodesReady, Object: "-", Message: "no node found"}}, nil
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+
+	results := make([]CheckResult, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		result := CheckResult{Check: CheckNodesReady, Object: "Node/" + node.Name,
+			Message: "the node reports no Ready condition"}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != corev1.NodeReady {
+				continue
+			}
+			result.Passed = condition.Status == corev1.ConditionTrue
+			result.Message = ""
+			if !result.Passed {
+				result.Message = fmt.Sprintf("Ready is %s: %s", condition.Status, condition.Message)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// corePodsCheck passes for the pods of kube-system which completed, or run
+// with all their containers ready
+type corePodsCheck struct{}
+
+func (corePodsCheck) Name() string { return CheckCorePods }
+
+func (corePodsCheck) Run(c client.Interface) ([]CheckResult, error) {
+	pods, err := c.ClientSet().CoreV1().Pods(coreNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	results := make([]CheckResult, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		result := CheckResult{Check: CheckCorePods, Object: fmt.Sprintf("Pod/%s/%s", coreNamespace, pod.Name)}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			result.Passed = true
+		case corev1.PodRunning:
+			var notReady []string
+			for _, container := range pod.Status.ContainerStatuses {
+				if !container.Ready {
+					notReady = append(notReady, container.Name)
+				}
+			}
+			result.Passed = len(notReady) == 0
+			if !result.Passed {
+				result.Message = "containers not ready: " + strings.Join(notReady, ", ")
+			}
+		default:
+			result.Message = fmt.Sprintf("pod is %s", pod.Status.Phase)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// capiConditionsCheck passes for the Clusters and Machines of all
+// namespaces whose conditions are all True. Objects of Cluster API versions
+// without conditions are checked by their status fields instead.
+type capiConditionsCheck struct{}
+
+func (capiConditionsCheck) Name() string { return CheckCAPIConditions }
+
+func (capiConditionsCheck) Run(c client.Interface) ([]CheckResult, error) {
+	var results []CheckResult
+	for _, gvk := range []schema.GroupVersionKind{ClusterGVK, MachineGVK} {
+		objects, err := listResources(c, gvk, metav1.NamespaceAll, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objects {
+			result := CheckResult{
+				Check:  CheckCAPIConditions,
+				Object: fmt.Sprintf("%s/%s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName()),
+			}
+			result.Message = capiProblem(obj)
+			result.Passed = result.Message == ""
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// capiProblem describes why a Cluster API object isn't healthy, it is empty
+// for healthy objects
+func capiProblem(obj unstructured.Unstructured) string {
+	conditions, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if found {
+		var problems []string
+		for _, item := range conditions {
+			condition, ok := item.(map[string]interface{})
+			if !ok || condition["status"] == string(corev1.ConditionTrue) {
+				continue
+			}
+			problem := fmt.Sprintf("%v is %v", condition["type"], condition["status"])
+			if reason, _ := condition["reason"].(string); reason != "" {
+				problem += " (" + reason + ")"
+			}
+			problems = append(problems, problem)
+		}
+		return strings.Join(problems, ", ")
+	}
+
+	switch obj.GetKind() {
+	case ClusterGVK.Kind:
+		infrastructureReady, _, _ := unstructured.NestedBool(obj.Object, "status", "infrastructureReady")
+		initialized, _, _ := unstructured.NestedBool(obj.Object, "status", "controlPlaneInitialized")
+		var problems []string
+		if !infrastructureReady {
+			problems = append(problems, "infrastructure not ready")
+		}
+		if !initialized {
+			problems = append(problems, "control plane not initialized")
+		}
+		return strings.Join(problems, ", ")
+	default:
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase != machinePhaseRunning {
+			return fmt.Sprintf("phase is %s", valueOrDash(phase))
+		}
+		return ""
+	}
+}
+
+// DNSCheck resolves Host from a pod of the cluster running Image, which
+// must provide nslookup
+type DNSCheck struct {
+	Image        string
+	Host         string
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// dnsCheckPod is the name of the pod resolving the host of a DNSCheck
+const dnsCheckPod = "airshipctl-dns-check"
+
+// Name identifies the check in reports
+func (d *DNSCheck) Name() string { return CheckDNS }
+
+// Run starts a pod resolving the host in kube-system, waits for it to
+// complete and deletes it
+func (d *DNSCheck) Run(c client.Interface) ([]CheckResult, error) {
+	pods := c.ClientSet().CoreV1().Pods(coreNamespace)
+	if err := pods.Delete(dnsCheckPod, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: dnsCheckPod, Namespace: coreNamespace},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "nslookup",
+				Image:   d.Image,
+				Command: []string{"nslookup", d.Host},
+				// The output of a failed lookup becomes the message of the
+				// terminated container
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			}},
+		},
+	}
+	if _, err := pods.Create(pod); err != nil {
+		return nil, err
+	}
+	defer pods.Delete(dnsCheckPod, &metav1.DeleteOptions{}) //nolint:errcheck
+
+	result := CheckResult{Check: CheckDNS, Object: d.Host}
+	deadline := time.Now().Add(d.Timeout)
+	for {
+		live, err := pods.Get(dnsCheckPod, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case live.Status.Phase == corev1.PodSucceeded:
+			result.Passed = true
+			return []CheckResult{result}, nil
+		case live.Status.Phase == corev1.PodFailed:
+			result.Message = "resolution failed"
+			for _, container := range live.Status.ContainerStatuses {
+				if terminated := container.State.Terminated; terminated != nil && terminated.Message != "" {
+					result.Message += ": " + strings.TrimSpace(terminated.Message)
+				}
+			}
+			return []CheckResult{result}, nil
+		case time.Now().After(deadline):
+			result.Message = fmt.Sprintf("pod %s is still %s after %s", dnsCheckPod, live.Status.Phase, d.Timeout)
+			return []CheckResult{result}, nil
+		}
+		time.Sleep(d.PollInterval)
+	}
+}
+
+// DocumentCheck is a check declared by a ClusterCheck document of the
+// bundle: every resource it selects must match its condition
+type DocumentCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DocumentCheckSpec `json:"spec"`
+}
+
+// DocumentCheckSpec selects the resources of a DocumentCheck and the
+// condition they must match
+type DocumentCheckSpec struct {
+	// APIVersion and Kind of the resources
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace of the resources, all namespaces if empty
+	Namespace string `json:"namespace,omitempty"`
+	// Name selects a single resource
+	Name string `json:"name,omitempty"`
+	// LabelSelector selects the resources by label
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Condition is a JSONPath filter, see Expression
+	Condition string `json:"condition"`
+	// Message explains a failure, the condition is shown if empty
+	Message string `json:"message,omitempty"`
+}
+
+// DocumentChecks returns the checks declared by the ClusterCheck documents
+// of docs
+func DocumentChecks(docs []document.Document) ([]ClusterCheck, error) {
+	var checks []ClusterCheck
+	for _, doc := range docs {
+		if doc.GetKind() != document.ClusterCheckKind || doc.GetGroup() != document.ClusterCheckGroup {
+			continue
+		}
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		check := &DocumentCheck{}
+		if err = json.Unmarshal(data, check); err != nil {
+			return nil, ErrInvalidClusterCheck{Name: doc.GetName(), Reason: err.Error()}
+		}
+		switch {
+		case check.Spec.APIVersion == "" || check.Spec.Kind == "":
+			return nil, ErrInvalidClusterCheck{Name: check.GetName(), Reason: "spec.apiVersion and spec.kind are required"}
+		case check.Spec.Condition == "":
+			return nil, ErrInvalidClusterCheck{Name: check.GetName(), Reason: "spec.condition is required"}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// Name identifies the check in reports
+func (d *DocumentCheck) Name() string { return d.GetName() }
+
+// Run matches the resources selected by the check against its condition,
+// the check fails if it selects no resource
+func (d *DocumentCheck) Run(c client.Interface) ([]CheckResult, error) {
+	gvk := schema.FromAPIVersionAndKind(d.Spec.APIVersion, d.Spec.Kind)
+	list, err := c.List(gvk, d.Spec.Namespace, metav1.ListOptions{LabelSelector: d.Spec.LabelSelector})
+	if meta.IsNoMatchError(err) {
+		return []CheckResult{{Check: d.Name(), Object: "-", Message: fmt.Sprintf("unknown kind %s", gvk)}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expression := Expression{Condition: d.Spec.Condition}
+	message := d.Spec.Message
+	if message == "" {
+		message = fmt.Sprintf("condition %s not met", d.Spec.Condition)
+	}
+	var results []CheckResult
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if d.Spec.Name != "" && obj.GetName() != d.Spec.Name {
+			continue
+		}
+		matched, err := expression.Match(obj)
+		if err != nil {
+			return nil, err
+		}
+		result := CheckResult{Check: d.Name(), Object: checkedObject(obj), Passed: matched}
+		if !matched {
+			result.Message = message
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return []CheckResult{{Check: d.Name(), Object: "-", Message: fmt.Sprintf("no %s found", d.Spec.Kind)}}, nil
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Object < results[j].Object })
+	return results, nil
+}
+
+// checkedObject identifies obj in a report as Kind/namespace/name, or
+// Kind/name if it is cluster scoped
+func checkedObject(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetKind() + "/" + obj.GetName()
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,214 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+var deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+func checkedNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			{Type: corev1.NodeReady, Status: ready, Message: "kubelet stopped posting node status"},
+		}},
+	}
+}
+
+func checkedPod(name string, phase corev1.PodPhase, ready bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", Ready: ready}},
+		},
+	}
+}
+
+func checkedDeployment(namespace, name string, replicas, ready int64) *unstructured.Unstructured {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": replicas},
+		"status": map[string]interface{}{"readyReplicas": ready},
+	}}
+	deployment.SetGroupVersionKind(deploymentGVK)
+	deployment.SetNamespace(namespace)
+	deployment.SetName(name)
+	deployment.SetLabels(map[string]string{"app": namespace})
+	return deployment
+}
+
+// clientSetClient is a fake client whose ClientSet keeps its objects
+// between calls
+type clientSetClient struct {
+	*fake.Client
+	clientSet kubernetes.Interface
+}
+
+func (c clientSetClient) ClientSet() kubernetes.Interface {
+	return c.clientSet
+}
+
+func TestRunChecks(t *testing.T) {
+	conditionsMachine := clusterControlPlaneMachine("cp-2")
+	conditionsMachine.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+			map[string]interface{}{"type": "BootstrapReady", "status": "False", "reason": "WaitingForDataSecret"},
+		},
+	}
+	readyCluster := deletedCluster()
+	readyCluster.Object["status"] = map[string]interface{}{"infrastructureReady": true}
+
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK, deploymentGVK)),
+		fake.WithTypedObjects(
+			checkedNode("node2", corev1.ConditionUnknown),
+			checkedNode("node1", corev1.ConditionTrue),
+			checkedPod("etcd-node1", corev1.PodRunning, true),
+			checkedPod("coredns-1", corev1.PodRunning, false),
+			checkedPod("job-1", corev1.PodSucceeded, false),
+			checkedPod("proxy-1", corev1.PodPending, false),
+		),
+		fake.WithDynamicObjects(
+			readyCluster,
+			clusterControlPlaneMachine("cp-1"),
+			conditionsMachine,
+			checkedDeployment("ingress", "ingress-1", 2, 2),
+			checkedDeployment("ingress", "ingress-2", 2, 1),
+		))
+
+	bundle := testutil.NewTestBundle(t, "testdata/check")
+	docs, err := bundle.Select(document.NewClusterCheckSelector())
+	require.NoError(t, err)
+	checks, err := cluster.DocumentChecks(docs)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+
+	builtin := cluster.BuiltinChecks("busybox", time.Minute)
+	report := cluster.RunChecks(c, append(builtin[:3], checks...))
+	assert.False(t, report.Passed)
+	assert.Equal(t, cluster.ErrClusterChecksFailed{Failed: 7}, report.Err())
+
+	out := &bytes.Buffer{}
+	require.NoError(t, report.Print(out, cluster.OutputTable))
+	assert.Equal(t, ""+
+		"CHECK                OBJECT                                RESULT   MESSAGE\n"+
+		"nodes-ready          Node/node1                            PASS     -\n"+
+		"nodes-ready          Node/node2                            FAIL     "+
+		"Ready is Unknown: kubelet stopped posting node status\n"+
+		"core-pods            Pod/kube-system/coredns-1             FAIL     containers not ready: main\n"+
+		"core-pods            Pod/kube-system/etcd-node1            PASS     -\n"+
+		"core-pods            Pod/kube-system/job-1                 PASS     -\n"+
+		"core-pods            Pod/kube-system/proxy-1               FAIL     pod is Pending\n"+
+		"capi-conditions      Cluster/target-infra/target-cluster   FAIL     control plane not initialized\n"+
+		"capi-conditions      Machine/target-infra/cp-1             PASS     -\n"+
+		"capi-conditions      Machine/target-infra/cp-2             FAIL     "+
+		"BootstrapReady is False (WaitingForDataSecret)\n"+
+		"ingress-available    Deployment/ingress/ingress-1          PASS     -\n"+
+		"ingress-available    Deployment/ingress/ingress-2          FAIL     not all replicas are ready\n"+
+		"registry-available   -                                     FAIL     no Deployment found\n",
+		out.String())
+
+	out.Reset()
+	require.NoError(t, cluster.RunChecks(c, builtin[:1]).Print(out, cluster.OutputJSON))
+	assert.Equal(t, `{
+    "passed": false,
+    "results": [
+        {
+            "check": "nodes-ready",
+            "object": "Node/node1",
+            "passed": true
+        },
+        {
+            "check": "nodes-ready",
+            "object": "Node/node2",
+            "passed": false,
+            "message": "Ready is Unknown: kubelet stopped posting node status"
+        }
+    ]
+}
+`, out.String())
+	assert.Equal(t, cluster.ErrInvalidOutputFormat{Format: "yaml"}, report.Print(out, "yaml"))
+}
+
+func TestDNSCheck(t *testing.T) {
+	for _, phase := range []corev1.PodPhase{corev1.PodSucceeded, corev1.PodFailed, corev1.PodPending} {
+		clientSet := kubernetesfake.NewSimpleClientset()
+		clientSet.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+			pod.Status.Phase = phase
+			if phase == corev1.PodFailed {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name: "nslookup",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Message:  "nslookup: can't resolve 'kubernetes.default.svc.cluster.local'\n",
+					}},
+				}}
+			}
+			return false, nil, nil
+		})
+		check := &cluster.DNSCheck{
+			Image:        "busybox",
+			Host:         "kubernetes.default.svc.cluster.local",
+			Timeout:      10 * time.Millisecond,
+			PollInterval: time.Millisecond,
+		}
+
+		results, err := check.Run(clientSetClient{Client: fake.NewClient(), clientSet: clientSet})
+		require.NoError(t, err)
+		expected := cluster.CheckResult{Check: "dns", Object: "kubernetes.default.svc.cluster.local"}
+		switch phase {
+		case corev1.PodSucceeded:
+			expected.Passed = true
+		case corev1.PodFailed:
+			expected.Message = "resolution failed: nslookup: can't resolve 'kubernetes.default.svc.cluster.local'"
+		default:
+			expected.Message = "pod airshipctl-dns-check is still Pending after 10ms"
+		}
+		assert.Equal(t, []cluster.CheckResult{expected}, results)
+
+		// The pod is deleted once the check is done
+		pods, err := clientSet.CoreV1().Pods("kube-system").List(metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, pods.Items)
+	}
+}
+
+func TestDocumentChecksInvalid(t *testing.T) {
+	_, err := cluster.DocumentChecks(testDocuments(t, "testdata/check-invalid"))
+	assert.Equal(t, cluster.ErrInvalidClusterCheck{Name: "no-condition", Reason: "spec.condition is required"}, err)
+}
@@ -0,0 +1,330 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// Provisioning states of a BareMetalHost that was deprovisioned and can be
+// consumed again
+const (
+	hostStateReady     = "ready"
+	hostStateAvailable = "available"
+)
+
+// Deleter tears down workload clusters in the order Cluster API expects
+type Deleter struct {
+	Client       dynamic.Interface
+	Mapper       meta.RESTMapper
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives the resources deleted and the progress of the teardown
+	Out io.Writer
+}
+
+// NewDeleter returns a Deleter reporting to stdout
+func NewDeleter(c client.Interface, timeout time.Duration) *Deleter {
+	return &Deleter{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+// deletionStep is a group of resources of a cluster deleted together, along
+// with the Machines they own and the BareMetalHosts those are provisioned on
+type deletionStep struct {
+	name      string
+	resources []WatchTarget
+	// owns selects the Machines of the cluster removed by this step
+	owns     func(machine unstructured.Unstructured) bool
+	hosts    []string
+	progress string
+}
+
+// Delete removes the Cluster name in namespace in three steps: its
+// MachineDeployments, then its control plane, then the Cluster itself.
+// Each step blocks until its resources and their Machines are gone and the
+// BareMetalHosts of those Machines are deprovisioned, so that workers never
+// outlive the control plane they joined. An ErrDeleteTimeout is returned if
+// the teardown doesn't finish in time.
+func (d *Deleter) Delete(namespace, name string) error {
+	clusterResource, err := dynamicResource(d.Client, d.Mapper, ClusterGVK, namespace)
+	if err != nil {
+		return err
+	}
+	cluster, err := clusterResource.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	workers, err := d.machineDeployments(namespace, name)
+	if err != nil {
+		return err
+	}
+	steps := []*deletionStep{
+		{
+			name:      "workers",
+			resources: workers,
+			owns: func(machine unstructured.Unstructured) bool {
+				_, isControlPlane := machine.GetLabels()[controlPlaneLabel]
+				return !isControlPlane
+			},
+		},
+		{
+			name:      "control plane",
+			resources: controlPlaneTarget(cluster),
+			owns: func(machine unstructured.Unstructured) bool {
+				_, isControlPlane := machine.GetLabels()[controlPlaneLabel]
+				return isControlPlane
+			},
+		},
+		{
+			name:      "cluster",
+			resources: []WatchTarget{{GVK: ClusterGVK, Namespace: namespace, Name: name}},
+			owns:      func(unstructured.Unstructured) bool { return true },
+		},
+	}
+
+	deadline := time.Now().Add(d.Timeout)
+	for _, step := range steps {
+		if len(step.resources) == 0 {
+			continue
+		}
+		if err = d.deleteStep(step, namespace, name, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteStep deletes the resources of step and waits for the teardown of
+// their Machines and BareMetalHosts
+func (d *Deleter) deleteStep(step *deletionStep, namespace, clusterName string, deadline time.Time) error {
+	machines, err := d.machines(namespace, clusterName, step.owns)
+	if err != nil {
+		return err
+	}
+	if step.hosts, err = d.consumedHosts(namespace, machines); err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	for _, target := range step.resources {
+		resource, resErr := dynamicResource(d.Client, d.Mapper, target.GVK, target.Namespace)
+		if resErr != nil {
+			return resErr
+		}
+		err = resource.Delete(target.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		fmt.Fprintf(d.Out, "%s: deleting\n", target)
+	}
+
+	for {
+		done, checkErr := d.check(step, namespace, clusterName)
+		if checkErr != nil {
+			return checkErr
+		}
+		if done {
+			fmt.Fprintf(d.Out, "%s: deleted\n", step.name)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrDeleteTimeout{Step: step.name, Progress: step.progress}
+		}
+		time.Sleep(d.PollInterval)
+	}
+}
+
+// check reports the progress of step since the last check, and whether it
+// is done
+func (d *Deleter) check(step *deletionStep, namespace, clusterName string) (bool, error) {
+	remaining := 0
+	for _, target := range step.resources {
+		resource, err := dynamicResource(d.Client, d.Mapper, target.GVK, target.Namespace)
+		if err != nil {
+			return false, err
+		}
+		_, err = resource.Get(target.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return false, err
+		default:
+			remaining++
+		}
+	}
+	machines, err := d.machines(namespace, clusterName, step.owns)
+	if err != nil {
+		return false, err
+	}
+	deprovisioned, err := d.deprovisionedHosts(namespace, step.hosts)
+	if err != nil {
+		return false, err
+	}
+
+	progress := fmt.Sprintf("%d/%d resources deleted, %d machines left",
+		len(step.resources)-remaining, len(step.resources), len(machines))
+	if len(step.hosts) > 0 {
+		progress += fmt.Sprintf(", %d/%d hosts deprovisioned", deprovisioned, len(step.hosts))
+	}
+	if progress != step.progress {
+		step.progress = progress
+		fmt.Fprintf(d.Out, "%s: %s\n", step.name, progress)
+	}
+	return remaining == 0 && len(machines) == 0 && deprovisioned == len(step.hosts), nil
+}
+
+// machineDeployments returns the MachineDeployments of the Cluster name in
+// namespace
+func (d *Deleter) machineDeployments(namespace, name string) ([]WatchTarget, error) {
+	resource, err := dynamicResource(d.Client, d.Mapper, MachineDeploymentGVK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resource.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var targets []WatchTarget
+	for _, md := range list.Items {
+		if clusterName, _, _ := unstructured.NestedString(md.Object, "spec", "clusterName"); clusterName == name {
+			targets = append(targets, WatchTarget{GVK: MachineDeploymentGVK, Namespace: namespace, Name: md.GetName()})
+		}
+	}
+	return targets, nil
+}
+
+// machines returns the Machines of the Cluster clusterName in namespace
+// selected by owns
+func (d *Deleter) machines(namespace, clusterName string,
+	owns func(unstructured.Unstructured) bool) ([]unstructured.Unstructured, error) {
+	resource, err := dynamicResource(d.Client, d.Mapper, MachineGVK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resource.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var machines []unstructured.Unstructured
+	for _, machine := range list.Items {
+		name, _, _ := unstructured.NestedString(machine.Object, "spec", "clusterName")
+		if name == clusterName && owns(machine) {
+			machines = append(machines, machine)
+		}
+	}
+	return machines, nil
+}
+
+// consumedHosts returns the names of the BareMetalHosts of namespace
+// consumed by the infrastructure machines of machines. There are none if
+// the BareMetalHost kind isn't known to the cluster.
+func (d *Deleter) consumedHosts(namespace string, machines []unstructured.Unstructured) ([]string, error) {
+	if len(machines) == 0 {
+		return nil, nil
+	}
+	hosts, err := d.hosts(namespace)
+	if err != nil || hosts == nil {
+		return nil, err
+	}
+	consumers := make(map[string]bool, len(machines))
+	for _, machine := range machines {
+		kind, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "kind")
+		name, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "name")
+		consumers[refKey(namespace, kind, name)] = true
+	}
+	var consumed []string
+	for _, host := range hosts.Items {
+		kind, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "kind")
+		name, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "name")
+		if consumers[refKey(namespace, kind, name)] {
+			consumed = append(consumed, host.GetName())
+		}
+	}
+	return consumed, nil
+}
+
+// deprovisionedHosts returns how many of the BareMetalHosts names of
+// namespace were deprovisioned or removed
+func (d *Deleter) deprovisionedHosts(namespace string, names []string) (int, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+	hosts, err := d.hosts(namespace)
+	if err != nil || hosts == nil {
+		return 0, err
+	}
+	states := make(map[string]string, len(hosts.Items))
+	for _, host := range hosts.Items {
+		states[host.GetName()], _, _ = unstructured.NestedString(host.Object, "status", "provisioning", "state")
+	}
+	deprovisioned := 0
+	for _, name := range names {
+		state, found := states[name]
+		if !found || state == hostStateReady || state == hostStateAvailable {
+			deprovisioned++
+		}
+	}
+	return deprovisioned, nil
+}
+
+// hosts lists the BareMetalHosts of namespace, it returns nil if the
+// BareMetalHost kind isn't known to the cluster
+func (d *Deleter) hosts(namespace string) (*unstructured.UnstructuredList, error) {
+	resource, err := dynamicResource(d.Client, d.Mapper, BareMetalHostGVK, namespace)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resource.List(metav1.ListOptions{})
+}
+
+// controlPlaneTarget returns the control plane cluster refers to, if any
+func controlPlaneTarget(cluster *unstructured.Unstructured) []WatchTarget {
+	ref, found, _ := unstructured.NestedStringMap(cluster.Object, "spec", "controlPlaneRef")
+	if !found || ref["name"] == "" {
+		return nil
+	}
+	namespace := ref["namespace"]
+	if namespace == "" {
+		namespace = cluster.GetNamespace()
+	}
+	return []WatchTarget{{
+		GVK:       schema.FromAPIVersionAndKind(ref["apiVersion"], ref["kind"]),
+		Namespace: namespace,
+		Name:      ref["name"],
+	}}
+}
@@ -0,0 +1,149 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+)
+
+var (
+	machinesGVR = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1alpha3", Resource: "machines"}
+	hostsGVR    = schema.GroupVersionResource{Group: "metal3.io", Version: "v1alpha1", Resource: "baremetalhosts"}
+)
+
+func deletedCluster() *unstructured.Unstructured {
+	return provisioningObject(cluster.ClusterGVK, "target-cluster",
+		map[string]interface{}{
+			"controlPlaneRef": map[string]interface{}{
+				"apiVersion": "controlplane.cluster.x-k8s.io/v1alpha3",
+				"kind":       "KubeadmControlPlane",
+				"name":       "target-cp",
+			},
+		}, map[string]interface{}{}, nil)
+}
+
+func clusterControlPlaneMachine(name string) *unstructured.Unstructured {
+	return provisioningObject(cluster.MachineGVK, name,
+		map[string]interface{}{
+			"clusterName":       "target-cluster",
+			"infrastructureRef": map[string]interface{}{"kind": "Metal3Machine", "name": name},
+		}, map[string]interface{}{"phase": "Running"},
+		map[string]interface{}{"cluster.x-k8s.io/control-plane": ""})
+}
+
+// tearDownOnDelete makes the deletion of resource remove machine and
+// deprovision host, the way the Cluster API and baremetal controllers do.
+// The objects are changed through the reactor backing the fake client, as
+// the client itself can't be called from one of its reactors.
+func tearDownOnDelete(c *dynamicfake.FakeDynamicClient, resource, machine, host string) {
+	objects := c.ReactionChain[len(c.ReactionChain)-1]
+	c.PrependReactor("delete", resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		_, _, err := objects.React(k8stesting.NewDeleteAction(machinesGVR, "target-infra", machine))
+		if err != nil {
+			return true, nil, err
+		}
+		_, _, err = objects.React(k8stesting.NewUpdateAction(hostsGVR, "target-infra",
+			workerHost(host, "", "ready")))
+		return err != nil, nil, err
+	})
+}
+
+func newTestDeleter(c *dynamicfake.FakeDynamicClient) (*cluster.Deleter, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &cluster.Deleter{
+		Client: c,
+		Mapper: provisioningMapper(cluster.ClusterGVK, cluster.KubeadmControlPlaneGVK,
+			cluster.MachineDeploymentGVK, cluster.MachineGVK, cluster.BareMetalHostGVK),
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Out:          out,
+	}, out
+}
+
+func deleteTestObjects() []runtime.Object {
+	return []runtime.Object{
+		deletedCluster(),
+		liveControlPlane(false, map[string]interface{}{}),
+		provisioningObject(cluster.KubeadmControlPlaneGVK, "target-cp", map[string]interface{}{},
+			map[string]interface{}{}, nil),
+		machineDeployment(1),
+		clusterControlPlaneMachine("target-cp-1"),
+		workerMachine("workers-1", "Running", "node02"),
+		workerHost("node01", "target-cp-1", "provisioned"),
+		workerHost("node02", "workers-1", "provisioned"),
+	}
+}
+
+func TestDelete(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deleteTestObjects()...)
+	tearDownOnDelete(c, "machinedeployments", "workers-1", "node02")
+	tearDownOnDelete(c, "kubeadmcontrolplanes", "target-cp-1", "node01")
+	deleter, out := newTestDeleter(c)
+
+	require.NoError(t, deleter.Delete("target-infra", "target-cluster"))
+	assert.Equal(t, workersTarget+": deleting\n"+
+		"workers: 1/1 resources deleted, 0 machines left, 1/1 hosts deprovisioned\n"+
+		"workers: deleted\n"+
+		"KubeadmControlPlane/target-cp in namespace target-infra: deleting\n"+
+		"control plane: 1/1 resources deleted, 0 machines left, 1/1 hosts deprovisioned\n"+
+		"control plane: deleted\n"+
+		"Cluster/target-cluster in namespace target-infra: deleting\n"+
+		"cluster: 1/1 resources deleted, 0 machines left\n"+
+		"cluster: deleted\n", out.String())
+
+	// the control plane KubeadmControlPlane of another cluster is left alone
+	_, err := c.Resource(schema.GroupVersionResource{
+		Group:    "controlplane.cluster.x-k8s.io",
+		Version:  "v1alpha3",
+		Resource: "kubeadmcontrolplanes",
+	}).Namespace("target-infra").Get("cluster-controlplane", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestDeleteTimeout(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deleteTestObjects()...)
+	deleter, out := newTestDeleter(c)
+
+	err := deleter.Delete("target-infra", "target-cluster")
+	assert.Equal(t, cluster.ErrDeleteTimeout{
+		Step:     "workers",
+		Progress: "1/1 resources deleted, 1 machines left, 0/1 hosts deprovisioned",
+	}, err)
+	assert.Equal(t, workersTarget+": deleting\n"+
+		"workers: 1/1 resources deleted, 1 machines left, 0/1 hosts deprovisioned\n", out.String())
+}
+
+func TestDeleteClusterNotFound(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	deleter, out := newTestDeleter(c)
+
+	err := deleter.Delete("target-infra", "target-cluster")
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,231 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+// redactedValue replaces the values of secret data in differences
+const redactedValue = "<redacted>"
+
+// FieldDiff is a field of a resource whose live value doesn't match the
+// value in the documents
+type FieldDiff struct {
+	// Path is the dot separated path to the field, e.g. spec.replicas
+	Path string
+	// Live is empty if the field isn't set in the cluster
+	Live    string
+	Desired string
+}
+
+func (d FieldDiff) String() string {
+	if d.Live == "" {
+		return fmt.Sprintf("+ %s: %s", d.Path, d.Desired)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Path, d.Live, d.Desired)
+}
+
+// ResourceDiff lists how a live resource drifted from its document
+type ResourceDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Missing is true if the resource doesn't exist in the cluster
+	Missing bool
+	Fields  []FieldDiff
+}
+
+func (d ResourceDiff) String() string {
+	name := fmt.Sprintf("%s/%s", d.Kind, d.Name)
+	if d.Namespace != "" {
+		name = fmt.Sprintf("%s in namespace %s", name, d.Namespace)
+	}
+	if d.Missing {
+		return fmt.Sprintf("%s is missing from the cluster", name)
+	}
+	return name
+}
+
+// Differ compares documents with the live objects in a cluster
+type Differ struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// Retry configures retries of API calls failing with transient errors
+	Retry client.RetryOptions
+}
+
+// NewDiffer returns a Differ finding the resources of documents with the
+// RESTMapper of the client
+func NewDiffer(c client.Interface) *Differ {
+	return &Differ{
+		Client: c.DynamicClient(),
+		Mapper: c.RESTMapper(),
+		Retry:  client.DefaultRetryOptions(),
+	}
+}
+
+// Diff returns the resources of docs which are missing from the cluster or
+// whose fields differ from the documents. Only the fields set in the
+// documents are compared, so values defaulted by the cluster are not
+// reported. Namespaced documents without a namespace are looked up in
+// defaultNamespace.
+func (d *Differ) Diff(docs []document.Document, defaultNamespace string) ([]ResourceDiff, error) {
+	var diffs []ResourceDiff
+	for _, doc := range docs {
+		gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+		mapping, err := d.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := ResourceDiff{Kind: gvk.Kind, Name: doc.GetName()}
+		var resource dynamic.ResourceInterface = d.Client.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			diff.Namespace = doc.GetNamespace()
+			if diff.Namespace == "" {
+				diff.Namespace = defaultNamespace
+			}
+			resource = d.Client.Resource(mapping.Resource).Namespace(diff.Namespace)
+		}
+
+		var live *unstructured.Unstructured
+		err = client.Retry(d.Retry, "Get of "+diff.String(), func() (getErr error) {
+			live, getErr = resource.Get(diff.Name, metav1.GetOptions{})
+			return getErr
+		})
+		switch {
+		case apierrors.IsNotFound(err):
+			diff.Missing = true
+			diffs = append(diffs, diff)
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		desired, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]interface{}{}
+		if err = json.Unmarshal(desired, &fields); err != nil {
+			return nil, err
+		}
+		// Namespace is defaulted above and status is owned by the cluster
+		unstructuredMetadata, _ := fields["metadata"].(map[string]interface{})
+		delete(unstructuredMetadata, "namespace")
+		delete(fields, "status")
+
+		secret := gvk.Kind == document.SecretKind
+		if secret {
+			mergeStringData(fields)
+		}
+		diffFields("", live.Object, fields, secret, false, &diff.Fields)
+		if len(diff.Fields) > 0 {
+			sort.SliceStable(diff.Fields, func(i, j int) bool { return diff.Fields[i].Path < diff.Fields[j].Path })
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+func diffFields(path string, live, desired interface{}, secret, redact bool, diffs *[]FieldDiff) {
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredList, desiredIsList := desired.([]interface{})
+	liveList, liveIsList := live.([]interface{})
+
+	switch {
+	case desiredIsMap && liveIsMap:
+		for key, value := range desiredMap {
+			keyPath := joinFieldPath(path, key)
+			// Values of secrets are never printed
+			keyRedact := redact || (secret && path == "" && key == "data")
+			liveValue, exists := liveMap[key]
+			if !exists {
+				*diffs = append(*diffs, FieldDiff{Path: keyPath, Desired: renderField(value, keyRedact)})
+				continue
+			}
+			diffFields(keyPath, liveValue, value, secret, keyRedact, diffs)
+		}
+	case desiredIsList && liveIsList && len(desiredList) == len(liveList):
+		for i := range desiredList {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), liveList[i], desiredList[i], secret, redact, diffs)
+		}
+	default:
+		liveString, desiredString := renderField(live, false), renderField(desired, false)
+		if liveString == desiredString {
+			return
+		}
+		if redact {
+			liveString, desiredString = redactedValue, redactedValue
+		}
+		*diffs = append(*diffs, FieldDiff{Path: path, Live: liveString, Desired: desiredString})
+	}
+}
+
+// mergeStringData moves the stringData of a Secret to its data, the same
+// way the API server does, since stringData is never returned by it
+func mergeStringData(fields map[string]interface{}) {
+	stringData, ok := fields["stringData"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	data, ok := fields["data"].(map[string]interface{})
+	if !ok {
+		data = map[string]interface{}{}
+		fields["data"] = data
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+	delete(fields, "stringData")
+}
+
+func joinFieldPath(path, key string) string {
+	// Keep paths readable when keys contain dots, e.g. annotations
+	if strings.Contains(key, ".") {
+		key = fmt.Sprintf("%q", key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func renderField(value interface{}, redact bool) string {
+	if redact {
+		return redactedValue
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
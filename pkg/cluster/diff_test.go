@@ -0,0 +1,108 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	docs, err := testutil.NewTestBundle(t, "testdata/diff").GetAllDocuments()
+	require.NoError(t, err)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "test", "generation": int64(4)},
+		"spec": map[string]interface{}{
+			"replicas":             int64(2),
+			"revisionHistoryLimit": int64(10),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.18"},
+					},
+				},
+			},
+		},
+	}}
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "credentials", "namespace": "test"},
+		"data":       map[string]interface{}{"password": "b2xkLXBhc3N3b3Jk"},
+	}}
+	namespace := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "unchanged"},
+		"status":     map[string]interface{}{"phase": "Active"},
+	}}
+
+	differ := &cluster.Differ{
+		Client: fake.NewClient(fake.WithDynamicObjects(deployment, secret, namespace)).DynamicClient(),
+		Mapper: mapper,
+	}
+	diffs, err := differ.Diff(docs, "test")
+	require.NoError(t, err)
+
+	// Documents are ordered by kustomize
+	assert.Equal(t, []cluster.ResourceDiff{
+		{
+			Kind:      "ConfigMap",
+			Namespace: "other",
+			Name:      "missing",
+			Missing:   true,
+		},
+		{
+			Kind:      "Secret",
+			Namespace: "test",
+			Name:      "credentials",
+			Fields: []cluster.FieldDiff{
+				{Path: `data.password`, Live: "<redacted>", Desired: "<redacted>"},
+			},
+		},
+		{
+			Kind:      "Deployment",
+			Namespace: "test",
+			Name:      "web",
+			Fields: []cluster.FieldDiff{
+				{Path: `metadata.labels`, Desired: `{"app.kubernetes.io/name":"web"}`},
+				{Path: `spec.replicas`, Live: `2`, Desired: `3`},
+				{Path: `spec.template.spec.containers[0].image`, Live: `"nginx:1.18"`, Desired: `"nginx:1.19"`},
+			},
+		},
+	}, diffs)
+
+	assert.Equal(t, "ConfigMap/missing in namespace other is missing from the cluster", diffs[0].String())
+	assert.Equal(t, "~ spec.replicas: 2 -> 3", diffs[2].Fields[1].String())
+	assert.Equal(t, `+ metadata.labels: {"app.kubernetes.io/name":"web"}`, diffs[2].Fields[0].String())
+}
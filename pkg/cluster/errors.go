@@ -14,7 +14,11 @@
 
 package cluster
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ErrInvalidStatusCheck denotes that something went wrong while handling a
 // status-check annotation.
@@ -35,3 +39,390 @@ type ErrResourceNotFound struct {
 func (err ErrResourceNotFound) Error() string {
 	return fmt.Sprintf("could not find a status for resource %q", err.Resource)
 }
+
+// ErrDriftDetected is returned when live resources differ from the documents
+type ErrDriftDetected struct {
+	Resources int
+}
+
+func (err ErrDriftDetected) Error() string {
+	return fmt.Sprintf("%d resource(s) differ from the documents", err.Resources)
+}
+
+// ErrInvalidWatchTarget is returned when a watched resource is not given as
+// KIND/name or KIND/namespace/name
+type ErrInvalidWatchTarget struct {
+	Value string
+}
+
+func (err ErrInvalidWatchTarget) Error() string {
+	return fmt.Sprintf("invalid resource %q, must be KIND/name or KIND/namespace/name", err.Value)
+}
+
+// ErrInvalidWatchCondition is returned when a watched condition is not
+// given as Type[=Status]
+type ErrInvalidWatchCondition struct {
+	Value string
+}
+
+func (err ErrInvalidWatchCondition) Error() string {
+	return fmt.Sprintf("invalid condition %q, must be Type[=Status]", err.Value)
+}
+
+// ErrNoWatchTargets is returned when there are no resources to watch
+type ErrNoWatchTargets struct{}
+
+func (err ErrNoWatchTargets) Error() string {
+	return "no resources to watch, give them as arguments or with --phase"
+}
+
+// ErrWatchTimeout is returned when watched resources don't reach the
+// expected condition in time
+type ErrWatchTimeout struct {
+	Resources []string
+	Condition string
+}
+
+func (err ErrWatchTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for condition %s of %s", err.Condition, strings.Join(err.Resources, ", "))
+}
+
+// ErrInvalidOutputFormat is returned for an unknown provisioning status
+// format
+type ErrInvalidOutputFormat struct {
+	Format string
+}
+
+func (err ErrInvalidOutputFormat) Error() string {
+	return fmt.Sprintf("invalid output format %q, must be one of %q or %q", err.Format, OutputTable, OutputJSON)
+}
+
+// ErrKubeconfigNotFound is returned when the management cluster has no
+// kubeconfig Secret for a cluster
+type ErrKubeconfigNotFound struct {
+	Cluster   string
+	Namespace string
+}
+
+func (err ErrKubeconfigNotFound) Error() string {
+	return fmt.Sprintf("no kubeconfig found for cluster %q in namespace %q, Secret %q does not exist",
+		err.Cluster, err.Namespace, KubeconfigSecretName(err.Cluster))
+}
+
+// ErrInvalidKubeconfigSecret is returned when the kubeconfig Secret of a
+// cluster doesn't hold a kubeconfig
+type ErrInvalidKubeconfigSecret struct {
+	Secret    string
+	Namespace string
+	Key       string
+}
+
+func (err ErrInvalidKubeconfigSecret) Error() string {
+	return fmt.Sprintf("Secret %q in namespace %q has no kubeconfig under key %q", err.Secret, err.Namespace, err.Key)
+}
+
+// ErrNoControlPlanes is returned when the documents have no
+// KubeadmControlPlane to upgrade
+type ErrNoControlPlanes struct{}
+
+func (err ErrNoControlPlanes) Error() string {
+	return "no KubeadmControlPlane found in the documents"
+}
+
+// ErrMachineTemplateNotFound is returned when the machine template a
+// KubeadmControlPlane or a MachineDeployment is upgraded to is neither in
+// the cluster nor in the documents
+type ErrMachineTemplateNotFound struct {
+	Template string
+}
+
+func (err ErrMachineTemplateNotFound) Error() string {
+	return fmt.Sprintf("machine template %s not found in the cluster or the documents", err.Template)
+}
+
+// ErrUpgradePaused is returned when control planes to upgrade are paused
+type ErrUpgradePaused struct {
+	Resources []string
+}
+
+func (err ErrUpgradePaused) Error() string {
+	return fmt.Sprintf("upgrade of %s is paused", strings.Join(err.Resources, ", "))
+}
+
+// ErrUpgradeFailed is returned when a control plane reports a failure
+// during an upgrade
+type ErrUpgradeFailed struct {
+	Resource string
+	Reason   string
+	Message  string
+}
+
+func (err ErrUpgradeFailed) Error() string {
+	return fmt.Sprintf("upgrade of %s failed: %s (%s)", err.Resource, err.Message, err.Reason)
+}
+
+// ErrUpgradeTimeout is returned when control planes don't finish rolling
+// out in time
+type ErrUpgradeTimeout struct {
+	Resources []string
+}
+
+func (err ErrUpgradeTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for the upgrade of %s", strings.Join(err.Resources, ", "))
+}
+
+// ErrNoMachineDeployments is returned when the documents have no
+// MachineDeployment to scale
+type ErrNoMachineDeployments struct{}
+
+func (err ErrNoMachineDeployments) Error() string {
+	return "no MachineDeployment found in the documents"
+}
+
+// ErrInvalidReplicas is returned when a MachineDeployment is scaled to a
+// negative number of replicas
+type ErrInvalidReplicas struct {
+	Replicas int64
+}
+
+func (err ErrInvalidReplicas) Error() string {
+	return fmt.Sprintf("invalid number of replicas %d, must not be negative", err.Replicas)
+}
+
+// ErrReplicasRequired is returned when a MachineDeployment is given without
+// the number of replicas to scale it to
+type ErrReplicasRequired struct{}
+
+func (err ErrReplicasRequired) Error() string {
+	return "--replicas is required with --machinedeployment"
+}
+
+// ErrScaleTimeout is returned when MachineDeployments don't get to the
+// expected number of ready replicas in time
+type ErrScaleTimeout struct {
+	Resources []string
+}
+
+func (err ErrScaleTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for the replicas of %s to be ready", strings.Join(err.Resources, ", "))
+}
+
+// ErrDeleteNotConfirmed is returned when a cluster is to be deleted without
+// the confirmation flag
+type ErrDeleteNotConfirmed struct {
+	Cluster string
+}
+
+func (err ErrDeleteNotConfirmed) Error() string {
+	return fmt.Sprintf("refusing to delete cluster %s without --confirm", err.Cluster)
+}
+
+// ErrDeleteTimeout is returned when a step of the teardown of a cluster
+// doesn't finish in time
+type ErrDeleteTimeout struct {
+	Step     string
+	Progress string
+}
+
+func (err ErrDeleteTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for the deletion of the %s (%s)", err.Step, err.Progress)
+}
+
+// ErrControlPlaneTimeout is returned when the control plane of a cluster
+// isn't ready in time
+type ErrControlPlaneTimeout struct {
+	Cluster string
+	Timeout time.Duration
+	State   string
+}
+
+func (err ErrControlPlaneTimeout) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for the control plane of %s: %s", err.Timeout, err.Cluster, err.State)
+}
+
+// ErrInvalidBackup is returned when a file isn't a backup saved by airshipctl
+type ErrInvalidBackup struct {
+	Path string
+	Err  error
+}
+
+func (err ErrInvalidBackup) Error() string {
+	return fmt.Sprintf("%s is not a valid backup: %v", err.Path, err.Err)
+}
+
+// ErrRestoreFailed is returned when objects of a backup couldn't be restored
+type ErrRestoreFailed struct {
+	Objects []string
+}
+
+func (err ErrRestoreFailed) Error() string {
+	return fmt.Sprintf("failed to restore %s", strings.Join(err.Objects, ", "))
+}
+
+// ErrContextsUnreachable is returned when the management clusters of
+// contexts couldn't be queried
+type ErrContextsUnreachable struct {
+	Contexts []string
+}
+
+func (err ErrContextsUnreachable) Error() string {
+	return fmt.Sprintf("failed to list the clusters of contexts %s", strings.Join(err.Contexts, ", "))
+}
+
+// ErrNodeUpgradePaused is returned when the rollout of a MachineDeployment
+// was paused after a batch of machines was replaced
+type ErrNodeUpgradePaused struct {
+	Resource string
+	Replaced int64
+	Replicas int64
+}
+
+func (err ErrNodeUpgradePaused) Error() string {
+	return fmt.Sprintf("upgrade of %s paused after %d/%d machines were replaced, run the upgrade again to continue",
+		err.Resource, err.Replaced, err.Replicas)
+}
+
+// ErrNoKubeadmControlPlane is returned when the control plane of a cluster
+// isn't managed by a KubeadmControlPlane
+type ErrNoKubeadmControlPlane struct {
+	Cluster string
+}
+
+func (err ErrNoKubeadmControlPlane) Error() string {
+	return fmt.Sprintf("the control plane of %s is not a KubeadmControlPlane", err.Cluster)
+}
+
+// ErrCertRotationFailed is returned when the machines of a control plane
+// can't be replaced to rotate its certificates
+type ErrCertRotationFailed struct {
+	Resource string
+	Reason   string
+}
+
+func (err ErrCertRotationFailed) Error() string {
+	return fmt.Sprintf("certificate rotation of %s failed: %s", err.Resource, err.Reason)
+}
+
+// ErrCertRotationTimeout is returned when the machines of a control plane
+// aren't replaced in time
+type ErrCertRotationTimeout struct {
+	Resource string
+	Progress string
+}
+
+func (err ErrCertRotationTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for the certificate rotation of %s: %s", err.Resource, err.Progress)
+}
+
+// ErrCertificatesNotRotated is returned when the certificate of the API
+// server of a cluster didn't change after its machines were replaced
+type ErrCertificatesNotRotated struct {
+	Cluster string
+	Expiry  time.Time
+}
+
+func (err ErrCertificatesNotRotated) Error() string {
+	return fmt.Sprintf("the API server certificate of %s still expires %s after the rollout",
+		err.Cluster, err.Expiry.Format(time.RFC3339))
+}
+
+// ErrNoEtcdMembers is returned when a workload cluster has no running etcd
+// static pod
+type ErrNoEtcdMembers struct {
+	Namespace string
+}
+
+func (err ErrNoEtcdMembers) Error() string {
+	return fmt.Sprintf("no running etcd pod found in namespace %s", err.Namespace)
+}
+
+// ErrEtcdCommandFailed is returned when a command run in a pod to take or
+// restore an etcd snapshot fails
+type ErrEtcdCommandFailed struct {
+	Pod    string
+	Reason string
+}
+
+func (err ErrEtcdCommandFailed) Error() string {
+	return fmt.Sprintf("command in pod %s failed: %s", err.Pod, err.Reason)
+}
+
+// ErrEtcdSnapshotPassphraseRequired is returned when an encrypted etcd
+// snapshot is restored without a passphrase
+type ErrEtcdSnapshotPassphraseRequired struct {
+	Path string
+}
+
+func (err ErrEtcdSnapshotPassphraseRequired) Error() string {
+	return fmt.Sprintf("etcd snapshot %s is encrypted, a passphrase is required to restore it", err.Path)
+}
+
+// ErrEtcdRestoreFailed is returned when the pod restoring an etcd snapshot
+// on a node fails, Reason is the end of its output
+type ErrEtcdRestoreFailed struct {
+	Pod    string
+	Node   string
+	Reason string
+}
+
+func (err ErrEtcdRestoreFailed) Error() string {
+	if err.Reason == "" {
+		return fmt.Sprintf("restore of etcd on node %s failed in pod %s", err.Node, err.Pod)
+	}
+	return fmt.Sprintf("restore of etcd on node %s failed in pod %s: %s", err.Node, err.Pod, err.Reason)
+}
+
+// ErrEtcdRestoreTimeout is returned when the pods restoring an etcd
+// snapshot don't reach a phase, or the etcd pods don't become healthy, in
+// time
+type ErrEtcdRestoreTimeout struct {
+	Pods  []string
+	Phase string
+}
+
+func (err ErrEtcdRestoreTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for pods %s to be %s", strings.Join(err.Pods, ", "), err.Phase)
+}
+
+// ErrEtcdRestoreNotConfirmed is returned when etcd is to be restored
+// without the confirmation flag
+type ErrEtcdRestoreNotConfirmed struct {
+	Cluster string
+}
+
+func (err ErrEtcdRestoreNotConfirmed) Error() string {
+	return fmt.Sprintf("refusing to restore etcd of cluster %s without --confirm", err.Cluster)
+}
+
+// ErrClusterChecksFailed is returned when checks of a cluster failed
+type ErrClusterChecksFailed struct {
+	Failed int
+}
+
+func (err ErrClusterChecksFailed) Error() string {
+	return fmt.Sprintf("%d cluster checks failed", err.Failed)
+}
+
+// ErrInvalidClusterCheck is returned for a ClusterCheck document which
+// doesn't declare a valid check
+type ErrInvalidClusterCheck struct {
+	Name   string
+	Reason string
+}
+
+func (err ErrInvalidClusterCheck) Error() string {
+	return fmt.Sprintf("invalid ClusterCheck %s: %s", err.Name, err.Reason)
+}
+
+// ErrNodesNotReady is returned when the nodes of a cluster aren't Ready in
+// time
+type ErrNodesNotReady struct {
+	Cluster string
+	Ready   int
+	Total   int
+}
+
+func (err ErrNodesNotReady) Error() string {
+	return fmt.Sprintf("timed out waiting for the nodes of %s to be ready: %d/%d nodes ready",
+		err.Cluster, err.Ready, err.Total)
+}
@@ -0,0 +1,465 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	// etcdNamespace and etcdSelector find the etcd static pods kubeadm runs
+	// on the control plane nodes
+	etcdNamespace = "kube-system"
+	etcdSelector  = "component=etcd"
+	etcdContainer = "etcd"
+
+	// etcdSnapshotTmp is where a snapshot is saved in the etcd container
+	// before it is streamed out
+	etcdSnapshotTmp = "/tmp/airshipctl-snapshot.db"
+
+	// etcdRestoreLabel is set on the pods restoring a snapshot
+	etcdRestoreLabel = "airshipctl-etcd-restore"
+	// etcdRestoreTrigger is created in the restore pods once the snapshot
+	// was uploaded to all of them
+	etcdRestoreTrigger = "/tmp/airshipctl-restore"
+	// kubeadmManifestsDir holds the manifests of the static pods of the
+	// control plane
+	kubeadmManifestsDir = "/etc/kubernetes/manifests"
+)
+
+// EtcdMember is an etcd static pod of a workload cluster, described by the
+// flags kubeadm runs it with
+type EtcdMember struct {
+	Pod           string
+	Node          string
+	Image         string
+	Name          string
+	PeerURL       string
+	ClientURL     string
+	DataDir       string
+	CertFile      string
+	KeyFile       string
+	TrustedCAFile string
+}
+
+// EtcdManager takes and restores snapshots of the etcd cluster of a
+// workload cluster, running etcdctl in the pods of its control plane nodes
+type EtcdManager struct {
+	// ClientSet is a client of the workload cluster
+	ClientSet kubernetes.Interface
+	// Exec runs commands in the pods of the workload cluster
+	Exec         client.ExecFunc
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives the progress of the snapshots and restores
+	Out io.Writer
+}
+
+// NewEtcdManager returns an EtcdManager of the workload cluster called
+// clusterName in namespace, reached with the kubeconfig Cluster API
+// generated for it, reporting to stdout
+func NewEtcdManager(c client.Interface, namespace, clusterName string, timeout time.Duration) (*EtcdManager, error) {
+	kubeconfig, err := GetKubeconfig(c, namespace, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &EtcdManager{
+		ClientSet:    clientSet,
+		Exec:         client.PodExecutor(clientSet, config),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}, nil
+}
+
+// Members returns the running etcd members of the cluster, by pod name
+func (m *EtcdManager) Members() ([]EtcdMember, error) {
+	pods, err := m.ClientSet.CoreV1().Pods(etcdNamespace).List(metav1.ListOptions{LabelSelector: etcdSelector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	var members []EtcdMember
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if container.Name == etcdContainer {
+				members = append(members, etcdMember(pod, container))
+			}
+		}
+	}
+	if len(members) == 0 {
+		return nil, ErrNoEtcdMembers{Namespace: etcdNamespace}
+	}
+	return members, nil
+}
+
+// Snapshot saves a snapshot of etcd taken on its first member to path. The
+// snapshot is encrypted with passphrase unless it is empty.
+func (m *EtcdManager) Snapshot(path, passphrase string) error {
+	members, err := m.Members()
+	if err != nil {
+		return err
+	}
+	member := members[0]
+
+	save := member.etcdctl() + " snapshot save " + etcdSnapshotTmp + " >&2"
+	snapshot := &bytes.Buffer{}
+	if err = m.run(member.Pod, etcdContainer,
+		fmt.Sprintf("%s && cat %s && rm -f %s", save, etcdSnapshotTmp, etcdSnapshotTmp), nil, snapshot); err != nil {
+		return err
+	}
+	if snapshot.Len() == 0 {
+		return ErrEtcdCommandFailed{Pod: member.Pod, Reason: "the snapshot is empty"}
+	}
+
+	data := snapshot.Bytes()
+	encrypted := ""
+	if passphrase != "" {
+		if data, err = secret.Encrypt(data, passphrase); err != nil {
+			return err
+		}
+		encrypted = ", encrypted"
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Out, "Snapshot of etcd member %s saved to %s (%d bytes%s)\n", member.Name, path,
+		snapshot.Len(), encrypted)
+	return nil
+}
+
+// Restore replaces the data of all etcd members with the snapshot saved at
+// path by Snapshot, decrypting it with passphrase if it is encrypted.
+//
+// A pod is started on each control plane node, the snapshot is uploaded to
+// all of them before any is restored. Each pod then restores the snapshot
+// next to the data directory of its member, stops etcd and the API server
+// by moving their static pod manifests away, swaps the data directories,
+// keeping the previous one with an .airshipctl-previous suffix, and moves
+// the manifests back. The API server of the cluster is unreachable until
+// the restore is done. The restore pods were created after the snapshot was
+// taken, so they are gone from the restored cluster: a pod that is not found
+// after the restore was triggered is done, and the restore is confirmed by
+// the health of every etcd member. The restore pods are deleted whether the
+// restore succeeded or not.
+func (m *EtcdManager) Restore(path, passphrase string) (err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if secret.IsEncrypted(data) {
+		if passphrase == "" {
+			return ErrEtcdSnapshotPassphraseRequired{Path: path}
+		}
+		if data, err = secret.Decrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	members, err := m.Members()
+	if err != nil {
+		return err
+	}
+	initialCluster := make([]string, 0, len(members))
+	for _, member := range members {
+		initialCluster = append(initialCluster, member.Name+"="+member.PeerURL)
+	}
+
+	pods := make([]string, 0, len(members))
+	defer func() {
+		if deleteErr := m.deleteRestorePods(pods); err == nil {
+			err = deleteErr
+		}
+	}()
+	for _, member := range members {
+		pod, createErr := m.createRestorePod(member, strings.Join(initialCluster, ","))
+		if createErr != nil {
+			return createErr
+		}
+		pods = append(pods, pod)
+	}
+	if err = m.waitForRestorePods(pods, corev1.PodRunning); err != nil {
+		return err
+	}
+	for i, member := range members {
+		if err = m.run(pods[i], etcdRestoreLabel, "cat > "+member.snapshotPath(), bytes.NewReader(data),
+			ioutil.Discard); err != nil {
+			return err
+		}
+		fmt.Fprintf(m.Out, "Snapshot uploaded to node %s\n", member.Node)
+	}
+	for i, member := range members {
+		if err = m.run(pods[i], etcdRestoreLabel, "touch "+etcdRestoreTrigger, nil, ioutil.Discard); err != nil {
+			return err
+		}
+		fmt.Fprintf(m.Out, "Restoring etcd member %s on node %s\n", member.Name, member.Node)
+	}
+
+	if err = m.waitForRestorePods(pods, corev1.PodSucceeded); err != nil {
+		return err
+	}
+	if err = m.waitForEtcd(members); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Out, "etcd restored from %s on %d members\n", path, len(members))
+	return nil
+}
+
+// createRestorePod starts the pod restoring the snapshot for member, it
+// waits for etcdRestoreTrigger before touching the node
+func (m *EtcdManager) createRestorePod(member EtcdMember, initialCluster string) (string, error) {
+	restored := member.DataDir + ".airshipctl-restored"
+	previous := member.DataDir + ".airshipctl-previous"
+	stopped := filepath.Join(filepath.Dir(kubeadmManifestsDir), "airshipctl-etcd-restore")
+	manifests := []string{
+		filepath.Join(kubeadmManifestsDir, "etcd.yaml"),
+		filepath.Join(kubeadmManifestsDir, "kube-apiserver.yaml"),
+	}
+	script := strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("while [ ! -f %s ]; do sleep 1; done", etcdRestoreTrigger),
+		"rm -rf " + restored,
+		fmt.Sprintf("ETCDCTL_API=3 etcdctl snapshot restore %s --name %s --initial-cluster %s "+
+			"--initial-advertise-peer-urls %s --data-dir %s", member.snapshotPath(), member.Name, initialCluster,
+			member.PeerURL, restored),
+		"mkdir -p " + stopped,
+		fmt.Sprintf("mv %s %s", strings.Join(manifests, " "), stopped),
+		// Give the kubelet time to stop etcd and the API server
+		"sleep 30",
+		fmt.Sprintf("rm -rf %s && mv %s %s && mv %s %s", previous, member.DataDir, previous, restored, member.DataDir),
+		"rm -f " + member.snapshotPath(),
+		fmt.Sprintf("mv %s/* %s", stopped, kubeadmManifestsDir),
+	}, "\n")
+
+	kubernetesVolume, kubernetesMount := hostPathVolume("etc-kubernetes", filepath.Dir(kubeadmManifestsDir))
+	dataVolume, dataMount := hostPathVolume("etcd-data-parent", filepath.Dir(member.DataDir))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      etcdRestoreLabel + "-" + member.Node,
+			Namespace: etcdNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": etcdRestoreLabel},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:      member.Node,
+			HostNetwork:   true,
+			RestartPolicy: corev1.RestartPolicyNever,
+			Tolerations:   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:         etcdRestoreLabel,
+				Image:        member.Image,
+				Command:      []string{"sh", "-c", script},
+				VolumeMounts: []corev1.VolumeMount{kubernetesMount, dataMount},
+				// The output of a failed restore is reported by Restore
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			}},
+			Volumes: []corev1.Volume{kubernetesVolume, dataVolume},
+		},
+	}
+	if _, err := m.ClientSet.CoreV1().Pods(etcdNamespace).Create(pod); err != nil {
+		return "", err
+	}
+	return pod.Name, nil
+}
+
+// waitForRestorePods blocks until all pods reach phase. The API server of
+// the cluster goes away while etcd is restored, the errors reaching it are
+// ignored until the timeout. Pods waited for to succeed are done once they
+// are not found, the restored cluster doesn't know them.
+func (m *EtcdManager) waitForRestorePods(pods []string, phase corev1.PodPhase) error {
+	deadline := time.Now().Add(m.Timeout)
+	for {
+		var pending []string
+		for _, name := range pods {
+			pod, err := m.ClientSet.CoreV1().Pods(etcdNamespace).Get(name, metav1.GetOptions{})
+			switch {
+			case err != nil && apierrors.IsNotFound(err) && phase == corev1.PodSucceeded:
+				continue
+			case err != nil && client.IsTransient(err):
+				pending = append(pending, name)
+				continue
+			case err != nil:
+				return err
+			case pod.Status.Phase == corev1.PodFailed:
+				return ErrEtcdRestoreFailed{Pod: name, Node: pod.Spec.NodeName, Reason: terminationMessage(pod)}
+			case pod.Status.Phase != phase && pod.Status.Phase != corev1.PodSucceeded:
+				pending = append(pending, name)
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrEtcdRestoreTimeout{Pods: pending, Phase: string(phase)}
+		}
+		time.Sleep(m.PollInterval)
+	}
+}
+
+// waitForEtcd blocks until every member reports itself healthy, the
+// members run with the restored data once they do. The errors of the
+// health checks are ignored until the timeout, etcd and the API server
+// take a while to be back.
+func (m *EtcdManager) waitForEtcd(members []EtcdMember) error {
+	deadline := time.Now().Add(m.Timeout)
+	for {
+		var pending []string
+		for _, member := range members {
+			if err := m.run(member.Pod, etcdContainer, member.etcdctl()+" endpoint health >&2", nil,
+				ioutil.Discard); err != nil {
+				pending = append(pending, member.Pod)
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrEtcdRestoreTimeout{Pods: pending, Phase: "healthy"}
+		}
+		time.Sleep(m.PollInterval)
+	}
+}
+
+// deleteRestorePods deletes the restore pods, those not found are already
+// gone
+func (m *EtcdManager) deleteRestorePods(pods []string) error {
+	for _, pod := range pods {
+		err := m.ClientSet.CoreV1().Pods(etcdNamespace).Delete(pod, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// terminationMessage returns the termination message of the first
+// terminated container of pod
+func terminationMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return strings.TrimSpace(status.State.Terminated.Message)
+		}
+	}
+	return ""
+}
+
+// run runs script with sh in container of pod, the output of the script on
+// stderr is part of the error returned if it fails
+func (m *EtcdManager) run(pod, container, script string, stdin io.Reader, stdout io.Writer) error {
+	stderr := &bytes.Buffer{}
+	err := m.Exec(client.ExecOptions{
+		Namespace: etcdNamespace,
+		Pod:       pod,
+		Container: container,
+		Command:   []string{"sh", "-c", script},
+		Stdin:     stdin,
+		Stdout:    stdout,
+		Stderr:    stderr,
+	})
+	if err == nil {
+		return nil
+	}
+	reason := err.Error()
+	if output := strings.TrimSpace(stderr.String()); output != "" {
+		reason += ": " + output
+	}
+	return ErrEtcdCommandFailed{Pod: pod, Reason: reason}
+}
+
+// hostPathVolume returns a volume of the host directory path, and its mount
+// at the same path
+func hostPathVolume(name, path string) (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name:         name,
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}},
+	}
+	return volume, corev1.VolumeMount{Name: name, MountPath: path}
+}
+
+// etcdMember reads the flags of the etcd container of pod
+func etcdMember(pod corev1.Pod, container corev1.Container) EtcdMember {
+	flags := map[string]string{}
+	for _, arg := range append(container.Command, container.Args...) {
+		if i := strings.Index(arg, "="); strings.HasPrefix(arg, "--") && i > 0 {
+			flags[arg[2:i]] = arg[i+1:]
+		}
+	}
+	member := EtcdMember{
+		Pod:           pod.Name,
+		Node:          pod.Spec.NodeName,
+		Image:         container.Image,
+		Name:          flags["name"],
+		PeerURL:       strings.Split(flags["initial-advertise-peer-urls"], ",")[0],
+		ClientURL:     strings.Split(flags["listen-client-urls"], ",")[0],
+		DataDir:       flags["data-dir"],
+		CertFile:      flags["cert-file"],
+		KeyFile:       flags["key-file"],
+		TrustedCAFile: flags["trusted-ca-file"],
+	}
+	if member.ClientURL == "" {
+		member.ClientURL = "https://127.0.0.1:2379"
+	}
+	if member.DataDir == "" {
+		member.DataDir = "/var/lib/etcd"
+	}
+	return member
+}
+
+// etcdctl returns the etcdctl command reaching member with the certificates
+// of its server, kubeadm issues them for client authentication too
+func (member EtcdMember) etcdctl() string {
+	return fmt.Sprintf("ETCDCTL_API=3 etcdctl --endpoints=%s --cacert=%s --cert=%s --key=%s",
+		member.ClientURL, member.TrustedCAFile, member.CertFile, member.KeyFile)
+}
+
+// snapshotPath is where a snapshot is uploaded to on the node of member
+func (member EtcdMember) snapshotPath() string {
+	return member.DataDir + ".airshipctl-snapshot.db"
+}
@@ -0,0 +1,330 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+func etcdPod(node string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-" + node,
+			Namespace: "kube-system",
+			Labels:    map[string]string{"component": "etcd", "tier": "control-plane"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name:  "etcd",
+				Image: "k8s.gcr.io/etcd:3.4.3-0",
+				Command: []string{
+					"etcd",
+					"--cert-file=/etc/kubernetes/pki/etcd/server.crt",
+					"--data-dir=/var/lib/etcd",
+					"--initial-advertise-peer-urls=https://10.0.0." + node[len(node)-1:] + ":2380",
+					"--key-file=/etc/kubernetes/pki/etcd/server.key",
+					"--listen-client-urls=https://127.0.0.1:2379,https://10.0.0." + node[len(node)-1:] + ":2379",
+					"--name=" + node,
+					"--trusted-ca-file=/etc/kubernetes/pki/etcd/ca.crt",
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// fakeExec records the commands run in pods and answers snapshot saves
+// with snapshot
+type fakeExec struct {
+	snapshot []byte
+	err      error
+	commands []string
+	uploaded map[string][]byte
+}
+
+func (e *fakeExec) exec(opts client.ExecOptions) error {
+	script := opts.Command[len(opts.Command)-1]
+	e.commands = append(e.commands, opts.Pod+": "+script)
+	if e.err != nil {
+		return e.err
+	}
+	if strings.Contains(script, "snapshot save") {
+		_, err := opts.Stdout.Write(e.snapshot)
+		return err
+	}
+	if opts.Stdin != nil {
+		data, err := ioutil.ReadAll(opts.Stdin)
+		if err != nil {
+			return err
+		}
+		e.uploaded[opts.Pod] = data
+	}
+	return nil
+}
+
+func newTestEtcdManager(exec *fakeExec, objects ...runtime.Object) (*cluster.EtcdManager, *kubernetesfake.Clientset,
+	*bytes.Buffer) {
+	out := &bytes.Buffer{}
+	clientSet := kubernetesfake.NewSimpleClientset(objects...)
+	return &cluster.EtcdManager{
+		ClientSet:    clientSet,
+		Exec:         exec.exec,
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Out:          out,
+	}, clientSet, out
+}
+
+func TestEtcdMembers(t *testing.T) {
+	manager, _, _ := newTestEtcdManager(&fakeExec{},
+		etcdPod("node2", corev1.PodRunning), etcdPod("node1", corev1.PodRunning), etcdPod("node3", corev1.PodPending))
+
+	members, err := manager.Members()
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.EtcdMember{
+		{
+			Pod:           "etcd-node1",
+			Node:          "node1",
+			Image:         "k8s.gcr.io/etcd:3.4.3-0",
+			Name:          "node1",
+			PeerURL:       "https://10.0.0.1:2380",
+			ClientURL:     "https://127.0.0.1:2379",
+			DataDir:       "/var/lib/etcd",
+			CertFile:      "/etc/kubernetes/pki/etcd/server.crt",
+			KeyFile:       "/etc/kubernetes/pki/etcd/server.key",
+			TrustedCAFile: "/etc/kubernetes/pki/etcd/ca.crt",
+		},
+		{
+			Pod:           "etcd-node2",
+			Node:          "node2",
+			Image:         "k8s.gcr.io/etcd:3.4.3-0",
+			Name:          "node2",
+			PeerURL:       "https://10.0.0.2:2380",
+			ClientURL:     "https://127.0.0.1:2379",
+			DataDir:       "/var/lib/etcd",
+			CertFile:      "/etc/kubernetes/pki/etcd/server.crt",
+			KeyFile:       "/etc/kubernetes/pki/etcd/server.key",
+			TrustedCAFile: "/etc/kubernetes/pki/etcd/ca.crt",
+		},
+	}, members)
+
+	manager, _, _ = newTestEtcdManager(&fakeExec{}, etcdPod("node3", corev1.PodPending))
+	_, err = manager.Members()
+	assert.Equal(t, cluster.ErrNoEtcdMembers{Namespace: "kube-system"}, err)
+}
+
+func TestEtcdSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airship-etcd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	exec := &fakeExec{snapshot: []byte("snapshot")}
+	manager, _, out := newTestEtcdManager(exec, etcdPod("node1", corev1.PodRunning))
+
+	path := filepath.Join(dir, "snapshots", "target-cluster.db")
+	require.NoError(t, manager.Snapshot(path, ""))
+	assert.Equal(t, []string{"etcd-node1: ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 " +
+		"--cacert=/etc/kubernetes/pki/etcd/ca.crt --cert=/etc/kubernetes/pki/etcd/server.crt " +
+		"--key=/etc/kubernetes/pki/etcd/server.key snapshot save /tmp/airshipctl-snapshot.db >&2 && " +
+		"cat /tmp/airshipctl-snapshot.db && rm -f /tmp/airshipctl-snapshot.db"}, exec.commands)
+	assert.Equal(t, "Snapshot of etcd member node1 saved to "+path+" (8 bytes)\n", out.String())
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot", string(data))
+
+	require.NoError(t, manager.Snapshot(path, "passphrase"))
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	decrypted, err := secret.Decrypt(data, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot", string(decrypted))
+
+	exec.snapshot = nil
+	assert.Equal(t, cluster.ErrEtcdCommandFailed{Pod: "etcd-node1", Reason: "the snapshot is empty"},
+		manager.Snapshot(path, ""))
+
+	exec.err = errors.New("command terminated with exit code 1")
+	assert.Equal(t, cluster.ErrEtcdCommandFailed{Pod: "etcd-node1", Reason: "command terminated with exit code 1"},
+		manager.Snapshot(path, ""))
+}
+
+func TestEtcdRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airship-etcd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	plain := filepath.Join(dir, "plain.db")
+	require.NoError(t, ioutil.WriteFile(plain, []byte("snapshot"), 0600))
+	encryptedData, err := secret.Encrypt([]byte("snapshot"), "passphrase")
+	require.NoError(t, err)
+	encrypted := filepath.Join(dir, "encrypted.db")
+	require.NoError(t, ioutil.WriteFile(encrypted, encryptedData, 0600))
+
+	for _, path := range []string{plain, encrypted} {
+		exec := &fakeExec{uploaded: map[string][]byte{}}
+		manager, clientSet, out := newTestEtcdManager(exec,
+			etcdPod("node1", corev1.PodRunning), etcdPod("node2", corev1.PodRunning))
+		scripts := map[string]string{}
+		clientSet.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+			scripts[pod.Spec.NodeName] = pod.Spec.Containers[0].Command[2]
+			pod.Status.Phase = corev1.PodRunning
+			return false, nil, nil
+		})
+		// The restore pods are not found once triggered, the restored cluster
+		// doesn't know them
+		manager.Exec = func(opts client.ExecOptions) error {
+			if err := exec.exec(opts); err != nil {
+				return err
+			}
+			if strings.HasPrefix(opts.Command[2], "touch ") {
+				return clientSet.CoreV1().Pods("kube-system").Delete(opts.Pod, &metav1.DeleteOptions{})
+			}
+			return nil
+		}
+
+		require.NoError(t, manager.Restore(path, "passphrase"))
+		assert.Equal(t, ""+
+			"Snapshot uploaded to node node1\n"+
+			"Snapshot uploaded to node node2\n"+
+			"Restoring etcd member node1 on node node1\n"+
+			"Restoring etcd member node2 on node node2\n"+
+			"etcd restored from "+path+" on 2 members\n", out.String())
+		assert.Equal(t, map[string][]byte{
+			"airshipctl-etcd-restore-node1": []byte("snapshot"),
+			"airshipctl-etcd-restore-node2": []byte("snapshot"),
+		}, exec.uploaded)
+		assert.Contains(t, scripts["node2"], "\nETCDCTL_API=3 etcdctl snapshot restore "+
+			"/var/lib/etcd.airshipctl-snapshot.db --name node2 "+
+			"--initial-cluster node1=https://10.0.0.1:2380,node2=https://10.0.0.2:2380 "+
+			"--initial-advertise-peer-urls https://10.0.0.2:2380 --data-dir /var/lib/etcd.airshipctl-restored\n")
+		// The restore is confirmed by the health of the etcd members
+		assert.Contains(t, exec.commands, "etcd-node2: ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 "+
+			"--cacert=/etc/kubernetes/pki/etcd/ca.crt --cert=/etc/kubernetes/pki/etcd/server.crt "+
+			"--key=/etc/kubernetes/pki/etcd/server.key endpoint health >&2")
+
+		// Only the etcd pods are left
+		pods, err := clientSet.CoreV1().Pods("kube-system").List(metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, pods.Items, 2)
+	}
+}
+
+func TestEtcdRestoreErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airship-etcd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	encryptedData, err := secret.Encrypt([]byte("snapshot"), "passphrase")
+	require.NoError(t, err)
+	encrypted := filepath.Join(dir, "encrypted.db")
+	require.NoError(t, ioutil.WriteFile(encrypted, encryptedData, 0600))
+
+	manager, _, _ := newTestEtcdManager(&fakeExec{uploaded: map[string][]byte{}},
+		etcdPod("node1", corev1.PodRunning))
+	assert.Equal(t, cluster.ErrEtcdSnapshotPassphraseRequired{Path: encrypted}, manager.Restore(encrypted, ""))
+	assert.Equal(t, secret.ErrDecryptionFailed{Reason: "wrong passphrase or corrupted data"},
+		manager.Restore(encrypted, "wrong"))
+
+	tests := []struct {
+		name        string
+		phase       corev1.PodPhase
+		execErr     error
+		expectedErr error
+	}{
+		{
+			name:        "never-scheduled",
+			expectedErr: cluster.ErrEtcdRestoreTimeout{Pods: []string{"airshipctl-etcd-restore-node1"}, Phase: "Running"},
+		},
+		{
+			name:  "failed",
+			phase: corev1.PodFailed,
+			expectedErr: cluster.ErrEtcdRestoreFailed{
+				Pod:    "airshipctl-etcd-restore-node1",
+				Node:   "node1",
+				Reason: "Error: expected sha256",
+			},
+		},
+		{
+			name:    "upload-failed",
+			phase:   corev1.PodRunning,
+			execErr: errors.New("command terminated with exit code 1"),
+			expectedErr: cluster.ErrEtcdCommandFailed{
+				Pod:    "airshipctl-etcd-restore-node1",
+				Reason: "command terminated with exit code 1",
+			},
+		},
+		{
+			name:        "etcd-unhealthy",
+			phase:       corev1.PodSucceeded,
+			expectedErr: cluster.ErrEtcdRestoreTimeout{Pods: []string{"etcd-node1"}, Phase: "healthy"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExec{uploaded: map[string][]byte{}, err: tt.execErr}
+			manager, clientSet, _ := newTestEtcdManager(exec, etcdPod("node1", corev1.PodRunning))
+			clientSet.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				pod.Status.Phase = tt.phase
+				if tt.phase == corev1.PodFailed {
+					pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  "Error: expected sha256\n",
+						}},
+					}}
+				}
+				return false, nil, nil
+			})
+			if tt.phase == corev1.PodSucceeded {
+				manager.Exec = func(opts client.ExecOptions) error {
+					if strings.Contains(opts.Command[2], "endpoint health") {
+						return errors.New("context deadline exceeded")
+					}
+					return exec.exec(opts)
+				}
+			}
+
+			assert.Equal(t, tt.expectedErr, manager.Restore(encrypted, "passphrase"))
+
+			// The restore pods are deleted whatever the error
+			pods, err := clientSet.CoreV1().Pods("kube-system").List(metav1.ListOptions{})
+			require.NoError(t, err)
+			require.Len(t, pods.Items, 1)
+			assert.Equal(t, "etcd-node1", pods.Items[0].Name)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+// kubeconfigSecretKey is the key of the kubeconfig in the Secret Cluster API
+// generates for a cluster
+const kubeconfigSecretKey = "value"
+
+// KubeconfigSecretName returns the name of the Secret Cluster API stores the
+// admin kubeconfig of a cluster in
+func KubeconfigSecretName(clusterName string) string {
+	return clusterName + "-kubeconfig"
+}
+
+// GetKubeconfig returns the admin kubeconfig Cluster API generated for the
+// cluster called clusterName in namespace, read from its Secret in the
+// management cluster
+func GetKubeconfig(c client.Interface, namespace, clusterName string) ([]byte, error) {
+	name := KubeconfigSecretName(clusterName)
+	secret, err := c.ClientSet().CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrKubeconfigNotFound{Cluster: clusterName, Namespace: namespace}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, ok := secret.Data[kubeconfigSecretKey]
+	if !ok || len(kubeconfig) == 0 {
+		return nil, ErrInvalidKubeconfigSecret{Secret: name, Namespace: namespace, Key: kubeconfigSecretKey}
+	}
+	return kubeconfig, nil
+}
@@ -0,0 +1,58 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func kubeconfigSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "target-infra",
+		},
+		Data: data,
+	}
+}
+
+func TestGetKubeconfig(t *testing.T) {
+	c := fake.NewClient(fake.WithTypedObjects(
+		kubeconfigSecret("target-cluster-kubeconfig", map[string][]byte{"value": []byte("apiVersion: v1\n")}),
+		kubeconfigSecret("broken-kubeconfig", map[string][]byte{"kubeconfig": []byte("apiVersion: v1\n")}),
+	))
+
+	kubeconfig, err := cluster.GetKubeconfig(c, "target-infra", "target-cluster")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\n", string(kubeconfig))
+
+	_, err = cluster.GetKubeconfig(c, "other", "target-cluster")
+	assert.Equal(t, cluster.ErrKubeconfigNotFound{Cluster: "target-cluster", Namespace: "other"}, err)
+
+	_, err = cluster.GetKubeconfig(c, "target-infra", "broken")
+	assert.Equal(t, cluster.ErrInvalidKubeconfigSecret{
+		Secret:    "broken-kubeconfig",
+		Namespace: "target-infra",
+		Key:       "value",
+	}, err)
+}
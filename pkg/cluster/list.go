@@ -0,0 +1,144 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// ClusterList is the Clusters of the management clusters of several
+// contexts
+type ClusterList struct {
+	Clusters []ListedCluster `json:"clusters"`
+	// Unreachable are the contexts whose management cluster couldn't be
+	// queried
+	Unreachable []UnreachableContext `json:"unreachable,omitempty"`
+}
+
+// ListedCluster is a Cluster along with the context of its management
+// cluster
+type ListedCluster struct {
+	Context   string `json:"context"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	// Version is the Kubernetes version of the control plane, empty if it
+	// isn't a KubeadmControlPlane
+	Version    string `json:"version,omitempty"`
+	Machines   int    `json:"machines"`
+	ReadyNodes int    `json:"readyNodes"`
+}
+
+// UnreachableContext is a context whose management cluster couldn't be
+// queried, with the reason
+type UnreachableContext struct {
+	Context string `json:"context"`
+	Error   string `json:"error"`
+}
+
+// ListClusters queries the Clusters of all namespaces of the management
+// cluster of each of contexts, in order, with a client made by newClient.
+// Contexts which can't be queried are listed as unreachable, the others are
+// listed regardless.
+func ListClusters(contexts []string,
+	newClient func(context string) (client.ResourceAccessor, error)) *ClusterList {
+	list := &ClusterList{Clusters: []ListedCluster{}}
+	for _, context := range contexts {
+		status, err := contextStatus(context, newClient)
+		if err != nil {
+			list.Unreachable = append(list.Unreachable, UnreachableContext{Context: context, Error: err.Error()})
+			continue
+		}
+		for _, c := range status.Clusters {
+			listed := ListedCluster{
+				Context:    context,
+				Namespace:  c.Namespace,
+				Name:       c.Name,
+				Phase:      c.Phase,
+				Machines:   c.Machines,
+				ReadyNodes: c.ReadyNodes,
+			}
+			if c.ControlPlane != nil {
+				listed.Version = c.ControlPlane.Version
+			}
+			list.Clusters = append(list.Clusters, listed)
+		}
+	}
+	return list
+}
+
+func contextStatus(context string,
+	newClient func(context string) (client.ResourceAccessor, error)) (*ProvisioningStatus, error) {
+	c, err := newClient(context)
+	if err != nil {
+		return nil, err
+	}
+	return GetProvisioningStatus(c, "")
+}
+
+// Err returns an ErrContextsUnreachable if contexts couldn't be queried
+func (l *ClusterList) Err() error {
+	if len(l.Unreachable) == 0 {
+		return nil
+	}
+	err := ErrContextsUnreachable{}
+	for _, u := range l.Unreachable {
+		err.Contexts = append(err.Contexts, u.Context)
+	}
+	return err
+}
+
+// Print writes the list to w in the given format, see OutputTable and
+// OutputJSON
+func (l *ClusterList) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return l.printTable(w)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(l)
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (l *ClusterList) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "CLUSTER\tCONTEXT\tNAMESPACE\tPHASE\tVERSION\tNODES")
+	for _, c := range l.Clusters {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d/%d ready\n", c.Name, c.Context, c.Namespace,
+			valueOrDash(c.Phase), valueOrDash(c.Version), c.ReadyNodes, c.Machines)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(l.Unreachable) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw = util.NewTabWriter(w)
+	fmt.Fprintln(tw, "UNREACHABLE CONTEXT\tERROR")
+	for _, u := range l.Unreachable {
+		fmt.Fprintf(tw, "%s\t%s\n", u.Context, u.Error)
+	}
+	return tw.Flush()
+}
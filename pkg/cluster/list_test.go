@@ -0,0 +1,85 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func TestListClusters(t *testing.T) {
+	objects := provisioningObjects()
+	for _, obj := range objects {
+		if u := obj.(*unstructured.Unstructured); u.GetKind() == cluster.KubeadmControlPlaneGVK.Kind {
+			require.NoError(t, unstructured.SetNestedField(u.Object, "v1.18.3", "spec", "version"))
+		}
+	}
+	site := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK,
+			cluster.KubeadmControlPlaneGVK, cluster.BareMetalHostGVK)),
+		fake.WithDynamicObjects(objects...))
+
+	list := cluster.ListClusters([]string{"site-a", "site-b"}, func(context string) (client.ResourceAccessor, error) {
+		if context == "site-b" {
+			return nil, errors.New("connection refused")
+		}
+		return site, nil
+	})
+	assert.Equal(t, &cluster.ClusterList{
+		Clusters: []cluster.ListedCluster{
+			{
+				Context:    "site-a",
+				Namespace:  "target-infra",
+				Name:       "target-cluster",
+				Phase:      "Provisioned",
+				Version:    "v1.18.3",
+				Machines:   2,
+				ReadyNodes: 1,
+			},
+		},
+		Unreachable: []cluster.UnreachableContext{{Context: "site-b", Error: "connection refused"}},
+	}, list)
+	assert.Equal(t, cluster.ErrContextsUnreachable{Contexts: []string{"site-b"}}, list.Err())
+
+	out := &bytes.Buffer{}
+	require.NoError(t, list.Print(out, cluster.OutputTable))
+	assert.Equal(t,
+		"CLUSTER          CONTEXT   NAMESPACE      PHASE         VERSION   NODES\n"+
+			"target-cluster   site-a    target-infra   Provisioned   v1.18.3   1/2 ready\n"+
+			"\n"+
+			"UNREACHABLE CONTEXT   ERROR\n"+
+			"site-b                connection refused\n",
+		out.String())
+
+	assert.Equal(t, cluster.ErrInvalidOutputFormat{Format: "yaml"}, list.Print(out, "yaml"))
+}
+
+func TestListClustersEmpty(t *testing.T) {
+	list := cluster.ListClusters(nil, nil)
+	assert.NoError(t, list.Err())
+
+	out := &bytes.Buffer{}
+	require.NoError(t, list.Print(out, cluster.OutputJSON))
+	assert.Equal(t, "{\n    \"clusters\": []\n}\n", out.String())
+}
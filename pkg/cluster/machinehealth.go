@@ -0,0 +1,363 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// MachineHealthCheckGVK is the kind of the resources declaring when the
+// Machines of a cluster are remediated
+var MachineHealthCheckGVK = schema.GroupVersionKind{
+	Group:   "cluster.x-k8s.io",
+	Version: "v1alpha3",
+	Kind:    "MachineHealthCheck",
+}
+
+// Reasons of the events the MachineHealthCheck controller records while
+// remediating Machines
+const (
+	ReasonDetectedUnhealthy     = "DetectedUnhealthy"
+	ReasonMachineDeleted        = "MachineDeleted"
+	ReasonMachineDeletionFailed = "MachineDeletionFailed"
+	ReasonSkippedControlPlane   = "SkippedControlPlane"
+	ReasonRemediationRestricted = "RemediationRestricted"
+)
+
+// defaultMaxUnhealthy lets a MachineHealthCheck remediate all its Machines
+const defaultMaxUnhealthy = "100%"
+
+var remediationReasons = map[string]bool{
+	ReasonDetectedUnhealthy:     true,
+	ReasonMachineDeleted:        true,
+	ReasonMachineDeletionFailed: true,
+	ReasonSkippedControlPlane:   true,
+	ReasonRemediationRestricted: true,
+}
+
+// MachineHealthReport summarizes the MachineHealthChecks of a management
+// cluster, the Machines that are unhealthy and the remediations recorded
+// for them
+type MachineHealthReport struct {
+	HealthChecks []HealthCheckStatus `json:"healthChecks"`
+	Unhealthy    []UnhealthyMachine  `json:"unhealthyMachines"`
+	// Remediations are the most recent first
+	Remediations []RemediationEvent `json:"remediations"`
+}
+
+// HealthCheckStatus is the status of a MachineHealthCheck
+type HealthCheckStatus struct {
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	Cluster          string `json:"cluster"`
+	ExpectedMachines int64  `json:"expectedMachines"`
+	CurrentHealthy   int64  `json:"currentHealthy"`
+	MaxUnhealthy     string `json:"maxUnhealthy"`
+	// RemediationAllowed is false when more Machines are unhealthy than
+	// MaxUnhealthy, in which case none of them are remediated
+	RemediationAllowed bool `json:"remediationAllowed"`
+}
+
+// UnhealthyMachine is a Machine that failed, is being deleted or was found
+// unhealthy by a MachineHealthCheck
+type UnhealthyMachine struct {
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	Machine   string `json:"machine"`
+	// HealthChecks are the MachineHealthChecks selecting the Machine
+	HealthChecks []string `json:"healthChecks,omitempty"`
+	Phase        string   `json:"phase,omitempty"`
+	Node         string   `json:"node,omitempty"`
+	Deleting     bool     `json:"deleting"`
+	Reason       string   `json:"reason,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	Host         string   `json:"host,omitempty"`
+	HostState    string   `json:"hostState,omitempty"`
+	HostError    string   `json:"hostError,omitempty"`
+}
+
+// RemediationEvent is an event recorded by the MachineHealthCheck
+// controller for a Machine or a MachineHealthCheck
+type RemediationEvent struct {
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// GetMachineHealth reads the MachineHealthChecks, Machines, BareMetalHosts
+// and remediation events of namespace, or of all namespaces if it is
+// empty. MachineHealthChecks and BareMetalHosts are skipped if their kind
+// isn't known to the cluster.
+func GetMachineHealth(c client.Interface, namespace string) (*MachineHealthReport, error) {
+	healthChecks, err := listResources(c, MachineHealthCheckGVK, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	machines, err := listResources(c, MachineGVK, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := listResources(c, BareMetalHostGVK, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	events, err := c.ClientSet().CoreV1().Events(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MachineHealthReport{
+		HealthChecks: []HealthCheckStatus{},
+		Unhealthy:    []UnhealthyMachine{},
+		Remediations: []RemediationEvent{},
+	}
+	for _, mhc := range healthChecks {
+		report.HealthChecks = append(report.HealthChecks, healthCheckStatus(mhc))
+	}
+
+	// The latest remediation event of every Machine explains why it is
+	// unhealthy
+	latest := map[string]RemediationEvent{}
+	for _, event := range events.Items {
+		kind := event.InvolvedObject.Kind
+		if !remediationReasons[event.Reason] || (kind != MachineGVK.Kind && kind != MachineHealthCheckGVK.Kind) {
+			continue
+		}
+		remediation := RemediationEvent{
+			Namespace: event.InvolvedObject.Namespace,
+			Kind:      kind,
+			Name:      event.InvolvedObject.Name,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Count:     event.Count,
+			LastSeen:  eventTime(event),
+		}
+		report.Remediations = append(report.Remediations, remediation)
+		key := remediation.Namespace + "/" + remediation.Name
+		if kind == MachineGVK.Kind && !remediation.LastSeen.Before(latest[key].LastSeen) {
+			latest[key] = remediation
+		}
+	}
+	sort.SliceStable(report.Remediations, func(i, j int) bool {
+		return report.Remediations[i].LastSeen.After(report.Remediations[j].LastSeen)
+	})
+
+	consumers := make(map[string]unstructured.Unstructured, len(hosts))
+	for _, host := range hosts {
+		kind, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "kind")
+		name, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "name")
+		if name != "" {
+			consumers[refKey(host.GetNamespace(), kind, name)] = host
+		}
+	}
+
+	for _, machine := range machines {
+		unhealthy, ok := unhealthyMachine(machine, latest[machine.GetNamespace()+"/"+machine.GetName()])
+		if !ok {
+			continue
+		}
+		for _, mhc := range healthChecks {
+			if selects(mhc, machine) {
+				unhealthy.HealthChecks = append(unhealthy.HealthChecks, mhc.GetName())
+			}
+		}
+		kind, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "kind")
+		name, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "name")
+		if host, found := consumers[refKey(machine.GetNamespace(), kind, name)]; found {
+			unhealthy.Host = host.GetName()
+			unhealthy.HostState, _, _ = unstructured.NestedString(host.Object, "status", "provisioning", "state")
+			unhealthy.HostError, _, _ = unstructured.NestedString(host.Object, "status", "errorMessage")
+		}
+		report.Unhealthy = append(report.Unhealthy, unhealthy)
+	}
+	return report, nil
+}
+
+// Print writes the report to w in the given format, see OutputTable and
+// OutputJSON
+func (r *MachineHealthReport) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return r.printTable(w)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(r)
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (r *MachineHealthReport) printTable(w io.Writer) error {
+	if len(r.HealthChecks) == 0 {
+		fmt.Fprintln(w, "No MachineHealthChecks found.")
+	} else {
+		tw := util.NewTabWriter(w)
+		fmt.Fprintln(tw, "NAMESPACE\tHEALTH CHECK\tCLUSTER\tEXPECTED\tHEALTHY\tMAX UNHEALTHY\tREMEDIATION")
+		for _, hc := range r.HealthChecks {
+			remediation := "allowed"
+			if !hc.RemediationAllowed {
+				remediation = "restricted"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", hc.Namespace, hc.Name, hc.Cluster,
+				hc.ExpectedMachines, hc.CurrentHealthy, hc.MaxUnhealthy, remediation)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(r.Unhealthy) == 0 {
+		fmt.Fprintln(w, "No unhealthy machines.")
+	} else {
+		tw := util.NewTabWriter(w)
+		fmt.Fprintln(tw, "NAMESPACE\tCLUSTER\tMACHINE\tHEALTH CHECK\tPHASE\tNODE\tHOST\tHOST STATE\tREASON\tMESSAGE")
+		for _, m := range r.Unhealthy {
+			phase := valueOrDash(m.Phase)
+			if m.Deleting {
+				phase += " (deleting)"
+			}
+			message := m.Message
+			if m.HostError != "" {
+				message = strings.TrimPrefix(message+"; host: "+m.HostError, "; ")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", m.Namespace, valueOrDash(m.Cluster),
+				m.Machine, valueOrDash(strings.Join(m.HealthChecks, ",")), phase, valueOrDash(m.Node),
+				valueOrDash(m.Host), valueOrDash(m.HostState), valueOrDash(m.Reason), valueOrDash(message))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(r.Remediations) == 0 {
+		fmt.Fprintln(w, "No remediations.")
+		return nil
+	}
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "LAST SEEN\tNAMESPACE\tOBJECT\tREASON\tCOUNT\tMESSAGE")
+	for _, e := range r.Remediations {
+		fmt.Fprintf(tw, "%s\t%s\t%s/%s\t%s\t%d\t%s\n", e.LastSeen.UTC().Format(time.RFC3339), e.Namespace,
+			e.Kind, e.Name, e.Reason, e.Count, e.Message)
+	}
+	return tw.Flush()
+}
+
+func healthCheckStatus(mhc unstructured.Unstructured) HealthCheckStatus {
+	hc := HealthCheckStatus{Namespace: mhc.GetNamespace(), Name: mhc.GetName(), MaxUnhealthy: defaultMaxUnhealthy}
+	hc.Cluster, _, _ = unstructured.NestedString(mhc.Object, "spec", "clusterName")
+	hc.ExpectedMachines, _, _ = unstructured.NestedInt64(mhc.Object, "status", "expectedMachines")
+	hc.CurrentHealthy, _, _ = unstructured.NestedInt64(mhc.Object, "status", "currentHealthy")
+
+	// Same as the MachineHealthCheck controller, remediation stops when
+	// more Machines are unhealthy than MaxUnhealthy
+	hc.RemediationAllowed = true
+	maxUnhealthy, found, _ := unstructured.NestedFieldNoCopy(mhc.Object, "spec", "maxUnhealthy")
+	if !found {
+		return hc
+	}
+	var value intstr.IntOrString
+	switch v := maxUnhealthy.(type) {
+	case int64:
+		value = intstr.FromInt(int(v))
+	case string:
+		value = intstr.FromString(v)
+	default:
+		return hc
+	}
+	hc.MaxUnhealthy = value.String()
+	allowed, err := intstr.GetValueFromIntOrPercent(&value, int(hc.ExpectedMachines), false)
+	hc.RemediationAllowed = err == nil && hc.ExpectedMachines-hc.CurrentHealthy <= int64(allowed)
+	return hc
+}
+
+// unhealthyMachine reports machine if it failed, is being deleted, or its
+// latest remediation event isn't the one of its deletion
+func unhealthyMachine(machine unstructured.Unstructured, latest RemediationEvent) (UnhealthyMachine, bool) {
+	m := UnhealthyMachine{
+		Namespace: machine.GetNamespace(),
+		Machine:   machine.GetName(),
+		Deleting:  machine.GetDeletionTimestamp() != nil,
+	}
+	m.Cluster, _, _ = unstructured.NestedString(machine.Object, "spec", "clusterName")
+	m.Phase, _, _ = unstructured.NestedString(machine.Object, "status", "phase")
+	m.Node, _, _ = unstructured.NestedString(machine.Object, "status", "nodeRef", "name")
+	m.Reason, _, _ = unstructured.NestedString(machine.Object, "status", "failureReason")
+	m.Message, _, _ = unstructured.NestedString(machine.Object, "status", "failureMessage")
+
+	if m.Reason == "" && latest.Reason != "" && latest.Reason != ReasonMachineDeleted {
+		m.Reason, m.Message = latest.Reason, latest.Message
+	}
+	return m, m.Reason != "" || m.Message != "" || m.Deleting
+}
+
+// selects reports whether mhc checks the health of machine
+func selects(mhc unstructured.Unstructured, machine unstructured.Unstructured) bool {
+	clusterName, _, _ := unstructured.NestedString(mhc.Object, "spec", "clusterName")
+	machineCluster, _, _ := unstructured.NestedString(machine.Object, "spec", "clusterName")
+	if mhc.GetNamespace() != machine.GetNamespace() || clusterName != machineCluster {
+		return false
+	}
+
+	raw, found, _ := unstructured.NestedMap(mhc.Object, "spec", "selector")
+	if !found {
+		return false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err = json.Unmarshal(data, labelSelector); err != nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(machine.GetLabels()))
+}
+
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func machineHealthCheck(maxUnhealthy interface{}, expected, healthy int64) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"clusterName": "target-cluster",
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{"cluster.x-k8s.io/deployment-name": "workers"},
+		},
+	}
+	if maxUnhealthy != nil {
+		spec["maxUnhealthy"] = maxUnhealthy
+	}
+	return provisioningObject(cluster.MachineHealthCheckGVK, "workers-mhc", spec,
+		map[string]interface{}{"expectedMachines": expected, "currentHealthy": healthy}, nil)
+}
+
+func remediationEvent(kind, name, reason, message string, count int32, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "." + reason,
+			Namespace: "target-infra",
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "target-infra", Name: name},
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func TestGetMachineHealth(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	failed := workerMachine("workers-2", "Failed", "")
+	require.NoError(t, unstructured.SetNestedField(failed.Object, "CreateError", "status", "failureReason"))
+	require.NoError(t, unstructured.SetNestedField(failed.Object, "host provisioning failed",
+		"status", "failureMessage"))
+	host := workerHost("node03", "workers-2", "provisioning")
+	require.NoError(t, unstructured.SetNestedField(host.Object, "image checksum mismatch",
+		"status", "errorMessage"))
+
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.MachineHealthCheckGVK, cluster.MachineGVK,
+			cluster.BareMetalHostGVK)),
+		fake.WithDynamicObjects(
+			machineHealthCheck("40%", 3, 1),
+			workerMachine("workers-1", "Running", "node02"),
+			failed,
+			workerMachine("workers-3", "Running", "node04"),
+			workerHost("node02", "workers-1", "provisioned"),
+			host,
+		),
+		fake.WithTypedObjects(
+			remediationEvent("Machine", "workers-0", cluster.ReasonMachineDeleted,
+				"Machine target-infra/workers-mhc/workers-0/ has been remediated by being deleted", 1,
+				now.Add(-time.Hour)),
+			remediationEvent("Machine", "workers-3", cluster.ReasonDetectedUnhealthy,
+				"Machine target-infra/workers-mhc/workers-3/node04 has unhealthy node node04", 4,
+				now.Add(-time.Minute)),
+			remediationEvent("MachineHealthCheck", "workers-mhc", cluster.ReasonRemediationRestricted,
+				"Remediation restricted due to exceeded number of unhealthy machines", 2, now),
+			remediationEvent("Pod", "web", "BackOff", "Back-off restarting failed container", 1, now),
+		))
+
+	report, err := cluster.GetMachineHealth(c, "target-infra")
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.HealthCheckStatus{{
+		Namespace:          "target-infra",
+		Name:               "workers-mhc",
+		Cluster:            "target-cluster",
+		ExpectedMachines:   3,
+		CurrentHealthy:     1,
+		MaxUnhealthy:       "40%",
+		RemediationAllowed: false,
+	}}, report.HealthChecks)
+	assert.Equal(t, []cluster.UnhealthyMachine{
+		{
+			Namespace:    "target-infra",
+			Cluster:      "target-cluster",
+			Machine:      "workers-2",
+			HealthChecks: []string{"workers-mhc"},
+			Phase:        "Failed",
+			Reason:       "CreateError",
+			Message:      "host provisioning failed",
+			Host:         "node03",
+			HostState:    "provisioning",
+			HostError:    "image checksum mismatch",
+		},
+		{
+			Namespace:    "target-infra",
+			Cluster:      "target-cluster",
+			Machine:      "workers-3",
+			HealthChecks: []string{"workers-mhc"},
+			Phase:        "Running",
+			Node:         "node04",
+			Reason:       cluster.ReasonDetectedUnhealthy,
+			Message:      "Machine target-infra/workers-mhc/workers-3/node04 has unhealthy node node04",
+		},
+	}, report.Unhealthy)
+	require.Len(t, report.Remediations, 3)
+	assert.Equal(t, cluster.ReasonRemediationRestricted, report.Remediations[0].Reason)
+	assert.Equal(t, int32(4), report.Remediations[1].Count)
+	assert.Equal(t, "workers-0", report.Remediations[2].Name)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, report.Print(out, cluster.OutputTable))
+	assert.Contains(t, out.String(), "target-infra   workers-mhc    target-cluster   3          1         40%")
+	assert.Contains(t, out.String(), "host provisioning failed; host: image checksum mismatch")
+	assert.Contains(t, out.String(), "2020-06-01T12:00:00Z   target-infra   MachineHealthCheck/workers-mhc")
+}
+
+func TestGetMachineHealthWithoutHealthChecks(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.MachineGVK)),
+		fake.WithDynamicObjects(workerMachine("workers-1", "Running", "node02")))
+
+	report, err := cluster.GetMachineHealth(c, "target-infra")
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, report.Print(out, cluster.OutputTable))
+	assert.Equal(t, "No MachineHealthChecks found.\n\nNo unhealthy machines.\n\nNo remediations.\n", out.String())
+}
+
+func TestMachineHealthCheckRemediationAllowed(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxUnhealthy interface{}
+		expected     int64
+		healthy      int64
+		allowed      bool
+		shown        string
+	}{
+		{name: "default", expected: 3, healthy: 0, allowed: true, shown: "100%"},
+		{name: "count", maxUnhealthy: int64(1), expected: 3, healthy: 2, allowed: true, shown: "1"},
+		{name: "count-exceeded", maxUnhealthy: int64(1), expected: 3, healthy: 1, allowed: false, shown: "1"},
+		{name: "percent", maxUnhealthy: "40%", expected: 5, healthy: 3, allowed: true, shown: "40%"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClient(
+				fake.WithRESTMapper(provisioningMapper(cluster.MachineHealthCheckGVK, cluster.MachineGVK)),
+				fake.WithDynamicObjects(machineHealthCheck(tt.maxUnhealthy, tt.expected, tt.healthy)))
+			report, err := cluster.GetMachineHealth(c, "target-infra")
+			require.NoError(t, err)
+			require.Len(t, report.HealthChecks, 1)
+			assert.Equal(t, tt.allowed, report.HealthChecks[0].RemediationAllowed)
+			assert.Equal(t, tt.shown, report.HealthChecks[0].MaxUnhealthy)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// NodeUpgrader rolls the worker nodes of MachineDeployments out to the
+// machine template of their documents, e.g. one writing a new OS image,
+// replacing a batch of machines at a time
+type NodeUpgrader struct {
+	Client       dynamic.Interface
+	Mapper       meta.RESTMapper
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// BatchSize is the number of machines replaced at once, all machines of
+	// a MachineDeployment if zero
+	BatchSize int64
+	// PauseBetweenBatches pauses a MachineDeployment once a batch of
+	// machines was replaced, see Upgrade
+	PauseBetweenBatches bool
+	// Out receives the changes made and the progress of the rollouts
+	Out io.Writer
+}
+
+// NewNodeUpgrader returns a NodeUpgrader reporting to stdout
+func NewNodeUpgrader(c client.Interface, timeout time.Duration) *NodeUpgrader {
+	return &NodeUpgrader{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+// nodeRollout is a MachineDeployment being rolled out
+type nodeRollout struct {
+	WatchTarget
+	replicas   int64
+	generation int64
+	// batchEnd is the number of replaced machines the current batch ends at
+	batchEnd int64
+	progress string
+}
+
+// Upgrade patches the live MachineDeployments of docs with the
+// infrastructure template of their documents, creating the templates from
+// docs if they don't exist yet, and follows their rollouts one
+// MachineDeployment after the other. Cluster API creates a batch of
+// machines with the new template, then drains and deletes as many machines
+// with the old one, until all of them are replaced. Namespaced documents
+// without a namespace are looked up in defaultNamespace.
+//
+// With PauseBetweenBatches, the MachineDeployment is paused once a batch
+// was replaced and an ErrNodeUpgradePaused is returned. Running Upgrade
+// again resumes it for the next batch.
+func (n *NodeUpgrader) Upgrade(docs []document.Document, defaultNamespace string) error {
+	desired, err := machineDeploymentDocuments(docs, defaultNamespace)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(n.Timeout)
+	for _, md := range desired {
+		rollout, patchErr := n.patchMachineDeployment(md, docs, defaultNamespace)
+		if patchErr != nil {
+			return patchErr
+		}
+		if err = n.followRollout(rollout, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchMachineDeployment brings the live infrastructure template of md to
+// the one of its document, and resumes its rollout with batches of
+// BatchSize machines
+func (n *NodeUpgrader) patchMachineDeployment(md *unstructured.Unstructured, docs []document.Document,
+	defaultNamespace string) (*nodeRollout, error) {
+	rollout := &nodeRollout{WatchTarget: targetOf(md)}
+	resource, err := dynamicResource(n.Client, n.Mapper, rollout.GVK, rollout.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	live, err := resource.Get(rollout.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rollout.replicas = 1
+	if replicas, found, _ := unstructured.NestedInt64(live.Object, "spec", "replicas"); found {
+		rollout.replicas = replicas
+	}
+	batch := n.BatchSize
+	if batch <= 0 || batch > rollout.replicas {
+		batch = rollout.replicas
+	}
+	spec := map[string]interface{}{
+		"paused": false,
+		"strategy": map[string]interface{}{
+			"type": "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{
+				"maxSurge":       batch,
+				"maxUnavailable": 0,
+			},
+		},
+	}
+
+	template, _, _ := unstructured.NestedMap(md.Object, "spec", "template", "spec", "infrastructureRef")
+	liveTemplate, _, _ := unstructured.NestedMap(live.Object, "spec", "template", "spec", "infrastructureRef")
+	if template != nil && (template["kind"] != liveTemplate["kind"] || template["name"] != liveTemplate["name"]) {
+		if err = ensureTemplate(n.Client, n.Mapper, n.Out, template, rollout.Namespace, docs,
+			defaultNamespace); err != nil {
+			return nil, err
+		}
+		spec["template"] = map[string]interface{}{
+			"spec": map[string]interface{}{"infrastructureRef": template},
+		}
+		fmt.Fprintf(n.Out, "%s: rolling out from %s to %s, %d machines at a time\n", rollout,
+			n.templateImage(liveTemplate, rollout.Namespace), n.templateImage(template, rollout.Namespace), batch)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return nil, err
+	}
+	patched, err := resource.Patch(rollout.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rollout.generation = patched.GetGeneration()
+	return rollout, nil
+}
+
+// followRollout blocks until all machines of rollout are replaced, or until
+// a batch was replaced with PauseBetweenBatches
+func (n *NodeUpgrader) followRollout(rollout *nodeRollout, deadline time.Time) error {
+	resource, err := dynamicResource(n.Client, n.Mapper, rollout.GVK, rollout.Namespace)
+	if err != nil {
+		return err
+	}
+	batch := n.BatchSize
+	if batch <= 0 {
+		batch = rollout.replicas
+	}
+
+	for {
+		live, err := resource.Get(rollout.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		observed, _, _ := unstructured.NestedInt64(live.Object, "status", "observedGeneration")
+		// The status refers to the previous template until the patch is observed
+		if observed >= rollout.generation {
+			replicas, _, _ := unstructured.NestedInt64(live.Object, "status", "replicas")
+			updated, _, _ := unstructured.NestedInt64(live.Object, "status", "updatedReplicas")
+			ready, _, _ := unstructured.NestedInt64(live.Object, "status", "readyReplicas")
+			// Machines with the old template that are gone were replaced,
+			// while machines of the next batch may already be created
+			replaced := rollout.replicas - (replicas - updated)
+			if replaced > updated {
+				replaced = updated
+			}
+			if rollout.batchEnd == 0 {
+				rollout.batchEnd = min64(replaced+batch, rollout.replicas)
+			}
+
+			progress := fmt.Sprintf("%d/%d machines replaced, %d updated, %d ready", replaced, rollout.replicas,
+				updated, ready)
+			if progress != rollout.progress {
+				rollout.progress = progress
+				fmt.Fprintf(n.Out, "%s: %s\n", rollout, progress)
+			}
+
+			if replaced == rollout.replicas && replicas == rollout.replicas && ready == rollout.replicas {
+				fmt.Fprintf(n.Out, "%s: rollout complete\n", rollout)
+				return nil
+			}
+			// Cluster API deletes machines with the old template once as many
+			// new ones are available
+			if replaced >= rollout.batchEnd {
+				if n.PauseBetweenBatches {
+					return n.pauseRollout(resource, rollout, replaced)
+				}
+				rollout.batchEnd = min64(replaced+batch, rollout.replicas)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return ErrUpgradeTimeout{Resources: []string{rollout.String()}}
+		}
+		time.Sleep(n.PollInterval)
+	}
+}
+
+// pauseRollout pauses the MachineDeployment of rollout once a batch was
+// replaced
+func (n *NodeUpgrader) pauseRollout(resource dynamic.ResourceInterface, rollout *nodeRollout,
+	replaced int64) error {
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"paused": true}})
+	if err != nil {
+		return err
+	}
+	if _, err = resource.Patch(rollout.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(n.Out, "%s: paused after %d/%d machines were replaced\n", rollout, replaced, rollout.replicas)
+	return ErrNodeUpgradePaused{Resource: rollout.String(), Replaced: replaced, Replicas: rollout.replicas}
+}
+
+// templateImage describes the machine template referred to by ref with the
+// image it writes, for the metal3 templates which have one
+func (n *NodeUpgrader) templateImage(ref map[string]interface{}, namespace string) string {
+	kind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+	description := kind + "/" + name
+	apiVersion, _ := ref["apiVersion"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return description
+	}
+	resource, err := dynamicResource(n.Client, n.Mapper, gv.WithKind(kind), namespace)
+	if err != nil {
+		return description
+	}
+	template, err := resource.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return description
+	}
+	if image, _, _ := unstructured.NestedString(template.Object, "spec", "template", "spec", "image", "url"); image != "" {
+		description += " (image " + image + ")"
+	}
+	return description
+}
+
+// machineDeploymentDocuments returns the MachineDeployments of docs, with
+// defaultNamespace set on the ones without a namespace
+func machineDeploymentDocuments(docs []document.Document,
+	defaultNamespace string) ([]*unstructured.Unstructured, error) {
+	var deployments []*unstructured.Unstructured
+	for _, doc := range docs {
+		if doc.GetKind() != MachineDeploymentGVK.Kind || doc.GetGroup() != MachineDeploymentGVK.Group {
+			continue
+		}
+		obj, err := unstructuredDocument(doc, defaultNamespace)
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, obj)
+	}
+	if len(deployments) == 0 {
+		return nil, ErrNoMachineDeployments{}
+	}
+	return deployments, nil
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,188 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+)
+
+var machineDeploymentsGVR = schema.GroupVersionResource{
+	Group:    "cluster.x-k8s.io",
+	Version:  "v1alpha3",
+	Resource: "machinedeployments",
+}
+
+// rollingWorkers returns the workers MachineDeployment, with 4 machines of
+// the workers-v1 template
+func rollingWorkers() *unstructured.Unstructured {
+	return provisioningObject(cluster.MachineDeploymentGVK, "workers",
+		map[string]interface{}{
+			"clusterName": "target-cluster",
+			"replicas":    int64(4),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+						"kind":       "Metal3MachineTemplate",
+						"name":       "workers-v1",
+					},
+				},
+			},
+		}, rolloutStatus(4, 4, 4), nil)
+}
+
+func rolloutStatus(replicas, updated, ready int64) map[string]interface{} {
+	return map[string]interface{}{
+		"replicas":        replicas,
+		"updatedReplicas": updated,
+		"readyReplicas":   ready,
+	}
+}
+
+// rollOutOnGet makes the status of the workers MachineDeployment go through
+// statuses once it refers to the workers-v2 template, one status per read,
+// the way Cluster API rolls it out
+func rollOutOnGet(c *dynamicfake.FakeDynamicClient, statuses ...map[string]interface{}) {
+	objects := c.ReactionChain[len(c.ReactionChain)-1]
+	c.PrependReactor("get", "machinedeployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		_, obj, err := objects.React(k8stesting.NewGetAction(machineDeploymentsGVR, "target-infra", "workers"))
+		if err != nil {
+			return true, nil, err
+		}
+		md := obj.(*unstructured.Unstructured)
+		template, _, _ := unstructured.NestedString(md.Object, "spec", "template", "spec", "infrastructureRef", "name")
+		if template != "workers-v2" || len(statuses) == 0 {
+			return false, nil, nil
+		}
+		md.Object["status"] = statuses[0]
+		statuses = statuses[1:]
+		_, _, err = objects.React(k8stesting.NewUpdateAction(machineDeploymentsGVR, "target-infra", md))
+		return err != nil, nil, err
+	})
+}
+
+func newTestNodeUpgrader(statuses ...map[string]interface{}) (*cluster.NodeUpgrader, *bytes.Buffer) {
+	template := provisioningObject(machineTemplateGVK, "workers-v1",
+		map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"image": map[string]interface{}{"url": "http://10.23.24.101:80/images/ubuntu-18.04.qcow2"},
+				},
+			},
+		}, nil, nil)
+	c := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), rollingWorkers(), template)
+	rollOutOnGet(c, statuses...)
+
+	out := &bytes.Buffer{}
+	return &cluster.NodeUpgrader{
+		Client:       c,
+		Mapper:       provisioningMapper(cluster.MachineDeploymentGVK, machineTemplateGVK),
+		Timeout:      time.Second,
+		PollInterval: time.Millisecond,
+		BatchSize:    2,
+		Out:          out,
+	}, out
+}
+
+func workersSpec(t *testing.T, upgrader *cluster.NodeUpgrader) map[string]interface{} {
+	md, err := upgrader.Client.Resource(machineDeploymentsGVR).Namespace("target-infra").
+		Get("workers", metav1.GetOptions{})
+	require.NoError(t, err)
+	spec, _, err := unstructured.NestedMap(md.Object, "spec")
+	require.NoError(t, err)
+	return spec
+}
+
+func TestNodeUpgrade(t *testing.T) {
+	upgrader, out := newTestNodeUpgrader(
+		rolloutStatus(6, 2, 4),
+		rolloutStatus(4, 2, 4),
+		rolloutStatus(6, 4, 4),
+		rolloutStatus(4, 4, 4),
+	)
+
+	require.NoError(t, upgrader.Upgrade(testDocuments(t, "testdata/nodeupgrade"), "target-infra"))
+	assert.Equal(t, ""+
+		"Metal3MachineTemplate/workers-v2 in namespace target-infra: created\n"+
+		workersTarget+": rolling out from "+
+		"Metal3MachineTemplate/workers-v1 (image http://10.23.24.101:80/images/ubuntu-18.04.qcow2) to "+
+		"Metal3MachineTemplate/workers-v2 (image http://10.23.24.101:80/images/ubuntu-20.04.qcow2), "+
+		"2 machines at a time\n"+
+		workersTarget+": 0/4 machines replaced, 2 updated, 4 ready\n"+
+		workersTarget+": 2/4 machines replaced, 2 updated, 4 ready\n"+
+		workersTarget+": 2/4 machines replaced, 4 updated, 4 ready\n"+
+		workersTarget+": 4/4 machines replaced, 4 updated, 4 ready\n"+
+		workersTarget+": rollout complete\n", out.String())
+
+	spec := workersSpec(t, upgrader)
+	assert.Equal(t, map[string]interface{}{
+		"type":          "RollingUpdate",
+		"rollingUpdate": map[string]interface{}{"maxSurge": int64(2), "maxUnavailable": int64(0)},
+	}, spec["strategy"])
+	template, _, _ := unstructured.NestedString(spec, "template", "spec", "infrastructureRef", "name")
+	assert.Equal(t, "workers-v2", template)
+}
+
+func TestNodeUpgradePauseBetweenBatches(t *testing.T) {
+	upgrader, out := newTestNodeUpgrader(
+		rolloutStatus(6, 2, 4),
+		rolloutStatus(4, 2, 4),
+		rolloutStatus(6, 4, 4),
+		rolloutStatus(4, 4, 4),
+	)
+	upgrader.PauseBetweenBatches = true
+	docs := testDocuments(t, "testdata/nodeupgrade")
+
+	err := upgrader.Upgrade(docs, "target-infra")
+	assert.Equal(t, cluster.ErrNodeUpgradePaused{Resource: workersTarget, Replaced: 2, Replicas: 4}, err)
+	assert.Contains(t, out.String(), workersTarget+": paused after 2/4 machines were replaced\n")
+	assert.Equal(t, true, workersSpec(t, upgrader)["paused"])
+
+	// Resuming reads the MachineDeployment, which the next batch was
+	// created for in the meantime
+	out.Reset()
+	require.NoError(t, upgrader.Upgrade(docs, "target-infra"))
+	assert.Equal(t, ""+
+		workersTarget+": 4/4 machines replaced, 4 updated, 4 ready\n"+
+		workersTarget+": rollout complete\n", out.String())
+	assert.Equal(t, false, workersSpec(t, upgrader)["paused"])
+}
+
+func TestNodeUpgradeTimeout(t *testing.T) {
+	upgrader, _ := newTestNodeUpgrader(rolloutStatus(6, 2, 4))
+	upgrader.Timeout = 10 * time.Millisecond
+
+	err := upgrader.Upgrade(testDocuments(t, "testdata/nodeupgrade"), "target-infra")
+	assert.Equal(t, cluster.ErrUpgradeTimeout{Resources: []string{workersTarget}}, err)
+}
+
+func TestNodeUpgradeWithoutMachineDeployments(t *testing.T) {
+	upgrader, _ := newTestNodeUpgrader()
+	err := upgrader.Upgrade(testDocuments(t, "testdata/upgrade"), "target-infra")
+	assert.Equal(t, cluster.ErrNoMachineDeployments{}, err)
+}
@@ -0,0 +1,311 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Formats a ProvisioningStatus can be printed in
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// Kinds of the resources a ProvisioningStatus is built from
+var (
+	ClusterGVK             = schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1alpha3", Kind: "Cluster"}
+	MachineGVK             = schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1alpha3", Kind: "Machine"}
+	KubeadmControlPlaneGVK = schema.GroupVersionKind{
+		Group:   "controlplane.cluster.x-k8s.io",
+		Version: "v1alpha3",
+		Kind:    "KubeadmControlPlane",
+	}
+	BareMetalHostGVK = schema.GroupVersionKind{Group: "metal3.io", Version: "v1alpha1", Kind: "BareMetalHost"}
+)
+
+// Roles of the nodes of a cluster
+const (
+	RoleControlPlane = "control-plane"
+	RoleWorker       = "worker"
+)
+
+// controlPlaneLabel marks the Machines of the control plane of a cluster
+const controlPlaneLabel = "cluster.x-k8s.io/control-plane"
+
+// machinePhaseRunning is the phase of a Machine whose node joined the cluster
+const machinePhaseRunning = "Running"
+
+// ProvisioningStatus is the roll-up of the provisioning progress of the
+// clusters of a management cluster
+type ProvisioningStatus struct {
+	Clusters []ClusterProgress `json:"clusters"`
+	// Nodes are the Machines of all clusters, followed by the
+	// BareMetalHosts not consumed by any Machine
+	Nodes []NodeProgress `json:"nodes"`
+}
+
+// ClusterProgress is the provisioning progress of a Cluster
+type ClusterProgress struct {
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name"`
+	Phase               string `json:"phase"`
+	InfrastructureKind  string `json:"infrastructureKind,omitempty"`
+	InfrastructureReady bool   `json:"infrastructureReady"`
+	// ControlPlane is nil if the control plane of the cluster isn't a
+	// KubeadmControlPlane
+	ControlPlane *ControlPlaneProgress `json:"controlPlane,omitempty"`
+	Machines     int                   `json:"machines"`
+	ReadyNodes   int                   `json:"readyNodes"`
+}
+
+// ControlPlaneProgress is the provisioning progress of a KubeadmControlPlane
+type ControlPlaneProgress struct {
+	Name string `json:"name"`
+	// Version is the Kubernetes version the control plane runs, or is
+	// rolled out to
+	Version         string `json:"version,omitempty"`
+	Initialized     bool   `json:"initialized"`
+	Ready           bool   `json:"ready"`
+	Replicas        int64  `json:"replicas"`
+	ReadyReplicas   int64  `json:"readyReplicas"`
+	UpdatedReplicas int64  `json:"updatedReplicas"`
+}
+
+// NodeProgress is the provisioning progress of a node, as seen by its
+// Machine and by the BareMetalHost it runs on
+type NodeProgress struct {
+	Namespace string `json:"namespace"`
+	// Cluster and Machine are empty for a BareMetalHost that is not
+	// consumed by any Machine
+	Cluster      string `json:"cluster,omitempty"`
+	Machine      string `json:"machine,omitempty"`
+	Role         string `json:"role,omitempty"`
+	MachinePhase string `json:"machinePhase,omitempty"`
+	NodeName     string `json:"nodeName,omitempty"`
+	Host         string `json:"host,omitempty"`
+	HostState    string `json:"hostState,omitempty"`
+	Ready        bool   `json:"ready"`
+}
+
+// GetProvisioningStatus reads the Clusters, Machines, KubeadmControlPlanes
+// and BareMetalHosts of namespace, or of all namespaces if it is empty, and
+// rolls them up into a ProvisioningStatus. KubeadmControlPlanes and
+// BareMetalHosts are optional, they are skipped if their kind isn't known
+// to the cluster.
+func GetProvisioningStatus(c client.ResourceAccessor, namespace string) (*ProvisioningStatus, error) {
+	clusters, err := listResources(c, ClusterGVK, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	machines, err := listResources(c, MachineGVK, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	controlPlanes, err := listResources(c, KubeadmControlPlaneGVK, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := listResources(c, BareMetalHostGVK, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// BareMetalHosts refer to the infrastructure machine of the Machine
+	// they run, e.g. a Metal3Machine, with their consumer reference
+	consumers := make(map[string]unstructured.Unstructured, len(hosts))
+	for _, host := range hosts {
+		kind, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "kind")
+		name, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "name")
+		if name != "" {
+			consumers[refKey(host.GetNamespace(), kind, name)] = host
+		}
+	}
+
+	status := &ProvisioningStatus{Clusters: []ClusterProgress{}, Nodes: []NodeProgress{}}
+	progress := make(map[string]*ClusterProgress, len(clusters))
+	for _, cluster := range clusters {
+		status.Clusters = append(status.Clusters, clusterProgress(cluster, controlPlanes))
+	}
+	for i := range status.Clusters {
+		cp := &status.Clusters[i]
+		progress[cp.Namespace+"/"+cp.Name] = cp
+	}
+
+	consumed := make(map[string]bool, len(hosts))
+	for _, machine := range machines {
+		node := machineProgress(machine)
+		kind, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "kind")
+		name, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "name")
+		if host, ok := consumers[refKey(machine.GetNamespace(), kind, name)]; ok {
+			node.Host = host.GetName()
+			node.HostState, _, _ = unstructured.NestedString(host.Object, "status", "provisioning", "state")
+			consumed[host.GetNamespace()+"/"+host.GetName()] = true
+		}
+		if cp, ok := progress[node.Namespace+"/"+node.Cluster]; ok {
+			cp.Machines++
+			if node.Ready {
+				cp.ReadyNodes++
+			}
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+	for _, host := range hosts {
+		if consumed[host.GetNamespace()+"/"+host.GetName()] {
+			continue
+		}
+		state, _, _ := unstructured.NestedString(host.Object, "status", "provisioning", "state")
+		status.Nodes = append(status.Nodes, NodeProgress{
+			Namespace: host.GetNamespace(),
+			Host:      host.GetName(),
+			HostState: state,
+		})
+	}
+	return status, nil
+}
+
+// Print writes the status to w in the given format, see OutputTable and
+// OutputJSON
+func (s *ProvisioningStatus) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return s.printTable(w)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(s)
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (s *ProvisioningStatus) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "NAMESPACE\tCLUSTER\tPHASE\tINFRASTRUCTURE\tCONTROL PLANE\tNODES")
+	for _, c := range s.Clusters {
+		controlPlane := "-"
+		if c.ControlPlane != nil {
+			controlPlane = fmt.Sprintf("%d/%d ready", c.ControlPlane.ReadyReplicas, c.ControlPlane.Replicas)
+		}
+		infrastructure := readiness(c.InfrastructureReady)
+		if c.InfrastructureKind != "" {
+			infrastructure = c.InfrastructureKind + " " + infrastructure
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d/%d ready\n", c.Namespace, c.Name, valueOrDash(c.Phase),
+			infrastructure, controlPlane, c.ReadyNodes, c.Machines)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = util.NewTabWriter(w)
+	fmt.Fprintln(tw, "NAMESPACE\tCLUSTER\tMACHINE\tROLE\tPHASE\tNODE\tHOST\tHOST STATE\tREADY")
+	for _, n := range s.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n", n.Namespace, valueOrDash(n.Cluster),
+			valueOrDash(n.Machine), valueOrDash(n.Role), valueOrDash(n.MachinePhase), valueOrDash(n.NodeName),
+			valueOrDash(n.Host), valueOrDash(n.HostState), n.Ready)
+	}
+	return tw.Flush()
+}
+
+// listResources returns the resources of kind gvk sorted by namespace and
+// name. If optional, an unknown kind yields no resources instead of an error.
+func listResources(c client.ResourceAccessor, gvk schema.GroupVersionKind, namespace string,
+	optional bool) ([]unstructured.Unstructured, error) {
+	list, err := c.List(gvk, namespace, metav1.ListOptions{})
+	if optional && meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	return items, nil
+}
+
+func clusterProgress(cluster unstructured.Unstructured, controlPlanes []unstructured.Unstructured) ClusterProgress {
+	progress := ClusterProgress{Namespace: cluster.GetNamespace(), Name: cluster.GetName()}
+	progress.Phase, _, _ = unstructured.NestedString(cluster.Object, "status", "phase")
+	progress.InfrastructureKind, _, _ = unstructured.NestedString(cluster.Object, "spec", "infrastructureRef", "kind")
+	progress.InfrastructureReady, _, _ = unstructured.NestedBool(cluster.Object, "status", "infrastructureReady")
+
+	kind, _, _ := unstructured.NestedString(cluster.Object, "spec", "controlPlaneRef", "kind")
+	name, _, _ := unstructured.NestedString(cluster.Object, "spec", "controlPlaneRef", "name")
+	if kind != KubeadmControlPlaneGVK.Kind {
+		return progress
+	}
+	for _, kcp := range controlPlanes {
+		if kcp.GetNamespace() != cluster.GetNamespace() || kcp.GetName() != name {
+			continue
+		}
+		cp := &ControlPlaneProgress{Name: name}
+		cp.Version, _, _ = unstructured.NestedString(kcp.Object, "spec", "version")
+		cp.Initialized, _, _ = unstructured.NestedBool(kcp.Object, "status", "initialized")
+		cp.Ready, _, _ = unstructured.NestedBool(kcp.Object, "status", "ready")
+		cp.Replicas, _, _ = unstructured.NestedInt64(kcp.Object, "status", "replicas")
+		cp.ReadyReplicas, _, _ = unstructured.NestedInt64(kcp.Object, "status", "readyReplicas")
+		cp.UpdatedReplicas, _, _ = unstructured.NestedInt64(kcp.Object, "status", "updatedReplicas")
+		progress.ControlPlane = cp
+	}
+	return progress
+}
+
+func machineProgress(machine unstructured.Unstructured) NodeProgress {
+	node := NodeProgress{Namespace: machine.GetNamespace(), Machine: machine.GetName(), Role: RoleWorker}
+	node.Cluster, _, _ = unstructured.NestedString(machine.Object, "spec", "clusterName")
+	if _, ok := machine.GetLabels()[controlPlaneLabel]; ok {
+		node.Role = RoleControlPlane
+	}
+	node.MachinePhase, _, _ = unstructured.NestedString(machine.Object, "status", "phase")
+	node.NodeName, _, _ = unstructured.NestedString(machine.Object, "status", "nodeRef", "name")
+	node.Ready = node.MachinePhase == machinePhaseRunning && node.NodeName != ""
+	return node
+}
+
+func refKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
+func readiness(ready bool) string {
+	if ready {
+		return "ready"
+	}
+	return "not ready"
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,242 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func provisioningObject(gvk schema.GroupVersionKind, name string, spec, status map[string]interface{},
+	labels map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": "target-infra",
+	}
+	if labels != nil {
+		metadata["labels"] = labels
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": gvk.GroupVersion().String(),
+			"kind":       gvk.Kind,
+			"metadata":   metadata,
+			"spec":       spec,
+			"status":     status,
+		},
+	}
+}
+
+func provisioningObjects() []runtime.Object {
+	return []runtime.Object{
+		provisioningObject(cluster.ClusterGVK, "target-cluster",
+			map[string]interface{}{
+				"controlPlaneRef":   map[string]interface{}{"kind": "KubeadmControlPlane", "name": "target-cp"},
+				"infrastructureRef": map[string]interface{}{"kind": "Metal3Cluster", "name": "target-cluster"},
+			},
+			map[string]interface{}{"phase": "Provisioned", "infrastructureReady": true}, nil),
+		provisioningObject(cluster.KubeadmControlPlaneGVK, "target-cp", map[string]interface{}{},
+			map[string]interface{}{
+				"initialized":     true,
+				"ready":           true,
+				"replicas":        int64(1),
+				"readyReplicas":   int64(1),
+				"updatedReplicas": int64(1),
+			}, nil),
+		provisioningObject(cluster.MachineGVK, "target-cp-abcde",
+			map[string]interface{}{
+				"clusterName":       "target-cluster",
+				"infrastructureRef": map[string]interface{}{"kind": "Metal3Machine", "name": "target-cp-m3m"},
+			},
+			map[string]interface{}{
+				"phase":   "Running",
+				"nodeRef": map[string]interface{}{"name": "node01"},
+			},
+			map[string]interface{}{"cluster.x-k8s.io/control-plane": ""}),
+		provisioningObject(cluster.MachineGVK, "target-worker-fghij",
+			map[string]interface{}{
+				"clusterName":       "target-cluster",
+				"infrastructureRef": map[string]interface{}{"kind": "Metal3Machine", "name": "target-worker-m3m"},
+			},
+			map[string]interface{}{"phase": "Provisioning"}, nil),
+		provisioningObject(cluster.BareMetalHostGVK, "node01",
+			map[string]interface{}{
+				"consumerRef": map[string]interface{}{"kind": "Metal3Machine", "name": "target-cp-m3m"},
+			},
+			map[string]interface{}{"provisioning": map[string]interface{}{"state": "provisioned"}}, nil),
+		provisioningObject(cluster.BareMetalHostGVK, "node02",
+			map[string]interface{}{
+				"consumerRef": map[string]interface{}{"kind": "Metal3Machine", "name": "target-worker-m3m"},
+			},
+			map[string]interface{}{"provisioning": map[string]interface{}{"state": "provisioning"}}, nil),
+		provisioningObject(cluster.BareMetalHostGVK, "node03", map[string]interface{}{},
+			map[string]interface{}{"provisioning": map[string]interface{}{"state": "ready"}}, nil),
+	}
+}
+
+func provisioningMapper(gvks ...schema.GroupVersionKind) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range gvks {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+func TestGetProvisioningStatus(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK,
+			cluster.KubeadmControlPlaneGVK, cluster.BareMetalHostGVK)),
+		fake.WithDynamicObjects(provisioningObjects()...))
+
+	status, err := cluster.GetProvisioningStatus(c, "target-infra")
+	require.NoError(t, err)
+	assert.Equal(t, &cluster.ProvisioningStatus{
+		Clusters: []cluster.ClusterProgress{
+			{
+				Namespace:           "target-infra",
+				Name:                "target-cluster",
+				Phase:               "Provisioned",
+				InfrastructureKind:  "Metal3Cluster",
+				InfrastructureReady: true,
+				ControlPlane: &cluster.ControlPlaneProgress{
+					Name:            "target-cp",
+					Initialized:     true,
+					Ready:           true,
+					Replicas:        1,
+					ReadyReplicas:   1,
+					UpdatedReplicas: 1,
+				},
+				Machines:   2,
+				ReadyNodes: 1,
+			},
+		},
+		Nodes: []cluster.NodeProgress{
+			{
+				Namespace:    "target-infra",
+				Cluster:      "target-cluster",
+				Machine:      "target-cp-abcde",
+				Role:         cluster.RoleControlPlane,
+				MachinePhase: "Running",
+				NodeName:     "node01",
+				Host:         "node01",
+				HostState:    "provisioned",
+				Ready:        true,
+			},
+			{
+				Namespace:    "target-infra",
+				Cluster:      "target-cluster",
+				Machine:      "target-worker-fghij",
+				Role:         cluster.RoleWorker,
+				MachinePhase: "Provisioning",
+				Host:         "node02",
+				HostState:    "provisioning",
+			},
+			{
+				Namespace: "target-infra",
+				Host:      "node03",
+				HostState: "ready",
+			},
+		},
+	}, status)
+
+	status, err = cluster.GetProvisioningStatus(c, "other")
+	require.NoError(t, err)
+	assert.Equal(t, &cluster.ProvisioningStatus{Clusters: []cluster.ClusterProgress{},
+		Nodes: []cluster.NodeProgress{}}, status)
+}
+
+func TestGetProvisioningStatusOptionalKinds(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.MachineGVK)),
+		fake.WithDynamicObjects(provisioningObjects()...))
+
+	status, err := cluster.GetProvisioningStatus(c, "target-infra")
+	require.NoError(t, err)
+	require.Len(t, status.Clusters, 1)
+	assert.Nil(t, status.Clusters[0].ControlPlane)
+	require.Len(t, status.Nodes, 2)
+	assert.Empty(t, status.Nodes[0].Host)
+
+	c = fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.MachineGVK)),
+		fake.WithDynamicObjects(provisioningObjects()...))
+	_, err = cluster.GetProvisioningStatus(c, "target-infra")
+	assert.True(t, meta.IsNoMatchError(err))
+}
+
+func TestProvisioningStatusPrint(t *testing.T) {
+	status := &cluster.ProvisioningStatus{
+		Clusters: []cluster.ClusterProgress{
+			{
+				Namespace:    "target-infra",
+				Name:         "target-cluster",
+				Phase:        "Provisioned",
+				ControlPlane: &cluster.ControlPlaneProgress{Name: "target-cp", Replicas: 1},
+				Machines:     1,
+			},
+			{
+				Namespace:           "virtual",
+				Name:                "docker-cluster",
+				Phase:               "Provisioned",
+				InfrastructureKind:  "DockerCluster",
+				InfrastructureReady: true,
+			},
+		},
+		Nodes: []cluster.NodeProgress{
+			{
+				Namespace:    "target-infra",
+				Cluster:      "target-cluster",
+				Machine:      "target-cp-abcde",
+				Role:         cluster.RoleControlPlane,
+				MachinePhase: "Provisioning",
+				Host:         "node01",
+				HostState:    "provisioning",
+			},
+			{Namespace: "target-infra", Host: "node02", HostState: "ready"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, status.Print(out, cluster.OutputTable))
+	assert.Equal(t, ""+
+		"NAMESPACE      CLUSTER          PHASE         INFRASTRUCTURE        CONTROL PLANE   NODES\n"+
+		"target-infra   target-cluster   Provisioned   not ready             0/1 ready       0/1 ready\n"+
+		"virtual        docker-cluster   Provisioned   DockerCluster ready   -               0/0 ready\n"+
+		"\n"+
+		"NAMESPACE      CLUSTER          MACHINE           ROLE            PHASE          NODE   HOST     "+
+		"HOST STATE     READY\n"+
+		"target-infra   target-cluster   target-cp-abcde   control-plane   Provisioning   -      node01   "+
+		"provisioning   false\n"+
+		"target-infra   -                -                 -               -              -      node02   "+
+		"ready          false\n", out.String())
+
+	out.Reset()
+	require.NoError(t, status.Print(out, cluster.OutputJSON))
+	assert.Contains(t, out.String(), `"controlPlane": {
+                "name": "target-cp",`)
+	assert.Contains(t, out.String(), `"host": "node02",`)
+
+	assert.Equal(t, cluster.ErrInvalidOutputFormat{Format: "yaml"}, status.Print(out, "yaml"))
+}
@@ -0,0 +1,262 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// MachineDeploymentGVK is the kind of the worker node groups of a cluster
+var MachineDeploymentGVK = schema.GroupVersionKind{
+	Group:   "cluster.x-k8s.io",
+	Version: "v1alpha3",
+	Kind:    "MachineDeployment",
+}
+
+// machineDeploymentLabel is set by Cluster API on the Machines of a
+// MachineDeployment to its name
+const machineDeploymentLabel = "cluster.x-k8s.io/deployment-name"
+
+// hostStateProvisioned is the provisioning state of a BareMetalHost whose
+// image was written
+const hostStateProvisioned = "provisioned"
+
+// ScaleTarget is a MachineDeployment and the number of Machines it should
+// have
+type ScaleTarget struct {
+	Namespace string
+	Name      string
+	Replicas  int64
+}
+
+func (t ScaleTarget) String() string {
+	return fmt.Sprintf("MachineDeployment/%s in namespace %s", t.Name, t.Namespace)
+}
+
+// ScaleTargetsFromDocuments returns a target for every MachineDeployment of
+// docs, scaled to the replicas of its document. Documents without a
+// namespace are in defaultNamespace.
+func ScaleTargetsFromDocuments(docs []document.Document, defaultNamespace string) ([]ScaleTarget, error) {
+	var targets []ScaleTarget
+	for _, doc := range docs {
+		if doc.GetKind() != MachineDeploymentGVK.Kind || doc.GetGroup() != MachineDeploymentGVK.Group {
+			continue
+		}
+		obj, err := unstructuredDocument(doc, defaultNamespace)
+		if err != nil {
+			return nil, err
+		}
+		replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			// Cluster API defaults the replicas of a MachineDeployment to 1
+			replicas = 1
+		}
+		targets = append(targets, ScaleTarget{Namespace: obj.GetNamespace(), Name: obj.GetName(), Replicas: replicas})
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoMachineDeployments{}
+	}
+	return targets, nil
+}
+
+// Scaler changes the number of Machines of MachineDeployments and waits
+// for their nodes to be ready
+type Scaler struct {
+	Client       dynamic.Interface
+	Mapper       meta.RESTMapper
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives the changes made and the progress of the scaling
+	Out io.Writer
+}
+
+// NewScaler returns a Scaler reporting to stdout
+func NewScaler(c client.Interface, timeout time.Duration) *Scaler {
+	return &Scaler{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+type scaledDeployment struct {
+	ScaleTarget
+	progress string
+	done     bool
+}
+
+// Scale sets the replicas of the MachineDeployments of targets and blocks
+// until each of them has exactly that many Machines, all of them running a
+// ready node. The BareMetalHosts the Machines are provisioned on are
+// reported along the way. An ErrScaleTimeout is returned if some of them
+// don't get there in time.
+func (s *Scaler) Scale(targets []ScaleTarget) error {
+	deployments := make([]*scaledDeployment, 0, len(targets))
+	for _, target := range targets {
+		if target.Replicas < 0 {
+			return ErrInvalidReplicas{Replicas: target.Replicas}
+		}
+		if err := s.patchReplicas(target); err != nil {
+			return err
+		}
+		deployments = append(deployments, &scaledDeployment{ScaleTarget: target})
+	}
+
+	deadline := time.Now().Add(s.Timeout)
+	for {
+		var pending []string
+		for _, d := range deployments {
+			if d.done {
+				continue
+			}
+			if err := s.check(d); err != nil {
+				return err
+			}
+			if !d.done {
+				pending = append(pending, d.String())
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrScaleTimeout{Resources: pending}
+		}
+		time.Sleep(s.PollInterval)
+	}
+}
+
+func (s *Scaler) patchReplicas(target ScaleTarget) error {
+	resource, err := dynamicResource(s.Client, s.Mapper, MachineDeploymentGVK, target.Namespace)
+	if err != nil {
+		return err
+	}
+	live, err := resource.Get(target.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	current, _, _ := unstructured.NestedInt64(live.Object, "spec", "replicas")
+	if current == target.Replicas {
+		fmt.Fprintf(s.Out, "%s: already at %d replicas\n", target, target.Replicas)
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": target.Replicas},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = resource.Patch(target.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.Out, "%s: scaling from %d to %d replicas\n", target, current, target.Replicas)
+	return nil
+}
+
+// check reports the progress of d since the last check
+func (s *Scaler) check(d *scaledDeployment) error {
+	resource, err := dynamicResource(s.Client, s.Mapper, MachineGVK, d.Namespace)
+	if err != nil {
+		return err
+	}
+	machines, err := resource.List(metav1.ListOptions{LabelSelector: machineDeploymentLabel + "=" + d.Name})
+	if err != nil {
+		return err
+	}
+	hosts, err := s.hostsByConsumer(d.Namespace)
+	if err != nil {
+		return err
+	}
+
+	ready, provisioned, withHost := 0, 0, 0
+	for _, machine := range machines.Items {
+		if machineProgress(machine).Ready {
+			ready++
+		}
+		kind, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "kind")
+		name, _, _ := unstructured.NestedString(machine.Object, "spec", "infrastructureRef", "name")
+		if state, ok := hosts[refKey(d.Namespace, kind, name)]; ok {
+			withHost++
+			if state == hostStateProvisioned {
+				provisioned++
+			}
+		}
+	}
+
+	progress := fmt.Sprintf("%d machines, %d/%d ready", len(machines.Items), ready, d.Replicas)
+	if withHost > 0 {
+		progress += fmt.Sprintf(", %d/%d hosts provisioned", provisioned, withHost)
+	}
+	if progress != d.progress {
+		d.progress = progress
+		fmt.Fprintf(s.Out, "%s: %s\n", d, progress)
+	}
+
+	if int64(len(machines.Items)) == d.Replicas && int64(ready) == d.Replicas {
+		d.done = true
+		fmt.Fprintf(s.Out, "%s: %d replicas ready\n", d, d.Replicas)
+	}
+	return nil
+}
+
+// hostsByConsumer returns the provisioning states of the BareMetalHosts of
+// namespace by the infrastructure machine consuming them. There are none if
+// the BareMetalHost kind isn't known to the cluster.
+func (s *Scaler) hostsByConsumer(namespace string) (map[string]string, error) {
+	resource, err := dynamicResource(s.Client, s.Mapper, BareMetalHostGVK, namespace)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := resource.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]string, len(hosts.Items))
+	for _, host := range hosts.Items {
+		kind, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "kind")
+		name, _, _ := unstructured.NestedString(host.Object, "spec", "consumerRef", "name")
+		if name != "" {
+			states[refKey(namespace, kind, name)], _, _ = unstructured.NestedString(host.Object,
+				"status", "provisioning", "state")
+		}
+	}
+	return states, nil
+}
@@ -0,0 +1,151 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+const workersTarget = "MachineDeployment/workers in namespace target-infra"
+
+func machineDeployment(replicas int64) *unstructured.Unstructured {
+	return provisioningObject(cluster.MachineDeploymentGVK, "workers",
+		map[string]interface{}{"clusterName": "target-cluster", "replicas": replicas},
+		map[string]interface{}{}, nil)
+}
+
+func workerMachine(name, phase, node string) *unstructured.Unstructured {
+	machineStatus := map[string]interface{}{"phase": phase}
+	if node != "" {
+		machineStatus["nodeRef"] = map[string]interface{}{"name": node}
+	}
+	return provisioningObject(cluster.MachineGVK, name,
+		map[string]interface{}{
+			"clusterName":       "target-cluster",
+			"infrastructureRef": map[string]interface{}{"kind": "Metal3Machine", "name": name},
+		}, machineStatus,
+		map[string]interface{}{"cluster.x-k8s.io/deployment-name": "workers"})
+}
+
+func workerHost(name, machine, state string) *unstructured.Unstructured {
+	return provisioningObject(cluster.BareMetalHostGVK, name,
+		map[string]interface{}{
+			"consumerRef": map[string]interface{}{"kind": "Metal3Machine", "name": machine},
+		},
+		map[string]interface{}{"provisioning": map[string]interface{}{"state": state}}, nil)
+}
+
+func newTestScaler(objects ...runtime.Object) (*cluster.Scaler, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.MachineDeploymentGVK, cluster.MachineGVK,
+			cluster.BareMetalHostGVK)),
+		fake.WithDynamicObjects(objects...))
+	return &cluster.Scaler{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Out:          out,
+	}, out
+}
+
+func TestScaleTargetsFromDocuments(t *testing.T) {
+	targets, err := cluster.ScaleTargetsFromDocuments(testDocuments(t, "testdata/scale"), "default")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []cluster.ScaleTarget{
+		{Namespace: "target-infra", Name: "workers", Replicas: 2},
+		{Namespace: "default", Name: "storage", Replicas: 1},
+	}, targets)
+
+	_, err = cluster.ScaleTargetsFromDocuments(testDocuments(t, "testdata/diff"), "default")
+	assert.Equal(t, cluster.ErrNoMachineDeployments{}, err)
+}
+
+func TestScale(t *testing.T) {
+	target := cluster.ScaleTarget{Namespace: "target-infra", Name: "workers", Replicas: 2}
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		target      cluster.ScaleTarget
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			name: "scaled",
+			objects: []runtime.Object{
+				machineDeployment(1),
+				workerMachine("workers-1", "Running", "node02"),
+				workerMachine("workers-2", "Running", "node03"),
+				workerHost("node02", "workers-1", "provisioned"),
+				workerHost("node03", "workers-2", "provisioned"),
+			},
+			target: target,
+			expectedOut: workersTarget + ": scaling from 1 to 2 replicas\n" +
+				workersTarget + ": 2 machines, 2/2 ready, 2/2 hosts provisioned\n" +
+				workersTarget + ": 2 replicas ready\n",
+		},
+		{
+			name: "already-scaled",
+			objects: []runtime.Object{
+				machineDeployment(1),
+				workerMachine("workers-1", "Running", "node02"),
+			},
+			target: cluster.ScaleTarget{Namespace: "target-infra", Name: "workers", Replicas: 1},
+			expectedOut: workersTarget + ": already at 1 replicas\n" +
+				workersTarget + ": 1 machines, 1/1 ready\n" +
+				workersTarget + ": 1 replicas ready\n",
+		},
+		{
+			name: "timeout",
+			objects: []runtime.Object{
+				machineDeployment(1),
+				workerMachine("workers-1", "Running", "node02"),
+				workerMachine("workers-2", "Provisioning", ""),
+				workerHost("node02", "workers-1", "provisioned"),
+				workerHost("node03", "workers-2", "provisioning"),
+			},
+			target: target,
+			expectedOut: workersTarget + ": scaling from 1 to 2 replicas\n" +
+				workersTarget + ": 2 machines, 1/2 ready, 1/2 hosts provisioned\n",
+			expectedErr: cluster.ErrScaleTimeout{Resources: []string{workersTarget}},
+		},
+		{
+			name:        "negative-replicas",
+			objects:     []runtime.Object{machineDeployment(1)},
+			target:      cluster.ScaleTarget{Namespace: "target-infra", Name: "workers", Replicas: -1},
+			expectedErr: cluster.ErrInvalidReplicas{Replicas: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scaler, out := newTestScaler(tt.objects...)
+			assert.Equal(t, tt.expectedErr, scaler.Scale([]cluster.ScaleTarget{tt.target}))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
@@ -19,7 +19,6 @@ import (
 	"fmt"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -35,25 +34,23 @@ const (
 	UnknownStatus = Status("Unknown")
 )
 
-// StatusMap holds a mapping of schema.GroupVersionResource to various statuses
+// StatusMap holds a mapping of schema.GroupVersionKind to various statuses
 // a resource may be in, as well as the Expression used to check for that
 // status.
 type StatusMap struct {
-	client     client.Interface
-	mapping    map[schema.GroupVersionResource]map[Status]Expression
-	restMapper *meta.DefaultRESTMapper
+	client  client.Interface
+	mapping map[schema.GroupVersionKind]map[Status]Expression
 }
 
 // NewStatusMap creates a cluster-wide StatusMap. It iterates over all
 // CustomResourceDefinitions in the cluster that are annotated with the
 // airshipit.org/status-check annotation and creates a mapping from the
-// GroupVersionResource to the various statuses and their associated
+// GroupVersionKind to the various statuses and their associated
 // expressions.
 func NewStatusMap(client client.Interface) (*StatusMap, error) {
 	statusMap := &StatusMap{
-		client:     client,
-		mapping:    make(map[schema.GroupVersionResource]map[Status]Expression),
-		restMapper: meta.NewDefaultRESTMapper([]schema.GroupVersion{}),
+		client:  client,
+		mapping: make(map[schema.GroupVersionKind]map[Status]Expression),
 	}
 
 	crds, err := statusMap.client.ApiextensionsClientSet().
@@ -78,21 +75,16 @@ func NewStatusMap(client client.Interface) (*StatusMap, error) {
 func (sm *StatusMap) GetStatusForResource(resource document.Document) (Status, error) {
 	gvk := getGVK(resource)
 
-	restMapping, err := sm.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
+	expressionMap, ok := sm.mapping[gvk]
+	if !ok {
 		return "", ErrResourceNotFound{resource.GetName()}
 	}
 
-	gvr := restMapping.Resource
-	obj, err := sm.client.DynamicClient().Resource(gvr).Namespace(resource.GetNamespace()).
-		Get(resource.GetName(), metav1.GetOptions{})
+	obj, err := sm.client.Get(gvk, resource.GetNamespace(), resource.GetName())
 	if err != nil {
 		return "", err
 	}
 
-	// No need to check for existence - if there isn't a mapping for this
-	// resource, the following for loop won't run anyway
-	expressionMap := sm.mapping[gvr]
 	for status, expression := range expressionMap {
 		matched, err := expression.Match(obj)
 		if err != nil {
@@ -120,30 +112,16 @@ func (sm *StatusMap) addCRD(crd apiextensions.CustomResourceDefinition) error {
 		return err
 	}
 
-	gvrs := getGVRs(crd)
-	for _, gvr := range gvrs {
-		gvk := gvr.GroupVersion().WithKind(crd.Spec.Names.Kind)
-		gvrSingular := gvr.GroupVersion().WithResource(crd.Spec.Names.Singular)
-		sm.mapping[gvr] = statusChecks
-		sm.restMapper.AddSpecific(gvk, gvr, gvrSingular, meta.RESTScopeNamespace)
-	}
-
-	return nil
-}
-
-// getGVRs constructs a slice of schema.GroupVersionResource for
-// CustomResources defined by the CustomResourceDefinition.
-func getGVRs(crd apiextensions.CustomResourceDefinition) []schema.GroupVersionResource {
-	gvrs := make([]schema.GroupVersionResource, 0, len(crd.Spec.Versions))
 	for _, version := range crd.Spec.Versions {
-		gvr := schema.GroupVersionResource{
-			Group:    crd.Spec.Group,
-			Version:  version.Name,
-			Resource: crd.Spec.Names.Plural,
+		gvk := schema.GroupVersionKind{
+			Group:   crd.Spec.Group,
+			Version: version.Name,
+			Kind:    crd.Spec.Names.Kind,
 		}
-		gvrs = append(gvrs, gvr)
+		sm.mapping[gvk] = statusChecks
 	}
-	return gvrs
+
+	return nil
 }
 
 // getGVK constructs a schema.GroupVersionKind for a document
@@ -21,8 +21,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"opendev.org/airship/airshipctl/pkg/cluster"
 	"opendev.org/airship/airshipctl/pkg/document"
@@ -86,6 +88,7 @@ func TestGetStatusForResource(t *testing.T) {
 				ByName("stable-resource"),
 			client: fake.NewClient(
 				fake.WithCRDs(makeResourceCRD(annotationValidStatusCheck())),
+				fake.WithRESTMapper(resourceMapper()),
 				fake.WithDynamicObjects(makeResource("Resource", "stable-resource", "stable")),
 			),
 			expectedStatus: cluster.Status("Stable"),
@@ -97,6 +100,7 @@ func TestGetStatusForResource(t *testing.T) {
 				ByName("pending-resource"),
 			client: fake.NewClient(
 				fake.WithCRDs(makeResourceCRD(annotationValidStatusCheck())),
+				fake.WithRESTMapper(resourceMapper()),
 				fake.WithDynamicObjects(makeResource("Resource", "pending-resource", "pending")),
 			),
 			expectedStatus: cluster.Status("Pending"),
@@ -108,6 +112,7 @@ func TestGetStatusForResource(t *testing.T) {
 				ByName("unknown"),
 			client: fake.NewClient(
 				fake.WithCRDs(makeResourceCRD(annotationValidStatusCheck())),
+				fake.WithRESTMapper(resourceMapper()),
 				fake.WithDynamicObjects(makeResource("Resource", "unknown", "unknown")),
 			),
 			expectedStatus: cluster.UnknownStatus,
@@ -146,6 +151,13 @@ func TestGetStatusForResource(t *testing.T) {
 	}
 }
 
+// resourceMapper knows the custom resources created by makeResourceCRD
+func resourceMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Resource"}, meta.RESTScopeNamespace)
+	return mapper
+}
+
 func makeResource(kind, name, state string) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -0,0 +1,396 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// PausedAnnotation stops the Cluster API controllers from reconciling the
+// resource it is set on
+const PausedAnnotation = "cluster.x-k8s.io/paused"
+
+// Upgrader rolls the KubeadmControlPlanes of a cluster out to the version
+// and machine template of their documents
+type Upgrader struct {
+	Client       dynamic.Interface
+	Mapper       meta.RESTMapper
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives the changes made and the progress of the upgrades
+	Out io.Writer
+}
+
+// NewUpgrader returns an Upgrader reporting to stdout
+func NewUpgrader(c client.Interface, timeout time.Duration) *Upgrader {
+	return &Upgrader{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+// controlPlaneUpgrade is a KubeadmControlPlane being upgraded
+type controlPlaneUpgrade struct {
+	WatchTarget
+	version  string
+	progress string
+	done     bool
+}
+
+// Upgrade patches the live KubeadmControlPlanes of docs with the version
+// and infrastructure template of their documents, creating the machine
+// templates they refer to from docs if they don't exist yet, and blocks
+// until all of them finished rolling out. Namespaced documents without a
+// namespace are looked up in defaultNamespace.
+//
+// Running Upgrade again once the control planes were patched only follows
+// the rollouts, e.g. after a paused upgrade was resumed. An ErrUpgradePaused
+// is returned for control planes that are paused, as they won't roll out.
+func (u *Upgrader) Upgrade(docs []document.Document, defaultNamespace string) error {
+	desired, err := controlPlaneDocuments(docs, defaultNamespace)
+	if err != nil {
+		return err
+	}
+
+	upgrades := make([]*controlPlaneUpgrade, 0, len(desired))
+	var paused []string
+	for _, kcp := range desired {
+		upgrade, isPaused, patchErr := u.patchControlPlane(kcp, docs, defaultNamespace)
+		if patchErr != nil {
+			return patchErr
+		}
+		if isPaused {
+			fmt.Fprintf(u.Out, "%s: paused, the upgrade continues once it is resumed\n", upgrade)
+			paused = append(paused, upgrade.String())
+			continue
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	if len(paused) > 0 {
+		return ErrUpgradePaused{Resources: paused}
+	}
+
+	deadline := time.Now().Add(u.Timeout)
+	for {
+		var pending []string
+		for _, upgrade := range upgrades {
+			if upgrade.done {
+				continue
+			}
+			if err = u.checkRollout(upgrade); err != nil {
+				return err
+			}
+			if !upgrade.done {
+				pending = append(pending, upgrade.String())
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrUpgradeTimeout{Resources: pending}
+		}
+		time.Sleep(u.PollInterval)
+	}
+}
+
+// SetPaused pauses or resumes the live KubeadmControlPlanes of docs, so that
+// a rolling upgrade stops creating and deleting machines until it is resumed
+func (u *Upgrader) SetPaused(docs []document.Document, defaultNamespace string, paused bool) error {
+	desired, err := controlPlaneDocuments(docs, defaultNamespace)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	action := "resumed"
+	if paused {
+		value, action = "true", "paused"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{PausedAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, kcp := range desired {
+		target := targetOf(kcp)
+		resource, resErr := u.resource(target.GVK, target.Namespace)
+		if resErr != nil {
+			return resErr
+		}
+		if _, err = resource.Patch(target.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(u.Out, "%s: %s\n", target, action)
+	}
+	return nil
+}
+
+// patchControlPlane brings the live version and infrastructure template of
+// kcp to the ones of its document
+func (u *Upgrader) patchControlPlane(kcp *unstructured.Unstructured, docs []document.Document,
+	defaultNamespace string) (*controlPlaneUpgrade, bool, error) {
+	upgrade := &controlPlaneUpgrade{WatchTarget: targetOf(kcp)}
+	upgrade.version, _, _ = unstructured.NestedString(kcp.Object, "spec", "version")
+	template, _, _ := unstructured.NestedMap(kcp.Object, "spec", "infrastructureTemplate")
+
+	resource, err := u.resource(upgrade.GVK, upgrade.Namespace)
+	if err != nil {
+		return nil, false, err
+	}
+	live, err := resource.Get(upgrade.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	_, isPaused := live.GetAnnotations()[PausedAnnotation]
+
+	liveVersion, _, _ := unstructured.NestedString(live.Object, "spec", "version")
+	liveTemplate, _, _ := unstructured.NestedMap(live.Object, "spec", "infrastructureTemplate")
+	spec := map[string]interface{}{}
+	if upgrade.version != "" && upgrade.version != liveVersion {
+		spec["version"] = upgrade.version
+	}
+	if template != nil && (template["kind"] != liveTemplate["kind"] || template["name"] != liveTemplate["name"]) {
+		if err = ensureTemplate(u.Client, u.Mapper, u.Out, template, upgrade.Namespace, docs,
+			defaultNamespace); err != nil {
+			return nil, false, err
+		}
+		spec["infrastructureTemplate"] = template
+	}
+	if upgrade.version == "" {
+		upgrade.version = liveVersion
+	}
+	if len(spec) == 0 {
+		fmt.Fprintf(u.Out, "%s: version %s and machine template are up to date\n", upgrade, upgrade.version)
+		return upgrade, isPaused, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err = resource.Patch(upgrade.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, false, err
+	}
+	fmt.Fprintf(u.Out, "%s: upgrading from %s to %s with %s/%s\n", upgrade, liveVersion, upgrade.version,
+		template["kind"], template["name"])
+	return upgrade, isPaused, nil
+}
+
+// ensureTemplate creates the machine template referred to by ref from its
+// document, unless it already exists. Machine templates are immutable, so
+// upgrades refer to new ones. A reference without a namespace refers to a
+// template in the namespace of the resource using it, ownerNamespace.
+func ensureTemplate(c dynamic.Interface, mapper meta.RESTMapper, out io.Writer, ref map[string]interface{},
+	ownerNamespace string, docs []document.Document, defaultNamespace string) error {
+	apiVersion, _ := ref["apiVersion"].(string)
+	kind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return err
+	}
+	target := WatchTarget{GVK: gv.WithKind(kind), Namespace: ownerNamespace, Name: name}
+	if namespace, _ := ref["namespace"].(string); namespace != "" {
+		target.Namespace = namespace
+	}
+
+	resource, err := dynamicResource(c, mapper, target.GVK, target.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = resource.Get(target.Name, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	for _, doc := range docs {
+		if doc.GetKind() != kind || doc.GetName() != name || doc.GetGroup() != gv.Group {
+			continue
+		}
+		obj, convErr := unstructuredDocument(doc, defaultNamespace)
+		if convErr != nil {
+			return convErr
+		}
+		if obj.GetNamespace() != target.Namespace {
+			continue
+		}
+		if _, err = resource.Create(obj, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: created\n", target)
+		return nil
+	}
+	return ErrMachineTemplateNotFound{Template: target.String()}
+}
+
+// checkRollout reports the progress of upgrade since the last check. The
+// rollout is complete once all machines of the control plane run its
+// version and the KubeadmControlPlane reports all replicas updated and
+// ready, without the extra machine of a rolling upgrade.
+func (u *Upgrader) checkRollout(upgrade *controlPlaneUpgrade) error {
+	resource, err := u.resource(upgrade.GVK, upgrade.Namespace)
+	if err != nil {
+		return err
+	}
+	live, err := resource.Get(upgrade.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if message, _, _ := unstructured.NestedString(live.Object, "status", "failureMessage"); message != "" {
+		reason, _, _ := unstructured.NestedString(live.Object, "status", "failureReason")
+		return ErrUpgradeFailed{Resource: upgrade.String(), Reason: reason, Message: message}
+	}
+
+	desired, _, _ := unstructured.NestedInt64(live.Object, "spec", "replicas")
+	replicas, _, _ := unstructured.NestedInt64(live.Object, "status", "replicas")
+	updated, _, _ := unstructured.NestedInt64(live.Object, "status", "updatedReplicas")
+	ready, _, _ := unstructured.NestedInt64(live.Object, "status", "readyReplicas")
+	upgraded, err := u.upgradedMachines(live, upgrade.version)
+	if err != nil {
+		return err
+	}
+
+	progress := fmt.Sprintf("%d/%d machines at %s, %d updated, %d ready", upgraded, desired, upgrade.version,
+		updated, ready)
+	if _, isPaused := live.GetAnnotations()[PausedAnnotation]; isPaused {
+		progress += " (paused)"
+	}
+	if progress != upgrade.progress {
+		upgrade.progress = progress
+		fmt.Fprintf(u.Out, "%s: %s\n", upgrade, progress)
+	}
+
+	if int64(upgraded) == desired && replicas == desired && updated == desired && ready == desired {
+		upgrade.done = true
+		fmt.Fprintf(u.Out, "%s: upgrade to %s complete\n", upgrade, upgrade.version)
+	}
+	return nil
+}
+
+// upgradedMachines returns the number of Machines owned by kcp running
+// version
+func (u *Upgrader) upgradedMachines(kcp *unstructured.Unstructured, version string) (int, error) {
+	resource, err := u.resource(MachineGVK, kcp.GetNamespace())
+	if err != nil {
+		return 0, err
+	}
+	machines, err := resource.List(metav1.ListOptions{LabelSelector: controlPlaneLabel})
+	if err != nil {
+		return 0, err
+	}
+
+	upgraded := 0
+	for _, machine := range machines.Items {
+		if !ownedBy(machine, kcp) {
+			continue
+		}
+		machineVersion, _, _ := unstructured.NestedString(machine.Object, "spec", "version")
+		if machineVersion == version {
+			upgraded++
+		}
+	}
+	return upgraded, nil
+}
+
+func (u *Upgrader) resource(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	return dynamicResource(u.Client, u.Mapper, gvk, namespace)
+}
+
+// dynamicResource returns the resource of kind gvk in namespace, or the
+// cluster scoped resource of the kind
+func dynamicResource(c dynamic.Interface, mapper meta.RESTMapper, gvk schema.GroupVersionKind,
+	namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return c.Resource(mapping.Resource), nil
+	}
+	return c.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// controlPlaneDocuments returns the KubeadmControlPlanes of docs, with
+// defaultNamespace set on the ones without a namespace
+func controlPlaneDocuments(docs []document.Document, defaultNamespace string) ([]*unstructured.Unstructured, error) {
+	var controlPlanes []*unstructured.Unstructured
+	for _, doc := range docs {
+		if doc.GetKind() != KubeadmControlPlaneGVK.Kind || doc.GetGroup() != KubeadmControlPlaneGVK.Group {
+			continue
+		}
+		obj, err := unstructuredDocument(doc, defaultNamespace)
+		if err != nil {
+			return nil, err
+		}
+		controlPlanes = append(controlPlanes, obj)
+	}
+	if len(controlPlanes) == 0 {
+		return nil, ErrNoControlPlanes{}
+	}
+	return controlPlanes, nil
+}
+
+func unstructuredDocument(doc document.Document, defaultNamespace string) (*unstructured.Unstructured, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err = obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(defaultNamespace)
+	}
+	return obj, nil
+}
+
+func targetOf(obj *unstructured.Unstructured) WatchTarget {
+	return WatchTarget{GVK: obj.GroupVersionKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+func ownedBy(obj unstructured.Unstructured, owner *unstructured.Unstructured) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == owner.GetKind() && ref.Name == owner.GetName() {
+			return true
+		}
+	}
+	return false
+}
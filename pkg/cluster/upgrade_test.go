@@ -0,0 +1,207 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+var machineTemplateGVK = schema.GroupVersionKind{
+	Group:   "infrastructure.cluster.x-k8s.io",
+	Version: "v1alpha3",
+	Kind:    "Metal3MachineTemplate",
+}
+
+const controlPlaneTarget = "KubeadmControlPlane/cluster-controlplane in namespace target-infra"
+
+func liveControlPlane(paused bool, status map[string]interface{}) *unstructured.Unstructured {
+	kcp := provisioningObject(cluster.KubeadmControlPlaneGVK, "cluster-controlplane",
+		map[string]interface{}{
+			"replicas": int64(3),
+			"version":  "v1.17.1",
+			"infrastructureTemplate": map[string]interface{}{
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+				"kind":       "Metal3MachineTemplate",
+				"name":       "cluster-controlplane-v1",
+			},
+		}, status, nil)
+	if paused {
+		kcp.SetAnnotations(map[string]string{cluster.PausedAnnotation: "true"})
+	}
+	return kcp
+}
+
+func controlPlaneMachine(name, version string) *unstructured.Unstructured {
+	machine := provisioningObject(cluster.MachineGVK, name, map[string]interface{}{"version": version},
+		map[string]interface{}{}, map[string]interface{}{"cluster.x-k8s.io/control-plane": ""})
+	machine.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "KubeadmControlPlane",
+		Name:       "cluster-controlplane",
+	}})
+	return machine
+}
+
+func rolledOut() map[string]interface{} {
+	return map[string]interface{}{
+		"replicas":        int64(3),
+		"updatedReplicas": int64(3),
+		"readyReplicas":   int64(3),
+	}
+}
+
+func newTestUpgrader(objects ...runtime.Object) (*cluster.Upgrader, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	c := fake.NewClient(
+		fake.WithRESTMapper(provisioningMapper(cluster.KubeadmControlPlaneGVK, cluster.MachineGVK, machineTemplateGVK)),
+		fake.WithDynamicObjects(objects...))
+	return &cluster.Upgrader{
+		Client:       c.DynamicClient(),
+		Mapper:       c.RESTMapper(),
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Out:          out,
+	}, out
+}
+
+func testDocuments(t *testing.T, path string) []document.Document {
+	docs, err := testutil.NewTestBundle(t, path).GetAllDocuments()
+	require.NoError(t, err)
+	return docs
+}
+
+func TestUpgrade(t *testing.T) {
+	upgrader, out := newTestUpgrader(
+		liveControlPlane(false, rolledOut()),
+		controlPlaneMachine("cp-1", "v1.18.3"),
+		controlPlaneMachine("cp-2", "v1.18.3"),
+		controlPlaneMachine("cp-3", "v1.18.3"),
+	)
+
+	require.NoError(t, upgrader.Upgrade(testDocuments(t, "testdata/upgrade"), "target-infra"))
+	assert.Equal(t, ""+
+		"Metal3MachineTemplate/cluster-controlplane-v2 in namespace target-infra: created\n"+
+		controlPlaneTarget+": upgrading from v1.17.1 to v1.18.3 with Metal3MachineTemplate/cluster-controlplane-v2\n"+
+		controlPlaneTarget+": 3/3 machines at v1.18.3, 3 updated, 3 ready\n"+
+		controlPlaneTarget+": upgrade to v1.18.3 complete\n", out.String())
+
+	kcp, err := upgrader.Client.Resource(schema.GroupVersionResource{
+		Group:    "controlplane.cluster.x-k8s.io",
+		Version:  "v1alpha3",
+		Resource: "kubeadmcontrolplanes",
+	}).Namespace("target-infra").Get("cluster-controlplane", metav1.GetOptions{})
+	require.NoError(t, err)
+	version, _, _ := unstructured.NestedString(kcp.Object, "spec", "version")
+	template, _, _ := unstructured.NestedString(kcp.Object, "spec", "infrastructureTemplate", "name")
+	assert.Equal(t, "v1.18.3", version)
+	assert.Equal(t, "cluster-controlplane-v2", template)
+
+	// Upgrading again only follows the rollout
+	out.Reset()
+	require.NoError(t, upgrader.Upgrade(testDocuments(t, "testdata/upgrade"), "target-infra"))
+	assert.Equal(t, ""+
+		controlPlaneTarget+": version v1.18.3 and machine template are up to date\n"+
+		controlPlaneTarget+": 3/3 machines at v1.18.3, 3 updated, 3 ready\n"+
+		controlPlaneTarget+": upgrade to v1.18.3 complete\n", out.String())
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		docs        string
+		objects     []runtime.Object
+		expectedErr error
+	}{
+		{
+			name: "timeout",
+			docs: "testdata/upgrade",
+			objects: []runtime.Object{
+				liveControlPlane(false, rolledOut()),
+				controlPlaneMachine("cp-1", "v1.18.3"),
+				controlPlaneMachine("cp-2", "v1.17.1"),
+				controlPlaneMachine("cp-3", "v1.17.1"),
+			},
+			expectedErr: cluster.ErrUpgradeTimeout{Resources: []string{controlPlaneTarget}},
+		},
+		{
+			name:        "paused",
+			docs:        "testdata/upgrade",
+			objects:     []runtime.Object{liveControlPlane(true, rolledOut())},
+			expectedErr: cluster.ErrUpgradePaused{Resources: []string{controlPlaneTarget}},
+		},
+		{
+			name: "failed",
+			docs: "testdata/upgrade",
+			objects: []runtime.Object{liveControlPlane(false, map[string]interface{}{
+				"failureReason":  "UpdateError",
+				"failureMessage": "cannot upgrade etcd",
+			})},
+			expectedErr: cluster.ErrUpgradeFailed{
+				Resource: controlPlaneTarget,
+				Reason:   "UpdateError",
+				Message:  "cannot upgrade etcd",
+			},
+		},
+		{
+			name:    "missing-template",
+			docs:    "testdata/upgrade-missing-template",
+			objects: []runtime.Object{liveControlPlane(false, rolledOut())},
+			expectedErr: cluster.ErrMachineTemplateNotFound{
+				Template: "Metal3MachineTemplate/cluster-controlplane-v3 in namespace target-infra",
+			},
+		},
+		{
+			name:        "no-control-plane",
+			docs:        "testdata/diff",
+			expectedErr: cluster.ErrNoControlPlanes{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			upgrader, _ := newTestUpgrader(tt.objects...)
+			err := upgrader.Upgrade(testDocuments(t, tt.docs), "target-infra")
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestUpgradeSetPaused(t *testing.T) {
+	upgrader, out := newTestUpgrader(liveControlPlane(false, rolledOut()))
+	docs := testDocuments(t, "testdata/upgrade")
+
+	require.NoError(t, upgrader.SetPaused(docs, "target-infra", true))
+	assert.Equal(t, cluster.ErrUpgradePaused{Resources: []string{controlPlaneTarget}},
+		upgrader.Upgrade(docs, "target-infra"))
+
+	require.NoError(t, upgrader.SetPaused(docs, "target-infra", false))
+	assert.Contains(t, out.String(), controlPlaneTarget+": paused\n")
+	assert.Contains(t, out.String(), controlPlaneTarget+": resumed\n")
+}
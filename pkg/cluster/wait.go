@@ -0,0 +1,156 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// reachableTimeout bounds each attempt to reach the API server of a cluster
+const reachableTimeout = 10 * time.Second
+
+// ControlPlaneWaiter waits for the control plane of a workload cluster to be
+// initialized and for its API server to answer. It is meant to sit between
+// the phases that provision a cluster and the ones that use it, e.g. before
+// moving the Cluster API objects to the new cluster.
+type ControlPlaneWaiter struct {
+	Client       client.Interface
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives a line every time the state of the control plane changes
+	Out io.Writer
+	// Reachable returns an error if the API server of kubeconfig can't be
+	// reached, it requests the version of the server unless set otherwise
+	Reachable func(kubeconfig []byte) error
+}
+
+// NewControlPlaneWaiter returns a ControlPlaneWaiter reporting to stdout
+func NewControlPlaneWaiter(c client.Interface, timeout time.Duration) *ControlPlaneWaiter {
+	return &ControlPlaneWaiter{
+		Client:       c,
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+		Reachable:    serverReachable,
+	}
+}
+
+// WaitForControlPlane blocks until the Cluster name in namespace reports its
+// control plane initialized, its kubeconfig Secret exists and the API server
+// of that kubeconfig answers. An ErrControlPlaneTimeout is returned if that
+// doesn't happen in time.
+func (w *ControlPlaneWaiter) WaitForControlPlane(namespace, name string) error {
+	target := WatchTarget{GVK: ClusterGVK, Namespace: namespace, Name: name}
+	start := time.Now()
+	var last string
+	for {
+		state, ready, err := w.controlPlaneState(namespace, name)
+		if err != nil {
+			return err
+		}
+
+		elapsed := time.Since(start)
+		if state != last {
+			fmt.Fprintf(w.Out, "[%s] %s: %s\n", elapsed.Round(time.Second), target, state)
+			last = state
+		}
+
+		switch {
+		case ready:
+			return nil
+		case elapsed >= w.Timeout:
+			return ErrControlPlaneTimeout{Cluster: target.String(), Timeout: w.Timeout, State: state}
+		}
+		time.Sleep(w.PollInterval)
+	}
+}
+
+// controlPlaneState describes how far the control plane of a cluster got
+// and reports whether it is ready
+func (w *ControlPlaneWaiter) controlPlaneState(namespace, name string) (string, bool, error) {
+	cluster, err := w.Client.Get(ClusterGVK, namespace, name)
+	switch {
+	case apierrors.IsNotFound(err):
+		return "waiting for the Cluster to be created", false, nil
+	case err != nil && client.IsTransient(err):
+		return "waiting for the management cluster to be reachable", false, nil
+	case err != nil:
+		return "", false, err
+	}
+
+	initialized, _, _ := unstructured.NestedBool(cluster.Object, "status", "controlPlaneInitialized")
+	if !initialized {
+		state := "waiting for the control plane to be initialized"
+		if replicas, ok := w.controlPlaneReplicas(cluster); ok {
+			state += fmt.Sprintf(" (%s)", replicas)
+		}
+		return state, false, nil
+	}
+
+	kubeconfig, err := GetKubeconfig(w.Client, namespace, name)
+	if _, ok := err.(ErrKubeconfigNotFound); ok {
+		return "waiting for the kubeconfig Secret", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if err = w.Reachable(kubeconfig); err != nil {
+		return "waiting for the API server to be reachable", false, nil
+	}
+	return "control plane is ready", true, nil
+}
+
+// controlPlaneReplicas describes the replicas of the KubeadmControlPlane of
+// cluster, if it has one
+func (w *ControlPlaneWaiter) controlPlaneReplicas(cluster *unstructured.Unstructured) (string, bool) {
+	targets := controlPlaneTarget(cluster)
+	if len(targets) == 0 || targets[0].GVK.GroupKind() != KubeadmControlPlaneGVK.GroupKind() {
+		return "", false
+	}
+	kcp, err := w.Client.Get(KubeadmControlPlaneGVK, targets[0].Namespace, targets[0].Name)
+	if err != nil {
+		return "", false
+	}
+	replicas, _, _ := unstructured.NestedInt64(kcp.Object, "spec", "replicas")
+	ready, _, _ := unstructured.NestedInt64(kcp.Object, "status", "readyReplicas")
+	return fmt.Sprintf("%d/%d control plane machines ready", ready, replicas), true
+}
+
+// serverReachable requests the version of the API server of kubeconfig
+func serverReachable(kubeconfig []byte) error {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	config.Timeout = reachableTimeout
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	_, err = clientSet.Discovery().ServerVersion()
+	return err
+}
@@ -0,0 +1,119 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+const targetClusterName = "Cluster/target-cluster in namespace target-infra"
+
+func waitedCluster(initialized bool) runtime.Object {
+	c := deletedCluster()
+	c.Object["status"] = map[string]interface{}{"controlPlaneInitialized": initialized}
+	return c
+}
+
+func TestWaitForControlPlane(t *testing.T) {
+	controlPlane := provisioningObject(cluster.KubeadmControlPlaneGVK, "target-cp",
+		map[string]interface{}{"replicas": int64(3)},
+		map[string]interface{}{"readyReplicas": int64(1)}, nil)
+	secret := kubeconfigSecret("target-cluster-kubeconfig", map[string][]byte{"value": []byte("apiVersion: v1\n")})
+	unreachable := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		typedObjects []runtime.Object
+		reachableErr error
+		expectedOut  string
+		expectedErr  error
+	}{
+		{
+			name:         "ready",
+			objects:      []runtime.Object{waitedCluster(true)},
+			typedObjects: []runtime.Object{secret},
+			expectedOut:  "[0s] " + targetClusterName + ": control plane is ready\n",
+		},
+		{
+			name:        "no-cluster",
+			expectedOut: "[0s] " + targetClusterName + ": waiting for the Cluster to be created\n",
+			expectedErr: cluster.ErrControlPlaneTimeout{
+				Cluster: targetClusterName,
+				State:   "waiting for the Cluster to be created",
+			},
+		},
+		{
+			name:    "not-initialized",
+			objects: []runtime.Object{waitedCluster(false), controlPlane},
+			expectedOut: "[0s] " + targetClusterName + ": waiting for the control plane to be initialized " +
+				"(1/3 control plane machines ready)\n",
+			expectedErr: cluster.ErrControlPlaneTimeout{
+				Cluster: targetClusterName,
+				State:   "waiting for the control plane to be initialized (1/3 control plane machines ready)",
+			},
+		},
+		{
+			name:        "no-kubeconfig",
+			objects:     []runtime.Object{waitedCluster(true)},
+			expectedOut: "[0s] " + targetClusterName + ": waiting for the kubeconfig Secret\n",
+			expectedErr: cluster.ErrControlPlaneTimeout{
+				Cluster: targetClusterName,
+				State:   "waiting for the kubeconfig Secret",
+			},
+		},
+		{
+			name:         "unreachable",
+			objects:      []runtime.Object{waitedCluster(true)},
+			typedObjects: []runtime.Object{secret},
+			reachableErr: unreachable,
+			expectedOut:  "[0s] " + targetClusterName + ": waiting for the API server to be reachable\n",
+			expectedErr: cluster.ErrControlPlaneTimeout{
+				Cluster: targetClusterName,
+				State:   "waiting for the API server to be reachable",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			waiter := &cluster.ControlPlaneWaiter{
+				Client: fake.NewClient(
+					fake.WithRESTMapper(provisioningMapper(cluster.ClusterGVK, cluster.KubeadmControlPlaneGVK)),
+					fake.WithDynamicObjects(tt.objects...),
+					fake.WithTypedObjects(tt.typedObjects...)),
+				PollInterval: time.Millisecond,
+				Out:          out,
+				Reachable: func(kubeconfig []byte) error {
+					assert.Equal(t, "apiVersion: v1\n", string(kubeconfig))
+					return tt.reachableErr
+				},
+			}
+			assert.Equal(t, tt.expectedErr, waiter.WaitForControlPlane("target-infra", "target-cluster"))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,262 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// WatchTarget is a resource followed by a Watcher. An empty version of
+// GVK is resolved to the preferred version of the cluster.
+type WatchTarget struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+func (t WatchTarget) String() string {
+	if t.Namespace == "" {
+		return fmt.Sprintf("%s/%s", t.GVK.Kind, t.Name)
+	}
+	return fmt.Sprintf("%s/%s in namespace %s", t.GVK.Kind, t.Name, t.Namespace)
+}
+
+// ParseWatchTarget parses a target given as KIND/name or KIND/namespace/name,
+// where KIND is Kind or Kind.group, e.g. BareMetalHost.metal3.io/metal3/node01.
+// Kinds without a group are looked up in all groups.
+func ParseWatchTarget(value string) (WatchTarget, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return WatchTarget{}, ErrInvalidWatchTarget{Value: value}
+	}
+	for _, part := range parts {
+		if part == "" {
+			return WatchTarget{}, ErrInvalidWatchTarget{Value: value}
+		}
+	}
+
+	t := WatchTarget{GVK: schema.ParseGroupKind(parts[0]).WithVersion(""), Name: parts[len(parts)-1]}
+	if len(parts) == 3 {
+		t.Namespace = parts[1]
+	}
+	return t, nil
+}
+
+// WatchTargetsFromDocuments returns a target for every document
+func WatchTargetsFromDocuments(docs []document.Document) []WatchTarget {
+	targets := make([]WatchTarget, 0, len(docs))
+	for _, doc := range docs {
+		targets = append(targets, WatchTarget{
+			GVK:       schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()},
+			Namespace: doc.GetNamespace(),
+			Name:      doc.GetName(),
+		})
+	}
+	return targets
+}
+
+// WatchCondition is the status condition a Watcher waits for
+type WatchCondition struct {
+	Type   string
+	Status string
+}
+
+func (c WatchCondition) String() string {
+	return c.Type + "=" + c.Status
+}
+
+// ParseWatchCondition parses a condition given as Type[=Status], the status
+// defaults to "True"
+func ParseWatchCondition(value string) (WatchCondition, error) {
+	parts := strings.SplitN(value, "=", 2)
+	c := WatchCondition{Type: parts[0], Status: string(metav1.ConditionTrue)}
+	if len(parts) == 2 {
+		c.Status = parts[1]
+	}
+	if c.Type == "" || c.Status == "" {
+		return WatchCondition{}, ErrInvalidWatchCondition{Value: value}
+	}
+	return c, nil
+}
+
+// Watcher follows the status conditions of resources and reports every
+// transition until all of them reach the target condition
+type Watcher struct {
+	Client       client.Interface
+	Condition    WatchCondition
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives a line for every condition transition
+	Out io.Writer
+}
+
+// NewWatcher returns a Watcher reporting transitions to stdout
+func NewWatcher(c client.Interface, condition WatchCondition, timeout time.Duration) *Watcher {
+	return &Watcher{
+		Client:       c,
+		Condition:    condition,
+		Timeout:      timeout,
+		PollInterval: status.DefaultPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+type watchedResource struct {
+	WatchTarget
+	found      bool
+	seen       bool
+	conditions map[string]string
+	done       bool
+}
+
+// Watch blocks until all targets have the condition of the Watcher. Namespaced
+// targets without a namespace are looked up in defaultNamespace. An
+// ErrWatchTimeout is returned if some of them don't get there in time.
+func (w *Watcher) Watch(targets []WatchTarget, defaultNamespace string) error {
+	resources := make([]*watchedResource, 0, len(targets))
+	for _, t := range targets {
+		resolved, err := w.resolve(t, defaultNamespace)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, &watchedResource{WatchTarget: resolved, conditions: map[string]string{}})
+	}
+
+	deadline := time.Now().Add(w.Timeout)
+	for {
+		var pending []string
+		for _, r := range resources {
+			if r.done {
+				continue
+			}
+			if err := w.check(r); err != nil {
+				return err
+			}
+			if !r.done {
+				pending = append(pending, r.String())
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrWatchTimeout{Resources: pending, Condition: w.Condition.String()}
+		}
+		time.Sleep(w.PollInterval)
+	}
+}
+
+// resolve fills in the version and the namespace of a target. Kinds
+// without a group are looked up in all groups.
+func (w *Watcher) resolve(t WatchTarget, defaultNamespace string) (WatchTarget, error) {
+	mapper := w.Client.RESTMapper()
+	var versions []string
+	if t.GVK.Version != "" {
+		versions = append(versions, t.GVK.Version)
+	}
+	mapping, err := mapper.RESTMapping(t.GVK.GroupKind(), versions...)
+	if meta.IsNoMatchError(err) && t.GVK.Group == "" {
+		var gvk schema.GroupVersionKind
+		gvk, err = mapper.KindFor(schema.GroupVersionResource{Resource: strings.ToLower(t.GVK.Kind)})
+		if err == nil {
+			mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	if err != nil {
+		return WatchTarget{}, err
+	}
+
+	t.GVK = mapping.GroupVersionKind
+	switch {
+	case mapping.Scope.Name() == meta.RESTScopeNameRoot:
+		t.Namespace = ""
+	case t.Namespace == "":
+		t.Namespace = defaultNamespace
+	}
+	return t, nil
+}
+
+// check reports the transitions of r since the last check
+func (w *Watcher) check(r *watchedResource) error {
+	obj, err := w.Client.Get(r.GVK, r.Namespace, r.Name)
+	if apierrors.IsNotFound(err) {
+		if r.found || !r.seen {
+			fmt.Fprintf(w.Out, "%s: not found\n", r)
+		}
+		r.found, r.seen = false, true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !r.found && r.seen {
+		fmt.Fprintf(w.Out, "%s: created\n", r)
+	}
+	r.found, r.seen = true, true
+
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	for _, item := range conditions {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(c, "type")
+		condStatus, _, _ := unstructured.NestedString(c, "status")
+		reason, _, _ := unstructured.NestedString(c, "reason")
+		message, _, _ := unstructured.NestedString(c, "message")
+		if condType == "" {
+			continue
+		}
+
+		state := condStatus + reason + message
+		if r.conditions[condType] != state {
+			r.conditions[condType] = state
+			fmt.Fprintf(w.Out, "%s: %s\n", r, formatCondition(condType, condStatus, reason, message))
+		}
+		if condType == w.Condition.Type && condStatus == w.Condition.Status {
+			r.done = true
+		}
+	}
+	return nil
+}
+
+func formatCondition(condType, condStatus, reason, message string) string {
+	s := condType + "=" + condStatus
+	if reason != "" {
+		s += " (" + reason + ")"
+	}
+	if message != "" {
+		s += " " + message
+	}
+	return s
+}
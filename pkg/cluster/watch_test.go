@@ -0,0 +1,152 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func deploymentWithReady(name, ready, reason string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "test",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":   "Available",
+						"status": "True",
+					},
+					map[string]interface{}{
+						"type":   "Ready",
+						"status": ready,
+						"reason": reason,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestParseWatchTarget(t *testing.T) {
+	target, err := cluster.ParseWatchTarget("BareMetalHost.metal3.io/metal3/node01")
+	require.NoError(t, err)
+	assert.Equal(t, cluster.WatchTarget{
+		GVK:       schema.GroupVersionKind{Group: "metal3.io", Kind: "BareMetalHost"},
+		Namespace: "metal3",
+		Name:      "node01",
+	}, target)
+
+	target, err = cluster.ParseWatchTarget("Deployment/web")
+	require.NoError(t, err)
+	assert.Equal(t, cluster.WatchTarget{GVK: schema.GroupVersionKind{Kind: "Deployment"}, Name: "web"}, target)
+
+	for _, value := range []string{"Deployment", "Deployment/", "a/b/c/d"} {
+		_, err = cluster.ParseWatchTarget(value)
+		assert.Equal(t, cluster.ErrInvalidWatchTarget{Value: value}, err)
+	}
+}
+
+func TestParseWatchCondition(t *testing.T) {
+	condition, err := cluster.ParseWatchCondition("Ready")
+	require.NoError(t, err)
+	assert.Equal(t, cluster.WatchCondition{Type: "Ready", Status: "True"}, condition)
+
+	condition, err = cluster.ParseWatchCondition("Provisioned=False")
+	require.NoError(t, err)
+	assert.Equal(t, cluster.WatchCondition{Type: "Provisioned", Status: "False"}, condition)
+
+	_, err = cluster.ParseWatchCondition("Ready=")
+	assert.Equal(t, cluster.ErrInvalidWatchCondition{Value: "Ready="}, err)
+}
+
+func TestWatch(t *testing.T) {
+	ready := cluster.WatchCondition{Type: "Ready", Status: "True"}
+	tests := []struct {
+		name        string
+		targets     []string
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			name:    "condition-reached",
+			targets: []string{"Deployment/ready"},
+			expectedOut: "Deployment/ready in namespace test: Available=True\n" +
+				"Deployment/ready in namespace test: Ready=True (MinimumReplicasAvailable)\n",
+		},
+		{
+			name:    "condition-not-reached",
+			targets: []string{"Deployment.apps/test/progressing"},
+			expectedOut: "Deployment/progressing in namespace test: Available=True\n" +
+				"Deployment/progressing in namespace test: Ready=False (Progressing)\n",
+			expectedErr: cluster.ErrWatchTimeout{
+				Resources: []string{"Deployment/progressing in namespace test"},
+				Condition: "Ready=True",
+			},
+		},
+		{
+			name:        "not-found",
+			targets:     []string{"Deployment/missing"},
+			expectedOut: "Deployment/missing in namespace test: not found\n",
+			expectedErr: cluster.ErrWatchTimeout{
+				Resources: []string{"Deployment/missing in namespace test"},
+				Condition: "Ready=True",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			targets := make([]cluster.WatchTarget, 0, len(tt.targets))
+			for _, value := range tt.targets {
+				target, err := cluster.ParseWatchTarget(value)
+				require.NoError(t, err)
+				targets = append(targets, target)
+			}
+
+			out := &bytes.Buffer{}
+			apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+			mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{apps})
+			mapper.Add(apps.WithKind("Deployment"), meta.RESTScopeNamespace)
+			watcher := cluster.NewWatcher(fake.NewClient(
+				fake.WithRESTMapper(mapper),
+				fake.WithDynamicObjects(
+					deploymentWithReady("ready", "True", "MinimumReplicasAvailable"),
+					deploymentWithReady("progressing", "False", "Progressing"),
+				)), ready, 20*time.Millisecond)
+			watcher.PollInterval = time.Millisecond
+			watcher.Out = out
+
+			assert.Equal(t, tt.expectedErr, watcher.Watch(targets, "test"))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
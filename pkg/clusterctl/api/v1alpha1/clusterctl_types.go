@@ -35,13 +35,25 @@ type Clusterctl struct {
 	Providers   []*Provider  `json:"providers,omitempty"`
 	InitOptions *InitOptions `json:"init-options,omitempty"`
 	MoveOptions *MoveOptions `json:"move-options,omitempty"`
+
+	// AirGapped if set to true, every provider that is initialized must be declared in Providers
+	// and none of the provider repositories may point to GitHub, so that clusterctl never falls back
+	// to its built-in provider list or reaches out to the internet.
+	AirGapped bool `json:"air-gapped,omitempty"`
 }
 
 // Provider is part of clusterctl config
 type Provider struct {
 	Name string `json:"name,"`
 	Type string `json:"type,"`
-	URL  string `json:"url,omitempty"`
+	// URL of the components file of the provider, either a path on the local filesystem or an http(s)
+	// URL of an internal mirror, laid out as {basepath}/{provider-label}/{version}/{components.yaml}.
+	// ignored if IsClusterctlRepository is set to false
+	URL string `json:"url,omitempty"`
+
+	// Version pins the provider to a single version. It is used when init-options reference the
+	// provider without a version, and init-options requesting any other version are rejected.
+	Version string `json:"version,omitempty"`
 
 	// IsClusterctlRepository if set to true, clusterctl provider's repository implementation will be used
 	// if omitted or set to false, airshipctl repository implementation will be used.
@@ -63,6 +75,8 @@ type InitOptions struct {
 	BootstrapProviders []string `json:"bootstrap-providers,omitempty"`
 
 	// InfrastructureProviders and versions (e.g. aws:v0.5.0) to add to the management cluster.
+	// Several infrastructure providers (e.g. metal3 and docker) may be initialized side by side,
+	// each Cluster then selects the one it runs on through its infrastructureRef.
 	InfrastructureProviders []string `json:"infrastructure-providers,omitempty"`
 
 	// ControlPlaneProviders and versions (e.g. kubeadm:v0.3.0) to add to the management cluster.
@@ -34,7 +34,10 @@ var _ Interface = &Client{}
 // Interface is abstraction to Clusterctl
 type Interface interface {
 	Init(kubeconfigPath, kubeconfigContext string) error
-	Move(fromKubeconfigPath, fromKubeconfigContext, toKubeconfigPath, toKubeconfigContext, namespace string) error
+	Move(fromKubeconfigPath, fromKubeconfigContext, toKubeconfigPath, toKubeconfigContext,
+		namespace string) (*MoveReport, error)
+	PlanUpgrade(kubeconfigPath, kubeconfigContext string) ([]UpgradePlan, error)
+	ApplyUpgrade(kubeconfigPath, kubeconfigContext string) ([]UpgradePlan, error)
 }
 
 // Client Implements interface to Clusterctl
@@ -42,6 +45,7 @@ type Client struct {
 	clusterctlClient clusterctlclient.Client
 	initOptions      clusterctlclient.InitOptions
 	moveOptions      clusterctlclient.MoveOptions
+	options          *airshipv1.Clusterctl
 }
 
 // NewClient returns instance of clusterctl client
@@ -50,21 +54,18 @@ func NewClient(root string, debug bool, options *airshipv1.Clusterctl) (Interfac
 		debugVerbosity := 5
 		clog.SetLogger(clog.NewLogger(clog.WithThreshold(&debugVerbosity)))
 	}
-	initOptions := options.InitOptions
-	var cio clusterctlclient.InitOptions
-	if initOptions != nil {
-		cio = clusterctlclient.InitOptions{
-			BootstrapProviders:      initOptions.BootstrapProviders,
-			CoreProvider:            initOptions.CoreProvider,
-			InfrastructureProviders: initOptions.InfrastructureProviders,
-			ControlPlaneProviders:   initOptions.ControlPlaneProviders,
-		}
+	if err := validateProviders(options); err != nil {
+		return nil, err
+	}
+	cio, err := newInitOptions(options)
+	if err != nil {
+		return nil, err
 	}
 	cclient, err := newClusterctlClient(root, options)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{clusterctlClient: cclient, initOptions: cio}, nil
+	return &Client{clusterctlClient: cclient, initOptions: cio, options: options}, nil
 }
 
 // Init implements interface to Clusterctl
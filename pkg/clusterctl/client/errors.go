@@ -16,6 +16,7 @@ package client
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ErrProviderNotDefined is returned when wrong AuthType is provided
@@ -36,3 +37,87 @@ type ErrProviderRepoNotFound struct {
 func (e ErrProviderRepoNotFound) Error() string {
 	return fmt.Sprintf("failed to find repository for provider %s of type %s", e.ProviderName, e.ProviderType)
 }
+
+// ErrNothingToMove is returned when the namespace to move has no Cluster objects
+type ErrNothingToMove struct {
+	Namespace string
+}
+
+func (e ErrNothingToMove) Error() string {
+	return fmt.Sprintf("no Cluster objects to move in namespace %s", e.Namespace)
+}
+
+// ErrMovePreflightFailed is returned when the checks run before a move found problems, nothing is moved then
+type ErrMovePreflightFailed struct {
+	Namespace string
+	Problems  int
+}
+
+func (e ErrMovePreflightFailed) Error() string {
+	return fmt.Sprintf("preflight checks found %d problem(s) moving namespace %s, nothing was moved",
+		e.Problems, e.Namespace)
+}
+
+// ErrMoveVerificationFailed is returned when objects of the moved namespace are missing in the target cluster
+type ErrMoveVerificationFailed struct {
+	Namespace string
+	Kinds     []string
+}
+
+func (e ErrMoveVerificationFailed) Error() string {
+	return fmt.Sprintf("%s objects of namespace %s are missing in the target cluster after the move",
+		strings.Join(e.Kinds, ", "), e.Namespace)
+}
+
+// ErrProviderNotDeclared is returned when an air-gapped deployment uses a provider missing in Clusterctl document
+type ErrProviderNotDeclared struct {
+	ProviderName string
+	ProviderType string
+}
+
+func (e ErrProviderNotDeclared) Error() string {
+	return fmt.Sprintf("provider %s of type %s must be declared in Clusterctl document for air-gapped deployments",
+		e.ProviderName, e.ProviderType)
+}
+
+// ErrVersionNotPinned is returned when a provider version other than the pinned one is requested
+type ErrVersionNotPinned struct {
+	ProviderName  string
+	ProviderType  string
+	Version       string
+	PinnedVersion string
+}
+
+func (e ErrVersionNotPinned) Error() string {
+	if e.Version == "" {
+		return fmt.Sprintf("pinned version %s of provider %s of type %s is not defined in its versions",
+			e.PinnedVersion, e.ProviderName, e.ProviderType)
+	}
+	return fmt.Sprintf("version %s of provider %s of type %s doesn't match pinned version %s",
+		e.Version, e.ProviderName, e.ProviderType, e.PinnedVersion)
+}
+
+// ErrRemoteRepository is returned when an air-gapped deployment uses a provider repository hosted on GitHub
+type ErrRemoteRepository struct {
+	ProviderName string
+	ProviderType string
+	URL          string
+}
+
+func (e ErrRemoteRepository) Error() string {
+	return fmt.Sprintf("repository %s of provider %s of type %s can't be used for air-gapped deployments",
+		e.URL, e.ProviderName, e.ProviderType)
+}
+
+// ErrProviderDowngrade is returned when the Clusterctl document defines an older version than the installed one
+type ErrProviderDowngrade struct {
+	ProviderName   string
+	ProviderType   string
+	CurrentVersion string
+	TargetVersion  string
+}
+
+func (e ErrProviderDowngrade) Error() string {
+	return fmt.Sprintf("provider %s of type %s can't be downgraded from %s to %s",
+		e.ProviderName, e.ProviderType, e.CurrentVersion, e.TargetVersion)
+}
@@ -91,6 +91,18 @@ func (f RepositoryFactory) repoFactory(provider config.Provider) (repository.Cli
 			ProviderType: string(repoType),
 			ProviderName: name}, nil
 	}
+	// if repository is hosted on an internal mirror, construct an airshipctl http implementation of
+	// repository interface, since clusterctl supports only GitHub and local filesystem repositories
+	if isHTTPRepository(airProv.URL) {
+		repo, err := implementations.NewHTTPRepository(airProv.URL)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Creating airshipctl http repository implementation interface for provider %s of type %s\n",
+			name,
+			repoType)
+		return repository.New(provider, f.ConfigClient, repository.InjectRepository(repo))
+	}
 	log.Printf("Creating clusterctl repository implementation interface for provider %s of type %s\n",
 		name,
 		repoType)
@@ -15,6 +15,9 @@
 package client
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -286,3 +289,37 @@ func TestRepositoryFactoryErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoFactoryHTTPRepository(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir(filepath.Join(testDataDir, "mirror"))))
+	defer srv.Close()
+
+	o := testOptions(t, testConfigFactory)
+	o.Providers = append(o.Providers, &airshipv1.Provider{
+		Name:                   "mirrored-infra",
+		Type:                   "InfrastructureProvider",
+		URL:                    srv.URL + "/infrastructure-mirrored-infra/v0.3.1/infrastructure-components.yaml",
+		IsClusterctlRepository: true,
+	})
+	configClient := testNewConfig(t, o)
+	factory := RepositoryFactory{
+		root:         testDataDir,
+		Options:      o,
+		ConfigClient: configClient,
+	}
+	provider, err := configClient.Providers().Get("mirrored-infra", "InfrastructureProvider")
+	require.NoError(t, err)
+	repoClient, err := factory.repoFactory(provider)
+	require.NoError(t, err)
+	versions, err := repoClient.GetVersions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v0.3.1"}, versions)
+
+	component, err := repoClient.Components().Get(repository.ComponentsOptions{Version: "v0.3.1"})
+	require.NoError(t, err)
+	b, err := component.Yaml()
+	require.NoError(t, err)
+	actualNamespace := &v1.Namespace{}
+	require.NoError(t, yaml.Unmarshal(b, actualNamespace))
+	assert.Equal(t, "mirrored", actualNamespace.GetName())
+}
@@ -34,9 +34,12 @@ func init() {
 	bmoapis.AddToScheme(cluster.Scheme)
 }
 
-// Move implements interface to Clusterctl
+// Move implements interface to Clusterctl. The move is validated before any object is touched, and the objects of
+// the namespace that arrived in the target cluster are counted afterwards. The report of that verification is
+// returned once the objects were moved, along with ErrMoveVerificationFailed if objects are missing. If the preflight
+// checks find problems, the report lists them and ErrMovePreflightFailed is returned without moving anything.
 func (c *Client) Move(fromKubeconfigPath, fromKubeconfigContext,
-	toKubeconfigPath, toKubeconfigContext, namespace string) error {
+	toKubeconfigPath, toKubeconfigContext, namespace string) (*MoveReport, error) {
 	ctx := context.TODO()
 	var err error
 	// ephemeral cluster client
@@ -45,7 +48,7 @@ func (c *Client) Move(fromKubeconfigPath, fromKubeconfigContext,
 		Context: fromKubeconfigContext}, nil).Proxy()
 	cFrom, err := pFrom.NewClient()
 	if err != nil {
-		return errors.Wrap(err, "failed to create ephemeral cluster client")
+		return nil, errors.Wrap(err, "failed to create ephemeral cluster client")
 	}
 	// target cluster client
 	pTo := cluster.New(cluster.Kubeconfig{
@@ -53,21 +56,33 @@ func (c *Client) Move(fromKubeconfigPath, fromKubeconfigContext,
 		Context: toKubeconfigContext}, nil).Proxy()
 	cTo, err := pTo.NewClient()
 	if err != nil {
-		return errors.Wrap(err, "failed to create target cluster client")
+		return nil, errors.Wrap(err, "failed to create target cluster client")
 	}
 	// If namespace is empty, try to detect it.
 	if namespace == "" {
 		var currentNamespace string
 		currentNamespace, err = pFrom.CurrentNamespace()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		namespace = currentNamespace
 	}
-	// Pause
-	err = pauseUnpauseBMHs(ctx, cFrom, namespace, true)
+	// Validate
+	before, preflight, err := validateMove(ctx, cFrom, cTo, namespace)
 	if err != nil {
-		return errors.Wrap(err, "failed to pause BareMetalHost objects")
+		return nil, err
+	}
+	if !preflight.Passed() {
+		return &MoveReport{Namespace: namespace, Preflight: preflight},
+			ErrMovePreflightFailed{Namespace: namespace, Problems: len(preflight.Problems)}
+	}
+	// BareMetalHost objects are paused while they are moved, so that the baremetal operator doesn't act on them
+	moveBMHs := before[bmhKind] > 0
+	if moveBMHs {
+		err = pauseUnpauseBMHs(ctx, cFrom, namespace, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to pause BareMetalHost objects")
+		}
 	}
 
 	// clusterctl move
@@ -78,19 +93,22 @@ func (c *Client) Move(fromKubeconfigPath, fromKubeconfigContext,
 	}
 	err = c.clusterctlClient.Move(c.moveOptions)
 	if err != nil {
-		return errors.Wrapf(err, "error during clusterctl move")
-	}
-	// Update BMH Status
-	err = copyBMHStatus(ctx, cFrom, cTo, namespace)
-	if err != nil {
-		return errors.Wrap(err, "failed to copy BareMetalHost Status")
+		return nil, errors.Wrapf(err, "error during clusterctl move")
 	}
-	// Unpause
-	err = pauseUnpauseBMHs(ctx, cFrom, namespace, false)
-	if err != nil {
-		return errors.Wrap(err, "failed to unpause BareMetalHost objects")
+	if moveBMHs {
+		// Update BMH Status
+		err = copyBMHStatus(ctx, cFrom, cTo, namespace)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to copy BareMetalHost Status")
+		}
+		// Unpause
+		err = pauseUnpauseBMHs(ctx, cFrom, namespace, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unpause BareMetalHost objects")
+		}
 	}
-	return err
+	// Verify
+	return verifyMove(ctx, cFrom, cTo, namespace, before)
 }
 
 // copyBMHStatus will copy the BareMetalHost Status field from a specific
@@ -0,0 +1,220 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	bmh "github.com/metal3-io/baremetal-operator/pkg/apis/metal3/v1alpha1"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// bmhCRD is the name of the CRD of BareMetalHost objects, which is installed with the baremetal operator rather than
+// by clusterctl.
+const bmhCRD = "baremetalhosts.metal3.io"
+
+// transitionalBMHStates are the provisioning states in which the baremetal operator is still working on a host, which
+// would be interrupted by moving it.
+var transitionalBMHStates = map[bmh.ProvisioningState]bool{
+	bmh.StateRegistering:    true,
+	bmh.StateInspecting:     true,
+	bmh.StateProvisioning:   true,
+	bmh.StateDeprovisioning: true,
+	bmh.StateDeleting:       true,
+}
+
+// PreflightProblem is a reason the objects of a namespace can't be moved yet, along with the action resolving it.
+type PreflightProblem struct {
+	// Object is the kind and name of the object the problem was found with, e.g. Machine/worker-1
+	Object  string
+	Problem string
+	Action  string
+}
+
+// PreflightReport lists the problems found by the checks run before a move. Nothing is moved unless it has none.
+type PreflightReport struct {
+	Namespace string
+	Problems  []PreflightProblem
+}
+
+// Passed reports whether the checks found no problem.
+func (r PreflightReport) Passed() bool {
+	return len(r.Problems) == 0
+}
+
+// Print writes the problems of the report to w as a table.
+func (r PreflightReport) Print(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "OBJECT\tNAMESPACE\tPROBLEM\tACTION")
+	for _, p := range r.Problems {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Object, r.Namespace, p.Problem, p.Action)
+	}
+	return tw.Flush()
+}
+
+func (r *PreflightReport) add(object, problem, action string) {
+	for _, p := range r.Problems {
+		if p.Object == object && p.Problem == problem {
+			return
+		}
+	}
+	r.Problems = append(r.Problems, PreflightProblem{Object: object, Problem: problem, Action: action})
+}
+
+// preflightMove checks that the target cluster serves the provider CRDs of the objects to move in the versions they
+// are stored in, and that none of the objects of namespace are still being reconciled. Moving them otherwise would
+// only fail once part of them reached the target cluster.
+func preflightMove(ctx context.Context, cFrom client.Client, cTo client.Client, namespace string,
+	counts map[string]int) (*PreflightReport, error) {
+	report := &PreflightReport{Namespace: namespace}
+	if err := checkCRDs(ctx, cFrom, cTo, report); err != nil {
+		return nil, err
+	}
+	// Sites may not run the metal3 infrastructure provider, e.g. virtual clusters only, the checks of BareMetalHost
+	// objects apply when there are any to move. Their CRD isn't labeled by clusterctl, so it isn't compared above.
+	if counts[bmhKind] > 0 {
+		if _, err := getBMHs(ctx, cTo, namespace); meta.IsNoMatchError(err) {
+			report.add("CustomResourceDefinition/"+bmhCRD, "not installed in the target cluster",
+				"deploy the baremetal operator to the target cluster")
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to list BareMetalHost objects of the target cluster")
+		}
+	}
+	if err := checkReconciling(ctx, cFrom, namespace, counts[bmhKind] > 0, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkCRDs compares the CRDs installed by clusterctl in both clusters. clusterctl moves the objects of each CRD in
+// its storage version, which the target cluster must serve.
+func checkCRDs(ctx context.Context, cFrom client.Client, cTo client.Client, report *PreflightReport) error {
+	fromCRDs := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := cFrom.List(ctx, fromCRDs, client.HasLabels{clusterctlv1.ClusterctlLabelName}); err != nil {
+		return errors.Wrap(err, "failed to list the provider CRDs of the ephemeral cluster")
+	}
+	toCRDs := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := cTo.List(ctx, toCRDs); err != nil {
+		return errors.Wrap(err, "failed to list the CRDs of the target cluster")
+	}
+
+	served := make(map[string]map[string]bool, len(toCRDs.Items))
+	for _, crd := range toCRDs.Items {
+		served[crd.Name] = map[string]bool{}
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				served[crd.Name][version.Name] = true
+			}
+		}
+	}
+
+	for _, crd := range fromCRDs.Items {
+		object := "CustomResourceDefinition/" + crd.Name
+		provider := crd.Labels[clusterv1.ProviderLabelName]
+		if provider == "" {
+			provider = "providing " + crd.Spec.Names.Kind
+		}
+		versions, installed := served[crd.Name]
+		if !installed {
+			report.add(object, "not installed in the target cluster",
+				fmt.Sprintf("initialize provider %s in the target cluster", provider))
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Storage && !versions[version.Name] {
+				report.add(object, fmt.Sprintf("version %s is not served by the target cluster", version.Name),
+					fmt.Sprintf("install the version of provider %s of the ephemeral cluster in the target cluster",
+						provider))
+			}
+		}
+	}
+	return nil
+}
+
+// checkReconciling looks for objects of namespace that their controllers are still working on.
+func checkReconciling(ctx context.Context, c client.Client, namespace string, withBMHs bool,
+	report *PreflightReport) error {
+	clusters := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusters, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, "failed to list Cluster objects")
+	}
+	for _, cluster := range clusters.Items {
+		object := "Cluster/" + cluster.Name
+		switch {
+		case cluster.DeletionTimestamp != nil:
+			report.add(object, "being deleted", "wait for the deletion to complete")
+		case !cluster.Status.InfrastructureReady:
+			report.add(object, "infrastructure is not ready", "wait for the infrastructure to be provisioned")
+		case !cluster.Status.ControlPlaneInitialized:
+			report.add(object, "control plane is not initialized",
+				"wait for the control plane, e.g. with airshipctl cluster wait "+cluster.Name)
+		}
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, "failed to list Machine objects")
+	}
+	for _, machine := range machines.Items {
+		object := "Machine/" + machine.Name
+		switch {
+		case machine.DeletionTimestamp != nil:
+			report.add(object, "being deleted", "wait for the deletion to complete")
+		case machine.Status.NodeRef == nil:
+			report.add(object, "has no node yet", "wait for the machine to be provisioned")
+		}
+	}
+
+	deployments := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, "failed to list MachineDeployment objects")
+	}
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas != replicas {
+			report.add("MachineDeployment/"+deployment.Name,
+				fmt.Sprintf("rolling out, %d/%d machines updated", deployment.Status.UpdatedReplicas, replicas),
+				"wait for the rollout to complete")
+		}
+	}
+
+	if !withBMHs {
+		return nil
+	}
+	hosts, err := getBMHs(ctx, c, namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list BareMetalHost objects")
+	}
+	for _, host := range hosts.Items {
+		if state := host.Status.Provisioning.State; transitionalBMHStates[state] {
+			report.add(bmhKind+"/"+host.Name, "still "+string(state),
+				"wait for the baremetal operator to settle the host")
+		}
+	}
+	return nil
+}
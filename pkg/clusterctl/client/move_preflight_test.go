@@ -0,0 +1,170 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+)
+
+func machinesCRD(storage string, served ...string) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "machines.cluster.x-k8s.io",
+			Labels: map[string]string{
+				clusterctlv1.ClusterctlLabelName: "",
+				clusterv1.ProviderLabelName:      "cluster-api",
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "cluster.x-k8s.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Machine"},
+		},
+	}
+	for _, version := range served {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    version,
+			Served:  true,
+			Storage: version == storage,
+		})
+	}
+	return crd
+}
+
+func Test_move_preflightMove(t *testing.T) {
+	replicas := int32(2)
+	deletedMachine := machine1.DeepCopy()
+	deletedMachine.Name = "machine2"
+	now := metav1.Now()
+	deletedMachine.DeletionTimestamp = &now
+	pendingMachine := machine1.DeepCopy()
+	pendingMachine.Name = "machine3"
+	pendingMachine.Status.NodeRef = nil
+	uninitializedCluster := cluster1.DeepCopy()
+	uninitializedCluster.Name = "cluster2"
+	uninitializedCluster.Status.ControlPlaneInitialized = false
+	rollingOut := &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "cluster.x-k8s.io/v1alpha3",
+			Kind:       "MachineDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workers",
+			Namespace: "ns1",
+		},
+		Spec:   clusterv1.MachineDeploymentSpec{Replicas: &replicas},
+		Status: clusterv1.MachineDeploymentStatus{UpdatedReplicas: 1},
+	}
+
+	tests := []struct {
+		name         string
+		from         []runtime.Object
+		to           []runtime.Object
+		wantProblems []PreflightProblem
+	}{
+		{
+			name: "passes when the target cluster serves the provider CRDs and objects are settled",
+			from: []runtime.Object{machinesCRD("v1alpha3", "v1alpha2", "v1alpha3"), cluster1, machine1},
+			to:   []runtime.Object{machinesCRD("v1alpha3", "v1alpha3")},
+		},
+		{
+			name: "reports a provider CRD missing in the target cluster",
+			from: []runtime.Object{machinesCRD("v1alpha3", "v1alpha3"), cluster1},
+			wantProblems: []PreflightProblem{{
+				Object:  "CustomResourceDefinition/machines.cluster.x-k8s.io",
+				Problem: "not installed in the target cluster",
+				Action:  "initialize provider cluster-api in the target cluster",
+			}},
+		},
+		{
+			name: "reports a storage version not served by the target cluster",
+			from: []runtime.Object{machinesCRD("v1alpha3", "v1alpha3"), cluster1},
+			to:   []runtime.Object{machinesCRD("v1alpha2", "v1alpha2")},
+			wantProblems: []PreflightProblem{{
+				Object:  "CustomResourceDefinition/machines.cluster.x-k8s.io",
+				Problem: "version v1alpha3 is not served by the target cluster",
+				Action:  "install the version of provider cluster-api of the ephemeral cluster in the target cluster",
+			}},
+		},
+		{
+			name: "reports objects being reconciled",
+			from: []runtime.Object{uninitializedCluster, deletedMachine, pendingMachine, rollingOut},
+			wantProblems: []PreflightProblem{
+				{
+					Object:  "Cluster/cluster2",
+					Problem: "control plane is not initialized",
+					Action:  "wait for the control plane, e.g. with airshipctl cluster wait cluster2",
+				},
+				{
+					Object:  "Machine/machine2",
+					Problem: "being deleted",
+					Action:  "wait for the deletion to complete",
+				},
+				{
+					Object:  "Machine/machine3",
+					Problem: "has no node yet",
+					Action:  "wait for the machine to be provisioned",
+				},
+				{
+					Object:  "MachineDeployment/workers",
+					Problem: "rolling out, 1/2 machines updated",
+					Action:  "wait for the rollout to complete",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			report, err := preflightMove(context.TODO(), newClientWithMoveObjects(tt.from...),
+				newClientWithMoveObjects(tt.to...), "ns1", map[string]int{"Cluster": 1})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(report.Problems).To(Equal(tt.wantProblems))
+			g.Expect(report.Passed()).To(Equal(len(tt.wantProblems) == 0))
+		})
+	}
+}
+
+func TestMoveReportPrintPreflight(t *testing.T) {
+	g := NewWithT(t)
+	report := MoveReport{
+		Namespace: "ns1",
+		Preflight: &PreflightReport{
+			Namespace: "ns1",
+			Problems: []PreflightProblem{
+				{Object: "Machine/machine2", Problem: "being deleted", Action: "wait for the deletion to complete"},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	g.Expect(report.Print(out)).To(Succeed())
+	g.Expect(out.String()).To(Equal(
+		"OBJECT             NAMESPACE   PROBLEM         ACTION\n" +
+			"Machine/machine2   ns1         being deleted   wait for the deletion to complete\n"))
+}
@@ -0,0 +1,174 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	bmh "github.com/metal3-io/baremetal-operator/pkg/apis/metal3/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// movedKinds are the kinds of objects counted to verify a move, with a function returning an empty list of them.
+var movedKinds = []struct {
+	kind    string
+	newList func() runtime.Object
+}{
+	{"Cluster", func() runtime.Object { return &clusterv1.ClusterList{} }},
+	{"Machine", func() runtime.Object { return &clusterv1.MachineList{} }},
+	{bmhKind, func() runtime.Object { return &bmh.BareMetalHostList{} }},
+}
+
+// bmhKind is the kind of the hosts of the metal3 infrastructure provider
+const bmhKind = "BareMetalHost"
+
+// MoveReport compares the objects of a moved namespace in the source cluster before the move with the objects in
+// the target cluster after it.
+type MoveReport struct {
+	Namespace string
+	Kinds     []MovedKind
+	// Preflight is set when the checks run before the move found problems, in which case nothing was moved
+	Preflight *PreflightReport
+}
+
+// MovedKind counts the objects of a kind involved in a move.
+type MovedKind struct {
+	Kind string
+	// Before is the number of objects in the source cluster before the move
+	Before int
+	// Source is the number of objects left in the source cluster after the move
+	Source int
+	// Target is the number of objects in the target cluster after the move
+	Target int
+}
+
+// Verified reports whether all objects of the kind arrived in the target cluster.
+func (k MovedKind) Verified() bool {
+	return k.Target >= k.Before
+}
+
+// Verified reports whether all objects of the namespace arrived in the target cluster.
+func (r MoveReport) Verified() bool {
+	for _, kind := range r.Kinds {
+		if !kind.Verified() {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes the report to w as a table, or the problems found before the move if it didn't take place.
+func (r MoveReport) Print(w io.Writer) error {
+	if r.Preflight != nil && !r.Preflight.Passed() {
+		return r.Preflight.Print(w)
+	}
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "KIND\tNAMESPACE\tBEFORE\tSOURCE\tTARGET\tSTATUS")
+	for _, kind := range r.Kinds {
+		status := "moved"
+		if !kind.Verified() {
+			status = "missing"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n", kind.Kind, r.Namespace, kind.Before, kind.Source, kind.Target, status)
+	}
+	return tw.Flush()
+}
+
+// validateMove checks that the objects of namespace can be moved from the cluster of cFrom to the cluster of cTo,
+// and returns the number of objects of each of movedKinds there are to move along with the report of the preflight
+// checks. clusterctl itself checks the providers of the target cluster.
+func validateMove(ctx context.Context, cFrom client.Client, cTo client.Client,
+	namespace string) (map[string]int, *PreflightReport, error) {
+	counts, err := countMovedKinds(ctx, cFrom, namespace)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list objects to move")
+	}
+	if counts["Cluster"] == 0 {
+		return nil, nil, ErrNothingToMove{Namespace: namespace}
+	}
+
+	report, err := preflightMove(ctx, cFrom, cTo, namespace, counts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Debugf("Moving %d Cluster, %d Machine and %d BareMetalHost objects of namespace %s",
+		counts["Cluster"], counts["Machine"], counts[bmhKind], namespace)
+	return counts, report, nil
+}
+
+// verifyMove counts the objects of namespace in both clusters after the move and compares them with the number of
+// objects before it.
+func verifyMove(ctx context.Context, cFrom client.Client, cTo client.Client, namespace string,
+	before map[string]int) (*MoveReport, error) {
+	source, err := countMovedKinds(ctx, cFrom, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list objects left in the ephemeral cluster")
+	}
+	target, err := countMovedKinds(ctx, cTo, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list objects moved to the target cluster")
+	}
+
+	report := &MoveReport{Namespace: namespace}
+	var missing []string
+	for _, moved := range movedKinds {
+		if _, counted := before[moved.kind]; !counted {
+			continue
+		}
+		kind := MovedKind{
+			Kind:   moved.kind,
+			Before: before[moved.kind],
+			Source: source[moved.kind],
+			Target: target[moved.kind],
+		}
+		if !kind.Verified() {
+			missing = append(missing, kind.Kind)
+		}
+		report.Kinds = append(report.Kinds, kind)
+	}
+
+	if len(missing) > 0 {
+		return report, ErrMoveVerificationFailed{Namespace: namespace, Kinds: missing}
+	}
+	return report, nil
+}
+
+// countMovedKinds counts the objects of each of movedKinds in namespace. Kinds the cluster doesn't know, e.g.
+// BareMetalHost in a cluster without the metal3 provider, are left out.
+func countMovedKinds(ctx context.Context, c client.Client, namespace string) (map[string]int, error) {
+	counts := make(map[string]int, len(movedKinds))
+	for _, moved := range movedKinds {
+		list := moved.newList()
+		err := c.List(ctx, list, client.InNamespace(namespace))
+		if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		counts[moved.kind] = meta.LenList(list)
+	}
+	return counts, nil
+}
@@ -0,0 +1,282 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bmoapis "github.com/metal3-io/baremetal-operator/pkg/apis"
+	bmh "github.com/metal3-io/baremetal-operator/pkg/apis/metal3/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var cluster1 = &clusterv1.Cluster{
+	TypeMeta: metav1.TypeMeta{
+		APIVersion: "cluster.x-k8s.io/v1alpha3",
+		Kind:       "Cluster",
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "cluster1",
+		Namespace: "ns1",
+	},
+	Status: clusterv1.ClusterStatus{
+		InfrastructureReady:     true,
+		ControlPlaneInitialized: true,
+	},
+}
+
+var machine1 = &clusterv1.Machine{
+	TypeMeta: metav1.TypeMeta{
+		APIVersion: "cluster.x-k8s.io/v1alpha3",
+		Kind:       "Machine",
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "machine1",
+		Namespace: "ns1",
+	},
+	Status: clusterv1.MachineStatus{
+		NodeRef: &corev1.ObjectReference{Kind: "Node", Name: "node1"},
+	},
+}
+
+var bmhProvisioning = &bmh.BareMetalHost{
+	TypeMeta: metav1.TypeMeta{
+		APIVersion: "metal3.io/v1alpha1",
+		Kind:       "BareMetalHost",
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "bmh3",
+		Namespace: "ns1",
+	},
+	Status: bmh.BareMetalHostStatus{
+		Provisioning: bmh.ProvisionStatus{
+			State: bmh.StateProvisioning,
+		},
+	},
+}
+
+func newClientWithMoveObjects(objects ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	//nolint:errcheck
+	bmoapis.AddToScheme(scheme)
+	//nolint:errcheck
+	clusterv1.AddToScheme(scheme)
+	//nolint:errcheck
+	apiextensionsv1.AddToScheme(scheme)
+	return fake.NewFakeClientWithScheme(scheme, objects...)
+}
+
+func newClientWithoutBMHScheme(objects ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	//nolint:errcheck
+	clusterv1.AddToScheme(scheme)
+	//nolint:errcheck
+	apiextensionsv1.AddToScheme(scheme)
+	return fake.NewFakeClientWithScheme(scheme, objects...)
+}
+
+func Test_move_validateMove(t *testing.T) {
+	type args struct {
+		cFrom     client.Client
+		cTo       client.Client
+		namespace string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      interface{}
+		want         map[string]int
+		wantProblems []PreflightProblem
+	}{
+		{
+			name: "counts the objects to move",
+			args: args{
+				cFrom:     newClientWithMoveObjects(cluster1, machine1, bmh1, bmh2),
+				cTo:       newClientWithMoveObjects(),
+				namespace: "ns1",
+			},
+			want: map[string]int{"Cluster": 1, "Machine": 1, "BareMetalHost": 2},
+		},
+		{
+			name: "fails when there is no Cluster object in the namespace",
+			args: args{
+				cFrom:     newClientWithMoveObjects(cluster1, bmh1),
+				cTo:       newClientWithMoveObjects(),
+				namespace: "ns2",
+			},
+			wantErr: ErrNothingToMove{Namespace: "ns2"},
+		},
+		{
+			name: "reports a BareMetalHost object being provisioned",
+			args: args{
+				cFrom:     newClientWithMoveObjects(cluster1, bmh1, bmhProvisioning),
+				cTo:       newClientWithMoveObjects(),
+				namespace: "ns1",
+			},
+			want: map[string]int{"Cluster": 1, "Machine": 0, "BareMetalHost": 2},
+			wantProblems: []PreflightProblem{{
+				Object:  "BareMetalHost/bmh3",
+				Problem: "still " + string(bmh.StateProvisioning),
+				Action:  "wait for the baremetal operator to settle the host",
+			}},
+		},
+		{
+			name: "skips BareMetalHost objects when the source cluster has none",
+			args: args{
+				cFrom:     newClientWithMoveObjects(cluster1, machine1),
+				cTo:       newClientWithoutBMHScheme(),
+				namespace: "ns1",
+			},
+			want: map[string]int{"Cluster": 1, "Machine": 1, "BareMetalHost": 0},
+		},
+		{
+			name: "skips BareMetalHost objects when the source cluster doesn't know the kind",
+			args: args{
+				cFrom:     newClientWithoutBMHScheme(cluster1, machine1),
+				cTo:       newClientWithoutBMHScheme(),
+				namespace: "ns1",
+			},
+			want: map[string]int{"Cluster": 1, "Machine": 1},
+		},
+		{
+			name: "fails when BareMetalHost objects can't be listed in the target cluster",
+			args: args{
+				cFrom:     newClientWithMoveObjects(cluster1, bmh1),
+				cTo:       newClientWithoutBMHScheme(),
+				namespace: "ns1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			counts, report, err := validateMove(context.TODO(), tt.args.cFrom, tt.args.cTo, tt.args.namespace)
+			switch tt.wantErr.(type) {
+			case nil:
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(counts).To(Equal(tt.want))
+				g.Expect(report.Problems).To(Equal(tt.wantProblems))
+			case bool:
+				g.Expect(err).To(HaveOccurred())
+			default:
+				g.Expect(err).To(Equal(tt.wantErr))
+			}
+		})
+	}
+}
+
+func Test_move_verifyMove(t *testing.T) {
+	before := map[string]int{"Cluster": 1, "Machine": 1, "BareMetalHost": 2}
+	type args struct {
+		cFrom client.Client
+		cTo   client.Client
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+		want    *MoveReport
+	}{
+		{
+			name: "verifies that all objects arrived in the target cluster",
+			args: args{
+				cFrom: newClientWithMoveObjects(bmh1, bmh2),
+				cTo:   newClientWithMoveObjects(cluster1, machine1, bmh1, bmh2),
+			},
+			want: &MoveReport{
+				Namespace: "ns1",
+				Kinds: []MovedKind{
+					{Kind: "Cluster", Before: 1, Source: 0, Target: 1},
+					{Kind: "Machine", Before: 1, Source: 0, Target: 1},
+					{Kind: "BareMetalHost", Before: 2, Source: 2, Target: 2},
+				},
+			},
+		},
+		{
+			name: "reports the kinds of missing objects",
+			args: args{
+				cFrom: newClientWithMoveObjects(machine1, bmh1, bmh2),
+				cTo:   newClientWithMoveObjects(cluster1, bmh1),
+			},
+			wantErr: ErrMoveVerificationFailed{Namespace: "ns1", Kinds: []string{"Machine", "BareMetalHost"}},
+			want: &MoveReport{
+				Namespace: "ns1",
+				Kinds: []MovedKind{
+					{Kind: "Cluster", Before: 1, Source: 0, Target: 1},
+					{Kind: "Machine", Before: 1, Source: 1, Target: 0},
+					{Kind: "BareMetalHost", Before: 2, Source: 2, Target: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			report, err := verifyMove(context.TODO(), tt.args.cFrom, tt.args.cTo, "ns1", before)
+			if tt.wantErr != nil {
+				g.Expect(err).To(Equal(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(report).To(Equal(tt.want))
+			g.Expect(report.Verified()).To(Equal(tt.wantErr == nil))
+		})
+	}
+}
+
+func TestMoveReportPrint(t *testing.T) {
+	g := NewWithT(t)
+	report := MoveReport{
+		Namespace: "ns1",
+		Kinds: []MovedKind{
+			{Kind: "Cluster", Before: 1, Source: 0, Target: 1},
+			{Kind: "BareMetalHost", Before: 2, Source: 2, Target: 1},
+		},
+	}
+	out := &bytes.Buffer{}
+	g.Expect(report.Print(out)).To(Succeed())
+	g.Expect(out.String()).To(Equal(
+		"KIND            NAMESPACE   BEFORE   SOURCE   TARGET   STATUS\n" +
+			"Cluster         ns1         1        0        1        moved\n" +
+			"BareMetalHost   ns1         2        2        1        missing\n"))
+}
+
+func Test_move_verifyMoveWithoutBMH(t *testing.T) {
+	g := NewWithT(t)
+	before := map[string]int{"Cluster": 1, "Machine": 1}
+	report, err := verifyMove(context.TODO(), newClientWithoutBMHScheme(),
+		newClientWithoutBMHScheme(cluster1, machine1), "ns1", before)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report).To(Equal(&MoveReport{
+		Namespace: "ns1",
+		Kinds: []MovedKind{
+			{Kind: "Cluster", Before: 1, Source: 0, Target: 1},
+			{Kind: "Machine", Before: 1, Source: 0, Target: 1},
+		},
+	}))
+}
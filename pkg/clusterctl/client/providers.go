@@ -0,0 +1,168 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	clusterctlconfig "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+
+	airshipv1 "opendev.org/airship/airshipctl/pkg/clusterctl/api/v1alpha1"
+)
+
+const (
+	gitHubHost = "github.com"
+)
+
+// newInitOptions converts init-options of the Clusterctl document to clusterctl init options,
+// setting the pinned version of every provider that is referenced without a version
+func newInitOptions(options *airshipv1.Clusterctl) (clusterctlclient.InitOptions, error) {
+	cio := clusterctlclient.InitOptions{}
+	initOptions := options.InitOptions
+	if initOptions == nil {
+		return cio, nil
+	}
+	var err error
+	if initOptions.CoreProvider != "" {
+		cio.CoreProvider, err = pinProvider(options, initOptions.CoreProvider, clusterctlv1.CoreProviderType)
+		if err != nil {
+			return cio, err
+		}
+	}
+	if cio.BootstrapProviders, err = pinProviders(options, initOptions.BootstrapProviders,
+		clusterctlv1.BootstrapProviderType); err != nil {
+		return cio, err
+	}
+	if cio.InfrastructureProviders, err = pinProviders(options, initOptions.InfrastructureProviders,
+		clusterctlv1.InfrastructureProviderType); err != nil {
+		return cio, err
+	}
+	if cio.ControlPlaneProviders, err = pinProviders(options, initOptions.ControlPlaneProviders,
+		clusterctlv1.ControlPlaneProviderType); err != nil {
+		return cio, err
+	}
+	return cio, nil
+}
+
+func pinProviders(options *airshipv1.Clusterctl, providers []string,
+	providerType clusterctlv1.ProviderType) ([]string, error) {
+	if providers == nil {
+		return nil, nil
+	}
+	pinned := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		p, err := pinProvider(options, provider, providerType)
+		if err != nil {
+			return nil, err
+		}
+		pinned = append(pinned, p)
+	}
+	return pinned, nil
+}
+
+// pinProvider returns provider in name:version form, where version defaults to the pinned one
+func pinProvider(options *airshipv1.Clusterctl, provider string,
+	providerType clusterctlv1.ProviderType) (string, error) {
+	name, version := parseProvider(provider)
+	airProv := options.Provider(name, providerType)
+	if airProv == nil {
+		if options.AirGapped {
+			return "", ErrProviderNotDeclared{ProviderName: name, ProviderType: string(providerType)}
+		}
+		return provider, nil
+	}
+	if airProv.Version == "" {
+		return provider, nil
+	}
+	if version != "" && version != airProv.Version {
+		return "", ErrVersionNotPinned{
+			ProviderName:  name,
+			ProviderType:  string(providerType),
+			Version:       version,
+			PinnedVersion: airProv.Version,
+		}
+	}
+	return name + ":" + airProv.Version, nil
+}
+
+// parseProvider splits provider reference of name[:version] form
+func parseProvider(provider string) (string, string) {
+	t := strings.SplitN(provider, ":", 2)
+	if len(t) == 1 {
+		return t[0], ""
+	}
+	return t[0], t[1]
+}
+
+// validateProviders checks that pinned versions are available in airshipctl repositories and, for
+// air-gapped deployments, that clusterctl is never going to reach out to GitHub
+func validateProviders(options *airshipv1.Clusterctl) error {
+	for _, provider := range options.Providers {
+		if !provider.IsClusterctlRepository {
+			if _, ok := provider.Versions[provider.Version]; provider.Version != "" && !ok {
+				return ErrVersionNotPinned{
+					ProviderName:  provider.Name,
+					ProviderType:  provider.Type,
+					PinnedVersion: provider.Version,
+				}
+			}
+			continue
+		}
+		if !options.AirGapped {
+			continue
+		}
+		u, err := url.Parse(provider.URL)
+		if err != nil {
+			return err
+		}
+		if u.Host == gitHubHost {
+			return ErrRemoteRepository{ProviderName: provider.Name, ProviderType: provider.Type, URL: provider.URL}
+		}
+	}
+	if !options.AirGapped {
+		return nil
+	}
+	// clusterctl initializes these providers when init-options don't list any of their type
+	defaults := []struct {
+		name         string
+		providerType clusterctlv1.ProviderType
+		listed       bool
+	}{
+		{clusterctlconfig.ClusterAPIProviderName, clusterctlv1.CoreProviderType,
+			options.InitOptions != nil && options.InitOptions.CoreProvider != ""},
+		{clusterctlconfig.KubeadmBootstrapProviderName, clusterctlv1.BootstrapProviderType,
+			options.InitOptions != nil && len(options.InitOptions.BootstrapProviders) > 0},
+		{clusterctlconfig.KubeadmControlPlaneProviderName, clusterctlv1.ControlPlaneProviderType,
+			options.InitOptions != nil && len(options.InitOptions.ControlPlaneProviders) > 0},
+	}
+	for _, d := range defaults {
+		if !d.listed && options.Provider(d.name, d.providerType) == nil {
+			return ErrProviderNotDeclared{ProviderName: d.name, ProviderType: string(d.providerType)}
+		}
+	}
+	return nil
+}
+
+// isHTTPRepository returns true if the URL points to an internal http(s) mirror rather than GitHub
+func isHTTPRepository(providerURL string) bool {
+	u, err := url.Parse(providerURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != gitHubHost
+}
@@ -0,0 +1,163 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+
+	airshipv1 "opendev.org/airship/airshipctl/pkg/clusterctl/api/v1alpha1"
+)
+
+var (
+	testConfigPinned = `apiVersion: airshipit.org/v1alpha1
+kind: Clusterctl
+metadata:
+  labels:
+    airshipit.org/deploy-k8s: "false"
+  name: clusterctl-v1
+init-options:
+  core-provider: "cluster-api"
+  bootstrap-providers:
+    - "kubeadm:v0.3.3"
+  infrastructure-providers:
+    - "metal3"
+  control-plane-providers:
+    - "kubeadm"
+providers:
+  - name: "metal3"
+    type: "InfrastructureProvider"
+    url: "https://mirror.example.com/capi/infrastructure-metal3/v0.3.1/infrastructure-components.yaml"
+    clusterctl-repository: true
+    version: v0.3.1
+  - name: "kubeadm"
+    type: "BootstrapProvider"
+    version: v0.3.3
+    versions:
+      v0.3.3: functions/capi/infrastructure/v0.3.1
+  - name: "cluster-api"
+    type: "CoreProvider"
+    version: v0.3.3
+    versions:
+      v0.3.2: functions/capi/infrastructure/v0.3.2
+      v0.3.3: functions/capi/infrastructure/v0.3.1
+  - name: "kubeadm"
+    type: "ControlPlaneProvider"
+    url: "/manifests/capi/control-plane-kubeadm/v0.3.3/control-plane-components.yaml"
+    clusterctl-repository: true`
+)
+
+func TestNewInitOptions(t *testing.T) {
+	o := testOptions(t, testConfigPinned)
+	cio, err := newInitOptions(o)
+	require.NoError(t, err)
+	assert.Equal(t, clusterctlclient.InitOptions{
+		CoreProvider:            "cluster-api:v0.3.3",
+		BootstrapProviders:      []string{"kubeadm:v0.3.3"},
+		InfrastructureProviders: []string{"metal3:v0.3.1"},
+		ControlPlaneProviders:   []string{"kubeadm"},
+	}, cio)
+}
+
+func TestNewInitOptionsVersionMismatch(t *testing.T) {
+	o := testOptions(t, testConfigPinned)
+	o.InitOptions.InfrastructureProviders = []string{"metal3:v0.3.2"}
+	_, err := newInitOptions(o)
+	assert.Equal(t, ErrVersionNotPinned{
+		ProviderName:  "metal3",
+		ProviderType:  "InfrastructureProvider",
+		Version:       "v0.3.2",
+		PinnedVersion: "v0.3.1",
+	}, err)
+}
+
+func TestValidateProviders(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(o *airshipv1.Clusterctl)
+		expectedErr error
+	}{
+		{
+			name:   "pinned providers",
+			modify: func(o *airshipv1.Clusterctl) {},
+		},
+		{
+			name: "air-gapped with declared providers",
+			modify: func(o *airshipv1.Clusterctl) {
+				o.AirGapped = true
+			},
+		},
+		{
+			name: "pinned version not in versions",
+			modify: func(o *airshipv1.Clusterctl) {
+				o.Provider("cluster-api", "CoreProvider").Version = "v0.3.4"
+			},
+			expectedErr: ErrVersionNotPinned{
+				ProviderName:  "cluster-api",
+				ProviderType:  "CoreProvider",
+				PinnedVersion: "v0.3.4",
+			},
+		},
+		{
+			name: "air-gapped with GitHub repository",
+			modify: func(o *airshipv1.Clusterctl) {
+				o.AirGapped = true
+				o.Provider("metal3", "InfrastructureProvider").URL = "https://github.com/metal3-io/" +
+					"cluster-api-provider-metal3/releases/v0.3.1/infrastructure-components.yaml"
+			},
+			expectedErr: ErrRemoteRepository{
+				ProviderName: "metal3",
+				ProviderType: "InfrastructureProvider",
+				URL: "https://github.com/metal3-io/" +
+					"cluster-api-provider-metal3/releases/v0.3.1/infrastructure-components.yaml",
+			},
+		},
+		{
+			name: "GitHub repository allowed when not air-gapped",
+			modify: func(o *airshipv1.Clusterctl) {
+				o.Provider("metal3", "InfrastructureProvider").URL = "https://github.com/metal3-io/" +
+					"cluster-api-provider-metal3/releases/v0.3.1/infrastructure-components.yaml"
+			},
+		},
+		{
+			name: "air-gapped with default provider not declared",
+			modify: func(o *airshipv1.Clusterctl) {
+				o.AirGapped = true
+				o.InitOptions.CoreProvider = ""
+				o.Providers = o.Providers[:2]
+			},
+			expectedErr: ErrProviderNotDeclared{ProviderName: "cluster-api", ProviderType: "CoreProvider"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			o := testOptions(t, testConfigPinned)
+			tt.modify(o)
+			assert.Equal(t, tt.expectedErr, validateProviders(o))
+		})
+	}
+}
+
+func TestNewClientAirGappedUndeclaredProvider(t *testing.T) {
+	o := testOptions(t, testConfigPinned)
+	o.AirGapped = true
+	o.InitOptions.InfrastructureProviders = []string{"aws"}
+	_, err := NewClient("", false, o)
+	assert.Equal(t, ErrProviderNotDeclared{ProviderName: "aws", ProviderType: "InfrastructureProvider"}, err)
+}
@@ -0,0 +1,236 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+
+	airshipv1 "opendev.org/airship/airshipctl/pkg/clusterctl/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// ProviderUpgrade is a provider installed in the management cluster and the version the Clusterctl document
+// defines for it.
+type ProviderUpgrade struct {
+	Namespace      string
+	Name           string
+	Type           string
+	CurrentVersion string
+	// TargetVersion is empty if the Clusterctl document doesn't define a version for the provider, in which
+	// case the provider is left untouched.
+	TargetVersion string
+}
+
+// Pending returns true if the provider is to be upgraded.
+func (p ProviderUpgrade) Pending() bool {
+	return p.TargetVersion != "" && p.TargetVersion != p.CurrentVersion
+}
+
+// ref returns the provider in the namespace/name:version form clusterctl upgrades expect.
+func (p ProviderUpgrade) ref() string {
+	return fmt.Sprintf("%s/%s:%s", p.Namespace, p.Name, p.TargetVersion)
+}
+
+// UpgradePlan is the upgrade of the providers of a management group, i.e. of a core provider and the providers
+// managed along with it, to the versions of the Clusterctl document.
+type UpgradePlan struct {
+	// ManagementGroup is the core provider of the group in namespace/name form.
+	ManagementGroup string
+	Providers       []ProviderUpgrade
+}
+
+// UpToDate returns true if none of the providers of the management group is to be upgraded.
+func (p UpgradePlan) UpToDate() bool {
+	for _, provider := range p.Providers {
+		if provider.Pending() {
+			return false
+		}
+	}
+	return true
+}
+
+// PrintUpgradePlans writes plans to w as a table.
+func PrintUpgradePlans(w io.Writer, plans []UpgradePlan) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "MANAGEMENT GROUP\tNAMESPACE\tNAME\tTYPE\tCURRENT VERSION\tTARGET VERSION\tSTATUS")
+	for _, plan := range plans {
+		for _, provider := range plan.Providers {
+			target, status := provider.TargetVersion, "up to date"
+			switch {
+			case target == "":
+				target, status = "-", "not in document"
+			case provider.Pending():
+				status = "upgrade"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", plan.ManagementGroup, provider.Namespace,
+				provider.Name, provider.Type, provider.CurrentVersion, target, status)
+		}
+	}
+	return tw.Flush()
+}
+
+// PlanUpgrade implements interface to Clusterctl. The providers installed in the management cluster are compared
+// with the versions the Clusterctl document defines for them.
+func (c *Client) PlanUpgrade(kubeconfigPath, kubeconfigContext string) ([]UpgradePlan, error) {
+	plans, err := c.clusterctlClient.PlanUpgrade(clusterctlclient.PlanUpgradeOptions{
+		Kubeconfig: clusterctlclient.Kubeconfig{
+			Path:    kubeconfigPath,
+			Context: kubeconfigContext,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var upgradePlans []UpgradePlan
+	// clusterctl plans the upgrade of a management group for each contract it can move to, the installed
+	// providers are the same in all of them
+	planned := make(map[string]bool)
+	for _, plan := range plans {
+		core := plan.CoreProvider
+		group := core.Namespace + "/" + core.ProviderName
+		if planned[group] {
+			continue
+		}
+		planned[group] = true
+
+		upgradePlan := UpgradePlan{ManagementGroup: group}
+		for _, item := range plan.Providers {
+			provider := ProviderUpgrade{
+				Namespace:      item.Namespace,
+				Name:           item.ProviderName,
+				Type:           item.Type,
+				CurrentVersion: item.Version,
+				TargetVersion: targetVersion(c.options, c.initOptions, item.ProviderName,
+					clusterctlv1.ProviderType(item.Type)),
+			}
+			if isDowngrade(provider.CurrentVersion, provider.TargetVersion) {
+				return nil, ErrProviderDowngrade{
+					ProviderName:   provider.Name,
+					ProviderType:   provider.Type,
+					CurrentVersion: provider.CurrentVersion,
+					TargetVersion:  provider.TargetVersion,
+				}
+			}
+			upgradePlan.Providers = append(upgradePlan.Providers, provider)
+		}
+		upgradePlans = append(upgradePlans, upgradePlan)
+	}
+	return upgradePlans, nil
+}
+
+// ApplyUpgrade implements interface to Clusterctl. The providers of every management group are upgraded to the
+// versions the Clusterctl document defines for them, and the plans that were applied are returned.
+func (c *Client) ApplyUpgrade(kubeconfigPath, kubeconfigContext string) ([]UpgradePlan, error) {
+	plans, err := c.PlanUpgrade(kubeconfigPath, kubeconfigContext)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.UpToDate() {
+			log.Printf("Providers of management group %s are up to date\n", plan.ManagementGroup)
+			continue
+		}
+		options := clusterctlclient.ApplyUpgradeOptions{
+			Kubeconfig: clusterctlclient.Kubeconfig{
+				Path:    kubeconfigPath,
+				Context: kubeconfigContext,
+			},
+			ManagementGroup: plan.ManagementGroup,
+		}
+		for _, provider := range plan.Providers {
+			if !provider.Pending() {
+				continue
+			}
+			switch clusterctlv1.ProviderType(provider.Type) {
+			case clusterctlv1.CoreProviderType:
+				options.CoreProvider = provider.ref()
+			case clusterctlv1.BootstrapProviderType:
+				options.BootstrapProviders = append(options.BootstrapProviders, provider.ref())
+			case clusterctlv1.ControlPlaneProviderType:
+				options.ControlPlaneProviders = append(options.ControlPlaneProviders, provider.ref())
+			case clusterctlv1.InfrastructureProviderType:
+				options.InfrastructureProviders = append(options.InfrastructureProviders, provider.ref())
+			}
+		}
+		log.Printf("Upgrading providers of management group %s\n", plan.ManagementGroup)
+		if err = c.clusterctlClient.ApplyUpgrade(options); err != nil {
+			return nil, err
+		}
+	}
+	return plans, nil
+}
+
+// targetVersion returns the version of a provider defined by the Clusterctl document: the version init-options
+// initialize it with, the version it is pinned to, or the latest version of its airshipctl repository, in that
+// order. It is empty if the document defines none of them.
+func targetVersion(options *airshipv1.Clusterctl, cio clusterctlclient.InitOptions, name string,
+	providerType clusterctlv1.ProviderType) string {
+	var initProviders []string
+	switch providerType {
+	case clusterctlv1.CoreProviderType:
+		initProviders = []string{cio.CoreProvider}
+	case clusterctlv1.BootstrapProviderType:
+		initProviders = cio.BootstrapProviders
+	case clusterctlv1.ControlPlaneProviderType:
+		initProviders = cio.ControlPlaneProviders
+	case clusterctlv1.InfrastructureProviderType:
+		initProviders = cio.InfrastructureProviders
+	}
+	for _, initProvider := range initProviders {
+		if initName, initVersion := parseProvider(initProvider); initName == name && initVersion != "" {
+			return initVersion
+		}
+	}
+
+	provider := options.Provider(name, providerType)
+	if provider == nil {
+		return ""
+	}
+	if provider.Version != "" {
+		return provider.Version
+	}
+	var latest *version.Version
+	var latestVersion string
+	for ver := range provider.Versions {
+		semVer, err := version.ParseSemantic(ver)
+		if err != nil {
+			continue
+		}
+		if latest == nil || latest.LessThan(semVer) {
+			latest, latestVersion = semVer, ver
+		}
+	}
+	return latestVersion
+}
+
+// isDowngrade returns true if target is an older semantic version than current.
+func isDowngrade(current, target string) bool {
+	currentVersion, err := version.ParseSemantic(current)
+	if err != nil {
+		return false
+	}
+	targetVersion, err := version.ParseSemantic(target)
+	if err != nil {
+		return false
+	}
+	return targetVersion.LessThan(currentVersion)
+}
@@ -0,0 +1,168 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// fakeClusterctlClient returns a fixed upgrade plan and records the upgrades applied
+type fakeClusterctlClient struct {
+	clusterctlclient.Client
+	plans   []clusterctlclient.UpgradePlan
+	applied []clusterctlclient.ApplyUpgradeOptions
+}
+
+func (c *fakeClusterctlClient) PlanUpgrade(
+	clusterctlclient.PlanUpgradeOptions) ([]clusterctlclient.UpgradePlan, error) {
+	return c.plans, nil
+}
+
+func (c *fakeClusterctlClient) ApplyUpgrade(options clusterctlclient.ApplyUpgradeOptions) error {
+	c.applied = append(c.applied, options)
+	return nil
+}
+
+func installedProvider(namespace, name string, providerType clusterctlv1.ProviderType,
+	version string) clusterctlv1.Provider {
+	return clusterctlv1.Provider{
+		ObjectMeta:   metav1.ObjectMeta{Namespace: namespace, Name: name},
+		ProviderName: name,
+		Type:         string(providerType),
+		Version:      version,
+	}
+}
+
+func testUpgradeClient(t *testing.T, coreVersion string) (*Client, *fakeClusterctlClient) {
+	t.Helper()
+	o := testOptions(t, testConfigPinned)
+	cio, err := newInitOptions(o)
+	require.NoError(t, err)
+
+	core := installedProvider("capi-system", "cluster-api", clusterctlv1.CoreProviderType, coreVersion)
+	items := []cluster.UpgradeItem{
+		{Provider: core},
+		{Provider: installedProvider("capi-kubeadm-bootstrap-system", "kubeadm",
+			clusterctlv1.BootstrapProviderType, "v0.3.3")},
+		{Provider: installedProvider("capm3-system", "metal3",
+			clusterctlv1.InfrastructureProviderType, "v0.3.0")},
+		{Provider: installedProvider("capi-system", "docker",
+			clusterctlv1.InfrastructureProviderType, "v0.3.0")},
+	}
+	fake := &fakeClusterctlClient{
+		// clusterctl plans a management group once per contract it can move to
+		plans: []clusterctlclient.UpgradePlan{
+			{Contract: "v1alpha3", CoreProvider: core, Providers: items},
+			{Contract: "v1alpha4", CoreProvider: core, Providers: items},
+		},
+	}
+	return &Client{clusterctlClient: fake, initOptions: cio, options: o}, fake
+}
+
+func TestPlanUpgrade(t *testing.T) {
+	c, _ := testUpgradeClient(t, "v0.3.2")
+	plans, err := c.PlanUpgrade("kubeconfig", "context")
+	require.NoError(t, err)
+	assert.Equal(t, []UpgradePlan{
+		{
+			ManagementGroup: "capi-system/cluster-api",
+			Providers: []ProviderUpgrade{
+				{
+					Namespace:      "capi-system",
+					Name:           "cluster-api",
+					Type:           "CoreProvider",
+					CurrentVersion: "v0.3.2",
+					TargetVersion:  "v0.3.3",
+				},
+				{
+					Namespace:      "capi-kubeadm-bootstrap-system",
+					Name:           "kubeadm",
+					Type:           "BootstrapProvider",
+					CurrentVersion: "v0.3.3",
+					TargetVersion:  "v0.3.3",
+				},
+				{
+					Namespace:      "capm3-system",
+					Name:           "metal3",
+					Type:           "InfrastructureProvider",
+					CurrentVersion: "v0.3.0",
+					TargetVersion:  "v0.3.1",
+				},
+				{
+					Namespace:      "capi-system",
+					Name:           "docker",
+					Type:           "InfrastructureProvider",
+					CurrentVersion: "v0.3.0",
+				},
+			},
+		},
+	}, plans)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, PrintUpgradePlans(out, plans))
+	assert.Equal(t, "MANAGEMENT GROUP          NAMESPACE                       NAME          "+
+		"TYPE                     CURRENT VERSION   TARGET VERSION   STATUS\n"+
+		"capi-system/cluster-api   capi-system                     cluster-api   "+
+		"CoreProvider             v0.3.2            v0.3.3           upgrade\n"+
+		"capi-system/cluster-api   capi-kubeadm-bootstrap-system   kubeadm       "+
+		"BootstrapProvider        v0.3.3            v0.3.3           up to date\n"+
+		"capi-system/cluster-api   capm3-system                    metal3        "+
+		"InfrastructureProvider   v0.3.0            v0.3.1           upgrade\n"+
+		"capi-system/cluster-api   capi-system                     docker        "+
+		"InfrastructureProvider   v0.3.0            -                not in document\n", out.String())
+}
+
+func TestPlanUpgradeDowngrade(t *testing.T) {
+	c, _ := testUpgradeClient(t, "v0.3.4")
+	_, err := c.PlanUpgrade("kubeconfig", "context")
+	assert.Equal(t, ErrProviderDowngrade{
+		ProviderName:   "cluster-api",
+		ProviderType:   "CoreProvider",
+		CurrentVersion: "v0.3.4",
+		TargetVersion:  "v0.3.3",
+	}, err)
+}
+
+func TestApplyUpgrade(t *testing.T) {
+	c, fake := testUpgradeClient(t, "v0.3.2")
+	plans, err := c.ApplyUpgrade("kubeconfig", "context")
+	require.NoError(t, err)
+	assert.Len(t, plans, 1)
+	assert.Equal(t, []clusterctlclient.ApplyUpgradeOptions{
+		{
+			Kubeconfig:              clusterctlclient.Kubeconfig{Path: "kubeconfig", Context: "context"},
+			ManagementGroup:         "capi-system/cluster-api",
+			CoreProvider:            "capi-system/cluster-api:v0.3.3",
+			InfrastructureProviders: []string{"capm3-system/metal3:v0.3.1"},
+		},
+	}, fake.applied)
+}
+
+func TestApplyUpgradeUpToDate(t *testing.T) {
+	c, fake := testUpgradeClient(t, "v0.3.3")
+	fake.plans[0].Providers = fake.plans[0].Providers[:2]
+	fake.plans = fake.plans[:1]
+	_, err := c.ApplyUpgrade("kubeconfig", "context")
+	require.NoError(t, err)
+	assert.Empty(t, fake.applied)
+}
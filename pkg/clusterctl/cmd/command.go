@@ -35,14 +35,18 @@ type Command struct {
 
 // NewCommand returns instance of Command
 func NewCommand(rs *environment.AirshipCTLSettings) (*Command, error) {
-	bundle, err := getBundle(rs.Config)
+	bundle, err := getBundle(rs)
 	if err != nil {
 		return nil, err
 	}
-	if err = rs.Config.EnsureComplete(); err != nil {
-		return nil, err
-	}
-	root, err := rs.Config.CurrentContextTargetPath()
+	return NewCommandFromBundle(rs, bundle, rs.Config.CurrentContext)
+}
+
+// NewCommandFromBundle returns instance of Command using the Clusterctl document of bundle and connecting to the
+// cluster of kubeconfigContext
+func NewCommandFromBundle(rs *environment.AirshipCTLSettings, bundle document.Bundle,
+	kubeconfigContext string) (*Command, error) {
+	root, err := rs.CurrentContextTargetPath()
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +65,7 @@ func NewCommand(rs *environment.AirshipCTLSettings) (*Command, error) {
 		documentRoot:      root,
 		client:            client,
 		options:           options,
-		kubeconfigContext: rs.Config.CurrentContext,
+		kubeconfigContext: kubeconfigContext,
 	}, nil
 }
 
@@ -88,19 +92,36 @@ func clusterctlOptions(bundle document.Bundle) (*airshipv1.Clusterctl, error) {
 	return options, nil
 }
 
-func getBundle(conf *config.Config) (document.Bundle, error) {
-	path, err := conf.CurrentContextEntryPoint(config.ClusterctlPhase)
+func getBundle(rs *environment.AirshipCTLSettings) (document.Bundle, error) {
+	path, err := rs.CurrentContextEntryPoint(config.ClusterctlPhase)
 	if err != nil {
 		return nil, err
 	}
 	return document.NewBundleByPath(path)
 }
 
-// Move runs clusterctl move
-func (c *Command) Move(toKubeconfigContext string) error {
-	if c.options.MoveOptions != nil {
-		return c.client.Move(c.kubeconfigPath, c.kubeconfigContext,
-			c.kubeconfigPath, toKubeconfigContext, c.options.MoveOptions.Namespace)
+// Move runs clusterctl move. A non-empty namespace overrides the namespace of the Clusterctl document.
+func (c *Command) Move(toKubeconfigContext, namespace string) (*client.MoveReport, error) {
+	return c.client.Move(c.kubeconfigPath, c.kubeconfigContext, c.kubeconfigPath, toKubeconfigContext,
+		c.MoveNamespace(namespace))
+}
+
+// MoveNamespace returns the namespace moved by Move: namespace if not empty, the namespace of the Clusterctl
+// document otherwise. An empty namespace is left to be detected in the ephemeral cluster.
+func (c *Command) MoveNamespace(namespace string) string {
+	if namespace == "" && c.options.MoveOptions != nil {
+		return c.options.MoveOptions.Namespace
 	}
-	return c.client.Move(c.kubeconfigPath, c.kubeconfigContext, c.kubeconfigPath, toKubeconfigContext, "")
+	return namespace
+}
+
+// PlanUpgrade runs clusterctl upgrade plan, comparing the installed providers with the versions of the Clusterctl
+// document
+func (c *Command) PlanUpgrade() ([]client.UpgradePlan, error) {
+	return c.client.PlanUpgrade(c.kubeconfigPath, c.kubeconfigContext)
+}
+
+// ApplyUpgrade runs clusterctl upgrade apply with the versions of the Clusterctl document
+func (c *Command) ApplyUpgrade() ([]client.UpgradePlan, error) {
+	return c.client.ApplyUpgrade(c.kubeconfigPath, c.kubeconfigContext)
 }
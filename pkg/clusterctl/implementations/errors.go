@@ -44,3 +44,23 @@ type ErrValueForVariableNotSet struct {
 func (e ErrValueForVariableNotSet) Error() string {
 	return fmt.Sprintf("value for variable %q is not set", e.Variable)
 }
+
+// ErrInvalidRepositoryURL is returned when a repository URL doesn't point to a components file of a version
+type ErrInvalidRepositoryURL struct {
+	URL string
+}
+
+func (e ErrInvalidRepositoryURL) Error() string {
+	return fmt.Sprintf("repository URL %q must follow the {basepath}/{provider-label}/{version}/{components.yaml} "+
+		"layout and use the http or https scheme", e.URL)
+}
+
+// ErrFileDownload is returned when a file can't be downloaded from a repository mirror
+type ErrFileDownload struct {
+	URL    string
+	Status string
+}
+
+func (e ErrFileDownload) Error() string {
+	return fmt.Sprintf("failed to download %s: %s", e.URL, e.Status)
+}
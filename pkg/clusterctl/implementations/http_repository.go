@@ -0,0 +1,101 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package implementations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// HTTPRepository implements Repository from clusterctl project for providers hosted on an
+// internal http(s) mirror. The provider URL is expected to point to the components file of
+// the pinned version, following the layout of clusterctl local repositories:
+// {basepath}/{provider-label}/{version}/{components.yaml}
+type HTTPRepository struct {
+	client         *http.Client
+	root           string
+	componentsPath string
+	defaultVersion string
+}
+
+var _ repository.Repository = &HTTPRepository{}
+
+// ComponentsPath returns the file name of the components file
+func (r *HTTPRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// GetVersions returns the version the repository URL points to, as a mirror can't be listed
+func (r *HTTPRepository) GetVersions() ([]string, error) {
+	return []string{r.defaultVersion}, nil
+}
+
+// DefaultVersion returns the version the repository URL points to
+func (r *HTTPRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath returns the empty string, all files are fetched relative to the version directory
+func (r *HTTPRepository) RootPath() string {
+	return ""
+}
+
+// GetFile downloads a file of the given provider version from the mirror
+func (r *HTTPRepository) GetFile(version string, filePath string) ([]byte, error) {
+	if version == "latest" || version == "" {
+		version = r.defaultVersion
+	}
+	fileURL := strings.Join([]string{r.root, version, filePath}, "/")
+	log.Debugf("Downloading cluster-api provider file from %s", fileURL)
+	resp, err := r.client.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrFileDownload{URL: fileURL, Status: resp.Status}
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// NewHTTPRepository builds instance of http repository from the URL of a components file
+func NewHTTPRepository(providerURL string) (repository.Repository, error) {
+	u, err := url.Parse(providerURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrInvalidRepositoryURL{URL: providerURL}
+	}
+	versionDir, componentsPath := path.Split(strings.TrimSuffix(u.Path, "/"))
+	root, version := path.Split(strings.TrimSuffix(versionDir, "/"))
+	if componentsPath == "" || version == "" || root == "" {
+		return nil, ErrInvalidRepositoryURL{URL: providerURL}
+	}
+	u.Path = strings.TrimSuffix(root, "/")
+	return &HTTPRepository{
+		client:         http.DefaultClient,
+		root:           u.String(),
+		componentsPath: componentsPath,
+		defaultVersion: version,
+	}, nil
+}
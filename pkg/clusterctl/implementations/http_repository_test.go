@@ -0,0 +1,99 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package implementations_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/clusterctl/implementations"
+)
+
+func TestNewHTTPRepository(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		componentsPath string
+		defaultVersion string
+		expectedErr    error
+	}{
+		{
+			name:           "mirror url",
+			url:            "https://mirror.example.com/capi/infrastructure-metal3/v0.3.1/infrastructure-components.yaml",
+			componentsPath: "infrastructure-components.yaml",
+			defaultVersion: "v0.3.1",
+		},
+		{
+			name: "no version directory",
+			url:  "https://mirror.example.com/infrastructure-components.yaml",
+			expectedErr: implementations.ErrInvalidRepositoryURL{
+				URL: "https://mirror.example.com/infrastructure-components.yaml",
+			},
+		},
+		{
+			name: "local path",
+			url:  "/capi/infrastructure-metal3/v0.3.1/infrastructure-components.yaml",
+			expectedErr: implementations.ErrInvalidRepositoryURL{
+				URL: "/capi/infrastructure-metal3/v0.3.1/infrastructure-components.yaml",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := implementations.NewHTTPRepository(tt.url)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.componentsPath, repo.ComponentsPath())
+			assert.Equal(t, tt.defaultVersion, repo.DefaultVersion())
+			versions, err := repo.GetVersions()
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.defaultVersion}, versions)
+		})
+	}
+}
+
+func TestHTTPRepositoryGetFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/capi/infrastructure-metal3/v0.3.1/metadata.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := w.Write([]byte("kind: Metadata\n"))
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	repo, err := implementations.NewHTTPRepository(srv.URL +
+		"/capi/infrastructure-metal3/v0.3.1/infrastructure-components.yaml")
+	require.NoError(t, err)
+
+	b, err := repo.GetFile("latest", "metadata.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Metadata\n", string(b))
+
+	_, err = repo.GetFile("v0.3.2", "metadata.yaml")
+	assert.Equal(t, implementations.ErrFileDownload{
+		URL:    srv.URL + "/capi/infrastructure-metal3/v0.3.2/metadata.yaml",
+		Status: "404 Not Found",
+	}, err)
+}
@@ -21,7 +21,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	kubeconfig "k8s.io/client-go/tools/clientcmd/api"
 
+	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -80,3 +82,54 @@ func TestModifyAuthInfo(t *testing.T) {
 	assert.EqualValues(t, modifiedAuthinfo.KubeAuthInfo().Token, co.Token)
 	assert.EqualValues(t, modifiedAuthinfo, authinfo)
 }
+
+func TestModifyAuthInfoPlugins(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	co := testutil.DummyAuthInfoOptions()
+	co.AuthProvider = "oidc"
+	co.AuthProviderArgs = []string{"client-id=airshipctl", "refresh-token=token"}
+	authinfo := conf.AddAuthInfo(co)
+	authProvider := authinfo.KubeAuthInfo().AuthProvider
+	require.NotNil(t, authProvider)
+	assert.Equal(t, "oidc", authProvider.Name)
+	assert.Equal(t, map[string]string{"client-id": "airshipctl", "refresh-token": "token"}, authProvider.Config)
+
+	// Update one arg and remove another one
+	conf.ModifyAuthInfo(authinfo, &config.AuthInfoOptions{
+		AuthProviderArgs: []string{"client-id=other", "refresh-token-"},
+	})
+	assert.Equal(t, map[string]string{"client-id": "other"}, authinfo.KubeAuthInfo().AuthProvider.Config)
+
+	conf.ModifyAuthInfo(authinfo, &config.AuthInfoOptions{
+		ExecCommand: "kubectl",
+		ExecArgs:    []string{"oidc-login", "get-token"},
+		ExecEnv:     []string{"FOO=foo", "BAR=bar"},
+	})
+	// The exec plugin replaces the auth provider
+	assert.Nil(t, authinfo.KubeAuthInfo().AuthProvider)
+	exec := authinfo.KubeAuthInfo().Exec
+	require.NotNil(t, exec)
+	assert.Equal(t, "kubectl", exec.Command)
+	assert.Equal(t, config.DefaultExecAPIVersion, exec.APIVersion)
+	assert.Equal(t, []string{"oidc-login", "get-token"}, exec.Args)
+	assert.Equal(t, []kubeconfig.ExecEnvVar{{Name: "BAR", Value: "bar"}, {Name: "FOO", Value: "foo"}}, exec.Env)
+
+	conf.ModifyAuthInfo(authinfo, &config.AuthInfoOptions{
+		ExecAPIVersion: "client.authentication.k8s.io/v1alpha1",
+		ExecEnv:        []string{"FOO=changed", "BAR-"},
+	})
+	assert.Equal(t, "client.authentication.k8s.io/v1alpha1", exec.APIVersion)
+	assert.Equal(t, []string{"oidc-login", "get-token"}, exec.Args)
+	assert.Equal(t, []kubeconfig.ExecEnvVar{{Name: "FOO", Value: "changed"}}, exec.Env)
+
+	// The auth provider replaces the exec plugin
+	conf.ModifyAuthInfo(authinfo, &config.AuthInfoOptions{
+		AuthProvider:     "oidc",
+		AuthProviderArgs: []string{"client-id=airshipctl"},
+	})
+	assert.Nil(t, authinfo.KubeAuthInfo().Exec)
+	require.NotNil(t, authinfo.KubeAuthInfo().AuthProvider)
+	assert.Equal(t, map[string]string{"client-id": "airshipctl"}, authinfo.KubeAuthInfo().AuthProvider.Config)
+}
@@ -0,0 +1,189 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	// BackupConfigEntry is the name of the airship config file in a backup
+	BackupConfigEntry = "config"
+	// BackupKubeConfigEntry is the name of the kubeconfig file in a backup
+	BackupKubeConfigEntry = "kubeconfig"
+)
+
+// Backup writes a gzipped tarball containing the airship config file and
+// the kubeconfig file managed by it to w. The files are stored as they are
+// on disk, a missing kubeconfig file is skipped. If passphrase is not empty
+// the tarball is encrypted with a key derived from it.
+func (c *Config) Backup(w io.Writer, passphrase string) error {
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	entries := []struct {
+		name     string
+		path     string
+		optional bool
+	}{
+		{BackupConfigEntry, c.loadedConfigPath, false},
+		{BackupKubeConfigEntry, c.kubeConfigPath, true},
+	}
+	for _, entry := range entries {
+		data, err := ioutil.ReadFile(entry.path)
+		if os.IsNotExist(err) && entry.optional {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err = tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gzw.Close(); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	if passphrase != "" {
+		var err error
+		if data, err = secret.Encrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(data)
+	return err
+}
+
+// Restore replaces the airship config file and the kubeconfig file with the
+// ones stored in a backup created by Backup. The passphrase is required if
+// the backup is encrypted. The content of the backup is validated before any
+// file is written.
+func (c *Config) Restore(r io.Reader, passphrase string) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if secret.IsEncrypted(data) {
+		if passphrase == "" {
+			return ErrBackupPassphraseRequired{}
+		}
+		if data, err = secret.Decrypt(data, passphrase); err != nil {
+			if decryptErr, ok := err.(secret.ErrDecryptionFailed); ok {
+				return ErrInvalidBackup{Reason: decryptErr.Reason}
+			}
+			return err
+		}
+	}
+
+	entries, err := readBackupEntries(data)
+	if err != nil {
+		return err
+	}
+
+	airshipConfigYaml, ok := entries[BackupConfigEntry]
+	if !ok {
+		return ErrInvalidBackup{Reason: "airship config is missing"}
+	}
+	migrated, _, err := MigrateConfig(airshipConfigYaml)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(migrated, &Config{}); err != nil {
+		return ErrInvalidBackup{Reason: err.Error()}
+	}
+
+	kubeConfigYaml, hasKubeConfig := entries[BackupKubeConfigEntry]
+	if hasKubeConfig {
+		if _, err = clientcmd.Load(kubeConfigYaml); err != nil {
+			return ErrInvalidBackup{Reason: err.Error()}
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.loadedConfigPath), 0755); err != nil {
+		return err
+	}
+	if err = writeLockedFile(c.loadedConfigPath, airshipConfigYaml, 0644); err != nil {
+		return err
+	}
+	if !hasKubeConfig {
+		return nil
+	}
+	if err = os.MkdirAll(filepath.Dir(c.kubeConfigPath), 0755); err != nil {
+		return err
+	}
+	return writeLockedFile(c.kubeConfigPath, kubeConfigYaml, 0600)
+}
+
+// readBackupEntries returns the content of the files in a gzipped tarball
+// keyed by their names
+func readBackupEntries(data []byte) (map[string][]byte, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidBackup{Reason: err.Error()}
+	}
+	defer gzr.Close()
+
+	entries := map[string][]byte{}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidBackup{Reason: err.Error()}
+		}
+		if header.Name != BackupConfigEntry && header.Name != BackupKubeConfigEntry {
+			return nil, ErrInvalidBackup{Reason: "unexpected file " + header.Name}
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, ErrInvalidBackup{Reason: err.Error()}
+		}
+		entries[header.Name] = content
+	}
+	return entries, nil
+}
@@ -0,0 +1,81 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestBackupRestore(t *testing.T) {
+	for _, passphrase := range []string{"", "secret"} {
+		conf, cleanup := testutil.InitConfig(t)
+
+		originalConfig, err := ioutil.ReadFile(conf.LoadedConfigPath())
+		require.NoError(t, err)
+		originalKubeConfig, err := ioutil.ReadFile(conf.KubeConfigPath())
+		require.NoError(t, err)
+
+		backup := &bytes.Buffer{}
+		require.NoError(t, conf.Backup(backup, passphrase))
+
+		// Simulate a bad edit of both files
+		require.NoError(t, ioutil.WriteFile(conf.LoadedConfigPath(), []byte("broken"), 0600))
+		require.NoError(t, ioutil.WriteFile(conf.KubeConfigPath(), []byte("broken"), 0600))
+
+		require.NoError(t, conf.Restore(bytes.NewReader(backup.Bytes()), passphrase))
+
+		restoredConfig, err := ioutil.ReadFile(conf.LoadedConfigPath())
+		require.NoError(t, err)
+		assert.Equal(t, originalConfig, restoredConfig)
+		restoredKubeConfig, err := ioutil.ReadFile(conf.KubeConfigPath())
+		require.NoError(t, err)
+		assert.Equal(t, originalKubeConfig, restoredKubeConfig)
+
+		cleanup(t)
+	}
+}
+
+func TestRestoreErrors(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	originalConfig, err := ioutil.ReadFile(conf.LoadedConfigPath())
+	require.NoError(t, err)
+
+	encrypted := &bytes.Buffer{}
+	require.NoError(t, conf.Backup(encrypted, "secret"))
+
+	err = conf.Restore(bytes.NewReader(encrypted.Bytes()), "")
+	assert.Equal(t, config.ErrBackupPassphraseRequired{}, err)
+
+	err = conf.Restore(bytes.NewReader(encrypted.Bytes()), "wrong")
+	assert.Equal(t, config.ErrInvalidBackup{Reason: "wrong passphrase or corrupted data"}, err)
+
+	err = conf.Restore(bytes.NewReader([]byte("not a backup")), "")
+	assert.IsType(t, config.ErrInvalidBackup{}, err)
+
+	// A failed restore must leave the existing files untouched
+	restoredConfig, err := ioutil.ReadFile(conf.LoadedConfigPath())
+	require.NoError(t, err)
+	assert.Equal(t, originalConfig, restoredConfig)
+}
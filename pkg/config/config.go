@@ -67,6 +67,22 @@ type Config struct {
 	// BootstrapInfo is the configuration for container runtime, ISO builder and remote management
 	BootstrapInfo map[string]*Bootstrap `json:"bootstrapInfo"`
 
+	// EncryptionConfigs is a map of referenceable names to the key providers
+	// used for the secrets of manifests
+	EncryptionConfigs map[string]*EncryptionConfig `json:"encryptionConfigs,omitempty"`
+
+	// Profiles is a map of referenceable names to settings layered over
+	// the ones of the current context, selected with --profile
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+
+	// RESTClient tunes the rate limits and timeouts of the clients used to
+	// talk to clusters
+	RESTClient *RESTClient `json:"restClient,omitempty"`
+
+	// EtcdBackupDir is the directory etcd snapshots of workload clusters are
+	// saved to when no directory is given
+	EtcdBackupDir string `json:"etcdBackupDir,omitempty"`
+
 	// loadedConfigPath is the full path to the the location of the config
 	// file from which this config was loaded
 	// +not persisted in file
@@ -103,6 +119,7 @@ func (c *Config) LoadConfig(airshipConfigPath, kubeConfigPath string) error {
 // An error is returned if:
 // * airshipConfigPath is the empty string
 // * the file at airshipConfigPath is inaccessible
+// * the file at airshipConfigPath cannot be migrated to the current schema
 // * the file at airshipConfigPath cannot be marshaled into Config
 func (c *Config) loadFromAirConfig(airshipConfigPath string) error {
 	if airshipConfigPath == "" {
@@ -119,7 +136,17 @@ func (c *Config) loadFromAirConfig(airshipConfigPath string) error {
 		return err
 	}
 
-	return util.ReadYAMLFile(airshipConfigPath, c)
+	data, err := ioutil.ReadFile(airshipConfigPath)
+	if err != nil {
+		return err
+	}
+
+	data, err = migrateConfigFile(airshipConfigPath, data)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, c)
 }
 
 func (c *Config) loadKubeConfig(kubeConfigPath string) error {
@@ -408,13 +435,20 @@ func (c *Config) EnsureComplete() error {
 // PersistConfig updates the airshipctl config and kubeconfig files to match
 // the current Config and KubeConfig objects.
 // If either file did not previously exist, the file will be created.
-// Otherwise, the file will be overwritten
+// Otherwise, the file will be overwritten. Both files are locked while they
+// are written and replaced atomically, so concurrent airshipctl invocations
+// never observe partially written files
 func (c *Config) PersistConfig() error {
 	airshipConfigYaml, err := c.ToYaml()
 	if err != nil {
 		return err
 	}
 
+	kubeConfigYaml, err := clientcmd.Write(*c.kubeConfig)
+	if err != nil {
+		return err
+	}
+
 	// WriteFile doesn't create the directory, create it if needed
 	configDir := filepath.Dir(c.loadedConfigPath)
 	err = os.MkdirAll(configDir, 0755)
@@ -423,17 +457,33 @@ func (c *Config) PersistConfig() error {
 	}
 
 	// Write the Airship Config file
-	err = ioutil.WriteFile(c.loadedConfigPath, airshipConfigYaml, 0644)
+	err = writeLockedFile(c.loadedConfigPath, airshipConfigYaml, 0644)
 	if err != nil {
 		return err
 	}
 
 	// Persist the kubeconfig file referenced
-	if err := clientcmd.WriteToFile(*c.kubeConfig, c.kubeConfigPath); err != nil {
+	err = os.MkdirAll(filepath.Dir(c.kubeConfigPath), 0755)
+	if err != nil {
 		return err
 	}
+	return writeLockedFile(c.kubeConfigPath, kubeConfigYaml, 0600)
+}
 
-	return nil
+// writeLockedFile atomically replaces the content of filename while holding
+// an advisory lock on it
+func writeLockedFile(filename string, data []byte, mode os.FileMode) (err error) {
+	unlock, err := util.LockFile(filename, AirshipConfigLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	return util.WriteFileAtomic(filename, data, mode)
 }
 
 func (c *Config) String() string {
@@ -543,16 +593,31 @@ func (c *Config) ModifyCluster(cluster *Cluster, theCluster *ClusterOptions) (*C
 			kcluster.CertificateAuthorityData = nil
 		}
 	}
+	if theCluster.CertificateAuthorityData != "" {
+		caData, err := DecodeCertificateAuthority([]byte(theCluster.CertificateAuthorityData),
+			"certificate-authority-data")
+		if err != nil {
+			return cluster, err
+		}
+		kcluster.CertificateAuthorityData = caData
+		kcluster.InsecureSkipTLSVerify = false
+		kcluster.CertificateAuthority = ""
+		return cluster, nil
+	}
 	if theCluster.CertificateAuthority == "" {
 		return cluster, nil
 	}
 
 	if theCluster.EmbedCAData {
 		readData, err := ioutil.ReadFile(theCluster.CertificateAuthority)
-		kcluster.CertificateAuthorityData = readData
 		if err != nil {
 			return cluster, err
 		}
+		caData, err := DecodeCertificateAuthority(readData, theCluster.CertificateAuthority)
+		if err != nil {
+			return cluster, err
+		}
+		kcluster.CertificateAuthorityData = caData
 		kcluster.InsecureSkipTLSVerify = false
 		kcluster.CertificateAuthority = ""
 	} else {
@@ -642,7 +707,15 @@ func (c *Config) ModifyContext(context *Context, theContext *ContextOptions) {
 	if kubeContext == nil {
 		return
 	}
-	if theContext.Cluster != "" {
+	switch {
+	case theContext.ClusterType != "":
+		clusterName := theContext.Cluster
+		if clusterName == "" {
+			clusterName = c.ContextClusterComplexName(context).Name
+		}
+		kubeContext.Cluster = c.KubeClusterName(clusterName, theContext.ClusterType)
+		context.NameInKubeconf = kubeContext.Cluster
+	case theContext.Cluster != "":
 		kubeContext.Cluster = theContext.Cluster
 	}
 	if theContext.AuthInfo != "" {
@@ -678,9 +751,8 @@ func (c *Config) CurrentContextCluster() (*Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
-	clusterName := NewClusterComplexNameFromKubeClusterName(currentContext.KubeContext().Cluster)
-
-	return c.Clusters[clusterName.Name].ClusterTypes[currentContext.ClusterType()], nil
+	clusterName := c.ContextClusterComplexName(currentContext)
+	return c.GetCluster(clusterName.Name, clusterName.Type)
 }
 
 // CurrentContextAuthInfo returns the AuthInfo for the current context
@@ -745,7 +817,7 @@ func (c *Config) CurrentContextClusterType() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return context.ClusterType(), nil
+	return c.ContextClusterComplexName(context).Type, nil
 }
 
 // CurrentContextClusterName returns cluster name of current context
@@ -754,7 +826,61 @@ func (c *Config) CurrentContextClusterName() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return context.ClusterName(), nil
+	return c.ContextClusterComplexName(context).Name, nil
+}
+
+// ClusterNames returns the sorted names of the clusters which are defined
+// with the given cluster type
+func (c *Config) ClusterNames(clusterType string) []string {
+	names := []string{}
+	for name, clusterPurpose := range c.Clusters {
+		if clusterPurpose == nil {
+			continue
+		}
+		if _, exists := clusterPurpose.ClusterTypes[clusterType]; exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KubeClusterName returns the name under which the cluster with the given
+// name and type is stored in the kubeconfig
+func (c *Config) KubeClusterName(clusterName, clusterType string) string {
+	if cluster, err := c.GetCluster(clusterName, clusterType); err == nil && cluster.NameInKubeconf != "" {
+		return cluster.NameInKubeconf
+	}
+	complexName := NewClusterComplexName(clusterName, clusterType)
+	return complexName.String()
+}
+
+// ClusterComplexNameByKubeName returns the name and type of the cluster that
+// is stored in the kubeconfig as kubeClusterName. Clusters which are not
+// defined in the airship config fall back to the <name>_<type> naming
+// convention.
+func (c *Config) ClusterComplexNameByKubeName(kubeClusterName string) ClusterComplexName {
+	for name, clusterPurpose := range c.Clusters {
+		if clusterPurpose == nil {
+			continue
+		}
+		for clusterType, cluster := range clusterPurpose.ClusterTypes {
+			if cluster != nil && cluster.NameInKubeconf == kubeClusterName {
+				return NewClusterComplexName(name, clusterType)
+			}
+		}
+	}
+	return NewClusterComplexNameFromKubeClusterName(kubeClusterName)
+}
+
+// ContextClusterComplexName returns the name and type of the cluster the
+// given context refers to
+func (c *Config) ContextClusterComplexName(context *Context) ClusterComplexName {
+	kubeClusterName := context.NameInKubeconf
+	if kubeContext := context.KubeContext(); kubeClusterName == "" && kubeContext != nil {
+		kubeClusterName = kubeContext.Cluster
+	}
+	return c.ClusterComplexNameByKubeName(kubeClusterName)
 }
 
 // GetAuthInfo returns an instance of authino
@@ -829,6 +955,89 @@ func (c *Config) ModifyAuthInfo(authinfo *AuthInfo, theAuthInfo *AuthInfoOptions
 	if theAuthInfo.ClientKey != "" {
 		kubeAuthInfo.ClientKey = EncodeString(theAuthInfo.ClientKey)
 	}
+	modifyAuthProvider(kubeAuthInfo, theAuthInfo)
+	modifyExec(kubeAuthInfo, theAuthInfo)
+}
+
+// modifyAuthProvider updates the auth provider plugin configuration of
+// kubeAuthInfo. Changing the name of the provider drops its configuration.
+// Setting a provider removes the exec plugin, since both cannot be used at
+// the same time.
+func modifyAuthProvider(kubeAuthInfo *clientcmdapi.AuthInfo, theAuthInfo *AuthInfoOptions) {
+	if theAuthInfo.AuthProvider != "" {
+		kubeAuthInfo.Exec = nil
+		if kubeAuthInfo.AuthProvider == nil || kubeAuthInfo.AuthProvider.Name != theAuthInfo.AuthProvider {
+			kubeAuthInfo.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: theAuthInfo.AuthProvider}
+		}
+	}
+	if kubeAuthInfo.AuthProvider == nil {
+		return
+	}
+
+	// The args were checked by Validate
+	set, remove, _ := parseKeyValues("auth-provider-arg", theAuthInfo.AuthProviderArgs)
+	if kubeAuthInfo.AuthProvider.Config == nil && len(set) > 0 {
+		kubeAuthInfo.AuthProvider.Config = map[string]string{}
+	}
+	for key, value := range set {
+		kubeAuthInfo.AuthProvider.Config[key] = value
+	}
+	for _, key := range remove {
+		delete(kubeAuthInfo.AuthProvider.Config, key)
+	}
+}
+
+// modifyExec updates the exec credential plugin configuration of
+// kubeAuthInfo. Setting a command removes the auth provider.
+func modifyExec(kubeAuthInfo *clientcmdapi.AuthInfo, theAuthInfo *AuthInfoOptions) {
+	if theAuthInfo.ExecCommand != "" {
+		kubeAuthInfo.AuthProvider = nil
+		if kubeAuthInfo.Exec == nil {
+			kubeAuthInfo.Exec = &clientcmdapi.ExecConfig{APIVersion: DefaultExecAPIVersion}
+		}
+		kubeAuthInfo.Exec.Command = theAuthInfo.ExecCommand
+	}
+	if kubeAuthInfo.Exec == nil {
+		return
+	}
+
+	if theAuthInfo.ExecAPIVersion != "" {
+		kubeAuthInfo.Exec.APIVersion = theAuthInfo.ExecAPIVersion
+	}
+	if theAuthInfo.ExecArgs != nil {
+		kubeAuthInfo.Exec.Args = theAuthInfo.ExecArgs
+	}
+
+	// The env vars were checked by Validate
+	set, remove, _ := parseKeyValues("exec-env", theAuthInfo.ExecEnv)
+	removed := make(map[string]bool, len(remove))
+	for _, name := range remove {
+		removed[name] = true
+	}
+	env := make([]clientcmdapi.ExecEnvVar, 0, len(kubeAuthInfo.Exec.Env)+len(set))
+	for _, envVar := range kubeAuthInfo.Exec.Env {
+		value, updated := set[envVar.Name]
+		switch {
+		case updated:
+			envVar.Value = value
+			delete(set, envVar.Name)
+		case removed[envVar.Name]:
+			continue
+		}
+		env = append(env, envVar)
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, clientcmdapi.ExecEnvVar{Name: name, Value: set[name]})
+	}
+	if len(env) == 0 {
+		env = nil
+	}
+	kubeAuthInfo.Exec.Env = env
 }
 
 // ImportFromKubeConfig absorbs the clusters, contexts and credentials from the
@@ -918,6 +1127,63 @@ func (c *Config) importAuthInfos(importKubeConfig *clientcmdapi.Config) {
 	}
 }
 
+// DiscoverKubeConfig loads the kubeconfig at kubeConfigPath. If no path is
+// given, the kubeconfig kubectl would use is loaded, taking the KUBECONFIG
+// environment variable and $HOME/.kube/config into account.
+func DiscoverKubeConfig(kubeConfigPath string) (*clientcmdapi.Config, error) {
+	if kubeConfigPath != "" {
+		return clientcmd.LoadFromFile(kubeConfigPath)
+	}
+	return clientcmd.NewDefaultClientConfigLoadingRules().Load()
+}
+
+// ImportContext imports the context named contextName from kubeConfig,
+// along with the cluster and credentials it refers to, and binds it to the
+// given manifest. Existing contexts take precedence, in which case nothing
+// is imported and false is returned.
+func (c *Config) ImportContext(kubeConfig *clientcmdapi.Config, contextName, manifest string) (bool, error) {
+	kubeContext, ok := kubeConfig.Contexts[contextName]
+	if !ok {
+		return false, ErrMissingConfig{What: fmt.Sprintf("Context with name '%s'", contextName)}
+	}
+	if _, ok = c.Manifests[manifest]; !ok {
+		return false, ErrMissingConfig{What: fmt.Sprintf("Manifest with name '%s'", manifest)}
+	}
+	if _, ok = c.Contexts[contextName]; ok {
+		return false, nil
+	}
+	if _, ok = c.kubeConfig.Contexts[contextName]; ok {
+		return false, nil
+	}
+
+	// Only import the objects referred to by the chosen context, which must
+	// all exist. A context may have no user.
+	subset := clientcmdapi.NewConfig()
+	subset.Contexts[contextName] = kubeContext
+	cluster, exists := kubeConfig.Clusters[kubeContext.Cluster]
+	if !exists {
+		return false, ErrMissingConfig{
+			What: fmt.Sprintf("Cluster with name '%s' of context '%s'", kubeContext.Cluster, contextName),
+		}
+	}
+	subset.Clusters[kubeContext.Cluster] = cluster
+	if kubeContext.AuthInfo != "" {
+		authInfo, exists := kubeConfig.AuthInfos[kubeContext.AuthInfo]
+		if !exists {
+			return false, ErrMissingConfig{
+				What: fmt.Sprintf("User with name '%s' of context '%s'", kubeContext.AuthInfo, contextName),
+			}
+		}
+		subset.AuthInfos[kubeContext.AuthInfo] = authInfo
+	}
+
+	c.importClusters(subset)
+	c.importContexts(subset)
+	c.importAuthInfos(subset)
+	c.Contexts[contextName].Manifest = manifest
+	return true, nil
+}
+
 // CurrentContextBootstrapInfo returns bootstrap info for current context
 func (c *Config) CurrentContextBootstrapInfo() (*Bootstrap, error) {
 	currentCluster, err := c.CurrentContextCluster()
@@ -959,6 +1225,80 @@ func (c *Config) CurrentContextManagementConfig() (*ManagementConfiguration, err
 	return managementCfg, nil
 }
 
+// GetEncryptionConfig returns the encryption configuration with the given name
+func (c *Config) GetEncryptionConfig(name string) (*EncryptionConfig, error) {
+	encryptionConfig, exists := c.EncryptionConfigs[name]
+	if !exists {
+		return nil, ErrMissingConfig{What: fmt.Sprintf("Encryption config with name '%s'", name)}
+	}
+	return encryptionConfig, nil
+}
+
+// AddEncryptionConfig creates a new encryption configuration from the given
+// options
+func (c *Config) AddEncryptionConfig(theEncryptionConfig *EncryptionConfigOptions) (*EncryptionConfig, error) {
+	encryptionConfig := &EncryptionConfig{}
+	if err := c.ModifyEncryptionConfig(encryptionConfig, theEncryptionConfig); err != nil {
+		return nil, err
+	}
+	if c.EncryptionConfigs == nil {
+		c.EncryptionConfigs = map[string]*EncryptionConfig{}
+	}
+	c.EncryptionConfigs[theEncryptionConfig.Name] = encryptionConfig
+	return encryptionConfig, nil
+}
+
+// ModifyEncryptionConfig updates the encryption configuration with the given
+// options and makes the manifests listed in them refer to it. The lists of
+// KMS URIs and SOPS recipients replace the previous ones when given.
+func (c *Config) ModifyEncryptionConfig(encryptionConfig *EncryptionConfig,
+	theEncryptionConfig *EncryptionConfigOptions) error {
+	for _, name := range theEncryptionConfig.Manifests {
+		if _, exists := c.Manifests[name]; !exists {
+			return ErrMissingConfig{What: fmt.Sprintf("Manifest with name '%s'", name)}
+		}
+	}
+
+	if theEncryptionConfig.EncryptionKeyPath != "" {
+		encryptionConfig.EncryptionKeyPath = theEncryptionConfig.EncryptionKeyPath
+	}
+	if theEncryptionConfig.DecryptionKeyPath != "" {
+		encryptionConfig.DecryptionKeyPath = theEncryptionConfig.DecryptionKeyPath
+	}
+	if theEncryptionConfig.KMSURIs != nil {
+		encryptionConfig.KMSURIs = theEncryptionConfig.KMSURIs
+	}
+	if theEncryptionConfig.SOPSRecipients != nil {
+		encryptionConfig.SOPSRecipients = theEncryptionConfig.SOPSRecipients
+	}
+	for _, name := range theEncryptionConfig.Manifests {
+		c.Manifests[name].EncryptionConfig = theEncryptionConfig.Name
+	}
+	return nil
+}
+
+// CurrentContextEncryptionConfig returns the encryption configuration of
+// the manifest of the current context
+func (c *Config) CurrentContextEncryptionConfig() (*EncryptionConfig, error) {
+	currentContext, err := c.GetCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, exists := c.Manifests[currentContext.Manifest]
+	if !exists {
+		return nil, ErrMissingConfig{What: fmt.Sprintf("Manifest with name '%s'", currentContext.Manifest)}
+	}
+
+	if manifest.EncryptionConfig == "" {
+		return nil, ErrMissingConfig{
+			What: fmt.Sprintf("No encryption config listed for manifest %s", currentContext.Manifest),
+		}
+	}
+
+	return c.GetEncryptionConfig(manifest.EncryptionConfig)
+}
+
 // Purge removes the config file
 func (c *Config) Purge() error {
 	return os.Remove(c.loadedConfigPath)
@@ -151,6 +151,45 @@ func RunSetContext(o *ContextOptions, airconfig *Config, writeToStorage bool) (b
 	return modified, nil
 }
 
+// RunSetEncryptionConfig validates the given command line options and invokes
+// AddEncryptionConfig/ModifyEncryptionConfig
+func RunSetEncryptionConfig(o *EncryptionConfigOptions, airconfig *Config, writeToStorage bool) (bool, error) {
+	modified := false
+	err := o.Validate()
+	if err != nil {
+		return modified, err
+	}
+
+	encryptionConfig, err := airconfig.GetEncryptionConfig(o.Name)
+	if err != nil {
+		var cerr ErrMissingConfig
+		if !errors.As(err, &cerr) {
+			// An error occurred, but it wasn't a "missing" config error.
+			return modified, err
+		}
+
+		// encryption config didn't exist, create it
+		if _, err = airconfig.AddEncryptionConfig(o); err != nil {
+			return modified, err
+		}
+	} else {
+		// encryption config exists, lets update
+		if err = airconfig.ModifyEncryptionConfig(encryptionConfig, o); err != nil {
+			return modified, err
+		}
+		modified = true
+	}
+
+	// Update configuration file just in time persistence approach
+	if writeToStorage {
+		if err := airconfig.PersistConfig(); err != nil {
+			return modified, err
+		}
+	}
+
+	return modified, nil
+}
+
 // RunUseContext validates the given context name and updates it as current context
 func RunUseContext(desiredContext string, airconfig *Config) error {
 	if _, err := airconfig.GetContext(desiredContext); err != nil {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	kubeconfig "k8s.io/client-go/tools/clientcmd/api"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/testutil"
@@ -404,6 +405,23 @@ func TestModifyContext(t *testing.T) {
 	assert.EqualValues(t, conf.Contexts[co.Name], context)
 }
 
+func TestModifyContextClusterType(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	co := testutil.DummyContextOptions()
+	co.Cluster = "def"
+	co.ClusterType = config.Target
+	context := conf.AddContext(co)
+	assert.Equal(t, "def_target", context.KubeContext().Cluster)
+	assert.Equal(t, config.NewClusterComplexName("def", config.Target), conf.ContextClusterComplexName(context))
+
+	// Changing only the cluster type keeps the cluster
+	conf.ModifyContext(context, &config.ContextOptions{ClusterType: config.Ephemeral})
+	assert.Equal(t, "def_ephemeral", context.KubeContext().Cluster)
+	assert.Equal(t, "def_ephemeral", context.NameInKubeconf)
+}
+
 func TestGetCurrentContext(t *testing.T) {
 	t.Run("getCurrentContext", func(t *testing.T) {
 		conf, cleanup := testutil.InitConfig(t)
@@ -440,6 +458,18 @@ func TestCurrentContextCluster(t *testing.T) {
 	cluster, err = conf.CurrentContextCluster()
 	require.NoError(t, err)
 	assert.Equal(t, conf.Clusters[clusterName].ClusterTypes[clusterType], cluster)
+
+	// The cluster of the context is missing
+	conf.Contexts[currentContextName].NameInKubeconf = "missing_ephemeral"
+	cluster, err = conf.CurrentContextCluster()
+	assert.Equal(t, config.ErrMissingConfig{What: "Cluster with name 'missing' of type 'ephemeral'"}, err)
+	assert.Nil(t, cluster)
+
+	conf.Contexts[currentContextName].NameInKubeconf = "def_target"
+	delete(conf.Clusters[clusterName].ClusterTypes, "target")
+	cluster, err = conf.CurrentContextCluster()
+	assert.Equal(t, config.ErrMissingConfig{What: "Cluster with name 'def' of type 'target'"}, err)
+	assert.Nil(t, cluster)
 }
 
 func TestCurrentContextAuthInfo(t *testing.T) {
@@ -551,6 +581,31 @@ func TestCurrentContextClusterName(t *testing.T) {
 	assert.Equal(t, expectedClusterName, actualClusterName)
 }
 
+func TestClusterNames(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	assert.Equal(t, []string{"def"}, conf.ClusterNames(config.Ephemeral))
+	assert.Equal(t, []string{"clustertypenil", "def", "invalidName", "onlyinkubeconf", "wrongonlyinkubeconf"},
+		conf.ClusterNames(config.Target))
+	assert.Empty(t, conf.ClusterNames("unknown"))
+}
+
+func TestClusterComplexNameByKubeName(t *testing.T) {
+	conf := testutil.DummyConfig()
+	// The name in kubeconfig doesn't have to follow the <name>_<type> convention
+	conf.Clusters["dummy_cluster"].ClusterTypes[config.Ephemeral].NameInKubeconf = "kind-ephemeral"
+
+	assert.Equal(t, config.NewClusterComplexName("dummy_cluster", config.Ephemeral),
+		conf.ClusterComplexNameByKubeName("kind-ephemeral"))
+	assert.Equal(t, "kind-ephemeral", conf.KubeClusterName("dummy_cluster", config.Ephemeral))
+
+	// Unknown clusters fall back to the naming convention
+	assert.Equal(t, config.NewClusterComplexName("other", config.Ephemeral),
+		conf.ClusterComplexNameByKubeName("other_ephemeral"))
+	assert.Equal(t, "other_target", conf.KubeClusterName("other", config.Target))
+}
+
 func TestNewClusterComplexNameFromKubeClusterName(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -707,6 +762,54 @@ users:
 	})
 }
 
+func TestImportContext(t *testing.T) {
+	conf := testutil.DummyConfig()
+
+	kubeConfig := kubeconfig.NewConfig()
+	kubeConfig.Clusters["discovered"] = &kubeconfig.Cluster{Server: "https://1.2.3.4:6443"}
+	kubeConfig.AuthInfos["discovered-admin"] = &kubeconfig.AuthInfo{Token: "dummy_token"}
+	kubeConfig.Contexts["discovered-admin@discovered"] = &kubeconfig.Context{
+		Cluster:  "discovered",
+		AuthInfo: "discovered-admin",
+	}
+	kubeConfig.Contexts["dummy_context"] = &kubeconfig.Context{Cluster: "discovered"}
+
+	imported, err := conf.ImportContext(kubeConfig, "discovered-admin@discovered", "dummy_manifest")
+	require.NoError(t, err)
+	assert.True(t, imported)
+
+	context, err := conf.GetContext("discovered-admin@discovered")
+	require.NoError(t, err)
+	assert.Equal(t, "dummy_manifest", context.Manifest)
+	assert.Equal(t, "discovered_target", context.NameInKubeconf)
+	_, err = conf.GetCluster("discovered", config.Target)
+	assert.NoError(t, err)
+	assert.Contains(t, conf.AuthInfos, "discovered-admin")
+
+	// Existing contexts take precedence
+	imported, err = conf.ImportContext(kubeConfig, "dummy_context", "dummy_manifest")
+	require.NoError(t, err)
+	assert.False(t, imported)
+	assert.Equal(t, "dummy_cluster_ephemeral", conf.Contexts["dummy_context"].NameInKubeconf)
+
+	_, err = conf.ImportContext(kubeConfig, "nonexistent", "dummy_manifest")
+	assert.Equal(t, config.ErrMissingConfig{What: "Context with name 'nonexistent'"}, err)
+
+	_, err = conf.ImportContext(kubeConfig, "discovered-admin@discovered", "nonexistent")
+	assert.Equal(t, config.ErrMissingConfig{What: "Manifest with name 'nonexistent'"}, err)
+
+	// Contexts referring to missing clusters or users are not imported
+	kubeConfig.Contexts["no-cluster"] = &kubeconfig.Context{Cluster: "missing", AuthInfo: "discovered-admin"}
+	_, err = conf.ImportContext(kubeConfig, "no-cluster", "dummy_manifest")
+	assert.Equal(t, config.ErrMissingConfig{What: "Cluster with name 'missing' of context 'no-cluster'"}, err)
+	assert.NotContains(t, conf.Contexts, "no-cluster")
+
+	kubeConfig.Contexts["no-user"] = &kubeconfig.Context{Cluster: "discovered", AuthInfo: "missing"}
+	_, err = conf.ImportContext(kubeConfig, "no-user", "dummy_manifest")
+	assert.Equal(t, config.ErrMissingConfig{What: "User with name 'missing' of context 'no-user'"}, err)
+	assert.NotContains(t, conf.Contexts, "no-user")
+}
+
 func TestImportErrors(t *testing.T) {
 	conf, cleanupConfig := testutil.InitConfig(t)
 	defer cleanupConfig(t)
@@ -731,3 +834,32 @@ func TestImportErrors(t *testing.T) {
 		assert.Contains(t, err.Error(), "json parse error")
 	})
 }
+
+func TestCurrentContextEncryptionConfig(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+	conf.CurrentContext = currentContextName
+	conf.Manifests[defaultString] = config.NewManifest()
+	conf.Contexts[currentContextName].Manifest = defaultString
+
+	_, err := conf.CurrentContextEncryptionConfig()
+	assert.Error(t, err)
+
+	_, err = conf.AddEncryptionConfig(&config.EncryptionConfigOptions{
+		Name:              "keys",
+		EncryptionKeyPath: "/tmp/public.key",
+		Manifests:         []string{defaultString},
+	})
+	require.NoError(t, err)
+
+	encryptionConfig, err := conf.CurrentContextEncryptionConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/public.key", encryptionConfig.EncryptionKeyPath)
+
+	_, err = conf.AddEncryptionConfig(&config.EncryptionConfigOptions{
+		Name:      "other",
+		Manifests: []string{"unknown"},
+	})
+	assert.Error(t, err)
+	assert.NotContains(t, conf.EncryptionConfigs, "other")
+}
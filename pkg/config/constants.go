@@ -14,7 +14,11 @@
 
 package config
 
-import "opendev.org/airship/airshipctl/pkg/remote/redfish"
+import (
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
 
 // Constants related to the ClusterType type
 const (
@@ -29,6 +33,8 @@ const (
 	InitinfraPhase  = "initinfra"
 	ClusterctlPhase = InitinfraPhase
 	BootstrapPhase  = "bootstrap"
+	// PhasesEntryPoint holds the Phase documents of a cluster type
+	PhasesEntryPoint = "phases"
 )
 
 // AllClusterTypes holds cluster types
@@ -39,6 +45,7 @@ const (
 	AirshipConfig                         = "config"
 	AirshipConfigAPIVersion               = AirshipConfigGroup + "/" + AirshipConfigVersion
 	AirshipConfigDir                      = ".airship"
+	AirshipConfigDirEnv                   = "AIRSHIP_CONFIG_DIR"
 	AirshipConfigEnv                      = "AIRSHIPCONFIG"
 	AirshipConfigGroup                    = "airshipit.org"
 	AirshipConfigKind                     = "Config"
@@ -53,6 +60,8 @@ const (
 	AirshipKubeConfigEnv                  = "AIRSHIP_KUBECONFIG"
 	AirshipPluginPath                     = "kustomize-plugins"
 	AirshipPluginPathEnv                  = "AIRSHIP_KUSTOMIZE_PLUGINS"
+	AirshipProfileEnv                     = "AIRSHIP_PROFILE"
+	AirshipXDGConfigDir                   = "airship"
 
 	// Modules
 	AirshipDefaultBootstrapImage = "quay.io/airshipit/isogen:latest-debian_stable"
@@ -60,8 +69,18 @@ const (
 	AirshipDefaultManagementType = redfish.ClientType
 )
 
+// AirshipConfigLockTimeout is how long to wait for other airshipctl
+// processes to finish writing the config files
+const AirshipConfigLockTimeout = 10 * time.Second
+
 // Default values for remote operations
 const (
 	DefaultSystemActionRetries = 30
 	DefaultSystemRebootDelay   = 30
+	DefaultRequestRetries      = 3
+	DefaultRequestRetryDelay   = 10
 )
+
+// DefaultExecAPIVersion is the ExecCredential API version used for exec
+// credential plugins unless another one is given
+const DefaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
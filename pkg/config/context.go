@@ -67,13 +67,15 @@ func (c *Context) SetKubeContext(kc *api.Context) {
 }
 
 // ClusterType returns cluster type by extracting the type portion from
-// the complex cluster name
+// the complex cluster name. Config.ContextClusterComplexName should be
+// preferred, since it takes the clusters defined in the config into account.
 func (c *Context) ClusterType() string {
 	return NewClusterComplexNameFromKubeClusterName(c.NameInKubeconf).Type
 }
 
 // ClusterName returns cluster name by extracting the name portion from
-// the complex cluster name
+// the complex cluster name. Config.ContextClusterComplexName should be
+// preferred, since it takes the clusters defined in the config into account.
 func (c *Context) ClusterName() string {
 	return NewClusterComplexNameFromKubeClusterName(c.NameInKubeconf).Name
 }
@@ -0,0 +1,156 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RedactedValue replaces the values of secrets in differences
+const RedactedValue = "<redacted>"
+
+// secretKeys are the keys of the airship config holding secrets
+var secretKeys = map[string]bool{
+	"keyPass":  true,
+	"httpPass": true,
+	"sshPass":  true,
+}
+
+// DiffType tells how an element of the airship config changed
+type DiffType string
+
+// Types of differences
+const (
+	DiffAdded    DiffType = "+"
+	DiffRemoved  DiffType = "-"
+	DiffModified DiffType = "~"
+)
+
+// Difference describes an element of the airship config that differs
+// between two versions of it
+type Difference struct {
+	Type DiffType
+	// Path is the dot separated path to the element, e.g. contexts.dev.manifest
+	Path string
+	// Old and New are the values of modified scalar elements, secrets are
+	// replaced with RedactedValue
+	Old string
+	New string
+}
+
+func (d Difference) String() string {
+	if d.Type != DiffModified {
+		return fmt.Sprintf("%s %s", d.Type, d.Path)
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", d.Type, d.Path, d.Old, d.New)
+}
+
+// DiffConfigs compares two airship config documents and returns their
+// differences sorted by path. Both documents are migrated to the current
+// schema first, so configs written by older versions compare cleanly.
+// Lists are compared as a whole.
+func DiffConfigs(oldData, newData []byte) ([]Difference, error) {
+	oldConfig, err := unmarshalForDiff(oldData)
+	if err != nil {
+		return nil, err
+	}
+	newConfig, err := unmarshalForDiff(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []Difference
+	diffValues("", oldConfig, newConfig, false, &diffs)
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+func unmarshalForDiff(data []byte) (map[string]interface{}, error) {
+	migrated, _, err := MigrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]interface{}{}
+	if err = yaml.Unmarshal(migrated, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func diffValues(path string, oldValue, newValue interface{}, secret bool, diffs *[]Difference) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	switch {
+	case oldIsMap && newIsMap:
+		diffMaps(path, oldMap, newMap, diffs)
+	case oldIsMap || newIsMap:
+		*diffs = append(*diffs,
+			Difference{Type: DiffRemoved, Path: path},
+			Difference{Type: DiffAdded, Path: path})
+	default:
+		oldString, newString := renderDiffValue(oldValue), renderDiffValue(newValue)
+		if oldString == newString {
+			return
+		}
+		if secret {
+			oldString, newString = RedactedValue, RedactedValue
+		}
+		*diffs = append(*diffs, Difference{Type: DiffModified, Path: path, Old: oldString, New: newString})
+	}
+}
+
+func diffMaps(path string, oldMap, newMap map[string]interface{}, diffs *[]Difference) {
+	for key, oldValue := range oldMap {
+		keyPath := joinDiffPath(path, key)
+		newValue, exists := newMap[key]
+		if !exists {
+			*diffs = append(*diffs, Difference{Type: DiffRemoved, Path: keyPath})
+			continue
+		}
+		diffValues(keyPath, oldValue, newValue, secretKeys[key], diffs)
+	}
+	for key := range newMap {
+		if _, exists := oldMap[key]; !exists {
+			*diffs = append(*diffs, Difference{Type: DiffAdded, Path: joinDiffPath(path, key)})
+		}
+	}
+}
+
+func joinDiffPath(path, key string) string {
+	// Keep paths readable when names contain dots
+	if strings.Contains(key, ".") {
+		key = fmt.Sprintf("%q", key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func renderDiffValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,84 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	oldConfig := []byte(`contexts:
+  dummy_context:
+    manifest: dummy_manifest
+currentContext: dummy_context
+manifests:
+  dummy_manifest:
+    repositories:
+      primary:
+        auth:
+          sshPass: secret
+    targetPath: /tmp
+`)
+	newConfig := []byte(`apiVersion: airshipit.org/v1alpha1
+kind: Config
+contexts:
+  dummy_context:
+    manifest: other_manifest
+currentContext: dummy_context
+manifests:
+  dummy_manifest:
+    repositories:
+      primary:
+        auth:
+          sshPass: changed
+    targetPath:
+      nested: value
+  other_manifest: {}
+`)
+
+	// The unversioned config is migrated before it is compared
+	diffs, err := config.DiffConfigs(oldConfig, oldConfig)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = config.DiffConfigs(oldConfig, newConfig)
+	require.NoError(t, err)
+	expected := []config.Difference{
+		{
+			Type: config.DiffModified,
+			Path: "contexts.dummy_context.manifest",
+			Old:  `"dummy_manifest"`,
+			New:  `"other_manifest"`,
+		},
+		{
+			Type: config.DiffModified,
+			Path: "manifests.dummy_manifest.repositories.primary.auth.sshPass",
+			Old:  config.RedactedValue,
+			New:  config.RedactedValue,
+		},
+		{Type: config.DiffRemoved, Path: "manifests.dummy_manifest.targetPath"},
+		{Type: config.DiffAdded, Path: "manifests.dummy_manifest.targetPath"},
+		{Type: config.DiffAdded, Path: "manifests.other_manifest"},
+	}
+	assert.Equal(t, expected, diffs)
+
+	_, err = config.DiffConfigs([]byte("apiVersion: airshipit.org/v0"), newConfig)
+	assert.Error(t, err)
+}
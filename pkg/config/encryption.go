@@ -0,0 +1,70 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+// kmsURIPattern matches <service>://<key>. The key part is not parsed as a
+// URL authority since key identifiers such as AWS ARNs contain colons.
+var kmsURIPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://.+$`)
+
+// EncryptionConfig defines the key providers used to encrypt and decrypt
+// the secrets of the manifests referring to it. Any combination of providers
+// may be set, consumers use the ones they support.
+type EncryptionConfig struct {
+	// EncryptionKeyPath is the path to the public key used to encrypt secrets
+	EncryptionKeyPath string `json:"encryptionKeyPath,omitempty"`
+
+	// DecryptionKeyPath is the path to the private key used to decrypt secrets
+	DecryptionKeyPath string `json:"decryptionKeyPath,omitempty"`
+
+	// KMSURIs are the URIs of the key management service keys used to
+	// encrypt and decrypt secrets, e.g. awskms://arn:aws:kms:... or
+	// gcpkms://projects/.../cryptoKeys/...
+	KMSURIs []string `json:"kmsURIs,omitempty"`
+
+	// SOPSRecipients are the age or PGP recipients secrets are encrypted
+	// for with SOPS
+	SOPSRecipients []string `json:"sopsRecipients,omitempty"`
+}
+
+// String converts an encryption configuration to a human-readable string.
+func (e *EncryptionConfig) String() string {
+	yamlData, err := yaml.Marshal(&e)
+	if err != nil {
+		return ""
+	}
+
+	return string(yamlData)
+}
+
+// Validate checks that the KMS URIs of an encryption configuration start
+// with a scheme identifying the key management service.
+func (e *EncryptionConfig) Validate() error {
+	return validateKMSURIs(e.KMSURIs)
+}
+
+func validateKMSURIs(uris []string) error {
+	for _, uri := range uris {
+		if !kmsURIPattern.MatchString(uri) {
+			return ErrInvalidKMSURI{URI: uri}
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 )
@@ -140,6 +141,26 @@ func (e ErrConflictingAuthOptions) Error() string {
 	return "Specifying token and username/password is not allowed at the same time."
 }
 
+// ErrConflictingAuthPluginOptions returned when both an auth provider and an
+// exec plugin are set at same time
+type ErrConflictingAuthPluginOptions struct {
+}
+
+func (e ErrConflictingAuthPluginOptions) Error() string {
+	return "Specifying auth-provider and exec-command is not allowed at the same time."
+}
+
+// ErrInvalidKeyValue returned when a flag expecting key=value pairs is
+// given something else
+type ErrInvalidKeyValue struct {
+	Flag  string
+	Given string
+}
+
+func (e ErrInvalidKeyValue) Error() string {
+	return fmt.Sprintf("Invalid value %q for %s, expected key=value or key- to remove a key.", e.Given, e.Flag)
+}
+
 // ErrConflictingClusterOptions returned when both certificate-authority and
 // insecure-skip-tls-verify is set at same time
 type ErrConflictingClusterOptions struct {
@@ -149,6 +170,37 @@ func (e ErrConflictingClusterOptions) Error() string {
 	return "Specifying certificate-authority and insecure-skip-tls-verify mode is not allowed at the same time."
 }
 
+// ErrConflictingCertificateAuthorityOptions returned when both certificate-authority and
+// certificate-authority-data is set at same time
+type ErrConflictingCertificateAuthorityOptions struct {
+}
+
+func (e ErrConflictingCertificateAuthorityOptions) Error() string {
+	return "Specifying certificate-authority and certificate-authority-data is not allowed at the same time."
+}
+
+// ErrConflictingCertificateAuthoritySecret returned when
+// certificate-authority-secret is set together with certificate-authority or
+// certificate-authority-data
+type ErrConflictingCertificateAuthoritySecret struct {
+}
+
+func (e ErrConflictingCertificateAuthoritySecret) Error() string {
+	return "Specifying certificate-authority-secret together with certificate-authority or " +
+		"certificate-authority-data is not allowed."
+}
+
+// ErrInvalidCertificateAuthority returned when certificate authority data
+// doesn't contain a base64 or PEM encoded certificate
+type ErrInvalidCertificateAuthority struct {
+	Source string
+}
+
+func (e ErrInvalidCertificateAuthority) Error() string {
+	return fmt.Sprintf("Certificate authority data from %s must contain a PEM encoded certificate, "+
+		"optionally base64 encoded.", e.Source)
+}
+
 // ErrEmptyClusterName returned when empty cluster name is set
 type ErrEmptyClusterName struct {
 }
@@ -189,6 +241,90 @@ type ErrUnknownManagementType struct {
 }
 
 func (e ErrUnknownManagementType) Error() string {
-	return fmt.Sprintf("Unknown management type '%s'. Known types include '%s' and '%s'.", e.Type,
-		redfish.ClientType, redfishdell.ClientType)
+	return fmt.Sprintf("Unknown management type '%s'. Known types include '%s', '%s' and '%s'.", e.Type,
+		redfish.ClientType, redfishdell.ClientType, ipmi.ClientType)
+}
+
+// ErrIncompleteClientCertificate is returned when only one of the client certificate and key is given in a
+// management configuration.
+type ErrIncompleteClientCertificate struct{}
+
+func (e ErrIncompleteClientCertificate) Error() string {
+	return "Management configuration requires both clientCertificate and clientKey for TLS client authentication."
+}
+
+// ErrUnsupportedConfigVersion is returned when the airship config file was
+// written with an apiVersion that cannot be migrated to the current one
+type ErrUnsupportedConfigVersion struct {
+	Version string
+}
+
+func (e ErrUnsupportedConfigVersion) Error() string {
+	return fmt.Sprintf("Unsupported airship config apiVersion %q, expected %q.", e.Version, AirshipConfigAPIVersion)
+}
+
+// ErrConflictingDiscoverOptions returned when both --import-context and --all
+// are given to config discover
+type ErrConflictingDiscoverOptions struct {
+}
+
+func (e ErrConflictingDiscoverOptions) Error() string {
+	return "Specifying --import-context and --all Flag is not allowed at the same time."
+}
+
+// ErrInvalidContextSelection returned when a selected context doesn't match
+// any of the discovered contexts
+type ErrInvalidContextSelection struct {
+	Selection string
+}
+
+func (e ErrInvalidContextSelection) Error() string {
+	return fmt.Sprintf("Invalid context selection %q.", e.Selection)
+}
+
+// ErrBackupPassphraseRequired returned when an encrypted backup is restored
+// without a passphrase
+type ErrBackupPassphraseRequired struct {
+}
+
+func (e ErrBackupPassphraseRequired) Error() string {
+	return "Backup is encrypted, a passphrase is required to restore it."
+}
+
+// ErrInvalidBackup returned when a backup can't be read or doesn't contain
+// a valid airship config
+type ErrInvalidBackup struct {
+	Reason string
+}
+
+func (e ErrInvalidBackup) Error() string {
+	return fmt.Sprintf("Invalid airship config backup: %s.", e.Reason)
+}
+
+// ErrEmptyEncryptionConfigName returned when empty encryption config name is set
+type ErrEmptyEncryptionConfigName struct {
+}
+
+func (e ErrEmptyEncryptionConfigName) Error() string {
+	return "Encryption config name must not be empty."
+}
+
+// ErrInvalidKMSURI returned when a KMS URI doesn't identify the key
+// management service by its scheme
+type ErrInvalidKMSURI struct {
+	URI string
+}
+
+func (e ErrInvalidKMSURI) Error() string {
+	return fmt.Sprintf("Invalid KMS URI %q, expected <service>://<key>.", e.URI)
+}
+
+// ErrInvalidRequestTimeout returned when the request timeout of the REST
+// client settings is not a positive duration
+type ErrInvalidRequestTimeout struct {
+	Value string
+}
+
+func (e ErrInvalidRequestTimeout) Error() string {
+	return fmt.Sprintf("Invalid request timeout %q, expected a duration such as 30s.", e.Value)
 }
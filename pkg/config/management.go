@@ -17,6 +17,7 @@ package config
 import (
 	"sigs.k8s.io/yaml"
 
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 )
@@ -31,6 +32,28 @@ type ManagementConfiguration struct {
 	// Insecure indicates whether the SSL certificate should be checked on remote management requests.
 	Insecure bool `json:"insecure,omitempty"`
 
+	// CertificateAuthority is the path of a PEM file with the certificates of the CAs that sign the certificates of
+	// BMCs, e.g. the self-signed certificates on an isolated management network. They are trusted in addition to
+	// the CAs of the system.
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+
+	// ClientCertificate and ClientKey are the paths of the PEM files with the certificate and private key used to
+	// authenticate to BMCs that require TLS client authentication.
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	ClientKey         string `json:"clientKey,omitempty"`
+
+	// RequestRetries is the number of times a request to a BMC is retried when it fails with a transient error, e.g.
+	// a 500 response while the BMC resets.
+	RequestRetries int `json:"requestRetries,omitempty"`
+
+	// RequestRetryDelay is the number of seconds to wait before retrying a request, unless the BMC asks for another
+	// delay.
+	RequestRetryDelay int `json:"requestRetryDelay,omitempty"`
+
+	// SessionCacheTTL is the number of seconds Redfish sessions are kept in a file to be reused by later commands,
+	// which is only readable by its owner. Sessions are only reused for the duration of a command if it is 0.
+	SessionCacheTTL int `json:"sessionCacheTTL,omitempty"`
+
 	// SystemActionRetries is the number of attempts to poll a host for a status.
 	SystemActionRetries int `json:"systemActionRetries,omitempty"`
 
@@ -68,14 +91,21 @@ func (m *ManagementConfiguration) String() string {
 	return string(yamlData)
 }
 
-// Validate validates that a management configuration is valid. Currently, this only checks the value of the management
-// type as the other fields have appropriate zero values and may be omitted.
+// Validate validates that a management configuration is valid. It checks the value of the management type and that a
+// client certificate is given together with its key, the other fields have appropriate zero values and may be
+// omitted.
 func (m *ManagementConfiguration) Validate() error {
+	if (m.ClientCertificate == "") != (m.ClientKey == "") {
+		return ErrIncompleteClientCertificate{}
+	}
+
 	switch m.Type {
 	case redfish.ClientType:
 		m.Type = redfish.ClientType
 	case redfishdell.ClientType:
 		m.Type = redfishdell.ClientType
+	case ipmi.ClientType:
+		m.Type = ipmi.ClientType
 	default:
 		return ErrUnknownManagementType{Type: m.Type}
 	}
@@ -87,6 +117,8 @@ func (m *ManagementConfiguration) Validate() error {
 func NewManagementConfiguration() *ManagementConfiguration {
 	return &ManagementConfiguration{
 		Insecure:            insecureDefaultValue,
+		RequestRetries:      DefaultRequestRetries,
+		RequestRetryDelay:   DefaultRequestRetryDelay,
 		SystemActionRetries: DefaultSystemActionRetries,
 		SystemRebootDelay:   DefaultSystemRebootDelay,
 		Type:                AirshipDefaultManagementType,
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 )
 
@@ -61,6 +62,24 @@ func TestValidateRedfishDell(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateIPMI(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.Type = ipmi.ClientType
+
+	err := cfg.Validate()
+	assert.NoError(t, err)
+}
+
+func TestValidateClientCertificate(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.ClientCertificate = "client.pem"
+
+	assert.Equal(t, config.ErrIncompleteClientCertificate{}, cfg.Validate())
+
+	cfg.ClientKey = "client-key.pem"
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestValidateInvalidManagementType(t *testing.T) {
 	cfg := config.NewManagementConfiguration()
 	cfg.Type = "invalid"
@@ -35,6 +35,9 @@ type Manifest struct {
 	// you would expect that at treasuremap/manifests you would have ephemeral/initinfra and
 	// ephemera/target directories, containing kustomize.yaml.
 	SubPath string `json:"subPath"`
+	// EncryptionConfig is the name of the encryption configuration holding
+	// the key providers for the secrets of the manifest
+	EncryptionConfig string `json:"encryptionConfig,omitempty"`
 }
 
 // Repository is a tuple that holds the information for the remote sources of manifest yaml documents.
@@ -75,10 +78,8 @@ type RepoCheckout struct {
 	Branch string `json:"branch"`
 	// Tag is the tag name to checkout
 	Tag string `json:"tag"`
-	// RemoteRef is not supported currently TODO
 	// RemoteRef is used for remote checkouts such as gerrit change requests/github pull request
 	// for example refs/changes/04/691202/5
-	// TODO Add support for fetching remote refs
 	RemoteRef string `json:"remoteRef,omitempty"`
 	// ForceCheckout is a boolean to indicate whether to use the `--force` option when checking out
 	ForceCheckout bool `json:"force"`
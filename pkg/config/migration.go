@@ -0,0 +1,115 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// AirshipConfigBackupSuffix is appended to the airship config file name
+// when a copy of it is kept before migration
+const AirshipConfigBackupSuffix = ".bak"
+
+// migrationFunc upgrades a raw airship config document in place by exactly
+// one schema version and returns the apiVersion of the resulting document
+type migrationFunc func(raw map[string]interface{}) (string, error)
+
+// migrations maps the apiVersion a config document was written with to the
+// function that upgrades it to the next known version. When the schema is
+// changed, the previous AirshipConfigAPIVersion should be added here.
+var migrations = map[string]migrationFunc{
+	// Config files written before apiVersion was introduced
+	"": migrateUnversioned,
+}
+
+// migrateUnversioned stamps kind and apiVersion onto a config file that
+// predates schema versioning. The layout of these files is identical to
+// v1alpha1.
+func migrateUnversioned(raw map[string]interface{}) (string, error) {
+	raw["kind"] = AirshipConfigKind
+	raw["apiVersion"] = AirshipConfigAPIVersion
+	return AirshipConfigAPIVersion, nil
+}
+
+// MigrateConfig upgrades the given airship config document to the current
+// schema version. The returned boolean reports whether any migration was
+// applied; if it is false the input is returned untouched.
+func MigrateConfig(data []byte) ([]byte, bool, error) {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, false, err
+	}
+
+	version, ok := raw["apiVersion"].(string)
+	if !ok && raw["apiVersion"] != nil {
+		return nil, false, ErrUnsupportedConfigVersion{Version: fmt.Sprint(raw["apiVersion"])}
+	}
+	if version == AirshipConfigAPIVersion {
+		return data, false, nil
+	}
+
+	for version != AirshipConfigAPIVersion {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, false, ErrUnsupportedConfigVersion{Version: version}
+		}
+		next, err := migrate(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		log.Debugf("Migrated airship config from %q to %q", version, next)
+		version = next
+	}
+
+	migrated, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	return migrated, true, nil
+}
+
+// migrateConfigFile upgrades the airship config file at configPath to the
+// current schema version. Before the file is rewritten its original content
+// is saved next to it with the AirshipConfigBackupSuffix. The rewritten file
+// keeps the mode of the original one since it may hold credentials.
+func migrateConfigFile(configPath string, data []byte) ([]byte, error) {
+	migrated, changed, err := MigrateConfig(data)
+	if err != nil || !changed {
+		return migrated, err
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath := configPath + AirshipConfigBackupSuffix
+	if err = ioutil.WriteFile(backupPath, data, 0600); err != nil {
+		return nil, err
+	}
+	log.Printf("Airship config %s was migrated to %s, original saved to %s",
+		configPath, AirshipConfigAPIVersion, backupPath)
+
+	if err = writeLockedFile(configPath, migrated, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return migrated, nil
+}
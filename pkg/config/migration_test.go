@@ -0,0 +1,117 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	unversionedConfigYAML = `contexts:
+  dummy_context:
+    manifest: dummy_manifest
+currentContext: dummy_context
+`
+	currentConfigYAML = `apiVersion: airshipit.org/v1alpha1
+currentContext: dummy_context
+kind: Config
+`
+	unknownConfigYAML = `apiVersion: airshipit.org/v0
+currentContext: dummy_context
+kind: Config
+`
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		given           string
+		expectedChanged bool
+		expectedErr     error
+	}{
+		{
+			name:            "current-version",
+			given:           currentConfigYAML,
+			expectedChanged: false,
+		},
+		{
+			name:            "unversioned",
+			given:           unversionedConfigYAML,
+			expectedChanged: true,
+		},
+		{
+			name:        "unknown-version",
+			given:       unknownConfigYAML,
+			expectedErr: config.ErrUnsupportedConfigVersion{Version: "airshipit.org/v0"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			migrated, changed, err := config.MigrateConfig([]byte(tt.given))
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedChanged, changed)
+
+			actual := &config.Config{}
+			require.NoError(t, yaml.Unmarshal(migrated, actual))
+			assert.Equal(t, config.AirshipConfigAPIVersion, actual.APIVersion)
+			assert.Equal(t, config.AirshipConfigKind, actual.Kind)
+			assert.Equal(t, "dummy_context", actual.CurrentContext)
+		})
+	}
+}
+
+func TestLoadConfigMigration(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "airship-migration-test")
+	defer cleanup(t)
+
+	configPath := filepath.Join(testDir, "config")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(unversionedConfigYAML), 0600))
+
+	conf := config.NewConfig()
+	require.NoError(t, conf.LoadConfig(configPath, filepath.Join(testDir, "kubeconfig")))
+	assert.Equal(t, config.AirshipConfigAPIVersion, conf.APIVersion)
+
+	// The original file must be kept as a backup
+	backup, err := ioutil.ReadFile(configPath + config.AirshipConfigBackupSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, unversionedConfigYAML, string(backup))
+
+	// The migrated file keeps the mode of the original one
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	persisted := &config.Config{}
+	data, err := ioutil.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, persisted))
+	assert.Equal(t, config.AirshipConfigAPIVersion, persisted.APIVersion)
+}
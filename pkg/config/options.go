@@ -17,8 +17,14 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
+	"crypto/x509"
+	b64 "encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 )
 
 // AuthInfoOptions holds all configurable options for
@@ -31,6 +37,36 @@ type AuthInfoOptions struct {
 	Username          string
 	Password          string
 	EmbedCertData     bool
+
+	// AuthProvider is the name of an auth provider plugin, such as oidc
+	AuthProvider string
+	// AuthProviderArgs are key=value pairs passed to the auth provider,
+	// a key followed by "-" removes it
+	AuthProviderArgs []string
+
+	// ExecCommand is the command of an exec credential plugin
+	ExecCommand string
+	// ExecAPIVersion is the API version of the ExecCredential objects
+	// exchanged with the plugin
+	ExecAPIVersion string
+	// ExecArgs replace the arguments passed to the exec plugin
+	ExecArgs []string
+	// ExecEnv are key=value pairs added to the environment of the exec
+	// plugin, a key followed by "-" removes it
+	ExecEnv []string
+}
+
+// EncryptionConfigOptions holds all configurable options for encryption
+// configuration
+type EncryptionConfigOptions struct {
+	Name              string
+	EncryptionKeyPath string
+	DecryptionKeyPath string
+	KMSURIs           []string
+	SOPSRecipients    []string
+	// Manifests are the names of the manifests that will refer to the
+	// encryption configuration
+	Manifests []string
 }
 
 // ContextOptions holds all configurable options for context
@@ -52,7 +88,10 @@ type ClusterOptions struct {
 	Server                string
 	InsecureSkipTLSVerify bool
 	CertificateAuthority  string
-	EmbedCAData           bool
+	// CertificateAuthorityData holds PEM encoded certificate authority
+	// data, optionally base64 encoded, to embed into the kubeconfig
+	CertificateAuthorityData string
+	EmbedCAData              bool
 }
 
 // TODO(howell): The following functions are tightly coupled with flags passed
@@ -74,6 +113,18 @@ func (o *AuthInfoOptions) Validate() error {
 		return ErrConflictingAuthOptions{}
 	}
 
+	if o.AuthProvider != "" && o.ExecCommand != "" {
+		return ErrConflictingAuthPluginOptions{}
+	}
+
+	if _, _, err := parseKeyValues("auth-provider-arg", o.AuthProviderArgs); err != nil {
+		return err
+	}
+
+	if _, _, err := parseKeyValues("exec-env", o.ExecEnv); err != nil {
+		return err
+	}
+
 	if !o.EmbedCertData {
 		return nil
 	}
@@ -128,10 +179,18 @@ func (o *ClusterOptions) Validate() error {
 		return err
 	}
 
-	if o.InsecureSkipTLSVerify && o.CertificateAuthority != "" {
+	if o.InsecureSkipTLSVerify && (o.CertificateAuthority != "" || o.CertificateAuthorityData != "") {
 		return ErrConflictingClusterOptions{}
 	}
 
+	if o.CertificateAuthorityData != "" {
+		if o.CertificateAuthority != "" {
+			return ErrConflictingCertificateAuthorityOptions{}
+		}
+		_, err = DecodeCertificateAuthority([]byte(o.CertificateAuthorityData), "certificate-authority-data")
+		return err
+	}
+
 	if !o.EmbedCAData {
 		return nil
 	}
@@ -140,7 +199,49 @@ func (o *ClusterOptions) Validate() error {
 		return err
 	}
 
-	return nil
+	data, err := ioutil.ReadFile(o.CertificateAuthority)
+	if err != nil {
+		return err
+	}
+	_, err = DecodeCertificateAuthority(data, o.CertificateAuthority)
+	return err
+}
+
+// DecodeCertificateAuthority returns the PEM encoded certificates contained
+// in data, which may be either PEM or base64 encoded PEM. An error is
+// returned if data doesn't hold at least one valid certificate. The source
+// is only used to describe where the data came from in errors.
+func DecodeCertificateAuthority(data []byte, source string) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("-----BEGIN")) {
+		decoded, err := b64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, ErrInvalidCertificateAuthority{Source: source}
+		}
+		data = bytes.TrimSpace(decoded)
+	}
+
+	found := false
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, ErrInvalidCertificateAuthority{Source: source}
+		}
+		found = true
+	}
+	if !found {
+		return nil, ErrInvalidCertificateAuthority{Source: source}
+	}
+
+	caData := make([]byte, 0, len(data)+1)
+	return append(append(caData, data...), '\n'), nil
 }
 
 func checkExists(flagName, path string) error {
@@ -152,3 +253,32 @@ func checkExists(flagName, path string) error {
 	}
 	return nil
 }
+
+// Validate checks for the possible encryption configuration option values
+// and returns Error when invalid value or incompatible choice of values
+// given
+func (o *EncryptionConfigOptions) Validate() error {
+	if o.Name == "" {
+		return ErrEmptyEncryptionConfigName{}
+	}
+	return validateKMSURIs(o.KMSURIs)
+}
+
+// parseKeyValues splits key=value pairs given to flag into the values to
+// set and the keys to remove, which are given as key-
+func parseKeyValues(flag string, pairs []string) (map[string]string, []string, error) {
+	set := map[string]string{}
+	var remove []string
+	for _, pair := range pairs {
+		if strings.HasSuffix(pair, "-") && !strings.Contains(pair, "=") {
+			remove = append(remove, strings.TrimSuffix(pair, "-"))
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, nil, ErrInvalidKeyValue{Flag: flag, Given: pair}
+		}
+		set[kv[0]] = kv[1]
+	}
+	return set, remove, nil
+}
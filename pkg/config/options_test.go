@@ -79,6 +79,38 @@ func TestAuthInfoOptionsValidate(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "AuthProviderAndExec",
+			testOptions: config.AuthInfoOptions{
+				AuthProvider: "oidc",
+				ExecCommand:  "kubectl",
+			},
+			expectError: true,
+		},
+		{
+			name: "AuthProviderWithArgs",
+			testOptions: config.AuthInfoOptions{
+				AuthProvider:     "oidc",
+				AuthProviderArgs: []string{"client-id=airshipctl", "refresh-token-"},
+			},
+			expectError: false,
+		},
+		{
+			name: "MalformedAuthProviderArg",
+			testOptions: config.AuthInfoOptions{
+				AuthProvider:     "oidc",
+				AuthProviderArgs: []string{"client-id"},
+			},
+			expectError: true,
+		},
+		{
+			name: "MalformedExecEnv",
+			testOptions: config.AuthInfoOptions{
+				ExecCommand: "kubectl",
+				ExecEnv:     []string{"=value"},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -160,6 +192,9 @@ func TestClusterOptionsValidate(t *testing.T) {
 	aRealFilename := aRealfile.Name()
 	defer os.Remove(aRealFilename)
 
+	caData, err := ioutil.ReadFile("testdata/ca.crt")
+	require.NoError(t, err)
+
 	tests := []struct {
 		name        string
 		testOptions config.ClusterOptions
@@ -219,13 +254,130 @@ func TestClusterOptionsValidate(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name: "EmbedWithGoodCA",
+			name: "EmbedWithEmptyCA",
 			testOptions: config.ClusterOptions{
 				Name:                 "testCluster",
 				ClusterType:          "target",
 				EmbedCAData:          true,
 				CertificateAuthority: aRealFilename,
 			},
+			expectError: true,
+		},
+		{
+			name: "EmbedWithGoodCA",
+			testOptions: config.ClusterOptions{
+				Name:                 "testCluster",
+				ClusterType:          "target",
+				EmbedCAData:          true,
+				CertificateAuthority: "testdata/ca.crt",
+			},
+			expectError: false,
+		},
+		{
+			name: "EmbedWithCAThatIsNotACertificate",
+			testOptions: config.ClusterOptions{
+				Name:                 "testCluster",
+				ClusterType:          "target",
+				EmbedCAData:          true,
+				CertificateAuthority: "testdata/test-key.pem",
+			},
+			expectError: true,
+		},
+		{
+			name: "CertificateAuthorityData",
+			testOptions: config.ClusterOptions{
+				Name:                     "testCluster",
+				ClusterType:              "target",
+				CertificateAuthorityData: string(caData),
+			},
+			expectError: false,
+		},
+		{
+			name: "InvalidCertificateAuthorityData",
+			testOptions: config.ClusterOptions{
+				Name:                     "testCluster",
+				ClusterType:              "target",
+				CertificateAuthorityData: "not base64",
+			},
+			expectError: true,
+		},
+		{
+			name: "CertificateAuthorityAndCertificateAuthorityData",
+			testOptions: config.ClusterOptions{
+				Name:                     "testCluster",
+				ClusterType:              "target",
+				CertificateAuthority:     "testdata/ca.crt",
+				CertificateAuthorityData: string(caData),
+			},
+			expectError: true,
+		},
+		{
+			name: "InsecureSkipTLSVerifyAndCertificateAuthorityData",
+			testOptions: config.ClusterOptions{
+				Name:                     "testCluster",
+				ClusterType:              "target",
+				InsecureSkipTLSVerify:    true,
+				CertificateAuthorityData: string(caData),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(subTest *testing.T) {
+			err := tt.testOptions.Validate()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCertificateAuthority(t *testing.T) {
+	encoded, err := ioutil.ReadFile("testdata/ca.crt")
+	require.NoError(t, err)
+
+	pemData, err := config.DecodeCertificateAuthority(encoded, "testdata/ca.crt")
+	require.NoError(t, err)
+	assert.Contains(t, string(pemData), "-----BEGIN CERTIFICATE-----")
+
+	// PEM data is returned as is
+	decoded, err := config.DecodeCertificateAuthority(pemData, "pem")
+	require.NoError(t, err)
+	assert.Equal(t, pemData, decoded)
+
+	_, err = config.DecodeCertificateAuthority([]byte("-----BEGIN CERTIFICATE-----\ngarbage"), "garbage")
+	assert.Equal(t, config.ErrInvalidCertificateAuthority{Source: "garbage"}, err)
+}
+
+func TestEncryptionConfigOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		testOptions config.EncryptionConfigOptions
+		expectError bool
+	}{
+		{
+			name:        "MissingName",
+			testOptions: config.EncryptionConfigOptions{},
+			expectError: true,
+		},
+		{
+			name: "InvalidKMSURI",
+			testOptions: config.EncryptionConfigOptions{
+				Name:    "testEncryptionConfig",
+				KMSURIs: []string{"arn:aws:kms:us-east-1:123456789012:key/example", "no-scheme"},
+			},
+			expectError: true,
+		},
+		{
+			name: "ValidKMSURI",
+			testOptions: config.EncryptionConfigOptions{
+				Name:    "testEncryptionConfig",
+				KMSURIs: []string{"awskms://arn:aws:kms:us-east-1:123456789012:key/example"},
+			},
 			expectError: false,
 		},
 	}
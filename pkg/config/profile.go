@@ -0,0 +1,61 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Profile is a named set of settings layered over the ones of the current
+// context, e.g. to keep dev, staging and prod defaults apart. Settings given
+// explicitly on the command line or through the environment take precedence
+// over the ones of the selected profile.
+type Profile struct {
+	// Debug enables verbose output
+	Debug bool `json:"debug,omitempty"`
+
+	// DryRun makes commands supporting it simulate their changes unless
+	// --dry-run=false is given
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// CreateNamespaces makes phase apply create missing namespaces unless
+	// --create-namespaces=false is given
+	CreateNamespaces bool `json:"createNamespaces,omitempty"`
+
+	// KubeConfig is the path to the kubeconfig used instead of the default
+	// one of the airship config directory
+	KubeConfig string `json:"kubeconfig,omitempty"`
+}
+
+// String converts a profile to a human-readable string.
+func (p *Profile) String() string {
+	yamlData, err := yaml.Marshal(&p)
+	if err != nil {
+		return ""
+	}
+
+	return string(yamlData)
+}
+
+// GetProfile returns the profile with the given name
+func (c *Config) GetProfile(name string) (*Profile, error) {
+	profile, exists := c.Profiles[name]
+	if !exists {
+		return nil, ErrMissingConfig{What: fmt.Sprintf("Profile with name '%s'", name)}
+	}
+	return profile, nil
+}
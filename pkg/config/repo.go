@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
@@ -59,9 +60,6 @@ func (c *RepoCheckout) Validate() error {
 	if count > 1 {
 		return ErrMutuallyExclusiveCheckout{}
 	}
-	if c.RemoteRef != "" {
-		return fmt.Errorf("Repository checkout by RemoteRef is not yet implemented\n%w", errors.ErrNotImplemented{})
-	}
 	return nil
 }
 
@@ -182,6 +180,8 @@ func (repo *Repository) ToCheckoutOptions(force bool) *git.CheckoutOptions {
 			co.Branch = plumbing.NewTagReferenceName(repo.CheckoutOptions.Tag)
 		case repo.CheckoutOptions.CommitHash != "":
 			co.Hash = plumbing.NewHash(repo.CheckoutOptions.CommitHash)
+		case repo.CheckoutOptions.RemoteRef != "":
+			co.Branch = plumbing.ReferenceName(repo.CheckoutOptions.RemoteRef)
 		}
 	}
 	return co
@@ -208,8 +208,30 @@ func (repo *Repository) ToCloneOptions(auth transport.AuthMethod) *git.CloneOpti
 
 // ToFetchOptions returns an instance of git.FetchOptions for given authentication
 // FetchOptions describes how a fetch should be performed
+// When a branch, tag or remote ref is configured, only that ref is fetched.
+// Branches are fetched into their remote tracking branch, the checkout then
+// fast-forwards the local branch, so that local commits are never overwritten
 func (repo *Repository) ToFetchOptions(auth transport.AuthMethod) *git.FetchOptions {
-	return &git.FetchOptions{Auth: auth}
+	fo := &git.FetchOptions{Auth: auth}
+	if repo.CheckoutOptions != nil {
+		var ref plumbing.ReferenceName
+		switch {
+		case repo.CheckoutOptions.Branch != "":
+			ref = plumbing.NewBranchReferenceName(repo.CheckoutOptions.Branch)
+		case repo.CheckoutOptions.Tag != "":
+			ref = plumbing.NewTagReferenceName(repo.CheckoutOptions.Tag)
+		case repo.CheckoutOptions.RemoteRef != "":
+			ref = plumbing.ReferenceName(repo.CheckoutOptions.RemoteRef)
+		}
+		if ref != "" {
+			dst := ref
+			if ref.IsBranch() {
+				dst = plumbing.NewRemoteReferenceName(git.DefaultRemoteName, ref.Short())
+			}
+			fo.RefSpecs = []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", ref, dst))}
+		}
+	}
+	return fo
 }
 
 // URL returns the repository URL in a string format
@@ -17,6 +17,8 @@ package config_test
 import (
 	"testing"
 
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"sigs.k8s.io/yaml"
 
 	"github.com/stretchr/testify/assert"
@@ -287,3 +289,58 @@ func TestURL(t *testing.T) {
 		assert.Equal(t, repo.URLString, repo.URL())
 	}
 }
+
+func TestRemoteRefCheckout(t *testing.T) {
+	remoteRef := "refs/changes/04/691202/5"
+	repo := &config.Repository{
+		URLString:       "https://opendev.org/airship/airshipctl",
+		CheckoutOptions: &config.RepoCheckout{RemoteRef: remoteRef},
+	}
+	require.NoError(t, repo.Validate())
+
+	co := repo.ToCheckoutOptions(false)
+	assert.Equal(t, plumbing.ReferenceName(remoteRef), co.Branch)
+
+	fo := repo.ToFetchOptions(nil)
+	assert.Equal(t, []gitconfig.RefSpec{"+refs/changes/04/691202/5:refs/changes/04/691202/5"}, fo.RefSpecs)
+	assert.NoError(t, fo.Validate())
+}
+
+func TestToFetchOptionsRefSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkout *config.RepoCheckout
+		expected []gitconfig.RefSpec
+	}{
+		{
+			name: "NoCheckoutOptions",
+		},
+		{
+			name:     "Branch",
+			checkout: &config.RepoCheckout{Branch: "master"},
+			expected: []gitconfig.RefSpec{"+refs/heads/master:refs/remotes/origin/master"},
+		},
+		{
+			name:     "BranchRemoteRef",
+			checkout: &config.RepoCheckout{RemoteRef: "refs/heads/feature"},
+			expected: []gitconfig.RefSpec{"+refs/heads/feature:refs/remotes/origin/feature"},
+		},
+		{
+			name:     "Tag",
+			checkout: &config.RepoCheckout{Tag: "v1.0"},
+			expected: []gitconfig.RefSpec{"+refs/tags/v1.0:refs/tags/v1.0"},
+		},
+		{
+			name:     "CommitHash",
+			checkout: &config.RepoCheckout{CommitHash: "01c4f7f32beb9851ae8f119a6b8e497d2b1e2bb8"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &config.Repository{CheckoutOptions: tt.checkout}
+			assert.Equal(t, tt.expected, repo.ToFetchOptions(nil).RefSpecs)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+)
+
+// RESTClient tunes the clients airshipctl uses to talk to clusters. Large
+// bundles may need a higher QPS and Burst than the client-go defaults of 5
+// and 10 to avoid client-side throttling. Zero values keep the defaults.
+type RESTClient struct {
+	// QPS is the maximum number of queries per second sent to a cluster
+	QPS float32 `json:"qps,omitempty"`
+
+	// Burst is the number of queries allowed above QPS for short periods
+	Burst int `json:"burst,omitempty"`
+
+	// RequestTimeout is how long to wait for a single request, e.g. "30s"
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+}
+
+// Timeout returns the parsed RequestTimeout, zero if it isn't set
+func (r *RESTClient) Timeout() (time.Duration, error) {
+	if r.RequestTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(r.RequestTimeout)
+	if err != nil || timeout < 0 {
+		return 0, ErrInvalidRequestTimeout{Value: r.RequestTimeout}
+	}
+	return timeout, nil
+}
@@ -0,0 +1,62 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+func TestRESTClientTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		err      error
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "valid",
+			value:    "90s",
+			expected: 90 * time.Second,
+		},
+		{
+			name:  "negative",
+			value: "-1s",
+			err:   config.ErrInvalidRequestTimeout{Value: "-1s"},
+		},
+		{
+			name:  "malformed",
+			value: "soon",
+			err:   config.ErrInvalidRequestTimeout{Value: "soon"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &config.RESTClient{RequestTimeout: tt.value}
+			timeout, err := rc.Timeout()
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, timeout)
+		})
+	}
+}
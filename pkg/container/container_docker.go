@@ -15,6 +15,7 @@
 package container
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 
 	"opendev.org/airship/airshipctl/pkg/log"
 )
@@ -174,6 +176,7 @@ func (c *DockerContainer) getConfig(
 		Cmd:         cmd,
 		AttachStdin: true,
 		OpenStdin:   true,
+		StdinOnce:   true,
 		Env:         envVars,
 	}
 	hCfg := container.HostConfig{
@@ -269,9 +272,14 @@ func (c *DockerContainer) RunCommand(
 		if attachErr != nil {
 			return attachErr
 		}
+		defer conn.Close()
 		if _, err = io.Copy(conn.Conn, containerInput); err != nil {
 			return err
 		}
+		// Closing the input lets commands reading it until EOF complete
+		if err = conn.CloseWrite(); err != nil {
+			return err
+		}
 	}
 
 	if err = c.dockerClient.ContainerStart(*c.ctx, c.id, types.ContainerStartOptions{}); err != nil {
@@ -324,7 +332,18 @@ func (c *DockerContainer) RunCommandOutput(
 		return nil, err
 	}
 
-	return c.dockerClient.ContainerLogs(*c.ctx, c.id, types.ContainerLogsOptions{ShowStdout: true})
+	logs, err := c.dockerClient.ContainerLogs(*c.ctx, c.id, types.ContainerLogsOptions{ShowStdout: true})
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+	// Without a TTY, docker multiplexes the output streams of the container
+	// in the logs
+	output := &bytes.Buffer{}
+	if _, err = stdcopy.StdCopy(output, ioutil.Discard, logs); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(output), nil
 }
 
 // RmContainer kills and removes a container from the docker host.
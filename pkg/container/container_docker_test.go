@@ -15,6 +15,7 @@
 package container
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -30,6 +31,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 type mockConn struct {
@@ -440,6 +442,28 @@ func TestRunCommandOutput(t *testing.T) {
 			expectedResult: "",
 			expectedErr:    testError,
 		},
+		{
+			cmd:            []string{"testCmd"},
+			containerInput: strings.NewReader("testInput"),
+			volumeMounts:   nil,
+			mockDockerClient: mockDockerClient{
+				containerAttach: func() (types.HijackedResponse, error) {
+					return types.HijackedResponse{
+						Conn: mockConn{WData: make([]byte, len([]byte("testInput")))},
+					}, nil
+				},
+				containerLogs: func() (io.ReadCloser, error) {
+					logs := &bytes.Buffer{}
+					_, err := stdcopy.NewStdWriter(logs, stdcopy.Stdout).Write([]byte("testOutput"))
+					require.NoError(t, err)
+					_, err = stdcopy.NewStdWriter(logs, stdcopy.Stderr).Write([]byte("testError"))
+					require.NoError(t, err)
+					return ioutil.NopCloser(logs), nil
+				},
+			},
+			expectedResult: "testOutput",
+			expectedErr:    nil,
+		},
 	}
 	for _, tt := range tests {
 		cnt := getDockerContainerMock(tt.mockDockerClient)
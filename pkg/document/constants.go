@@ -30,8 +30,22 @@ const (
 const (
 	SecretKind        = "Secret"
 	BareMetalHostKind = "BareMetalHost"
+	BIOSSettingsKind  = "BIOSSettings"
+	RAIDConfigKind    = "RAIDConfig"
 
 	ClusterctlMetadataKind    = "Metadata"
 	ClusterctlMetadataVersion = "v1alpha3"
 	ClusterctlMetadataGroup   = "clusterctl.cluster.x-k8s.io"
+
+	ClusterCheckKind    = "ClusterCheck"
+	ClusterCheckVersion = "v1alpha1"
+	ClusterCheckGroup   = "airshipit.org"
+
+	PhaseKind    = "Phase"
+	PhaseVersion = "v1alpha1"
+	PhaseGroup   = "airshipit.org"
+
+	PhasePlanKind    = "PhasePlan"
+	PhasePlanVersion = "v1alpha1"
+	PhasePlanGroup   = "airshipit.org"
 )
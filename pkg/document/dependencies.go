@@ -0,0 +1,168 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependsOnAnnotation lists the documents which have to be deployed before
+// the annotated one. It is a comma separated list of references of the form
+// Kind/name or Kind/namespace/name, a reference without a namespace points
+// to the namespace of the annotated document.
+const DependsOnAnnotation = BaseAirshipSelector + "/depends-on"
+
+const crdKind = "CustomResourceDefinition"
+
+// DependencyGraph orders documents so that every document is deployed after
+// the documents it depends on. Besides the DependsOnAnnotation, documents
+// implicitly depend on the Namespace they are in and on the
+// CustomResourceDefinition of their kind, if those are part of the same set
+// of documents.
+type DependencyGraph struct {
+	// Waves are groups of documents which only depend on documents of the
+	// previous waves. Documents keep their original order within a wave.
+	Waves [][]Document
+
+	dependencies map[Document][]Document
+}
+
+// NewDependencyGraph builds the dependency graph of docs. Dependencies on
+// documents which aren't part of docs and cycles are errors.
+func NewDependencyGraph(docs []Document) (*DependencyGraph, error) {
+	byRef := make(map[string]Document, len(docs))
+	namespaces := map[string]Document{}
+	crds := map[string]Document{}
+	for _, doc := range docs {
+		byRef[docRef(doc.GetKind(), doc.GetNamespace(), doc.GetName())] = doc
+		switch doc.GetKind() {
+		case "Namespace":
+			namespaces[doc.GetName()] = doc
+		case crdKind:
+			group, err := doc.GetString("spec.group")
+			if err != nil {
+				return nil, err
+			}
+			kind, err := doc.GetString("spec.names.kind")
+			if err != nil {
+				return nil, err
+			}
+			crds[group+"/"+kind] = doc
+		}
+	}
+
+	g := &DependencyGraph{dependencies: make(map[Document][]Document, len(docs))}
+	for _, doc := range docs {
+		var deps []Document
+		if ns, ok := namespaces[doc.GetNamespace()]; ok {
+			deps = append(deps, ns)
+		}
+		if crd, ok := crds[doc.GetGroup()+"/"+doc.GetKind()]; ok {
+			deps = append(deps, crd)
+		}
+
+		refs, ok := doc.GetAnnotations()[DependsOnAnnotation]
+		if ok {
+			for _, ref := range strings.Split(refs, ",") {
+				dep, err := resolveDependency(doc, strings.TrimSpace(ref), byRef)
+				if err != nil {
+					return nil, err
+				}
+				deps = append(deps, dep)
+			}
+		}
+		g.dependencies[doc] = deps
+	}
+
+	return g, g.sort(docs)
+}
+
+// Dependencies returns the documents doc directly depends on
+func (g *DependencyGraph) Dependencies(doc Document) []Document {
+	return g.dependencies[doc]
+}
+
+// sort splits docs into waves, each document goes to the wave following
+// the last wave of its dependencies
+func (g *DependencyGraph) sort(docs []Document) error {
+	wave := make(map[Document]int, len(docs))
+	remaining := docs
+	for len(remaining) > 0 {
+		var blocked []Document
+		for _, doc := range remaining {
+			level, ready := 0, true
+			for _, dep := range g.dependencies[doc] {
+				depLevel, placed := wave[dep]
+				if !placed {
+					ready = false
+					break
+				}
+				if depLevel+1 > level {
+					level = depLevel + 1
+				}
+			}
+			if !ready {
+				blocked = append(blocked, doc)
+				continue
+			}
+			wave[doc] = level
+			for len(g.Waves) <= level {
+				g.Waves = append(g.Waves, nil)
+			}
+			g.Waves[level] = append(g.Waves[level], doc)
+		}
+
+		if len(blocked) == len(remaining) {
+			names := make([]string, len(blocked))
+			for i, doc := range blocked {
+				names[i] = docRef(doc.GetKind(), doc.GetNamespace(), doc.GetName())
+			}
+			return ErrDependencyCycle{Documents: names}
+		}
+		remaining = blocked
+	}
+	return nil
+}
+
+func resolveDependency(doc Document, ref string, byRef map[string]Document) (Document, error) {
+	parts := strings.Split(ref, "/")
+	var key string
+	switch len(parts) {
+	case 2:
+		key = docRef(parts[0], doc.GetNamespace(), parts[1])
+		if _, ok := byRef[key]; !ok {
+			// Cluster scoped documents are referenced without a namespace
+			key = docRef(parts[0], "", parts[1])
+		}
+	case 3:
+		key = docRef(parts[0], parts[1], parts[2])
+	default:
+		return nil, ErrInvalidDependency{DocName: doc.GetName(), Reference: ref}
+	}
+
+	dep, ok := byRef[key]
+	if !ok {
+		return nil, ErrDependencyNotFound{DocName: doc.GetName(), Reference: ref}
+	}
+	return dep, nil
+}
+
+func docRef(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", kind, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
@@ -0,0 +1,89 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestNewDependencyGraph(t *testing.T) {
+	docs, err := testutil.NewTestBundle(t, "testdata/dependencies/valid").GetAllDocuments()
+	require.NoError(t, err)
+
+	graph, err := document.NewDependencyGraph(docs)
+	require.NoError(t, err)
+
+	var waves [][]string
+	for _, wave := range graph.Waves {
+		var names []string
+		for _, doc := range wave {
+			names = append(names, doc.GetKind()+"/"+doc.GetName())
+		}
+		waves = append(waves, names)
+	}
+	require.Len(t, waves, 3)
+	assert.ElementsMatch(t, []string{
+		"Namespace/infra",
+		"CustomResourceDefinition/widgets.example.com",
+		"ClusterRole/web",
+		"ConfigMap/standalone",
+	}, waves[0])
+	assert.Equal(t, []string{"Widget/w1"}, waves[1])
+	assert.Equal(t, []string{"Deployment/web"}, waves[2])
+
+	deployment := graph.Waves[2][0]
+	var deps []string
+	for _, dep := range graph.Dependencies(deployment) {
+		deps = append(deps, dep.GetKind()+"/"+dep.GetName())
+	}
+	assert.Equal(t, []string{"Namespace/infra", "Widget/w1", "ClusterRole/web"}, deps)
+}
+
+func TestNewDependencyGraphErrors(t *testing.T) {
+	tests := []struct {
+		dir         string
+		expectedErr error
+	}{
+		{
+			dir:         "testdata/dependencies/cycle",
+			expectedErr: document.ErrDependencyCycle{Documents: []string{"ConfigMap/first", "ConfigMap/second"}},
+		},
+		{
+			dir:         "testdata/dependencies/missing",
+			expectedErr: document.ErrDependencyNotFound{DocName: "first", Reference: "Secret/other/missing"},
+		},
+		{
+			dir:         "testdata/dependencies/invalid",
+			expectedErr: document.ErrInvalidDependency{DocName: "first", Reference: "first"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.dir, func(t *testing.T) {
+			docs, err := testutil.NewTestBundle(t, tt.dir).GetAllDocuments()
+			require.NoError(t, err)
+
+			_, err = document.NewDependencyGraph(docs)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
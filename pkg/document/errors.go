@@ -16,6 +16,7 @@ package document
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ErrDocNotFound returned if desired document not found by selector
@@ -41,6 +42,25 @@ type ErrDocumentMalformed struct {
 	Message string
 }
 
+// ErrInvalidDependency returned if a reference in the depends-on annotation
+// of a document is malformed
+type ErrInvalidDependency struct {
+	DocName   string
+	Reference string
+}
+
+// ErrDependencyNotFound returned if a document depends on a document that
+// isn't deployed with it
+type ErrDependencyNotFound struct {
+	DocName   string
+	Reference string
+}
+
+// ErrDependencyCycle returned if documents depend on each other
+type ErrDependencyCycle struct {
+	Documents []string
+}
+
 func (e ErrDocNotFound) Error() string {
 	return fmt.Sprintf("document filtered by selector %v found no documents", e.Selector)
 }
@@ -56,3 +76,16 @@ func (e ErrDocumentDataKeyNotFound) Error() string {
 func (e ErrDocumentMalformed) Error() string {
 	return fmt.Sprintf("document %q is malformed: %q", e.DocName, e.Message)
 }
+
+func (e ErrInvalidDependency) Error() string {
+	return fmt.Sprintf("document %q has invalid dependency %q, expected Kind/name or Kind/namespace/name",
+		e.DocName, e.Reference)
+}
+
+func (e ErrDependencyNotFound) Error() string {
+	return fmt.Sprintf("document %q depends on %q which is not found", e.DocName, e.Reference)
+}
+
+func (e ErrDependencyCycle) Error() string {
+	return fmt.Sprintf("dependency cycle between documents %s", strings.Join(e.Documents, ", "))
+}
@@ -27,9 +27,6 @@ type Settings struct {
 
 // Pull clones repositories
 func (s *Settings) Pull() error {
-	if err := s.Config.EnsureComplete(); err != nil {
-		return err
-	}
 	err := s.cloneRepositories()
 	if err != nil {
 		return err
@@ -40,7 +37,7 @@ func (s *Settings) Pull() error {
 
 func (s *Settings) cloneRepositories() error {
 	// Clone main repository
-	currentManifest, err := s.Config.CurrentContextManifest()
+	currentManifest, err := s.CurrentContextManifest()
 	if err != nil {
 		return err
 	}
@@ -55,7 +52,8 @@ func (s *Settings) cloneRepositories() error {
 		if err != nil {
 			return err
 		}
-		err = repository.Download(extraRepoConfig.ToCheckoutOptions(true).Force)
+		force := extraRepoConfig.CheckoutOptions != nil && extraRepoConfig.CheckoutOptions.ForceCheckout
+		err = repository.Download(force)
 		if err != nil {
 			return err
 		}
@@ -18,6 +18,7 @@ import (
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage"
 )
 
@@ -29,6 +30,9 @@ type Adapter interface {
 	Worktree() (*git.Worktree, error)
 	Head() (*plumbing.Reference, error)
 	ResolveRevision(plumbing.Revision) (*plumbing.Hash, error)
+	Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error)
+	SetReference(ref *plumbing.Reference) error
+	CommitObject(h plumbing.Hash) (*object.Commit, error)
 	IsOpen() bool
 	SetFilesystem(billy.Filesystem)
 	SetStorer(s storage.Storer)
@@ -89,3 +93,8 @@ func (g *GitDriver) SetFilesystem(fs billy.Filesystem) {
 func (g *GitDriver) SetStorer(s storage.Storer) {
 	g.Storer = s
 }
+
+// SetReference stores the given reference in the repository
+func (g *GitDriver) SetReference(ref *plumbing.Reference) error {
+	return g.Repository.Storer.SetReference(ref)
+}
@@ -0,0 +1,45 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrDirtyWorktree is returned when a repository can't be checked out because
+// its worktree has local modifications
+type ErrDirtyWorktree struct {
+	Repo  string
+	Files []string
+}
+
+func (e ErrDirtyWorktree) Error() string {
+	return fmt.Sprintf("repository %s has local modifications in %s, "+
+		"commit or discard them, or enable force checkout", e.Repo, strings.Join(e.Files, ", "))
+}
+
+// ErrBranchDiverged is returned when the local branch of a repository can't
+// be fast-forwarded to the fetched remote branch, because it has commits which
+// are not part of the remote branch
+type ErrBranchDiverged struct {
+	Repo   string
+	Branch string
+}
+
+func (e ErrBranchDiverged) Error() string {
+	return fmt.Sprintf("branch %s of repository %s has local commits which are not part of the remote branch, "+
+		"can't fast-forward it", e.Branch, e.Repo)
+}
@@ -18,10 +18,12 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/storage"
@@ -96,14 +98,24 @@ func (repo *Repository) Update(force bool) error {
 	if err != nil {
 		return err
 	}
-	err = repo.Driver.Fetch(repo.ToFetchOptions(auth))
+	if err = repo.fetch(auth); err != nil {
+		return err
+	}
+	return repo.Checkout(force)
+}
+
+func (repo *Repository) fetch(auth transport.AuthMethod) error {
+	err := repo.Driver.Fetch(repo.ToFetchOptions(auth))
 	if err != nil && err != git.NoErrAlreadyUpToDate {
 		return fmt.Errorf("failed to fetch refs for repository %v: %w", repo.Name, err)
 	}
-	return repo.Checkout(force)
+	return nil
 }
 
 // Checkout git repository, ToCheckoutOptions method will be used go get CheckoutOptions
+// If enforce is false and the worktree has local modifications or untracked files, ErrDirtyWorktree is
+// returned. If enforce is true, local modifications and untracked files are discarded,
+// similar to git reset --hard followed by git clean -fd.
 func (repo *Repository) Checkout(enforce bool) error {
 	log.Debugf("Attempting to checkout the repository %s", repo.Name)
 	if !repo.Driver.IsOpen() {
@@ -114,7 +126,87 @@ func (repo *Repository) Checkout(enforce bool) error {
 	if err != nil {
 		return fmt.Errorf("could not get worktree from the repo, %w", err)
 	}
-	return tree.Checkout(co)
+
+	if !enforce {
+		status, statusErr := tree.Status()
+		if statusErr != nil {
+			return fmt.Errorf("could not get status of the worktree, %w", statusErr)
+		}
+		if files := modifiedFiles(status); len(files) > 0 {
+			return ErrDirtyWorktree{Repo: repo.Name, Files: files}
+		}
+	}
+
+	if co.Branch.IsBranch() {
+		if err = repo.fastForward(co.Branch); err != nil {
+			return err
+		}
+	}
+
+	if err = tree.Checkout(co); err != nil {
+		return err
+	}
+
+	if enforce {
+		return tree.Clean(&git.CleanOptions{Dir: true})
+	}
+	return nil
+}
+
+// fastForward moves the local branch to the commit of its remote tracking
+// branch. The branch is created if it doesn't exist yet, ErrBranchDiverged is
+// returned if it has commits which are not part of the remote branch.
+func (repo *Repository) fastForward(branch plumbing.ReferenceName) error {
+	remoteName := plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch.Short())
+	remote, err := repo.Driver.Reference(remoteName, true)
+	if err == plumbing.ErrReferenceNotFound {
+		// Nothing was fetched for the branch, keep it as it is
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	local, err := repo.Driver.Reference(branch, true)
+	switch {
+	case err == plumbing.ErrReferenceNotFound:
+		log.Debugf("Creating branch %s of repository %s at %s", branch.Short(), repo.Name, remote.Hash())
+		return repo.Driver.SetReference(plumbing.NewHashReference(branch, remote.Hash()))
+	case err != nil:
+		return err
+	case local.Hash() == remote.Hash():
+		return nil
+	}
+
+	localCommit, err := repo.Driver.CommitObject(local.Hash())
+	if err != nil {
+		return err
+	}
+	remoteCommit, err := repo.Driver.CommitObject(remote.Hash())
+	if err != nil {
+		return err
+	}
+	isAncestor, err := localCommit.IsAncestor(remoteCommit)
+	if err != nil {
+		return err
+	}
+	if !isAncestor {
+		return ErrBranchDiverged{Repo: repo.Name, Branch: branch.Short()}
+	}
+	log.Debugf("Fast-forwarding branch %s of repository %s to %s", branch.Short(), repo.Name, remote.Hash())
+	return repo.Driver.SetReference(plumbing.NewHashReference(branch, remote.Hash()))
+}
+
+// modifiedFiles returns the sorted list of files which have been modified
+// in the worktree or the index, or which are not tracked
+func modifiedFiles(status git.Status) []string {
+	var files []string
+	for file, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
 }
 
 // Open the repository
@@ -135,10 +227,10 @@ func (repo *Repository) Clone() error {
 }
 
 // Download will clone and checkout repository based on auth and checkout fields of the Repository object
-// If repository is already cloned, it will be opened and checked out to configured hash,branch,tag etc...
-// no remotes will be modified in this case, also no refs will be updated.
-// enforce parameter is used to simulate git reset --hard option.
-// If you want to enforce state of the repository, please delete current git repository before downloading.
+// If repository is already cloned, it will be opened, the configured refs will be fetched and
+// the repository checked out to configured hash,branch,tag etc...
+// enforce parameter is used to simulate git reset --hard and git clean options, without it
+// ErrDirtyWorktree is returned if the existing repository has local modifications.
 func (repo *Repository) Download(enforceCheckout bool) error {
 	log.Debugf("Attempting to download the repository %s", repo.Name)
 
@@ -154,5 +246,13 @@ func (repo *Repository) Download(enforceCheckout bool) error {
 		}
 	}
 
+	auth, err := repo.ToAuth()
+	if err != nil {
+		return fmt.Errorf("failed to build auth options for repository %v: %w", repo.Name, err)
+	}
+	if err = repo.fetch(auth); err != nil {
+		return err
+	}
+
 	return repo.Checkout(enforceCheckout)
 }
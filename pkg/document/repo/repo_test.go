@@ -21,6 +21,7 @@ import (
 	fixtures "github.com/go-git/go-git-fixtures/v4"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
@@ -63,7 +64,8 @@ func TestDownload(t *testing.T) {
 		CloneOptions: &git.CloneOptions{
 			URL: fx.DotGit().Root(),
 		},
-		URLString: fx.DotGit().Root(),
+		FetchOptions: &git.FetchOptions{},
+		URLString:    fx.DotGit().Root(),
 	}
 
 	fs := memfs.New()
@@ -151,6 +153,64 @@ func TestUpdate(t *testing.T) {
 	assert.Error(t, updateError)
 }
 
+func TestUpdateFastForward(t *testing.T) {
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	url := fx.DotGit().Root()
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: plumbing.Master},
+		CloneOptions:    &git.CloneOptions{URL: url},
+		FetchOptions:    &git.FetchOptions{},
+		URLString:       url,
+	}
+
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	fs := memfs.New()
+	repo.Driver = &GitDriver{
+		Filesystem: fs,
+		Storer:     memory.NewStorage(),
+	}
+	require.NoError(t, repo.Clone())
+	ref, err := repo.Driver.Head()
+	require.NoError(t, err)
+	headHash := ref.Hash()
+
+	// Move the local branch one commit behind the remote branch
+	prevCommitHash, err := repo.Driver.ResolveRevision("HEAD~1")
+	require.NoError(t, err)
+	tree, err := repo.Driver.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, tree.Reset(&git.ResetOptions{Commit: *prevCommitHash, Mode: git.HardReset}))
+
+	require.NoError(t, repo.Update(false))
+	ref, err = repo.Driver.Head()
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.Master, ref.Name())
+	assert.Equal(t, headHash, ref.Hash())
+	status, err := tree.Status()
+	require.NoError(t, err)
+	assert.True(t, status.IsClean())
+
+	// Commit on the local branch, which can't be fast-forwarded anymore
+	file, err := fs.Create("local.txt")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	_, err = tree.Add("local.txt")
+	require.NoError(t, err)
+	localHash, err := tree.Commit("local commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "airshipctl", Email: "airshipctl@example.com"},
+	})
+	require.NoError(t, err)
+
+	err = repo.Update(true)
+	assert.Equal(t, ErrBranchDiverged{Repo: repo.Name, Branch: "master"}, err)
+	ref, err = repo.Driver.Head()
+	require.NoError(t, err)
+	assert.Equal(t, localHash, ref.Hash())
+}
+
 func TestOpen(t *testing.T) {
 	defer testutil.CleanUpGitFixtures(t)
 
@@ -209,3 +269,47 @@ func TestCheckout(t *testing.T) {
 	err = repo.Checkout(true)
 	assert.Error(t, err)
 }
+
+func TestCheckoutDirtyWorktree(t *testing.T) {
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	url := fx.DotGit().Root()
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: plumbing.Master},
+		CloneOptions:    &git.CloneOptions{URL: url},
+		FetchOptions:    &git.FetchOptions{},
+		URLString:       url,
+	}
+
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	fs := memfs.New()
+	repo.Driver = &GitDriver{
+		Filesystem: fs,
+		Storer:     memory.NewStorage(),
+	}
+	require.NoError(t, repo.Download(false))
+
+	// Modify a tracked file and add an untracked one
+	modified, err := fs.Create("CHANGELOG")
+	require.NoError(t, err)
+	_, err = modified.Write([]byte("local change"))
+	require.NoError(t, err)
+	require.NoError(t, modified.Close())
+	untracked, err := fs.Create("untracked.txt")
+	require.NoError(t, err)
+	require.NoError(t, untracked.Close())
+
+	err = repo.Download(false)
+	assert.Equal(t, ErrDirtyWorktree{Repo: repo.Name, Files: []string{"CHANGELOG", "untracked.txt"}}, err)
+
+	// Forcing the checkout discards local modifications and untracked files
+	builder.CheckoutOptions = &git.CheckoutOptions{Branch: plumbing.Master, Force: true}
+	require.NoError(t, repo.Download(true))
+	tree, err := repo.Driver.Worktree()
+	require.NoError(t, err)
+	status, err := tree.Status()
+	require.NoError(t, err)
+	assert.True(t, status.IsClean())
+}
@@ -167,6 +167,24 @@ func NewClusterctlMetadataSelector() Selector {
 		ClusterctlMetadataKind)
 }
 
+// NewClusterCheckSelector returns a selector to get the documents declaring
+// health checks of a cluster
+func NewClusterCheckSelector() Selector {
+	return NewSelector().ByGvk(ClusterCheckGroup, ClusterCheckVersion, ClusterCheckKind)
+}
+
+// NewPhaseSelector returns a selector to get the documents declaring the
+// phases of a site
+func NewPhaseSelector() Selector {
+	return NewSelector().ByGvk(PhaseGroup, PhaseVersion, PhaseKind)
+}
+
+// NewPhasePlanSelector returns a selector to get the documents declaring the
+// plans sequencing the phases of a site
+func NewPhasePlanSelector() Selector {
+	return NewSelector().ByGvk(PhasePlanGroup, PhasePlanVersion, PhasePlanKind)
+}
+
 // NewClusterctlSelector returns a selector to get document that controls how clusterctl
 // components will be applied
 func NewClusterctlSelector() Selector {
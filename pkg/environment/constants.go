@@ -16,3 +16,7 @@ package environment
 
 // HomeEnvVar holds value of HOME directory from env
 const HomeEnvVar = "$HOME"
+
+// XDGConfigHomeEnv is the environment variable defined by the XDG base
+// directory specification for user specific configuration files
+const XDGConfigHomeEnv = "XDG_CONFIG_HOME"
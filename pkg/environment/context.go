@@ -0,0 +1,47 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package environment
+
+import (
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+// CurrentContextManifest returns the manifest of the current context after
+// making sure the airship config is complete
+func (a *AirshipCTLSettings) CurrentContextManifest() (*config.Manifest, error) {
+	if err := a.Config.EnsureComplete(); err != nil {
+		return nil, err
+	}
+	return a.Config.CurrentContextManifest()
+}
+
+// CurrentContextTargetPath returns the target path of the current context's
+// manifest after making sure the airship config is complete
+func (a *AirshipCTLSettings) CurrentContextTargetPath() (string, error) {
+	if err := a.Config.EnsureComplete(); err != nil {
+		return "", err
+	}
+	return a.Config.CurrentContextTargetPath()
+}
+
+// CurrentContextEntryPoint returns the path to the documents of the given
+// phase for the current context after making sure the airship config is
+// complete
+func (a *AirshipCTLSettings) CurrentContextEntryPoint(phase string) (string, error) {
+	if err := a.Config.EnsureComplete(); err != nil {
+		return "", err
+	}
+	return a.Config.CurrentContextEntryPoint(phase)
+}
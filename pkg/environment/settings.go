@@ -18,8 +18,10 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"k8s.io/client-go/tools/clientcmd"
 
@@ -33,7 +35,26 @@ type AirshipCTLSettings struct {
 	Debug             bool
 	AirshipConfigPath string
 	KubeConfigPath    string
-	Config            *config.Config
+	// KubeContext is the kubeconfig context clients of clusters connect
+	// to instead of the current context
+	KubeContext string
+	// Profile is the name of the profile layered over the current context
+	Profile string
+	// DryRun is the default of the --dry-run flag of commands supporting it
+	DryRun bool
+	// CreateNamespaces is the default of the --create-namespaces flag of
+	// phase apply
+	CreateNamespaces bool
+	// QPS, Burst and RequestTimeout tune the clients of clusters, zero
+	// values keep the defaults of client-go
+	QPS            float32
+	Burst          int
+	RequestTimeout time.Duration
+	Config         *config.Config
+
+	// flags are the persistent flags added by InitFlags, used to tell
+	// explicitly given settings from defaults
+	flags *pflag.FlagSet
 }
 
 // A singleton for the kustomize plugin path configuration
@@ -63,7 +84,43 @@ func (a *AirshipCTLSettings) InitFlags(cmd *cobra.Command) {
 		&a.KubeConfigPath,
 		clientcmd.RecommendedConfigPathFlag,
 		"",
-		`Path to kubeconfig associated with airshipctl configuration. (default "`+defaultKubeConfigPath+`")`)
+		`Path to kubeconfig associated with airshipctl configuration, overrides $`+config.AirshipKubeConfigEnv+
+			`. (default "`+defaultKubeConfigPath+`")`)
+
+	flags.StringVar(
+		&a.KubeContext,
+		"context",
+		"",
+		"Name of the kubeconfig context cluster-facing commands connect to instead of the current context")
+
+	flags.StringVar(
+		&a.Profile,
+		"profile",
+		"",
+		"Name of the airshipctl configuration profile to use, overrides $"+config.AirshipProfileEnv)
+
+	flags.Float32Var(
+		&a.QPS,
+		"qps",
+		0,
+		"Maximum number of queries per second sent to the cluster, overrides restClient.qps of the"+
+			" airshipctl configuration. The client-go default of 5 is used if unset")
+
+	flags.IntVar(
+		&a.Burst,
+		"burst",
+		0,
+		"Number of queries allowed above --qps for short periods, overrides restClient.burst of the"+
+			" airshipctl configuration. The client-go default of 10 is used if unset")
+
+	flags.DurationVar(
+		&a.RequestTimeout,
+		"request-timeout",
+		0,
+		"How long to wait for a single request to the cluster, overrides restClient.requestTimeout"+
+			" of the airshipctl configuration. Requests don't time out if unset")
+
+	a.flags = flags
 }
 
 // InitConfig - Initializes and loads Config it exists.
@@ -71,6 +128,7 @@ func (a *AirshipCTLSettings) InitConfig() {
 	a.Config = config.NewConfig()
 
 	a.initAirshipConfigPath()
+	explicitKubeConfig := a.KubeConfigPath != "" || os.Getenv(config.AirshipKubeConfigEnv) != ""
 	a.initKubeConfigPath()
 	initPluginPath()
 
@@ -79,6 +137,85 @@ func (a *AirshipCTLSettings) InitConfig() {
 		// Should stop airshipctl
 		log.Fatal(err)
 	}
+
+	if err = a.applyProfile(explicitKubeConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = a.applyRESTClient(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// applyRESTClient takes the client settings which weren't given on the
+// command line from the airship config
+func (a *AirshipCTLSettings) applyRESTClient() error {
+	rc := a.Config.RESTClient
+	if rc == nil {
+		return nil
+	}
+
+	if rc.QPS != 0 && !a.flagChanged("qps") {
+		a.QPS = rc.QPS
+	}
+	if rc.Burst != 0 && !a.flagChanged("burst") {
+		a.Burst = rc.Burst
+	}
+	timeout, err := rc.Timeout()
+	if err != nil {
+		return err
+	}
+	if timeout != 0 && !a.flagChanged("request-timeout") {
+		a.RequestTimeout = timeout
+	}
+	return nil
+}
+
+// applyProfile layers the settings of the selected profile over the ones
+// which weren't given explicitly. The profile is selected by the --profile
+// flag or the AIRSHIP_PROFILE environment variable. Settings are resolved
+// in the following order, the first one set wins:
+// * command line flags
+// * environment variables
+// * the selected profile
+// * defaults
+func (a *AirshipCTLSettings) applyProfile(explicitKubeConfig bool) error {
+	if a.Profile == "" {
+		a.Profile = os.Getenv(config.AirshipProfileEnv)
+	}
+	if a.Profile == "" {
+		return nil
+	}
+
+	profile, err := a.Config.GetProfile(a.Profile)
+	if err != nil {
+		return err
+	}
+
+	// --debug=false turns off the verbose output enabled by a profile
+	if profile.Debug && !a.flagChanged("debug") {
+		a.Debug = true
+	}
+	if profile.DryRun {
+		a.DryRun = true
+	}
+	if profile.CreateNamespaces {
+		a.CreateNamespaces = true
+	}
+
+	if profile.KubeConfig == "" || explicitKubeConfig || profile.KubeConfig == a.KubeConfigPath {
+		return nil
+	}
+	// The kubeconfig is loaded along with the airship config
+	a.KubeConfigPath = profile.KubeConfig
+	a.Config = config.NewConfig()
+	return a.Config.LoadConfig(a.AirshipConfigPath, a.KubeConfigPath)
+}
+
+// flagChanged reports whether the persistent flag name was given on the
+// command line
+func (a *AirshipCTLSettings) flagChanged(name string) bool {
+	return a.flags != nil && a.flags.Changed(name)
 }
 
 func (a *AirshipCTLSettings) initAirshipConfigPath() {
@@ -93,9 +230,8 @@ func (a *AirshipCTLSettings) initAirshipConfigPath() {
 		return
 	}
 
-	// Otherwise, we'll try putting it in the home directory
-	homeDir := userHomeDir()
-	a.AirshipConfigPath = filepath.Join(homeDir, config.AirshipConfigDir, config.AirshipConfig)
+	// Otherwise, we'll try putting it in the airship config directory
+	a.AirshipConfigPath = filepath.Join(AirshipConfigDir(), config.AirshipConfig)
 }
 
 func (a *AirshipCTLSettings) initKubeConfigPath() {
@@ -117,9 +253,8 @@ func (a *AirshipCTLSettings) initKubeConfigPath() {
 		return
 	}
 
-	// Otherwise, we'll try putting it in the home directory
-	homeDir := userHomeDir()
-	a.KubeConfigPath = filepath.Join(homeDir, config.AirshipConfigDir, config.AirshipKubeConfig)
+	// Otherwise, we'll try putting it in the airship config directory
+	a.KubeConfigPath = filepath.Join(AirshipConfigDir(), config.AirshipKubeConfig)
 }
 
 // Sets the location to look for kustomize plugins (including airshipctl itself).
@@ -133,9 +268,8 @@ func initPluginPath() {
 		return
 	}
 
-	// Otherwise, we'll try putting it in the home directory
-	homeDir := userHomeDir()
-	pluginPath = filepath.Join(homeDir, config.AirshipConfigDir, config.AirshipPluginPath)
+	// Otherwise, we'll try putting it in the airship config directory
+	pluginPath = filepath.Join(AirshipConfigDir(), config.AirshipPluginPath)
 }
 
 // PluginPath returns the kustomize plugin path
@@ -145,6 +279,30 @@ func PluginPath() string {
 	return pluginPath
 }
 
+// AirshipConfigDir returns the directory where airshipctl keeps its
+// configuration files. The first of the following locations is used:
+// * the directory named by the AIRSHIP_CONFIG_DIR environment variable
+// * $HOME/.airship, if it already exists
+// * $XDG_CONFIG_HOME/airship, if XDG_CONFIG_HOME is set
+// * $HOME/.airship
+func AirshipConfigDir() string {
+	if configDir := os.Getenv(config.AirshipConfigDirEnv); configDir != "" {
+		return configDir
+	}
+
+	// Existing installations keep using the legacy location
+	homeConfigDir := filepath.Join(userHomeDir(), config.AirshipConfigDir)
+	if _, err := os.Stat(homeConfigDir); err == nil {
+		return homeConfigDir
+	}
+
+	if xdgConfigHome := os.Getenv(XDGConfigHomeEnv); xdgConfigHome != "" {
+		return filepath.Join(xdgConfigHome, config.AirshipXDGConfigDir)
+	}
+
+	return homeConfigDir
+}
+
 // userHomeDir is a utility function that wraps os.UserHomeDir and returns no
 // errors. If the user has no home directory, the returned value will be the
 // empty string
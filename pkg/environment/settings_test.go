@@ -17,12 +17,15 @@ limitations under the License.
 package environment_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
@@ -38,6 +41,10 @@ func TestInitFlags(t *testing.T) {
 }
 
 func TestInitConfig(t *testing.T) {
+	// Make sure the environment of the test runner doesn't affect defaults
+	defer unsetEnv(environment.XDGConfigHomeEnv)()
+	defer unsetEnv(config.AirshipConfigDirEnv)()
+
 	t.Run("DefaultToHomeDirectory", func(subTest *testing.T) {
 		// Set up a fake $HOME directory
 		testDir, cleanup := testutil.TempDir(t, "test-home")
@@ -55,6 +62,65 @@ func TestInitConfig(t *testing.T) {
 		assert.Equal(t, expectedPluginPath, environment.PluginPath())
 	})
 
+	t.Run("PreferXDGConfigHomeToDefault", func(subTest *testing.T) {
+		// Set up a fake $HOME directory
+		testDir, cleanup := testutil.TempDir(t, "test-home")
+		defer cleanup(t)
+		defer setHome(testDir)()
+
+		xdgConfigHome := filepath.Join(testDir, "xdg")
+		os.Setenv(environment.XDGConfigHomeEnv, xdgConfigHome)
+		defer os.Unsetenv(environment.XDGConfigHomeEnv)
+
+		var testSettings environment.AirshipCTLSettings
+		expectedAirshipConfig := filepath.Join(xdgConfigHome, config.AirshipXDGConfigDir, config.AirshipConfig)
+		expectedKubeConfig := filepath.Join(xdgConfigHome, config.AirshipXDGConfigDir, config.AirshipKubeConfig)
+
+		testSettings.InitConfig()
+		assert.Equal(t, expectedAirshipConfig, testSettings.AirshipConfigPath)
+		assert.Equal(t, expectedKubeConfig, testSettings.KubeConfigPath)
+	})
+
+	t.Run("PreferExistingHomeDirectoryToXDGConfigHome", func(subTest *testing.T) {
+		// Set up a fake $HOME directory
+		testDir, cleanup := testutil.TempDir(t, "test-home")
+		defer cleanup(t)
+		defer setHome(testDir)()
+
+		err := os.Mkdir(filepath.Join(testDir, config.AirshipConfigDir), 0755)
+		require.NoError(t, err)
+
+		os.Setenv(environment.XDGConfigHomeEnv, filepath.Join(testDir, "xdg"))
+		defer os.Unsetenv(environment.XDGConfigHomeEnv)
+
+		var testSettings environment.AirshipCTLSettings
+		expectedAirshipConfig := filepath.Join(testDir, config.AirshipConfigDir, config.AirshipConfig)
+
+		testSettings.InitConfig()
+		assert.Equal(t, expectedAirshipConfig, testSettings.AirshipConfigPath)
+	})
+
+	t.Run("PreferConfigDirEnvToDefault", func(subTest *testing.T) {
+		// Set up a fake $HOME directory
+		testDir, cleanup := testutil.TempDir(t, "test-home")
+		defer cleanup(t)
+		defer setHome(testDir)()
+
+		configDir := filepath.Join(testDir, "custom")
+		os.Setenv(config.AirshipConfigDirEnv, configDir)
+		defer os.Unsetenv(config.AirshipConfigDirEnv)
+
+		var testSettings environment.AirshipCTLSettings
+		expectedAirshipConfig := filepath.Join(configDir, config.AirshipConfig)
+		expectedKubeConfig := filepath.Join(configDir, config.AirshipKubeConfig)
+		expectedPluginPath := filepath.Join(configDir, config.AirshipPluginPath)
+
+		testSettings.InitConfig()
+		assert.Equal(t, expectedAirshipConfig, testSettings.AirshipConfigPath)
+		assert.Equal(t, expectedKubeConfig, testSettings.KubeConfigPath)
+		assert.Equal(t, expectedPluginPath, environment.PluginPath())
+	})
+
 	t.Run("PreferEnvToDefault", func(subTest *testing.T) {
 		// Set up a fake $HOME directory
 		testDir, cleanup := testutil.TempDir(t, "test-home")
@@ -138,3 +204,157 @@ func setHome(path string) (resetHome func()) {
 		os.Setenv("HOME", oldHome)
 	}
 }
+
+// unsetEnv unsets the environment variable `key`, and returns a function
+// that can be used to restore its original value
+func unsetEnv(key string) (resetEnv func()) {
+	oldValue, exists := os.LookupEnv(key)
+	os.Unsetenv(key)
+	return func() {
+		if exists {
+			os.Setenv(key, oldValue)
+		}
+	}
+}
+
+func TestCurrentContextHelpers(t *testing.T) {
+	settings := &environment.AirshipCTLSettings{Config: testutil.DummyConfig()}
+
+	manifest, err := settings.CurrentContextManifest()
+	require.NoError(t, err)
+	assert.Equal(t, testutil.DummyManifest(), manifest)
+
+	targetPath, err := settings.CurrentContextTargetPath()
+	require.NoError(t, err)
+	assert.Equal(t, manifest.TargetPath, targetPath)
+
+	entryPoint, err := settings.CurrentContextEntryPoint("initinfra")
+	require.NoError(t, err)
+	clusterType, err := settings.Config.CurrentContextClusterType()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(manifest.TargetPath, manifest.SubPath, clusterType, "initinfra"), entryPoint)
+
+	t.Run("IncompleteConfig", func(subTest *testing.T) {
+		settings := &environment.AirshipCTLSettings{Config: config.NewConfig()}
+		expectedErr := settings.Config.EnsureComplete()
+		require.Error(subTest, expectedErr)
+
+		_, err := settings.CurrentContextManifest()
+		assert.Equal(subTest, expectedErr, err)
+		_, err = settings.CurrentContextTargetPath()
+		assert.Equal(subTest, expectedErr, err)
+		_, err = settings.CurrentContextEntryPoint("initinfra")
+		assert.Equal(subTest, expectedErr, err)
+	})
+}
+
+func TestInitConfigProfile(t *testing.T) {
+	defer unsetEnv(config.AirshipProfileEnv)()
+	defer unsetEnv(config.AirshipKubeConfigEnv)()
+
+	testDir, cleanup := testutil.TempDir(t, "test-profile")
+	defer cleanup(t)
+
+	airshipConfigPath := filepath.Join(testDir, "config")
+	defaultKubeConfig := filepath.Join(testDir, "kubeconfig")
+	profileKubeConfig := filepath.Join(testDir, "prod-kubeconfig")
+	airshipConfig := `apiVersion: airshipit.org/v1alpha1
+kind: Config
+profiles:
+  prod:
+    debug: true
+    dryRun: true
+    createNamespaces: true
+    kubeconfig: ` + profileKubeConfig + `
+`
+	require.NoError(t, ioutil.WriteFile(airshipConfigPath, []byte(airshipConfig), 0600))
+
+	newSettings := func(args ...string) *environment.AirshipCTLSettings {
+		settings := &environment.AirshipCTLSettings{}
+		testCmd := &cobra.Command{}
+		settings.InitFlags(testCmd)
+		require.NoError(t, testCmd.ParseFlags(append([]string{"--airshipconf", airshipConfigPath}, args...)))
+		return settings
+	}
+
+	t.Run("NoProfile", func(subTest *testing.T) {
+		os.Setenv(config.AirshipKubeConfigEnv, defaultKubeConfig)
+		defer os.Unsetenv(config.AirshipKubeConfigEnv)
+
+		settings := newSettings()
+		settings.InitConfig()
+		assert.False(subTest, settings.Debug)
+		assert.False(subTest, settings.DryRun)
+		assert.Equal(subTest, defaultKubeConfig, settings.KubeConfigPath)
+	})
+
+	t.Run("ProfileFlag", func(subTest *testing.T) {
+		settings := newSettings("--profile", "prod")
+		settings.InitConfig()
+		assert.True(subTest, settings.Debug)
+		assert.True(subTest, settings.DryRun)
+		assert.True(subTest, settings.CreateNamespaces)
+		assert.Equal(subTest, profileKubeConfig, settings.KubeConfigPath)
+		assert.Equal(subTest, profileKubeConfig, settings.Config.KubeConfigPath())
+	})
+
+	t.Run("ProfileEnv", func(subTest *testing.T) {
+		os.Setenv(config.AirshipProfileEnv, "prod")
+		defer os.Unsetenv(config.AirshipProfileEnv)
+
+		settings := newSettings()
+		settings.InitConfig()
+		assert.Equal(subTest, "prod", settings.Profile)
+		assert.True(subTest, settings.DryRun)
+	})
+
+	t.Run("ExplicitSettingsWin", func(subTest *testing.T) {
+		os.Setenv(config.AirshipKubeConfigEnv, defaultKubeConfig)
+		defer os.Unsetenv(config.AirshipKubeConfigEnv)
+
+		settings := newSettings("--profile", "prod", "--debug=false")
+		settings.InitConfig()
+		assert.False(subTest, settings.Debug)
+		assert.True(subTest, settings.DryRun)
+		assert.Equal(subTest, defaultKubeConfig, settings.KubeConfigPath)
+	})
+}
+
+func TestInitConfigRESTClient(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "test-rest-client")
+	defer cleanup(t)
+
+	airshipConfigPath := filepath.Join(testDir, "config")
+	airshipConfig := `apiVersion: airshipit.org/v1alpha1
+kind: Config
+restClient:
+  qps: 50
+  burst: 100
+  requestTimeout: 2m
+`
+	require.NoError(t, ioutil.WriteFile(airshipConfigPath, []byte(airshipConfig), 0600))
+
+	newSettings := func(args ...string) *environment.AirshipCTLSettings {
+		settings := &environment.AirshipCTLSettings{}
+		testCmd := &cobra.Command{}
+		settings.InitFlags(testCmd)
+		require.NoError(t, testCmd.ParseFlags(append([]string{"--airshipconf", airshipConfigPath}, args...)))
+		return settings
+	}
+
+	t.Run("FromConfig", func(subTest *testing.T) {
+		settings := newSettings()
+		settings.InitConfig()
+		assert.Equal(subTest, float32(50), settings.QPS)
+		assert.Equal(subTest, 100, settings.Burst)
+		assert.Equal(subTest, 2*time.Minute, settings.RequestTimeout)
+	})
+
+	t.Run("FlagsWin", func(subTest *testing.T) {
+		settings := newSettings("--qps", "20", "--request-timeout", "10s")
+		settings.InitConfig()
+		assert.Equal(subTest, float32(20), settings.QPS)
+		assert.Equal(subTest, 100, settings.Burst)
+		assert.Equal(subTest, 10*time.Second, settings.RequestTimeout)
+	})
+}
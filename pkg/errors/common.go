@@ -14,6 +14,32 @@
 
 package errors
 
+import (
+	"errors"
+)
+
+// ExitCodeFailure is the exit code of airshipctl for errors that don't
+// specify one
+const ExitCodeFailure = 1
+
+// ExitCoder is implemented by errors that make airshipctl terminate with
+// a specific exit code
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// ExitCode returns the exit code airshipctl should terminate with after
+// err occurred: the one of the first ExitCoder in the chain of err, or
+// ExitCodeFailure
+func ExitCode(err error) int {
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitCodeFailure
+}
+
 // AirshipError is the base error type
 // used to create extended error types
 // in other airshipctl packages.
@@ -0,0 +1,36 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	aerror "opendev.org/airship/airshipctl/pkg/errors"
+)
+
+type errExitCode struct{}
+
+func (e errExitCode) Error() string { return "exit code error" }
+
+func (e errExitCode) ExitCode() int { return 42 }
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, aerror.ExitCodeFailure, aerror.ExitCode(aerror.ErrNotImplemented{}))
+	assert.Equal(t, 42, aerror.ExitCode(errExitCode{}))
+	assert.Equal(t, 42, aerror.ExitCode(fmt.Errorf("wrapped: %w", errExitCode{})))
+}
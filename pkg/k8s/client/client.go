@@ -18,9 +18,17 @@ import (
 	"path/filepath"
 
 	apix "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	// Register the oidc auth provider for credentials of clusters fronted
+	// by an OpenID Connect identity provider. Exec credential plugins are
+	// supported by client-go without registration.
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	"k8s.io/client-go/rest"
 
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
@@ -34,12 +42,21 @@ import (
 // * An ApiextensionsClientSet which provides interactions with CustomResourceDefinitions
 // * A Kubectl interface that is built on top of kubectl libraries and
 //   implements such kubectl subcommands as kubectl apply (more will be added)
+// * A RESTConfig for clients of subresources, such as port forwarding
+// * The Namespace of the kubeconfig context, which resources without
+//   namespace are applied to
+// * A ResourceAccessor to get, list and delete resources of any kind by
+//   GroupVersionKind and name
 type Interface interface {
 	ClientSet() kubernetes.Interface
 	DynamicClient() dynamic.Interface
 	ApiextensionsClientSet() apix.Interface
 
 	Kubectl() kubectl.Interface
+	RESTConfig() *rest.Config
+	Namespace() string
+
+	ResourceAccessor
 }
 
 // Client is an implementation of Interface
@@ -48,7 +65,10 @@ type Client struct {
 	dynamicClient dynamic.Interface
 	apixClient    apix.Interface
 
-	kubectl kubectl.Interface
+	kubectl    kubectl.Interface
+	restConfig *rest.Config
+	restMapper meta.RESTMapper
+	namespace  string
 }
 
 // Client implements Interface
@@ -65,7 +85,21 @@ func NewClient(settings *environment.AirshipCTLSettings) (Interface, error) {
 	client := new(Client)
 	var err error
 
-	f := k8sutils.FactoryFromKubeConfigPath(settings.KubeConfigPath)
+	restOptions := k8sutils.RESTOptions{
+		QPS:     settings.QPS,
+		Burst:   settings.Burst,
+		Timeout: settings.RequestTimeout,
+		Context: settings.KubeContext,
+	}
+	f := k8sutils.FactoryWithRESTOptions(settings.KubeConfigPath, restOptions)
+
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err = checkExecProvider(config, settings.KubeConfigPath); err != nil {
+		return nil, err
+	}
 
 	pathToBufferDir := filepath.Dir(settings.AirshipConfigPath)
 	client.kubectl = kubectl.NewKubectl(f).WithBufferDir(pathToBufferDir)
@@ -80,16 +114,22 @@ func NewClient(settings *environment.AirshipCTLSettings) (Interface, error) {
 		return nil, err
 	}
 
-	// kubectl factories can't create CRD clients...
-	config, err := clientcmd.BuildConfigFromFlags("", settings.KubeConfigPath)
+	client.restMapper, err = f.ToRESTMapper()
 	if err != nil {
 		return nil, err
 	}
 
+	// kubectl factories can't create CRD clients...
 	client.apixClient, err = apix.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
+	client.restConfig = config
+
+	client.namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
 
 	return client, nil
 }
@@ -133,3 +173,45 @@ func (c *Client) Kubectl() kubectl.Interface {
 func (c *Client) SetKubectl(kctl kubectl.Interface) {
 	c.kubectl = kctl
 }
+
+// RESTConfig returns the configuration used to connect to the cluster
+func (c *Client) RESTConfig() *rest.Config {
+	return c.restConfig
+}
+
+// Namespace returns the namespace of the kubeconfig context the client
+// connects to, the default namespace if the context sets none
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// SetNamespace sets the namespace of the kubeconfig context
+func (c *Client) SetNamespace(namespace string) {
+	c.namespace = namespace
+}
+
+// RESTMapper returns the mapper translating kinds to resources of the cluster
+func (c *Client) RESTMapper() meta.RESTMapper {
+	return c.restMapper
+}
+
+// SetRESTMapper sets the mapper translating kinds to resources
+func (c *Client) SetRESTMapper(mapper meta.RESTMapper) {
+	c.restMapper = mapper
+}
+
+// Get returns the resource of kind gvk called name
+func (c *Client) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	return NewResources(c.dynamicClient, c.restMapper).Get(gvk, namespace, name)
+}
+
+// List returns the resources of kind gvk matching opts
+func (c *Client) List(gvk schema.GroupVersionKind, namespace string,
+	opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return NewResources(c.dynamicClient, c.restMapper).List(gvk, namespace, opts)
+}
+
+// Delete deletes the resource of kind gvk called name
+func (c *Client) Delete(gvk schema.GroupVersionKind, namespace, name string, opts *metav1.DeleteOptions) error {
+	return NewResources(c.dynamicClient, c.restMapper).Delete(gvk, namespace, name, opts)
+}
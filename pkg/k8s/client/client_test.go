@@ -15,6 +15,10 @@
 package client_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
@@ -27,8 +31,9 @@ import (
 )
 
 const (
-	kubeconfigPath   = "testdata/kubeconfig.yaml"
-	airshipConfigDir = "testdata"
+	kubeconfigPath     = "testdata/kubeconfig.yaml"
+	oidcKubeconfigPath = "testdata/kubeconfig-oidc.yaml"
+	airshipConfigDir   = "testdata"
 )
 
 func TestNewClient(t *testing.T) {
@@ -54,4 +59,130 @@ func TestNewClient(t *testing.T) {
 	assert.NotNil(t, client.DynamicClient())
 	assert.NotNil(t, client.ApiextensionsClientSet())
 	assert.NotNil(t, client.Kubectl())
+	assert.NotNil(t, client.RESTMapper())
+}
+
+func TestNewClientWithContext(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	akp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs(airshipConfigDir)
+	require.NoError(t, err)
+
+	settings := &environment.AirshipCTLSettings{
+		Config:            conf,
+		AirshipConfigPath: adir,
+		KubeConfigPath:    akp,
+		KubeContext:       "dummy_cluster",
+	}
+
+	c, err := client.NewClient(settings)
+	require.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:6443", c.RESTConfig().Host)
+
+	settings.KubeContext = "missing_cluster"
+	_, err = client.NewClient(settings)
+	assert.Error(t, err)
+}
+
+func TestNewClientWithOIDC(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	akp, err := filepath.Abs(oidcKubeconfigPath)
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs(airshipConfigDir)
+	require.NoError(t, err)
+
+	settings := &environment.AirshipCTLSettings{
+		Config:            conf,
+		AirshipConfigPath: adir,
+		KubeConfigPath:    akp,
+	}
+
+	// The kubeconfig refers to a user authenticated by the oidc auth provider
+	client, err := client.NewClient(settings)
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+	assert.NotNil(t, client.ClientSet())
+}
+
+// execPlugin prints an ExecCredential with the given token
+const execPlugin = `#!/bin/sh
+echo '{"apiVersion": "client.authentication.k8s.io/v1beta1", "kind": "ExecCredential",' \
+	'"status": {"token": "exec-token"}}'
+`
+
+const execKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+  name: cloud
+contexts:
+- context:
+    cluster: cloud
+    user: exec-user
+  name: cloud
+current-context: cloud
+users:
+- name: exec-user
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: %s
+`
+
+func TestNewClientWithExecPlugin(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	testDir, cleanupDir := testutil.TempDir(t, "exec-plugin")
+	defer cleanupDir(t)
+
+	var authorization string
+	// Credentials are only sent to clusters served over TLS
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"major": "1", "minor": "17", "gitVersion": "v1.17.4"}`)
+	}))
+	defer server.Close()
+
+	pluginPath := filepath.Join(testDir, "credential-plugin")
+	require.NoError(t, ioutil.WriteFile(pluginPath, []byte(execPlugin), 0700))
+	kp := filepath.Join(testDir, "kubeconfig")
+	require.NoError(t, ioutil.WriteFile(kp, []byte(fmt.Sprintf(execKubeconfig, server.URL, pluginPath)), 0600))
+
+	settings := &environment.AirshipCTLSettings{
+		Config:            conf,
+		AirshipConfigPath: testDir,
+		KubeConfigPath:    kp,
+	}
+
+	c, err := client.NewClient(settings)
+	require.NoError(t, err)
+	_, err = c.ClientSet().Discovery().ServerVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer exec-token", authorization)
+
+	// A missing plugin is reported right away
+	missing := filepath.Join(testDir, "missing-plugin")
+	require.NoError(t, ioutil.WriteFile(kp, []byte(fmt.Sprintf(execKubeconfig, server.URL, missing)), 0600))
+	_, err = client.NewClient(settings)
+	assert.Equal(t, client.ErrExecPluginNotFound{Command: missing}, err)
+
+	// Relative commands are looked up next to the kubeconfig, not in the working directory
+	require.NoError(t, ioutil.WriteFile(kp, []byte(fmt.Sprintf(execKubeconfig, server.URL, "./credential-plugin")),
+		0600))
+	_, err = client.NewClient(settings)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(kp, []byte(fmt.Sprintf(execKubeconfig, server.URL, "./missing-plugin")), 0600))
+	_, err = client.NewClient(settings)
+	assert.Equal(t, client.ErrExecPluginNotFound{Command: missing}, err)
 }
@@ -0,0 +1,85 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrUnsupportedPortForwardTarget is returned when ports can't be forwarded
+// to the kind of resource requested
+type ErrUnsupportedPortForwardTarget struct {
+	Target string
+}
+
+func (e ErrUnsupportedPortForwardTarget) Error() string {
+	return fmt.Sprintf("can't forward ports to %q, only pods, services and deployments are supported", e.Target)
+}
+
+// ErrInvalidPortForwardPort is returned for a port which isn't a number or a
+// LOCAL:REMOTE pair of numbers
+type ErrInvalidPortForwardPort struct {
+	Port string
+}
+
+func (e ErrInvalidPortForwardPort) Error() string {
+	return fmt.Sprintf("invalid port %q, must be PORT or LOCAL_PORT:REMOTE_PORT", e.Port)
+}
+
+// ErrPodNotRunning is returned when ports are forwarded to a pod which
+// isn't running
+type ErrPodNotRunning struct {
+	Namespace string
+	Name      string
+	Phase     corev1.PodPhase
+}
+
+func (e ErrPodNotRunning) Error() string {
+	return fmt.Sprintf("pod %s in namespace %s is not running, its phase is %s", e.Name, e.Namespace, e.Phase)
+}
+
+// ErrNoRunningPod is returned when a service or deployment has no running
+// pod to forward ports to
+type ErrNoRunningPod struct {
+	Namespace string
+	Target    string
+}
+
+func (e ErrNoRunningPod) Error() string {
+	return fmt.Sprintf("no running pod found for %s in namespace %s", e.Target, e.Namespace)
+}
+
+// ErrServicePortNotFound is returned when a service doesn't expose the
+// requested port
+type ErrServicePortNotFound struct {
+	Service string
+	Port    int32
+}
+
+func (e ErrServicePortNotFound) Error() string {
+	return fmt.Sprintf("service %s does not expose port %d", e.Service, e.Port)
+}
+
+// ErrExecPluginNotFound is returned when the exec credential plugin of the
+// kubeconfig user can't be found
+type ErrExecPluginNotFound struct {
+	Command string
+}
+
+func (e ErrExecPluginNotFound) Error() string {
+	return fmt.Sprintf("exec credential plugin %q not found, it must be installed and in PATH", e.Command)
+}
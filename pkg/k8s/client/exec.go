@@ -0,0 +1,61 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/rest"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// checkExecProvider makes sure the exec credential plugin of config, e.g.
+// aws-iam-authenticator or kubelogin, can be run. Otherwise client-go only
+// reports the problem with the first request to the cluster.
+//
+// client-go hands the terminal to the plugin only if stdout is a terminal,
+// plugins that would prompt for credentials fail instead of blocking when
+// airshipctl runs unattended, e.g. in CI pipelines.
+//
+// Like client-go, a relative command containing a path separator is resolved
+// against the directory of the kubeconfig file, while a bare command name is
+// looked up in PATH.
+func checkExecProvider(config *rest.Config, kubeconfigPath string) error {
+	provider := config.ExecProvider
+	if provider == nil {
+		return nil
+	}
+	command := provider.Command
+	if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+		dir, err := filepath.Abs(filepath.Dir(kubeconfigPath))
+		if err != nil {
+			return err
+		}
+		command = filepath.Join(dir, command)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return ErrExecPluginNotFound{Command: command}
+	}
+	if !terminal.IsTerminal(int(os.Stdout.Fd())) || !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		log.Debugf("Not attached to a terminal, exec credential plugin %s can't prompt for credentials",
+			provider.Command)
+	}
+	return nil
+}
@@ -17,11 +17,18 @@ package fake
 import (
 	apix "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apixFake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	kubernetesFake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/scheme"
 
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
@@ -34,6 +41,9 @@ type Client struct {
 	mockDynamicClient          func() dynamic.Interface
 	mockApiextensionsClientSet func() apix.Interface
 	mockKubectl                func() kubectl.Interface
+	mockRESTConfig             func() *rest.Config
+	mockRESTMapper             func() meta.RESTMapper
+	namespace                  string
 }
 
 var _ client.Interface = &Client{}
@@ -65,6 +75,43 @@ func (c *Client) Kubectl() kubectl.Interface {
 	return c.mockKubectl()
 }
 
+// RESTConfig is used to get the configuration of a fake cluster. To set the
+// configuration to be returned, use the WithRESTConfig ResourceAccumulator
+func (c *Client) RESTConfig() *rest.Config {
+	return c.mockRESTConfig()
+}
+
+// Namespace is used to get the namespace of the kubeconfig context of a fake
+// cluster, the default namespace unless set with the WithNamespace
+// ResourceAccumulator
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// RESTMapper is used to get the mapper of kinds to resources of a fake
+// cluster. It knows the built-in kinds of kubernetes, to add other kinds
+// use the WithRESTMapper ResourceAccumulator
+func (c *Client) RESTMapper() meta.RESTMapper {
+	return c.mockRESTMapper()
+}
+
+// Get returns a resource of the mocked dynamic client. Every call uses a new
+// dynamic client, see WithDynamicObjects
+func (c *Client) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	return client.NewResources(c.DynamicClient(), c.RESTMapper()).Get(gvk, namespace, name)
+}
+
+// List returns resources of the mocked dynamic client
+func (c *Client) List(gvk schema.GroupVersionKind, namespace string,
+	opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return client.NewResources(c.DynamicClient(), c.RESTMapper()).List(gvk, namespace, opts)
+}
+
+// Delete deletes a resource of the mocked dynamic client
+func (c *Client) Delete(gvk schema.GroupVersionKind, namespace, name string, opts *metav1.DeleteOptions) error {
+	return client.NewResources(c.DynamicClient(), c.RESTMapper()).Delete(gvk, namespace, name, opts)
+}
+
 // A ResourceAccumulator is an option meant to be passed to NewClient.
 // ResourceAccumulators can be mixed and matched to create a collection of
 // mocked clients, each having their own fake objects.
@@ -102,6 +149,19 @@ func NewClient(resourceAccumulators ...ResourceAccumulator) *Client {
 			return kubectl.NewKubectl(k8sutils.NewMockKubectlFactory())
 		}
 	}
+	if fakeClient.mockRESTConfig == nil {
+		fakeClient.mockRESTConfig = func() *rest.Config {
+			return &rest.Config{}
+		}
+	}
+	if fakeClient.namespace == "" {
+		fakeClient.namespace = metav1.NamespaceDefault
+	}
+	if fakeClient.mockRESTMapper == nil {
+		fakeClient.mockRESTMapper = func() meta.RESTMapper {
+			return testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme)
+		}
+	}
 	return fakeClient
 }
 
@@ -137,10 +197,38 @@ func WithDynamicObjects(objs ...runtime.Object) ResourceAccumulator {
 }
 
 // WithKubectl returns a ResourceAccumulator with an instance of a kubectl.Interface.
-func WithKubectl(kubectlInstance *kubectl.Kubectl) ResourceAccumulator {
+func WithKubectl(kubectlInstance kubectl.Interface) ResourceAccumulator {
 	return func(c *Client) {
 		c.mockKubectl = func() kubectl.Interface {
 			return kubectlInstance
 		}
 	}
 }
+
+// WithRESTConfig returns a ResourceAccumulator with a configuration to
+// connect to a cluster.
+func WithRESTConfig(config *rest.Config) ResourceAccumulator {
+	return func(c *Client) {
+		c.mockRESTConfig = func() *rest.Config {
+			return config
+		}
+	}
+}
+
+// WithNamespace returns a ResourceAccumulator with the namespace of the
+// kubeconfig context, which resources without namespace are applied to.
+func WithNamespace(namespace string) ResourceAccumulator {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithRESTMapper returns a ResourceAccumulator with a mapper of kinds to
+// resources, e.g. to access custom resources with Get, List and Delete.
+func WithRESTMapper(mapper meta.RESTMapper) ResourceAccumulator {
+	return func(c *Client) {
+		c.mockRESTMapper = func() meta.RESTMapper {
+			return mapper
+		}
+	}
+}
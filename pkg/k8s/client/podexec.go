@@ -0,0 +1,69 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecOptions describe a command run in a container of a pod
+type ExecOptions struct {
+	Namespace string
+	Pod       string
+	Container string
+	Command   []string
+
+	// Stdin, if set, is streamed to the standard input of the command
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExecFunc runs a command in a container of a pod and blocks until it exits
+type ExecFunc func(opts ExecOptions) error
+
+// PodExecutor returns an ExecFunc running commands in the pods of the
+// cluster of config
+func PodExecutor(clientSet kubernetes.Interface, config *rest.Config) ExecFunc {
+	return func(opts ExecOptions) error {
+		req := clientSet.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(opts.Namespace).
+			Name(opts.Pod).
+			SubResource("exec").
+			VersionedParams(&corev1.PodExecOptions{
+				Container: opts.Container,
+				Command:   opts.Command,
+				Stdin:     opts.Stdin != nil,
+				Stdout:    opts.Stdout != nil,
+				Stderr:    opts.Stderr != nil,
+			}, scheme.ParameterCodec)
+		executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+		if err != nil {
+			return err
+		}
+		return executor.Stream(remotecommand.StreamOptions{
+			Stdin:  opts.Stdin,
+			Stdout: opts.Stdout,
+			Stderr: opts.Stderr,
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwarder forwards local ports to a pod of the cluster, e.g. to reach
+// the ISO server or the Ironic API while debugging a bootstrap
+type PortForwarder struct {
+	ClientSet kubernetes.Interface
+	Config    *rest.Config
+
+	Namespace string
+	// Target is the name of a pod, or TYPE/NAME where TYPE is pod, service
+	// (svc) or deployment (deploy). Services and deployments are forwarded
+	// to one of their running pods.
+	Target string
+	// Ports are LOCAL:REMOTE pairs, or a single port used on both sides.
+	// Remote ports of services are service ports, they are translated to
+	// the ports of the pod.
+	Ports []string
+	// Addresses to listen on, localhost by default
+	Addresses []string
+
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// NewPortForwarder returns a PortForwarder listening on localhost and
+// reporting to stdout and stderr
+func NewPortForwarder(c Interface, namespace, target string, ports []string) *PortForwarder {
+	return &PortForwarder{
+		ClientSet: c.ClientSet(),
+		Config:    c.RESTConfig(),
+		Namespace: namespace,
+		Target:    target,
+		Ports:     ports,
+		Addresses: []string{"localhost"},
+		Out:       os.Stdout,
+		ErrOut:    os.Stderr,
+	}
+}
+
+// ForwardPorts forwards the ports until stopCh is closed. readyCh, if not
+// nil, is closed once the local ports are listening.
+func (pf *PortForwarder) ForwardPorts(stopCh <-chan struct{}, readyCh chan struct{}) error {
+	pod, ports, err := pf.Pod()
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(pf.Config)
+	if err != nil {
+		return err
+	}
+	url := pf.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pf.Namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	fw, err := portforward.NewOnAddresses(dialer, pf.Addresses, ports, stopCh, readyCh, pf.Out, pf.ErrOut)
+	if err != nil {
+		return err
+	}
+	return fw.ForwardPorts()
+}
+
+// Pod returns the name of the pod the ports are forwarded to, and the ports
+// with the remote ones translated to ports of the pod
+func (pf *PortForwarder) Pod() (string, []string, error) {
+	kind, name := "pod", pf.Target
+	if i := strings.Index(pf.Target, "/"); i >= 0 {
+		kind, name = pf.Target[:i], pf.Target[i+1:]
+	}
+
+	var (
+		pod *corev1.Pod
+		svc *corev1.Service
+		err error
+	)
+	switch kind {
+	case "pod", "pods", "po":
+		pod, err = pf.ClientSet.CoreV1().Pods(pf.Namespace).Get(name, metav1.GetOptions{})
+		if err == nil && pod.Status.Phase != corev1.PodRunning {
+			err = ErrPodNotRunning{Namespace: pf.Namespace, Name: name, Phase: pod.Status.Phase}
+		}
+	case "service", "services", "svc":
+		svc, err = pf.ClientSet.CoreV1().Services(pf.Namespace).Get(name, metav1.GetOptions{})
+		if err == nil {
+			pod, err = pf.runningPod(labels.SelectorFromSet(svc.Spec.Selector))
+		}
+	case "deployment", "deployments", "deploy":
+		deployment, getErr := pf.ClientSet.AppsV1().Deployments(pf.Namespace).Get(name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", nil, getErr
+		}
+		selector, selectorErr := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if selectorErr != nil {
+			return "", nil, selectorErr
+		}
+		pod, err = pf.runningPod(selector)
+	default:
+		err = ErrUnsupportedPortForwardTarget{Target: pf.Target}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	ports, err := translatePorts(pf.Ports, svc, pod)
+	if err != nil {
+		return "", nil, err
+	}
+	return pod.Name, ports, nil
+}
+
+// runningPod returns the first running pod matching selector in the
+// namespace of the port forward, by name to make the choice predictable
+func (pf *PortForwarder) runningPod(selector labels.Selector) (*corev1.Pod, error) {
+	pods, err := pf.ClientSet.CoreV1().Pods(pf.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, ErrNoRunningPod{Namespace: pf.Namespace, Target: pf.Target}
+}
+
+// translatePorts converts the ports to the LOCAL:REMOTE format with remote
+// ports of svc, if any, replaced by the target ports of pod
+func translatePorts(ports []string, svc *corev1.Service, pod *corev1.Pod) ([]string, error) {
+	translated := make([]string, 0, len(ports))
+	for _, port := range ports {
+		local, remote := port, port
+		if i := strings.Index(port, ":"); i >= 0 {
+			local, remote = port[:i], port[i+1:]
+		}
+		remotePort, err := strconv.Atoi(remote)
+		if err != nil || remotePort < 1 || remotePort > 65535 {
+			return nil, ErrInvalidPortForwardPort{Port: port}
+		}
+		if local == "" {
+			// An empty local port makes portforward pick a random one
+			local = "0"
+		}
+
+		if svc != nil {
+			remotePort, err = podPort(svc, pod, int32(remotePort))
+			if err != nil {
+				return nil, err
+			}
+		}
+		translated = append(translated, local+":"+strconv.Itoa(remotePort))
+	}
+	return translated, nil
+}
+
+func podPort(svc *corev1.Service, pod *corev1.Pod, port int32) (int, error) {
+	for _, svcPort := range svc.Spec.Ports {
+		if svcPort.Port != port {
+			continue
+		}
+		switch {
+		case svcPort.TargetPort.Type == intstr.Int && svcPort.TargetPort.IntVal == 0:
+			// The target port defaults to the service port
+			return int(port), nil
+		case svcPort.TargetPort.Type == intstr.Int:
+			return int(svcPort.TargetPort.IntVal), nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == svcPort.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+	}
+	return 0, ErrServicePortNotFound{Service: svc.Name, Port: port}
+}
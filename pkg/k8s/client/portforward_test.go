@@ -0,0 +1,144 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func ironicPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "metal3", Labels: map[string]string{"app": "ironic"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "ironic",
+				Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: 6385}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestPortForwarderPod(t *testing.T) {
+	objs := []runtime.Object{
+		ironicPod("ironic-a", corev1.PodPending),
+		ironicPod("ironic-b", corev1.PodRunning),
+		ironicPod("ironic-c", corev1.PodRunning),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ironic", Namespace: "metal3"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "ironic"},
+				Ports: []corev1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromString("api")},
+					{Port: 443, TargetPort: intstr.FromInt(8443)},
+					{Port: 8080},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "metal3"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "none"}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ironic", Namespace: "metal3"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ironic"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		ports         []string
+		expectedPod   string
+		expectedPorts []string
+		expectedErr   error
+	}{
+		{
+			name:          "pod",
+			target:        "ironic-c",
+			ports:         []string{"6385", "8080:80", ":22"},
+			expectedPod:   "ironic-c",
+			expectedPorts: []string{"6385:6385", "8080:80", "0:22"},
+		},
+		{
+			name:        "pod not running",
+			target:      "pod/ironic-a",
+			ports:       []string{"6385"},
+			expectedErr: client.ErrPodNotRunning{Namespace: "metal3", Name: "ironic-a", Phase: corev1.PodPending},
+		},
+		{
+			name:          "service",
+			target:        "svc/ironic",
+			ports:         []string{"8000:80", "443", "8080"},
+			expectedPod:   "ironic-b",
+			expectedPorts: []string{"8000:6385", "443:8443", "8080:8080"},
+		},
+		{
+			name:        "service port not exposed",
+			target:      "service/ironic",
+			ports:       []string{"22"},
+			expectedErr: client.ErrServicePortNotFound{Service: "ironic", Port: 22},
+		},
+		{
+			name:        "service without running pods",
+			target:      "svc/empty",
+			ports:       []string{"80"},
+			expectedErr: client.ErrNoRunningPod{Namespace: "metal3", Target: "svc/empty"},
+		},
+		{
+			name:          "deployment",
+			target:        "deploy/ironic",
+			ports:         []string{"6385"},
+			expectedPod:   "ironic-b",
+			expectedPorts: []string{"6385:6385"},
+		},
+		{
+			name:        "invalid port",
+			target:      "ironic-b",
+			ports:       []string{"8080:http"},
+			expectedErr: client.ErrInvalidPortForwardPort{Port: "8080:http"},
+		},
+		{
+			name:        "unsupported target",
+			target:      "node/master",
+			ports:       []string{"22"},
+			expectedErr: client.ErrUnsupportedPortForwardTarget{Target: "node/master"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			kclient := fake.NewClient(fake.WithTypedObjects(objs...))
+			pf := client.NewPortForwarder(kclient, "metal3", tt.target, tt.ports)
+			pod, ports, err := pf.Pod()
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedPod, pod)
+			assert.Equal(t, tt.expectedPorts, ports)
+		})
+	}
+}
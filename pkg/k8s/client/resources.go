@@ -0,0 +1,127 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ResourceAccessor gets, lists and deletes resources of any kind known to
+// the cluster. The namespace is ignored for cluster scoped kinds, an empty
+// namespace lists namespaced resources of all namespaces.
+type ResourceAccessor interface {
+	Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error)
+	List(gvk schema.GroupVersionKind, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	Delete(gvk schema.GroupVersionKind, namespace, name string, opts *metav1.DeleteOptions) error
+	RESTMapper() meta.RESTMapper
+}
+
+// Resources implements ResourceAccessor on top of a dynamic client, kinds
+// are translated to resources with a RESTMapper
+type Resources struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// Retry configures retries of API calls failing with transient errors
+	Retry RetryOptions
+}
+
+// Resources implements ResourceAccessor
+var _ ResourceAccessor = &Resources{}
+
+// NewResources returns Resources retrying API calls with the default
+// retry options
+func NewResources(dynamicClient dynamic.Interface, mapper meta.RESTMapper) *Resources {
+	return &Resources{
+		Client: dynamicClient,
+		Mapper: mapper,
+		Retry:  DefaultRetryOptions(),
+	}
+}
+
+// Get returns the resource of kind gvk called name
+func (r *Resources) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	resource, err := r.resource(gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj *unstructured.Unstructured
+	err = Retry(r.Retry, describe("Get of", gvk, namespace, name), func() (getErr error) {
+		obj, getErr = resource.Get(name, metav1.GetOptions{})
+		return getErr
+	})
+	return obj, err
+}
+
+// List returns the resources of kind gvk matching opts
+func (r *Resources) List(gvk schema.GroupVersionKind, namespace string,
+	opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	resource, err := r.resource(gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var list *unstructured.UnstructuredList
+	err = Retry(r.Retry, describe("List of", gvk, namespace, ""), func() (listErr error) {
+		list, listErr = resource.List(opts)
+		return listErr
+	})
+	return list, err
+}
+
+// Delete deletes the resource of kind gvk called name
+func (r *Resources) Delete(gvk schema.GroupVersionKind, namespace, name string, opts *metav1.DeleteOptions) error {
+	resource, err := r.resource(gvk, namespace)
+	if err != nil {
+		return err
+	}
+
+	return Retry(r.Retry, describe("Delete of", gvk, namespace, name), func() error {
+		return resource.Delete(name, opts)
+	})
+}
+
+// RESTMapper returns the mapper used to find the resources of kinds
+func (r *Resources) RESTMapper() meta.RESTMapper {
+	return r.Mapper
+}
+
+func (r *Resources) resource(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := r.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return r.Client.Resource(mapping.Resource), nil
+	}
+	return r.Client.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+func describe(operation string, gvk schema.GroupVersionKind, namespace, name string) string {
+	s := fmt.Sprintf("%s %s", operation, gvk.Kind)
+	if name != "" {
+		s += "/" + name
+	}
+	if namespace != "" {
+		s += " in namespace " + namespace
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+var (
+	configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+)
+
+func newUnstructured(gvk schema.GroupVersionKind, namespace, name string) runtime.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestResources(t *testing.T) {
+	resources := client.NewResources(
+		fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(),
+			newUnstructured(configMapGVK, "test", "first"),
+			newUnstructured(configMapGVK, "test", "second"),
+			newUnstructured(configMapGVK, "other", "third"),
+			newUnstructured(namespaceGVK, "", "test"),
+		),
+		testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	obj, err := resources.Get(configMapGVK, "test", "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", obj.GetName())
+
+	// The namespace of cluster scoped kinds is ignored
+	obj, err = resources.Get(namespaceGVK, "default", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "test", obj.GetName())
+
+	list, err := resources.List(configMapGVK, "test", metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+
+	list, err = resources.List(configMapGVK, "", metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+
+	require.NoError(t, resources.Delete(configMapGVK, "test", "first", &metav1.DeleteOptions{}))
+	_, err = resources.Get(configMapGVK, "test", "first")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	_, err = resources.Get(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Missing"}, "", "name")
+	assert.True(t, meta.IsNoMatchError(err))
+}
@@ -0,0 +1,139 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// RetryOptions configures how calls to the kubernetes API failing with
+// transient errors are retried
+type RetryOptions struct {
+	// Retries is the maximum number of retries, 0 disables retrying
+	Retries int
+	// InitialDelay is the delay before the first retry, it is multiplied
+	// by Factor after every retry up to MaxDelay
+	InitialDelay time.Duration
+	Factor       float64
+	MaxDelay     time.Duration
+}
+
+// DefaultRetryOptions returns the retry options used unless configured
+// otherwise
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		Retries:      5,
+		InitialDelay: 500 * time.Millisecond,
+		Factor:       2,
+		MaxDelay:     30 * time.Second,
+	}
+}
+
+// retryJitter is the maximum random fraction added to every delay, so that
+// concurrent clients don't retry in lockstep
+const retryJitter = 0.1
+
+// transientMessages are parts of error messages of network failures which
+// lost their original type while being wrapped
+var transientMessages = []string{
+	"connection refused",
+	"connection reset by peer",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"unexpected EOF",
+}
+
+// Retry calls fn until it succeeds, fails with an error which is not
+// transient or the retries are exhausted. Retries are reported in verbose
+// logs with the operation name.
+func Retry(opts RetryOptions, operation string, fn func() error) error {
+	delay := opts.InitialDelay
+	for retry := 0; ; retry++ {
+		err := fn()
+		switch {
+		case err == nil:
+			if retry > 0 {
+				log.Debugf("%s succeeded after %d retries", operation, retry)
+			}
+			return nil
+		case !IsTransient(err):
+			return err
+		case retry >= opts.Retries:
+			if retry > 0 {
+				log.Debugf("%s failed after %d retries", operation, retry)
+			}
+			return err
+		}
+
+		wait := wait.Jitter(delay, retryJitter)
+		log.Debugf("%s failed with a transient error, retry %d/%d in %s: %v",
+			operation, retry+1, opts.Retries, wait.Round(time.Millisecond), err)
+		time.Sleep(wait)
+
+		delay = time.Duration(float64(delay) * opts.Factor)
+		if opts.MaxDelay > 0 && delay > opts.MaxDelay {
+			delay = opts.MaxDelay
+		}
+	}
+}
+
+// IsTransient reports whether a call to the kubernetes API that failed with
+// err may succeed if retried: conflicts, throttling, server timeouts and
+// unavailability, and network errors. Aggregated errors are transient if
+// all of them are.
+func IsTransient(err error) bool {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, e := range agg.Errors() {
+			if !IsTransient(e) {
+				return false
+			}
+		}
+		return len(agg.Errors()) > 0
+	}
+
+	switch {
+	case apierrors.IsConflict(err),
+		apierrors.IsTooManyRequests(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsServiceUnavailable(err),
+		utilnet.IsConnectionReset(err),
+		utilnet.IsConnectionRefused(err),
+		utilnet.IsProbableEOF(err):
+		return true
+	}
+	if netErr, ok := err.(net.Error); ok && (netErr.Timeout() || netErr.Temporary()) {
+		return true
+	}
+	if _, ok := err.(apierrors.APIStatus); ok {
+		// Other API errors, e.g. validation failures, are permanent
+		return false
+	}
+	for _, msg := range transientMessages {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
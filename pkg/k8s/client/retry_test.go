@@ -0,0 +1,142 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package client_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+var cmResource = schema.GroupResource{Resource: "configmaps"}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{
+			name:      "conflict",
+			err:       apierrors.NewConflict(cmResource, "cm", errors.New("modified")),
+			transient: true,
+		},
+		{
+			name:      "too many requests",
+			err:       apierrors.NewTooManyRequests("slow down", 1),
+			transient: true,
+		},
+		{
+			name:      "server timeout",
+			err:       apierrors.NewServerTimeout(cmResource, "get", 1),
+			transient: true,
+		},
+		{
+			name:      "connection refused",
+			err:       syscall.ECONNREFUSED,
+			transient: true,
+		},
+		{
+			name:      "wrapped connection refused",
+			err:       errors.New("dial tcp 10.0.0.1:6443: connect: connection refused"),
+			transient: true,
+		},
+		{
+			name:      "not found",
+			err:       apierrors.NewNotFound(cmResource, "cm"),
+			transient: false,
+		},
+		{
+			name:      "invalid",
+			err:       apierrors.NewBadRequest("connection refused"),
+			transient: false,
+		},
+		{
+			name: "aggregate of transient errors",
+			err: utilerrors.NewAggregate([]error{
+				apierrors.NewTooManyRequests("slow down", 1),
+				syscall.ECONNRESET,
+			}),
+			transient: true,
+		},
+		{
+			name: "aggregate with permanent error",
+			err: utilerrors.NewAggregate([]error{
+				apierrors.NewTooManyRequests("slow down", 1),
+				apierrors.NewNotFound(cmResource, "cm"),
+			}),
+			transient: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, client.IsTransient(tt.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	opts := client.RetryOptions{
+		Retries:      3,
+		InitialDelay: time.Millisecond,
+		Factor:       2,
+		MaxDelay:     2 * time.Millisecond,
+	}
+	transientErr := apierrors.NewTooManyRequests("slow down", 1)
+
+	t.Run("succeeds after transient errors", func(t *testing.T) {
+		calls := 0
+		err := client.Retry(opts, "test", func() error {
+			calls++
+			if calls < 3 {
+				return transientErr
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		calls := 0
+		err := client.Retry(opts, "test", func() error {
+			calls++
+			return transientErr
+		})
+		assert.Equal(t, transientErr, err)
+		assert.Equal(t, opts.Retries+1, calls)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		permanentErr := apierrors.NewNotFound(cmResource, "cm")
+		err := client.Retry(opts, "test", func() error {
+			calls++
+			return permanentErr
+		})
+		assert.Equal(t, permanentErr, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,220 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package drain cordons nodes and evicts their pods, so that hosts can be
+// taken down for maintenance or upgrades without disrupting workloads more
+// than their PodDisruptionBudgets allow.
+package drain
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/drain"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Options configures how pods are removed from a node
+type Options struct {
+	// GracePeriod given to pods to terminate, the grace period of the pod
+	// itself is used if negative
+	GracePeriod time.Duration
+	// Timeout of the whole drain, zero means no timeout
+	Timeout time.Duration
+	// IgnoreDaemonSets drains nodes with pods of DaemonSets, those pods are
+	// left on the node since their controller doesn't respect cordoning
+	IgnoreDaemonSets bool
+	// DeleteEmptyDirData drains nodes with pods using emptyDir volumes, the
+	// data of those volumes is lost
+	DeleteEmptyDirData bool
+	// Force drains nodes with pods that are not managed by a controller,
+	// those pods are not recreated elsewhere
+	Force bool
+	// PodSelector limits the pods removed from the node
+	PodSelector string
+	// DryRun reports the pods that would be removed without removing them
+	DryRun bool
+}
+
+// DefaultOptions returns options refusing to drain nodes running pods that
+// would be lost, like kubectl drain does
+func DefaultOptions() Options {
+	return Options{
+		GracePeriod: -1,
+		Timeout:     5 * time.Minute,
+	}
+}
+
+// Drainer cordons, drains and uncordons nodes of a cluster. Pods are
+// removed with the eviction API when the cluster supports it, so
+// PodDisruptionBudgets are respected, and deleted otherwise.
+type Drainer struct {
+	Client kubernetes.Interface
+	Options
+}
+
+// NewDrainer returns a Drainer removing pods as described by opts
+func NewDrainer(clientSet kubernetes.Interface, opts Options) *Drainer {
+	return &Drainer{Client: clientSet, Options: opts}
+}
+
+// Cordon marks the node unschedulable
+func (d *Drainer) Cordon(nodeName string) error {
+	return d.setUnschedulable(nodeName, true)
+}
+
+// Uncordon marks the node schedulable
+func (d *Drainer) Uncordon(nodeName string) error {
+	return d.setUnschedulable(nodeName, false)
+}
+
+// Drain cordons the node and removes its pods. If pods that can't be
+// removed safely run on the node, nothing is removed and ErrDrainBlocked
+// is returned, see Options for how to allow their removal. The node stays
+// cordoned either way.
+func (d *Drainer) Drain(nodeName string) error {
+	if err := d.Cordon(nodeName); err != nil {
+		return err
+	}
+	log.Printf("Draining node %s", nodeName)
+	if err := d.checkPods(nodeName); err != nil {
+		return err
+	}
+
+	helper := d.helper()
+	list, errs := helper.GetPodsForDeletion(nodeName)
+	if errs != nil {
+		return utilerrors.NewAggregate(errs)
+	}
+	if warnings := list.Warnings(); warnings != "" {
+		log.Printf("WARNING: %s", warnings)
+	}
+	if d.DryRun {
+		for _, pod := range list.Pods() {
+			log.Printf("Pod %s/%s would be removed", pod.Namespace, pod.Name)
+		}
+		return nil
+	}
+	return helper.DeleteOrEvictPods(list.Pods())
+}
+
+// checkPods returns an error listing the pods of the node that can't be
+// removed with the options of the Drainer
+func (d *Drainer) checkPods(nodeName string) error {
+	pods, err := d.Client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: d.PodSelector,
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var blocking []string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		if reason := d.blockingReason(pod); reason != "" {
+			blocking = append(blocking, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, reason))
+		}
+	}
+	if len(blocking) > 0 {
+		return ErrDrainBlocked{Node: nodeName, Pods: blocking}
+	}
+	return nil
+}
+
+// blockingReason explains why pod prevents the node from being drained, it
+// is empty if the pod can be removed
+func (d *Drainer) blockingReason(pod corev1.Pod) string {
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		// Static pods can't be removed through the API, they are ignored
+		return ""
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return ""
+	}
+
+	controller := metav1.GetControllerOf(&pod)
+	switch {
+	case controller != nil && controller.Kind == "DaemonSet":
+		if !d.IgnoreDaemonSets {
+			return "managed by DaemonSet " + controller.Name
+		}
+		// Pods of DaemonSets are left on the node
+		return ""
+	case controller == nil && !d.Force:
+		return "not managed by a controller"
+	}
+
+	if !d.DeleteEmptyDirData {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				return "uses emptyDir volume " + volume.Name
+			}
+		}
+	}
+	return ""
+}
+
+func (d *Drainer) setUnschedulable(nodeName string, unschedulable bool) error {
+	node, err := d.Client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	if unschedulable {
+		log.Printf("Cordoning node %s", nodeName)
+	} else {
+		log.Printf("Uncordoning node %s", nodeName)
+	}
+	if d.DryRun {
+		return nil
+	}
+	return drain.RunCordonOrUncordon(d.helper(), node, unschedulable)
+}
+
+func (d *Drainer) helper() *drain.Helper {
+	gracePeriod := -1
+	if d.GracePeriod >= 0 {
+		gracePeriod = int(d.GracePeriod.Seconds())
+	}
+	return &drain.Helper{
+		Client:              d.Client,
+		Force:               d.Force,
+		GracePeriodSeconds:  gracePeriod,
+		IgnoreAllDaemonSets: d.IgnoreDaemonSets,
+		Timeout:             d.Timeout,
+		DeleteLocalData:     d.DeleteEmptyDirData,
+		PodSelector:         d.PodSelector,
+		Out:                 log.Writer(),
+		ErrOut:              log.Writer(),
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			action := "deleted"
+			if usingEviction {
+				action = "evicted"
+			}
+			log.Printf("Pod %s/%s %s", pod.Namespace, pod.Name, action)
+		},
+	}
+}
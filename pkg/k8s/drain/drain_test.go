@@ -0,0 +1,183 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package drain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/drain"
+)
+
+const nodeName = "node01"
+
+func node() *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+}
+
+func pod(name string, managed bool) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+	if managed {
+		controller := true
+		p.OwnerReferences = []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", Controller: &controller},
+		}
+	}
+	return p
+}
+
+func daemonSetPod(name string) *corev1.Pod {
+	p := pod(name, true)
+	p.OwnerReferences[0].Kind = "DaemonSet"
+	p.OwnerReferences[0].Name = "agent"
+	return p
+}
+
+func emptyDirPod(name string) *corev1.Pod {
+	p := pod(name, true)
+	p.Spec.Volumes = []corev1.Volume{
+		{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	return p
+}
+
+func isUnschedulable(t *testing.T, c kubernetes.Interface) bool {
+	return isNodeUnschedulable(t, c, nodeName)
+}
+
+func isNodeUnschedulable(t *testing.T, c kubernetes.Interface, name string) bool {
+	n, err := c.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return n.Spec.Unschedulable
+}
+
+func podExists(t *testing.T, c kubernetes.Interface, name string) bool {
+	_, err := c.CoreV1().Pods("test").Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestCordon(t *testing.T) {
+	c := fake.NewSimpleClientset(node())
+	d := drain.NewDrainer(c, drain.DefaultOptions())
+
+	require.NoError(t, d.Cordon(nodeName))
+	assert.True(t, isUnschedulable(t, c))
+	// Cordoning twice is not an error
+	require.NoError(t, d.Cordon(nodeName))
+
+	require.NoError(t, d.Uncordon(nodeName))
+	assert.False(t, isUnschedulable(t, c))
+
+	assert.True(t, apierrors.IsNotFound(d.Cordon("missing")))
+}
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		options       func(*drain.Options)
+		expectedErr   error
+		cordoned      bool
+		remainingPods []string
+	}{
+		{
+			name:     "managed-pods-are-removed",
+			objects:  []runtime.Object{node(), pod("web-1", true)},
+			cordoned: true,
+		},
+		{
+			name:    "unmanaged-pods-block-drain",
+			objects: []runtime.Object{node(), pod("web-1", true), pod("standalone", false)},
+			expectedErr: drain.ErrDrainBlocked{
+				Node: nodeName,
+				Pods: []string{"test/standalone (not managed by a controller)"},
+			},
+			cordoned:      true,
+			remainingPods: []string{"web-1", "standalone"},
+		},
+		{
+			name:    "daemonset-and-emptydir-pods-block-drain",
+			objects: []runtime.Object{node(), daemonSetPod("agent-1"), emptyDirPod("cache-1")},
+			expectedErr: drain.ErrDrainBlocked{
+				Node: nodeName,
+				Pods: []string{
+					"test/agent-1 (managed by DaemonSet agent)",
+					"test/cache-1 (uses emptyDir volume cache)",
+				},
+			},
+			cordoned:      true,
+			remainingPods: []string{"agent-1", "cache-1"},
+		},
+		{
+			name:    "daemonset-pods-are-ignored-and-emptydir-pods-removed",
+			objects: []runtime.Object{node(), daemonSetPod("agent-1"), emptyDirPod("cache-1")},
+			options: func(o *drain.Options) {
+				o.IgnoreDaemonSets = true
+				o.DeleteEmptyDirData = true
+			},
+			cordoned:      true,
+			remainingPods: []string{"agent-1"},
+		},
+		{
+			name:     "unmanaged-pods-are-removed-with-force",
+			objects:  []runtime.Object{node(), pod("web-1", true), pod("standalone", false)},
+			options:  func(o *drain.Options) { o.Force = true },
+			cordoned: true,
+		},
+		{
+			name:          "dry-run",
+			objects:       []runtime.Object{node(), pod("web-1", true)},
+			options:       func(o *drain.Options) { o.DryRun = true },
+			remainingPods: []string{"web-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset(tt.objects...)
+			opts := drain.DefaultOptions()
+			if tt.options != nil {
+				tt.options(&opts)
+			}
+
+			err := drain.NewDrainer(c, opts).Drain(nodeName)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.cordoned, isUnschedulable(t, c))
+			for _, name := range []string{"web-1", "standalone", "agent-1", "cache-1"} {
+				expected := false
+				for _, remaining := range tt.remainingPods {
+					expected = expected || remaining == name
+				}
+				assert.Equal(t, expected, podExists(t, c, name), name)
+			}
+		})
+	}
+}
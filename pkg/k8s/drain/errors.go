@@ -0,0 +1,62 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package drain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrDrainBlocked is returned when pods of a node can't be removed safely
+type ErrDrainBlocked struct {
+	Node string
+	Pods []string
+}
+
+func (e ErrDrainBlocked) Error() string {
+	return fmt.Sprintf("cannot drain node %s, the following pods would be lost: %s",
+		e.Node, strings.Join(e.Pods, ", "))
+}
+
+// ErrInvalidMaxUnavailable is returned when nodes can't be rolled because
+// fewer than one of them may be unavailable
+type ErrInvalidMaxUnavailable struct {
+	MaxUnavailable int
+}
+
+func (e ErrInvalidMaxUnavailable) Error() string {
+	return fmt.Sprintf("max unavailable nodes must be at least 1, got %d", e.MaxUnavailable)
+}
+
+// ErrNodeNotReady is returned when a node doesn't become Ready in time
+type ErrNodeNotReady struct {
+	Node    string
+	Timeout time.Duration
+}
+
+func (e ErrNodeNotReady) Error() string {
+	return fmt.Sprintf("node %s is not ready after %s", e.Node, e.Timeout)
+}
+
+// ErrNodeFailed is returned when a rolling operation failed on a node
+type ErrNodeFailed struct {
+	Node string
+	Err  error
+}
+
+func (e ErrNodeFailed) Error() string {
+	return fmt.Sprintf("node %s: %v", e.Node, e.Err)
+}
@@ -0,0 +1,127 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// NodeAction is performed on a node once all its pods have been removed,
+// e.g. a firmware update or a reboot into a new OS image
+type NodeAction func(nodeName string) error
+
+// RollingOptions configures how an action is rolled across nodes
+type RollingOptions struct {
+	// MaxUnavailable is how many nodes are drained at the same time
+	MaxUnavailable int
+	// ReadyTimeout is how long to wait for a node to be Ready after the
+	// action before it is uncordoned, the node isn't waited for if zero
+	ReadyTimeout time.Duration
+	// PollInterval is how often the Ready condition of a node is checked
+	PollInterval time.Duration
+}
+
+// DefaultRollingOptions returns options handling one node at a time
+func DefaultRollingOptions() RollingOptions {
+	return RollingOptions{
+		MaxUnavailable: 1,
+		ReadyTimeout:   10 * time.Minute,
+		PollInterval:   5 * time.Second,
+	}
+}
+
+// Roll performs action on every node, in batches of MaxUnavailable nodes.
+// Every node of a batch is drained, then action is run and the node is
+// uncordoned once it is Ready. Pods are evicted, so that their
+// PodDisruptionBudgets are respected. Batches following one with a failure
+// are not started, and failed nodes are left cordoned for inspection.
+func (d *Drainer) Roll(nodes []string, opts RollingOptions, action NodeAction) error {
+	if opts.MaxUnavailable < 1 {
+		return ErrInvalidMaxUnavailable{MaxUnavailable: opts.MaxUnavailable}
+	}
+
+	for start := 0; start < len(nodes); start += opts.MaxUnavailable {
+		end := start + opts.MaxUnavailable
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		batch := nodes[start:end]
+
+		var mu sync.Mutex
+		var errs []error
+		workqueue.ParallelizeUntil(context.Background(), len(batch), len(batch), func(i int) {
+			if err := d.rollNode(batch[i], opts, action); err != nil {
+				mu.Lock()
+				errs = append(errs, ErrNodeFailed{Node: batch[i], Err: err})
+				mu.Unlock()
+			}
+		})
+		if len(errs) > 0 {
+			return utilerrors.NewAggregate(errs)
+		}
+	}
+	return nil
+}
+
+func (d *Drainer) rollNode(nodeName string, opts RollingOptions, action NodeAction) error {
+	if err := d.Drain(nodeName); err != nil {
+		return err
+	}
+	if d.DryRun {
+		log.Printf("Node %s would be updated and uncordoned", nodeName)
+		return nil
+	}
+
+	if err := action(nodeName); err != nil {
+		return err
+	}
+	if opts.ReadyTimeout > 0 {
+		if err := d.waitReady(nodeName, opts); err != nil {
+			return err
+		}
+	}
+	return d.Uncordon(nodeName)
+}
+
+// waitReady blocks until the Ready condition of the node is True
+func (d *Drainer) waitReady(nodeName string, opts RollingOptions) error {
+	log.Printf("Waiting for node %s to be ready", nodeName)
+	err := wait.PollImmediate(opts.PollInterval, opts.ReadyTimeout, func() (bool, error) {
+		node, err := d.Client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, c := range node.Status.Conditions {
+			if c.Type == corev1.NodeReady {
+				return c.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return ErrNodeNotReady{Node: nodeName, Timeout: opts.ReadyTimeout}
+	}
+	return err
+}
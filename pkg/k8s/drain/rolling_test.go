@@ -0,0 +1,138 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package drain_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/drain"
+)
+
+func readyNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestRoll(t *testing.T) {
+	nodes := []string{"node01", "node02", "node03"}
+	errFirmware := errors.New("firmware update failed")
+
+	tests := []struct {
+		name           string
+		maxUnavailable int
+		notReady       string
+		failOn         string
+		expectedErr    error
+		expectedNodes  []string
+		cordoned       []string
+	}{
+		{
+			name:           "one-at-a-time",
+			maxUnavailable: 1,
+			expectedNodes:  nodes,
+		},
+		{
+			name:           "in-batches",
+			maxUnavailable: 2,
+			expectedNodes:  nodes,
+		},
+		{
+			name:           "stops-after-failure",
+			maxUnavailable: 1,
+			failOn:         "node02",
+			expectedErr:    utilerrors.NewAggregate([]error{drain.ErrNodeFailed{Node: "node02", Err: errFirmware}}),
+			expectedNodes:  []string{"node01", "node02"},
+			cordoned:       []string{"node02"},
+		},
+		{
+			name:           "node-not-ready",
+			maxUnavailable: 1,
+			notReady:       "node01",
+			expectedErr: utilerrors.NewAggregate([]error{drain.ErrNodeFailed{
+				Node: "node01",
+				Err:  drain.ErrNodeNotReady{Node: "node01", Timeout: 10 * time.Millisecond},
+			}}),
+			expectedNodes: []string{"node01"},
+			cordoned:      []string{"node01"},
+		},
+		{
+			name:           "invalid-max-unavailable",
+			maxUnavailable: 0,
+			expectedErr:    drain.ErrInvalidMaxUnavailable{MaxUnavailable: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset()
+			for _, name := range nodes {
+				ready := corev1.ConditionTrue
+				if name == tt.notReady {
+					ready = corev1.ConditionFalse
+				}
+				_, err := c.CoreV1().Nodes().Create(readyNode(name, ready))
+				require.NoError(t, err)
+			}
+
+			var mu sync.Mutex
+			var updated []string
+			action := func(nodeName string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				updated = append(updated, nodeName)
+				// The node must be drained while it is updated
+				node, err := c.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+				if assert.NoError(t, err) {
+					assert.True(t, node.Spec.Unschedulable)
+				}
+				if nodeName == tt.failOn {
+					return errFirmware
+				}
+				return nil
+			}
+
+			opts := drain.DefaultRollingOptions()
+			opts.MaxUnavailable = tt.maxUnavailable
+			opts.ReadyTimeout = 10 * time.Millisecond
+			opts.PollInterval = time.Millisecond
+			err := drain.NewDrainer(c, drain.DefaultOptions()).Roll(nodes, opts, action)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.ElementsMatch(t, tt.expectedNodes, updated)
+
+			for _, name := range nodes {
+				expected := false
+				for _, cordoned := range tt.cordoned {
+					expected = expected || cordoned == name
+				}
+				assert.Equal(t, expected, isNodeUnschedulable(t, c, name), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package events streams the kubernetes events of resources while they are
+// being deployed.
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Streamer prints the warning events of a set of resources, such as
+// FailedScheduling or image pull back-offs, as they happen. An event is
+// relevant if it involves one of the resources or an object whose name
+// starts with the name of one of them, such as the pods of a deployment.
+type Streamer struct {
+	Client kubernetes.Interface
+	Out    io.Writer
+
+	names      sets.String
+	namespaces sets.String
+	watchers   []watch.Interface
+	wg         sync.WaitGroup
+	since      time.Time
+}
+
+// NewStreamer returns a Streamer printing events to stdout
+func NewStreamer(client kubernetes.Interface) *Streamer {
+	return &Streamer{
+		Client:     client,
+		Out:        os.Stdout,
+		names:      sets.NewString(),
+		namespaces: sets.NewString(),
+	}
+}
+
+// AddResource registers a resource whose events are streamed. Events of
+// cluster scoped resources, which have an empty namespace, are not watched.
+func (s *Streamer) AddResource(namespace, name string) {
+	if namespace == "" {
+		return
+	}
+	s.namespaces.Insert(namespace)
+	s.names.Insert(name)
+}
+
+// Start watches the events of all added resources until Stop is called.
+// Only events happening after Start are printed.
+func (s *Streamer) Start() error {
+	// Event timestamps have a precision of one second
+	s.since = time.Now().Truncate(time.Second)
+	for _, ns := range s.namespaces.List() {
+		w, err := s.Client.CoreV1().Events(ns).Watch(metav1.ListOptions{})
+		if err != nil {
+			s.Stop()
+			return err
+		}
+		s.watchers = append(s.watchers, w)
+		s.wg.Add(1)
+		go s.stream(w)
+	}
+	return nil
+}
+
+// Stop stops watching events and waits until all received events are
+// printed
+func (s *Streamer) Stop() {
+	for _, w := range s.watchers {
+		w.Stop()
+	}
+	s.wg.Wait()
+	s.watchers = nil
+}
+
+func (s *Streamer) stream(w watch.Interface) {
+	defer s.wg.Done()
+	for e := range w.ResultChan() {
+		event, ok := e.Object.(*corev1.Event)
+		if !ok || e.Type == watch.Deleted || !s.relevant(event) {
+			continue
+		}
+		obj := event.InvolvedObject
+		fmt.Fprintf(s.Out, "%s %s %s/%s", event.Type, event.Reason, strings.ToLower(obj.Kind), obj.Name)
+		if obj.Namespace != "" {
+			fmt.Fprintf(s.Out, " in namespace %s", obj.Namespace)
+		}
+		fmt.Fprintf(s.Out, ": %s\n", strings.TrimSpace(event.Message))
+	}
+}
+
+func (s *Streamer) relevant(event *corev1.Event) bool {
+	if event.Type != corev1.EventTypeWarning || eventTime(event).Before(s.since) {
+		return false
+	}
+	name := event.InvolvedObject.Name
+	if s.names.Has(name) {
+		return true
+	}
+	for _, owner := range s.names.UnsortedList() {
+		if strings.HasPrefix(name, owner+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// eventTime returns when the event last occurred, events record it in
+// different fields depending on the reporting component
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/events"
+)
+
+func newEvent(name, eventType, reason, kind, objName string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Name:      objName,
+			Namespace: "test",
+		},
+		Type:          eventType,
+		Reason:        reason,
+		Message:       reason + " message",
+		LastTimestamp: metav1.NewTime(at),
+	}
+}
+
+func TestStreamer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	out := &bytes.Buffer{}
+
+	streamer := events.NewStreamer(client)
+	streamer.Out = out
+	streamer.AddResource("test", "web")
+	streamer.AddResource("", "cluster-scoped")
+	require.NoError(t, streamer.Start())
+
+	now := time.Now()
+	for _, event := range []*corev1.Event{
+		newEvent("scheduling", corev1.EventTypeWarning, "FailedScheduling", "Pod", "web-5d4f8-x2x9z", now),
+		newEvent("pull", corev1.EventTypeWarning, "Failed", "Pod", "web-5d4f8-x2x9z", now),
+		newEvent("normal", corev1.EventTypeNormal, "Scheduled", "Pod", "web-5d4f8-x2x9z", now),
+		newEvent("unrelated", corev1.EventTypeWarning, "BackOff", "Pod", "webhook-1", now),
+		newEvent("old", corev1.EventTypeWarning, "BackOff", "Deployment", "web", now.Add(-time.Hour)),
+	} {
+		_, err := client.CoreV1().Events("test").Create(event)
+		require.NoError(t, err)
+	}
+	streamer.Stop()
+
+	assert.Equal(t, "Warning FailedScheduling pod/web-5d4f8-x2x9z in namespace test: FailedScheduling message\n"+
+		"Warning Failed pod/web-5d4f8-x2x9z in namespace test: Failed message\n", out.String())
+}
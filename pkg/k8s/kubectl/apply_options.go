@@ -15,16 +15,28 @@
 package kubectl
 
 import (
+	"time"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/kubectl/pkg/cmd/apply"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// Dry run strategies of kubectl apply
+const (
+	DryRunNone   = "none"
+	DryRunClient = "client"
+	DryRunServer = "server"
+)
+
 // ApplyOptions is a abstraction layer
 // to ApplyOptions of kubectl/apply package
 type ApplyOptions struct {
 	ApplyOptions *apply.ApplyOptions
+
+	deploymentID     string
+	pruneProtections []PruneProtection
 }
 
 // SetDryRun enables/disables the dry run flag in kubectl apply options
@@ -32,6 +44,12 @@ func (ao *ApplyOptions) SetDryRun(dryRun bool) {
 	ao.ApplyOptions.DryRun = dryRun
 }
 
+// SetServerDryRun enables/disables the server dry run in kubectl apply
+// options, changes are submitted to the server but not persisted
+func (ao *ApplyOptions) SetServerDryRun(serverDryRun bool) {
+	ao.ApplyOptions.ServerDryRun = serverDryRun
+}
+
 // SetPrune enables/disables the prune flag in kubectl apply options
 func (ao *ApplyOptions) SetPrune(label string) {
 	if label != "" {
@@ -42,14 +60,82 @@ func (ao *ApplyOptions) SetPrune(label string) {
 	}
 }
 
+// SetPruneProtections sets the resources that are never pruned, they are
+// reported with ActionProtected instead. DefaultPruneProtections are used
+// unless this is called.
+func (ao *ApplyOptions) SetPruneProtections(protections []PruneProtection) {
+	ao.pruneProtections = protections
+}
+
+func (ao *ApplyOptions) isProtected(c PruneCandidate) bool {
+	for _, p := range ao.pruneProtections {
+		if p.Matches(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDeploymentID sets the identity of the deployment the applied resources
+// belong to. Every resource is labeled with it, see DeploymentLabel.
+func (ao *ApplyOptions) SetDeploymentID(id string) {
+	ao.deploymentID = id
+}
+
+// DeploymentSelector returns the label selector matching the resources of
+// the deployment set with SetDeploymentID
+func (ao *ApplyOptions) DeploymentSelector() string {
+	return DeploymentLabel + "=" + ao.deploymentID
+}
+
+// SetServerSide enables/disables server-side apply. Changes are sent to the
+// server as apply patches owned by fieldManager, conflicts with fields owned
+// by other managers are overridden if forceConflicts is true.
+func (ao *ApplyOptions) SetServerSide(enabled bool, fieldManager string, forceConflicts bool) {
+	ao.ApplyOptions.ServerSideApply = enabled
+	ao.ApplyOptions.FieldManager = fieldManager
+	ao.ApplyOptions.ForceConflicts = forceConflicts
+}
+
 // SetSourceFiles sets files to read for kubectl apply command
 func (ao *ApplyOptions) SetSourceFiles(fileNames []string) {
 	ao.ApplyOptions.DeleteOptions.Filenames = fileNames
 }
 
-// Run executes the `apply` command.
-func (ao *ApplyOptions) Run() error {
-	return ao.ApplyOptions.Run()
+// Run executes the `apply` command and returns the action taken on every
+// resource. Instead of being printed, the resources and warnings reported
+// by kubectl are collected in the result, which is also returned if the
+// apply failed part way. Pruning is done by airshipctl rather than kubectl
+// so that protected resources are skipped.
+func (ao *ApplyOptions) Run() (*ApplyResult, error) {
+	o := ao.ApplyOptions
+	if o.ForceConflicts && !o.ServerSideApply {
+		return nil, ErrForceConflictsWithoutServerSide{}
+	}
+	// The result of a server-side apply can't be computed locally, so dry
+	// run is delegated to the server
+	if o.ServerSideApply && o.DryRun {
+		o.DryRun = false
+		o.ServerDryRun = true
+	}
+
+	rec := newResultRecorder(o.ErrOut)
+	toPrinter, errOut := o.ToPrinter, o.ErrOut
+	o.ToPrinter, o.ErrOut = rec.toPrinter, rec
+	defer func() { o.ToPrinter, o.ErrOut = toPrinter, errOut }()
+
+	prune := o.Prune
+	o.Prune = false
+	defer func() { o.Prune = prune }()
+
+	start := time.Now()
+	err := o.Run()
+	if err == nil && prune {
+		err = ao.prune(rec)
+	}
+	rec.result.Duration = time.Since(start)
+	rec.result.DryRun = o.DryRun || o.ServerDryRun
+	return rec.result, err
 }
 
 // NewApplyOptions is a helper function that Creates ApplyOptions of kubectl apply module
@@ -119,5 +205,5 @@ func NewApplyOptions(f cmdutil.Factory, streams genericclioptions.IOStreams) (*A
 	if err != nil {
 		return nil, err
 	}
-	return &ApplyOptions{ApplyOptions: o}, nil
+	return &ApplyOptions{ApplyOptions: o, pruneProtections: DefaultPruneProtections}, nil
 }
@@ -48,7 +48,13 @@ func TestApplyOptionsRun(t *testing.T) {
 	require.NoError(t, err, "Could not build ApplyAdapter")
 	aa.SetDryRun(true)
 	aa.SetSourceFiles([]string{filenameRC})
-	assert.NoError(t, aa.Run())
+	result, err := aa.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, "ReplicationController", result.Resources[0].Kind)
+	assert.Equal(t, "test", result.Resources[0].Namespace)
+	assert.Equal(t, "test-rc", result.Resources[0].Name)
+	assert.True(t, result.DryRun)
 }
 
 func TestNewApplyOptionsFactoryFailures(t *testing.T) {
@@ -85,3 +91,21 @@ func TestNewApplyOptionsFactoryFailures(t *testing.T) {
 		assert.Equal(t, err, test.expectedError)
 	}
 }
+
+func TestApplyOptionsServerSide(t *testing.T) {
+	f := k8stest.NewFakeFactoryForRC(t, filenameRC)
+	defer f.Cleanup()
+
+	aa, err := kubectl.NewApplyOptions(f, testStreams)
+	require.NoError(t, err)
+
+	aa.SetServerSide(true, "airshipctl", true)
+	assert.True(t, aa.ApplyOptions.ServerSideApply)
+	assert.Equal(t, "airshipctl", aa.ApplyOptions.FieldManager)
+	assert.True(t, aa.ApplyOptions.ForceConflicts)
+
+	aa.SetServerSide(false, "airshipctl", true)
+	aa.SetSourceFiles([]string{filenameRC})
+	_, err = aa.Run()
+	assert.Equal(t, kubectl.ErrForceConflictsWithoutServerSide{}, err)
+}
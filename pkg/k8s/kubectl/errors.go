@@ -0,0 +1,86 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidDeploymentID is returned when a deployment identity can't be
+// used as a label value
+type ErrInvalidDeploymentID struct {
+	ID      string
+	Reasons []string
+}
+
+func (e ErrInvalidDeploymentID) Error() string {
+	return fmt.Sprintf("invalid deployment identity %q: %s", e.ID, strings.Join(e.Reasons, "; "))
+}
+
+// ErrForceConflictsWithoutServerSide is returned when conflicts are to be
+// forced while server-side apply is disabled
+type ErrForceConflictsWithoutServerSide struct{}
+
+func (e ErrForceConflictsWithoutServerSide) Error() string {
+	return "forcing conflicts is only supported with server-side apply"
+}
+
+// ErrInvalidDryRunStrategy is returned for an unknown dry run strategy
+type ErrInvalidDryRunStrategy struct {
+	Strategy string
+}
+
+func (e ErrInvalidDryRunStrategy) Error() string {
+	return fmt.Sprintf("invalid dry run strategy %q, must be one of %q, %q or %q",
+		e.Strategy, DryRunNone, DryRunClient, DryRunServer)
+}
+
+// ErrInvalidOutputFormat is returned for an unknown apply result format
+type ErrInvalidOutputFormat struct {
+	Format string
+}
+
+func (e ErrInvalidOutputFormat) Error() string {
+	return fmt.Sprintf("invalid output format %q, must be one of %q or %q", e.Format, OutputTable, OutputJSON)
+}
+
+// ErrSchemaValidation is returned when documents don't match the OpenAPI
+// schema of the cluster
+type ErrSchemaValidation struct {
+	Documents []DocumentValidation
+}
+
+func (e ErrSchemaValidation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d document(s) don't match the schema of the cluster:", len(e.Documents))
+	for _, doc := range e.Documents {
+		fmt.Fprintf(&b, "\n%s:", doc.Document)
+		for _, msg := range doc.Errors {
+			fmt.Fprintf(&b, "\n  - %s", msg)
+		}
+	}
+	return b.String()
+}
+
+// ErrInvalidPruneProtection is returned when a prune protection can't be parsed
+type ErrInvalidPruneProtection struct {
+	Value string
+}
+
+func (e ErrInvalidPruneProtection) Error() string {
+	return fmt.Sprintf("invalid prune protection %q, must be Kind[.group][/name] or Kind[.group]/namespace/name",
+		e.Value)
+}
@@ -23,6 +23,8 @@ import (
 // Interface provides a abstraction layer built on top of kubectl libraries
 // to implement kubectl subcommands as kubectl apply
 type Interface interface {
-	Apply(docs []document.Document, ao *ApplyOptions) error
+	Apply(docs []document.Document, ao *ApplyOptions) (*ApplyResult, error)
 	ApplyOptions() (*ApplyOptions, error)
+	PrunePreview(docs []document.Document, ao *ApplyOptions) ([]PruneCandidate, error)
+	Validate(docs []document.Document) error
 }
@@ -56,11 +56,12 @@ func (kubectl *Kubectl) WithBufferDir(bd string) *Kubectl {
 	return kubectl
 }
 
-// Apply is abstraction to kubectl apply command
-func (kubectl *Kubectl) Apply(docs []document.Document, ao *ApplyOptions) error {
+// Apply is abstraction to kubectl apply command, it returns the action
+// taken on every resource of docs
+func (kubectl *Kubectl) Apply(docs []document.Document, ao *ApplyOptions) (*ApplyResult, error) {
 	tf, err := kubectl.TempFile(kubectl.bufferDir, "initinfra")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer func(f document.File) {
@@ -72,10 +73,13 @@ func (kubectl *Kubectl) Apply(docs []document.Document, ao *ApplyOptions) error
 	}(tf)
 	defer tf.Close()
 	for _, doc := range docs {
+		if ao.deploymentID != "" {
+			doc.Label(map[string]string{DeploymentLabel: ao.deploymentID})
+		}
 		// Write out documents to temporary file
 		err = utilyaml.WriteOut(tf, doc)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	ao.SetSourceFiles([]string{tf.Name()})
@@ -75,6 +75,7 @@ func TestApply(t *testing.T) {
 	ao, err := kctl.ApplyOptions()
 	require.NoError(t, err, "failed to get documents from bundle")
 	ao.SetDryRun(true)
+	ao.SetDeploymentID("initinfra")
 
 	b := testutil.NewTestBundle(t, fixtureDir)
 	docs, err := b.GetByAnnotation("airshipit.org/initinfra")
@@ -118,6 +119,10 @@ func TestApply(t *testing.T) {
 	}
 	for _, test := range tests {
 		kctl.FileSystem = test.fs
-		assert.Equal(t, kctl.Apply(docs, ao), test.expectedErr)
+		_, err = kctl.Apply(docs, ao)
+		assert.Equal(t, test.expectedErr, err)
+	}
+	for _, doc := range docs {
+		assert.Equal(t, "initinfra", doc.GetLabels()[kubectl.DeploymentLabel])
 	}
 }
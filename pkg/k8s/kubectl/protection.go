@@ -0,0 +1,87 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"strings"
+)
+
+// PruneProtection matches resources that must never be pruned, even if
+// they are no longer part of the applied documents. Empty Namespace and
+// Name match any namespace and name.
+type PruneProtection struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// DefaultPruneProtections are the resources whose deletion is either
+// destructive to data or to the cluster itself
+var DefaultPruneProtections = []PruneProtection{
+	{Kind: "Namespace"},
+	{Kind: "PersistentVolume"},
+	{Group: "metal3.io", Kind: "BareMetalHost"},
+}
+
+// ParsePruneProtection parses a prune protection from one of the forms
+// Kind[.group], Kind[.group]/name or Kind[.group]/namespace/name, e.g.
+// BareMetalHost.metal3.io/metal3/node01
+func ParsePruneProtection(value string) (PruneProtection, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) > 3 {
+		return PruneProtection{}, ErrInvalidPruneProtection{Value: value}
+	}
+	for _, part := range parts {
+		if part == "" {
+			return PruneProtection{}, ErrInvalidPruneProtection{Value: value}
+		}
+	}
+
+	p := PruneProtection{}
+	p.Kind = parts[0]
+	if i := strings.Index(p.Kind, "."); i >= 0 {
+		p.Kind, p.Group = p.Kind[:i], p.Kind[i+1:]
+	}
+	switch len(parts) {
+	case 2:
+		p.Name = parts[1]
+	case 3:
+		p.Namespace, p.Name = parts[1], parts[2]
+	}
+	return p, nil
+}
+
+// Matches returns true if c is protected by p
+func (p PruneProtection) Matches(c PruneCandidate) bool {
+	return p.Group == c.Group &&
+		p.Kind == c.Kind &&
+		(p.Namespace == "" || p.Namespace == c.Namespace) &&
+		(p.Name == "" || p.Name == c.Name)
+}
+
+func (p PruneProtection) String() string {
+	s := p.Kind
+	if p.Group != "" {
+		s += "." + p.Group
+	}
+	if p.Namespace != "" {
+		s += "/" + p.Namespace
+	}
+	if p.Name != "" {
+		s += "/" + p.Name
+	}
+	return s
+}
@@ -0,0 +1,271 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// DeploymentLabel is set on every resource applied as part of a deployment,
+// its value is the deployment identity. Resources carrying the label that are
+// missing from the next apply of the same deployment are pruned.
+const DeploymentLabel = document.BaseAirshipSelector + "/deployment"
+
+// defaultPruneKinds are the kinds of resources always considered for
+// pruning, the default ones of kubectl apply --prune
+var defaultPruneKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Endpoints"},
+	{Version: "v1", Kind: "Namespace"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Version: "v1", Kind: "PersistentVolume"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "ReplicationController"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+}
+
+// pruneKinds returns the kinds of resources considered for pruning: the
+// default ones and the kinds of the applied resources, so that custom
+// resources are pruned too. Resources of a kind that is no longer applied
+// at all are only pruned if it is a default one.
+func pruneKinds(applied []schema.GroupVersionKind) []schema.GroupVersionKind {
+	kinds := append([]schema.GroupVersionKind{}, defaultPruneKinds...)
+	seen := map[schema.GroupKind]bool{}
+	for _, gvk := range kinds {
+		seen[gvk.GroupKind()] = true
+	}
+	for _, gvk := range applied {
+		if !seen[gvk.GroupKind()] {
+			seen[gvk.GroupKind()] = true
+			kinds = append(kinds, gvk)
+		}
+	}
+	return kinds
+}
+
+// PruneCandidate identifies a resource in the cluster that is deleted
+// when the deployment it belongs to is applied with pruning enabled
+type PruneCandidate struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+	// Protected candidates match a PruneProtection and are never deleted
+	Protected bool
+}
+
+func (c PruneCandidate) String() string {
+	if c.Namespace == "" {
+		return fmt.Sprintf("%s/%s", c.Kind, c.Name)
+	}
+	return fmt.Sprintf("%s/%s in namespace %s", c.Kind, c.Name, c.Namespace)
+}
+
+// pruneCandidate is a PruneCandidate with the resource used to delete it
+type pruneCandidate struct {
+	PruneCandidate
+	gvk      schema.GroupVersionKind
+	resource schema.GroupVersionResource
+}
+
+// ValidateDeploymentID checks that id can be used as the value of the
+// DeploymentLabel
+func ValidateDeploymentID(id string) error {
+	if errs := validation.IsValidLabelValue(id); len(errs) > 0 {
+		return ErrInvalidDeploymentID{ID: id, Reasons: errs}
+	}
+	return nil
+}
+
+// PrunePreview returns the resources that would be deleted if docs were
+// applied with ao. Only resources matching the prune selector of ao, which
+// were applied as part of the deployment and are not part of docs are
+// returned, see wasApplied.
+// Candidates matching the prune protections of ao are returned as
+// Protected. Nothing is returned if pruning is disabled in ao.
+func (kubectl *Kubectl) PrunePreview(docs []document.Document, ao *ApplyOptions) ([]PruneCandidate, error) {
+	o := ao.ApplyOptions
+	if !o.Prune {
+		return nil, nil
+	}
+
+	applied := sets.NewString()
+	namespaces := sets.NewString()
+	kinds := make([]schema.GroupVersionKind, 0, len(docs))
+	for _, doc := range docs {
+		ns := doc.GetNamespace()
+		gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+		kinds = append(kinds, gvk)
+		mapping, err := o.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			ns = metav1.NamespaceNone
+		} else if ns == "" {
+			ns = o.Namespace
+		}
+		applied.Insert(pruneKey(gvk.Group, gvk.Kind, ns, doc.GetName()))
+		if ns != metav1.NamespaceNone {
+			namespaces.Insert(ns)
+		}
+	}
+
+	found, err := ao.pruneCandidates(applied, namespaces, kinds)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]PruneCandidate, 0, len(found))
+	for _, c := range found {
+		candidates = append(candidates, c.PruneCandidate)
+	}
+	return candidates, nil
+}
+
+// pruneCandidates returns the resources of the prune kinds of appliedKinds
+// matching the prune selector, which were applied as part of the deployment
+// and are not in applied. Namespaced kinds are only looked up in namespaces.
+func (ao *ApplyOptions) pruneCandidates(applied, namespaces sets.String,
+	appliedKinds []schema.GroupVersionKind) ([]pruneCandidate, error) {
+	o := ao.ApplyOptions
+	var candidates []pruneCandidate
+	for _, gvk := range pruneKinds(appliedKinds) {
+		mapping, err := o.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// The kind is not served by the cluster, there is nothing to prune
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		listNamespaces := []string{metav1.NamespaceNone}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			listNamespaces = namespaces.List()
+		}
+		for _, ns := range listNamespaces {
+			list, err := o.DynamicClient.Resource(mapping.Resource).
+				Namespace(ns).
+				List(metav1.ListOptions{LabelSelector: o.Selector})
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range list.Items {
+				if !ao.wasApplied(item) ||
+					applied.Has(pruneKey(gvk.Group, gvk.Kind, item.GetNamespace(), item.GetName())) {
+					continue
+				}
+				c := PruneCandidate{
+					Group:     gvk.Group,
+					Kind:      gvk.Kind,
+					Namespace: item.GetNamespace(),
+					Name:      item.GetName(),
+				}
+				c.Protected = ao.isProtected(c)
+				candidates = append(candidates, pruneCandidate{PruneCandidate: c, gvk: gvk, resource: mapping.Resource})
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// wasApplied reports whether item was applied as part of the deployment of
+// ao: it carries its DeploymentLabel, which server-side apply preserves like
+// any other field, or it was created by a client-side kubectl apply. Other
+// resources matching the prune selector are never pruned.
+func (ao *ApplyOptions) wasApplied(item unstructured.Unstructured) bool {
+	if ao.deploymentID != "" && item.GetLabels()[DeploymentLabel] == ao.deploymentID {
+		return true
+	}
+	_, ok := item.GetAnnotations()[corev1.LastAppliedConfigAnnotation]
+	return ok
+}
+
+// prune deletes the resources of the deployment that were not applied by
+// the last run, except for the protected ones. Every candidate is reported
+// to rec, protected ones with ActionProtected.
+func (ao *ApplyOptions) prune(rec *resultRecorder) error {
+	o := ao.ApplyOptions
+	candidates, err := ao.pruneCandidates(rec.applied, rec.namespaces, rec.kinds)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		action := ActionPruned
+		switch {
+		case c.Protected:
+			log.Printf("%s is protected from pruning, skipping it", c)
+			action = ActionProtected
+		case !o.DryRun:
+			if err = ao.deletePruned(c); err != nil {
+				return err
+			}
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(c.gvk)
+		obj.SetNamespace(c.Namespace)
+		obj.SetName(c.Name)
+		printer, err := rec.toPrinter(action)
+		if err != nil {
+			return err
+		}
+		if err = printer.PrintObj(obj, o.Out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePruned deletes a pruned resource the same way kubectl apply does
+func (ao *ApplyOptions) deletePruned(c pruneCandidate) error {
+	o := ao.ApplyOptions
+	options := &metav1.DeleteOptions{}
+	policy := metav1.DeletePropagationForeground
+	if o.DeleteOptions != nil {
+		if o.DeleteOptions.GracePeriod >= 0 {
+			options = metav1.NewDeleteOptions(int64(o.DeleteOptions.GracePeriod))
+		}
+		if !o.DeleteOptions.Cascade {
+			policy = metav1.DeletePropagationOrphan
+		}
+	}
+	if o.ServerDryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	options.PropagationPolicy = &policy
+	return o.DynamicClient.Resource(c.resource).Namespace(c.Namespace).Delete(c.Name, options)
+}
+
+func pruneKey(group, kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", group, kind, namespace, name)
+}
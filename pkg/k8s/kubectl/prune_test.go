@@ -0,0 +1,204 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/testutil"
+	k8stest "opendev.org/airship/airshipctl/testutil/k8sutils"
+)
+
+func deployedObject(apiVersion, kind, name, namespace, deployment string, applied bool) runtime.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(map[string]string{kubectl.DeploymentLabel: deployment})
+	if applied {
+		obj.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: "{}"})
+	}
+	return obj
+}
+
+func TestPrunePreview(t *testing.T) {
+	f := k8stest.NewFakeFactoryForRC(t, filenameRC)
+	defer f.Cleanup()
+	f.FakeDynamicClient = fakedynamic.NewSimpleDynamicClient(scheme.Scheme,
+		// Part of the applied documents
+		deployedObject("v1", "ReplicationController", "test-rc", "test", "initinfra", true),
+		// Removed from the documents
+		deployedObject("v1", "ReplicationController", "old-rc", "test", "initinfra", true),
+		deployedObject("v1", "Namespace", "old-namespace", "", "initinfra", true),
+		// Applied server-side, without the last applied annotation
+		deployedObject("v1", "ConfigMap", "server-side", "test", "initinfra", false),
+		// Belongs to another deployment
+		deployedObject("v1", "ConfigMap", "workload", "test", "workloads", true),
+	)
+
+	kctl := kubectl.NewKubectl(f)
+	ao, err := kctl.ApplyOptions()
+	require.NoError(t, err)
+	ao.SetDeploymentID("initinfra")
+
+	docs, err := testutil.NewTestBundle(t, fixtureDir).GetAllDocuments()
+	require.NoError(t, err)
+
+	candidates, err := kctl.PrunePreview(docs, ao)
+	require.NoError(t, err)
+	assert.Empty(t, candidates, "nothing is pruned unless pruning is enabled")
+
+	ao.SetPrune(ao.DeploymentSelector())
+	candidates, err = kctl.PrunePreview(docs, ao)
+	require.NoError(t, err)
+	assert.Equal(t, []kubectl.PruneCandidate{
+		{Kind: "ConfigMap", Namespace: "test", Name: "server-side"},
+		{Kind: "Namespace", Name: "old-namespace", Protected: true},
+		{Kind: "ReplicationController", Namespace: "test", Name: "old-rc"},
+	}, candidates)
+	assert.Equal(t, "Namespace/old-namespace", candidates[1].String())
+	assert.Equal(t, "ReplicationController/old-rc in namespace test", candidates[2].String())
+
+	ao.SetPruneProtections([]kubectl.PruneProtection{{Kind: "ReplicationController", Name: "old-rc"}})
+	candidates, err = kctl.PrunePreview(docs, ao)
+	require.NoError(t, err)
+	assert.Equal(t, []kubectl.PruneCandidate{
+		{Kind: "ConfigMap", Namespace: "test", Name: "server-side"},
+		{Kind: "Namespace", Name: "old-namespace"},
+		{Kind: "ReplicationController", Namespace: "test", Name: "old-rc", Protected: true},
+	}, candidates)
+}
+
+func TestPrunePreviewCustomResources(t *testing.T) {
+	f := k8stest.NewFakeFactoryForRC(t, filenameRC)
+	defer f.Cleanup()
+	f.FakeDynamicClient = fakedynamic.NewSimpleDynamicClient(scheme.Scheme,
+		deployedObject("metal3.io/v1alpha1", "BareMetalHost", "node01", "metal3", "hosts", false),
+		deployedObject("metal3.io/v1alpha1", "BareMetalHost", "node02", "metal3", "hosts", false),
+	)
+
+	kctl := kubectl.NewKubectl(f)
+	ao, err := kctl.ApplyOptions()
+	require.NoError(t, err)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "metal3.io", Version: "v1alpha1", Kind: "BareMetalHost"},
+		meta.RESTScopeNamespace)
+	ao.ApplyOptions.Mapper = mapper
+	ao.SetDeploymentID("hosts")
+	ao.SetPrune(ao.DeploymentSelector())
+
+	docs, err := testutil.NewTestBundle(t, "testdata/prune").GetAllDocuments()
+	require.NoError(t, err)
+
+	// The kinds of the applied documents are pruned, BareMetalHosts are
+	// protected by default
+	candidates, err := kctl.PrunePreview(docs, ao)
+	require.NoError(t, err)
+	assert.Equal(t, []kubectl.PruneCandidate{
+		{Group: "metal3.io", Kind: "BareMetalHost", Namespace: "metal3", Name: "node01", Protected: true},
+	}, candidates)
+
+	ao.SetPruneProtections(nil)
+	candidates, err = kctl.PrunePreview(docs, ao)
+	require.NoError(t, err)
+	assert.Equal(t, []kubectl.PruneCandidate{
+		{Group: "metal3.io", Kind: "BareMetalHost", Namespace: "metal3", Name: "node01"},
+	}, candidates)
+}
+
+func TestApplyOptionsRunPrune(t *testing.T) {
+	workflowObject := func(kind, name, namespace string) runtime.Object {
+		obj := deployedObject("v1", kind, name, namespace, "", true).(*unstructured.Unstructured)
+		obj.SetLabels(map[string]string{"airshipit.org/initinfra": "workflow"})
+		return obj
+	}
+
+	manualObject := workflowObject("ConfigMap", "manual", "test").(*unstructured.Unstructured)
+	manualObject.SetAnnotations(nil)
+
+	f := k8stest.NewFakeFactoryForRC(t, filenameRC)
+	defer f.Cleanup()
+	f.FakeDynamicClient = fakedynamic.NewSimpleDynamicClient(scheme.Scheme,
+		workflowObject("ReplicationController", "test-rc", "test"),
+		workflowObject("ReplicationController", "old-rc", "test"),
+		workflowObject("Namespace", "old-namespace", ""),
+		// Not applied as part of a deployment
+		manualObject,
+	)
+
+	ao, err := kubectl.NewApplyOptions(f, testStreams)
+	require.NoError(t, err)
+	ao.SetDryRun(true)
+	ao.SetPrune("airshipit.org/initinfra=workflow")
+	ao.SetSourceFiles([]string{filenameRC})
+
+	result, err := ao.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 3)
+	assert.Equal(t, "test-rc", result.Resources[0].Name)
+	assert.Equal(t, kubectl.ActionProtected, result.Resources[1].Action)
+	assert.Equal(t, "old-namespace", result.Resources[1].Name)
+	assert.Equal(t, kubectl.ActionPruned, result.Resources[2].Action)
+	assert.Equal(t, "old-rc", result.Resources[2].Name)
+	assert.True(t, ao.ApplyOptions.Prune, "pruning is restored after the run")
+}
+
+func TestParsePruneProtection(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected kubectl.PruneProtection
+	}{
+		{value: "Namespace", expected: kubectl.PruneProtection{Kind: "Namespace"}},
+		{
+			value:    "BareMetalHost.metal3.io",
+			expected: kubectl.PruneProtection{Group: "metal3.io", Kind: "BareMetalHost"},
+		},
+		{value: "Secret/creds", expected: kubectl.PruneProtection{Kind: "Secret", Name: "creds"}},
+		{
+			value:    "BareMetalHost.metal3.io/metal3/node01",
+			expected: kubectl.PruneProtection{Group: "metal3.io", Kind: "BareMetalHost", Namespace: "metal3", Name: "node01"},
+		},
+	}
+	for _, tt := range tests {
+		p, err := kubectl.ParsePruneProtection(tt.value)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, p)
+		assert.Equal(t, tt.value, p.String())
+	}
+
+	for _, value := range []string{"", "Secret/", "a/b/c/d"} {
+		_, err := kubectl.ParsePruneProtection(value)
+		assert.Equal(t, kubectl.ErrInvalidPruneProtection{Value: value}, err)
+	}
+}
+
+func TestValidateDeploymentID(t *testing.T) {
+	assert.NoError(t, kubectl.ValidateDeploymentID("initinfra"))
+
+	err := kubectl.ValidateDeploymentID("not/valid")
+	assert.IsType(t, kubectl.ErrInvalidDeploymentID{}, err)
+}
@@ -0,0 +1,202 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Actions kubectl apply takes on a resource
+const (
+	ActionCreated           = "created"
+	ActionConfigured        = "configured"
+	ActionUnchanged         = "unchanged"
+	ActionServerSideApplied = "serverside-applied"
+	ActionPruned            = "pruned"
+	// ActionProtected is reported for resources that would have been
+	// pruned but match a PruneProtection
+	ActionProtected = "protected"
+)
+
+// Formats an ApplyResult can be printed in
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// ResourceResult is the action taken on a single resource by an apply
+type ResourceResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	// Duration is the time elapsed since the previous resource was
+	// handled, or since the start of the apply for the first one
+	Duration time.Duration `json:"duration"`
+}
+
+// ApplyResult reports what an apply did to every resource, in the order
+// they were handled
+type ApplyResult struct {
+	Resources []ResourceResult `json:"resources"`
+	// DryRun is true if the changes were only simulated
+	DryRun bool `json:"dryRun,omitempty"`
+	// Warnings are the warnings reported by kubectl and the API server
+	Warnings []string      `json:"warnings,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Merge adds the resources and warnings of other to the result. A resource
+// already in the result keeps its action if other found it unchanged, so
+// that applying documents again doesn't hide what the first apply did.
+func (r *ApplyResult) Merge(other *ApplyResult) {
+	if other == nil {
+		return
+	}
+	for _, res := range other.Resources {
+		i := r.find(res)
+		switch {
+		case i < 0:
+			r.Resources = append(r.Resources, res)
+		case res.Action != ActionUnchanged:
+			r.Resources[i].Action = res.Action
+		}
+	}
+	r.DryRun = r.DryRun || other.DryRun
+	r.Warnings = append(r.Warnings, other.Warnings...)
+	r.Duration += other.Duration
+}
+
+func (r *ApplyResult) find(res ResourceResult) int {
+	for i, existing := range r.Resources {
+		if existing.Kind == res.Kind && existing.Namespace == res.Namespace && existing.Name == res.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Print writes the result to w in the given format, see OutputTable and
+// OutputJSON
+func (r *ApplyResult) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return r.printTable(w)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(r)
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (r *ApplyResult) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tACTION\tDURATION")
+	for _, res := range r.Resources {
+		action := res.Action
+		if r.DryRun {
+			action += " (dry run)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			res.Kind, res.Namespace, res.Name, action, res.Duration.Round(time.Millisecond))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
+// resultRecorder collects the ApplyResult of a kubectl apply run. It
+// replaces the printer kubectl reports every handled resource to, and
+// intercepts the warnings kubectl writes to its error stream.
+type resultRecorder struct {
+	result *ApplyResult
+	last   time.Time
+	errOut io.Writer
+
+	// applied, namespaces and kinds hold the resources that were applied,
+	// the namespaces they were applied to and their kinds, they are used to
+	// find what to prune
+	applied    sets.String
+	namespaces sets.String
+	kinds      []schema.GroupVersionKind
+}
+
+func newResultRecorder(errOut io.Writer) *resultRecorder {
+	return &resultRecorder{
+		result:     &ApplyResult{},
+		last:       time.Now(),
+		errOut:     errOut,
+		applied:    sets.NewString(),
+		namespaces: sets.NewString(),
+	}
+}
+
+// toPrinter matches the ToPrinter function of kubectl apply options
+func (rec *resultRecorder) toPrinter(action string) (printers.ResourcePrinter, error) {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, _ io.Writer) error {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if action != ActionPruned && action != ActionProtected {
+			rec.applied.Insert(pruneKey(gvk.Group, gvk.Kind, accessor.GetNamespace(), accessor.GetName()))
+			if accessor.GetNamespace() != "" {
+				rec.namespaces.Insert(accessor.GetNamespace())
+			}
+			rec.kinds = append(rec.kinds, gvk)
+		}
+		now := time.Now()
+		rec.result.Resources = append(rec.result.Resources, ResourceResult{
+			Kind:      gvk.Kind,
+			Namespace: accessor.GetNamespace(),
+			Name:      accessor.GetName(),
+			Action:    action,
+			Duration:  now.Sub(rec.last),
+		})
+		rec.last = now
+		return nil
+	}), nil
+}
+
+// Write records writes starting with "Warning:" as warnings and passes
+// everything else to the original error stream. kubectl writes every
+// warning with a single call.
+func (rec *resultRecorder) Write(p []byte) (int, error) {
+	msg := string(p)
+	if !strings.HasPrefix(msg, "Warning:") {
+		return rec.errOut.Write(p)
+	}
+	rec.result.Warnings = append(rec.result.Warnings, strings.TrimSpace(strings.TrimPrefix(msg, "Warning:")))
+	return len(p), nil
+}
@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+)
+
+func TestApplyResultMerge(t *testing.T) {
+	result := &kubectl.ApplyResult{
+		Resources: []kubectl.ResourceResult{
+			{Kind: "ConfigMap", Namespace: "default", Name: "created", Action: kubectl.ActionCreated},
+			{Kind: "ConfigMap", Namespace: "default", Name: "changed", Action: kubectl.ActionUnchanged},
+		},
+		Duration: time.Second,
+	}
+	result.Merge(&kubectl.ApplyResult{
+		Resources: []kubectl.ResourceResult{
+			{Kind: "ConfigMap", Namespace: "default", Name: "created", Action: kubectl.ActionUnchanged},
+			{Kind: "ConfigMap", Namespace: "default", Name: "changed", Action: kubectl.ActionConfigured},
+			{Kind: "ConfigMap", Namespace: "default", Name: "old", Action: kubectl.ActionPruned},
+		},
+		Warnings: []string{"deprecated"},
+		DryRun:   true,
+		Duration: time.Second,
+	})
+	result.Merge(nil)
+
+	assert.Equal(t, &kubectl.ApplyResult{
+		Resources: []kubectl.ResourceResult{
+			{Kind: "ConfigMap", Namespace: "default", Name: "created", Action: kubectl.ActionCreated},
+			{Kind: "ConfigMap", Namespace: "default", Name: "changed", Action: kubectl.ActionConfigured},
+			{Kind: "ConfigMap", Namespace: "default", Name: "old", Action: kubectl.ActionPruned},
+		},
+		Warnings: []string{"deprecated"},
+		DryRun:   true,
+		Duration: 2 * time.Second,
+	}, result)
+}
+
+func TestApplyResultPrint(t *testing.T) {
+	result := &kubectl.ApplyResult{
+		Resources: []kubectl.ResourceResult{
+			{Kind: "Namespace", Name: "test", Action: kubectl.ActionCreated, Duration: 1500 * time.Microsecond},
+			{Kind: "ConfigMap", Namespace: "test", Name: "cm", Action: kubectl.ActionUnchanged},
+		},
+		Warnings: []string{"deprecated"},
+		Duration: 2 * time.Millisecond,
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, result.Print(buf, kubectl.OutputTable))
+	assert.Equal(t, `KIND        NAMESPACE   NAME   ACTION      DURATION
+Namespace               test   created     2ms
+ConfigMap   test        cm     unchanged   0s
+Warning: deprecated
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, result.Print(buf, kubectl.OutputJSON))
+	assert.JSONEq(t, `{
+		"resources": [
+			{"kind": "Namespace", "name": "test", "action": "created", "duration": 1500000},
+			{"kind": "ConfigMap", "namespace": "test", "name": "cm", "action": "unchanged", "duration": 0}
+		],
+		"warnings": ["deprecated"],
+		"duration": 2000000
+	}`, buf.String())
+
+	assert.Equal(t, kubectl.ErrInvalidOutputFormat{Format: "yaml"}, result.Print(buf, "yaml"))
+}
@@ -0,0 +1,80 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl
+
+import (
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+	openapivalidation "k8s.io/kubectl/pkg/util/openapi/validation"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// DocumentValidation lists the problems found in a single document
+type DocumentValidation struct {
+	// Document is the kind and name of the document, e.g. ConfigMap/foo
+	Document string
+	Errors   []string
+}
+
+// Validate checks docs against the OpenAPI schema published by the cluster,
+// so that mistakes are found before anything is applied. Unknown fields,
+// type mismatches and missing required fields are reported grouped by
+// document with ErrSchemaValidation. Documents of kinds the cluster doesn't
+// know yet, e.g. custom resources of CRDs in the same bundle, are skipped.
+func (kubectl *Kubectl) Validate(docs []document.Document) error {
+	resources, err := kubectl.OpenAPISchema()
+	if err != nil {
+		return err
+	}
+	validator := openapivalidation.NewSchemaValidation(resources)
+
+	result := ErrSchemaValidation{}
+	for _, doc := range docs {
+		data, err := doc.AsYAML()
+		if err != nil {
+			return err
+		}
+		if err = validator.ValidateBytes(data); err != nil {
+			result.Documents = append(result.Documents, DocumentValidation{
+				Document: doc.GetKind() + "/" + doc.GetName(),
+				Errors:   validationMessages(err),
+			})
+		}
+	}
+
+	if len(result.Documents) > 0 {
+		return result
+	}
+	return nil
+}
+
+// validationMessages flattens the errors of the schema validation. The
+// path is already part of the wrapped errors, so the wrapping is dropped.
+func validationMessages(err error) []string {
+	errs := []error{err}
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		errs = agg.Errors()
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if ve, ok := e.(validation.ValidationError); ok {
+			e = ve.Err
+		}
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
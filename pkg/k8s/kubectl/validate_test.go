@@ -0,0 +1,59 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubectl_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	openapitesting "k8s.io/kubectl/pkg/util/openapi/testing"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/testutil"
+	k8stest "opendev.org/airship/airshipctl/testutil/k8sutils"
+)
+
+func TestValidate(t *testing.T) {
+	b := testutil.NewTestBundle(t, "testdata/validate")
+	docs, err := b.GetAllDocuments()
+	require.NoError(t, err)
+
+	f := k8stest.NewMockKubectlFactory().
+		WithOpenAPISchemaByError(openapitesting.NewFakeResources("testdata/validate/swagger.json"), nil)
+	err = kubectl.NewKubectl(f).Validate(docs)
+	assert.Equal(t, kubectl.ErrSchemaValidation{
+		Documents: []kubectl.DocumentValidation{
+			{
+				Document: "ConfigMap/invalid",
+				Errors: []string{
+					`invalid type for io.k8s.api.core.v1.ConfigMap.binaryData: got "string", expected "map"`,
+					`unknown field "dat" in io.k8s.api.core.v1.ConfigMap`,
+				},
+			},
+			{
+				Document: "Service/missing-ports",
+				Errors: []string{
+					`missing required field "port" in io.k8s.api.core.v1.ServicePort`,
+				},
+			},
+		},
+	}, err)
+
+	schemaErr := errors.New("failed to download schema")
+	f = k8stest.NewMockKubectlFactory().WithOpenAPISchemaByError(nil, schemaErr)
+	assert.Equal(t, schemaErr, kubectl.NewKubectl(f).Validate(docs))
+}
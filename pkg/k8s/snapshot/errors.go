@@ -0,0 +1,39 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrSnapshotNotFound is returned when there is no snapshot to restore
+type ErrSnapshotNotFound struct {
+	Path string
+}
+
+func (e ErrSnapshotNotFound) Error() string {
+	return fmt.Sprintf("no snapshot found at %s, the phase was not applied yet", e.Path)
+}
+
+// ErrRestoreFailed is returned when resources of a snapshot couldn't be
+// restored
+type ErrRestoreFailed struct {
+	Resources []string
+}
+
+func (e ErrRestoreFailed) Error() string {
+	return fmt.Sprintf("failed to restore %s", strings.Join(e.Resources, ", "))
+}
@@ -0,0 +1,239 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package snapshot records the state of resources before they are applied,
+// so that a failed apply can be rolled back
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Resource identifies a resource of the cluster
+type Resource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r Resource) String() string {
+	if r.Namespace == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Name + " in namespace " + r.Namespace
+}
+
+// Snapshot is the state of resources before an apply. CustomResourceDefinitions
+// are never part of a snapshot, rolling them back would delete or alter all
+// of their custom resources.
+type Snapshot struct {
+	// Objects are the resources which existed, as they were
+	Objects []map[string]interface{} `json:"objects,omitempty"`
+	// Created are the resources which didn't exist
+	Created []Resource `json:"created,omitempty"`
+}
+
+// serverFields are set by the API server and can't be restored
+var serverFields = [][]string{
+	{"metadata", "creationTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "selfLink"},
+	{"metadata", "uid"},
+	{"status"},
+}
+
+// Take records the live state of the resources of docs. Namespaced
+// documents without a namespace are looked up in defaultNamespace.
+func Take(client dynamic.Interface, mapper meta.RESTMapper, docs []document.Document,
+	defaultNamespace string) (*Snapshot, error) {
+	s := &Snapshot{}
+	for _, doc := range docs {
+		ref := Resource{
+			APIVersion: schema.GroupVersion{Group: doc.GetGroup(), Version: doc.GetVersion()}.String(),
+			Kind:       doc.GetKind(),
+			Namespace:  doc.GetNamespace(),
+			Name:       doc.GetName(),
+		}
+		if isCRD(ref) {
+			continue
+		}
+
+		resource, err := resourceClient(client, mapper, &ref, defaultNamespace)
+		if meta.IsNoMatchError(err) {
+			// Custom resources of CRDs that are not established yet
+			log.Debugf("Not recording %s, its kind is unknown to the cluster", ref)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		live, err := resource.Get(ref.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			s.Created = append(s.Created, ref)
+		case err != nil:
+			return nil, err
+		default:
+			for _, field := range serverFields {
+				unstructured.RemoveNestedField(live.Object, field...)
+			}
+			s.Objects = append(s.Objects, live.Object)
+		}
+	}
+	return s, nil
+}
+
+// Restore puts the resources back in the recorded state: resources created
+// since are deleted, the others are reverted to their recorded content and
+// recreated if they were deleted. Every resource is tried, the ones that
+// couldn't be restored are reported with ErrRestoreFailed.
+func (s *Snapshot) Restore(client dynamic.Interface, mapper meta.RESTMapper) error {
+	restoreErr := ErrRestoreFailed{}
+	for i := len(s.Created) - 1; i >= 0; i-- {
+		ref := s.Created[i]
+		if err := deleteResource(client, mapper, ref); err != nil {
+			log.Printf("Failed to delete %s: %v", ref, err)
+			restoreErr.Resources = append(restoreErr.Resources, ref.String())
+			continue
+		}
+		log.Printf("%s deleted", ref)
+	}
+
+	for _, obj := range s.Objects {
+		u := &unstructured.Unstructured{Object: obj}
+		ref := Resource{APIVersion: u.GetAPIVersion(), Kind: u.GetKind(), Namespace: u.GetNamespace(), Name: u.GetName()}
+		if err := restoreObject(client, mapper, ref, u); err != nil {
+			log.Printf("Failed to restore %s: %v", ref, err)
+			restoreErr.Resources = append(restoreErr.Resources, ref.String())
+			continue
+		}
+		log.Printf("%s restored", ref)
+	}
+
+	if len(restoreErr.Resources) > 0 {
+		return restoreErr
+	}
+	return nil
+}
+
+func deleteResource(client dynamic.Interface, mapper meta.RESTMapper, ref Resource) error {
+	resource, err := resourceClient(client, mapper, &ref, "")
+	if err != nil {
+		return err
+	}
+	propagation := metav1.DeletePropagationBackground
+	err = resource.Delete(ref.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func restoreObject(client dynamic.Interface, mapper meta.RESTMapper, ref Resource, obj *unstructured.Unstructured) error {
+	resource, err := resourceClient(client, mapper, &ref, "")
+	if err != nil {
+		return err
+	}
+
+	live, err := resource.Get(ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = resource.Create(obj.DeepCopy(), metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	restored := obj.DeepCopy()
+	restored.SetResourceVersion(live.GetResourceVersion())
+	_, err = resource.Update(restored, metav1.UpdateOptions{})
+	return err
+}
+
+// resourceClient returns the client of the resource ref. The namespace of
+// ref is set to defaultNamespace if its kind is namespaced and it has none.
+func resourceClient(client dynamic.Interface, mapper meta.RESTMapper,
+	ref *Resource, defaultNamespace string) (dynamic.ResourceInterface, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := client.Resource(mapping.Resource)
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return resource, nil
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = defaultNamespace
+	}
+	return resource.Namespace(ref.Namespace), nil
+}
+
+func isCRD(ref Resource) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	return err == nil && gv.Group == "apiextensions.k8s.io" && ref.Kind == "CustomResourceDefinition"
+}
+
+// Load reads a snapshot saved with Save
+func Load(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrSnapshotNotFound{Path: path}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{}
+	if err = yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the snapshot to path. Snapshots may contain secrets, so the
+// file is only readable by its owner.
+func (s *Snapshot) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,110 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package snapshot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/snapshot"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+var configMaps = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func testMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+		meta.RESTScopeRoot)
+	return mapper
+}
+
+func configMap(namespace, name, value string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"key": value},
+	}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestSnapshot(t *testing.T) {
+	docs, err := testutil.NewTestBundle(t, "testdata").GetAllDocuments()
+	require.NoError(t, err)
+
+	existing := configMap("default", "existing", "old")
+	existing.SetUID("1234")
+	existing.SetResourceVersion("1")
+	client := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+
+	s, err := snapshot.Take(client, testMapper(), docs, "default")
+	require.NoError(t, err)
+	// Server fields are dropped, the CRD and the unknown Widget are skipped
+	assert.Equal(t, &snapshot.Snapshot{
+		Objects: []map[string]interface{}{configMap("default", "existing", "old").Object},
+		Created: []snapshot.Resource{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test", Name: "created"}},
+	}, s)
+
+	testDir, cleanup := testutil.TempDir(t, "snapshot")
+	defer cleanup(t)
+	path := filepath.Join(testDir, "snapshots", "initinfra.yaml")
+	require.NoError(t, s.Save(path))
+	loaded, err := snapshot.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	_, err = snapshot.Load(filepath.Join(testDir, "missing.yaml"))
+	assert.Equal(t, snapshot.ErrSnapshotNotFound{Path: filepath.Join(testDir, "missing.yaml")}, err)
+
+	// Simulate the apply
+	_, err = client.Resource(configMaps).Namespace("default").
+		Update(configMap("default", "existing", "new"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = client.Resource(configMaps).Namespace("test").
+		Create(configMap("test", "created", "value"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, loaded.Restore(client, testMapper()))
+	restored, err := client.Resource(configMaps).Namespace("default").Get("existing", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "old", restored.Object["data"].(map[string]interface{})["key"])
+	_, err = client.Resource(configMaps).Namespace("test").Get("created", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Restoring again recreates deleted resources and ignores missing ones
+	require.NoError(t, client.Resource(configMaps).Namespace("default").Delete("existing", nil))
+	require.NoError(t, loaded.Restore(client, testMapper()))
+	_, err = client.Resource(configMaps).Namespace("default").Get("existing", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	err = loaded.Restore(client, meta.NewDefaultRESTMapper(nil))
+	assert.Equal(t, snapshot.ErrRestoreFailed{Resources: []string{
+		"ConfigMap/created in namespace test",
+		"ConfigMap/existing in namespace default",
+	}}, err)
+}
@@ -0,0 +1,70 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrResourceFailed is returned when a waited resource reports a failure
+type ErrResourceFailed struct {
+	Resource string
+	Message  string
+}
+
+func (e ErrResourceFailed) Error() string {
+	return fmt.Sprintf("%s has failed: %s", e.Resource, e.Message)
+}
+
+// ErrWaitTimeout is returned when resources don't become Current in time
+type ErrWaitTimeout struct {
+	Resources []string
+}
+
+func (e ErrWaitTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for %s to become %s", strings.Join(e.Resources, ", "), Current)
+}
+
+// ErrInvalidWaitTimeout is returned when the WaitTimeoutAnnotation of a
+// document is not a duration
+type ErrInvalidWaitTimeout struct {
+	Document string
+	Value    string
+}
+
+func (e ErrInvalidWaitTimeout) Error() string {
+	return fmt.Sprintf("invalid %s annotation %q of document %s", WaitTimeoutAnnotation, e.Value, e.Document)
+}
+
+// ErrInvalidKindTimeout is returned when a wait timeout of a kind is not
+// given as Kind[.group]=duration
+type ErrInvalidKindTimeout struct {
+	Value string
+}
+
+func (e ErrInvalidKindTimeout) Error() string {
+	return fmt.Sprintf("invalid wait timeout %q, must be Kind[.group]=duration", e.Value)
+}
+
+// ErrKindsNotServed is returned when the API server doesn't serve kinds in
+// time, e.g. because their CustomResourceDefinitions are not Established
+type ErrKindsNotServed struct {
+	Kinds []string
+}
+
+func (e ErrKindsNotServed) Error() string {
+	return fmt.Sprintf("timed out waiting for the API server to serve %s", strings.Join(e.Kinds, ", "))
+}
@@ -0,0 +1,71 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// WaitForKinds blocks until the API server serves all kinds, e.g. the ones
+// defined by CustomResourceDefinitions which just became Established. The
+// discovery information of the server lags behind the CRDs, so resources of
+// these kinds can't be mapped, applied or waited for before. Once all kinds
+// are served the Mapper of the waiter is replaced by one knowing them.
+func (w *Waiter) WaitForKinds(dc discovery.DiscoveryInterface, kinds []schema.GroupKind) error {
+	deadline := time.Now().Add(w.Timeout)
+	for {
+		mapper, missing, err := discoverKinds(dc, kinds)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			w.Mapper = mapper
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrKindsNotServed{Kinds: missing}
+		}
+		log.Debugf("Waiting for kinds %v to be served", missing)
+		time.Sleep(w.PollInterval)
+	}
+}
+
+// discoverKinds returns a mapper built from the current discovery
+// information and the kinds it can't map yet
+func discoverKinds(dc discovery.DiscoveryInterface, kinds []schema.GroupKind) (meta.RESTMapper, []string, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(dc)
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, nil, err
+	}
+
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	var missing []string
+	for _, gk := range kinds {
+		if _, err = mapper.RESTMapping(gk); err != nil {
+			if !meta.IsNoMatchError(err) {
+				return nil, nil, err
+			}
+			missing = append(missing, gk.String())
+		}
+	}
+	return mapper, missing, nil
+}
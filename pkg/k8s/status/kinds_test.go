@@ -0,0 +1,59 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+func TestWaitForKinds(t *testing.T) {
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{
+		Resources: []*metav1.APIResourceList{
+			{
+				GroupVersion: "metal3.io/v1alpha1",
+				APIResources: []metav1.APIResource{
+					{Name: "baremetalhosts", Kind: "BareMetalHost", Namespaced: true},
+				},
+			},
+		},
+	}}
+	bmh := schema.GroupKind{Group: "metal3.io", Kind: "BareMetalHost"}
+	cluster := schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "Cluster"}
+
+	waiter := status.NewWaiter(fakedynamic.NewSimpleDynamicClient(runtime.NewScheme()), testMapper(), 0)
+	waiter.PollInterval = time.Millisecond
+
+	require.NoError(t, waiter.WaitForKinds(dc, []schema.GroupKind{bmh}))
+	// The mapper is refreshed with the newly served kinds
+	mapping, err := waiter.Mapper.RESTMapping(bmh)
+	require.NoError(t, err)
+	assert.Equal(t, "baremetalhosts", mapping.Resource.Resource)
+
+	waiter.Timeout = 10 * time.Millisecond
+	err = waiter.WaitForKinds(dc, []schema.GroupKind{bmh, cluster})
+	assert.Equal(t, status.ErrKindsNotServed{Kinds: []string{"Cluster.cluster.x-k8s.io"}}, err)
+}
@@ -0,0 +1,298 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package status computes whether kubernetes resources have been fully
+// reconciled, following the semantics of the kstatus library used by
+// kustomize and kpt, and waits for them to get there.
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Status is the reconciliation state of a resource
+type Status string
+
+// Statuses a resource can have
+const (
+	// Current means the resource is fully reconciled and ready
+	Current Status = "Current"
+	// InProgress means the resource is still being reconciled
+	InProgress Status = "InProgress"
+	// Failed means the reconciliation of the resource failed
+	Failed Status = "Failed"
+	// Terminating means the resource is being deleted
+	Terminating Status = "Terminating"
+	// NotFound means the resource doesn't exist in the cluster
+	NotFound Status = "NotFound"
+)
+
+const (
+	// ReadyConditionAnnotation overrides how readiness of a resource is
+	// computed. Its value is the type of a status condition, optionally
+	// followed by "=" and the expected status of the condition which
+	// defaults to "True". The resource is Current once the condition matches.
+	ReadyConditionAnnotation = document.BaseAirshipSelector + "/ready-condition"
+
+	// WaitTimeoutAnnotation overrides how long to wait for a resource to
+	// become Current, its value is a duration such as "10m"
+	WaitTimeoutAnnotation = document.BaseAirshipSelector + "/wait-timeout"
+)
+
+// Result is the computed status of a resource with a human readable
+// explanation
+type Result struct {
+	Status  Status
+	Message string
+}
+
+// condition is the subset of fields status conditions have in common
+type condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// Compute returns the status of the given resource. Resources without
+// kind-specific readiness rules are Current unless their Ready condition is
+// present and not True.
+func Compute(obj *unstructured.Unstructured) (Result, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return Result{Status: Terminating, Message: "resource is being deleted"}, nil
+	}
+
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return Result{}, err
+	}
+	if found && observed < obj.GetGeneration() {
+		return Result{
+			Status:  InProgress,
+			Message: fmt.Sprintf("generation %d is not observed yet", obj.GetGeneration()),
+		}, nil
+	}
+
+	conditions, err := getConditions(obj)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if override, ok := obj.GetAnnotations()[ReadyConditionAnnotation]; ok {
+		return conditionOverrideStatus(override, conditions), nil
+	}
+
+	if c, ok := conditions["Stalled"]; ok && c.Status == string(metav1.ConditionTrue) {
+		return Result{Status: Failed, Message: c.Message}, nil
+	}
+	if c, ok := conditions["Reconciling"]; ok && c.Status == string(metav1.ConditionTrue) {
+		return Result{Status: InProgress, Message: c.Message}, nil
+	}
+
+	gk := obj.GroupVersionKind().GroupKind()
+	if rule, ok := kindRules[gk]; ok {
+		return rule(obj, conditions)
+	}
+
+	if c, ok := conditions["Ready"]; ok && c.Status != string(metav1.ConditionTrue) {
+		return Result{Status: InProgress, Message: c.Message}, nil
+	}
+	return Result{Status: Current, Message: "resource is current"}, nil
+}
+
+func conditionOverrideStatus(override string, conditions map[string]condition) Result {
+	condType, expected := override, string(metav1.ConditionTrue)
+	if i := strings.Index(override, "="); i >= 0 {
+		condType, expected = override[:i], override[i+1:]
+	}
+
+	c, ok := conditions[condType]
+	switch {
+	case !ok:
+		return Result{Status: InProgress, Message: fmt.Sprintf("condition %s is not reported yet", condType)}
+	case c.Status != expected:
+		return Result{
+			Status:  InProgress,
+			Message: fmt.Sprintf("condition %s is %s, waiting for %s", condType, c.Status, expected),
+		}
+	default:
+		return Result{Status: Current, Message: fmt.Sprintf("condition %s is %s", condType, c.Status)}
+	}
+}
+
+func getConditions(obj *unstructured.Unstructured) (map[string]condition, error) {
+	list, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make(map[string]condition, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := condition{}
+		c.Type, _, _ = unstructured.NestedString(fields, "type")
+		c.Status, _, _ = unstructured.NestedString(fields, "status")
+		c.Reason, _, _ = unstructured.NestedString(fields, "reason")
+		c.Message, _, _ = unstructured.NestedString(fields, "message")
+		conditions[c.Type] = c
+	}
+	return conditions, nil
+}
+
+type statusRule func(obj *unstructured.Unstructured, conditions map[string]condition) (Result, error)
+
+var kindRules = map[schema.GroupKind]statusRule{
+	{Group: "apps", Kind: "Deployment"}:                               deploymentStatus,
+	{Group: "apps", Kind: "StatefulSet"}:                              statefulSetStatus,
+	{Group: "apps", Kind: "DaemonSet"}:                                daemonSetStatus,
+	{Group: "apps", Kind: "ReplicaSet"}:                               replicaSetStatus,
+	{Kind: "ReplicationController"}:                                   replicaSetStatus,
+	{Kind: "Pod"}:                                                     podStatus,
+	{Kind: "PersistentVolumeClaim"}:                                   pvcStatus,
+	{Kind: "Service"}:                                                 serviceStatus,
+	{Group: "batch", Kind: "Job"}:                                     jobStatus,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: crdStatus,
+}
+
+// replicaCounts reads the desired number of replicas from spec and the
+// given counters from status, missing fields are treated as 0
+func replicaCounts(obj *unstructured.Unstructured, desiredPath []string, fields ...string) (int64, []int64) {
+	desired, found, _ := unstructured.NestedInt64(obj.Object, desiredPath...)
+	if !found && desiredPath[0] == "spec" {
+		desired = 1
+	}
+	counts := make([]int64, len(fields))
+	for i, field := range fields {
+		counts[i], _, _ = unstructured.NestedInt64(obj.Object, "status", field)
+	}
+	return desired, counts
+}
+
+func replicasResult(desired int64, counts []int64, fields []string) Result {
+	for i, count := range counts {
+		if count < desired {
+			return Result{
+				Status:  InProgress,
+				Message: fmt.Sprintf("%s: %d/%d", fields[i], count, desired),
+			}
+		}
+	}
+	return Result{Status: Current, Message: fmt.Sprintf("replicas: %d/%d", desired, desired)}
+}
+
+func deploymentStatus(obj *unstructured.Unstructured, conditions map[string]condition) (Result, error) {
+	if c, ok := conditions["Progressing"]; ok && c.Reason == "ProgressDeadlineExceeded" {
+		return Result{Status: Failed, Message: c.Message}, nil
+	}
+	fields := []string{"updatedReplicas", "readyReplicas", "availableReplicas"}
+	desired, counts := replicaCounts(obj, []string{"spec", "replicas"}, fields...)
+	if replicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas"); replicas > desired {
+		return Result{
+			Status:  InProgress,
+			Message: fmt.Sprintf("%d old replicas are pending termination", replicas-desired),
+		}, nil
+	}
+	return replicasResult(desired, counts, fields), nil
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured, _ map[string]condition) (Result, error) {
+	fields := []string{"readyReplicas", "currentReplicas"}
+	desired, counts := replicaCounts(obj, []string{"spec", "replicas"}, fields...)
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if current != update {
+		return Result{
+			Status:  InProgress,
+			Message: fmt.Sprintf("waiting for revision %s to roll out", update),
+		}, nil
+	}
+	return replicasResult(desired, counts, fields), nil
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured, _ map[string]condition) (Result, error) {
+	fields := []string{"updatedNumberScheduled", "numberAvailable", "numberReady"}
+	desired, counts := replicaCounts(obj, []string{"status", "desiredNumberScheduled"}, fields...)
+	return replicasResult(desired, counts, fields), nil
+}
+
+func replicaSetStatus(obj *unstructured.Unstructured, _ map[string]condition) (Result, error) {
+	fields := []string{"readyReplicas", "availableReplicas"}
+	desired, counts := replicaCounts(obj, []string{"spec", "replicas"}, fields...)
+	return replicasResult(desired, counts, fields), nil
+}
+
+func podStatus(obj *unstructured.Unstructured, conditions map[string]condition) (Result, error) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return Result{Status: Current, Message: "pod has completed"}, nil
+	case "Failed":
+		return Result{Status: Failed, Message: "pod has failed"}, nil
+	case "Running":
+		if c, ok := conditions["Ready"]; ok && c.Status == string(metav1.ConditionTrue) {
+			return Result{Status: Current, Message: "pod is ready"}, nil
+		}
+	}
+	return Result{Status: InProgress, Message: "pod is not ready yet"}, nil
+}
+
+func pvcStatus(obj *unstructured.Unstructured, _ map[string]condition) (Result, error) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase != "Bound" {
+		return Result{Status: InProgress, Message: "claim is not bound yet"}, nil
+	}
+	return Result{Status: Current, Message: "claim is bound"}, nil
+}
+
+func serviceStatus(obj *unstructured.Unstructured, _ map[string]condition) (Result, error) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType != "LoadBalancer" {
+		return Result{Status: Current, Message: "service is ready"}, nil
+	}
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	if len(ingress) == 0 {
+		return Result{Status: InProgress, Message: "load balancer is not provisioned yet"}, nil
+	}
+	return Result{Status: Current, Message: "load balancer is provisioned"}, nil
+}
+
+func jobStatus(_ *unstructured.Unstructured, conditions map[string]condition) (Result, error) {
+	if c, ok := conditions["Failed"]; ok && c.Status == string(metav1.ConditionTrue) {
+		return Result{Status: Failed, Message: c.Message}, nil
+	}
+	if c, ok := conditions["Complete"]; ok && c.Status == string(metav1.ConditionTrue) {
+		return Result{Status: Current, Message: "job has completed"}, nil
+	}
+	return Result{Status: InProgress, Message: "job is not complete yet"}, nil
+}
+
+func crdStatus(_ *unstructured.Unstructured, conditions map[string]condition) (Result, error) {
+	if c, ok := conditions["NamesAccepted"]; ok && c.Status == string(metav1.ConditionFalse) {
+		return Result{Status: Failed, Message: c.Message}, nil
+	}
+	if c, ok := conditions["Established"]; ok && c.Status == string(metav1.ConditionTrue) {
+		return Result{Status: Current, Message: "definition is established"}, nil
+	}
+	return Result{Status: InProgress, Message: "definition is not established yet"}, nil
+}
@@ -0,0 +1,172 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+func newObject(apiVersion, kind string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	return obj
+}
+
+func conditions(conds ...map[string]interface{}) map[string]interface{} {
+	list := make([]interface{}, len(conds))
+	for i, c := range conds {
+		list[i] = c
+	}
+	return map[string]interface{}{"conditions": list}
+}
+
+func TestCompute(t *testing.T) {
+	deleted := newObject("v1", "ConfigMap", map[string]interface{}{})
+	now := metav1.Now()
+	deleted.SetDeletionTimestamp(&now)
+
+	outdated := newObject("apps/v1", "Deployment", map[string]interface{}{
+		"status": map[string]interface{}{"observedGeneration": int64(1)},
+	})
+	outdated.SetGeneration(2)
+
+	overridden := newObject("example.com/v1", "Machine", map[string]interface{}{
+		"status": conditions(
+			map[string]interface{}{"type": "Ready", "status": "False"},
+			map[string]interface{}{"type": "Provisioned", "status": "True"},
+		),
+	})
+	overridden.SetAnnotations(map[string]string{status.ReadyConditionAnnotation: "Provisioned"})
+
+	overriddenStatus := newObject("example.com/v1", "Machine", map[string]interface{}{
+		"status": conditions(map[string]interface{}{"type": "Paused", "status": "True"}),
+	})
+	overriddenStatus.SetAnnotations(map[string]string{status.ReadyConditionAnnotation: "Paused=False"})
+
+	tests := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected status.Status
+	}{
+		{
+			name:     "terminating",
+			obj:      deleted,
+			expected: status.Terminating,
+		},
+		{
+			name:     "generation-not-observed",
+			obj:      outdated,
+			expected: status.InProgress,
+		},
+		{
+			name: "deployment-rolling-out",
+			obj: newObject("apps/v1", "Deployment", map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"updatedReplicas": int64(3), "readyReplicas": int64(1)},
+			}),
+			expected: status.InProgress,
+		},
+		{
+			name: "deployment-ready",
+			obj: newObject("apps/v1", "Deployment", map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"replicas":          int64(2),
+					"updatedReplicas":   int64(2),
+					"readyReplicas":     int64(2),
+					"availableReplicas": int64(2),
+				},
+			}),
+			expected: status.Current,
+		},
+		{
+			name: "deployment-deadline-exceeded",
+			obj: newObject("apps/v1", "Deployment", map[string]interface{}{
+				"status": conditions(map[string]interface{}{
+					"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded",
+				}),
+			}),
+			expected: status.Failed,
+		},
+		{
+			name: "pod-running-not-ready",
+			obj: newObject("v1", "Pod", map[string]interface{}{
+				"status": map[string]interface{}{"phase": "Running"},
+			}),
+			expected: status.InProgress,
+		},
+		{
+			name: "job-failed",
+			obj: newObject("batch/v1", "Job", map[string]interface{}{
+				"status": conditions(map[string]interface{}{"type": "Failed", "status": "True"}),
+			}),
+			expected: status.Failed,
+		},
+		{
+			name: "crd-established",
+			obj: newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", map[string]interface{}{
+				"status": conditions(map[string]interface{}{"type": "Established", "status": "True"}),
+			}),
+			expected: status.Current,
+		},
+		{
+			name: "generic-not-ready",
+			obj: newObject("example.com/v1", "Machine", map[string]interface{}{
+				"status": conditions(map[string]interface{}{"type": "Ready", "status": "False"}),
+			}),
+			expected: status.InProgress,
+		},
+		{
+			name: "generic-stalled",
+			obj: newObject("example.com/v1", "Machine", map[string]interface{}{
+				"status": conditions(map[string]interface{}{"type": "Stalled", "status": "True"}),
+			}),
+			expected: status.Failed,
+		},
+		{
+			name:     "generic-without-status",
+			obj:      newObject("v1", "ConfigMap", map[string]interface{}{}),
+			expected: status.Current,
+		},
+		{
+			name:     "condition-override",
+			obj:      overridden,
+			expected: status.Current,
+		},
+		{
+			name:     "condition-override-with-status",
+			obj:      overriddenStatus,
+			expected: status.InProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := status.Compute(tt.obj)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Status, result.Message)
+		})
+	}
+}
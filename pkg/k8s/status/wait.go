@@ -0,0 +1,213 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+// DefaultPollInterval is how often the status of resources is checked
+const DefaultPollInterval = 2 * time.Second
+
+// Waiter waits for resources to become Current
+type Waiter struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// Timeout is how long to wait for each resource, unless overridden with
+	// the WaitTimeoutAnnotation or KindTimeouts
+	Timeout time.Duration
+	// KindTimeouts overrides Timeout for all resources of a kind, the
+	// WaitTimeoutAnnotation of a resource takes precedence over it
+	KindTimeouts map[schema.GroupKind]time.Duration
+	PollInterval time.Duration
+	// Out receives the table with the status of all resources, it is
+	// printed again every time a status changes
+	Out io.Writer
+	// Retry configures retries of API calls failing with transient errors
+	Retry client.RetryOptions
+}
+
+// NewWaiter returns a Waiter printing the status table to stdout
+func NewWaiter(dynamicClient dynamic.Interface, mapper meta.RESTMapper, timeout time.Duration) *Waiter {
+	return &Waiter{
+		Client:       dynamicClient,
+		Mapper:       mapper,
+		Timeout:      timeout,
+		PollInterval: DefaultPollInterval,
+		Out:          os.Stdout,
+		Retry:        client.DefaultRetryOptions(),
+	}
+}
+
+type waitedResource struct {
+	kind      string
+	namespace string
+	name      string
+	resource  dynamic.ResourceInterface
+	deadline  time.Time
+	Result
+}
+
+func (r *waitedResource) String() string {
+	if r.namespace == "" {
+		return fmt.Sprintf("%s/%s", r.kind, r.name)
+	}
+	return fmt.Sprintf("%s/%s in namespace %s", r.kind, r.name, r.namespace)
+}
+
+// Wait blocks until all resources described by docs are Current. Resources
+// without a namespace are looked up in defaultNamespace if they are
+// namespaced. An error is returned as soon as a resource Failed, or once the
+// timeout of every resource that isn't Current has expired.
+func (w *Waiter) Wait(docs []document.Document, defaultNamespace string) error {
+	resources, err := w.resources(docs, defaultNamespace)
+	if err != nil {
+		return err
+	}
+
+	for {
+		changed := false
+		pending := 0
+		var timedOut []string
+		for _, r := range resources {
+			if r.Status == Current {
+				continue
+			}
+			result, err := w.status(r)
+			if err != nil {
+				return err
+			}
+			if result != r.Result {
+				r.Result = result
+				changed = true
+			}
+			switch {
+			case r.Status == Current:
+			case r.Status == Failed:
+				w.printTable(resources)
+				return ErrResourceFailed{Resource: r.String(), Message: r.Message}
+			case time.Now().After(r.deadline):
+				timedOut = append(timedOut, r.String())
+			default:
+				pending++
+			}
+		}
+		if changed {
+			w.printTable(resources)
+		}
+
+		if pending == 0 {
+			if len(timedOut) > 0 {
+				return ErrWaitTimeout{Resources: timedOut}
+			}
+			return nil
+		}
+		time.Sleep(w.PollInterval)
+	}
+}
+
+func (w *Waiter) resources(docs []document.Document, defaultNamespace string) ([]*waitedResource, error) {
+	start := time.Now()
+	resources := make([]*waitedResource, 0, len(docs))
+	for _, doc := range docs {
+		gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+		mapping, err := w.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		timeout, ok := w.KindTimeouts[gvk.GroupKind()]
+		if !ok {
+			timeout = w.Timeout
+		}
+		if value, ok := doc.GetAnnotations()[WaitTimeoutAnnotation]; ok {
+			if timeout, err = time.ParseDuration(value); err != nil {
+				return nil, ErrInvalidWaitTimeout{Document: doc.GetName(), Value: value}
+			}
+		}
+
+		r := &waitedResource{
+			kind:     gvk.Kind,
+			name:     doc.GetName(),
+			deadline: start.Add(timeout),
+			Result:   Result{Status: InProgress},
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			r.namespace = doc.GetNamespace()
+			if r.namespace == "" {
+				r.namespace = defaultNamespace
+			}
+			r.resource = w.Client.Resource(mapping.Resource).Namespace(r.namespace)
+		} else {
+			r.resource = w.Client.Resource(mapping.Resource)
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+func (w *Waiter) status(r *waitedResource) (Result, error) {
+	var obj *unstructured.Unstructured
+	err := client.Retry(w.Retry, "Get of "+r.String(), func() (getErr error) {
+		obj, getErr = r.resource.Get(r.name, metav1.GetOptions{})
+		return getErr
+	})
+	if apierrors.IsNotFound(err) {
+		return Result{Status: NotFound, Message: "resource doesn't exist yet"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Compute(obj)
+}
+
+func (w *Waiter) printTable(resources []*waitedResource) {
+	tw := tabwriter.NewWriter(w.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tSTATUS\tMESSAGE")
+	for _, r := range resources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.kind, r.namespace, r.name, r.Status, r.Message)
+	}
+	fmt.Fprintln(tw)
+	tw.Flush()
+}
+
+// ParseKindTimeout parses a wait timeout of a kind given as
+// Kind[.group]=duration, e.g. BareMetalHost.metal3.io=60m
+func ParseKindTimeout(value string) (schema.GroupKind, time.Duration, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return schema.GroupKind{}, 0, ErrInvalidKindTimeout{Value: value}
+	}
+	timeout, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return schema.GroupKind{}, 0, ErrInvalidKindTimeout{Value: value}
+	}
+	return schema.ParseGroupKind(parts[0]), timeout, nil
+}
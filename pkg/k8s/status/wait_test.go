@@ -0,0 +1,126 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func testMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+func deployment(name string, ready int64) *unstructured.Unstructured {
+	obj := newObject("apps/v1", "Deployment", map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(1)},
+		"status": map[string]interface{}{
+			"updatedReplicas":   ready,
+			"readyReplicas":     ready,
+			"availableReplicas": ready,
+		},
+	})
+	obj.SetName(name)
+	obj.SetNamespace("test")
+	return obj
+}
+
+func TestWait(t *testing.T) {
+	docs, err := testutil.NewTestBundle(t, "testdata").GetAllDocuments()
+	require.NoError(t, err)
+
+	namespace := newObject("v1", "Namespace", map[string]interface{}{})
+
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		kindTimeouts map[schema.GroupKind]time.Duration
+		expectedErr  error
+	}{
+		{
+			name:    "all-current",
+			objects: []runtime.Object{deployment("ready", 1), deployment("slow", 1), namespace},
+		},
+		{
+			name:        "per-resource-timeout",
+			objects:     []runtime.Object{deployment("ready", 1), deployment("slow", 0), namespace},
+			expectedErr: status.ErrWaitTimeout{Resources: []string{"Deployment/slow in namespace test"}},
+		},
+		{
+			name:         "per-kind-timeout",
+			objects:      []runtime.Object{deployment("ready", 0), deployment("slow", 1), namespace},
+			kindTimeouts: map[schema.GroupKind]time.Duration{{Group: "apps", Kind: "Deployment"}: 0},
+			expectedErr:  status.ErrWaitTimeout{Resources: []string{"Deployment/ready in namespace test"}},
+		},
+		{
+			name:         "per-resource-timeout-overrides-kind",
+			objects:      []runtime.Object{deployment("ready", 1), deployment("slow", 0), namespace},
+			kindTimeouts: map[schema.GroupKind]time.Duration{{Group: "apps", Kind: "Deployment"}: time.Hour},
+			expectedErr:  status.ErrWaitTimeout{Resources: []string{"Deployment/slow in namespace test"}},
+		},
+		{
+			name:        "not-found",
+			objects:     []runtime.Object{deployment("ready", 1), namespace},
+			expectedErr: status.ErrWaitTimeout{Resources: []string{"Deployment/slow in namespace test"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			waiter := status.NewWaiter(fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), tt.objects...),
+				testMapper(), time.Minute)
+			waiter.PollInterval = time.Millisecond
+			waiter.Out = out
+			waiter.KindTimeouts = tt.kindTimeouts
+
+			assert.Equal(t, tt.expectedErr, waiter.Wait(docs, "test"))
+			assert.Contains(t, out.String(), "KIND")
+		})
+	}
+}
+
+func TestParseKindTimeout(t *testing.T) {
+	gk, timeout, err := status.ParseKindTimeout("BareMetalHost.metal3.io=60m")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Group: "metal3.io", Kind: "BareMetalHost"}, gk)
+	assert.Equal(t, time.Hour, timeout)
+
+	gk, timeout, err = status.ParseKindTimeout("Job=10m")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Kind: "Job"}, gk)
+	assert.Equal(t, 10*time.Minute, timeout)
+
+	for _, value := range []string{"Job", "=10m", "Job=later"} {
+		_, _, err = status.ParseKindTimeout(value)
+		assert.Equal(t, status.ErrInvalidKindTimeout{Value: value}, err)
+	}
+}
@@ -15,14 +15,68 @@
 package utils
 
 import (
+	"time"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// RESTOptions tunes the clients of a cluster, zero values keep the
+// defaults of client-go
+type RESTOptions struct {
+	// QPS is the maximum number of queries per second sent to the cluster
+	QPS float32
+	// Burst is the number of queries allowed above QPS for short periods
+	Burst int
+	// Timeout of every request, zero means no timeout
+	Timeout time.Duration
+	// Context is the kubeconfig context to connect to, the current context
+	// of the kubeconfig is used if it is empty
+	Context string
+}
+
+// Apply sets the non-zero options on config
+func (o RESTOptions) Apply(config *rest.Config) {
+	if o.QPS > 0 {
+		config.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		config.Burst = o.Burst
+	}
+	if o.Timeout > 0 {
+		config.Timeout = o.Timeout
+	}
+}
+
+// restOptionsGetter applies RESTOptions to the REST configs of ConfigFlags
+type restOptionsGetter struct {
+	*genericclioptions.ConfigFlags
+	opts RESTOptions
+}
+
+func (g restOptionsGetter) ToRESTConfig() (*rest.Config, error) {
+	config, err := g.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	g.opts.Apply(config)
+	return config, nil
+}
+
 // FactoryFromKubeConfigPath returns a factory with the
 // default Kubernetes resources for the given kube config path
 func FactoryFromKubeConfigPath(kp string) cmdutil.Factory {
+	return FactoryWithRESTOptions(kp, RESTOptions{})
+}
+
+// FactoryWithRESTOptions returns a factory for the given kube config path
+// whose clients are tuned with opts
+func FactoryWithRESTOptions(kp string, opts RESTOptions) cmdutil.Factory {
 	kf := genericclioptions.NewConfigFlags(false)
 	kf.KubeConfig = &kp
-	return cmdutil.NewFactory(kf)
+	if opts.Context != "" {
+		kf.Context = &opts.Context
+	}
+	return cmdutil.NewFactory(restOptionsGetter{ConfigFlags: kf, opts: opts})
 }
@@ -15,9 +15,23 @@
 package apply
 
 import (
+	"context"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/events"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/snapshot"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/phase/rollback"
 )
 
 // Options is an abstraction used to apply the phase
@@ -25,56 +39,382 @@ type Options struct {
 	RootSettings *environment.AirshipCTLSettings
 	Client       client.Interface
 
-	DryRun    bool
-	Prune     bool
-	PhaseName string
+	DryRun         bool
+	ServerDryRun   bool
+	Prune          bool
+	ServerSide     bool
+	FieldManager   string
+	ForceConflicts bool
+	Validate       bool
+	Wait           bool
+	WaitTimeout    time.Duration
+	// KindWaitTimeouts overrides WaitTimeout for all resources of a kind
+	KindWaitTimeouts map[schema.GroupKind]time.Duration
+	// Retries is how many times API calls failing with transient errors,
+	// e.g. conflicts or throttling, are retried
+	Retries int
+	// MaxParallel is how many independent documents are applied at the
+	// same time
+	MaxParallel int
+	PhaseName   string
+	// EntryPoint is the path of the kustomization of the documents to apply,
+	// the entry point of PhaseName in the current context if empty
+	EntryPoint string
+	// ProgressOut receives the status of resources while waiting for them,
+	// stdout is used if it is nil
+	ProgressOut io.Writer
+	// RollbackOnFailure restores the resources of the phase to their state
+	// before the apply if it fails
+	RollbackOnFailure bool
+	// SnapshotDir is where the state of the resources is saved before they
+	// are applied, rollback.DefaultSnapshotDir if empty
+	SnapshotDir string
+	// PruneProtections are resources, in addition to
+	// kubectl.DefaultPruneProtections, that are never pruned. See
+	// kubectl.ParsePruneProtection for their format.
+	PruneProtections []string
+	// CreateNamespaces creates the namespaces documents are put in, if they
+	// neither exist in the cluster nor are part of the documents
+	CreateNamespaces bool
 }
 
 // NewOptions return instance of Options
 func NewOptions(settings *environment.AirshipCTLSettings) *Options {
 	// At this point AirshipCTLSettings may not be fully initialized
-	applyOptions := &Options{RootSettings: settings}
+	applyOptions := &Options{RootSettings: settings, MaxParallel: 1}
 	return applyOptions
 }
 
-// Run apply subcommand logic
-func (applyOptions *Options) Run() error {
+// Run apply subcommand logic, it returns what was done to every resource of
+// the phase. If the apply fails part way, the result covers the resources
+// handled until then.
+func (applyOptions *Options) Run() (*kubectl.ApplyResult, error) {
+	// Resources of a phase are identified by the phase name, so that the ones
+	// removed from the documents can be pruned by the next apply
+	if err := kubectl.ValidateDeploymentID(applyOptions.PhaseName); err != nil {
+		return nil, err
+	}
+	if applyOptions.MaxParallel < 1 {
+		return nil, ErrInvalidMaxParallel{MaxParallel: applyOptions.MaxParallel}
+	}
+
 	kctl := applyOptions.Client.Kubectl()
-	ao, err := kctl.ApplyOptions()
+	ao, err := applyOptions.kubectlApplyOptions(kctl, applyOptions.Prune)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	ao.SetDryRun(applyOptions.DryRun)
-	// If prune is true, set selector for pruning
-	if applyOptions.Prune {
-		ao.SetPrune(document.ApplyPhaseSelector + applyOptions.PhaseName)
+	kustomizePath := applyOptions.EntryPoint
+	if kustomizePath == "" {
+		if kustomizePath, err = applyOptions.RootSettings.CurrentContextEntryPoint(applyOptions.PhaseName); err != nil {
+			return nil, err
+		}
 	}
 
-	globalConf := applyOptions.RootSettings.Config
+	b, err := document.NewBundleByPath(kustomizePath)
+	if err != nil {
+		return nil, err
+	}
 
-	if err = globalConf.EnsureComplete(); err != nil {
-		return err
+	// Returns all documents for this phase
+	docs, err := b.Select(document.NewDeployToK8sSelector())
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, document.ErrDocNotFound{}
 	}
 
-	kustomizePath, err := globalConf.CurrentContextEntryPoint(applyOptions.PhaseName)
+	graph, err := document.NewDependencyGraph(docs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	b, err := document.NewBundleByPath(kustomizePath)
+	if applyOptions.Validate {
+		if err = kctl.Validate(docs); err != nil {
+			return nil, err
+		}
+	}
+
+	candidates, err := kctl.PrunePreview(docs, ao)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, c := range candidates {
+		if c.Protected {
+			log.Printf("%s is protected from pruning", c)
+			continue
+		}
+		log.Printf("%s will be pruned", c)
 	}
 
-	// Returns all documents for this phase
-	docs, err := b.Select(document.NewDeployToK8sSelector())
+	namespace := ao.ApplyOptions.Namespace
+	dryRun := applyOptions.DryRun || applyOptions.ServerDryRun
+	if !dryRun {
+		streamer := events.NewStreamer(applyOptions.Client.ClientSet())
+		for _, doc := range docs {
+			docNamespace := doc.GetNamespace()
+			if docNamespace == "" {
+				docNamespace = namespace
+			}
+			streamer.AddResource(docNamespace, doc.GetName())
+		}
+		// Events help diagnosing failures but are not required to deploy
+		if err = streamer.Start(); err != nil {
+			log.Debugf("Failed to stream events: %v", err)
+		} else {
+			defer streamer.Stop()
+		}
+	}
+
+	var created []kubectl.ResourceResult
+	if applyOptions.CreateNamespaces {
+		if created, err = applyOptions.createNamespaces(docs); err != nil {
+			return &kubectl.ApplyResult{Resources: created, DryRun: dryRun}, err
+		}
+	}
+
+	var snap *snapshot.Snapshot
+	if !dryRun {
+		if snap, err = applyOptions.saveSnapshot(docs, ao.ApplyOptions.Mapper, namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := applyOptions.deploy(kctl, docs, graph, ao.ApplyOptions.Mapper, namespace)
+	result.Resources = append(created, result.Resources...)
+	if err != nil && snap != nil && applyOptions.RollbackOnFailure {
+		log.Printf("Failed to apply phase %s, rolling back", applyOptions.PhaseName)
+		if rollbackErr := snap.Restore(applyOptions.Client.DynamicClient(), ao.ApplyOptions.Mapper); rollbackErr != nil {
+			log.Printf("Failed to roll back phase %s: %v", applyOptions.PhaseName, rollbackErr)
+		}
+	}
+	return result, err
+}
+
+// saveSnapshot records the state of the resources of docs before they are
+// applied, so that the phase can be rolled back
+func (applyOptions *Options) saveSnapshot(docs []document.Document, mapper meta.RESTMapper,
+	namespace string) (*snapshot.Snapshot, error) {
+	snap, err := snapshot.Take(applyOptions.Client.DynamicClient(), mapper, docs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := applyOptions.SnapshotDir
+	if dir == "" {
+		dir = rollback.DefaultSnapshotDir(applyOptions.RootSettings)
+	}
+	return snap, snap.Save(rollback.SnapshotPath(dir, applyOptions.PhaseName))
+}
+
+// deploy applies docs in dependency order and waits for them if requested
+func (applyOptions *Options) deploy(kctl kubectl.Interface, docs []document.Document, graph *document.DependencyGraph,
+	mapper meta.RESTMapper, namespace string) (*kubectl.ApplyResult, error) {
+	dryRun := applyOptions.DryRun || applyOptions.ServerDryRun
+	waiter := status.NewWaiter(applyOptions.Client.DynamicClient(), mapper, applyOptions.WaitTimeout)
+	waiter.KindTimeouts = applyOptions.KindWaitTimeouts
+	waiter.Retry = applyOptions.retryOptions()
+	if applyOptions.ProgressOut != nil {
+		waiter.Out = applyOptions.ProgressOut
+	}
+
+	var err error
+	result := &kubectl.ApplyResult{}
+	if len(graph.Waves) > 1 || applyOptions.MaxParallel > 1 {
+		if err = applyOptions.applyInOrder(kctl, graph, waiter, namespace, result); err != nil {
+			return result, err
+		}
+		if applyOptions.Prune {
+			// Documents applied one by one can't be used to find the
+			// resources to prune, so all of them are applied once more
+			err = applyOptions.apply(kctl, docs, true, result)
+		}
+	} else {
+		err = applyOptions.apply(kctl, docs, applyOptions.Prune, result)
+	}
+	if err != nil || !applyOptions.Wait || dryRun {
+		return result, err
+	}
+
+	return result, waiter.Wait(docs, namespace)
+}
+
+// kubectlApplyOptions returns kubectl apply options configured for the
+// phase, pruning is only enabled if prune is true
+func (applyOptions *Options) kubectlApplyOptions(kctl kubectl.Interface, prune bool) (*kubectl.ApplyOptions, error) {
+	ao, err := kctl.ApplyOptions()
 	if err != nil {
+		return nil, err
+	}
+
+	ao.SetDeploymentID(applyOptions.PhaseName)
+	ao.SetDryRun(applyOptions.DryRun)
+	ao.SetServerDryRun(applyOptions.ServerDryRun)
+	ao.SetServerSide(applyOptions.ServerSide, applyOptions.FieldManager, applyOptions.ForceConflicts)
+	if prune {
+		ao.SetPrune(ao.DeploymentSelector())
+	}
+
+	protections := append([]kubectl.PruneProtection{}, kubectl.DefaultPruneProtections...)
+	for _, value := range applyOptions.PruneProtections {
+		p, err := kubectl.ParsePruneProtection(value)
+		if err != nil {
+			return nil, err
+		}
+		protections = append(protections, p)
+	}
+	ao.SetPruneProtections(protections)
+	return ao, nil
+}
+
+// retryOptions returns the default retry options with the number of
+// retries requested for the phase
+func (applyOptions *Options) retryOptions() client.RetryOptions {
+	opts := client.DefaultRetryOptions()
+	opts.Retries = applyOptions.Retries
+	return opts
+}
+
+// apply applies docs, retrying on transient errors, and merges what was
+// done into result. kubectl apply options can't be reused once run, so
+// every attempt gets new ones.
+func (applyOptions *Options) apply(kctl kubectl.Interface, docs []document.Document, prune bool,
+	result *kubectl.ApplyResult) error {
+	operation := "Apply of phase " + applyOptions.PhaseName
+	if len(docs) == 1 {
+		operation = "Apply of " + documentName(docs[0])
+	}
+	return client.Retry(applyOptions.retryOptions(), operation, func() error {
+		ao, err := applyOptions.kubectlApplyOptions(kctl, prune)
+		if err != nil {
+			return err
+		}
+		attempt, err := kctl.Apply(docs, ao)
+		result.Merge(attempt)
 		return err
+	})
+}
+
+// applyInOrder applies the documents of graph one by one, wave by wave. Up
+// to MaxParallel documents of a wave are applied at the same time. Unless
+// in dry run, documents which others depend on have to become Current
+// before the next wave is applied, and the kinds defined by
+// CustomResourceDefinitions have to be served. Documents depending on a document that
+// failed are skipped, all others are still applied.
+func (applyOptions *Options) applyInOrder(kctl kubectl.Interface, graph *document.DependencyGraph,
+	waiter *status.Waiter, namespace string, result *kubectl.ApplyResult) error {
+	dryRun := applyOptions.DryRun || applyOptions.ServerDryRun
+	hasDependents := map[document.Document]bool{}
+	for _, wave := range graph.Waves {
+		for _, doc := range wave {
+			for _, dep := range graph.Dependencies(doc) {
+				hasDependents[dep] = true
+			}
+		}
 	}
-	if len(docs) == 0 {
-		return document.ErrDocNotFound{}
+
+	failed := map[document.Document]bool{}
+	applyErr := ErrOrderedApplyFailed{}
+	for _, wave := range graph.Waves {
+		var pending []document.Document
+		for _, doc := range wave {
+			if dependsOnFailed(graph, doc, failed) {
+				log.Printf("Skipping %s, a document it depends on has failed", documentName(doc))
+				failed[doc] = true
+				applyErr.Skipped = append(applyErr.Skipped, documentName(doc))
+				continue
+			}
+			pending = append(pending, doc)
+		}
+
+		// Documents of the same wave don't depend on each other, so they
+		// can be applied concurrently. Results are merged afterwards to
+		// keep them in document order.
+		results := make([]*kubectl.ApplyResult, len(pending))
+		errs := make([]error, len(pending))
+		workqueue.ParallelizeUntil(context.Background(), applyOptions.MaxParallel, len(pending), func(i int) {
+			results[i] = &kubectl.ApplyResult{}
+			errs[i] = applyOptions.apply(kctl, pending[i:i+1], false, results[i])
+		})
+
+		var applied []document.Document
+		for i, doc := range pending {
+			result.Merge(results[i])
+			if errs[i] != nil {
+				log.Printf("Failed to apply %s: %v", documentName(doc), errs[i])
+				failed[doc] = true
+				applyErr.Failed = append(applyErr.Failed, documentName(doc))
+				continue
+			}
+			if hasDependents[doc] && !dryRun {
+				applied = append(applied, doc)
+			}
+		}
+
+		for _, doc := range applied {
+			if err := waiter.Wait([]document.Document{doc}, namespace); err != nil {
+				log.Printf("Failed to wait for %s: %v", documentName(doc), err)
+				failed[doc] = true
+				applyErr.Failed = append(applyErr.Failed, documentName(doc))
+			}
+		}
+
+		// Resources of the kinds defined by CRDs of this wave can only be
+		// applied once the API server serves them, which happens some time
+		// after the CRDs are Established
+		crds, kinds := definedKinds(applied, failed)
+		if len(kinds) == 0 {
+			continue
+		}
+		if err := waiter.WaitForKinds(applyOptions.Client.ClientSet().Discovery(), kinds); err != nil {
+			log.Printf("Failed to wait for custom resource kinds: %v", err)
+			for _, doc := range crds {
+				failed[doc] = true
+				applyErr.Failed = append(applyErr.Failed, documentName(doc))
+			}
+		}
 	}
 
-	return kctl.Apply(docs, ao)
+	if len(applyErr.Failed) > 0 {
+		return applyErr
+	}
+	return nil
+}
+
+func dependsOnFailed(graph *document.DependencyGraph, doc document.Document, failed map[document.Document]bool) bool {
+	for _, dep := range graph.Dependencies(doc) {
+		if failed[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// definedKinds returns the CustomResourceDefinitions of docs which haven't
+// failed and the kinds they define
+func definedKinds(docs []document.Document, failed map[document.Document]bool) ([]document.Document,
+	[]schema.GroupKind) {
+	var crds []document.Document
+	var kinds []schema.GroupKind
+	for _, doc := range docs {
+		if failed[doc] || doc.GetGroup() != "apiextensions.k8s.io" || doc.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		group, err := doc.GetString("spec.group")
+		if err != nil {
+			continue
+		}
+		kind, err := doc.GetString("spec.names.kind")
+		if err != nil {
+			continue
+		}
+		crds = append(crds, doc)
+		kinds = append(kinds, schema.GroupKind{Group: group, Kind: kind})
+	}
+	return crds, kinds
+}
+
+func documentName(doc document.Document) string {
+	return doc.GetKind() + "/" + doc.GetName()
 }
@@ -16,17 +16,30 @@ package apply_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubectlapply "k8s.io/kubectl/pkg/cmd/apply"
 
+	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/snapshot"
 	"opendev.org/airship/airshipctl/pkg/phase/apply"
+	"opendev.org/airship/airshipctl/pkg/phase/rollback"
+	"opendev.org/airship/airshipctl/testutil"
 	"opendev.org/airship/airshipctl/testutil/k8sutils"
 )
 
@@ -38,6 +51,7 @@ const (
 
 var (
 	ErrDynamicClientError = errors.New("ErrDynamicClientError")
+	ErrApplyError         = errors.New("ErrApplyError")
 )
 
 func TestDeploy(t *testing.T) {
@@ -80,11 +94,192 @@ func TestDeploy(t *testing.T) {
 	for _, test := range tests {
 		ao.Prune = test.prune
 		ao.Client = test.client
-		actualErr := ao.Run()
+		_, actualErr := ao.Run()
 		assert.Equal(t, test.expectedError, actualErr)
 	}
 }
 
+// orderedKubectl records the documents it applies and fails to apply the
+// document named failName. The first conflicts applies fail with a conflict.
+type orderedKubectl struct {
+	mu        sync.Mutex
+	applied   []string
+	failName  string
+	conflicts int
+}
+
+func (k *orderedKubectl) Apply(docs []document.Document, _ *kubectl.ApplyOptions) (*kubectl.ApplyResult, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.conflicts > 0 {
+		k.conflicts--
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, docs[0].GetName(), ErrApplyError)
+	}
+	result := &kubectl.ApplyResult{}
+	for _, doc := range docs {
+		k.applied = append(k.applied, doc.GetName())
+		if doc.GetName() == k.failName {
+			return result, ErrApplyError
+		}
+		result.Resources = append(result.Resources, kubectl.ResourceResult{
+			Kind:   doc.GetKind(),
+			Name:   doc.GetName(),
+			Action: kubectl.ActionCreated,
+		})
+	}
+	return result, nil
+}
+
+func (k *orderedKubectl) ApplyOptions() (*kubectl.ApplyOptions, error) {
+	o := kubectlapply.NewApplyOptions(genericclioptions.NewTestIOStreamsDiscard())
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	o.Mapper = mapper
+	o.Namespace = "default"
+	return &kubectl.ApplyOptions{ApplyOptions: o}, nil
+}
+
+func (k *orderedKubectl) Validate([]document.Document) error {
+	return nil
+}
+
+func (k *orderedKubectl) PrunePreview([]document.Document, *kubectl.ApplyOptions) ([]kubectl.PruneCandidate, error) {
+	return nil, nil
+}
+
+func TestDeployInDependencyOrder(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+
+	ao := apply.NewOptions(rs)
+	ao.PhaseName = "ordered"
+	ao.DryRun = true
+
+	kctl := &orderedKubectl{}
+	ao.Client = fake.NewClient(fake.WithKubectl(kctl))
+	result, err := ao.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base", "independent", "dependent"}, kctl.applied)
+	assert.Len(t, result.Resources, 3)
+
+	kctl = &orderedKubectl{failName: "base"}
+	ao.Client = fake.NewClient(fake.WithKubectl(kctl))
+	result, err = ao.Run()
+	assert.Equal(t, apply.ErrOrderedApplyFailed{
+		Failed:  []string{"ConfigMap/base"},
+		Skipped: []string{"ConfigMap/dependent"},
+	}, err)
+	assert.Equal(t, []string{"base", "independent"}, kctl.applied)
+	assert.Equal(t, []kubectl.ResourceResult{
+		{Kind: "ConfigMap", Name: "independent", Action: kubectl.ActionCreated},
+	}, result.Resources)
+}
+
+func TestDeployRetriesConflicts(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+
+	ao := apply.NewOptions(rs)
+	ao.PhaseName = "ordered"
+	ao.DryRun = true
+	ao.Retries = 1
+
+	kctl := &orderedKubectl{conflicts: 1}
+	ao.Client = fake.NewClient(fake.WithKubectl(kctl))
+	_, err := ao.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base", "independent", "dependent"}, kctl.applied)
+
+	kctl = &orderedKubectl{conflicts: 2}
+	ao.Client = fake.NewClient(fake.WithKubectl(kctl))
+	_, err = ao.Run()
+	assert.Equal(t, apply.ErrOrderedApplyFailed{
+		Failed:  []string{"ConfigMap/base"},
+		Skipped: []string{"ConfigMap/dependent"},
+	}, err)
+	assert.Equal(t, []string{"independent"}, kctl.applied)
+}
+
+func TestDeployInParallel(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+
+	ao := apply.NewOptions(rs)
+	ao.PhaseName = "ordered"
+	ao.DryRun = true
+	ao.MaxParallel = 3
+
+	kctl := &orderedKubectl{}
+	ao.Client = fake.NewClient(fake.WithKubectl(kctl))
+	result, err := ao.Run()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"base", "independent", "dependent"}, kctl.applied)
+	// Only documents without dependencies can be applied concurrently
+	assert.Equal(t, "dependent", kctl.applied[2])
+	// Results are reported in document order whatever the order of applies
+	assert.Equal(t, []kubectl.ResourceResult{
+		{Kind: "ConfigMap", Name: "base", Action: kubectl.ActionCreated},
+		{Kind: "ConfigMap", Name: "independent", Action: kubectl.ActionCreated},
+		{Kind: "ConfigMap", Name: "dependent", Action: kubectl.ActionCreated},
+	}, result.Resources)
+
+	ao.MaxParallel = 0
+	_, err = ao.Run()
+	assert.Equal(t, apply.ErrInvalidMaxParallel{MaxParallel: 0}, err)
+
+	ao.MaxParallel = 1
+	ao.PruneProtections = []string{"Secret//creds"}
+	_, err = ao.Run()
+	assert.Equal(t, kubectl.ErrInvalidPruneProtection{Value: "Secret//creds"}, err)
+}
+
+func TestDeployCreatesNamespaces(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+
+	ao := apply.NewOptions(rs)
+	ao.PhaseName = "ordered"
+	ao.DryRun = true
+	ao.CreateNamespaces = true
+
+	ao.Client = fake.NewClient(fake.WithKubectl(&orderedKubectl{}))
+	result, err := ao.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 4)
+	assert.Equal(t, kubectl.ResourceResult{Kind: "Namespace", Name: "test", Action: kubectl.ActionCreated},
+		result.Resources[0])
+
+	// Existing namespaces are left alone
+	ao.Client = fake.NewClient(
+		fake.WithKubectl(&orderedKubectl{}),
+		fake.WithTypedObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}))
+	result, err = ao.Run()
+	require.NoError(t, err)
+	assert.Len(t, result.Resources, 3)
+}
+
+func TestDeploySavesSnapshot(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+	testDir, cleanup := testutil.TempDir(t, "apply-snapshot")
+	defer cleanup(t)
+
+	ao := apply.NewOptions(rs)
+	ao.PhaseName = "ordered"
+	ao.SnapshotDir = testDir
+	ao.RollbackOnFailure = true
+	ao.ProgressOut = ioutil.Discard
+
+	// The applied ConfigMaps never show up in the fake cluster, so waiting
+	// for base to be ready fails
+	ao.Client = fake.NewClient(fake.WithKubectl(&orderedKubectl{}))
+	_, err := ao.Run()
+	assert.Equal(t, apply.ErrOrderedApplyFailed{
+		Failed:  []string{"ConfigMap/base"},
+		Skipped: []string{"ConfigMap/dependent"},
+	}, err)
+
+	s, err := snapshot.Load(rollback.SnapshotPath(testDir, "ordered"))
+	require.NoError(t, err)
+	assert.Empty(t, s.Objects)
+	assert.Len(t, s.Created, 3)
+}
+
 // makeNewFakeRootSettings takes kubeconfig path and directory path to fixture dir as argument.
 func makeNewFakeRootSettings(t *testing.T, kp string, dir string) *environment.AirshipCTLSettings {
 	t.Helper()
@@ -0,0 +1,45 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrOrderedApplyFailed is returned when documents applied in dependency
+// order failed
+type ErrOrderedApplyFailed struct {
+	Failed  []string
+	Skipped []string
+}
+
+func (e ErrOrderedApplyFailed) Error() string {
+	msg := fmt.Sprintf("failed to apply %s", strings.Join(e.Failed, ", "))
+	if len(e.Skipped) > 0 {
+		msg += fmt.Sprintf(", skipped dependent documents %s", strings.Join(e.Skipped, ", "))
+	}
+	return msg
+}
+
+// ErrInvalidMaxParallel is returned when the number of documents to apply
+// concurrently isn't positive
+type ErrInvalidMaxParallel struct {
+	MaxParallel int
+}
+
+func (e ErrInvalidMaxParallel) Error() string {
+	return fmt.Sprintf("invalid number of documents to apply in parallel %d, must be at least 1", e.MaxParallel)
+}
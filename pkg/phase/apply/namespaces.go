@@ -0,0 +1,94 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// referencedNamespaces returns the namespaces documents are put in which
+// aren't defined by a Namespace document, sorted by name
+func referencedNamespaces(docs []document.Document) []string {
+	referenced := sets.NewString()
+	defined := sets.NewString()
+	for _, doc := range docs {
+		if doc.GetKind() == "Namespace" && doc.GetGroup() == "" {
+			defined.Insert(doc.GetName())
+			continue
+		}
+		if ns := doc.GetNamespace(); ns != "" {
+			referenced.Insert(ns)
+		}
+	}
+	return referenced.Difference(defined).List()
+}
+
+// createNamespaces creates the namespaces referenced by docs which don't
+// exist in the cluster. They are labeled with the phase like the applied
+// resources, so that they are found when the phase is pruned. The created
+// namespaces are returned as resource results.
+func (applyOptions *Options) createNamespaces(docs []document.Document) ([]kubectl.ResourceResult, error) {
+	dryRun := applyOptions.DryRun || applyOptions.ServerDryRun
+	namespaces := applyOptions.Client.ClientSet().CoreV1().Namespaces()
+
+	var results []kubectl.ResourceResult
+	for _, name := range referencedNamespaces(docs) {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{kubectl.DeploymentLabel: applyOptions.PhaseName},
+			},
+		}
+		var created bool
+		err := client.Retry(applyOptions.retryOptions(), "Creation of namespace "+name, func() error {
+			_, err := namespaces.Get(name, metav1.GetOptions{})
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			if dryRun {
+				created = true
+				return nil
+			}
+			_, err = namespaces.Create(ns)
+			if apierrors.IsAlreadyExists(err) {
+				return nil
+			}
+			created = err == nil
+			return err
+		})
+		if err != nil {
+			return results, err
+		}
+		if !created {
+			continue
+		}
+
+		if dryRun {
+			log.Printf("Namespace %s will be created", name)
+		} else {
+			log.Printf("Namespace %s created", name)
+		}
+		results = append(results, kubectl.ResourceResult{Kind: "Namespace", Name: name, Action: kubectl.ActionCreated})
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Report summarizes an apply of a phase, it is meant to be consumed by CI
+// pipelines to gate and trend deployments
+type Report struct {
+	Phase     string        `json:"phase"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+	DryRun    bool          `json:"dryRun,omitempty"`
+	StartTime time.Time     `json:"startTime"`
+	Duration  time.Duration `json:"duration"`
+	Summary   ReportSummary `json:"summary"`
+	// Resources is the action taken on every resource with its duration
+	Resources []kubectl.ResourceResult `json:"resources"`
+	// Failed and Skipped are the documents which failed to apply and the
+	// ones which weren't applied because a dependency failed
+	Failed   []string `json:"failed,omitempty"`
+	Skipped  []string `json:"skipped,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ReportSummary counts the resources of a Report by outcome
+type ReportSummary struct {
+	Created   int `json:"created"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+	Pruned    int `json:"pruned"`
+	Protected int `json:"protected"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// NewReport builds the report of an apply started at start from the result
+// and error returned by Options.Run, result may be nil
+func NewReport(phase string, start time.Time, result *kubectl.ApplyResult, err error) *Report {
+	r := &Report{
+		Phase:     phase,
+		Success:   err == nil,
+		StartTime: start,
+		Duration:  time.Since(start),
+		Resources: []kubectl.ResourceResult{},
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	var orderedErr ErrOrderedApplyFailed
+	if errors.As(err, &orderedErr) {
+		r.Failed = orderedErr.Failed
+		r.Skipped = orderedErr.Skipped
+	}
+	r.Summary.Failed = len(r.Failed)
+	r.Summary.Skipped = len(r.Skipped)
+
+	if result == nil {
+		return r
+	}
+	r.DryRun = result.DryRun
+	r.Resources = result.Resources
+	r.Warnings = result.Warnings
+	for _, res := range result.Resources {
+		switch res.Action {
+		case kubectl.ActionCreated:
+			r.Summary.Created++
+		case kubectl.ActionConfigured, kubectl.ActionServerSideApplied:
+			r.Summary.Changed++
+		case kubectl.ActionUnchanged:
+			r.Summary.Unchanged++
+		case kubectl.ActionPruned:
+			r.Summary.Pruned++
+		case kubectl.ActionProtected:
+			r.Summary.Protected++
+		}
+	}
+	return r
+}
+
+// Write saves the report as JSON to path, replacing an existing file
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, append(data, '\n'), 0644)
+}
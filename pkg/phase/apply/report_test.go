@@ -0,0 +1,79 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/phase/apply"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestReport(t *testing.T) {
+	start := time.Now()
+	result := &kubectl.ApplyResult{
+		Resources: []kubectl.ResourceResult{
+			{Kind: "Namespace", Name: "test", Action: kubectl.ActionCreated},
+			{Kind: "ConfigMap", Namespace: "test", Name: "base", Action: kubectl.ActionConfigured},
+			{Kind: "ConfigMap", Namespace: "test", Name: "old", Action: kubectl.ActionPruned},
+			{Kind: "Namespace", Name: "legacy", Action: kubectl.ActionProtected},
+		},
+		Warnings: []string{"deprecated"},
+	}
+	applyErr := apply.ErrOrderedApplyFailed{
+		Failed:  []string{"ConfigMap/independent"},
+		Skipped: []string{"ConfigMap/dependent"},
+	}
+
+	report := apply.NewReport("ordered", start, result, applyErr)
+	assert.False(t, report.Success)
+	assert.Equal(t, applyErr.Error(), report.Error)
+	assert.Equal(t, apply.ReportSummary{
+		Created:   1,
+		Changed:   1,
+		Pruned:    1,
+		Protected: 1,
+		Failed:    1,
+		Skipped:   1,
+	}, report.Summary)
+	assert.Equal(t, applyErr.Failed, report.Failed)
+	assert.Equal(t, []string{"deprecated"}, report.Warnings)
+
+	testDir, cleanup := testutil.TempDir(t, "apply-report")
+	defer cleanup(t)
+	reportFile := filepath.Join(testDir, "report.json")
+	require.NoError(t, report.Write(reportFile))
+
+	data, err := ioutil.ReadFile(reportFile)
+	require.NoError(t, err)
+	written := &apply.Report{}
+	require.NoError(t, json.Unmarshal(data, written))
+	assert.Equal(t, report.Summary, written.Summary)
+	assert.Equal(t, report.Resources, written.Resources)
+
+	// Applies failing before anything is applied are reported too
+	report = apply.NewReport("ordered", start, nil, apply.ErrInvalidMaxParallel{})
+	assert.False(t, report.Success)
+	assert.Empty(t, report.Resources)
+	assert.Equal(t, apply.ReportSummary{}, report.Summary)
+}
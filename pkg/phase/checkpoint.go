@@ -0,0 +1,93 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// DefaultCheckpointDir returns the directory the progress of plans is
+// recorded in, next to the airship config
+func DefaultCheckpointDir(settings *environment.AirshipCTLSettings) string {
+	return filepath.Join(filepath.Dir(settings.AirshipConfigPath), "checkpoints")
+}
+
+// CheckpointPath returns the path of the checkpoint of a plan run in a
+// context, in dir
+func CheckpointPath(dir, context, plan string) string {
+	return filepath.Join(dir, context, plan+".yaml")
+}
+
+// Checkpoint records the phases of a plan which completed, so that a run of
+// the plan which failed can be resumed after the last of them
+type Checkpoint struct {
+	Plan    string `json:"plan"`
+	Context string `json:"context"`
+	// Completed are the phases which completed, in the order they were run
+	Completed []CompletedPhase `json:"completed"`
+}
+
+// CompletedPhase is a phase of a plan which completed
+type CompletedPhase struct {
+	Group       string    `json:"group"`
+	Name        string    `json:"name"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// LoadCheckpoint reads the checkpoint at path
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrCheckpointNotFound{Path: path}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Checkpoint{}
+	if err = yaml.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the checkpoint to path
+func (c *Checkpoint) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// IsCompleted tells whether the phase called name of group completed
+func (c *Checkpoint) IsCompleted(group, name string) bool {
+	for _, p := range c.Completed {
+		if p.Group == group && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
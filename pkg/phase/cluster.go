@@ -0,0 +1,171 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// WorkloadCluster is a cluster deployed by Cluster API, reached with the
+// kubeconfig Cluster API generated for it
+type WorkloadCluster struct {
+	// Name of the Cluster object of the cluster
+	Name string `json:"name"`
+	// Namespace of the Cluster object, the default namespace if empty
+	Namespace string `json:"namespace,omitempty"`
+	// ManagementCluster is the context of the cluster holding the Cluster
+	// object, the current context if empty
+	ManagementCluster string `json:"managementCluster,omitempty"`
+}
+
+func (w WorkloadCluster) String() string {
+	return "workload:" + w.namespace() + "/" + w.Name
+}
+
+func (w WorkloadCluster) namespace() string {
+	if w.Namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return w.Namespace
+}
+
+// validateCluster checks that the Phase document names its cluster at most
+// one way
+func (c Config) validateCluster() error {
+	var set []string
+	if c.Cluster != "" {
+		set = append(set, "config.cluster")
+	}
+	if c.ClusterType != "" {
+		set = append(set, "config.clusterType")
+	}
+	if c.WorkloadCluster != nil {
+		set = append(set, "config.workloadCluster")
+	}
+	switch {
+	case len(set) > 1:
+		return ErrConflictingClusterFields{Fields: set}
+	case c.ClusterType != "":
+		if err := config.ValidClusterType(c.ClusterType); err != nil {
+			return ErrInvalidClusterType{Field: "config.clusterType", Err: err}
+		}
+	case c.WorkloadCluster != nil && c.WorkloadCluster.Name == "":
+		return ErrPhaseFieldRequired{Field: "config.workloadCluster.name"}
+	}
+	return nil
+}
+
+// clusterContext returns the context of the cluster p is run against, the
+// context of the settings if p doesn't name one. The context of a
+// clusterType is the context of the cluster of that type named like the
+// cluster of the context of the settings, that context if it matches. The
+// context of a workload cluster is the context of its management cluster.
+func clusterContext(settings *environment.AirshipCTLSettings, p *Phase) (string, error) {
+	current := settings.KubeContext
+	if current == "" {
+		current = settings.Config.CurrentContext
+	}
+
+	name := p.Config.Cluster
+	if p.Config.WorkloadCluster != nil {
+		name = p.Config.WorkloadCluster.ManagementCluster
+	}
+	switch {
+	case name != "":
+		if _, err := settings.Config.GetContext(name); err != nil {
+			return "", err
+		}
+		return name, nil
+	case p.Config.ClusterType != "":
+		return contextOfType(settings.Config, current, p)
+	default:
+		return settings.KubeContext, nil
+	}
+}
+
+// contextOfType returns the context of the cluster of the clusterType of p
+// named like the cluster of the context current
+func contextOfType(airconfig *config.Config, current string, p *Phase) (string, error) {
+	currentContext, err := airconfig.GetContext(current)
+	if err != nil {
+		return "", err
+	}
+	want := config.NewClusterComplexName(
+		airconfig.ContextClusterComplexName(currentContext).Name, p.Config.ClusterType)
+	if airconfig.ContextClusterComplexName(currentContext) == want {
+		return current, nil
+	}
+
+	names := make([]string, 0, len(airconfig.Contexts))
+	for name := range airconfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if airconfig.ContextClusterComplexName(airconfig.Contexts[name]) == want {
+			return name, nil
+		}
+	}
+	return "", ErrClusterContextNotFound{Phase: p.Name, Cluster: want.String()}
+}
+
+// connect points the settings of executor to the workload cluster of its
+// phase, if any, with the kubeconfig Cluster API generated for it. The
+// kubeconfig is read from the management cluster and written to a temporary
+// file, readable by the current user only, which the returned function
+// removes.
+func (e *Engine) connect(executor *phaseExecutor) (func(), error) {
+	workload := executor.phase.Config.WorkloadCluster
+	if workload == nil {
+		return func() {}, nil
+	}
+	settings := executor.config.Settings
+	kclient, err := e.ClientFactory(settings)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, err := cluster.GetKubeconfig(kclient, workload.namespace(), workload.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ioutil.TempFile("", "airshipctl-kubeconfig-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { os.Remove(file.Name()) } //nolint:errcheck
+	_, err = file.Write(kubeconfig)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	// The kubeconfig Cluster API generates has the workload cluster as its
+	// current context
+	settings.KubeConfigPath = file.Name()
+	settings.KubeContext = ""
+	return cleanup, nil
+}
@@ -0,0 +1,199 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+const clusterConfigFile = "testdata/config-cluster.yaml"
+
+// kubeconfigExecutor records the kubeconfig its phase is run with
+type kubeconfigExecutor struct {
+	*fakeExecutor
+	kubeconfig *string
+}
+
+func (e kubeconfigExecutor) Run(ch chan<- ifc.Event) {
+	defer close(ch)
+	data, err := ioutil.ReadFile(e.config.Settings.KubeConfigPath)
+	if err != nil {
+		ch <- ifc.Event{Err: err}
+		return
+	}
+	*e.kubeconfig = string(data)
+}
+
+func TestEngineClusterType(t *testing.T) {
+	tests := []struct {
+		name             string
+		ephemeralContext bool
+		expectedContext  string
+		expectedErr      error
+	}{
+		{
+			name:             "ephemeral-phase",
+			ephemeralContext: true,
+			expectedContext:  "dummy_cluster_ephemeral",
+		},
+		{
+			name:             "target-phase",
+			ephemeralContext: true,
+			expectedContext:  "dummy_cluster",
+		},
+		{
+			name: "ephemeral-phase",
+			expectedErr: phase.ErrClusterContextNotFound{
+				Phase:   "ephemeral-phase",
+				Cluster: "dummy_cluster_ephemeral",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			settings := makeSettings(t, clusterConfigFile)
+			if tt.ephemeralContext {
+				settings.Config.Contexts["dummy_cluster_ephemeral"] = &config.Context{
+					NameInKubeconf: "dummy_cluster_ephemeral",
+					Manifest:       "dummy_manifest",
+				}
+			}
+			var executorConfig ifc.ExecutorConfig
+			engine := phase.NewEngine(settings, nil)
+			engine.Executors[kubernetesapply.Name] = func(c ifc.ExecutorConfig) (ifc.Executor, error) {
+				executorConfig = c
+				return &fakeExecutor{config: c}, nil
+			}
+
+			err := engine.Validate(tt.name, false)
+			assert.Equal(t, tt.expectedErr, err)
+			if err == nil {
+				assert.Equal(t, tt.expectedContext, executorConfig.Settings.KubeContext)
+			}
+		})
+	}
+}
+
+func TestEngineRunWorkloadCluster(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "workload-kubeconfig", Namespace: "target-infra"},
+		Data:       map[string][]byte{"value": []byte("apiVersion: v1\n")},
+	}
+
+	tests := []struct {
+		name               string
+		objects            []runtime.Object
+		expectedKubeconfig string
+		expectedErr        error
+	}{
+		{
+			name:               "kubeconfig",
+			objects:            []runtime.Object{secret},
+			expectedKubeconfig: "apiVersion: v1\n",
+		},
+		{
+			name:        "no-kubeconfig",
+			expectedErr: cluster.ErrKubeconfigNotFound{Cluster: "workload", Namespace: "target-infra"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var executorConfig ifc.ExecutorConfig
+			var kubeconfig string
+			engine := phase.NewEngine(makeSettings(t, clusterConfigFile), nil)
+			engine.Out = ioutil.Discard
+			engine.Executors[kubernetesapply.Name] = func(c ifc.ExecutorConfig) (ifc.Executor, error) {
+				executorConfig = c
+				return kubeconfigExecutor{fakeExecutor: &fakeExecutor{config: c}, kubeconfig: &kubeconfig}, nil
+			}
+			engine.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+				return fake.NewClient(fake.WithTypedObjects(tt.objects...)), nil
+			}
+
+			assert.Equal(t, tt.expectedErr, engine.Run("workload-phase"))
+			assert.Equal(t, tt.expectedKubeconfig, kubeconfig)
+			if tt.expectedErr == nil {
+				assert.Empty(t, executorConfig.Settings.KubeContext)
+				// The kubeconfig doesn't outlive the phase
+				_, err := os.Stat(executorConfig.Settings.KubeConfigPath)
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestInvalidCluster(t *testing.T) {
+	settings := makeSettings(t, "testdata/config-invalid.yaml")
+	tests := []struct {
+		name           string
+		expectedReason string
+	}{
+		{
+			name:           "unknown-cluster-type",
+			expectedReason: "config.clusterType: cluster type must be one of [ephemeral target]",
+		},
+		{
+			name:           "cluster-and-cluster-type",
+			expectedReason: "only one of config.cluster and config.clusterType can be set",
+		},
+		{
+			name:           "unnamed-workload-cluster",
+			expectedReason: "config.workloadCluster.name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := phase.Get(settings, tt.name)
+			assert.Equal(t, phase.ErrInvalidPhase{Name: tt.name, Reason: tt.expectedReason}, err)
+		})
+	}
+}
+
+func TestNewPhaseListClusters(t *testing.T) {
+	list, err := phase.NewPhaseList(makeSettings(t, clusterConfigFile), phase.ListFilter{})
+	require.NoError(t, err)
+	clusters := map[string]string{}
+	for _, p := range list.Phases {
+		clusters[p.Name] = p.Cluster
+	}
+	assert.Equal(t, map[string]string{
+		"ephemeral-phase": "ephemeral",
+		"target-phase":    "dummy_cluster",
+		"workload-phase":  "workload:target-infra/workload",
+	}, clusters)
+}
@@ -0,0 +1,154 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Dependencies returns the names of the phases the phase called name depends
+// on, directly or not, followed by name itself, in an order they can be run
+// in. Phases depending on each other and dependencies on phases which are
+// not declared are errors.
+func Dependencies(settings *environment.AirshipCTLSettings, name string) ([]string, error) {
+	phases, err := List(settings)
+	if err != nil {
+		return nil, err
+	}
+	w := newDependencyWalker(phases, false)
+	if _, ok := w.phases[name]; !ok {
+		return nil, ErrPhaseNotFound{Name: name}
+	}
+	if err = w.visit(name, nil); err != nil {
+		return nil, err
+	}
+	return w.order, nil
+}
+
+// orderPlan returns the groups of plan with their phases sorted so that
+// every phase is run after the phases it depends on. Phases keep the order
+// of the plan otherwise. Dependencies on phases of later groups are errors,
+// dependencies on phases which are not part of the plan are ignored.
+func orderPlan(plan *Plan, phases map[string]*Phase) ([]PhaseGroup, error) {
+	groupOf := map[string]int{}
+	for i, group := range plan.PhaseGroups {
+		for _, ref := range group.Phases {
+			groupOf[ref.Name] = i
+		}
+	}
+
+	groups := make([]PhaseGroup, 0, len(plan.PhaseGroups))
+	for i, group := range plan.PhaseGroups {
+		groupPhases := make([]*Phase, 0, len(group.Phases))
+		for _, ref := range group.Phases {
+			p := phases[ref.Name]
+			for _, dep := range p.Config.DependsOn {
+				if j, ok := groupOf[dep]; ok && j > i {
+					return nil, ErrInvalidPlan{
+						Name: plan.Name,
+						Reason: fmt.Sprintf("phase %s of group %s depends on phase %s of the later group %s",
+							p.Name, group.Name, dep, plan.PhaseGroups[j].Name),
+					}
+				}
+			}
+			groupPhases = append(groupPhases, p)
+		}
+
+		w := newDependencyWalker(groupPhases, true)
+		for _, p := range groupPhases {
+			if err := w.visit(p.Name, nil); err != nil {
+				return nil, ErrInvalidPlan{Name: plan.Name, Reason: err.Error()}
+			}
+		}
+		ordered := PhaseGroup{Name: group.Name, Phases: make([]PhaseRef, 0, len(w.order))}
+		for _, name := range w.order {
+			ordered.Phases = append(ordered.Phases, PhaseRef{Name: name})
+		}
+		groups = append(groups, ordered)
+	}
+	return groups, nil
+}
+
+// visit states of the phases of a dependencyWalker
+const (
+	visiting = iota + 1
+	visited
+)
+
+// dependencyWalker sorts phases so that every phase comes after the phases
+// it depends on
+type dependencyWalker struct {
+	phases map[string]*Phase
+	// ignoreMissing skips dependencies on phases the walker doesn't have
+	// instead of failing
+	ignoreMissing bool
+
+	state map[string]int
+	// order are the visited phases, every phase after its dependencies
+	order []string
+}
+
+func newDependencyWalker(phases []*Phase, ignoreMissing bool) *dependencyWalker {
+	w := &dependencyWalker{
+		phases:        make(map[string]*Phase, len(phases)),
+		ignoreMissing: ignoreMissing,
+		state:         map[string]int{},
+	}
+	for _, p := range phases {
+		w.phases[p.Name] = p
+	}
+	return w
+}
+
+// visit adds the dependencies of the phase called name and then the phase
+// to the order, path are the phases depending on it being visited
+func (w *dependencyWalker) visit(name string, path []string) error {
+	switch w.state[name] {
+	case visited:
+		return nil
+	case visiting:
+		for i, p := range path {
+			if p == name {
+				path = path[i:]
+				break
+			}
+		}
+		return ErrPhaseDependencyCycle{Phases: append(path, name)}
+	}
+
+	p, ok := w.phases[name]
+	if !ok {
+		if w.ignoreMissing {
+			return nil
+		}
+		return ErrInvalidPhase{
+			Name:   path[len(path)-1],
+			Reason: fmt.Sprintf("depends on phase %s which is not declared", name),
+		}
+	}
+
+	w.state[name] = visiting
+	path = append(path, name)
+	for _, dep := range p.Config.DependsOn {
+		if err := w.visit(dep, path); err != nil {
+			return err
+		}
+	}
+	w.state[name] = visited
+	w.order = append(w.order, name)
+	return nil
+}
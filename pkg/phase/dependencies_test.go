@@ -0,0 +1,161 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const dependenciesConfigFile = "testdata/config-dependencies.yaml"
+
+func TestDependencies(t *testing.T) {
+	settings := makeSettings(t, dependenciesConfigFile)
+
+	tests := []struct {
+		name          string
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name:          "crds",
+			expectedNames: []string{"crds"},
+		},
+		{
+			name:          "workloads",
+			expectedNames: []string{"crds", "controllers", "workloads"},
+		},
+		{
+			name:        "cycle-b",
+			expectedErr: phase.ErrPhaseDependencyCycle{Phases: []string{"cycle-b", "cycle-c", "cycle-a", "cycle-b"}},
+		},
+		{
+			name:        "dangling",
+			expectedErr: phase.ErrInvalidPhase{Name: "dangling", Reason: "depends on phase missing which is not declared"},
+		},
+		{
+			name:        "missing",
+			expectedErr: phase.ErrPhaseNotFound{Name: "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := phase.Dependencies(settings, tt.name)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+// dependencyEngine returns an Engine running the phases of the dependency
+// site with fakeExecutors
+func dependencyEngine(t *testing.T, ran *[]string) (*phase.Engine, *bytes.Buffer, func(*testing.T)) {
+	t.Helper()
+	dir, cleanup := testutil.TempDir(t, "checkpoints")
+	out := &bytes.Buffer{}
+	engine := phase.NewEngine(makeSettings(t, dependenciesConfigFile), nil)
+	engine.Out = out
+	engine.CheckpointDir = dir
+	engine.Executors = map[string]ifc.ExecutorFactory{
+		kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+			return &fakeExecutor{config: config, ran: ran}, nil
+		},
+	}
+	return engine, out, cleanup
+}
+
+func TestRunWithDependencies(t *testing.T) {
+	var ran []string
+	engine, out, cleanup := dependencyEngine(t, &ran)
+	defer cleanup(t)
+
+	require.NoError(t, engine.RunWithDependencies("workloads"))
+	assert.Equal(t, []string{"crds", "controllers", "workloads"}, ran)
+	assert.Equal(t, "Running phase crds\nran crds\nRunning phase controllers\nran controllers\n"+
+		"Running phase workloads\nran workloads\n", out.String())
+
+	ran = nil
+	assert.Equal(t, phase.ErrPhaseDependencyCycle{Phases: []string{"cycle-a", "cycle-b", "cycle-c", "cycle-a"}},
+		engine.RunWithDependencies("cycle-a"))
+	assert.Empty(t, ran)
+}
+
+func TestRunPlanDependencies(t *testing.T) {
+	tests := []struct {
+		plan        string
+		expectedRun []string
+		expectedErr error
+	}{
+		{
+			plan:        "reordered",
+			expectedRun: []string{"crds", "controllers", "workloads"},
+		},
+		{
+			plan:        "partial",
+			expectedRun: []string{"controllers", "workloads"},
+		},
+		{
+			plan: "backwards",
+			expectedErr: phase.ErrInvalidPlan{
+				Name:   "backwards",
+				Reason: "phase controllers of group first depends on phase crds of the later group second",
+			},
+		},
+		{
+			plan: "cycle",
+			expectedErr: phase.ErrInvalidPlan{
+				Name: "cycle",
+				Reason: phase.ErrPhaseDependencyCycle{
+					Phases: []string{"cycle-a", "cycle-b", "cycle-c", "cycle-a"},
+				}.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.plan, func(t *testing.T) {
+			var ran []string
+			engine, _, cleanup := dependencyEngine(t, &ran)
+			defer cleanup(t)
+			assert.Equal(t, tt.expectedErr, engine.RunPlan(tt.plan, false))
+			assert.Equal(t, tt.expectedRun, ran)
+		})
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	var ran []string
+	engine, _, cleanup := dependencyEngine(t, &ran)
+	defer cleanup(t)
+
+	assert.NoError(t, engine.Validate("workloads", false))
+	assert.Equal(t, phase.ErrPhaseValidation{
+		Phase: "dangling",
+		Errors: []error{
+			phase.ErrInvalidPhase{Name: "dangling", Reason: "depends on phase missing which is not declared"},
+		},
+	}, engine.Validate("dangling", false))
+}
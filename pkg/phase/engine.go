@@ -0,0 +1,398 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/phase/events"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/clusterctl"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/genericcontainer"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// Registry contains factory functions for the available executors, by name
+var Registry = make(map[string]ifc.ExecutorFactory)
+
+func init() {
+	clusterctl.RegisterExecutor(Registry)
+	genericcontainer.RegisterExecutor(Registry)
+	kubernetesapply.RegisterExecutor(Registry)
+}
+
+// Engine runs the phases of the current context with the executors they
+// name, replacing the deployment logic of individual commands
+type Engine struct {
+	Settings *environment.AirshipCTLSettings
+	// Executors are the executors phases can name, by name
+	Executors map[string]ifc.ExecutorFactory
+	// ClientFactory returns the clients executors reach clusters with
+	ClientFactory client.Factory
+	Out           io.Writer
+	// CheckpointDir is where the progress of plans is recorded,
+	// DefaultCheckpointDir if empty
+	CheckpointDir string
+	// PollInterval is how often the wait conditions of phases are checked
+	PollInterval time.Duration
+	// Processors handle the events of the phases run, in addition to the
+	// printing of their messages to Out. They must be safe for concurrent
+	// use when MaxParallel is above 1.
+	Processors []ifc.EventProcessor
+	// MaxParallel is how many phases of a plan group can be run at the same
+	// time, phases are run one after the other if it is 1 or less
+	MaxParallel int
+	// DryRun makes the engine report what running phases would do: their
+	// executors are run in dry run mode, wait conditions are only listed
+	// and plans don't record checkpoints
+	DryRun bool
+
+	// printer writes the messages of events to Out, it is created with the
+	// first event
+	printer *events.Printer
+}
+
+// NewEngine returns an Engine with the executors of the Registry, connecting
+// to clusters with clients of factory and writing to stdout
+func NewEngine(settings *environment.AirshipCTLSettings, factory client.Factory) *Engine {
+	executors := make(map[string]ifc.ExecutorFactory, len(Registry))
+	for name, executorFactory := range Registry {
+		executors[name] = executorFactory
+	}
+	return &Engine{
+		Settings:      settings,
+		Executors:     executors,
+		ClientFactory: factory,
+		Out:           os.Stdout,
+		PollInterval:  status.DefaultPollInterval,
+	}
+}
+
+// Run validates and runs the phase called name, and then waits for its wait
+// conditions
+func (e *Engine) Run(name string) error {
+	log.Printf("Running phase %s", name)
+	return e.run(name)
+}
+
+// run runs the phase called name like Run, without announcing it. Plans and
+// dependencies announce their phases themselves.
+func (e *Engine) run(name string) error {
+	executor, err := e.executor(name)
+	if err != nil {
+		return err
+	}
+	if err = executor.Validate(); err != nil {
+		return err
+	}
+
+	if e.DryRun {
+		fmt.Fprintf(e.Out, "Phase %s: executor %s, cluster %s, documents %s (dry run)\n",
+			name, executor.config.ExecutorName, phaseContext(e.Settings, executor.phase), executor.config.EntryPoint)
+	}
+	disconnect, err := e.connect(executor)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+	ch := make(chan ifc.Event)
+	go executor.Run(ch)
+	for event := range ch {
+		if event.Phase == "" {
+			event.Phase = name
+		}
+		if event.Err != nil {
+			event.Type = ifc.EventError
+			err = event.Err
+		}
+		if processErr := e.emit(event); processErr != nil && err == nil {
+			err = processErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return e.wait(executor.phase, executor.config.Settings)
+}
+
+// emit prints the message of event to Out and hands event to every
+// processor of the engine
+func (e *Engine) emit(event ifc.Event) error {
+	if e.printer == nil {
+		e.printer = events.NewPrinter(e.Out)
+	}
+	if err := e.printer.Process(event); err != nil {
+		return err
+	}
+	for _, processor := range e.Processors {
+		if err := processor.Process(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render writes the documents of the phase called name, as its executor
+// sees them
+func (e *Engine) Render(name string, out io.Writer) error {
+	executor, err := e.executor(name)
+	if err != nil {
+		return err
+	}
+	return executor.Render(out)
+}
+
+// Rollback undoes what running the phase called name did to its cluster,
+// as its executor sees it
+func (e *Engine) Rollback(name string, options ifc.RollbackOptions) error {
+	executor, err := e.executor(name)
+	if err != nil {
+		return err
+	}
+	log.Printf("Rolling back phase %s", name)
+	return executor.Rollback(options)
+}
+
+// Validate checks the phase called name without running it: its Phase
+// document and dependencies, the documents built from its entry point and
+// the expectations of its executor on them. With schema, the documents deployed to the
+// cluster of the phase are also checked against the OpenAPI schema of the
+// cluster. Problems found with the documents are returned together with
+// ErrPhaseValidation.
+func (e *Engine) Validate(name string, schema bool) error {
+	executor, err := e.executor(name)
+	if err != nil {
+		return err
+	}
+	b, err := document.NewBundleByPath(executor.config.EntryPoint)
+	if err != nil {
+		return err
+	}
+
+	result := ErrPhaseValidation{Phase: name}
+	if _, err = Dependencies(e.Settings, name); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	if schema {
+		if err = e.validateSchema(executor, b); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+	if err = executor.Validate(); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	if len(result.Errors) > 0 {
+		return result
+	}
+	return nil
+}
+
+// validateSchema checks the documents of b deployed to the cluster of the
+// phase of executor against the OpenAPI schema of the cluster
+func (e *Engine) validateSchema(executor *phaseExecutor, b document.Bundle) error {
+	docs, err := b.Select(document.NewDeployToK8sSelector())
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+	disconnect, err := e.connect(executor)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+	kclient, err := e.ClientFactory(executor.config.Settings)
+	if err != nil {
+		return err
+	}
+	return kclient.Kubectl().Validate(docs)
+}
+
+// phaseExecutor is the executor of a phase, with the configuration it was
+// built with
+type phaseExecutor struct {
+	ifc.Executor
+	phase  *Phase
+	config ifc.ExecutorConfig
+}
+
+// executor returns the executor of the phase called name
+func (e *Engine) executor(name string) (*phaseExecutor, error) {
+	p, err := Get(e.Settings, name)
+	if err != nil {
+		return nil, err
+	}
+	newExecutor, ok := e.Executors[p.Config.Executor]
+	if !ok {
+		return nil, ErrUnknownExecutor{Phase: p.Name, Executor: p.Config.Executor}
+	}
+
+	entryPoint, err := e.Settings.CurrentContextEntryPoint(p.EntryPoint())
+	if err != nil {
+		return nil, err
+	}
+	settings := *e.Settings
+	if settings.KubeContext, err = clusterContext(&settings, p); err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(entryPoint); err != nil {
+		return nil, ErrInvalidPhase{Name: p.Name, Reason: fmt.Sprintf("no documents at %s", entryPoint)}
+	}
+
+	config := ifc.ExecutorConfig{
+		PhaseName:     p.Name,
+		ExecutorName:  p.Config.Executor,
+		Config:        p.Config.ExecutorConfig,
+		Settings:      &settings,
+		EntryPoint:    entryPoint,
+		ClientFactory: e.ClientFactory,
+		Out:           e.Out,
+		DryRun:        e.DryRun,
+	}
+	executor, err := newExecutor(config)
+	if err != nil {
+		return nil, err
+	}
+	return &phaseExecutor{Executor: executor, phase: p, config: config}, nil
+}
+
+// RunWithDependencies runs the phases the phase called name depends on,
+// directly or not, and then the phase, stopping at the first one failing
+func (e *Engine) RunWithDependencies(name string) error {
+	names, err := Dependencies(e.Settings, name)
+	if err != nil {
+		return err
+	}
+	for _, phaseName := range names {
+		fmt.Fprintf(e.Out, "Running phase %s\n", phaseName)
+		if err = e.run(phaseName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunPlan runs the phases of the plan called name one after the other,
+// stopping at the first one failing. The phases of every group are run
+// after the phases they depend on, with MaxParallel above 1 the phases of a
+// group which don't depend on each other are run at the same time. All phases of the plan are looked up
+// before any is run. Every phase completing is recorded in the checkpoint of
+// the plan, with resume the phases recorded by the previous run are skipped.
+// The checkpoint is removed once the plan completed. With DryRun, the
+// checkpoint is read but never written nor removed.
+func (e *Engine) RunPlan(name string, resume bool) error {
+	plan, err := GetPlan(e.Settings, name)
+	if err != nil {
+		return err
+	}
+	phases := map[string]*Phase{}
+	for _, phaseName := range plan.PhaseNames() {
+		if phases[phaseName], err = Get(e.Settings, phaseName); err != nil {
+			return ErrInvalidPlan{Name: plan.Name, Reason: err.Error()}
+		}
+	}
+	groups, err := orderPlan(plan, phases)
+	if err != nil {
+		return err
+	}
+
+	context := e.Settings.Config.CurrentContext
+	path := CheckpointPath(e.checkpointDir(), context, plan.Name)
+	checkpoint := &Checkpoint{Plan: plan.Name, Context: context}
+	if resume {
+		previous, loadErr := LoadCheckpoint(path)
+		switch loadErr.(type) {
+		case nil:
+			checkpoint = previous
+		case ErrCheckpointNotFound:
+			fmt.Fprintf(e.Out, "No checkpoint of plan %s, running all its phases\n", plan.Name)
+		default:
+			return loadErr
+		}
+	} else if err = e.removeCheckpoint(path); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		if e.MaxParallel > 1 {
+			if err = e.runGroupParallel(plan.Name, group, phases, checkpoint, path); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, ref := range group.Phases {
+			if checkpoint.IsCompleted(group.Name, ref.Name) {
+				fmt.Fprintf(e.Out, "Skipping phase %s of group %s, completed by a previous run\n", ref.Name, group.Name)
+				continue
+			}
+			fmt.Fprintf(e.Out, "Running phase %s of group %s\n", ref.Name, group.Name)
+			if err = e.run(ref.Name); err != nil {
+				return ErrPlanFailed{Plan: plan.Name, Group: group.Name, Phase: ref.Name, Err: err}
+			}
+			checkpoint.Completed = append(checkpoint.Completed, CompletedPhase{
+				Group:       group.Name,
+				Name:        ref.Name,
+				CompletedAt: time.Now().UTC(),
+			})
+			if err = e.saveCheckpoint(checkpoint, path); err != nil {
+				return err
+			}
+		}
+	}
+	if err = e.removeCheckpoint(path); err != nil {
+		return err
+	}
+	if e.DryRun {
+		fmt.Fprintf(e.Out, "Plan %s completed (dry run)\n", plan.Name)
+		return nil
+	}
+	fmt.Fprintf(e.Out, "Plan %s completed\n", plan.Name)
+	return nil
+}
+
+func (e *Engine) checkpointDir() string {
+	if e.CheckpointDir != "" {
+		return e.CheckpointDir
+	}
+	return DefaultCheckpointDir(e.Settings)
+}
+
+// saveCheckpoint records checkpoint at path, unless the engine runs in dry
+// run mode
+func (e *Engine) saveCheckpoint(checkpoint *Checkpoint, path string) error {
+	if e.DryRun {
+		return nil
+	}
+	return checkpoint.Save(path)
+}
+
+// removeCheckpoint removes the checkpoint at path if any, unless the engine
+// runs in dry run mode
+func (e *Engine) removeCheckpoint(path string) error {
+	if e.DryRun {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
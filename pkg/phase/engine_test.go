@@ -0,0 +1,320 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/clusterctl"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	k8stest "opendev.org/airship/airshipctl/testutil/k8sutils"
+)
+
+// fakeExecutor records the phases it runs
+type fakeExecutor struct {
+	config      ifc.ExecutorConfig
+	runErr      error
+	validateErr error
+	ran         *[]string
+}
+
+// ranMu guards the phases fakeExecutors ran, as plans can run them at the
+// same time
+var ranMu sync.Mutex
+
+func (e *fakeExecutor) Run(ch chan<- ifc.Event) {
+	defer close(ch)
+	ranMu.Lock()
+	*e.ran = append(*e.ran, e.config.PhaseName)
+	ranMu.Unlock()
+	ch <- ifc.Event{Type: ifc.EventStarted, Message: "ran " + e.config.PhaseName}
+	if e.runErr != nil {
+		ch <- ifc.Event{Err: e.runErr}
+	}
+}
+
+func (e *fakeExecutor) Render(out io.Writer) error {
+	_, err := fmt.Fprintf(out, "documents of %s\n", e.config.PhaseName)
+	return err
+}
+
+func (e *fakeExecutor) Validate() error {
+	return e.validateErr
+}
+
+func (e *fakeExecutor) Rollback(options ifc.RollbackOptions) error {
+	*e.ran = append(*e.ran, fmt.Sprintf("rollback %s delete=%t", e.config.PhaseName, options.Delete))
+	return nil
+}
+
+// fakeEngine returns an Engine whose KubernetesApply executor is a
+// fakeExecutor failing to run or validate the given phases
+func fakeEngine(t *testing.T, ran *[]string, runErrs, validateErrs map[string]error) (*phase.Engine, *bytes.Buffer) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	engine := phase.NewEngine(makeSettings(t, airshipConfigFile), nil)
+	engine.Out = out
+	engine.Executors = map[string]ifc.ExecutorFactory{
+		kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+			return &fakeExecutor{
+				config:      config,
+				runErr:      runErrs[config.PhaseName],
+				validateErr: validateErrs[config.PhaseName],
+				ran:         ran,
+			}, nil
+		},
+	}
+	return engine, out
+}
+
+func TestRegistry(t *testing.T) {
+	engine := phase.NewEngine(makeSettings(t, airshipConfigFile), nil)
+	assert.Contains(t, engine.Executors, kubernetesapply.Name)
+	assert.Contains(t, engine.Executors, clusterctl.Name)
+}
+
+func TestEngineRun(t *testing.T) {
+	errRun := errors.New("run failed")
+	errValidate := errors.New("invalid documents")
+
+	tests := []struct {
+		name         string
+		runErrs      map[string]error
+		validateErrs map[string]error
+		expectedRan  []string
+		expectedOut  string
+		expectedErr  error
+	}{
+		{
+			name:        "initinfra",
+			expectedRan: []string{"initinfra"},
+			expectedOut: "ran initinfra\n",
+		},
+		{
+			name:        "initinfra",
+			runErrs:     map[string]error{"initinfra": errRun},
+			expectedRan: []string{"initinfra"},
+			expectedOut: "ran initinfra\n",
+			expectedErr: errRun,
+		},
+		{
+			name:         "initinfra",
+			validateErrs: map[string]error{"initinfra": errValidate},
+			expectedErr:  errValidate,
+		},
+		{
+			name:        "missing",
+			expectedErr: phase.ErrPhaseNotFound{Name: "missing"},
+		},
+		{
+			name:        "unknown-context",
+			expectedErr: config.ErrMissingConfig{What: "Context with name 'missing_context'"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			engine, out := fakeEngine(t, &ran, tt.runErrs, tt.validateErrs)
+			assert.Equal(t, tt.expectedErr, engine.Run(tt.name))
+			assert.Equal(t, tt.expectedRan, ran)
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
+
+func TestEngineRunDryRun(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+	entryPoint, err := settings.CurrentContextEntryPoint("initinfra")
+	require.NoError(t, err)
+
+	var config ifc.ExecutorConfig
+	var ran []string
+	out := &bytes.Buffer{}
+	engine := phase.NewEngine(settings, nil)
+	engine.Out = out
+	engine.DryRun = true
+	engine.Executors[kubernetesapply.Name] = func(c ifc.ExecutorConfig) (ifc.Executor, error) {
+		config = c
+		return &fakeExecutor{config: c, ran: &ran}, nil
+	}
+
+	require.NoError(t, engine.Run("target-initinfra"))
+	assert.True(t, config.DryRun)
+	assert.Equal(t, []string{"target-initinfra"}, ran)
+	assert.Equal(t, fmt.Sprintf("Phase target-initinfra: executor KubernetesApply, cluster dummy_target, "+
+		"documents %s (dry run)\nran target-initinfra\n", entryPoint), out.String())
+}
+
+type recordingProcessor struct {
+	events []ifc.Event
+}
+
+func (p *recordingProcessor) Process(event ifc.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestEngineRunProcessors(t *testing.T) {
+	errRun := errors.New("run failed")
+	var ran []string
+	engine, out := fakeEngine(t, &ran, map[string]error{"initinfra": errRun}, nil)
+	processor := &recordingProcessor{}
+	engine.Processors = []ifc.EventProcessor{processor}
+
+	assert.Equal(t, errRun, engine.Run("initinfra"))
+	assert.Equal(t, []ifc.Event{
+		{Type: ifc.EventStarted, Phase: "initinfra", Message: "ran initinfra"},
+		{Type: ifc.EventError, Phase: "initinfra", Err: errRun},
+	}, processor.events)
+	assert.Equal(t, "ran initinfra\n", out.String())
+}
+
+func TestEngineExecutorConfig(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+	initinfra, err := settings.CurrentContextEntryPoint("initinfra")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		expectedContext string
+		expectedConfig  string
+	}{
+		{
+			name:            "initinfra",
+			expectedContext: settings.KubeContext,
+			expectedConfig:  `{"wait": true, "waitTimeout": "10m"}`,
+		},
+		{
+			name:            "target-initinfra",
+			expectedContext: "dummy_target",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var config ifc.ExecutorConfig
+			engine := phase.NewEngine(settings, nil)
+			engine.Executors[kubernetesapply.Name] = func(c ifc.ExecutorConfig) (ifc.Executor, error) {
+				config = c
+				return &fakeExecutor{config: c}, nil
+			}
+
+			require.NoError(t, engine.Validate(tt.name, false))
+			assert.Equal(t, tt.name, config.PhaseName)
+			assert.Equal(t, kubernetesapply.Name, config.ExecutorName)
+			assert.Equal(t, initinfra, config.EntryPoint)
+			assert.Equal(t, tt.expectedContext, config.Settings.KubeContext)
+			if tt.expectedConfig != "" {
+				assert.JSONEq(t, tt.expectedConfig, string(config.Config))
+			}
+		})
+	}
+	// Phases don't change the settings of the engine
+	assert.NotEqual(t, "dummy_target", settings.KubeContext)
+}
+
+func TestEngineUnknownExecutor(t *testing.T) {
+	engine := phase.NewEngine(makeSettings(t, airshipConfigFile), nil)
+	engine.Executors = map[string]ifc.ExecutorFactory{}
+	assert.Equal(t, phase.ErrUnknownExecutor{Phase: "initinfra", Executor: kubernetesapply.Name},
+		engine.Run("initinfra"))
+}
+
+func TestEngineRender(t *testing.T) {
+	var ran []string
+	engine, _ := fakeEngine(t, &ran, nil, nil)
+	out := &bytes.Buffer{}
+	require.NoError(t, engine.Render("target-initinfra", out))
+	assert.Equal(t, "documents of target-initinfra\n", out.String())
+	assert.Empty(t, ran)
+}
+
+func TestEngineValidate(t *testing.T) {
+	errValidate := errors.New("invalid documents")
+	errSchema := errors.New("failed to download schema")
+
+	tests := []struct {
+		name         string
+		schema       bool
+		validateErrs map[string]error
+		expectedErr  error
+	}{
+		{
+			name: "initinfra",
+		},
+		{
+			name:         "initinfra",
+			validateErrs: map[string]error{"initinfra": errValidate},
+			expectedErr:  phase.ErrPhaseValidation{Phase: "initinfra", Errors: []error{errValidate}},
+		},
+		{
+			name:         "initinfra",
+			schema:       true,
+			validateErrs: map[string]error{"initinfra": errValidate},
+			expectedErr:  phase.ErrPhaseValidation{Phase: "initinfra", Errors: []error{errSchema, errValidate}},
+		},
+		{
+			name:        "missing",
+			expectedErr: phase.ErrPhaseNotFound{Name: "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			engine, _ := fakeEngine(t, &ran, nil, tt.validateErrs)
+			engine.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+				f := k8stest.NewMockKubectlFactory().WithOpenAPISchemaByError(nil, errSchema)
+				return fake.NewClient(fake.WithKubectl(kubectl.NewKubectl(f))), nil
+			}
+			assert.Equal(t, tt.expectedErr, engine.Validate(tt.name, tt.schema))
+			assert.Empty(t, ran)
+		})
+	}
+}
+
+func TestEngineValidateNoDocuments(t *testing.T) {
+	engine := phase.NewEngine(makeSettings(t, "testdata/config-invalid.yaml"), nil)
+	err := engine.Validate("no-documents", false)
+	assert.IsType(t, phase.ErrInvalidPhase{}, err)
+}
+
+func TestEngineRollback(t *testing.T) {
+	var ran []string
+	engine, _ := fakeEngine(t, &ran, nil, nil)
+	require.NoError(t, engine.Rollback("initinfra", ifc.RollbackOptions{Delete: true}))
+	assert.Equal(t, []string{"rollback initinfra delete=true"}, ran)
+
+	assert.Equal(t, phase.ErrPhaseNotFound{Name: "missing"}, engine.Rollback("missing", ifc.RollbackOptions{}))
+}
@@ -0,0 +1,223 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrPhaseNotFound is returned when no Phase document declares a phase
+type ErrPhaseNotFound struct {
+	Name string
+}
+
+func (e ErrPhaseNotFound) Error() string {
+	return fmt.Sprintf("phase %s is not declared by any Phase document", e.Name)
+}
+
+// ErrInvalidPhase is returned for a Phase document which doesn't declare a
+// valid phase
+type ErrInvalidPhase struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrInvalidPhase) Error() string {
+	return fmt.Sprintf("invalid Phase %s: %s", e.Name, e.Reason)
+}
+
+// ErrUnknownExecutor is returned when a phase is to be run by an executor
+// airshipctl doesn't have
+type ErrUnknownExecutor struct {
+	Phase    string
+	Executor string
+}
+
+func (e ErrUnknownExecutor) Error() string {
+	return fmt.Sprintf("unknown executor %s of phase %s", e.Executor, e.Phase)
+}
+
+// ErrClusterContextNotFound is returned when no context of the airship
+// config reaches the cluster of the clusterType of a phase
+type ErrClusterContextNotFound struct {
+	Phase   string
+	Cluster string
+}
+
+func (e ErrClusterContextNotFound) Error() string {
+	return fmt.Sprintf("no context of cluster %s, which phase %s is run against", e.Cluster, e.Phase)
+}
+
+// ErrInvalidPlan is returned for a PhasePlan document which doesn't declare
+// a valid plan
+type ErrInvalidPlan struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrInvalidPlan) Error() string {
+	return fmt.Sprintf("invalid PhasePlan %s: %s", e.Name, e.Reason)
+}
+
+// ErrPlanNotFound is returned when no PhasePlan document declares a plan
+type ErrPlanNotFound struct {
+	Name string
+}
+
+func (e ErrPlanNotFound) Error() string {
+	return fmt.Sprintf("plan %s is not declared by any PhasePlan document", e.Name)
+}
+
+// ErrInvalidOutputFormat is returned for an output format phases can't be
+// printed in. Formats are the valid formats, OutputTable and OutputYAML if
+// empty.
+type ErrInvalidOutputFormat struct {
+	Format  string
+	Formats []string
+}
+
+func (e ErrInvalidOutputFormat) Error() string {
+	formats := e.Formats
+	if len(formats) == 0 {
+		formats = []string{OutputTable, OutputYAML}
+	}
+	return fmt.Sprintf("invalid output format %q, must be %q or %q", e.Format, formats[0], formats[1])
+}
+
+// ErrPlanFailed is returned when a phase of a plan failed, the phases after
+// it are not run
+type ErrPlanFailed struct {
+	Plan  string
+	Group string
+	Phase string
+	Err   error
+}
+
+func (e ErrPlanFailed) Error() string {
+	return fmt.Sprintf("plan %s failed at phase %s of group %s: %v", e.Plan, e.Phase, e.Group, e.Err)
+}
+
+// ErrPhaseValidation is returned when the documents of a phase are not fit
+// to be run by its executor
+type ErrPhaseValidation struct {
+	Phase  string
+	Errors []error
+}
+
+func (e ErrPhaseValidation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "phase %s is invalid:", e.Phase)
+	for _, err := range e.Errors {
+		fmt.Fprintf(&b, "\n- %v", err)
+	}
+	return b.String()
+}
+
+// ErrCheckpointNotFound is returned when a plan has no checkpoint to resume
+// from
+type ErrCheckpointNotFound struct {
+	Path string
+}
+
+func (e ErrCheckpointNotFound) Error() string {
+	return fmt.Sprintf("no checkpoint found at %s", e.Path)
+}
+
+// ErrPhaseDependencyCycle is returned when phases depend on each other
+type ErrPhaseDependencyCycle struct {
+	Phases []string
+}
+
+func (e ErrPhaseDependencyCycle) Error() string {
+	return fmt.Sprintf("phases depend on each other: %s", strings.Join(e.Phases, " -> "))
+}
+
+// ErrWaitConditionsTimeout is returned when the wait conditions of a phase
+// are not met in time
+type ErrWaitConditionsTimeout struct {
+	Phase      string
+	Timeout    time.Duration
+	Conditions []string
+}
+
+func (e ErrWaitConditionsTimeout) Error() string {
+	return fmt.Sprintf("wait conditions of phase %s not met after %s: %s",
+		e.Phase, e.Timeout, strings.Join(e.Conditions, ", "))
+}
+
+// ErrWaitConditionFieldRequired is returned when a wait condition of a phase
+// lacks a field
+type ErrWaitConditionFieldRequired struct {
+	Field string
+}
+
+func (e ErrWaitConditionFieldRequired) Error() string {
+	return fmt.Sprintf("%s is required", e.Field)
+}
+
+// ErrInvalidJSONPath is returned when the JSONPath of a wait condition of a
+// phase can't be parsed
+type ErrInvalidJSONPath struct {
+	JSONPath string
+	Err      error
+}
+
+func (e ErrInvalidJSONPath) Error() string {
+	return fmt.Sprintf("invalid jsonPath %s: %v", e.JSONPath, e.Err)
+}
+
+// ErrConflictingClusterFields is returned when a Phase document names its
+// cluster with more than one of the given fields
+type ErrConflictingClusterFields struct {
+	Fields []string
+}
+
+func (e ErrConflictingClusterFields) Error() string {
+	last := len(e.Fields) - 1
+	return fmt.Sprintf("only one of %s and %s can be set", strings.Join(e.Fields[:last], ", "), e.Fields[last])
+}
+
+// ErrInvalidClusterType is returned when the clusterType of a Phase document
+// is not a valid cluster type
+type ErrInvalidClusterType struct {
+	Field string
+	Err   error
+}
+
+func (e ErrInvalidClusterType) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// ErrPhaseFieldRequired is returned when a Phase document lacks a field
+type ErrPhaseFieldRequired struct {
+	Field string
+}
+
+func (e ErrPhaseFieldRequired) Error() string {
+	return fmt.Sprintf("%s is required", e.Field)
+}
+
+// ErrInvalidKustomization is returned when a kustomization of the documents
+// of a phase can't be read
+type ErrInvalidKustomization struct {
+	Path string
+	Err  error
+}
+
+func (e ErrInvalidKustomization) Error() string {
+	return fmt.Sprintf("invalid kustomization %s: %v", e.Path, e.Err)
+}
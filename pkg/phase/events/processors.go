@@ -0,0 +1,91 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// Printer writes the messages of events to a terminal. Errors are left to
+// whoever gets them from the engine. It can be used by phases run at the same
+// time.
+type Printer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewPrinter returns a Printer writing to out
+func NewPrinter(out io.Writer) *Printer {
+	return &Printer{out: out}
+}
+
+// Process writes the message of event on its own line
+func (p *Printer) Process(event ifc.Event) error {
+	if event.Type == ifc.EventError || event.Message == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintln(p.out, event.Message)
+	return err
+}
+
+// Record is an event as written by JSONWriter
+type Record struct {
+	Time     time.Time     `json:"time"`
+	Type     ifc.EventType `json:"type"`
+	Phase    string        `json:"phase"`
+	Resource string        `json:"resource,omitempty"`
+	Message  string        `json:"message,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// JSONWriter writes every event as a JSON object on its own line, so that
+// the progress of phases can be followed by other programs. It can be used by
+// phases run at the same time.
+type JSONWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	// Now returns the time events are recorded at, time.Now by default
+	Now func() time.Time
+}
+
+// NewJSONWriter returns a JSONWriter writing to out
+func NewJSONWriter(out io.Writer) *JSONWriter {
+	return &JSONWriter{enc: json.NewEncoder(out), Now: time.Now}
+}
+
+// Process writes event as a Record
+func (w *JSONWriter) Process(event ifc.Event) error {
+	record := Record{
+		Time:     w.Now().UTC(),
+		Type:     event.Type,
+		Phase:    event.Phase,
+		Resource: event.Resource,
+		Message:  event.Message,
+	}
+	if event.Err != nil {
+		record.Error = event.Err.Error()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(record)
+}
@@ -0,0 +1,86 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase/events"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+func testEvents() []ifc.Event {
+	return []ifc.Event{
+		{Type: ifc.EventStarted, Phase: "initinfra", Message: "Applying the documents of phase initinfra"},
+		{
+			Type:     ifc.EventResourceApplied,
+			Phase:    "initinfra",
+			Resource: "Deployment/default/web",
+			Message:  "Deployment/web in namespace default created",
+		},
+		{Type: ifc.EventError, Phase: "initinfra", Err: errors.New("apply failed")},
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	out := &bytes.Buffer{}
+	printer := events.NewPrinter(out)
+	for _, event := range testEvents() {
+		require.NoError(t, printer.Process(event))
+	}
+	assert.Equal(t, "Applying the documents of phase initinfra\nDeployment/web in namespace default created\n",
+		out.String())
+}
+
+func TestPrinterConcurrent(t *testing.T) {
+	out := &bytes.Buffer{}
+	printer := events.NewPrinter(out)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, event := range testEvents() {
+				assert.NoError(t, printer.Process(event))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 20, strings.Count(out.String(), "\n"))
+}
+
+func TestJSONWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := events.NewJSONWriter(out)
+	writer.Now = func() time.Time { return time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC) }
+	for _, event := range testEvents() {
+		require.NoError(t, writer.Process(event))
+	}
+	expected := `{"time":"2020-06-01T12:00:00Z","type":"started","phase":"initinfra",` +
+		`"message":"Applying the documents of phase initinfra"}
+{"time":"2020-06-01T12:00:00Z","type":"resourceApplied","phase":"initinfra",` +
+		`"resource":"Deployment/default/web","message":"Deployment/web in namespace default created"}
+{"time":"2020-06-01T12:00:00Z","type":"error","phase":"initinfra","error":"apply failed"}
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,181 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package clusterctl
+
+import (
+	"fmt"
+	"io"
+
+	clusterctlcmd "opendev.org/airship/airshipctl/pkg/clusterctl/cmd"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// Name is the name of the executor running clusterctl with the Clusterctl
+// document of a phase
+const Name = "Clusterctl"
+
+// Actions of the Clusterctl executor
+const (
+	// ActionInit installs the providers of the Clusterctl document in the
+	// cluster of the phase
+	ActionInit = "init"
+	// ActionMove moves the Cluster API objects from the cluster of the phase
+	// to the cluster of the target context
+	ActionMove = "move"
+)
+
+// Config is the executorConfig of phases run by the Clusterctl executor
+type Config struct {
+	// Action is the clusterctl command run, init or move
+	Action string `json:"action"`
+	// TargetContext is the context of the cluster move moves objects to
+	TargetContext string `json:"targetContext,omitempty"`
+	// Namespace of the objects move moves, the namespace of the Clusterctl
+	// document if empty
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Executor runs clusterctl
+type Executor struct {
+	config ifc.ExecutorConfig
+	cfg    Config
+}
+
+// RegisterExecutor registers the Clusterctl executor
+func RegisterExecutor(registry map[string]ifc.ExecutorFactory) {
+	registry[Name] = New
+}
+
+// New returns an Executor running clusterctl for the phase of config
+func New(config ifc.ExecutorConfig) (ifc.Executor, error) {
+	e := &Executor{config: config}
+	if err := ifc.DecodeConfig(config, &e.cfg); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Run runs the clusterctl action of the phase
+func (e *Executor) Run(ch chan<- ifc.Event) {
+	defer close(ch)
+
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+	kubeContext := e.config.Settings.KubeContext
+	if kubeContext == "" {
+		kubeContext = e.config.Settings.Config.CurrentContext
+	}
+	command, err := clusterctlcmd.NewCommandFromBundle(e.config.Settings, b, kubeContext)
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+
+	if e.config.DryRun {
+		ch <- e.event(ifc.EventCompleted, e.dryRunMessage(kubeContext), nil)
+		return
+	}
+
+	var completed string
+	switch e.cfg.Action {
+	case ActionInit:
+		ch <- e.event(ifc.EventStarted, fmt.Sprintf("Initializing the providers of context %s", kubeContext), nil)
+		err = command.Init()
+		completed = fmt.Sprintf("Providers of context %s initialized", kubeContext)
+	case ActionMove:
+		ch <- e.event(ifc.EventStarted, fmt.Sprintf("Moving Cluster API objects from context %s to context %s",
+			kubeContext, e.cfg.TargetContext), nil)
+		report, moveErr := command.Move(e.cfg.TargetContext, e.cfg.Namespace)
+		if report != nil {
+			err = report.Print(e.config.Out)
+		}
+		if moveErr != nil {
+			err = moveErr
+		}
+		completed = fmt.Sprintf("Cluster API objects moved to context %s", e.cfg.TargetContext)
+	default:
+		err = e.invalidAction()
+	}
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+	ch <- e.event(ifc.EventCompleted, completed, nil)
+}
+
+// dryRunMessage describes what running the action against kubeContext
+// would do
+func (e *Executor) dryRunMessage(kubeContext string) string {
+	if e.cfg.Action == ActionMove {
+		return fmt.Sprintf("Cluster API objects would be moved from context %s to context %s (dry run)",
+			kubeContext, e.cfg.TargetContext)
+	}
+	return fmt.Sprintf("Providers of context %s would be initialized (dry run)", kubeContext)
+}
+
+func (e *Executor) event(eventType ifc.EventType, message string, err error) ifc.Event {
+	return ifc.Event{Type: eventType, Phase: e.config.PhaseName, Message: message, Err: err}
+}
+
+// Render writes the documents of the phase
+func (e *Executor) Render(out io.Writer) error {
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		return err
+	}
+	return b.Write(out)
+}
+
+// Validate checks the action of the phase and that its documents have a
+// Clusterctl document
+func (e *Executor) Validate() error {
+	switch e.cfg.Action {
+	case ActionInit:
+	case ActionMove:
+		if e.cfg.TargetContext == "" {
+			return e.invalidConfig("targetContext is required to move")
+		}
+		if _, err := e.config.Settings.Config.GetContext(e.cfg.TargetContext); err != nil {
+			return err
+		}
+	default:
+		return e.invalidAction()
+	}
+
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		return err
+	}
+	_, err = b.SelectOne(document.NewClusterctlSelector())
+	return err
+}
+
+// Rollback is not supported, clusterctl has no way to undo an init or a
+// move
+func (e *Executor) Rollback(ifc.RollbackOptions) error {
+	return ifc.ErrRollbackNotSupported{Phase: e.config.PhaseName, Executor: Name}
+}
+
+func (e *Executor) invalidAction() error {
+	return e.invalidConfig(fmt.Sprintf("unknown action %q, must be %q or %q", e.cfg.Action, ActionInit, ActionMove))
+}
+
+func (e *Executor) invalidConfig(reason string) error {
+	return ifc.ErrInvalidExecutorConfig{Phase: e.config.PhaseName, Executor: Name, Reason: reason}
+}
@@ -0,0 +1,128 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package clusterctl_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/clusterctl"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func executorConfig(entryPoint, cfg string) ifc.ExecutorConfig {
+	return ifc.ExecutorConfig{
+		PhaseName:    "clusterctl-init",
+		ExecutorName: clusterctl.Name,
+		Config:       []byte(cfg),
+		Settings:     &environment.AirshipCTLSettings{Config: testutil.DummyConfig()},
+		EntryPoint:   entryPoint,
+		Out:          &bytes.Buffer{},
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := clusterctl.New(executorConfig("testdata/clusterctl", `{"action": ["init"]}`))
+	assert.IsType(t, ifc.ErrInvalidExecutorConfig{}, err)
+
+	_, err = clusterctl.New(executorConfig("testdata/clusterctl", `{"action": "move", "target": "target"}`))
+	assert.IsType(t, ifc.ErrInvalidExecutorConfig{}, err)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		entryPoint  string
+		config      string
+		expectedErr error
+	}{
+		{
+			name:       "init",
+			entryPoint: "testdata/clusterctl",
+			config:     `{"action": "init"}`,
+		},
+		{
+			name:       "move",
+			entryPoint: "testdata/clusterctl",
+			config:     `{"action": "move", "targetContext": "dummy_context"}`,
+		},
+		{
+			name:       "no-clusterctl-document",
+			entryPoint: "testdata/no-clusterctl",
+			config:     `{"action": "init"}`,
+			expectedErr: document.ErrDocNotFound{
+				Selector: document.NewClusterctlSelector(),
+			},
+		},
+		{
+			name:       "move-without-target",
+			entryPoint: "testdata/clusterctl",
+			config:     `{"action": "move"}`,
+			expectedErr: ifc.ErrInvalidExecutorConfig{
+				Phase:    "clusterctl-init",
+				Executor: clusterctl.Name,
+				Reason:   "targetContext is required to move",
+			},
+		},
+		{
+			name:        "move-to-missing-context",
+			entryPoint:  "testdata/clusterctl",
+			config:      `{"action": "move", "targetContext": "missing"}`,
+			expectedErr: config.ErrMissingConfig{What: "Context with name 'missing'"},
+		},
+		{
+			name:       "unknown-action",
+			entryPoint: "testdata/clusterctl",
+			config:     `{"action": "delete"}`,
+			expectedErr: ifc.ErrInvalidExecutorConfig{
+				Phase:    "clusterctl-init",
+				Executor: clusterctl.Name,
+				Reason:   `unknown action "delete", must be "init" or "move"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			executor, err := clusterctl.New(executorConfig(tt.entryPoint, tt.config))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedErr, executor.Validate())
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	executor, err := clusterctl.New(executorConfig("testdata/clusterctl", `{"action": "init"}`))
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, executor.Render(out))
+	assert.Contains(t, out.String(), "name: clusterctl-v1")
+}
+
+func TestRollback(t *testing.T) {
+	executor, err := clusterctl.New(executorConfig("testdata/clusterctl", `{"action": "init"}`))
+	require.NoError(t, err)
+	assert.Equal(t, ifc.ErrRollbackNotSupported{Phase: "clusterctl-init", Executor: clusterctl.Name},
+		executor.Rollback(ifc.RollbackOptions{}))
+}
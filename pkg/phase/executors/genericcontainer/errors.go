@@ -0,0 +1,40 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package genericcontainer
+
+import (
+	"fmt"
+)
+
+// ErrInvalidOutput is returned when the output of a container isn't a
+// ResourceList
+type ErrInvalidOutput struct {
+	Image  string
+	Reason string
+}
+
+func (e ErrInvalidOutput) Error() string {
+	return fmt.Sprintf("output of image %s is not a valid ResourceList: %s", e.Image, e.Reason)
+}
+
+// ErrFunctionFailed is returned when a container reports an error result
+type ErrFunctionFailed struct {
+	Image   string
+	Message string
+}
+
+func (e ErrFunctionFailed) Error() string {
+	return fmt.Sprintf("image %s failed: %s", e.Image, e.Message)
+}
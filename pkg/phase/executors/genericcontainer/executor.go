@@ -0,0 +1,269 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package genericcontainer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/container"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// Name is the name of the executor running a container, as a KRM function,
+// against the documents of a phase
+const Name = "GenericContainer"
+
+// DefaultDriver is the container runtime used when the executorConfig
+// doesn't name one
+const DefaultDriver = "docker"
+
+// Values of the severity of the results of a function
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Config is the executorConfig of phases run by the GenericContainer
+// executor
+type Config struct {
+	// Image is the container image of the function
+	Image string `json:"image"`
+	// Driver is the container runtime running the image, docker by default
+	Driver string `json:"driver,omitempty"`
+	// Cmd overrides the default command of the image
+	Cmd []string `json:"cmd,omitempty"`
+	// Env are the environment variables of the container, as KEY=value
+	Env []string `json:"env,omitempty"`
+	// Mounts are the volumes of the container, as hostPath:containerPath
+	Mounts []string `json:"mounts,omitempty"`
+	// FunctionConfig is given to the function as the functionConfig of the
+	// ResourceList
+	FunctionConfig json.RawMessage `json:"config,omitempty"`
+}
+
+// ResourceList is read and written by functions on their standard input and
+// output, as described by the KRM functions specification
+type ResourceList struct {
+	APIVersion     string                   `json:"apiVersion"`
+	Kind           string                   `json:"kind"`
+	Items          []map[string]interface{} `json:"items"`
+	FunctionConfig json.RawMessage          `json:"functionConfig,omitempty"`
+	Results        []Result                 `json:"results,omitempty"`
+}
+
+// Result is reported by a function about its run
+type Result struct {
+	Message string `json:"message"`
+	// Severity is error, warning or info, error if empty
+	Severity string `json:"severity,omitempty"`
+}
+
+// ContainerFactory returns the container running the image url with driver
+type ContainerFactory func(ctx *context.Context, driver string, url string) (container.Container, error)
+
+// Executor runs a container against the documents of a phase
+type Executor struct {
+	config           ifc.ExecutorConfig
+	cfg              Config
+	containerFactory ContainerFactory
+}
+
+// RegisterExecutor registers the GenericContainer executor
+func RegisterExecutor(registry map[string]ifc.ExecutorFactory) {
+	registry[Name] = New
+}
+
+// New returns an Executor running the container of the phase of config
+func New(config ifc.ExecutorConfig) (ifc.Executor, error) {
+	return NewExecutor(config, container.NewContainer)
+}
+
+// NewExecutor returns an Executor running the container of the phase of
+// config, created by factory
+func NewExecutor(config ifc.ExecutorConfig, factory ContainerFactory) (*Executor, error) {
+	e := &Executor{config: config, containerFactory: factory}
+	if err := ifc.DecodeConfig(config, &e.cfg); err != nil {
+		return nil, err
+	}
+	if e.cfg.Driver == "" {
+		e.cfg.Driver = DefaultDriver
+	}
+	return e, nil
+}
+
+// Run runs the container with the documents of the phase as its input. The
+// items of the ResourceList written by the container are written to the
+// output of the executor, and the results it reports are sent as events.
+func (e *Executor) Run(ch chan<- ifc.Event) {
+	defer close(ch)
+
+	input, err := e.resourceList()
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+	if e.config.DryRun {
+		ch <- e.event(ifc.EventCompleted, fmt.Sprintf("Image %s would be run with %d documents (dry run)",
+			e.cfg.Image, len(input.Items)), nil)
+		return
+	}
+
+	ch <- e.event(ifc.EventStarted, fmt.Sprintf("Running image %s", e.cfg.Image), nil)
+	output, err := e.run(input)
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+
+	for _, result := range output.Results {
+		switch result.Severity {
+		case SeverityWarning:
+			ch <- e.event(ifc.EventWarning, "Warning: "+result.Message, nil)
+		case SeverityInfo:
+			log.Print(result.Message)
+		default:
+			err = ErrFunctionFailed{Image: e.cfg.Image, Message: result.Message}
+		}
+	}
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+	if err = writeItems(e.config.Out, output.Items); err != nil {
+		ch <- e.event(ifc.EventError, "", err)
+		return
+	}
+	ch <- e.event(ifc.EventCompleted, fmt.Sprintf("Image %s run", e.cfg.Image), nil)
+}
+
+func (e *Executor) event(eventType ifc.EventType, message string, err error) ifc.Event {
+	return ifc.Event{Type: eventType, Phase: e.config.PhaseName, Message: message, Err: err}
+}
+
+// run runs the container with input and decodes the ResourceList it writes.
+// The container is kept for debugging when it fails or with debug set.
+func (e *Executor) run(input *ResourceList) (*ResourceList, error) {
+	in, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	cnt, err := e.containerFactory(&ctx, e.cfg.Driver, e.cfg.Image)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := cnt.RunCommandOutput(e.cfg.Cmd, bytes.NewReader(in), e.cfg.Mounts, e.cfg.Env)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.config.Settings.Debug {
+		log.Debugf("Debug flag is set. Container %s stopped but not deleted.", cnt.GetID())
+	} else if err = cnt.RmContainer(); err != nil {
+		return nil, err
+	}
+
+	output := &ResourceList{}
+	if err = yaml.Unmarshal(out, output); err != nil {
+		return nil, ErrInvalidOutput{Image: e.cfg.Image, Reason: err.Error()}
+	}
+	if output.Kind != "ResourceList" {
+		return nil, ErrInvalidOutput{Image: e.cfg.Image, Reason: fmt.Sprintf("kind %q is not ResourceList", output.Kind)}
+	}
+	return output, nil
+}
+
+// resourceList returns the ResourceList given to the container, with the
+// documents of the phase as its items
+func (e *Executor) resourceList() (*ResourceList, error) {
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := b.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ResourceList{
+		APIVersion:     "config.kubernetes.io/v1alpha1",
+		Kind:           "ResourceList",
+		Items:          []map[string]interface{}{},
+		FunctionConfig: e.cfg.FunctionConfig,
+	}
+	for _, doc := range docs {
+		item := map[string]interface{}{}
+		if err = doc.ToObject(&item); err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
+
+// writeItems writes the items as a stream of YAML documents
+func writeItems(out io.Writer, items []map[string]interface{}) error {
+	for _, item := range items {
+		data, err := yaml.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render writes the documents given to the container
+func (e *Executor) Render(out io.Writer) error {
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		return err
+	}
+	return b.Write(out)
+}
+
+// Validate checks that the executorConfig names an image and that the
+// documents of the phase can be built
+func (e *Executor) Validate() error {
+	if e.cfg.Image == "" {
+		return ifc.ErrInvalidExecutorConfig{Phase: e.config.PhaseName, Executor: Name, Reason: "image is required"}
+	}
+	_, err := document.NewBundleByPath(e.config.EntryPoint)
+	return err
+}
+
+// Rollback is not supported, what the container did is unknown to the
+// executor
+func (e *Executor) Rollback(ifc.RollbackOptions) error {
+	return ifc.ErrRollbackNotSupported{Phase: e.config.PhaseName, Executor: Name}
+}
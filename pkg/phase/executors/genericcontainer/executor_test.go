@@ -0,0 +1,201 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package genericcontainer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/container"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/genericcontainer"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+type fakeContainer struct {
+	output  string
+	input   []byte
+	cmd     []string
+	removed bool
+}
+
+func (c *fakeContainer) ImagePull() error { return nil }
+
+func (c *fakeContainer) RunCommand([]string, io.Reader, []string, []string, bool) error { return nil }
+
+func (c *fakeContainer) RunCommandOutput(cmd []string, input io.Reader, _ []string, _ []string) (io.ReadCloser, error) {
+	var err error
+	c.cmd = cmd
+	c.input, err = ioutil.ReadAll(input)
+	return ioutil.NopCloser(strings.NewReader(c.output)), err
+}
+
+func (c *fakeContainer) RmContainer() error {
+	c.removed = true
+	return nil
+}
+
+func (c *fakeContainer) GetID() string { return "fake" }
+
+func newExecutor(t *testing.T, cfg string, cnt *fakeContainer,
+	dryRun bool) (*genericcontainer.Executor, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	config := ifc.ExecutorConfig{
+		PhaseName:    "mirror-images",
+		ExecutorName: genericcontainer.Name,
+		Config:       []byte(cfg),
+		Settings:     &environment.AirshipCTLSettings{Config: testutil.DummyConfig()},
+		EntryPoint:   "testdata/bundle",
+		Out:          out,
+		DryRun:       dryRun,
+	}
+	executor, err := genericcontainer.NewExecutor(config,
+		func(_ *context.Context, driver string, url string) (container.Container, error) {
+			assert.Equal(t, genericcontainer.DefaultDriver, driver)
+			assert.Equal(t, "mirror:latest", url)
+			return cnt, nil
+		})
+	require.NoError(t, err)
+	return executor, out
+}
+
+func runEvents(executor ifc.Executor) []ifc.Event {
+	ch := make(chan ifc.Event)
+	go executor.Run(ch)
+	var events []ifc.Event
+	for event := range ch {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestNew(t *testing.T) {
+	_, err := genericcontainer.New(ifc.ExecutorConfig{Config: []byte(`{"image": "mirror", "command": ["run"]}`)})
+	assert.IsType(t, ifc.ErrInvalidExecutorConfig{}, err)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		output         string
+		expectedTypes  []ifc.EventType
+		expectedErr    error
+		expectedOutput string
+	}{
+		{
+			name: "items",
+			output: `apiVersion: config.kubernetes.io/v1alpha1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: mirrored
+results:
+- message: registry is slow
+  severity: warning
+`,
+			expectedTypes:  []ifc.EventType{ifc.EventStarted, ifc.EventWarning, ifc.EventCompleted},
+			expectedOutput: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: mirrored\n",
+		},
+		{
+			name: "error-result",
+			output: `{"apiVersion": "config.kubernetes.io/v1alpha1", "kind": "ResourceList", "items": [],
+"results": [{"message": "registry unreachable"}]}`,
+			expectedTypes: []ifc.EventType{ifc.EventStarted, ifc.EventError},
+			expectedErr:   genericcontainer.ErrFunctionFailed{Image: "mirror:latest", Message: "registry unreachable"},
+		},
+		{
+			name:          "not-a-resource-list",
+			output:        `{"kind": "ConfigMap"}`,
+			expectedTypes: []ifc.EventType{ifc.EventStarted, ifc.EventError},
+			expectedErr: genericcontainer.ErrInvalidOutput{
+				Image:  "mirror:latest",
+				Reason: `kind "ConfigMap" is not ResourceList`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cnt := &fakeContainer{output: tt.output}
+			executor, out := newExecutor(t,
+				`{"image": "mirror:latest", "cmd": ["mirror"], "config": {"registry": "localhost:5000"}}`, cnt, false)
+			events := runEvents(executor)
+
+			var types []ifc.EventType
+			for _, event := range events {
+				types = append(types, event.Type)
+			}
+			assert.Equal(t, tt.expectedTypes, types)
+			assert.Equal(t, tt.expectedErr, events[len(events)-1].Err)
+			assert.Equal(t, tt.expectedOutput, out.String())
+			assert.Equal(t, []string{"mirror"}, cnt.cmd)
+			assert.True(t, cnt.removed)
+
+			input := genericcontainer.ResourceList{}
+			require.NoError(t, json.Unmarshal(cnt.input, &input))
+			assert.Equal(t, "ResourceList", input.Kind)
+			assert.JSONEq(t, `{"registry": "localhost:5000"}`, string(input.FunctionConfig))
+			require.Len(t, input.Items, 1)
+			assert.Equal(t, "ConfigMap", input.Items[0]["kind"])
+		})
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	cnt := &fakeContainer{}
+	executor, _ := newExecutor(t, `{"image": "mirror:latest"}`, cnt, true)
+
+	events := runEvents(executor)
+	require.Len(t, events, 1)
+	assert.Equal(t, ifc.EventCompleted, events[0].Type)
+	assert.Nil(t, cnt.input)
+}
+
+func TestValidate(t *testing.T) {
+	executor, _ := newExecutor(t, `{}`, &fakeContainer{}, false)
+	assert.Equal(t, ifc.ErrInvalidExecutorConfig{
+		Phase:    "mirror-images",
+		Executor: genericcontainer.Name,
+		Reason:   "image is required",
+	}, executor.Validate())
+
+	executor, _ = newExecutor(t, `{"image": "mirror:latest"}`, &fakeContainer{}, false)
+	assert.NoError(t, executor.Validate())
+}
+
+func TestRender(t *testing.T) {
+	executor, _ := newExecutor(t, `{"image": "mirror:latest"}`, &fakeContainer{}, false)
+	out := &bytes.Buffer{}
+	require.NoError(t, executor.Render(out))
+	assert.Contains(t, out.String(), "name: images")
+}
+
+func TestRollback(t *testing.T) {
+	executor, _ := newExecutor(t, `{"image": "mirror:latest"}`, &fakeContainer{}, false)
+	assert.Equal(t, ifc.ErrRollbackNotSupported{Phase: "mirror-images", Executor: genericcontainer.Name},
+		executor.Rollback(ifc.RollbackOptions{}))
+}
@@ -0,0 +1,332 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetesapply
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/phase/apply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/pkg/phase/rollback"
+)
+
+// Name is the name of the executor applying the documents of a phase to its
+// cluster, as phase apply does
+const Name = "KubernetesApply"
+
+// Config is the executorConfig of phases run by the KubernetesApply executor
+type Config struct {
+	Prune      bool `json:"prune,omitempty"`
+	ServerSide bool `json:"serverSide,omitempty"`
+	Wait       bool `json:"wait,omitempty"`
+	// WaitTimeout is how long to wait for each resource with wait, 5m by
+	// default
+	WaitTimeout *metav1.Duration `json:"waitTimeout,omitempty"`
+	// MaxParallel is how many independent documents are applied at the same
+	// time, 1 by default
+	MaxParallel int `json:"maxParallel,omitempty"`
+}
+
+// Executor applies the documents of a phase
+type Executor struct {
+	config  ifc.ExecutorConfig
+	options *apply.Options
+}
+
+// RegisterExecutor registers the KubernetesApply executor
+func RegisterExecutor(registry map[string]ifc.ExecutorFactory) {
+	registry[Name] = New
+}
+
+// New returns an Executor applying the documents of the phase of config
+func New(config ifc.ExecutorConfig) (ifc.Executor, error) {
+	cfg := Config{MaxParallel: 1}
+	if err := ifc.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+
+	options := apply.NewOptions(config.Settings)
+	options.PhaseName = config.PhaseName
+	options.EntryPoint = config.EntryPoint
+	options.Prune = cfg.Prune
+	options.ServerSide = cfg.ServerSide
+	options.FieldManager = "airshipctl"
+	options.Validate = true
+	options.Wait = cfg.Wait
+	options.WaitTimeout = 5 * time.Minute
+	if cfg.WaitTimeout != nil {
+		options.WaitTimeout = cfg.WaitTimeout.Duration
+	}
+	options.MaxParallel = cfg.MaxParallel
+	options.Retries = client.DefaultRetryOptions().Retries
+	options.ProgressOut = config.Out
+	options.CreateNamespaces = config.Settings.CreateNamespaces
+	options.DryRun = config.Settings.DryRun
+	// Dry runs of the engine check the documents against the cluster
+	if config.DryRun {
+		options.DryRun = false
+		options.ServerDryRun = true
+	}
+	return &Executor{config: config, options: options}, nil
+}
+
+// Run applies the documents, sending an event for every resource the apply
+// handled
+func (e *Executor) Run(ch chan<- ifc.Event) {
+	defer close(ch)
+
+	kclient, err := e.config.ClientFactory(e.config.Settings)
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", "", err)
+		return
+	}
+	e.options.Client = kclient
+
+	ch <- e.event(ifc.EventStarted, "", fmt.Sprintf("Applying the documents of phase %s", e.config.PhaseName), nil)
+	result, err := e.options.Run()
+	if result != nil {
+		for _, res := range result.Resources {
+			ch <- e.resourceEvent(res, result.DryRun)
+		}
+		for _, warning := range result.Warnings {
+			ch <- e.event(ifc.EventWarning, "", "Warning: "+warning, nil)
+		}
+	}
+	if err != nil {
+		ch <- e.event(ifc.EventError, "", "", err)
+		return
+	}
+	ch <- e.event(ifc.EventCompleted, "", fmt.Sprintf("Phase %s applied", e.config.PhaseName), nil)
+}
+
+func (e *Executor) event(eventType ifc.EventType, resource, message string, err error) ifc.Event {
+	return ifc.Event{Type: eventType, Phase: e.config.PhaseName, Resource: resource, Message: message, Err: err}
+}
+
+func (e *Executor) resourceEvent(res kubectl.ResourceResult, dryRun bool) ifc.Event {
+	resource := res.Kind + "/" + res.Name
+	message := resource
+	if res.Namespace != "" {
+		resource = res.Kind + "/" + res.Namespace + "/" + res.Name
+		message += " in namespace " + res.Namespace
+	}
+	message += " " + res.Action
+	if dryRun {
+		message += " (dry run)"
+	}
+	return e.event(ifc.EventResourceApplied, resource, message, nil)
+}
+
+// Render writes the documents deployed to the cluster
+func (e *Executor) Render(out io.Writer) error {
+	b, err := e.bundle()
+	if err != nil {
+		return err
+	}
+	return b.Write(out)
+}
+
+// Validate checks that the phase has documents to deploy and that their
+// dependencies can be ordered
+func (e *Executor) Validate() error {
+	if err := kubectl.ValidateDeploymentID(e.config.PhaseName); err != nil {
+		return err
+	}
+	b, err := e.bundle()
+	if err != nil {
+		return err
+	}
+	docs, err := b.GetAllDocuments()
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return document.ErrDocNotFound{Selector: document.NewDeployToK8sSelector()}
+	}
+	_, err = document.NewDependencyGraph(docs)
+	return err
+}
+
+// Rollback restores the resources of the phase to their state before the
+// last apply, or deletes the ones deployed by the phase with Delete
+func (e *Executor) Rollback(options ifc.RollbackOptions) error {
+	kclient, err := e.config.ClientFactory(e.config.Settings)
+	if err != nil {
+		return err
+	}
+	if options.Delete {
+		return e.delete(kclient)
+	}
+
+	o := rollback.NewOptions(e.config.Settings)
+	o.Client = kclient
+	o.PhaseName = e.config.PhaseName
+	return o.Run()
+}
+
+// delete deletes the resources of the documents of the phase which have the
+// DeploymentLabel of the phase. Resources are deleted before the ones they
+// depend on, e.g. custom resources before their CustomResourceDefinition.
+func (e *Executor) delete(kclient client.Interface) error {
+	b, err := e.bundle()
+	if err != nil {
+		return err
+	}
+	docs, err := b.GetAllDocuments()
+	if err != nil {
+		return err
+	}
+	graph, err := document.NewDependencyGraph(docs)
+	if err != nil {
+		return err
+	}
+
+	for i := len(graph.Waves) - 1; i >= 0; i-- {
+		for _, doc := range graph.Waves[i] {
+			if err = e.deleteResource(kclient, doc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteResource deletes the resource of doc if it was deployed by the
+// phase. Documents without namespace are looked up in the namespace of the
+// kubeconfig context, where they were applied.
+func (e *Executor) deleteResource(kclient client.Interface, doc document.Document) error {
+	namespace := doc.GetNamespace()
+	if namespace == "" {
+		namespace = kclient.Namespace()
+	}
+	gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+	resource := doc.GetKind() + "/" + doc.GetName()
+
+	obj, err := kclient.Get(gvk, namespace, doc.GetName())
+	switch {
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	if obj.GetLabels()[kubectl.DeploymentLabel] != e.config.PhaseName {
+		log.Printf("%s was not deployed by phase %s, keeping it", resource, e.config.PhaseName)
+		return nil
+	}
+
+	if e.config.Settings.DryRun {
+		fmt.Fprintf(e.config.Out, "%s deleted (dry run)\n", resource)
+		return nil
+	}
+	propagation := metav1.DeletePropagationBackground
+	err = kclient.Delete(gvk, namespace, doc.GetName(), &metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	fmt.Fprintf(e.config.Out, "%s deleted\n", resource)
+	return nil
+}
+
+// Status looks up the documents of the phase deployed to its cluster. A
+// resource only counts as deployed by the phase if it has the
+// DeploymentLabel of the phase.
+func (e *Executor) Status() (*ifc.Status, error) {
+	b, err := e.bundle()
+	if err != nil {
+		return nil, err
+	}
+	docs, err := b.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	kclient, err := e.config.ClientFactory(e.config.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ifc.Status{}
+	for _, doc := range docs {
+		rs, err := e.resourceStatus(kclient, doc)
+		if err != nil {
+			return nil, err
+		}
+		switch rs.Status {
+		case status.Current:
+			result.Present++
+		case status.NotFound:
+			result.Missing++
+		default:
+			result.Degraded++
+		}
+		result.Resources = append(result.Resources, rs)
+	}
+	return result, nil
+}
+
+// resourceStatus returns the state of the resource of doc in the cluster,
+// looking documents without namespace up where they were applied
+func (e *Executor) resourceStatus(kclient client.Interface, doc document.Document) (ifc.ResourceStatus, error) {
+	rs := ifc.ResourceStatus{
+		Kind:      doc.GetKind(),
+		Namespace: doc.GetNamespace(),
+		Name:      doc.GetName(),
+		Status:    status.NotFound,
+	}
+	namespace := doc.GetNamespace()
+	if namespace == "" {
+		namespace = kclient.Namespace()
+	}
+	gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+	obj, err := kclient.Get(gvk, namespace, doc.GetName())
+	switch {
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		return rs, nil
+	case err != nil:
+		return rs, err
+	}
+
+	if obj.GetLabels()[kubectl.DeploymentLabel] != e.config.PhaseName {
+		rs.Message = fmt.Sprintf("not deployed by phase %s", e.config.PhaseName)
+		return rs, nil
+	}
+	computed, err := status.Compute(obj)
+	if err != nil {
+		return rs, err
+	}
+	rs.Status = computed.Status
+	rs.Message = computed.Message
+	return rs, nil
+}
+
+// bundle returns the documents of the phase deployed to the cluster
+func (e *Executor) bundle() (document.Bundle, error) {
+	b, err := document.NewBundleByPath(e.config.EntryPoint)
+	if err != nil {
+		return nil, err
+	}
+	return b.SelectBundle(document.NewDeployToK8sSelector())
+}
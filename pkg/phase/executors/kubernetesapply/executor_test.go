@@ -0,0 +1,282 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetesapply_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func executorConfig(phaseName, entryPoint, config string) ifc.ExecutorConfig {
+	return ifc.ExecutorConfig{
+		PhaseName:    phaseName,
+		ExecutorName: kubernetesapply.Name,
+		Config:       []byte(config),
+		Settings:     &environment.AirshipCTLSettings{Config: testutil.DummyConfig()},
+		EntryPoint:   entryPoint,
+		Out:          &bytes.Buffer{},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		expectedErr bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:   "configured",
+			config: `{"wait": true, "waitTimeout": "10m", "maxParallel": 4}`,
+		},
+		{
+			name:        "invalid",
+			config:      `{"wait": "yes"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown-field",
+			config:      `{"waitTimout": "10m"}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			executor, err := kubernetesapply.New(executorConfig("initinfra", "testdata/initinfra", tt.config))
+			if tt.expectedErr {
+				assert.IsType(t, ifc.ErrInvalidExecutorConfig{}, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, executor)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		phaseName   string
+		entryPoint  string
+		expectedErr error
+	}{
+		{
+			name:       "valid",
+			phaseName:  "initinfra",
+			entryPoint: "testdata/initinfra",
+		},
+		{
+			name:        "no-documents",
+			phaseName:   "no-deploy",
+			entryPoint:  "testdata/no-deploy",
+			expectedErr: document.ErrDocNotFound{Selector: document.NewDeployToK8sSelector()},
+		},
+		{
+			name:        "missing-dependency",
+			phaseName:   "missing-dependency",
+			entryPoint:  "testdata/missing-dependency",
+			expectedErr: document.ErrDependencyNotFound{DocName: "initinfra-config", Reference: "ConfigMap/missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			executor, err := kubernetesapply.New(executorConfig(tt.phaseName, tt.entryPoint, ""))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedErr, executor.Validate())
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	executor, err := kubernetesapply.New(executorConfig("initinfra", "testdata/initinfra", ""))
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, executor.Render(out))
+	assert.Contains(t, out.String(), "name: initinfra-config")
+	assert.NotContains(t, out.String(), "name: local-config")
+}
+
+func TestRunClientError(t *testing.T) {
+	errClient := errors.New("no cluster")
+	config := executorConfig("initinfra", "testdata/initinfra", "")
+	config.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+		return nil, errClient
+	}
+	executor, err := kubernetesapply.New(config)
+	require.NoError(t, err)
+
+	ch := make(chan ifc.Event)
+	go executor.Run(ch)
+	var events []ifc.Event
+	for event := range ch {
+		events = append(events, event)
+	}
+	assert.Equal(t, []ifc.Event{{Type: ifc.EventError, Phase: "initinfra", Err: errClient}}, events)
+}
+
+// statusObjects are the live resources of the documents of testdata/status
+func statusObjects() []runtime.Object {
+	labels := map[string]interface{}{kubectl.DeploymentLabel: "initinfra"}
+	return []runtime.Object{
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "deployed", "namespace": "default", "labels": labels},
+		}},
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "not-deployed", "namespace": "default"},
+		}},
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "default", "labels": labels},
+			"spec":       map[string]interface{}{"replicas": int64(2)},
+			"status":     map[string]interface{}{"replicas": int64(2), "readyReplicas": int64(1)},
+		}},
+	}
+}
+
+func statusExecutorConfig() ifc.ExecutorConfig {
+	config := executorConfig("initinfra", "testdata/status", "")
+	config.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+		return fake.NewClient(fake.WithDynamicObjects(statusObjects()...)), nil
+	}
+	return config
+}
+
+func TestStatus(t *testing.T) {
+	executor, err := kubernetesapply.New(statusExecutorConfig())
+	require.NoError(t, err)
+
+	reporter, ok := executor.(ifc.StatusReporter)
+	require.True(t, ok)
+	result, err := reporter.Status()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Present)
+	assert.Equal(t, 2, result.Missing)
+	assert.Equal(t, 1, result.Degraded)
+	byName := map[string]ifc.ResourceStatus{}
+	for _, rs := range result.Resources {
+		byName[rs.Name] = rs
+	}
+	require.Len(t, byName, 4)
+	assert.Equal(t, status.Current, byName["deployed"].Status)
+	assert.Equal(t, ifc.ResourceStatus{
+		Kind:      "ConfigMap",
+		Namespace: "default",
+		Name:      "not-deployed",
+		Status:    status.NotFound,
+		Message:   "not deployed by phase initinfra",
+	}, byName["not-deployed"])
+	assert.Equal(t, status.NotFound, byName["absent"].Status)
+	assert.Equal(t, status.InProgress, byName["web"].Status)
+}
+
+func TestContextNamespace(t *testing.T) {
+	// Documents without namespace are applied to the namespace of the kubeconfig context
+	configMap := func(namespace string, labels map[string]string) runtime.Object {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "settings", "namespace": namespace},
+		}}
+		obj.SetLabels(labels)
+		return obj
+	}
+	kclient := fake.NewClient(fake.WithNamespace("target-infra"), fake.WithDynamicObjects(
+		configMap("target-infra", map[string]string{kubectl.DeploymentLabel: "initinfra"}),
+		configMap("default", nil),
+	))
+	config := executorConfig("initinfra", "testdata/context-namespace", "")
+	config.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+		return kclient, nil
+	}
+	out := &bytes.Buffer{}
+	config.Out = out
+	executor, err := kubernetesapply.New(config)
+	require.NoError(t, err)
+
+	result, err := executor.(ifc.StatusReporter).Status()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Present)
+
+	require.NoError(t, executor.Rollback(ifc.RollbackOptions{Delete: true}))
+	assert.Equal(t, "ConfigMap/settings deleted\n", out.String())
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	_, err = kclient.Get(gvk, "target-infra", "settings")
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = kclient.Get(gvk, "default", "settings")
+	assert.NoError(t, err)
+}
+
+func TestRollbackDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		expectedOut string
+	}{
+		{
+			name:        "delete",
+			expectedOut: "ConfigMap/deployed deleted\nDeployment/web deleted\n",
+		},
+		{
+			name:        "dry-run",
+			dryRun:      true,
+			expectedOut: "ConfigMap/deployed deleted (dry run)\nDeployment/web deleted (dry run)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			config := statusExecutorConfig()
+			config.Settings.DryRun = tt.dryRun
+			out := &bytes.Buffer{}
+			config.Out = out
+			executor, err := kubernetesapply.New(config)
+			require.NoError(t, err)
+
+			require.NoError(t, executor.Rollback(ifc.RollbackOptions{Delete: true}))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
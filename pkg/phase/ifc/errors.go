@@ -0,0 +1,42 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ifc
+
+import (
+	"fmt"
+)
+
+// ErrInvalidExecutorConfig is returned when the executorConfig of a phase
+// isn't valid for its executor
+type ErrInvalidExecutorConfig struct {
+	Phase    string
+	Executor string
+	Reason   string
+}
+
+func (e ErrInvalidExecutorConfig) Error() string {
+	return fmt.Sprintf("invalid executorConfig of phase %s for executor %s: %s", e.Phase, e.Executor, e.Reason)
+}
+
+// ErrRollbackNotSupported is returned when the executor of a phase can't
+// roll it back
+type ErrRollbackNotSupported struct {
+	Phase    string
+	Executor string
+}
+
+func (e ErrRollbackNotSupported) Error() string {
+	return fmt.Sprintf("executor %s of phase %s doesn't support rollback", e.Executor, e.Phase)
+}
@@ -0,0 +1,54 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ifc
+
+// EventType is the kind of progress an Event reports
+type EventType string
+
+// Types of the events sent about the progress of a phase
+const (
+	// EventStarted is sent when the executor starts running the phase
+	EventStarted EventType = "started"
+	// EventResourceApplied is sent for every resource the phase deployed
+	EventResourceApplied EventType = "resourceApplied"
+	// EventWarning is sent for problems which didn't fail the phase
+	EventWarning EventType = "warning"
+	// EventWaiting is sent while the phase waits for its resources
+	EventWaiting EventType = "waiting"
+	// EventError is sent when the phase failed, with Err set
+	EventError EventType = "error"
+	// EventCompleted is sent when the executor ran the phase successfully
+	EventCompleted EventType = "completed"
+)
+
+// Event is sent by executors about the progress of a phase
+type Event struct {
+	Type EventType
+	// Phase is the name of the phase the event is about
+	Phase string
+	// Resource identifies the resource the event is about, if any, as
+	// Kind/name or Kind/namespace/name
+	Resource string
+	// Message describes the progress of the phase
+	Message string
+	// Err is set when the phase failed
+	Err error
+}
+
+// EventProcessor handles the events of the phases run by the engine, e.g. to
+// report their progress
+type EventProcessor interface {
+	Process(event Event) error
+}
@@ -0,0 +1,117 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ifc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+)
+
+// Executor runs a phase. Executors are registered by name and Phase
+// documents name the executor running them.
+type Executor interface {
+	// Run runs the phase, sending events about its progress to ch. It
+	// closes ch once the phase is done.
+	Run(ch chan<- Event)
+	// Render writes the documents of the phase to out
+	Render(out io.Writer) error
+	// Validate checks that the phase can be run, without reaching any
+	// cluster
+	Validate() error
+	// Rollback undoes what running the phase did to its cluster
+	Rollback(options RollbackOptions) error
+}
+
+// RollbackOptions configure how a phase is rolled back
+type RollbackOptions struct {
+	// Delete deletes the resources deployed by the phase instead of
+	// restoring their state before the phase was last run
+	Delete bool
+}
+
+// ExecutorConfig is given to executors to run a phase
+type ExecutorConfig struct {
+	PhaseName string
+	// ExecutorName is the name the executor is registered with
+	ExecutorName string
+	// Config is the executorConfig of the Phase document, its fields depend
+	// on the executor
+	Config json.RawMessage
+	// Settings reach the cluster the phase is run against
+	Settings *environment.AirshipCTLSettings
+	// EntryPoint is the path of the kustomization of the documents of the
+	// phase
+	EntryPoint string
+	// ClientFactory returns clients of the cluster the phase is run against
+	ClientFactory client.Factory
+	// Out receives the detailed output of the executor, e.g. the result of
+	// an apply
+	Out io.Writer
+	// DryRun asks the executor to report what running the phase would do
+	// without changing anything, checking it against the cluster where it
+	// can, e.g. with a server side dry run
+	DryRun bool
+}
+
+// ExecutorFactory returns an executor running the phase of config.
+// Functions of such type are used in the executor registry.
+type ExecutorFactory func(config ExecutorConfig) (Executor, error)
+
+// DecodeConfig decodes the executorConfig of config into v, which is
+// expected to be the configuration type of the executor. Fields v doesn't
+// have are errors, so that misspelled options aren't silently ignored.
+func DecodeConfig(config ExecutorConfig, v interface{}) error {
+	if len(config.Config) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config.Config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return ErrInvalidExecutorConfig{Phase: config.PhaseName, Executor: config.ExecutorName, Reason: err.Error()}
+	}
+	return nil
+}
+
+// StatusReporter is implemented by executors which can tell how much of a
+// phase is deployed to its cluster
+type StatusReporter interface {
+	// Status looks up the resources of the phase in its cluster
+	Status() (*Status, error)
+}
+
+// ResourceStatus is the state of a resource of a phase in its cluster
+type ResourceStatus struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Status    status.Status `json:"status"`
+	Message   string        `json:"message,omitempty"`
+}
+
+// Status counts the resources of a phase by their state in its cluster.
+// Present resources are deployed by the phase and Current, degraded ones are
+// deployed by the phase but not Current, and missing ones don't exist or
+// were not deployed by the phase.
+type Status struct {
+	Present   int              `json:"present"`
+	Missing   int              `json:"missing"`
+	Degraded  int              `json:"degraded"`
+	Resources []ResourceStatus `json:"resources,omitempty"`
+}
@@ -0,0 +1,168 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Output formats of phase lists
+const (
+	OutputTable = "table"
+	OutputYAML  = "yaml"
+)
+
+// ListEntry describes a phase of a PhaseList
+type ListEntry struct {
+	Name     string `json:"name"`
+	Executor string `json:"executor"`
+	// Cluster is the context of the cluster the phase is run against
+	Cluster            string   `json:"cluster"`
+	DocumentEntryPoint string   `json:"documentEntryPoint"`
+	DependsOn          []string `json:"dependsOn,omitempty"`
+	Plans              []string `json:"plans,omitempty"`
+}
+
+// PhaseList is the phases declared in the current context, with the plans
+// referencing them
+type PhaseList struct {
+	Phases []ListEntry `json:"phases"`
+}
+
+// ListFilter selects the phases of a PhaseList, empty fields select all
+type ListFilter struct {
+	// Cluster selects the phases run against the cluster of a context
+	Cluster string
+	// Plan selects the phases referenced by a plan
+	Plan string
+}
+
+// NewPhaseList returns the phases declared in the current context selected
+// by filter, sorted by name
+func NewPhaseList(settings *environment.AirshipCTLSettings, filter ListFilter) (*PhaseList, error) {
+	phases, err := List(settings)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := ListPlans(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	referencedBy := map[string][]string{}
+	planFound := false
+	for _, plan := range plans {
+		planFound = planFound || plan.Name == filter.Plan
+		seen := map[string]bool{}
+		for _, name := range plan.PhaseNames() {
+			if !seen[name] {
+				referencedBy[name] = append(referencedBy[name], plan.Name)
+				seen[name] = true
+			}
+		}
+	}
+	if filter.Plan != "" && !planFound {
+		return nil, ErrPlanNotFound{Name: filter.Plan}
+	}
+
+	list := &PhaseList{Phases: []ListEntry{}}
+	for _, p := range phases {
+		entry := ListEntry{
+			Name:               p.Name,
+			Executor:           p.Config.Executor,
+			Cluster:            phaseContext(settings, p),
+			DocumentEntryPoint: p.EntryPoint(),
+			DependsOn:          p.Config.DependsOn,
+			Plans:              referencedBy[p.Name],
+		}
+		sort.Strings(entry.Plans)
+		if filter.Cluster != "" && entry.Cluster != filter.Cluster {
+			continue
+		}
+		if filter.Plan != "" && !contains(entry.Plans, filter.Plan) {
+			continue
+		}
+		list.Phases = append(list.Phases, entry)
+	}
+	sort.Slice(list.Phases, func(i, j int) bool { return list.Phases[i].Name < list.Phases[j].Name })
+	return list, nil
+}
+
+// Print writes the list to w as a table or YAML
+func (l *PhaseList) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return l.printTable(w)
+	case OutputYAML:
+		data, err := yaml.Marshal(l)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (l *PhaseList) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "PHASE\tEXECUTOR\tCLUSTER\tENTRYPOINT\tPLANS")
+	for _, p := range l.Phases {
+		plans := "-"
+		if len(p.Plans) > 0 {
+			plans = strings.Join(p.Plans, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.Name, p.Executor, p.Cluster, p.DocumentEntryPoint, plans)
+	}
+	return tw.Flush()
+}
+
+// phaseContext describes the cluster p is run against: its context, or its
+// workload cluster. Phases whose context can't be resolved are described by
+// how they name their cluster.
+func phaseContext(settings *environment.AirshipCTLSettings, p *Phase) string {
+	if p.Config.WorkloadCluster != nil {
+		return p.Config.WorkloadCluster.String()
+	}
+	context, err := clusterContext(settings, p)
+	switch {
+	case err != nil && p.Config.Cluster != "":
+		return p.Config.Cluster
+	case err != nil:
+		return p.Config.ClusterType
+	case context != "":
+		return context
+	default:
+		return settings.Config.CurrentContext
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+)
+
+func TestNewPhaseList(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+
+	tests := []struct {
+		name          string
+		filter        phase.ListFilter
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name:          "all",
+			expectedNames: []string{"initinfra", "target-initinfra", "unknown-context"},
+		},
+		{
+			name:          "cluster",
+			filter:        phase.ListFilter{Cluster: "dummy_target"},
+			expectedNames: []string{"target-initinfra"},
+		},
+		{
+			name:          "current-cluster",
+			filter:        phase.ListFilter{Cluster: "dummy_cluster"},
+			expectedNames: []string{"initinfra"},
+		},
+		{
+			name:          "plan",
+			filter:        phase.ListFilter{Plan: "initinfra-only"},
+			expectedNames: []string{"initinfra"},
+		},
+		{
+			name:          "cluster-and-plan",
+			filter:        phase.ListFilter{Cluster: "dummy_cluster", Plan: "deploy"},
+			expectedNames: []string{"initinfra"},
+		},
+		{
+			name:        "missing-plan",
+			filter:      phase.ListFilter{Plan: "missing"},
+			expectedErr: phase.ErrPlanNotFound{Name: "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := phase.NewPhaseList(settings, tt.filter)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, p := range list.Phases {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestPhaseListPrint(t *testing.T) {
+	list := &phase.PhaseList{Phases: []phase.ListEntry{
+		{
+			Name:               "initinfra",
+			Executor:           kubernetesapply.Name,
+			Cluster:            "dummy_cluster",
+			DocumentEntryPoint: "initinfra",
+			Plans:              []string{"deploy", "initinfra-only"},
+		},
+		{
+			Name:               "workers",
+			Executor:           kubernetesapply.Name,
+			Cluster:            "dummy_target",
+			DocumentEntryPoint: "target/workers",
+		},
+	}}
+
+	tests := []struct {
+		format      string
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			format: phase.OutputTable,
+			expectedOut: "PHASE       EXECUTOR          CLUSTER         ENTRYPOINT       PLANS\n" +
+				"initinfra   KubernetesApply   dummy_cluster   initinfra        deploy,initinfra-only\n" +
+				"workers     KubernetesApply   dummy_target    target/workers   -\n",
+		},
+		{
+			format: phase.OutputYAML,
+			expectedOut: `phases:
+- cluster: dummy_cluster
+  documentEntryPoint: initinfra
+  executor: KubernetesApply
+  name: initinfra
+  plans:
+  - deploy
+  - initinfra-only
+- cluster: dummy_target
+  documentEntryPoint: target/workers
+  executor: KubernetesApply
+  name: workers
+`,
+		},
+		{
+			format:      "json",
+			expectedErr: phase.ErrInvalidOutputFormat{Format: "json"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			assert.Equal(t, tt.expectedErr, list.Print(out, tt.format))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/phase/events"
+)
+
+// runGroupParallel runs the phases of group as soon as the phases of the
+// group they depend on completed, up to MaxParallel of them at the same time.
+// The output of every phase is written line by line, prefixed by its name.
+// Once a phase failed no other phase is started, the ones running are waited
+// for.
+func (e *Engine) runGroupParallel(plan string, group PhaseGroup, phases map[string]*Phase,
+	checkpoint *Checkpoint, path string) error {
+	mu := &sync.Mutex{}
+	out := &lineWriter{mu: mu, out: e.Out}
+
+	inGroup := map[string]bool{}
+	for _, ref := range group.Phases {
+		inGroup[ref.Name] = true
+	}
+	done := map[string]bool{}
+	var pending []string
+	for _, ref := range group.Phases {
+		if checkpoint.IsCompleted(group.Name, ref.Name) {
+			fmt.Fprintf(out, "Skipping phase %s of group %s, completed by a previous run\n", ref.Name, group.Name)
+			done[ref.Name] = true
+			continue
+		}
+		pending = append(pending, ref.Name)
+	}
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result)
+	running := 0
+	var failed error
+	for len(pending) > 0 || running > 0 {
+		if failed != nil {
+			pending = nil
+		}
+		var waiting []string
+		for _, name := range pending {
+			if running >= e.MaxParallel || !ready(phases[name], inGroup, done) {
+				waiting = append(waiting, name)
+				continue
+			}
+			fmt.Fprintf(out, "Running phase %s of group %s\n", name, group.Name)
+			running++
+			go func(name string) {
+				results <- result{name: name, err: e.runPrefixed(name, mu)}
+			}(name)
+		}
+		pending = waiting
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		if res.err != nil {
+			if failed == nil {
+				failed = ErrPlanFailed{Plan: plan, Group: group.Name, Phase: res.name, Err: res.err}
+			} else {
+				fmt.Fprintf(out, "Phase %s of group %s failed: %v\n", res.name, group.Name, res.err)
+			}
+			continue
+		}
+		done[res.name] = true
+		checkpoint.Completed = append(checkpoint.Completed, CompletedPhase{
+			Group:       group.Name,
+			Name:        res.name,
+			CompletedAt: time.Now().UTC(),
+		})
+		if err := e.saveCheckpoint(checkpoint, path); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// ready tells whether the phases of the group p depends on are done
+func ready(p *Phase, inGroup, done map[string]bool) bool {
+	for _, dep := range p.Config.DependsOn {
+		if inGroup[dep] && !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// runPrefixed runs the phase called name, prefixing every line it writes
+// with its name
+func (e *Engine) runPrefixed(name string, mu *sync.Mutex) error {
+	out := &lineWriter{mu: mu, out: e.Out, prefix: "[" + name + "] "}
+	engine := *e
+	engine.Out = out
+	engine.printer = events.NewPrinter(out)
+	err := engine.run(name)
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// lineWriter writes whole lines to out, each starting with prefix, so that
+// the output of phases run at the same time doesn't mix within lines.
+// lineWriters sharing mu don't write to out at the same time.
+type lineWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write writes the complete lines of p, the rest is kept until the line is
+// completed by the next writes or Flush
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if _, err := fmt.Fprintf(w.out, "%s%s", w.prefix, w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush writes the last line if it wasn't completed
+func (w *lineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+	w.buf = nil
+	return err
+}
@@ -0,0 +1,132 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// concurrencyTracker records how many phases ran at the same time
+type concurrencyTracker struct {
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (c *concurrencyTracker) add(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running += n
+	if c.running > c.max {
+		c.max = c.running
+	}
+}
+
+// slowExecutor is a fakeExecutor taking some time to run its phase
+type slowExecutor struct {
+	*fakeExecutor
+	tracker *concurrencyTracker
+}
+
+func (e *slowExecutor) Run(ch chan<- ifc.Event) {
+	e.tracker.add(1)
+	time.Sleep(20 * time.Millisecond)
+	e.tracker.add(-1)
+	e.fakeExecutor.Run(ch)
+}
+
+// slowEngine returns an Engine running the phases of the dependency site with
+// slowExecutors
+func slowEngine(t *testing.T, ran *[]string, runErrs map[string]error,
+	maxParallel int) (*phase.Engine, *bytes.Buffer, *concurrencyTracker, func(*testing.T)) {
+	t.Helper()
+	engine, out, cleanup := dependencyEngine(t, ran)
+	engine.MaxParallel = maxParallel
+	tracker := &concurrencyTracker{}
+	engine.Executors = map[string]ifc.ExecutorFactory{
+		kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+			return &slowExecutor{
+				fakeExecutor: &fakeExecutor{config: config, runErr: runErrs[config.PhaseName], ran: ran},
+				tracker:      tracker,
+			}, nil
+		},
+	}
+	return engine, out, tracker, cleanup
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRunPlanParallel(t *testing.T) {
+	var ran []string
+	engine, out, tracker, cleanup := slowEngine(t, &ran, nil, 2)
+	defer cleanup(t)
+
+	require.NoError(t, engine.RunPlan("racks", false))
+	assert.Equal(t, 2, tracker.max)
+	require.Len(t, ran, 4)
+	assert.Equal(t, "crds", ran[0])
+	assert.Less(t, indexOf(ran, "rack-a"), indexOf(ran, "rack-a-workloads"))
+
+	assert.Contains(t, out.String(), "Running phase crds of group racks\n[crds] ran crds\n")
+	assert.Contains(t, out.String(), "Running phase rack-b of group racks\n")
+	assert.Contains(t, out.String(), "[rack-b] ran rack-b\n")
+	assert.Contains(t, out.String(), "[rack-a-workloads] ran rack-a-workloads\n")
+	assert.Contains(t, out.String(), "Plan racks completed\n")
+}
+
+func TestRunPlanSequential(t *testing.T) {
+	var ran []string
+	engine, _, tracker, cleanup := slowEngine(t, &ran, nil, 1)
+	defer cleanup(t)
+
+	require.NoError(t, engine.RunPlan("racks", false))
+	assert.Equal(t, 1, tracker.max)
+	assert.Equal(t, []string{"crds", "rack-a", "rack-b", "rack-a-workloads"}, ran)
+}
+
+func TestRunPlanParallelFailure(t *testing.T) {
+	errApply := errors.New("apply failed")
+	var ran []string
+	engine, _, _, cleanup := slowEngine(t, &ran, map[string]error{"rack-a": errApply}, 2)
+	defer cleanup(t)
+
+	assert.Equal(t, phase.ErrPlanFailed{Plan: "racks", Group: "racks", Phase: "rack-a", Err: errApply},
+		engine.RunPlan("racks", false))
+	assert.ElementsMatch(t, []string{"crds", "rack-a", "rack-b"}, ran)
+
+	checkpoint, err := phase.LoadCheckpoint(phase.CheckpointPath(engine.CheckpointDir, "dummy_cluster", "racks"))
+	require.NoError(t, err)
+	assert.True(t, checkpoint.IsCompleted("racks", "crds"))
+	assert.True(t, checkpoint.IsCompleted("racks", "rack-b"))
+	assert.False(t, checkpoint.IsCompleted("racks", "rack-a"))
+}
@@ -0,0 +1,177 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Phase is a step of the deployment of a site, declared by a Phase document.
+// It names the documents of the step, the executor deploying them and the
+// cluster they are deployed to.
+type Phase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Config Config `json:"config"`
+}
+
+// Config is how a phase is run
+type Config struct {
+	// Executor is the name of the executor running the phase
+	Executor string `json:"executor"`
+	// DocumentEntryPoint is the directory of the kustomization of the
+	// documents of the phase, relative to the documents of the cluster type
+	// of the current context. It is the name of the phase if empty.
+	DocumentEntryPoint string `json:"documentEntryPoint,omitempty"`
+	// Cluster is the context of the cluster the phase is run against, the
+	// current context if empty
+	Cluster string `json:"cluster,omitempty"`
+	// ClusterType is the type, ephemeral or target, of the cluster the phase
+	// is run against instead of Cluster. The phase is run against the
+	// context of the cluster of that type named like the cluster of the
+	// current context.
+	ClusterType string `json:"clusterType,omitempty"`
+	// WorkloadCluster is the Cluster API workload cluster the phase is run
+	// against instead of Cluster, its kubeconfig is retrieved from its
+	// management cluster when the phase is run
+	WorkloadCluster *WorkloadCluster `json:"workloadCluster,omitempty"`
+	// DependsOn are the phases which have to be run before this one
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// WaitConditions have to be met by resources of the cluster once the
+	// executor completed, for the phase to complete
+	WaitConditions []WaitCondition `json:"waitConditions,omitempty"`
+	// WaitTimeout is how long to wait for the WaitConditions,
+	// DefaultWaitTimeout if empty
+	WaitTimeout *metav1.Duration `json:"waitTimeout,omitempty"`
+	// ExecutorConfig configures the executor, its fields depend on the
+	// executor
+	ExecutorConfig json.RawMessage `json:"executorConfig,omitempty"`
+}
+
+// EntryPoint returns the directory of the documents of the phase, relative
+// to the documents of the cluster type of the current context
+func (p *Phase) EntryPoint() string {
+	if p.Config.DocumentEntryPoint != "" {
+		return p.Config.DocumentEntryPoint
+	}
+	return p.Name
+}
+
+// Get returns the phase called name, declared in the Phase documents of the
+// current context
+func Get(settings *environment.AirshipCTLSettings, name string) (*Phase, error) {
+	selector := document.NewPhaseSelector().ByName(name)
+	docs, err := phaseDocuments(settings, selector)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, ErrPhaseNotFound{Name: name}
+	case 1:
+		return decodePhase(docs[0])
+	default:
+		return nil, document.ErrMultiDocsFound{Selector: selector}
+	}
+}
+
+// DocumentEntryPoint returns the path of the kustomization of the documents
+// of the phase called name. Phases not declared by a Phase document are the
+// directory called name.
+func DocumentEntryPoint(settings *environment.AirshipCTLSettings, name string) (string, error) {
+	p, err := Get(settings, name)
+	switch err.(type) {
+	case nil:
+		name = p.EntryPoint()
+	case ErrPhaseNotFound:
+	default:
+		return "", err
+	}
+	return settings.CurrentContextEntryPoint(name)
+}
+
+// List returns the phases declared in the Phase documents of the current
+// context
+func List(settings *environment.AirshipCTLSettings) ([]*Phase, error) {
+	docs, err := phaseDocuments(settings, document.NewPhaseSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	phases := make([]*Phase, 0, len(docs))
+	for _, doc := range docs {
+		p, err := decodePhase(doc)
+		if err != nil {
+			return nil, err
+		}
+		phases = append(phases, p)
+	}
+	return phases, nil
+}
+
+// phaseDocuments returns the Phase documents of the current context matching
+// selector, there are none if the current context has no phases directory
+func phaseDocuments(settings *environment.AirshipCTLSettings, selector document.Selector) ([]document.Document, error) {
+	entryPoint, err := settings.CurrentContextEntryPoint(config.PhasesEntryPoint)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(entryPoint); os.IsNotExist(err) {
+		return nil, nil
+	}
+	b, err := document.NewBundleByPath(entryPoint)
+	if err != nil {
+		return nil, err
+	}
+	return b.Select(selector)
+}
+
+// decodePhase returns the phase declared by doc
+func decodePhase(doc document.Document) (*Phase, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	p := &Phase{}
+	if err = json.Unmarshal(data, p); err != nil {
+		return nil, ErrInvalidPhase{Name: doc.GetName(), Reason: err.Error()}
+	}
+	if p.Config.Executor == "" {
+		return nil, ErrInvalidPhase{Name: p.Name, Reason: "config.executor is required"}
+	}
+	if err = p.Config.validateCluster(); err != nil {
+		return nil, ErrInvalidPhase{Name: p.Name, Reason: err.Error()}
+	}
+	for i, c := range p.Config.WaitConditions {
+		if err = c.validate(); err != nil {
+			return nil, ErrInvalidPhase{Name: p.Name, Reason: fmt.Sprintf("config.waitConditions[%d]: %v", i, err)}
+		}
+	}
+	for i, dep := range p.Config.DependsOn {
+		if dep == "" {
+			return nil, ErrInvalidPhase{Name: p.Name, Reason: fmt.Sprintf("config.dependsOn[%d] is required", i)}
+		}
+	}
+	return p, nil
+}
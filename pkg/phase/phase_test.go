@@ -0,0 +1,106 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+)
+
+const (
+	kubeconfigPath    = "testdata/kubeconfig.yaml"
+	airshipConfigFile = "testdata/config.yaml"
+)
+
+func makeSettings(t *testing.T, configPath string) *environment.AirshipCTLSettings {
+	t.Helper()
+
+	akp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+	acp, err := filepath.Abs(configPath)
+	require.NoError(t, err)
+
+	settings := &environment.AirshipCTLSettings{
+		AirshipConfigPath: acp,
+		KubeConfigPath:    akp,
+	}
+	settings.InitConfig()
+	return settings
+}
+
+func TestGet(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+
+	p, err := phase.Get(settings, "initinfra")
+	require.NoError(t, err)
+	assert.Equal(t, "initinfra", p.Name)
+	assert.Equal(t, kubernetesapply.Name, p.Config.Executor)
+	assert.Equal(t, "initinfra", p.EntryPoint())
+	assert.Equal(t, "", p.Config.Cluster)
+	assert.JSONEq(t, `{"wait": true, "waitTimeout": "10m"}`, string(p.Config.ExecutorConfig))
+
+	p, err = phase.Get(settings, "target-initinfra")
+	require.NoError(t, err)
+	assert.Equal(t, "initinfra", p.EntryPoint())
+	assert.Equal(t, "dummy_target", p.Config.Cluster)
+
+	_, err = phase.Get(settings, "missing")
+	assert.Equal(t, phase.ErrPhaseNotFound{Name: "missing"}, err)
+
+	_, err = phase.Get(makeSettings(t, "testdata/config-invalid.yaml"), "no-executor")
+	assert.Equal(t, phase.ErrInvalidPhase{Name: "no-executor", Reason: "config.executor is required"}, err)
+
+	_, err = phase.Get(makeSettings(t, "testdata/config-invalid.yaml"), "unnamed-dependency")
+	assert.Equal(t, phase.ErrInvalidPhase{Name: "unnamed-dependency", Reason: "config.dependsOn[0] is required"}, err)
+}
+
+func TestDocumentEntryPoint(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+	initinfra, err := settings.CurrentContextEntryPoint("initinfra")
+	require.NoError(t, err)
+	undeclared, err := settings.CurrentContextEntryPoint("undeclared")
+	require.NoError(t, err)
+
+	entryPoint, err := phase.DocumentEntryPoint(settings, "target-initinfra")
+	require.NoError(t, err)
+	assert.Equal(t, initinfra, entryPoint)
+
+	entryPoint, err = phase.DocumentEntryPoint(settings, "undeclared")
+	require.NoError(t, err)
+	assert.Equal(t, undeclared, entryPoint)
+
+	_, err = phase.DocumentEntryPoint(makeSettings(t, "testdata/config-invalid.yaml"), "no-executor")
+	assert.Equal(t, phase.ErrInvalidPhase{Name: "no-executor", Reason: "config.executor is required"}, err)
+}
+
+func TestList(t *testing.T) {
+	phases, err := phase.List(makeSettings(t, airshipConfigFile))
+	require.NoError(t, err)
+	var names []string
+	for _, p := range phases {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"initinfra", "target-initinfra", "unknown-context"}, names)
+
+	_, err = phase.List(makeSettings(t, "testdata/config-invalid.yaml"))
+	assert.Equal(t, phase.ErrInvalidPhase{Name: "no-executor", Reason: "config.executor is required"}, err)
+}
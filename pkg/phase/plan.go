@@ -0,0 +1,119 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Plan sequences the phases of a site, declared by a PhasePlan document.
+// Its groups of phases are run one after the other.
+type Plan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	PhaseGroups []PhaseGroup `json:"phaseGroups"`
+}
+
+// PhaseGroup is a named group of phases of a plan
+type PhaseGroup struct {
+	Name   string     `json:"name"`
+	Phases []PhaseRef `json:"phases"`
+}
+
+// PhaseRef references a phase by name
+type PhaseRef struct {
+	Name string `json:"name"`
+}
+
+// PhaseNames returns the names of the phases of the plan in order
+func (p *Plan) PhaseNames() []string {
+	var names []string
+	for _, group := range p.PhaseGroups {
+		for _, phase := range group.Phases {
+			names = append(names, phase.Name)
+		}
+	}
+	return names
+}
+
+// GetPlan returns the plan called name, declared in the PhasePlan documents
+// of the current context
+func GetPlan(settings *environment.AirshipCTLSettings, name string) (*Plan, error) {
+	selector := document.NewPhasePlanSelector().ByName(name)
+	docs, err := phaseDocuments(settings, selector)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, ErrPlanNotFound{Name: name}
+	case 1:
+		return decodePlan(docs[0])
+	default:
+		return nil, document.ErrMultiDocsFound{Selector: selector}
+	}
+}
+
+// ListPlans returns the plans declared in the PhasePlan documents of the
+// current context
+func ListPlans(settings *environment.AirshipCTLSettings) ([]*Plan, error) {
+	docs, err := phaseDocuments(settings, document.NewPhasePlanSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*Plan, 0, len(docs))
+	for _, doc := range docs {
+		plan, err := decodePlan(doc)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// decodePlan returns the plan declared by doc
+func decodePlan(doc document.Document) (*Plan, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	if err = json.Unmarshal(data, plan); err != nil {
+		return nil, ErrInvalidPlan{Name: doc.GetName(), Reason: err.Error()}
+	}
+	for i, group := range plan.PhaseGroups {
+		if group.Name == "" {
+			return nil, ErrInvalidPlan{Name: plan.Name, Reason: fmt.Sprintf("phaseGroups[%d].name is required", i)}
+		}
+		for j, phase := range group.Phases {
+			if phase.Name == "" {
+				return nil, ErrInvalidPlan{
+					Name:   plan.Name,
+					Reason: fmt.Sprintf("phaseGroups[%d].phases[%d].name is required", i, j),
+				}
+			}
+		}
+	}
+	return plan, nil
+}
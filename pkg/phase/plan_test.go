@@ -0,0 +1,197 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestGetPlan(t *testing.T) {
+	settings := makeSettings(t, airshipConfigFile)
+
+	plan, err := phase.GetPlan(settings, "deploy")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"initinfra", "target-initinfra"}, plan.PhaseNames())
+
+	_, err = phase.GetPlan(settings, "missing")
+	assert.Equal(t, phase.ErrPlanNotFound{Name: "missing"}, err)
+
+	_, err = phase.GetPlan(makeSettings(t, "testdata/config-invalid.yaml"), "no-group-name")
+	assert.Equal(t, phase.ErrInvalidPlan{Name: "no-group-name", Reason: "phaseGroups[0].name is required"}, err)
+}
+
+func TestRunPlan(t *testing.T) {
+	errApply := errors.New("apply failed")
+
+	tests := []struct {
+		plan        string
+		failPhase   string
+		expectedRun []string
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			plan:        "initinfra-only",
+			expectedRun: []string{"initinfra"},
+			expectedOut: "Running phase initinfra of group ephemeral\nran initinfra\nPlan initinfra-only completed\n",
+		},
+		{
+			plan:        "deploy",
+			expectedRun: []string{"initinfra", "target-initinfra"},
+			expectedOut: "Running phase initinfra of group ephemeral\nran initinfra\n" +
+				"Running phase target-initinfra of group target\nran target-initinfra\nPlan deploy completed\n",
+		},
+		{
+			plan:        "deploy",
+			failPhase:   "initinfra",
+			expectedRun: []string{"initinfra"},
+			expectedOut: "Running phase initinfra of group ephemeral\nran initinfra\n",
+			expectedErr: phase.ErrPlanFailed{Plan: "deploy", Group: "ephemeral", Phase: "initinfra", Err: errApply},
+		},
+		{
+			plan:        "missing-phase",
+			expectedErr: phase.ErrInvalidPlan{Name: "missing-phase", Reason: phase.ErrPhaseNotFound{Name: "missing"}.Error()},
+		},
+		{
+			plan:        "missing",
+			expectedErr: phase.ErrPlanNotFound{Name: "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.plan, func(t *testing.T) {
+			var run []string
+			engine, out := fakeEngine(t, &run, map[string]error{tt.failPhase: errApply}, nil)
+			dir, cleanup := testutil.TempDir(t, "checkpoints")
+			defer cleanup(t)
+			engine.CheckpointDir = dir
+			assert.Equal(t, tt.expectedErr, engine.RunPlan(tt.plan, false))
+			assert.Equal(t, tt.expectedRun, run)
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
+
+func TestRunPlanAnnouncesPhasesOnce(t *testing.T) {
+	logOut := &bytes.Buffer{}
+	log.Init(false, logOut)
+	defer log.Init(false, os.Stderr)
+
+	var run []string
+	engine, out := fakeEngine(t, &run, nil, nil)
+	dir, cleanup := testutil.TempDir(t, "checkpoints")
+	defer cleanup(t)
+	engine.CheckpointDir = dir
+	require.NoError(t, engine.RunPlan("initinfra-only", false))
+	assert.Equal(t, "Running phase initinfra of group ephemeral\nran initinfra\nPlan initinfra-only completed\n",
+		out.String())
+	assert.NotContains(t, logOut.String(), "Running phase")
+
+	// Phases run on their own are announced by the engine
+	require.NoError(t, engine.Run("initinfra"))
+	assert.Contains(t, logOut.String(), "Running phase initinfra\n")
+}
+
+func TestRunPlanResume(t *testing.T) {
+	errApply := errors.New("apply failed")
+	dir, cleanup := testutil.TempDir(t, "checkpoints")
+	defer cleanup(t)
+	path := phase.CheckpointPath(dir, "dummy_cluster", "deploy")
+
+	var run []string
+	engine, _ := fakeEngine(t, &run, map[string]error{"target-initinfra": errApply}, nil)
+	engine.CheckpointDir = dir
+	require.Error(t, engine.RunPlan("deploy", false))
+	assert.Equal(t, []string{"initinfra", "target-initinfra"}, run)
+
+	checkpoint, err := phase.LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy", checkpoint.Plan)
+	assert.Equal(t, "dummy_cluster", checkpoint.Context)
+	require.Len(t, checkpoint.Completed, 1)
+	assert.True(t, checkpoint.IsCompleted("ephemeral", "initinfra"))
+
+	run = nil
+	engine, out := fakeEngine(t, &run, nil, nil)
+	engine.CheckpointDir = dir
+	require.NoError(t, engine.RunPlan("deploy", true))
+	assert.Equal(t, []string{"target-initinfra"}, run)
+	assert.Equal(t, "Skipping phase initinfra of group ephemeral, completed by a previous run\n"+
+		"Running phase target-initinfra of group target\nran target-initinfra\nPlan deploy completed\n", out.String())
+
+	// The checkpoint of a completed plan is removed
+	_, err = phase.LoadCheckpoint(path)
+	assert.Equal(t, phase.ErrCheckpointNotFound{Path: path}, err)
+
+	run = nil
+	engine, out = fakeEngine(t, &run, nil, nil)
+	engine.CheckpointDir = dir
+	require.NoError(t, engine.RunPlan("deploy", true))
+	assert.Equal(t, []string{"initinfra", "target-initinfra"}, run)
+	assert.Contains(t, out.String(), "No checkpoint of plan deploy, running all its phases\n")
+}
+
+func TestRunPlanWithoutResume(t *testing.T) {
+	dir, cleanup := testutil.TempDir(t, "checkpoints")
+	defer cleanup(t)
+	checkpoint := &phase.Checkpoint{
+		Plan:      "deploy",
+		Context:   "dummy_cluster",
+		Completed: []phase.CompletedPhase{{Group: "ephemeral", Name: "initinfra"}},
+	}
+	require.NoError(t, checkpoint.Save(phase.CheckpointPath(dir, "dummy_cluster", "deploy")))
+
+	var run []string
+	engine, _ := fakeEngine(t, &run, nil, nil)
+	engine.CheckpointDir = dir
+	require.NoError(t, engine.RunPlan("deploy", false))
+	assert.Equal(t, []string{"initinfra", "target-initinfra"}, run)
+}
+
+func TestRunPlanDryRun(t *testing.T) {
+	dir, cleanup := testutil.TempDir(t, "checkpoints")
+	defer cleanup(t)
+	path := phase.CheckpointPath(dir, "dummy_cluster", "deploy")
+	checkpoint := &phase.Checkpoint{
+		Plan:      "deploy",
+		Context:   "dummy_cluster",
+		Completed: []phase.CompletedPhase{{Group: "ephemeral", Name: "initinfra"}},
+	}
+	require.NoError(t, checkpoint.Save(path))
+
+	var run []string
+	engine, out := fakeEngine(t, &run, nil, nil)
+	engine.CheckpointDir = dir
+	engine.DryRun = true
+	require.NoError(t, engine.RunPlan("deploy", false))
+	assert.Equal(t, []string{"initinfra", "target-initinfra"}, run)
+	assert.Contains(t, out.String(), "Plan deploy completed (dry run)\n")
+
+	// Dry runs leave the checkpoint of the plan alone
+	previous, err := phase.LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, checkpoint.Completed, previous.Completed)
+}
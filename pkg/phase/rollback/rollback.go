@@ -0,0 +1,77 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rollback
+
+import (
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/snapshot"
+)
+
+// DefaultSnapshotDir returns the directory the state of resources is saved
+// to before a phase is applied, next to the airship config
+func DefaultSnapshotDir(settings *environment.AirshipCTLSettings) string {
+	return filepath.Join(filepath.Dir(settings.AirshipConfigPath), "snapshots")
+}
+
+// SnapshotPath returns the path of the snapshot of a phase in dir
+func SnapshotPath(dir, phaseName string) string {
+	return filepath.Join(dir, phaseName+".yaml")
+}
+
+// Options is an abstraction used to roll back the last apply of a phase
+type Options struct {
+	RootSettings *environment.AirshipCTLSettings
+	Client       client.Interface
+	// Mapper maps kinds to resources, the mapper of kubectl is used if nil
+	Mapper meta.RESTMapper
+
+	// SnapshotDir is where snapshots are read from, DefaultSnapshotDir if
+	// empty
+	SnapshotDir string
+	PhaseName   string
+}
+
+// NewOptions return instance of Options
+func NewOptions(settings *environment.AirshipCTLSettings) *Options {
+	return &Options{RootSettings: settings}
+}
+
+// Run restores the resources of the phase to their state before the last
+// apply
+func (o *Options) Run() error {
+	dir := o.SnapshotDir
+	if dir == "" {
+		dir = DefaultSnapshotDir(o.RootSettings)
+	}
+	s, err := snapshot.Load(SnapshotPath(dir, o.PhaseName))
+	if err != nil {
+		return err
+	}
+
+	mapper := o.Mapper
+	if mapper == nil {
+		ao, err := o.Client.Kubectl().ApplyOptions()
+		if err != nil {
+			return err
+		}
+		mapper = ao.ApplyOptions.Mapper
+	}
+	return s.Restore(o.Client.DynamicClient(), mapper)
+}
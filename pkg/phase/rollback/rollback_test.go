@@ -0,0 +1,68 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rollback_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/k8s/snapshot"
+	"opendev.org/airship/airshipctl/pkg/phase/rollback"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestDefaultSnapshotDir(t *testing.T) {
+	settings := &environment.AirshipCTLSettings{AirshipConfigPath: "/home/user/.airship/config"}
+	assert.Equal(t, "/home/user/.airship/snapshots", rollback.DefaultSnapshotDir(settings))
+	assert.Equal(t, "/home/user/.airship/snapshots/initinfra.yaml",
+		rollback.SnapshotPath(rollback.DefaultSnapshotDir(settings), "initinfra"))
+}
+
+func TestRun(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "rollback")
+	defer cleanup(t)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm", "namespace": "default"},
+	}}
+
+	o := rollback.NewOptions(&environment.AirshipCTLSettings{})
+	o.Client = fake.NewClient(fake.WithDynamicObjects(cm))
+	o.Mapper = mapper
+	o.SnapshotDir = testDir
+	o.PhaseName = "initinfra"
+
+	assert.Equal(t, snapshot.ErrSnapshotNotFound{Path: filepath.Join(testDir, "initinfra.yaml")}, o.Run())
+
+	s := &snapshot.Snapshot{
+		Objects: []map[string]interface{}{cm.Object},
+		Created: []snapshot.Resource{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "created"}},
+	}
+	require.NoError(t, s.Save(rollback.SnapshotPath(testDir, "initinfra")))
+	assert.NoError(t, o.Run())
+}
@@ -0,0 +1,150 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// States of phases in their clusters
+const (
+	// StateDeployed means all resources of the phase are present
+	StateDeployed = "Deployed"
+	// StatePartial means some resources of the phase are missing
+	StatePartial = "Partial"
+	// StateNotDeployed means all resources of the phase are missing
+	StateNotDeployed = "NotDeployed"
+	// StateDegraded means some resources of the phase are not Current
+	StateDegraded = "Degraded"
+	// StateUnknown is reported for phases whose executor can't tell what is
+	// deployed
+	StateUnknown = "Unknown"
+	// StateError is reported for phases whose state couldn't be determined,
+	// e.g. because their cluster is not reachable
+	StateError = "Error"
+)
+
+// StatusEntry is the state of a phase in its cluster
+type StatusEntry struct {
+	Name     string `json:"name"`
+	Executor string `json:"executor"`
+	Cluster  string `json:"cluster"`
+	State    string `json:"state"`
+	// Status counts the resources of the phase, it is nil unless the state
+	// of the phase is known
+	*ifc.Status `json:",omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// StatusList is the state of phases in their clusters
+type StatusList struct {
+	Phases []StatusEntry `json:"phases"`
+}
+
+// Status returns the state of the phases selected by filter in their
+// clusters, sorted by name. Failing to get the state of a phase doesn't
+// prevent the state of the other phases from being returned.
+func (e *Engine) Status(filter ListFilter) (*StatusList, error) {
+	list, err := NewPhaseList(e.Settings, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StatusList{Phases: []StatusEntry{}}
+	for _, p := range list.Phases {
+		entry := StatusEntry{Name: p.Name, Executor: p.Executor, Cluster: p.Cluster}
+		if entry.Status, err = e.phaseStatus(p.Name); err != nil {
+			entry.State = StateError
+			entry.Error = err.Error()
+		} else {
+			entry.State = state(entry.Status)
+		}
+		result.Phases = append(result.Phases, entry)
+	}
+	return result, nil
+}
+
+// phaseStatus returns what is deployed of the phase called name, nil if its
+// executor can't tell
+func (e *Engine) phaseStatus(name string) (*ifc.Status, error) {
+	executor, err := e.executor(name)
+	if err != nil {
+		return nil, err
+	}
+	reporter, ok := executor.Executor.(ifc.StatusReporter)
+	if !ok {
+		return nil, nil
+	}
+	disconnect, err := e.connect(executor)
+	if err != nil {
+		return nil, err
+	}
+	defer disconnect()
+	return reporter.Status()
+}
+
+func state(s *ifc.Status) string {
+	switch {
+	case s == nil:
+		return StateUnknown
+	case s.Degraded > 0:
+		return StateDegraded
+	case s.Missing == 0:
+		return StateDeployed
+	case s.Present == 0:
+		return StateNotDeployed
+	default:
+		return StatePartial
+	}
+}
+
+// Print writes the list to w as a table or YAML. Only the YAML output has
+// the state of every resource.
+func (l *StatusList) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTable:
+		return l.printTable(w)
+	case OutputYAML:
+		data, err := yaml.Marshal(l)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return ErrInvalidOutputFormat{Format: format}
+	}
+}
+
+func (l *StatusList) printTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "PHASE\tCLUSTER\tSTATE\tPRESENT\tMISSING\tDEGRADED")
+	for _, p := range l.Phases {
+		present, missing, degraded := "-", "-", "-"
+		if p.Status != nil {
+			present = fmt.Sprint(p.Present)
+			missing = fmt.Sprint(p.Missing)
+			degraded = fmt.Sprint(p.Degraded)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Name, p.Cluster, p.State, present, missing, degraded)
+	}
+	return tw.Flush()
+}
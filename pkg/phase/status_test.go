@@ -0,0 +1,122 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// fakeStatusExecutor is a fakeExecutor which can tell what is deployed
+type fakeStatusExecutor struct {
+	fakeExecutor
+	status *ifc.Status
+	err    error
+}
+
+func (e *fakeStatusExecutor) Status() (*ifc.Status, error) {
+	return e.status, e.err
+}
+
+func TestEngineStatus(t *testing.T) {
+	errCluster := errors.New("connection refused")
+	engine := phase.NewEngine(makeSettings(t, airshipConfigFile), nil)
+	engine.Executors = map[string]ifc.ExecutorFactory{
+		kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+			if config.PhaseName == "target-initinfra" {
+				return &fakeStatusExecutor{err: errCluster}, nil
+			}
+			return &fakeStatusExecutor{status: &ifc.Status{Present: 1, Missing: 1}}, nil
+		},
+	}
+
+	list, err := engine.Status(phase.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Phases, 3)
+	assert.Equal(t, phase.StatusEntry{
+		Name:     "initinfra",
+		Executor: kubernetesapply.Name,
+		Cluster:  "dummy_cluster",
+		State:    phase.StatePartial,
+		Status:   &ifc.Status{Present: 1, Missing: 1},
+	}, list.Phases[0])
+	assert.Equal(t, phase.StatusEntry{
+		Name:     "target-initinfra",
+		Executor: kubernetesapply.Name,
+		Cluster:  "dummy_target",
+		State:    phase.StateError,
+		Error:    errCluster.Error(),
+	}, list.Phases[1])
+	assert.Equal(t, phase.StateError, list.Phases[2].State)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, list.Print(out, phase.OutputTable))
+	assert.Equal(t, "PHASE              CLUSTER           STATE     PRESENT   MISSING   DEGRADED\n"+
+		"initinfra          dummy_cluster     Partial   1         1         0\n"+
+		"target-initinfra   dummy_target      Error     -         -         -\n"+
+		"unknown-context    missing_context   Error     -         -         -\n", out.String())
+
+	_, err = engine.Status(phase.ListFilter{Plan: "missing"})
+	assert.Equal(t, phase.ErrPlanNotFound{Name: "missing"}, err)
+}
+
+func TestEngineStatusUnknown(t *testing.T) {
+	var ran []string
+	engine, _ := fakeEngine(t, &ran, nil, nil)
+	list, err := engine.Status(phase.ListFilter{Plan: "initinfra-only"})
+	require.NoError(t, err)
+	require.Len(t, list.Phases, 1)
+	assert.Equal(t, phase.StateUnknown, list.Phases[0].State)
+	assert.Nil(t, list.Phases[0].Status)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, list.Print(out, phase.OutputYAML))
+	assert.Equal(t, "phases:\n- cluster: dummy_cluster\n  executor: KubernetesApply\n"+
+		"  name: initinfra\n  state: Unknown\n", out.String())
+}
+
+func TestEngineStatusStates(t *testing.T) {
+	tests := []struct {
+		status        *ifc.Status
+		expectedState string
+	}{
+		{status: &ifc.Status{Present: 2}, expectedState: phase.StateDeployed},
+		{status: &ifc.Status{Present: 1, Missing: 1}, expectedState: phase.StatePartial},
+		{status: &ifc.Status{Missing: 2}, expectedState: phase.StateNotDeployed},
+		{status: &ifc.Status{Present: 1, Missing: 1, Degraded: 1}, expectedState: phase.StateDegraded},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expectedState, func(t *testing.T) {
+			engine := phase.NewEngine(makeSettings(t, airshipConfigFile), nil)
+			engine.Executors[kubernetesapply.Name] = func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+				return &fakeStatusExecutor{status: tt.status}, nil
+			}
+			list, err := engine.Status(phase.ListFilter{Plan: "initinfra-only"})
+			require.NoError(t, err)
+			require.Len(t, list.Phases, 1)
+			assert.Equal(t, tt.expectedState, list.Phases[0].State)
+		})
+	}
+}
@@ -0,0 +1,386 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Output formats of trees
+const (
+	OutputTree = "tree"
+	OutputDOT  = "dot"
+)
+
+var treeFormats = []string{OutputTree, OutputDOT}
+
+// Kinds of the nodes of a Tree
+const (
+	NodePlan          = "plan"
+	NodeGroup         = "group"
+	NodePhase         = "phase"
+	NodeKustomization = "kustomization"
+	NodeResource      = "resource"
+	// NodeMissing is a document path which doesn't exist or has no
+	// kustomization
+	NodeMissing = "missing"
+)
+
+// kustomizationFiles are the names kustomize reads kustomizations from
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// TreeNode is a plan, phase group, phase or document path of a Tree
+type TreeNode struct {
+	Kind string
+	// Name is the name of plans, groups and phases, and the path of
+	// documents relative to the target path of the current context
+	Name string
+	// Details are the executor and cluster of phases, or why a node is
+	// missing
+	Details  string
+	Children []*TreeNode
+
+	// id identifies the node in graphs, nodes with the same id are the
+	// same phase or document path
+	id string
+	// dependsOn are the ids of the phases a phase depends on
+	dependsOn []string
+}
+
+// Tree is the hierarchy of plans, their phase groups, their phases and the
+// kustomizations of the documents of the phases
+type Tree struct {
+	Roots []*TreeNode
+}
+
+// NewTree returns the tree of the plan called name, or of all plans of the
+// current context followed by the phases which are not part of any plan if
+// name is empty
+func NewTree(settings *environment.AirshipCTLSettings, name string) (*Tree, error) {
+	builder, err := newTreeBuilder(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []*Plan
+	if name != "" {
+		plan, getErr := GetPlan(settings, name)
+		if getErr != nil {
+			return nil, getErr
+		}
+		plans = []*Plan{plan}
+	} else if plans, err = ListPlans(settings); err != nil {
+		return nil, err
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Name < plans[j].Name })
+
+	tree := &Tree{}
+	planned := map[string]bool{}
+	for _, plan := range plans {
+		planNode := &TreeNode{Kind: NodePlan, Name: plan.Name, id: "plan/" + plan.Name}
+		for _, group := range plan.PhaseGroups {
+			groupNode := &TreeNode{Kind: NodeGroup, Name: group.Name, id: "group/" + plan.Name + "/" + group.Name}
+			for _, ref := range group.Phases {
+				planned[ref.Name] = true
+				phaseNode, phaseErr := builder.phaseNode(ref.Name)
+				if phaseErr != nil {
+					return nil, phaseErr
+				}
+				groupNode.Children = append(groupNode.Children, phaseNode)
+			}
+			planNode.Children = append(planNode.Children, groupNode)
+		}
+		tree.Roots = append(tree.Roots, planNode)
+	}
+	if name != "" {
+		return tree, nil
+	}
+
+	for _, p := range builder.phases {
+		if planned[p.Name] {
+			continue
+		}
+		phaseNode, phaseErr := builder.phaseNode(p.Name)
+		if phaseErr != nil {
+			return nil, phaseErr
+		}
+		tree.Roots = append(tree.Roots, phaseNode)
+	}
+	return tree, nil
+}
+
+// NewPhaseTree returns the tree of the kustomizations of the documents of the
+// phase called name
+func NewPhaseTree(settings *environment.AirshipCTLSettings, name string) (*Tree, error) {
+	builder, err := newTreeBuilder(settings)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := builder.byName[name]; !ok {
+		return nil, ErrPhaseNotFound{Name: name}
+	}
+	phaseNode, err := builder.phaseNode(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Tree{Roots: []*TreeNode{phaseNode}}, nil
+}
+
+// treeBuilder builds the nodes of phases and of their documents
+type treeBuilder struct {
+	settings *environment.AirshipCTLSettings
+	// targetPath is the path document paths are relative to
+	targetPath string
+	phases     []*Phase
+	byName     map[string]*Phase
+}
+
+func newTreeBuilder(settings *environment.AirshipCTLSettings) (*treeBuilder, error) {
+	phases, err := List(settings)
+	if err != nil {
+		return nil, err
+	}
+	targetPath, err := settings.Config.CurrentContextTargetPath()
+	if err != nil {
+		return nil, err
+	}
+	b := &treeBuilder{
+		settings:   settings,
+		targetPath: targetPath,
+		phases:     phases,
+		byName:     make(map[string]*Phase, len(phases)),
+	}
+	for _, p := range phases {
+		b.byName[p.Name] = p
+	}
+	return b, nil
+}
+
+// phaseNode returns the node of the phase called name with the node of its
+// document entry point as child
+func (b *treeBuilder) phaseNode(name string) (*TreeNode, error) {
+	p, ok := b.byName[name]
+	if !ok {
+		return &TreeNode{Kind: NodePhase, Name: name, Details: "not declared", id: "phase/" + name}, nil
+	}
+	node := &TreeNode{
+		Kind:    NodePhase,
+		Name:    p.Name,
+		Details: fmt.Sprintf("%s, cluster %s", p.Config.Executor, phaseContext(b.settings, p)),
+		id:      "phase/" + p.Name,
+	}
+	for _, dep := range p.Config.DependsOn {
+		node.dependsOn = append(node.dependsOn, "phase/"+dep)
+	}
+	entryPoint, err := b.settings.CurrentContextEntryPoint(p.EntryPoint())
+	if err != nil {
+		return nil, err
+	}
+	child, err := b.documentNode(entryPoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	node.Children = []*TreeNode{child}
+	return node, nil
+}
+
+// documentNode returns the node of the document file or kustomization
+// directory at path, with the nodes of the resources, generators and
+// transformers of the kustomization as children. stack are the
+// kustomizations including path, whose resources are not followed again.
+func (b *treeBuilder) documentNode(path string, stack []string) (*TreeNode, error) {
+	node := &TreeNode{Kind: NodeResource, Name: b.relative(path)}
+	node.id = "path/" + node.Name
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		node.Kind = NodeMissing
+		node.Details = "not found"
+		return node, nil
+	case err != nil:
+		return nil, err
+	case !info.IsDir():
+		return node, nil
+	}
+
+	node.Kind = NodeKustomization
+	for _, p := range stack {
+		if p == path {
+			node.Details = "included by itself"
+			return node, nil
+		}
+	}
+	k, err := readKustomization(path)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		node.Kind = NodeMissing
+		node.Details = "no kustomization"
+		return node, nil
+	}
+
+	stack = append(stack, path)
+	refs := append(append(append([]string{}, k.Resources...), k.Generators...), k.Transformers...)
+	for _, ref := range refs {
+		if isRemote(ref) {
+			node.Children = append(node.Children, &TreeNode{Kind: NodeResource, Name: ref, id: "remote/" + ref})
+			continue
+		}
+		child, childErr := b.documentNode(filepath.Join(path, ref), stack)
+		if childErr != nil {
+			return nil, childErr
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// relative returns path relative to the target path of the current context
+func (b *treeBuilder) relative(path string) string {
+	rel, err := filepath.Rel(b.targetPath, path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return rel
+}
+
+// readKustomization returns the kustomization of the directory at path, nil
+// if it has none
+func readKustomization(path string) (*types.Kustomization, error) {
+	for _, name := range kustomizationFiles {
+		data, err := ioutil.ReadFile(filepath.Join(path, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		k := &types.Kustomization{}
+		if err = yaml.Unmarshal(data, k); err != nil {
+			return nil, ErrInvalidKustomization{Path: filepath.Join(path, name), Err: err}
+		}
+		k.FixKustomizationPostUnmarshalling()
+		return k, nil
+	}
+	return nil, nil
+}
+
+// isRemote tells whether a kustomization reference is a remote target
+// instead of a local path
+func isRemote(ref string) bool {
+	return strings.Contains(ref, "://") || strings.HasPrefix(ref, "github.com/")
+}
+
+// Print writes the tree to w indented, or as a graphviz graph
+func (t *Tree) Print(w io.Writer, format string) error {
+	switch format {
+	case OutputTree:
+		for _, root := range t.Roots {
+			printNode(w, root, 0)
+		}
+		return nil
+	case OutputDOT:
+		return t.printDOT(w)
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: treeFormats}
+	}
+}
+
+func printNode(w io.Writer, node *TreeNode, depth int) {
+	fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth), node.label())
+	for _, child := range node.Children {
+		printNode(w, child, depth+1)
+	}
+}
+
+// label describes the node on a single line
+func (n *TreeNode) label() string {
+	label := n.Name
+	switch n.Kind {
+	case NodePlan, NodeGroup, NodePhase:
+		label = n.Kind + " " + n.Name
+	}
+	if n.Details != "" {
+		label += " (" + n.Details + ")"
+	}
+	return label
+}
+
+// printDOT writes the tree as a directed graph, where phases and document
+// paths appear once however many times they are referenced, and phases
+// point to the phases they depend on with dashed edges
+func (t *Tree) printDOT(w io.Writer) error {
+	fmt.Fprintln(w, "digraph airship {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	written := map[string]bool{}
+	edges := map[string]bool{}
+	var dependencies [][2]string
+	var writeNode func(node *TreeNode)
+	writeNode = func(node *TreeNode) {
+		if !written[node.id] {
+			written[node.id] = true
+			fmt.Fprintf(w, "  %q [label=%q, shape=%s];\n", node.id, node.label(), dotShape(node.Kind))
+			for _, dep := range node.dependsOn {
+				dependencies = append(dependencies, [2]string{node.id, dep})
+			}
+		}
+		for _, child := range node.Children {
+			edge := node.id + "\x00" + child.id
+			if !edges[edge] {
+				edges[edge] = true
+				fmt.Fprintf(w, "  %q -> %q;\n", node.id, child.id)
+			}
+			writeNode(child)
+		}
+	}
+	for _, root := range t.Roots {
+		writeNode(root)
+	}
+	// Dependencies on phases which are not part of the graph are left out
+	for _, dep := range dependencies {
+		if written[dep[1]] {
+			fmt.Fprintf(w, "  %q -> %q [style=dashed];\n", dep[0], dep[1])
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+func dotShape(kind string) string {
+	switch kind {
+	case NodePlan:
+		return "doubleoctagon"
+	case NodeGroup:
+		return "octagon"
+	case NodePhase:
+		return "box"
+	case NodeMissing:
+		return "plaintext"
+	default:
+		return "note"
+	}
+}
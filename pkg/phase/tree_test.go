@@ -0,0 +1,136 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const treeConfigFile = "testdata/config-tree.yaml"
+
+func TestNewTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			format: phase.OutputTree,
+			expected: `plan deploy
+  group ephemeral
+    phase crds (KubernetesApply, cluster dummy_cluster)
+      primary/site/tree/ephemeral/crds
+        primary/function/crds
+          primary/function/crds/crd.yaml
+        primary/site/tree/ephemeral/crds/cm.yaml
+  group target
+    phase workloads (KubernetesApply, cluster dummy_target)
+      primary/site/tree/ephemeral/apps (not found)
+    phase missing (not declared)
+phase standalone (KubernetesApply, cluster dummy_cluster)
+  primary/site/tree/ephemeral/crds
+    primary/function/crds
+      primary/function/crds/crd.yaml
+    primary/site/tree/ephemeral/crds/cm.yaml
+`,
+		},
+		{
+			name:   "deploy",
+			format: phase.OutputTree,
+			expected: `plan deploy
+  group ephemeral
+    phase crds (KubernetesApply, cluster dummy_cluster)
+      primary/site/tree/ephemeral/crds
+        primary/function/crds
+          primary/function/crds/crd.yaml
+        primary/site/tree/ephemeral/crds/cm.yaml
+  group target
+    phase workloads (KubernetesApply, cluster dummy_target)
+      primary/site/tree/ephemeral/apps (not found)
+    phase missing (not declared)
+`,
+		},
+		{
+			name:   "deploy",
+			format: phase.OutputDOT,
+			expected: `digraph airship {
+  rankdir=LR;
+  "plan/deploy" [label="plan deploy", shape=doubleoctagon];
+  "plan/deploy" -> "group/deploy/ephemeral";
+  "group/deploy/ephemeral" [label="group ephemeral", shape=octagon];
+  "group/deploy/ephemeral" -> "phase/crds";
+  "phase/crds" [label="phase crds (KubernetesApply, cluster dummy_cluster)", shape=box];
+  "phase/crds" -> "path/primary/site/tree/ephemeral/crds";
+  "path/primary/site/tree/ephemeral/crds" [label="primary/site/tree/ephemeral/crds", shape=note];
+  "path/primary/site/tree/ephemeral/crds" -> "path/primary/function/crds";
+  "path/primary/function/crds" [label="primary/function/crds", shape=note];
+  "path/primary/function/crds" -> "path/primary/function/crds/crd.yaml";
+  "path/primary/function/crds/crd.yaml" [label="primary/function/crds/crd.yaml", shape=note];
+  "path/primary/site/tree/ephemeral/crds" -> "path/primary/site/tree/ephemeral/crds/cm.yaml";
+  "path/primary/site/tree/ephemeral/crds/cm.yaml" [label="primary/site/tree/ephemeral/crds/cm.yaml", shape=note];
+  "plan/deploy" -> "group/deploy/target";
+  "group/deploy/target" [label="group target", shape=octagon];
+  "group/deploy/target" -> "phase/workloads";
+  "phase/workloads" [label="phase workloads (KubernetesApply, cluster dummy_target)", shape=box];
+  "phase/workloads" -> "path/primary/site/tree/ephemeral/apps";
+  "path/primary/site/tree/ephemeral/apps" [label="primary/site/tree/ephemeral/apps (not found)", shape=plaintext];
+  "group/deploy/target" -> "phase/missing";
+  "phase/missing" [label="phase missing (not declared)", shape=box];
+  "phase/workloads" -> "phase/crds" [style=dashed];
+}
+`,
+		},
+	}
+
+	settings := makeSettings(t, treeConfigFile)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name+"/"+tt.format, func(t *testing.T) {
+			tree, err := phase.NewTree(settings, tt.name)
+			require.NoError(t, err)
+			out := &bytes.Buffer{}
+			require.NoError(t, tree.Print(out, tt.format))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+
+	_, err := phase.NewTree(settings, "missing")
+	assert.Equal(t, phase.ErrPlanNotFound{Name: "missing"}, err)
+}
+
+func TestNewPhaseTree(t *testing.T) {
+	settings := makeSettings(t, treeConfigFile)
+	tree, err := phase.NewPhaseTree(settings, "crds")
+	require.NoError(t, err)
+	out := &bytes.Buffer{}
+	require.NoError(t, tree.Print(out, phase.OutputTree))
+	assert.Equal(t, `phase crds (KubernetesApply, cluster dummy_cluster)
+  primary/site/tree/ephemeral/crds
+    primary/function/crds
+      primary/function/crds/crd.yaml
+    primary/site/tree/ephemeral/crds/cm.yaml
+`, out.String())
+
+	_, err = phase.NewPhaseTree(settings, "missing")
+	assert.Equal(t, phase.ErrPhaseNotFound{Name: "missing"}, err)
+	assert.Equal(t, phase.ErrInvalidOutputFormat{Format: "json", Formats: []string{phase.OutputTree, phase.OutputDOT}},
+		tree.Print(out, "json"))
+}
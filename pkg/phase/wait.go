@@ -0,0 +1,211 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/status"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// DefaultWaitTimeout is how long the wait conditions of a phase are waited
+// for, unless the phase sets its waitTimeout
+const DefaultWaitTimeout = 10 * time.Minute
+
+// WaitCondition is a condition a resource of the cluster of a phase has to
+// meet for the phase to be complete
+type WaitCondition struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace of the resource, the namespace of the kubeconfig context of
+	// the cluster if empty, like for the documents applied by the phase. It
+	// is ignored for resources which are not namespaced.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// JSONPath is a template evaluated against the resource, e.g.
+	// {.status.phase}. The condition is met once it has a result, which
+	// has to be Value if set. Without JSONPath, the condition is met once
+	// the resource is Current.
+	JSONPath string `json:"jsonPath,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+func (c WaitCondition) String() string {
+	s := c.Kind + "/" + c.Name
+	if c.Namespace != "" {
+		s += " in namespace " + c.Namespace
+	}
+	switch {
+	case c.JSONPath == "":
+		return s + " is Current"
+	case c.Value == "":
+		return fmt.Sprintf("%s has %s", s, c.JSONPath)
+	default:
+		return fmt.Sprintf("%s has %s = %q", s, c.JSONPath, c.Value)
+	}
+}
+
+// resource identifies the resource of the condition in events
+func (c WaitCondition) resource() string {
+	if c.Namespace == "" {
+		return c.Kind + "/" + c.Name
+	}
+	return c.Kind + "/" + c.Namespace + "/" + c.Name
+}
+
+// validate checks that the condition names a resource and has a valid
+// JSONPath
+func (c WaitCondition) validate() error {
+	switch {
+	case c.APIVersion == "":
+		return ErrWaitConditionFieldRequired{Field: "apiVersion"}
+	case c.Kind == "":
+		return ErrWaitConditionFieldRequired{Field: "kind"}
+	case c.Name == "":
+		return ErrWaitConditionFieldRequired{Field: "name"}
+	case c.JSONPath != "":
+		if err := jsonpath.New(c.Name).Parse(c.JSONPath); err != nil {
+			return ErrInvalidJSONPath{JSONPath: c.JSONPath, Err: err}
+		}
+	}
+	return nil
+}
+
+// met tells whether the resource of the condition meets it, looking it up
+// in defaultNamespace if the condition has no namespace. A resource which
+// Failed is an error, a resource which doesn't exist doesn't meet the
+// condition.
+func (c WaitCondition) met(resources client.ResourceAccessor, defaultNamespace string) (bool, error) {
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	obj, err := resources.Get(schema.FromAPIVersionAndKind(c.APIVersion, c.Kind), namespace, c.Name)
+	switch {
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if c.JSONPath == "" {
+		var result status.Result
+		if result, err = status.Compute(obj); err != nil {
+			return false, err
+		}
+		if result.Status == status.Failed {
+			return false, status.ErrResourceFailed{Resource: c.Kind + "/" + c.Name, Message: result.Message}
+		}
+		return result.Status == status.Current, nil
+	}
+
+	jp := jsonpath.New(c.Name)
+	jp.AllowMissingKeys(true)
+	if err = jp.Parse(c.JSONPath); err != nil {
+		return false, err
+	}
+	buf := &bytes.Buffer{}
+	if err = jp.Execute(buf, obj.Object); err != nil {
+		return false, err
+	}
+	if c.Value == "" {
+		return buf.Len() > 0, nil
+	}
+	return buf.String() == c.Value, nil
+}
+
+// wait blocks until the resources of the cluster of settings meet the wait
+// conditions of p, or until the wait timeout of p expired. With DryRun, the
+// conditions are only listed.
+func (e *Engine) wait(p *Phase, settings *environment.AirshipCTLSettings) error {
+	if len(p.Config.WaitConditions) == 0 {
+		return nil
+	}
+	if e.DryRun {
+		for _, c := range p.Config.WaitConditions {
+			if err := e.emit(ifc.Event{
+				Type:     ifc.EventWaiting,
+				Phase:    p.Name,
+				Resource: c.resource(),
+				Message:  fmt.Sprintf("Would wait until %s (dry run)", c),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	kclient, err := e.ClientFactory(settings)
+	if err != nil {
+		return err
+	}
+	timeout := DefaultWaitTimeout
+	if p.Config.WaitTimeout != nil {
+		timeout = p.Config.WaitTimeout.Duration
+	}
+
+	if err = e.emit(ifc.Event{
+		Type:    ifc.EventWaiting,
+		Phase:   p.Name,
+		Message: fmt.Sprintf("Waiting for the conditions of phase %s", p.Name),
+	}); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	pending := p.Config.WaitConditions
+	for {
+		var remaining []WaitCondition
+		for _, c := range pending {
+			met, metErr := c.met(kclient, kclient.Namespace())
+			if metErr != nil {
+				return metErr
+			}
+			if met {
+				if err = e.emit(ifc.Event{
+					Type:     ifc.EventWaiting,
+					Phase:    p.Name,
+					Resource: c.resource(),
+					Message:  fmt.Sprintf("Condition met: %s", c),
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+			remaining = append(remaining, c)
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			result := ErrWaitConditionsTimeout{Phase: p.Name, Timeout: timeout}
+			for _, c := range remaining {
+				result.Conditions = append(result.Conditions, c.String())
+			}
+			return result
+		}
+		pending = remaining
+		time.Sleep(e.PollInterval)
+	}
+}
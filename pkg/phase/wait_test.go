@@ -0,0 +1,216 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/phase/executors/kubernetesapply"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+func deployment(readyReplicas int64) runtime.Object {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default", "generation": int64(1)},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(1),
+			"replicas":           int64(2),
+			"updatedReplicas":    int64(2),
+			"readyReplicas":      readyReplicas,
+			"availableReplicas":  readyReplicas,
+		},
+	}}
+}
+
+func settingsConfigMap(data map[string]interface{}) runtime.Object {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+		"data":       data,
+	}}
+}
+
+func inNamespace(obj runtime.Object, namespace string) runtime.Object {
+	u := obj.(*unstructured.Unstructured).DeepCopy()
+	u.SetNamespace(namespace)
+	return u
+}
+
+func TestEngineRunWaitConditions(t *testing.T) {
+	tests := []struct {
+		name        string
+		namespace   string
+		objects     []runtime.Object
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			name:    "current",
+			objects: []runtime.Object{deployment(2)},
+			expectedOut: "ran current\nWaiting for the conditions of phase current\n" +
+				"Condition met: Deployment/web in namespace default is Current\n",
+		},
+		{
+			name:        "current",
+			objects:     []runtime.Object{deployment(1)},
+			expectedOut: "ran current\nWaiting for the conditions of phase current\n",
+			expectedErr: phase.ErrWaitConditionsTimeout{
+				Phase:      "current",
+				Timeout:    10 * time.Millisecond,
+				Conditions: []string{"Deployment/web in namespace default is Current"},
+			},
+		},
+		{
+			name: "jsonpath",
+			objects: []runtime.Object{
+				settingsConfigMap(map[string]interface{}{"mode": "ready", "endpoint": "10.0.0.1"}),
+			},
+			expectedOut: "ran jsonpath\nWaiting for the conditions of phase jsonpath\n" +
+				"Condition met: ConfigMap/settings has {.data.mode} = \"ready\"\n" +
+				"Condition met: ConfigMap/settings has {.data.endpoint}\n",
+		},
+		{
+			name:        "jsonpath",
+			objects:     []runtime.Object{settingsConfigMap(map[string]interface{}{"mode": "starting"})},
+			expectedOut: "ran jsonpath\nWaiting for the conditions of phase jsonpath\n",
+			expectedErr: phase.ErrWaitConditionsTimeout{
+				Phase:   "jsonpath",
+				Timeout: 10 * time.Millisecond,
+				Conditions: []string{
+					"ConfigMap/settings has {.data.mode} = \"ready\"",
+					"ConfigMap/settings has {.data.endpoint}",
+				},
+			},
+		},
+		{
+			// Resources without namespace are looked up in the namespace of the kubeconfig context
+			name:      "jsonpath",
+			namespace: "target-infra",
+			objects: []runtime.Object{
+				inNamespace(settingsConfigMap(map[string]interface{}{"mode": "ready", "endpoint": "10.0.0.1"}),
+					"target-infra"),
+			},
+			expectedOut: "ran jsonpath\nWaiting for the conditions of phase jsonpath\n" +
+				"Condition met: ConfigMap/settings has {.data.mode} = \"ready\"\n" +
+				"Condition met: ConfigMap/settings has {.data.endpoint}\n",
+		},
+		{
+			name:        "missing-resource",
+			expectedOut: "ran missing-resource\nWaiting for the conditions of phase missing-resource\n",
+			expectedErr: phase.ErrWaitConditionsTimeout{
+				Phase:      "missing-resource",
+				Timeout:    10 * time.Millisecond,
+				Conditions: []string{"ConfigMap/missing is Current"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			out := &bytes.Buffer{}
+			engine := phase.NewEngine(makeSettings(t, "testdata/config-wait.yaml"), nil)
+			engine.Out = out
+			engine.PollInterval = time.Millisecond
+			engine.Executors = map[string]ifc.ExecutorFactory{
+				kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+					return &fakeExecutor{config: config, ran: &ran}, nil
+				},
+			}
+			engine.ClientFactory = func(*environment.AirshipCTLSettings) (client.Interface, error) {
+				return fake.NewClient(fake.WithDynamicObjects(tt.objects...), fake.WithNamespace(tt.namespace)), nil
+			}
+
+			assert.Equal(t, tt.expectedErr, engine.Run(tt.name))
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
+
+func TestEngineRunWaitConditionsDryRun(t *testing.T) {
+	var ran []string
+	out := &bytes.Buffer{}
+	engine := phase.NewEngine(makeSettings(t, "testdata/config-wait.yaml"), nil)
+	engine.Out = out
+	engine.DryRun = true
+	engine.Executors = map[string]ifc.ExecutorFactory{
+		kubernetesapply.Name: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+			return &fakeExecutor{config: config, ran: &ran}, nil
+		},
+	}
+
+	require.NoError(t, engine.Run("jsonpath"))
+	assert.Contains(t, out.String(), "ran jsonpath\n"+
+		"Would wait until ConfigMap/settings has {.data.mode} = \"ready\" (dry run)\n"+
+		"Would wait until ConfigMap/settings has {.data.endpoint} (dry run)\n")
+}
+
+func TestInvalidWaitCondition(t *testing.T) {
+	jsonPathErr := jsonpath.New("settings").Parse("{.data.mode")
+	require.Error(t, jsonPathErr)
+
+	settings := makeSettings(t, "testdata/config-invalid.yaml")
+	tests := []struct {
+		name        string
+		index       int
+		expectedErr error
+	}{
+		{
+			name:        "wait-no-api-version",
+			expectedErr: phase.ErrWaitConditionFieldRequired{Field: "apiVersion"},
+		},
+		{
+			name:        "wait-no-kind",
+			expectedErr: phase.ErrWaitConditionFieldRequired{Field: "kind"},
+		},
+		{
+			name:        "wait-no-name",
+			index:       1,
+			expectedErr: phase.ErrWaitConditionFieldRequired{Field: "name"},
+		},
+		{
+			name:        "wait-invalid-jsonpath",
+			expectedErr: phase.ErrInvalidJSONPath{JSONPath: "{.data.mode", Err: jsonPathErr},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := phase.Get(settings, tt.name)
+			assert.Equal(t, phase.ErrInvalidPhase{
+				Name:   tt.name,
+				Reason: fmt.Sprintf("config.waitConditions[%d]: %v", tt.index, tt.expectedErr),
+			}, err)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	// DefaultBIOSVerifyTimeout is how long to wait for BIOS settings to take effect after the reboot of a host
+	// unless configured otherwise. Applying them makes the host go through POST more than once.
+	DefaultBIOSVerifyTimeout = 20 * time.Minute
+
+	defaultBIOSPollInterval = 30 * time.Second
+)
+
+// BIOSOptions configures how BIOS settings are applied by Manager.ApplyBIOSSettings.
+type BIOSOptions struct {
+	// Reboot makes the hosts apply the settings right away and verifies them once they did. Otherwise the settings
+	// stay pending until the next reboot of the hosts.
+	Reboot        bool
+	VerifyTimeout time.Duration
+	PollInterval  time.Duration
+}
+
+// DefaultBIOSOptions returns options rebooting hosts and verifying their settings.
+func DefaultBIOSOptions() BIOSOptions {
+	return BIOSOptions{
+		Reboot:        true,
+		VerifyTimeout: DefaultBIOSVerifyTimeout,
+		PollInterval:  defaultBIOSPollInterval,
+	}
+}
+
+// HostBIOSResult is the outcome of applying BIOS settings to a host.
+type HostBIOSResult struct {
+	HostName string
+	// Changed are the sorted names of the attributes whose value differed from the settings.
+	Changed []string
+	// Skipped is set for hosts without BIOS settings.
+	Skipped bool
+	Err     error
+}
+
+// hostBIOSReport is a HostBIOSResult as written in OutputJSON and OutputYAML formats. Result is one of "applied",
+// "staged", "up to date", "skipped" and "failed".
+type hostBIOSReport struct {
+	Host    string   `json:"host"`
+	Result  string   `json:"result"`
+	Changed []string `json:"changed,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// BIOSSettings returns the BIOS attributes defined by the BIOSSettings documents of the given phase for the current
+// context, by the name of the host they apply to. A BIOSSettings document names its host in spec.host and lists the
+// attributes in spec.attributes, e.g.
+//
+//	apiVersion: airshipit.org/v1alpha1
+//	kind: BIOSSettings
+//	metadata:
+//	  name: node01-bios
+//	spec:
+//	  host: node01
+//	  attributes:
+//	    ProcVirtualization: Enabled
+//	    SriovGlobalEnable: Enabled
+func BIOSSettings(settings *environment.AirshipCTLSettings, phase string) (map[string]map[string]interface{}, error) {
+	docBundle, err := currentContextBundle(settings, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := docBundle.Select(document.NewSelector().ByGvk(document.BaseAirshipSelector, "v1alpha1",
+		document.BIOSSettingsKind))
+	if err != nil {
+		return nil, err
+	}
+
+	hostSettings := make(map[string]map[string]interface{}, len(docs))
+	for _, doc := range docs {
+		host, err := doc.GetString("spec.host")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := hostSettings[host]; ok {
+			return nil, ErrDuplicateBIOSSettings{Host: host}
+		}
+
+		attributes, err := doc.GetMap("spec.attributes")
+		if err != nil {
+			return nil, err
+		}
+		hostSettings[host] = attributes
+	}
+
+	return hostSettings, nil
+}
+
+// ApplyBIOSSettings applies the BIOS attributes in hostSettings, by host name, to the hosts of the manager, on at
+// most maxWorkers hosts at a time, see RunAction. Only attributes whose current value differs are changed; hosts
+// whose attributes all match are not rebooted. Hosts without settings are skipped. The results are returned in the
+// order of the hosts.
+func (m *Manager) ApplyBIOSSettings(maxWorkers int, hostSettings map[string]map[string]interface{},
+	opts BIOSOptions) []HostBIOSResult {
+	results := make([]HostBIOSResult, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		attributes, ok := hostSettings[host.HostName]
+		if !ok {
+			results[i] = HostBIOSResult{HostName: host.HostName, Skipped: true}
+			return
+		}
+
+		changed, err := applyBIOSAttributes(host.Context, host.Client, attributes, opts)
+		results[i] = HostBIOSResult{HostName: host.HostName, Changed: changed, Err: err}
+	})
+	return results
+}
+
+// PrintBIOSResults writes the outcomes of applying BIOS settings with opts to w in the given format, see OutputTable,
+// OutputJSON and OutputYAML.
+func PrintBIOSResults(w io.Writer, results []HostBIOSResult, opts BIOSOptions, format string) error {
+	reports := make([]hostBIOSReport, len(results))
+	for i, result := range results {
+		report := hostBIOSReport{Host: result.HostName, Changed: result.Changed}
+		switch {
+		case result.Err != nil:
+			report.Result, report.Error = "failed", result.Err.Error()
+		case result.Skipped:
+			report.Result = "skipped"
+		case len(result.Changed) == 0:
+			report.Result = "up to date"
+		case !opts.Reboot:
+			report.Result = "staged"
+		default:
+			report.Result = "applied"
+		}
+		reports[i] = report
+	}
+
+	if format != OutputTable {
+		return writeReports(w, reports, format)
+	}
+
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "HOST\tRESULT\tCHANGED\tDETAILS")
+	for _, report := range reports {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", report.Host, report.Result, strings.Join(report.Changed, ","), report.Error)
+	}
+	return tw.Flush()
+}
+
+// applyBIOSAttributes stages the attributes that differ from desired, reboots the host and waits for them to take
+// effect if opts says so, and returns the names of the changed attributes.
+func applyBIOSAttributes(ctx context.Context, client Client, desired map[string]interface{},
+	opts BIOSOptions) ([]string, error) {
+	current, err := client.BIOSAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := biosDiff(current, desired)
+	if err != nil || len(changed) == 0 {
+		return nil, err
+	}
+
+	pending := make(map[string]interface{}, len(changed))
+	for _, name := range changed {
+		pending[name] = desired[name]
+	}
+
+	log.Debugf("Setting BIOS attributes %v of node '%s'.", changed, client.NodeID())
+	if err = client.SetBIOSAttributes(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	if !opts.Reboot {
+		return changed, nil
+	}
+
+	if err = client.RebootSystem(ctx); err != nil {
+		return nil, err
+	}
+
+	return changed, waitForBIOSAttributes(ctx, client, pending, opts)
+}
+
+// waitForBIOSAttributes polls the BIOS attributes of a host until they match desired. The BMC may fail to report them
+// while the host is going through POST, such errors are only returned once the verify timeout expired.
+func waitForBIOSAttributes(ctx context.Context, client Client, desired map[string]interface{},
+	opts BIOSOptions) error {
+	deadline := time.Now().Add(opts.VerifyTimeout)
+	for {
+		current, err := client.BIOSAttributes(ctx)
+		var mismatched []string
+		if err == nil {
+			mismatched, err = biosDiff(current, desired)
+		}
+
+		switch {
+		case err == nil && len(mismatched) == 0:
+			return nil
+		case time.Now().After(deadline) && err != nil:
+			return err
+		case time.Now().After(deadline):
+			return ErrBIOSSettingsNotApplied{Attributes: mismatched}
+		}
+
+		log.Debugf("Waiting for BIOS attributes of node '%s' to take effect.", client.NodeID())
+		time.Sleep(opts.PollInterval)
+	}
+}
+
+// biosDiff returns the sorted names of the desired attributes whose current value differs. Values are compared in
+// their string form, since numbers of documents and BMC responses are not decoded to the same types.
+func biosDiff(current, desired map[string]interface{}) ([]string, error) {
+	var changed, unknown []string
+	for name, value := range desired {
+		currentValue, ok := current[name]
+		switch {
+		case !ok:
+			unknown = append(unknown, name)
+		case fmt.Sprint(currentValue) != fmt.Sprint(value):
+			changed = append(changed, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, ErrUnknownBIOSAttributes{Attributes: unknown}
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
@@ -0,0 +1,177 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestBIOSSettings(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("bios"))
+
+	hostSettings, err := BIOSSettings(settings, config.BootstrapPhase)
+	require.NoError(t, err)
+	require.Len(t, hostSettings, 1)
+	assert.Equal(t, "Enabled", hostSettings["master-0"]["ProcVirtualization"])
+	assert.Len(t, hostSettings["master-0"], 4)
+
+	hostSettings, err = BIOSSettings(initSettings(t, withTestDataPath("base")), config.BootstrapPhase)
+	require.NoError(t, err)
+	assert.Empty(t, hostSettings)
+}
+
+func TestManagerApplyBIOSSettings(t *testing.T) {
+	desired := map[string]interface{}{
+		"ProcVirtualization": "Enabled",
+		"ProcCores":          int64(16),
+		"NumLock":            false,
+	}
+	// Numbers are decoded as float64 from BMC responses
+	before := map[string]interface{}{"ProcVirtualization": "Disabled", "ProcCores": float64(16), "NumLock": true}
+	after := map[string]interface{}{"ProcVirtualization": "Enabled", "ProcCores": float64(16), "NumLock": false}
+	pending := map[string]interface{}{"ProcVirtualization": "Enabled", "NumLock": false}
+
+	tests := []struct {
+		name     string
+		opts     BIOSOptions
+		mocks    func(m *redfishutils.MockClient)
+		expected HostBIOSResult
+	}{
+		{
+			name: "reboot-and-verify",
+			opts: BIOSOptions{Reboot: true, VerifyTimeout: time.Minute},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("BIOSAttributes", mock.Anything).Once().Return(before, nil)
+				m.On("SetBIOSAttributes", mock.Anything, pending).Once().Return(nil)
+				m.On("RebootSystem", mock.Anything).Once().Return(nil)
+				// The BMC is busy and still reports the old settings while the host goes through POST
+				m.On("BIOSAttributes", mock.Anything).Once().Return(nil, redfish.ErrRedfishClient{})
+				m.On("BIOSAttributes", mock.Anything).Once().Return(before, nil)
+				m.On("BIOSAttributes", mock.Anything).Once().Return(after, nil)
+			},
+			expected: HostBIOSResult{Changed: []string{"NumLock", "ProcVirtualization"}},
+		},
+		{
+			name: "without-reboot",
+			opts: BIOSOptions{},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("BIOSAttributes", mock.Anything).Once().Return(before, nil)
+				m.On("SetBIOSAttributes", mock.Anything, pending).Once().Return(nil)
+			},
+			expected: HostBIOSResult{Changed: []string{"NumLock", "ProcVirtualization"}},
+		},
+		{
+			name: "already-applied",
+			opts: BIOSOptions{Reboot: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("BIOSAttributes", mock.Anything).Once().Return(after, nil)
+			},
+			expected: HostBIOSResult{},
+		},
+		{
+			name: "not-applied",
+			opts: BIOSOptions{Reboot: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("BIOSAttributes", mock.Anything).Once().Return(before, nil)
+				m.On("SetBIOSAttributes", mock.Anything, pending).Once().Return(nil)
+				m.On("RebootSystem", mock.Anything).Once().Return(nil)
+				m.On("BIOSAttributes", mock.Anything).Return(before, nil)
+			},
+			expected: HostBIOSResult{
+				Changed: []string{"NumLock", "ProcVirtualization"},
+				Err:     ErrBIOSSettingsNotApplied{Attributes: []string{"NumLock", "ProcVirtualization"}},
+			},
+		},
+		{
+			name: "unknown-attribute",
+			opts: BIOSOptions{Reboot: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("BIOSAttributes", mock.Anything).Once().Return(map[string]interface{}{"NumLock": true}, nil)
+			},
+			expected: HostBIOSResult{
+				Err: ErrUnknownBIOSAttributes{Attributes: []string{"ProcCores", "ProcVirtualization"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+			require.NoError(t, err)
+			defer rMock.AssertExpectations(t)
+
+			rMock.On("NodeID").Maybe().Return(systemID)
+			tt.mocks(rMock)
+
+			m := &Manager{Hosts: []baremetalHost{
+				{rMock, ctx, redfishURL, "node01", username, password},
+				{rMock, ctx, redfishURL, "node02", username, password},
+			}}
+			tt.opts.PollInterval = time.Millisecond
+
+			expected := tt.expected
+			expected.HostName = "node01"
+			assert.Equal(t, []HostBIOSResult{expected, {HostName: "node02", Skipped: true}},
+				m.ApplyBIOSSettings(1, map[string]map[string]interface{}{"node01": desired}, tt.opts))
+		})
+	}
+}
+
+func TestPrintBIOSResults(t *testing.T) {
+	results := []HostBIOSResult{
+		{HostName: "node01", Changed: []string{"BootMode", "ProcVirtualization"}},
+		{HostName: "node02"},
+		{HostName: "node03", Skipped: true},
+		{HostName: "node04", Err: redfish.ErrRedfishClient{Message: "Unauthorized."}},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintBIOSResults(buf, results, DefaultBIOSOptions(), OutputTable))
+	assert.Equal(t, `HOST     RESULT       CHANGED                       DETAILS
+node01   applied      BootMode,ProcVirtualization   
+node02   up to date                                 
+node03   skipped                                    
+node04   failed                                     redfish client encountered an error: Unauthorized.
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintBIOSResults(buf, results, DefaultBIOSOptions(), OutputJSON))
+	assert.JSONEq(t, `[
+		{"host": "node01", "result": "applied", "changed": ["BootMode", "ProcVirtualization"]},
+		{"host": "node02", "result": "up to date"},
+		{"host": "node03", "result": "skipped"},
+		{"host": "node04", "result": "failed", "error": "redfish client encountered an error: Unauthorized."}
+	]`, buf.String())
+
+	// Settings applied without reboot are only staged
+	expected := `[{"host": "node01", "result": "staged", "changed": ["BootMode", "ProcVirtualization"]}]`
+	buf.Reset()
+	require.NoError(t, PrintBIOSResults(buf, results[:1], BIOSOptions{}, OutputYAML))
+	data, err := yaml.YAMLToJSON(buf.Bytes())
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+}
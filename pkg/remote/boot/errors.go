@@ -0,0 +1,33 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package boot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownSource is returned for a boot source that isn't one of Sources
+type ErrUnknownSource struct {
+	Source string
+}
+
+func (e ErrUnknownSource) Error() string {
+	names := make([]string, len(Sources))
+	for i, source := range Sources {
+		names[i] = string(source)
+	}
+	return fmt.Sprintf("unknown boot source %q, must be one of %s", e.Source, strings.Join(names, ", "))
+}
@@ -0,0 +1,31 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package boot
+
+// SecureBoot is the UEFI Secure Boot state of a host. Changes to Secure Boot only take effect on the next boot of
+// the host, until then Enabled and Active differ.
+type SecureBoot struct {
+	// Enabled is whether Secure Boot is enabled for the next boot
+	Enabled bool `json:"enabled"`
+	// Active is whether Secure Boot verified the current boot
+	Active bool `json:"active"`
+	// Mode is the Secure Boot mode reported by the firmware, e.g. UserMode or SetupMode
+	Mode string `json:"mode,omitempty"`
+}
+
+// Pending reports whether the host has to be rebooted for the Secure Boot setting to take effect.
+func (s SecureBoot) Pending() bool {
+	return s.Enabled != s.Active
+}
@@ -0,0 +1,45 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package boot translates boot devices between different management clients.
+package boot
+
+import (
+	"strings"
+)
+
+// Source is a device a baremetal host can boot from
+type Source string
+
+const (
+	// SourceCD boots a baremetal host from its virtual or physical CD/DVD drive.
+	SourceCD Source = "cd"
+	// SourceDisk boots a baremetal host from its local disk.
+	SourceDisk Source = "disk"
+	// SourcePXE boots a baremetal host from the network.
+	SourcePXE Source = "pxe"
+)
+
+// Sources are all boot sources supported by management clients
+var Sources = []Source{SourceCD, SourceDisk, SourcePXE}
+
+// ParseSource returns the boot source named s, case insensitive
+func ParseSource(s string) (Source, error) {
+	for _, source := range Sources {
+		if strings.EqualFold(s, string(source)) {
+			return source, nil
+		}
+	}
+	return "", ErrUnknownSource{Source: s}
+}
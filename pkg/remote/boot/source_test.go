@@ -0,0 +1,37 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package boot_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+func TestParseSource(t *testing.T) {
+	source, err := boot.ParseSource("PXE")
+	assert.NoError(t, err)
+	assert.Equal(t, boot.SourcePXE, source)
+
+	source, err = boot.ParseSource("disk")
+	assert.NoError(t, err)
+	assert.Equal(t, boot.SourceDisk, source)
+
+	_, err = boot.ParseSource("floppy")
+	assert.Equal(t, boot.ErrUnknownSource{Source: "floppy"}, err)
+	assert.Equal(t, `unknown boot source "floppy", must be one of cd, disk, pxe`, err.Error())
+}
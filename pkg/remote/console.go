@@ -0,0 +1,49 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ConsoleLog returns an action that writes the serial console output of a host to w. BMCs that buffer the console
+// return the buffer right away; for the others, e.g. IPMI, the console is followed for the given duration, or until
+// interrupted if it is 0.
+func ConsoleLog(w io.Writer, duration time.Duration) HostAction {
+	return func(ctx context.Context, client Client) error {
+		if duration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, duration)
+			defer cancel()
+		}
+
+		return client.ConsoleLog(ctx, w)
+	}
+}
+
+// ConsoleURL returns an action that passes the URL of the graphical console of a host to f, e.g. to print it or open
+// it in a browser. The BMC asks for its credentials when the URL is opened.
+func ConsoleURL(f func(url string) error) HostAction {
+	return func(ctx context.Context, client Client) error {
+		url, err := client.ConsoleURL(ctx)
+		if err != nil {
+			return err
+		}
+
+		return f(url)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestConsoleLog(t *testing.T) {
+	hasDeadline := func(expected bool) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ok == expected
+		})
+	}
+
+	for _, tt := range []struct {
+		duration time.Duration
+		deadline bool
+	}{
+		{duration: time.Minute, deadline: true},
+		{duration: 0, deadline: false},
+	} {
+		ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		rMock.On("ConsoleLog", hasDeadline(tt.deadline), buf).Once().Return(nil)
+
+		assert.NoError(t, ConsoleLog(buf, tt.duration)(ctx, rMock))
+		rMock.AssertExpectations(t)
+	}
+}
+
+func TestConsoleURL(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+	defer rMock.AssertExpectations(t)
+
+	rMock.On("ConsoleURL", ctx).Once().Return("https://192.168.111.1/console", nil)
+	rMock.On("ConsoleURL", ctx).Once().Return("", redfish.ErrGraphicalConsoleDisabled{NodeID: systemID})
+
+	var urls []string
+	action := ConsoleURL(func(url string) error {
+		urls = append(urls, url)
+		return nil
+	})
+	assert.NoError(t, action(ctx, rMock))
+	assert.Equal(t, redfish.ErrGraphicalConsoleDisabled{NodeID: systemID}, action(ctx, rMock))
+	assert.Equal(t, []string{"https://192.168.111.1/console"}, urls)
+}
@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// credentialResolver resolves the BMC credentials of baremetal hosts from the Secrets referenced by the
+// spec.bmc.credentialsName field of their documents. A Secret is taken from the documents if they contain it, and
+// read from the cluster of the current context otherwise, e.g. when credentials are managed outside of the
+// documents. The cluster client is only created when it is needed.
+type credentialResolver struct {
+	settings      *environment.AirshipCTLSettings
+	clientFactory client.Factory
+	client        client.Interface
+}
+
+func newCredentialResolver(settings *environment.AirshipCTLSettings, factory client.Factory) *credentialResolver {
+	return &credentialResolver{settings: settings, clientFactory: factory}
+}
+
+// resolve returns the BMC username and password of the host defined by hostDoc.
+func (r *credentialResolver) resolve(hostDoc document.Document,
+	docBundle document.Bundle) (username string, password string, err error) {
+	username, password, err = document.GetBMHBMCCredentials(hostDoc, docBundle)
+	if !errors.As(err, &document.ErrDocNotFound{}) {
+		return username, password, err
+	}
+
+	secretName, err := hostDoc.GetString("spec.bmc.credentialsName")
+	if err != nil {
+		return "", "", err
+	}
+
+	namespace := hostDoc.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	log.Debugf("BMC credentials secret %s/%s of host '%s' is not part of the documents, reading it from the cluster.",
+		namespace, secretName, hostDoc.GetName())
+	return r.clusterCredentials(hostDoc.GetName(), namespace, secretName)
+}
+
+// clusterCredentials reads the BMC credentials of a host from a Secret in the cluster.
+func (r *credentialResolver) clusterCredentials(hostName, namespace, secretName string) (string, string, error) {
+	if r.client == nil {
+		kclient, err := r.clientFactory(r.settings)
+		if err != nil {
+			return "", "", ErrBMCSecretNotFound{Host: hostName, Namespace: namespace, Secret: secretName, Err: err}
+		}
+		r.client = kclient
+	}
+
+	secret, err := r.client.ClientSet().CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", ErrBMCSecretNotFound{Host: hostName, Namespace: namespace, Secret: secretName, Err: err}
+	}
+
+	credentials := make(map[string]string, 2)
+	for _, key := range []string{"username", "password"} {
+		value, ok := secret.Data[key]
+		if !ok {
+			return "", "", document.ErrDocumentDataKeyNotFound{DocName: secretName, Key: key}
+		}
+		credentials[key] = string(value)
+	}
+
+	return credentials["username"], credentials["password"], nil
+}
@@ -0,0 +1,102 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+)
+
+func bmcSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-0-bmc-secret", Namespace: "metal3"},
+		Data:       data,
+	}
+}
+
+func fakeClientFactory(calls *int, objs ...*corev1.Secret) client.Factory {
+	return func(_ *environment.AirshipCTLSettings) (client.Interface, error) {
+		*calls++
+		var typed []runtime.Object
+		for _, obj := range objs {
+			typed = append(typed, obj)
+		}
+		return fake.NewClient(fake.WithTypedObjects(typed...)), nil
+	}
+}
+
+func TestNewManagerClusterCredentials(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("clustercreds"))
+
+	var calls int
+	secret := bmcSecret(map[string][]byte{"username": []byte("admin"), "password": []byte("secret")})
+	m, err := newManager(settings, config.BootstrapPhase, fakeClientFactory(&calls, secret),
+		ByLabel(document.EphemeralHostSelector))
+	require.NoError(t, err)
+	require.Len(t, m.Hosts, 1)
+	assert.Equal(t, "admin", m.Hosts[0].username)
+	assert.Equal(t, "secret", m.Hosts[0].password)
+	assert.Equal(t, 1, calls)
+}
+
+func TestNewManagerBundleCredentialsDontNeedCluster(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	var calls int
+	_, err := newManager(settings, config.BootstrapPhase, fakeClientFactory(&calls),
+		ByLabel(document.EphemeralHostSelector))
+	require.NoError(t, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestNewManagerClusterCredentialsErrors(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("clustercreds"))
+	var calls int
+
+	// Secret is missing in the cluster
+	_, err := newManager(settings, config.BootstrapPhase, fakeClientFactory(&calls),
+		ByLabel(document.EphemeralHostSelector))
+	assert.IsType(t, ErrBMCSecretNotFound{}, err)
+
+	// Secret lacks the password
+	secret := bmcSecret(map[string][]byte{"username": []byte("admin")})
+	_, err = newManager(settings, config.BootstrapPhase, fakeClientFactory(&calls, secret),
+		ByLabel(document.EphemeralHostSelector))
+	assert.Equal(t, document.ErrDocumentDataKeyNotFound{DocName: "master-0-bmc-secret", Key: "password"}, err)
+
+	// Cluster is not reachable
+	factoryErr := errors.New("no kubeconfig")
+	_, err = newManager(settings, config.BootstrapPhase,
+		func(_ *environment.AirshipCTLSettings) (client.Interface, error) { return nil, factoryErr },
+		ByLabel(document.EphemeralHostSelector))
+	assert.Equal(t, ErrBMCSecretNotFound{
+		Host:      "master-0",
+		Namespace: "metal3",
+		Secret:    "master-0-bmc-secret",
+		Err:       factoryErr,
+	}, err)
+}
@@ -0,0 +1,132 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+)
+
+// DryRun makes the actions run by the manager leave the hosts untouched. The operations that change the state of a
+// host only verify that the BMC could perform them, with read-only requests, and write the operation they would
+// perform to out. Operations that only read from the BMC are still performed.
+func (m *Manager) DryRun(out io.Writer) {
+	w := &dryRunWriter{out: out}
+	for i, host := range m.Hosts {
+		m.Hosts[i].Client = dryRunClient{Client: host.Client, host: host, out: w}
+	}
+}
+
+// dryRunWriter serializes the lines of hosts acted on in parallel.
+type dryRunWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// dryRunClient is a client that reports the operations changing the state of the host instead of performing them.
+type dryRunClient struct {
+	Client
+	host baremetalHost
+	out  *dryRunWriter
+}
+
+// report writes the operation described by format out, along with the BMC and user it would be performed with,
+// unless verifying that the BMC could perform it failed with err.
+func (c dryRunClient) report(err error, format string, args ...interface{}) error {
+	if err != nil {
+		return err
+	}
+
+	c.out.mu.Lock()
+	defer c.out.mu.Unlock()
+	_, err = fmt.Fprintf(c.out.out, "(dry run) Host '%s' (node '%s' at BMC '%s' as user '%s'): would %s\n",
+		c.host.HostName, c.NodeID(), c.host.BMCAddress, c.host.username, fmt.Sprintf(format, args...))
+	return err
+}
+
+// reportPower reports an operation that changes the power state of the host, along with the current one.
+func (c dryRunClient) reportPower(ctx context.Context, operation string) error {
+	status, err := c.SystemPowerStatus(ctx)
+	return c.report(err, "%s, power state is %s", operation, status)
+}
+
+// CreateRAIDVolume reports the creation of volume.
+func (c dryRunClient) CreateRAIDVolume(ctx context.Context, controller string, volume raid.Volume) error {
+	return c.report(c.VerifyCredentials(ctx), "create RAID volume '%s' on controller '%s'", volume.Name, controller)
+}
+
+// DeleteRAIDVolume reports the deletion of a volume.
+func (c dryRunClient) DeleteRAIDVolume(ctx context.Context, controller string, volumeID string) error {
+	return c.report(c.VerifyCredentials(ctx), "delete RAID volume '%s' of controller '%s'", volumeID, controller)
+}
+
+// EjectVirtualMedia reports ejecting the virtual media once verified that the host has some.
+func (c dryRunClient) EjectVirtualMedia(ctx context.Context) error {
+	return c.report(c.VerifyVirtualMedia(ctx), "eject all virtual media")
+}
+
+// RebootSystem reports rebooting the host.
+func (c dryRunClient) RebootSystem(ctx context.Context) error {
+	return c.reportPower(ctx, "reboot")
+}
+
+// SetBIOSAttributes reports setting attributes.
+func (c dryRunClient) SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) error {
+	return c.report(c.VerifyCredentials(ctx), "set BIOS attributes %v", attributes)
+}
+
+// SetBootSource reports setting the boot source.
+func (c dryRunClient) SetBootSource(ctx context.Context, source boot.Source, persistent bool) error {
+	when := "the next boot"
+	if persistent {
+		when = "all boots"
+	}
+	return c.report(c.VerifyCredentials(ctx), "boot from %s for %s", source, when)
+}
+
+// SetBootSourceByType reports booting from the virtual CD once.
+func (c dryRunClient) SetBootSourceByType(ctx context.Context) error {
+	return c.report(c.VerifyCredentials(ctx), "boot from the virtual CD on the next boot")
+}
+
+// SetSecureBoot reports enabling or disabling Secure Boot, along with its current state.
+func (c dryRunClient) SetSecureBoot(ctx context.Context, enabled bool) error {
+	operation := "disable Secure Boot"
+	if enabled {
+		operation = "enable Secure Boot"
+	}
+	state, err := c.SecureBoot(ctx)
+	return c.report(err, "%s, Secure Boot is %s", operation, secureBootState(state.Enabled))
+}
+
+// SetVirtualMedia reports inserting the ISO once verified that the host has virtual media that can take it.
+func (c dryRunClient) SetVirtualMedia(ctx context.Context, isoPath string) error {
+	return c.report(c.VerifyVirtualMedia(ctx), "insert ISO '%s' into the virtual CD", isoPath)
+}
+
+// SystemPowerOff reports powering off the host.
+func (c dryRunClient) SystemPowerOff(ctx context.Context) error {
+	return c.reportPower(ctx, "power off")
+}
+
+// SystemPowerOn reports powering on the host.
+func (c dryRunClient) SystemPowerOn(ctx context.Context) error {
+	return c.reportPower(ctx, "power on")
+}
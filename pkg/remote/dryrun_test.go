@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestManagerDryRun(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	rMock.On("NodeID").Maybe().Return(systemID)
+	rMock.On("SystemPowerStatus", ctx).Twice().Return(power.StatusOn, nil)
+	rMock.On("VerifyVirtualMedia", ctx).Once().Return(nil)
+	rMock.On("VerifyCredentials", ctx).Once().Return(nil)
+
+	out := &bytes.Buffer{}
+	m := &Manager{Hosts: []baremetalHost{{rMock, ctx, redfishURL, "node01", username, password}}}
+	m.DryRun(out)
+
+	attachISO, err := AttachISO(isoURL)
+	require.NoError(t, err)
+	results := m.RunAction(1, func(ctx context.Context, client Client) error {
+		if err := client.SystemPowerOff(ctx); err != nil {
+			return err
+		}
+		if err := attachISO(ctx, client); err != nil {
+			return err
+		}
+		return client.RebootSystem(ctx)
+	})
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Err)
+	prefix := "(dry run) Host 'node01' (node 'System.Embedded.1' at BMC '" + redfishURL + "' as user 'admin'): would "
+	assert.Equal(t, prefix+"power off, power state is ON\n"+
+		prefix+"insert ISO '"+isoURL+"' into the virtual CD\n"+
+		prefix+"boot from the virtual CD on the next boot\n"+
+		prefix+"reboot, power state is ON\n", out.String())
+
+	// None of the operations changing the host reached the client
+	rMock.AssertExpectations(t)
+}
+
+func TestManagerDryRunVerificationFailed(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	expectedErr := redfish.ErrRedfishClient{Message: "Unable to get /redfish/v1/Systems/System.Embedded.1."}
+	rMock.On("NodeID").Maybe().Return(systemID)
+	rMock.On("VerifyCredentials", ctx).Once().Return(expectedErr)
+
+	out := &bytes.Buffer{}
+	m := &Manager{Hosts: []baremetalHost{{rMock, ctx, redfishURL, "node01", username, password}}}
+	m.DryRun(out)
+
+	results := m.RunAction(1, func(ctx context.Context, client Client) error {
+		return client.SetBootSource(ctx, boot.SourcePXE, true)
+	})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, expectedErr, results[0].Err)
+	assert.Empty(t, out.String())
+	rMock.AssertExpectations(t)
+}
@@ -16,8 +16,11 @@ package remote
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	aerror "opendev.org/airship/airshipctl/pkg/errors"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
 )
 
 // TODO: This need to be refactored to match the error format used elsewhere in airshipctl
@@ -36,7 +39,7 @@ func NewRemoteDirectErrorf(format string, v ...interface{}) error {
 }
 
 // ErrUnknownManagementType is an error that indicates the remote type specified in the airshipctl management
-// configuration (e.g. redfish, redfish-dell) is not supported.
+// configuration (e.g. redfish, redfish-dell, ipmi) is not supported.
 type ErrUnknownManagementType struct {
 	aerror.AirshipError
 	Type string
@@ -57,9 +60,159 @@ func (e ErrMissingBootstrapInfoOption) Error() string {
 	return fmt.Sprintf("missing bootstrapInfo option: %s", e.What)
 }
 
+// ErrNoHostSelector is returned when hosts must be selected explicitly to perform an action on them, but no selector
+// was given.
+type ErrNoHostSelector struct{}
+
+func (e ErrNoHostSelector) Error() string {
+	return "no hosts selected, select them with --name, --labels or --namespace, or use --all to select all hosts"
+}
+
 // ErrNoHostsFound is an error that indicates that no hosts matched the selection criteria passed to a manager.
 type ErrNoHostsFound struct{}
 
 func (e ErrNoHostsFound) Error() string {
 	return "no hosts selected"
 }
+
+// ErrInvalidOutputFormat is returned for an unknown host list or report format.
+type ErrInvalidOutputFormat struct {
+	Format  string
+	Formats []string
+}
+
+func (e ErrInvalidOutputFormat) Error() string {
+	return fmt.Sprintf("invalid output format %q, must be one of %s", e.Format, strings.Join(e.Formats, ", "))
+}
+
+// ExitCodeHostsUnreachable is the exit code of airshipctl when the BMC of one or more hosts couldn't be reached.
+const ExitCodeHostsUnreachable = 3
+
+// ErrHostsUnreachable is returned when an action couldn't be performed on some hosts because their BMC couldn't be
+// reached.
+type ErrHostsUnreachable struct {
+	Hosts []string
+}
+
+func (e ErrHostsUnreachable) Error() string {
+	return fmt.Sprintf("unable to reach the BMC of hosts: %s", strings.Join(e.Hosts, ", "))
+}
+
+// ExitCode makes airshipctl terminate with ExitCodeHostsUnreachable.
+func (e ErrHostsUnreachable) ExitCode() int {
+	return ExitCodeHostsUnreachable
+}
+
+// ErrHostActionFailed is returned when an action performed by a manager failed on some of its hosts.
+type ErrHostActionFailed struct {
+	Hosts []string
+	Total int
+}
+
+func (e ErrHostActionFailed) Error() string {
+	return fmt.Sprintf("action failed on %d of %d hosts: %s", len(e.Hosts), e.Total, strings.Join(e.Hosts, ", "))
+}
+
+// ErrBMCSecretNotFound is returned when the Secret holding the BMC credentials of a host is neither part of the
+// documents nor can be read from the cluster.
+type ErrBMCSecretNotFound struct {
+	Host      string
+	Namespace string
+	Secret    string
+	Err       error
+}
+
+func (e ErrBMCSecretNotFound) Error() string {
+	return fmt.Sprintf("BMC credentials secret %s/%s of host %s is not part of the documents and could not be read "+
+		"from the cluster: %v", e.Namespace, e.Secret, e.Host, e.Err)
+}
+
+// ErrInvalidISOURL is returned when the location of an ISO image to attach to the virtual CD of a host is not an
+// HTTP or HTTPS URL.
+type ErrInvalidISOURL struct {
+	URL string
+}
+
+func (e ErrInvalidISOURL) Error() string {
+	return fmt.Sprintf("invalid ISO URL %q, must be an http or https URL", e.URL)
+}
+
+// ErrProvisionTimeout is returned when a host did not become a Ready node of the cluster in time.
+type ErrProvisionTimeout struct {
+	Node    string
+	Timeout time.Duration
+	State   string
+}
+
+func (e ErrProvisionTimeout) Error() string {
+	return fmt.Sprintf("node %s was not ready after %s, last state: %s", e.Node, e.Timeout, e.State)
+}
+
+// ErrDuplicateBIOSSettings is returned when more than one BIOSSettings document applies to the same host.
+type ErrDuplicateBIOSSettings struct {
+	Host string
+}
+
+func (e ErrDuplicateBIOSSettings) Error() string {
+	return fmt.Sprintf("more than one BIOSSettings document for host %s", e.Host)
+}
+
+// ErrUnknownBIOSAttributes is returned when BIOS settings contain attributes the BIOS of a host doesn't have.
+type ErrUnknownBIOSAttributes struct {
+	Attributes []string
+}
+
+func (e ErrUnknownBIOSAttributes) Error() string {
+	return fmt.Sprintf("unknown BIOS attributes: %s", strings.Join(e.Attributes, ", "))
+}
+
+// ErrBIOSSettingsNotApplied is returned when BIOS attributes didn't take effect after the reboot of a host.
+type ErrBIOSSettingsNotApplied struct {
+	Attributes []string
+}
+
+func (e ErrBIOSSettingsNotApplied) Error() string {
+	return fmt.Sprintf("BIOS attributes were not applied after the reboot: %s", strings.Join(e.Attributes, ", "))
+}
+
+// ErrDuplicateRAIDConfig is returned when more than one RAIDConfig document applies to the same host.
+type ErrDuplicateRAIDConfig struct {
+	Host string
+}
+
+func (e ErrDuplicateRAIDConfig) Error() string {
+	return fmt.Sprintf("more than one RAIDConfig document for host %s", e.Host)
+}
+
+// ErrRAIDVolumeMismatch is returned when a declared RAID volume exists with another RAID type or other drives.
+// Such a volume is never replaced, it has to be deleted first.
+type ErrRAIDVolumeMismatch struct {
+	Volume  string
+	Current raid.Volume
+	Desired raid.Volume
+}
+
+func (e ErrRAIDVolumeMismatch) Error() string {
+	return fmt.Sprintf("RAID volume %s exists as %s on drives %s instead of %s on drives %s", e.Volume,
+		e.Current.RAIDType, strings.Join(e.Current.Drives, ", "), e.Desired.RAIDType,
+		strings.Join(e.Desired.Drives, ", "))
+}
+
+// ErrCheckFailed is the reason a health check is skipped when a check it depends on failed.
+type ErrCheckFailed struct {
+	Check string
+}
+
+func (e ErrCheckFailed) Error() string {
+	return fmt.Sprintf("check %s failed", e.Check)
+}
+
+// ErrBMCEventFailure is returned when the BMC of a host recorded a critical event while the host was provisioned.
+type ErrBMCEventFailure struct {
+	Host    string
+	Message string
+}
+
+func (e ErrBMCEventFailure) Error() string {
+	return fmt.Sprintf("BMC of host %s reported a failure: %s", e.Host, e.Message)
+}
@@ -0,0 +1,104 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+)
+
+// EventMonitor reports the events the BMC of a host records while airshipctl provisions it, so that failures to
+// insert virtual media, to boot or of BMC tasks show up instead of ending in a timeout.
+type EventMonitor struct {
+	host baremetalHost
+	// Out receives a line for every new event the BMC rated as a warning or failure
+	Out io.Writer
+
+	seen      map[string]bool
+	seeded    bool
+	supported bool
+}
+
+// MonitorEvents returns a monitor of the events the BMC of the host records from now on. The events recorded before
+// are not reported. The monitor of a host whose BMC can't report events, e.g. over IPMI, never reports any.
+func (b baremetalHost) MonitorEvents(out io.Writer) *EventMonitor {
+	m := &EventMonitor{
+		host:      b,
+		Out:       out,
+		seen:      map[string]bool{},
+		supported: true,
+	}
+	m.newEvents()
+	return m
+}
+
+// Poll writes the events the BMC recorded since the last poll to Out and returns ErrBMCEventFailure for the first
+// failure among them. Errors retrieving the events are only logged, a BMC busy booting the host doesn't always
+// answer.
+func (m *EventMonitor) Poll() error {
+	var failure error
+	for _, event := range m.newEvents() {
+		if event.Severity == events.SeverityOK {
+			log.Debugf("BMC of host '%s' recorded event: %s", m.host.HostName, event.Message)
+			continue
+		}
+
+		fmt.Fprintf(m.Out, "Host '%s': BMC recorded %s event: %s\n", m.host.HostName, event.Severity, event.Message)
+		if event.Failed() && failure == nil {
+			failure = ErrBMCEventFailure{Host: m.host.HostName, Message: event.Message}
+		}
+	}
+	return failure
+}
+
+// newEvents retrieves the events of the host that weren't seen before, oldest first. The first events retrieved are
+// only marked as seen.
+func (m *EventMonitor) newEvents() []events.Event {
+	if !m.supported {
+		return nil
+	}
+
+	all, err := m.host.Events(m.host.Context)
+	if _, ok := err.(ipmi.ErrOperationNotSupported); ok {
+		log.Debugf("Not monitoring the events of host '%s'. %v", m.host.HostName, err)
+		m.supported = false
+		return nil
+	}
+	if err != nil {
+		log.Debugf("Unable to retrieve the events of host '%s'. %v", m.host.HostName, err)
+		return nil
+	}
+
+	var result []events.Event
+	for _, event := range all {
+		// A record is reported again when its state changes, e.g. when a task fails
+		key := fmt.Sprintf("%s|%s|%s", event.ID, event.Severity, event.Message)
+		if !m.seen[key] && m.seeded {
+			result = append(result, event)
+		}
+		m.seen[key] = true
+	}
+	m.seeded = true
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
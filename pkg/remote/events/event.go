@@ -0,0 +1,43 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package events describes the events BMCs record about baremetal hosts, such as the entries of their event logs and
+// the outcome of the tasks they run.
+package events
+
+import "time"
+
+// Severity is how serious an event is, as rated by the BMC.
+type Severity string
+
+// Severities of Redfish events
+const (
+	SeverityOK       Severity = "OK"
+	SeverityWarning  Severity = "Warning"
+	SeverityCritical Severity = "Critical"
+)
+
+// Event is an event recorded by the BMC of a host. ID identifies the record of the event on the BMC, the same record
+// may be reported again with another severity or message when its state changes, e.g. a task that failed.
+type Event struct {
+	ID       string
+	Time     time.Time
+	Severity Severity
+	Message  string
+}
+
+// Failed reports whether the event is a failure of the host or of an operation of the BMC.
+func (e Event) Failed() bool {
+	return e.Severity == SeverityCritical
+}
@@ -0,0 +1,81 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestEventMonitor(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	booted := events.Event{ID: "/Sel/1", Severity: events.SeverityOK, Message: "The system booted."}
+	taskRunning := events.Event{ID: "/Tasks/1", Severity: events.SeverityOK, Message: "Task 'Mount' is Running"}
+	taskFailed := events.Event{
+		ID:       "/Tasks/1",
+		Time:     time.Date(2020, 6, 1, 10, 5, 0, 0, time.UTC),
+		Severity: events.SeverityCritical,
+		Message:  "Task 'Mount' is Exception",
+	}
+	noBootDevice := events.Event{
+		ID:       "/Sel/2",
+		Time:     time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+		Severity: events.SeverityWarning,
+		Message:  "No bootable device.",
+	}
+	rMock.On("Events", ctx).Once().Return([]events.Event{booted, taskRunning}, nil)
+	rMock.On("Events", ctx).Once().Return([]events.Event{booted, taskRunning}, nil)
+	rMock.On("Events", ctx).Once().Return(nil, errors.New("connection reset"))
+	rMock.On("Events", ctx).Once().Return([]events.Event{booted, taskFailed, noBootDevice}, nil)
+
+	out := &bytes.Buffer{}
+	host := baremetalHost{rMock, ctx, redfishURL, "node01", username, password}
+	monitor := host.MonitorEvents(out)
+
+	assert.NoError(t, monitor.Poll())
+	assert.NoError(t, monitor.Poll())
+	assert.Empty(t, out.String())
+
+	err = monitor.Poll()
+	assert.Equal(t, ErrBMCEventFailure{Host: "node01", Message: "Task 'Mount' is Exception"}, err)
+	assert.Equal(t, "Host 'node01': BMC recorded Warning event: No bootable device.\n"+
+		"Host 'node01': BMC recorded Critical event: Task 'Mount' is Exception\n", out.String())
+	rMock.AssertExpectations(t)
+}
+
+func TestEventMonitorNotSupported(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	rMock.On("Events", ctx).Once().Return(nil, ipmi.ErrOperationNotSupported{Operation: "retrieve BMC events"})
+
+	out := &bytes.Buffer{}
+	monitor := baremetalHost{rMock, ctx, redfishURL, "node01", username, password}.MonitorEvents(out)
+
+	assert.NoError(t, monitor.Poll())
+	assert.Empty(t, out.String())
+	rMock.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package hardware describes the hardware and firmware of baremetal hosts as reported by their BMC.
+package hardware
+
+// Inventory is the hardware and firmware of a baremetal host.
+type Inventory struct {
+	Manufacturer string     `json:"manufacturer,omitempty"`
+	Model        string     `json:"model,omitempty"`
+	SerialNumber string     `json:"serialNumber,omitempty"`
+	BIOSVersion  string     `json:"biosVersion,omitempty"`
+	Firmware     []Firmware `json:"firmware"`
+	NICs         []NIC      `json:"nics"`
+	Disks        []Disk     `json:"disks"`
+}
+
+// Firmware is a firmware component of a host, e.g. of the BMC, BIOS or a NIC.
+type Firmware struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version"`
+}
+
+// NIC is a network interface of a host.
+type NIC struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	MACAddress string `json:"macAddress,omitempty"`
+	SpeedMbps  int    `json:"speedMbps,omitempty"`
+}
+
+// Disk is a drive of a host.
+type Disk struct {
+	ID            string `json:"id"`
+	Name          string `json:"name,omitempty"`
+	Model         string `json:"model,omitempty"`
+	SerialNumber  string `json:"serialNumber,omitempty"`
+	MediaType     string `json:"mediaType,omitempty"`
+	CapacityBytes int64  `json:"capacityBytes,omitempty"`
+	Revision      string `json:"revision,omitempty"`
+}
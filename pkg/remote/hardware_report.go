@@ -0,0 +1,127 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+)
+
+// csvInventoryHeader is the header of hardware inventory reports in OutputCSV format. Every row describes one
+// component of a host: the system itself, whose version is the BIOS version, a firmware, a NIC or a disk.
+var csvInventoryHeader = []string{
+	"host", "bmc_address", "component", "id", "name", "manufacturer", "model", "serial_number", "version",
+	"mac_address", "speed_mbps", "capacity_bytes", "media_type",
+}
+
+// HostInventory is the hardware inventory of a host, or the error that prevented retrieving it.
+type HostInventory struct {
+	HostName   string
+	BMCAddress string
+	Inventory  hardware.Inventory
+	Err        error
+}
+
+// HardwareInventory retrieves the hardware inventory of all hosts of the manager, querying at most maxWorkers BMCs at
+// a time, see RunAction. The inventories are returned in the order of the hosts.
+func (m *Manager) HardwareInventory(maxWorkers int) []HostInventory {
+	inventories := make([]HostInventory, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		inventory, err := host.HardwareInventory(host.Context)
+		inventories[i] = HostInventory{
+			HostName:   host.HostName,
+			BMCAddress: host.BMCAddress,
+			Inventory:  inventory,
+			Err:        err,
+		}
+	})
+	return inventories
+}
+
+// inventoryReport is a host inventory as written in OutputJSON format.
+type inventoryReport struct {
+	Host       string `json:"host"`
+	BMCAddress string `json:"bmcAddress"`
+	hardware.Inventory
+}
+
+// PrintInventory writes a consolidated report of the hardware inventories of hosts to w in the given format, see
+// OutputJSON and OutputCSV. Hosts whose inventory couldn't be retrieved are left out.
+func PrintInventory(w io.Writer, inventories []HostInventory, format string) error {
+	switch format {
+	case OutputJSON:
+		reports := make([]inventoryReport, 0, len(inventories))
+		for _, inv := range inventories {
+			if inv.Err == nil {
+				reports = append(reports, inventoryReport{inv.HostName, inv.BMCAddress, inv.Inventory})
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(reports)
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvInventoryHeader); err != nil {
+			return err
+		}
+		for _, inv := range inventories {
+			if inv.Err != nil {
+				continue
+			}
+			if err := cw.WriteAll(inventoryRows(inv)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: []string{OutputJSON, OutputCSV}}
+	}
+}
+
+// inventoryRows lays out the inventory of a host as rows of csvInventoryHeader.
+func inventoryRows(inv HostInventory) [][]string {
+	row := func(component, id, name, manufacturer, model, serial, version, mac string,
+		speed int, capacity int64, mediaType string) []string {
+		return []string{inv.HostName, inv.BMCAddress, component, id, name, manufacturer, model, serial, version, mac,
+			formatInt(int64(speed)), formatInt(capacity), mediaType}
+	}
+
+	hw := inv.Inventory
+	rows := [][]string{row("system", "", "", hw.Manufacturer, hw.Model, hw.SerialNumber, hw.BIOSVersion, "", 0, 0, "")}
+	for _, fw := range hw.Firmware {
+		rows = append(rows, row("firmware", fw.ID, fw.Name, "", "", "", fw.Version, "", 0, 0, ""))
+	}
+	for _, nic := range hw.NICs {
+		rows = append(rows, row("nic", nic.ID, nic.Name, "", "", "", "", nic.MACAddress, nic.SpeedMbps, 0, ""))
+	}
+	for _, disk := range hw.Disks {
+		rows = append(rows, row("disk", disk.ID, disk.Name, "", disk.Model, disk.SerialNumber, disk.Revision, "", 0,
+			disk.CapacityBytes, disk.MediaType))
+	}
+	return rows
+}
+
+// formatInt renders unknown, i.e. zero, values as empty cells.
+func formatInt(i int64) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.FormatInt(i, 10)
+}
@@ -0,0 +1,96 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+var testInventory = hardware.Inventory{
+	Manufacturer: "Dell Inc.",
+	Model:        "PowerEdge R640",
+	SerialNumber: "CN123",
+	BIOSVersion:  "2.8.1",
+	Firmware:     []hardware.Firmware{{ID: "Installed-25227-4.20", Name: "iDRAC", Version: "4.20.20.20"}},
+	NICs:         []hardware.NIC{{ID: "NIC.Integrated.1-1-1", MACAddress: "E4:43:4B:0A:1B:2C", SpeedMbps: 10000}},
+	Disks: []hardware.Disk{
+		{ID: "Disk.Bay.0", Model: "MZ7KH480HAHQ0D3", MediaType: "SSD", CapacityBytes: 480103981056},
+	},
+}
+
+func TestManagerHardwareInventory(t *testing.T) {
+	var hosts []baremetalHost
+	for _, name := range []string{"node01", "node02"} {
+		ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+		require.NoError(t, err)
+
+		switch name {
+		case "node02":
+			rMock.On("HardwareInventory", ctx).Return(hardware.Inventory{}, redfish.ErrRedfishClient{})
+		default:
+			rMock.On("HardwareInventory", ctx).Return(testInventory, nil)
+		}
+
+		hosts = append(hosts, baremetalHost{rMock, ctx, redfishURL, name, username, password})
+	}
+
+	m := &Manager{Hosts: hosts}
+	assert.Equal(t, []HostInventory{
+		{HostName: "node01", BMCAddress: redfishURL, Inventory: testInventory},
+		{HostName: "node02", BMCAddress: redfishURL, Err: redfish.ErrRedfishClient{}},
+	}, m.HardwareInventory(0))
+}
+
+func TestPrintInventory(t *testing.T) {
+	inventories := []HostInventory{
+		{HostName: "node01", BMCAddress: "redfish+https://10.0.0.1/Systems/1", Inventory: testInventory},
+		{HostName: "node02", BMCAddress: "ipmi://10.0.0.2", Err: redfish.ErrRedfishClient{}},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintInventory(buf, inventories, OutputCSV))
+	assert.Equal(t, `host,bmc_address,component,id,name,manufacturer,model,serial_number,version,mac_address,`+
+		`speed_mbps,capacity_bytes,media_type
+node01,redfish+https://10.0.0.1/Systems/1,system,,,Dell Inc.,PowerEdge R640,CN123,2.8.1,,,,
+node01,redfish+https://10.0.0.1/Systems/1,firmware,Installed-25227-4.20,iDRAC,,,,4.20.20.20,,,,
+node01,redfish+https://10.0.0.1/Systems/1,nic,NIC.Integrated.1-1-1,,,,,,E4:43:4B:0A:1B:2C,10000,,
+node01,redfish+https://10.0.0.1/Systems/1,disk,Disk.Bay.0,,,MZ7KH480HAHQ0D3,,,,,480103981056,SSD
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintInventory(buf, inventories, OutputJSON))
+	assert.JSONEq(t, `[{
+		"host": "node01",
+		"bmcAddress": "redfish+https://10.0.0.1/Systems/1",
+		"manufacturer": "Dell Inc.",
+		"model": "PowerEdge R640",
+		"serialNumber": "CN123",
+		"biosVersion": "2.8.1",
+		"firmware": [{"id": "Installed-25227-4.20", "name": "iDRAC", "version": "4.20.20.20"}],
+		"nics": [{"id": "NIC.Integrated.1-1-1", "macAddress": "E4:43:4B:0A:1B:2C", "speedMbps": 10000}],
+		"disks": [{"id": "Disk.Bay.0", "model": "MZ7KH480HAHQ0D3", "mediaType": "SSD", "capacityBytes": 480103981056}]
+	}]`, buf.String())
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "table", Formats: []string{OutputJSON, OutputCSV}},
+		PrintInventory(buf, inventories, OutputTable))
+}
@@ -0,0 +1,177 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Names of the checks run by Manager.HealthCheck, in the order they run
+const (
+	CheckBMCReachable = "bmc-reachable"
+	CheckCredentials  = "credentials"
+	CheckPowerState   = "power-state"
+	CheckVirtualMedia = "virtual-media"
+)
+
+// CheckStatus is the outcome of a health check.
+type CheckStatus string
+
+// Outcomes of a health check. A check is skipped when the client of a host can't run it, or when a check it depends
+// on failed.
+const (
+	CheckPassed  CheckStatus = "pass"
+	CheckFailed  CheckStatus = "fail"
+	CheckSkipped CheckStatus = "skip"
+)
+
+// CheckResult is the outcome of a health check of a host, with the error that failed or skipped it.
+type CheckResult struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Err    error       `json:"-"`
+}
+
+// MarshalJSON renders the error of the result as its message.
+func (r CheckResult) MarshalJSON() ([]byte, error) {
+	type result CheckResult
+	details := ""
+	if r.Err != nil {
+		details = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		result
+		Details string `json:"details,omitempty"`
+	}{result(r), details})
+}
+
+// HostHealth is the outcome of the health checks of a host.
+type HostHealth struct {
+	HostName string        `json:"host"`
+	Checks   []CheckResult `json:"checks"`
+}
+
+// Healthy reports whether none of the checks of the host failed.
+func (h HostHealth) Healthy() bool {
+	for _, check := range h.Checks {
+		if check.Status == CheckFailed {
+			return false
+		}
+	}
+	return true
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context, client Client) error
+	// required checks skip the following ones when they fail
+	required bool
+}
+
+var healthChecks = []healthCheck{
+	{
+		name:     CheckBMCReachable,
+		check:    func(ctx context.Context, client Client) error { return client.Ping(ctx) },
+		required: true,
+	},
+	{
+		name:     CheckCredentials,
+		check:    func(ctx context.Context, client Client) error { return client.VerifyCredentials(ctx) },
+		required: true,
+	},
+	{
+		name: CheckPowerState,
+		check: func(ctx context.Context, client Client) error {
+			_, err := client.SystemPowerStatus(ctx)
+			return err
+		},
+	},
+	{
+		name:  CheckVirtualMedia,
+		check: func(ctx context.Context, client Client) error { return client.VerifyVirtualMedia(ctx) },
+	},
+}
+
+// HealthCheck checks that the BMCs of the hosts of the manager are reachable, accept their credentials, report the
+// power state of the hosts and have virtual media to boot them from, checking at most maxWorkers hosts at a time, see
+// RunAction. The outcomes are returned in the order of the hosts. Hosts aren't changed by the checks.
+func (m *Manager) HealthCheck(maxWorkers int) []HostHealth {
+	results := make([]HostHealth, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		results[i] = HostHealth{HostName: host.HostName, Checks: checkHealth(host.Context, host.Client)}
+	})
+	return results
+}
+
+func checkHealth(ctx context.Context, client Client) []CheckResult {
+	results := make([]CheckResult, 0, len(healthChecks))
+	var failed string
+	for _, hc := range healthChecks {
+		if failed != "" {
+			results = append(results, CheckResult{Name: hc.name, Status: CheckSkipped, Err: ErrCheckFailed{Check: failed}})
+			continue
+		}
+
+		err := hc.check(ctx, client)
+		switch err.(type) {
+		case nil:
+			results = append(results, CheckResult{Name: hc.name, Status: CheckPassed})
+		case ipmi.ErrOperationNotSupported:
+			// Virtual media are required to deploy hosts, other checks are just not available
+			if hc.name != CheckVirtualMedia {
+				results = append(results, CheckResult{Name: hc.name, Status: CheckSkipped, Err: err})
+				continue
+			}
+			results = append(results, CheckResult{Name: hc.name, Status: CheckFailed, Err: err})
+		default:
+			results = append(results, CheckResult{Name: hc.name, Status: CheckFailed, Err: err})
+			if hc.required {
+				failed = hc.name
+			}
+		}
+	}
+	return results
+}
+
+// PrintHealth writes the outcomes of health checks to w in the given format, see OutputTable and OutputJSON.
+func PrintHealth(w io.Writer, results []HostHealth, format string) error {
+	switch format {
+	case OutputTable:
+		tw := util.NewTabWriter(w)
+		fmt.Fprintln(tw, "HOST\tCHECK\tSTATUS\tDETAILS")
+		for _, host := range results {
+			for _, check := range host.Checks {
+				details := ""
+				if check.Err != nil {
+					details = check.Err.Error()
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", host.HostName, check.Name, check.Status, details)
+			}
+		}
+		return tw.Flush()
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(results)
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: []string{OutputTable, OutputJSON}}
+	}
+}
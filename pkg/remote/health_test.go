@@ -0,0 +1,150 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestManagerHealthCheck(t *testing.T) {
+	unreachable := redfish.ErrRedfishClient{Message: "Unable to reach BMC at https://192.168.111.1."}
+	noMedia := redfish.ErrRedfishClient{Message: "Manager 'iDRAC.Embedded.1' does not have virtual media type CD or DVD."}
+
+	tests := []struct {
+		name     string
+		mocks    func(m *redfishutils.MockClient)
+		expected []CheckResult
+		healthy  bool
+	}{
+		{
+			name: "healthy",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("Ping", mock.Anything).Once().Return(nil)
+				m.On("VerifyCredentials", mock.Anything).Once().Return(nil)
+				m.On("SystemPowerStatus", mock.Anything).Once().Return(power.StatusOn, nil)
+				m.On("VerifyVirtualMedia", mock.Anything).Once().Return(nil)
+			},
+			expected: []CheckResult{
+				{Name: CheckBMCReachable, Status: CheckPassed},
+				{Name: CheckCredentials, Status: CheckPassed},
+				{Name: CheckPowerState, Status: CheckPassed},
+				{Name: CheckVirtualMedia, Status: CheckPassed},
+			},
+			healthy: true,
+		},
+		{
+			name: "unreachable",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("Ping", mock.Anything).Once().Return(unreachable)
+			},
+			expected: []CheckResult{
+				{Name: CheckBMCReachable, Status: CheckFailed, Err: unreachable},
+				{Name: CheckCredentials, Status: CheckSkipped, Err: ErrCheckFailed{Check: CheckBMCReachable}},
+				{Name: CheckPowerState, Status: CheckSkipped, Err: ErrCheckFailed{Check: CheckBMCReachable}},
+				{Name: CheckVirtualMedia, Status: CheckSkipped, Err: ErrCheckFailed{Check: CheckBMCReachable}},
+			},
+		},
+		{
+			name: "no-virtual-media",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("Ping", mock.Anything).Once().Return(nil)
+				m.On("VerifyCredentials", mock.Anything).Once().Return(nil)
+				m.On("SystemPowerStatus", mock.Anything).Once().Return(power.StatusOff, nil)
+				m.On("VerifyVirtualMedia", mock.Anything).Once().Return(noMedia)
+			},
+			expected: []CheckResult{
+				{Name: CheckBMCReachable, Status: CheckPassed},
+				{Name: CheckCredentials, Status: CheckPassed},
+				{Name: CheckPowerState, Status: CheckPassed},
+				{Name: CheckVirtualMedia, Status: CheckFailed, Err: noMedia},
+			},
+		},
+		{
+			name: "ipmi",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("Ping", mock.Anything).Once().Return(ipmi.ErrOperationNotSupported{Operation: "ping"})
+				m.On("VerifyCredentials", mock.Anything).Once().Return(nil)
+				m.On("SystemPowerStatus", mock.Anything).Once().Return(power.StatusOn, nil)
+				m.On("VerifyVirtualMedia", mock.Anything).Once().
+					Return(ipmi.ErrOperationNotSupported{Operation: "insert virtual media"})
+			},
+			expected: []CheckResult{
+				{Name: CheckBMCReachable, Status: CheckSkipped, Err: ipmi.ErrOperationNotSupported{Operation: "ping"}},
+				{Name: CheckCredentials, Status: CheckPassed},
+				{Name: CheckPowerState, Status: CheckPassed},
+				{
+					Name:   CheckVirtualMedia,
+					Status: CheckFailed,
+					Err:    ipmi.ErrOperationNotSupported{Operation: "insert virtual media"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+			require.NoError(t, err)
+			defer rMock.AssertExpectations(t)
+			tt.mocks(rMock)
+
+			m := &Manager{Hosts: []baremetalHost{{rMock, ctx, redfishURL, "node01", username, password}}}
+			results := m.HealthCheck(1)
+			assert.Equal(t, []HostHealth{{HostName: "node01", Checks: tt.expected}}, results)
+			assert.Equal(t, tt.healthy, results[0].Healthy())
+		})
+	}
+}
+
+func TestPrintHealth(t *testing.T) {
+	results := []HostHealth{{
+		HostName: "node01",
+		Checks: []CheckResult{
+			{Name: CheckBMCReachable, Status: CheckPassed},
+			{Name: CheckCredentials, Status: CheckFailed, Err: redfish.ErrRedfishClient{Message: "Unauthorized."}},
+			{Name: CheckPowerState, Status: CheckSkipped, Err: ErrCheckFailed{Check: CheckCredentials}},
+		},
+	}}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintHealth(buf, results, OutputTable))
+	assert.Equal(t, `HOST     CHECK           STATUS   DETAILS
+node01   bmc-reachable   pass     
+node01   credentials     fail     redfish client encountered an error: Unauthorized.
+node01   power-state     skip     check credentials failed
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintHealth(buf, results[:1], OutputJSON))
+	assert.JSONEq(t, `[{"host": "node01", "checks": [
+		{"name": "bmc-reachable", "status": "pass"},
+		{"name": "credentials", "status": "fail", "details": "redfish client encountered an error: Unauthorized."},
+		{"name": "power-state", "status": "skip", "details": "check credentials failed"}
+	]}]`, buf.String())
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "csv", Formats: []string{OutputTable, OutputJSON}},
+		PrintHealth(buf, results, "csv"))
+}
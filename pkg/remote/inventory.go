@@ -0,0 +1,136 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	// HostRoleLabel is the label of a BareMetalHost document that holds the role of the host, e.g. worker
+	HostRoleLabel = document.BaseAirshipSelector + "/k8s-role"
+	// EphemeralHostLabel is the label that marks the BareMetalHost document of the ephemeral host
+	EphemeralHostLabel = document.BaseAirshipSelector + "/ephemeral-node"
+
+	// HostRoleEphemeral is reported as the role of the ephemeral host
+	HostRoleEphemeral = "ephemeral"
+
+	// OutputTable, OutputJSON, OutputYAML and OutputCSV are the formats host lists and reports can be printed in
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+)
+
+// HostInfo describes a baremetal host defined by a BareMetalHost document.
+type HostInfo struct {
+	Name       string            `json:"name"`
+	BMCAddress string            `json:"bmcAddress"`
+	Role       string            `json:"role,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// ListHosts returns the baremetal hosts defined in the documents of the given phase for the current context, sorted
+// by name. Only hosts matching name, labels and namespace are returned, empty values match all hosts. Unlike a
+// Manager, listing hosts doesn't require a management configuration or BMC credentials.
+func ListHosts(settings *environment.AirshipCTLSettings,
+	phase string,
+	name string,
+	labels string,
+	namespace string) ([]HostInfo, error) {
+	docBundle, err := currentContextBundle(settings, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := document.NewSelector().ByKind(document.BareMetalHostKind)
+	if name != "" {
+		selector = selector.ByName(name)
+	}
+	if labels != "" {
+		selector = selector.ByLabel(labels)
+	}
+	if namespace != "" {
+		selector = selector.ByNamespace(namespace)
+	}
+
+	docs, err := selectHostDocuments(docBundle, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]HostInfo, 0, len(docs))
+	for _, doc := range docs {
+		address, err := document.GetBMHBMCAddress(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		hosts = append(hosts, HostInfo{
+			Name:       doc.GetName(),
+			BMCAddress: address,
+			Role:       hostRole(doc.GetLabels()),
+			Labels:     doc.GetLabels(),
+		})
+	}
+
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+	return hosts, nil
+}
+
+// hostRole determines the role of a host from the labels of its document.
+func hostRole(labels map[string]string) string {
+	if strings.EqualFold(labels[EphemeralHostLabel], "true") {
+		return HostRoleEphemeral
+	}
+	return labels[HostRoleLabel]
+}
+
+// PrintHosts writes hosts to w in the given format, see OutputTable and OutputJSON.
+func PrintHosts(w io.Writer, hosts []HostInfo, format string) error {
+	switch format {
+	case OutputTable:
+		tw := util.NewTabWriter(w)
+		fmt.Fprintln(tw, "NAME\tBMC ADDRESS\tROLE\tLABELS")
+		for _, host := range hosts {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", host.Name, host.BMCAddress, host.Role, formatLabels(host.Labels))
+		}
+		return tw.Flush()
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(hosts)
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: []string{OutputTable, OutputJSON}}
+	}
+}
+
+// formatLabels renders labels as a sorted, comma separated list of key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,106 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+func TestListHosts(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	hosts, err := ListHosts(settings, config.BootstrapPhase, "", "", "")
+	require.NoError(t, err)
+
+	var names []string
+	for _, host := range hosts {
+		names = append(names, host.Name)
+	}
+	assert.Equal(t, []string{"master-0", "master-1", "master-2", "no-creds"}, names)
+
+	// Listing hosts must not require BMC credentials
+	assert.Equal(t, HostInfo{
+		Name:       "master-0",
+		BMCAddress: "redfish+http://nolocalhost:8888/redfish/v1/Systems/ephemeral",
+		Role:       HostRoleEphemeral,
+		Labels:     map[string]string{EphemeralHostLabel: "true"},
+	}, hosts[0])
+}
+
+func TestListHostsSelectors(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	hosts, err := ListHosts(settings, config.BootstrapPhase, "", "airshipit.org/test-node=true", "")
+	require.NoError(t, err)
+	assert.Len(t, hosts, 2)
+
+	hosts, err = ListHosts(settings, config.BootstrapPhase, "master-2", "airshipit.org/test-node=true", "")
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "master-2", hosts[0].Name)
+
+	hosts, err = ListHosts(settings, config.BootstrapPhase, "", document.EphemeralHostSelector+",bad-label=true", "")
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	hosts, err = ListHosts(settings, config.BootstrapPhase, "", "", "metal3")
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	hosts, err = ListHosts(initSettings(t, withTestDataPath("ipmi")), config.BootstrapPhase, "", "", "metal3")
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "master-0", hosts[0].Name)
+
+	_, err = ListHosts(settings, "bad-phase", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestPrintHosts(t *testing.T) {
+	hosts := []HostInfo{
+		{
+			Name:       "node01",
+			BMCAddress: "redfish+https://10.0.0.1/redfish/v1/Systems/1",
+			Role:       "worker",
+			Labels:     map[string]string{HostRoleLabel: "worker", "rack": "r1"},
+		},
+		{
+			Name:       "node02",
+			BMCAddress: "ipmi://10.0.0.2",
+			Labels:     map[string]string{"rack": "r2"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintHosts(buf, hosts, OutputTable))
+	assert.Equal(t, `NAME     BMC ADDRESS                                     ROLE     LABELS
+node01   redfish+https://10.0.0.1/redfish/v1/Systems/1   worker   airshipit.org/k8s-role=worker,rack=r1
+node02   ipmi://10.0.0.2                                          rack=r2
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintHosts(buf, hosts[1:], OutputJSON))
+	assert.JSONEq(t, `[{"name": "node02", "bmcAddress": "ipmi://10.0.0.2", "labels": {"rack": "r2"}}]`, buf.String())
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "yaml", Formats: []string{OutputTable, OutputJSON}},
+		PrintHosts(buf, hosts, "yaml"))
+}
@@ -0,0 +1,404 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package ipmi implements out-of-band management of hosts whose BMC lacks a usable Redfish service by invoking
+// ipmitool.
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+)
+
+const (
+	// ClientType is used by other packages as the identifier of the IPMI client.
+	ClientType string = "ipmi"
+
+	// addressScheme is the scheme of IPMI BMC addresses, e.g. ipmi://192.168.1.10:623
+	addressScheme = "ipmi"
+	defaultPort   = "623"
+
+	ipmiTool = "ipmitool"
+	// passwordEnv is read by ipmitool when it is given -E, keeping the password out of the process list
+	passwordEnv = "IPMI_PASSWORD"
+)
+
+// ipmiBootDevices maps boot sources to ipmitool boot devices
+var ipmiBootDevices = map[boot.Source]string{
+	boot.SourceCD:   "cdrom",
+	boot.SourceDisk: "disk",
+	boot.SourcePXE:  "pxe",
+}
+
+// Client holds details about an IPMI out-of-band system required for out-of-band management.
+type Client struct {
+	host                string
+	port                string
+	username            string
+	password            string
+	systemActionRetries int
+	systemRebootDelay   int
+
+	// RunCommand executes ipmitool with the given arguments and returns its combined output. It is meant to be
+	// mocked out for tests.
+	RunCommand func(ctx context.Context, env []string, args ...string) ([]byte, error)
+
+	// StreamCommand executes ipmitool with the given arguments, writes its output to w until it exits or ctx is done
+	// and returns its error output. It is meant to be mocked out for tests.
+	StreamCommand func(ctx context.Context, env []string, w io.Writer, args ...string) ([]byte, error)
+
+	// Sleep is meant to be mocked out for tests
+	Sleep func(d time.Duration)
+}
+
+// NodeID retrieves the address of the BMC of the host.
+func (c *Client) NodeID() string {
+	return net.JoinHostPort(c.host, c.port)
+}
+
+// BIOSAttributes is not supported by IPMI.
+func (c *Client) BIOSAttributes(ctx context.Context) (map[string]interface{}, error) {
+	return nil, ErrOperationNotSupported{Operation: "retrieve BIOS attributes"}
+}
+
+// ConsoleLog writes the serial console output of the host, received over Serial-over-LAN, to w until ctx is done.
+// IPMI BMCs don't buffer the console, only the output produced while attached is captured.
+func (c *Client) ConsoleLog(ctx context.Context, w io.Writer) error {
+	env, args := c.connection()
+	args = append(args, "sol", "activate")
+
+	out, err := c.StreamCommand(ctx, env, w, args...)
+	if err != nil {
+		return ErrIPMITool{Command: "sol activate", Output: strings.TrimSpace(string(out)), Err: err}
+	}
+
+	return nil
+}
+
+// ConsoleURL is not supported by IPMI, which has no graphical console.
+func (c *Client) ConsoleURL(ctx context.Context) (string, error) {
+	return "", ErrOperationNotSupported{Operation: "retrieve the graphical console URL"}
+}
+
+// CreateRAIDVolume is not supported by IPMI.
+func (c *Client) CreateRAIDVolume(ctx context.Context, controller string, volume raid.Volume) error {
+	return ErrOperationNotSupported{Operation: "create RAID volumes"}
+}
+
+// DeleteRAIDVolume is not supported by IPMI.
+func (c *Client) DeleteRAIDVolume(ctx context.Context, controller string, volumeID string) error {
+	return ErrOperationNotSupported{Operation: "delete RAID volumes"}
+}
+
+// EjectVirtualMedia is not supported by IPMI.
+func (c *Client) EjectVirtualMedia(ctx context.Context) error {
+	return ErrOperationNotSupported{Operation: "eject virtual media"}
+}
+
+// Events is not supported by IPMI.
+func (c *Client) Events(ctx context.Context) ([]events.Event, error) {
+	return nil, ErrOperationNotSupported{Operation: "retrieve BMC events"}
+}
+
+// HardwareInventory is not supported by IPMI.
+func (c *Client) HardwareInventory(ctx context.Context) (hardware.Inventory, error) {
+	return hardware.Inventory{}, ErrOperationNotSupported{Operation: "retrieve the hardware inventory"}
+}
+
+// Ping is not supported by IPMI, which has no request answered without credentials.
+func (c *Client) Ping(ctx context.Context) error {
+	return ErrOperationNotSupported{Operation: "ping the BMC without credentials"}
+}
+
+// RAIDVolumes is not supported by IPMI.
+func (c *Client) RAIDVolumes(ctx context.Context, controller string) ([]raid.Volume, error) {
+	return nil, ErrOperationNotSupported{Operation: "retrieve RAID volumes"}
+}
+
+// RebootSystem power cycles a host by sending a shutdown signal followed by a power on signal.
+func (c *Client) RebootSystem(ctx context.Context) error {
+	log.Debugf("Rebooting node '%s': powering off.", c.NodeID())
+	if _, err := c.run(ctx, "chassis", "power", "off"); err != nil {
+		log.Debugf("Failed to reboot node '%s': shutdown failure.", c.NodeID())
+		return err
+	}
+
+	if err := c.waitForPowerState(ctx, power.StatusOff); err != nil {
+		return err
+	}
+
+	log.Debugf("Rebooting node '%s': powering on.", c.NodeID())
+	if _, err := c.run(ctx, "chassis", "power", "on"); err != nil {
+		log.Debugf("Failed to reboot node '%s': startup failure.", c.NodeID())
+		return err
+	}
+
+	return c.waitForPowerState(ctx, power.StatusOn)
+}
+
+// SecureBoot is not supported by IPMI.
+func (c *Client) SecureBoot(ctx context.Context) (boot.SecureBoot, error) {
+	return boot.SecureBoot{}, ErrOperationNotSupported{Operation: "retrieve the Secure Boot state"}
+}
+
+// SetBIOSAttributes is not supported by IPMI.
+func (c *Client) SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) error {
+	return ErrOperationNotSupported{Operation: "set BIOS attributes"}
+}
+
+// SetBootSource makes the host boot from source. Unless persistent is true, the source is only used for the next
+// boot, after which the host returns to its default boot order.
+func (c *Client) SetBootSource(ctx context.Context, source boot.Source, persistent bool) error {
+	device, ok := ipmiBootDevices[source]
+	if !ok {
+		return boot.ErrUnknownSource{Source: string(source)}
+	}
+
+	log.Debugf("Setting boot device of node '%s' to '%s', persistent: %t.", c.NodeID(), device, persistent)
+	args := []string{"chassis", "bootdev", device}
+	if persistent {
+		args = append(args, "options=persistent")
+	}
+
+	if _, err := c.run(ctx, args...); err != nil {
+		return err
+	}
+
+	log.Debug("Successfully set boot device.")
+	return nil
+}
+
+// SetBootSourceByType is not supported by IPMI, since it relies on virtual media.
+func (c *Client) SetBootSourceByType(ctx context.Context) error {
+	return ErrOperationNotSupported{Operation: "boot from virtual media"}
+}
+
+// SetSecureBoot is not supported by IPMI.
+func (c *Client) SetSecureBoot(ctx context.Context, enabled bool) error {
+	return ErrOperationNotSupported{Operation: "change the Secure Boot state"}
+}
+
+// SetVirtualMedia is not supported by IPMI.
+func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
+	return ErrOperationNotSupported{Operation: "insert virtual media"}
+}
+
+// SystemPowerOff shuts down a host.
+func (c *Client) SystemPowerOff(ctx context.Context) error {
+	if _, err := c.run(ctx, "chassis", "power", "off"); err != nil {
+		return err
+	}
+
+	return c.waitForPowerState(ctx, power.StatusOff)
+}
+
+// SystemPowerOn powers on a host.
+func (c *Client) SystemPowerOn(ctx context.Context) error {
+	if _, err := c.run(ctx, "chassis", "power", "on"); err != nil {
+		return err
+	}
+
+	return c.waitForPowerState(ctx, power.StatusOn)
+}
+
+// SystemPowerStatus retrieves the power status of a host as a human-readable string.
+func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
+	out, err := c.run(ctx, "chassis", "power", "status")
+	if err != nil {
+		return power.StatusUnknown, err
+	}
+
+	// ipmitool prints e.g. "Chassis Power is on"
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return power.StatusUnknown, nil
+	}
+
+	switch strings.ToLower(fields[len(fields)-1]) {
+	case "on":
+		return power.StatusOn, nil
+	case "off":
+		return power.StatusOff, nil
+	default:
+		return power.StatusUnknown, nil
+	}
+}
+
+// VerifyCredentials checks that the BMC accepts the credentials of the client by retrieving its device information.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	_, err := c.run(ctx, "mc", "info")
+	return err
+}
+
+// VerifyVirtualMedia always fails, IPMI has no virtual media.
+func (c *Client) VerifyVirtualMedia(ctx context.Context) error {
+	return ErrOperationNotSupported{Operation: "insert virtual media"}
+}
+
+// waitForPowerState polls the power status of a host until it reaches desiredState or the retries are exhausted.
+func (c *Client) waitForPowerState(ctx context.Context, desiredState power.Status) error {
+	log.Debugf("Waiting for node '%s' to reach power state '%s'.", c.NodeID(), desiredState)
+
+	for retry := 0; retry <= c.systemActionRetries; retry++ {
+		status, err := c.SystemPowerStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		if status == desiredState {
+			log.Debugf("Node '%s' reached power state '%s'.", c.NodeID(), desiredState)
+			return nil
+		}
+
+		c.Sleep(time.Duration(c.systemRebootDelay) * time.Second)
+	}
+
+	return ErrOperationRetriesExceeded{
+		What:    fmt.Sprintf("reach desired power state %s", desiredState),
+		Retries: c.systemActionRetries,
+	}
+}
+
+// run invokes an ipmitool command against the BMC of the host.
+func (c *Client) run(ctx context.Context, command ...string) ([]byte, error) {
+	env, args := c.connection()
+	args = append(args, command...)
+
+	out, err := c.RunCommand(ctx, env, args...)
+	if err != nil {
+		return nil, ErrIPMITool{Command: strings.Join(command, " "), Output: strings.TrimSpace(string(out)), Err: err}
+	}
+
+	return out, nil
+}
+
+// connection returns the environment and arguments that make ipmitool connect to the BMC of the host.
+func (c *Client) connection() ([]string, []string) {
+	args := []string{"-I", "lanplus", "-H", c.host, "-p", c.port}
+	env := []string{}
+	if c.username != "" {
+		args = append(args, "-U", c.username)
+	}
+	if c.password != "" {
+		args = append(args, "-E")
+		env = append(env, passwordEnv+"="+c.password)
+	}
+	return env, args
+}
+
+// runIPMITool executes ipmitool with the given additional environment.
+func runIPMITool(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, ipmiTool, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.Bytes(), err
+}
+
+// streamIPMITool executes ipmitool with the given additional environment, writing its output to w until it exits or
+// ctx is done. Its stdin is kept open, since interactive commands such as "sol activate" exit at the end of their
+// input. Being stopped once ctx is done is how such commands end, it is not an error.
+func streamIPMITool(ctx context.Context, env []string, w io.Writer, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, ipmiTool, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = w
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stdin.Close()
+
+	if err = cmd.Run(); ctx.Err() != nil {
+		return stderr.Bytes(), nil
+	}
+	return stderr.Bytes(), err
+}
+
+// parseAddress returns the host and port of an IPMI BMC address. Both ipmi://host[:port] and bare host[:port]
+// addresses are accepted.
+func parseAddress(address string) (string, string, error) {
+	rawURL := address
+	if !strings.Contains(rawURL, "://") {
+		rawURL = addressScheme + "://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Scheme != addressScheme || u.Hostname() == "" {
+		return "", "", ErrInvalidAddress{Address: address}
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return u.Hostname(), port, nil
+}
+
+// NewClient returns a client with the capability to manage a host through ipmitool.
+func NewClient(address string,
+	username string,
+	password string,
+	systemActionRetries int,
+	systemRebootDelay int) (context.Context, *Client, error) {
+	ctx := context.Background()
+
+	host, port, err := parseAddress(address)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	c := &Client{
+		host:                host,
+		port:                port,
+		username:            username,
+		password:            password,
+		systemActionRetries: systemActionRetries,
+		systemRebootDelay:   systemRebootDelay,
+
+		RunCommand:    runIPMITool,
+		StreamCommand: streamIPMITool,
+		Sleep: func(d time.Duration) {
+			time.Sleep(d)
+		},
+	}
+
+	return ctx, c, nil
+}
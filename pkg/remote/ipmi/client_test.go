@@ -0,0 +1,313 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+)
+
+const (
+	bmcAddress          = "ipmi://192.168.111.1:6230"
+	systemActionRetries = 1
+	systemRebootDelay   = 0
+)
+
+// fakeIPMITool records ipmitool invocations and answers power status queries from a list of replies.
+type fakeIPMITool struct {
+	commands    []string
+	env         []string
+	powerStates []string
+	err         error
+}
+
+func (f *fakeIPMITool) run(_ context.Context, env []string, args ...string) ([]byte, error) {
+	f.env = env
+	// Skip the connection arguments, only the command is of interest
+	command := strings.Join(args, " ")
+	if i := strings.Index(command, "chassis"); i >= 0 {
+		command = command[i:]
+	}
+	f.commands = append(f.commands, command)
+
+	if f.err != nil {
+		return []byte("Error: Unable to establish IPMI v2 / RMCP+ session"), f.err
+	}
+
+	if command == "chassis power status" && len(f.powerStates) > 0 {
+		state := f.powerStates[0]
+		f.powerStates = f.powerStates[1:]
+		return []byte("Chassis Power is " + state + "\n"), nil
+	}
+
+	return nil, nil
+}
+
+func newTestClient(t *testing.T, fake *fakeIPMITool) (context.Context, *Client) {
+	t.Helper()
+
+	ctx, client, err := NewClient(bmcAddress, "admin", "password", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+
+	client.RunCommand = fake.run
+	client.Sleep = func(_ time.Duration) {}
+
+	return ctx, client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		address string
+		nodeID  string
+		err     error
+	}{
+		{
+			address: "ipmi://192.168.111.1:6230",
+			nodeID:  "192.168.111.1:6230",
+		},
+		{
+			address: "ipmi://bmc.example.com",
+			nodeID:  "bmc.example.com:623",
+		},
+		{
+			address: "192.168.111.1",
+			nodeID:  "192.168.111.1:623",
+		},
+		{
+			address: "ipmi://[fd00::1]:6230",
+			nodeID:  "[fd00::1]:6230",
+		},
+		{
+			address: "redfish+https://192.168.111.1/redfish/v1/Systems/1",
+			err:     ErrInvalidAddress{Address: "redfish+https://192.168.111.1/redfish/v1/Systems/1"},
+		},
+		{
+			address: "",
+			err:     ErrInvalidAddress{Address: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.address, func(t *testing.T) {
+			_, client, err := NewClient(tt.address, "", "", systemActionRetries, systemRebootDelay)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.nodeID, client.NodeID())
+		})
+	}
+}
+
+func TestRunPassesPasswordThroughEnvironment(t *testing.T) {
+	var gotArgs []string
+	fake := &fakeIPMITool{}
+	ctx, client := newTestClient(t, fake)
+	client.RunCommand = func(ctx context.Context, env []string, args ...string) ([]byte, error) {
+		gotArgs = args
+		return fake.run(ctx, env, args...)
+	}
+
+	_, err := client.run(ctx, "chassis", "power", "status")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"-I", "lanplus", "-H", "192.168.111.1", "-p", "6230", "-U", "admin", "-E", "chassis", "power", "status",
+	}, gotArgs)
+	assert.Equal(t, []string{"IPMI_PASSWORD=password"}, fake.env)
+}
+
+func TestSystemPowerStatus(t *testing.T) {
+	tests := []struct {
+		output string
+		status power.Status
+	}{
+		{output: "on", status: power.StatusOn},
+		{output: "off", status: power.StatusOff},
+		{output: "unknown", status: power.StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		fake := &fakeIPMITool{powerStates: []string{tt.output}}
+		ctx, client := newTestClient(t, fake)
+
+		status, err := client.SystemPowerStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, status)
+	}
+}
+
+func TestSystemPowerStatusError(t *testing.T) {
+	fake := &fakeIPMITool{err: errors.New("exit status 1")}
+	ctx, client := newTestClient(t, fake)
+
+	status, err := client.SystemPowerStatus(ctx)
+	assert.Equal(t, power.StatusUnknown, status)
+	assert.Equal(t, ErrIPMITool{
+		Command: "chassis power status",
+		Output:  "Error: Unable to establish IPMI v2 / RMCP+ session",
+		Err:     fake.err,
+	}, err)
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	fake := &fakeIPMITool{}
+	ctx, client := newTestClient(t, fake)
+
+	require.NoError(t, client.VerifyCredentials(ctx))
+	require.Len(t, fake.commands, 1)
+	assert.True(t, strings.HasSuffix(fake.commands[0], " mc info"))
+
+	fake.err = errors.New("exit status 1")
+	assert.Equal(t, ErrIPMITool{
+		Command: "mc info",
+		Output:  "Error: Unable to establish IPMI v2 / RMCP+ session",
+		Err:     fake.err,
+	}, client.VerifyCredentials(ctx))
+}
+
+func TestSystemPowerOn(t *testing.T) {
+	fake := &fakeIPMITool{powerStates: []string{"off", "on"}}
+	ctx, client := newTestClient(t, fake)
+
+	require.NoError(t, client.SystemPowerOn(ctx))
+	assert.Equal(t, []string{"chassis power on", "chassis power status", "chassis power status"}, fake.commands)
+}
+
+func TestSystemPowerOff(t *testing.T) {
+	fake := &fakeIPMITool{powerStates: []string{"off"}}
+	ctx, client := newTestClient(t, fake)
+
+	require.NoError(t, client.SystemPowerOff(ctx))
+	assert.Equal(t, []string{"chassis power off", "chassis power status"}, fake.commands)
+}
+
+func TestSystemPowerOffRetriesExceeded(t *testing.T) {
+	fake := &fakeIPMITool{powerStates: []string{"on", "on"}}
+	ctx, client := newTestClient(t, fake)
+
+	err := client.SystemPowerOff(ctx)
+	_, ok := err.(ErrOperationRetriesExceeded)
+	assert.True(t, ok)
+}
+
+func TestRebootSystem(t *testing.T) {
+	fake := &fakeIPMITool{powerStates: []string{"off", "on"}}
+	ctx, client := newTestClient(t, fake)
+
+	require.NoError(t, client.RebootSystem(ctx))
+	assert.Equal(t, []string{
+		"chassis power off",
+		"chassis power status",
+		"chassis power on",
+		"chassis power status",
+	}, fake.commands)
+}
+
+func TestSetBootSource(t *testing.T) {
+	tests := []struct {
+		source     boot.Source
+		persistent bool
+		command    string
+	}{
+		{
+			source:  boot.SourceCD,
+			command: "chassis bootdev cdrom",
+		},
+		{
+			source:     boot.SourceDisk,
+			persistent: true,
+			command:    "chassis bootdev disk options=persistent",
+		},
+		{
+			source:  boot.SourcePXE,
+			command: "chassis bootdev pxe",
+		},
+	}
+
+	for _, tt := range tests {
+		fake := &fakeIPMITool{}
+		ctx, client := newTestClient(t, fake)
+
+		require.NoError(t, client.SetBootSource(ctx, tt.source, tt.persistent))
+		assert.Equal(t, []string{tt.command}, fake.commands)
+	}
+
+	_, client := newTestClient(t, &fakeIPMITool{})
+	err := client.SetBootSource(context.Background(), boot.Source("floppy"), false)
+	assert.Equal(t, boot.ErrUnknownSource{Source: "floppy"}, err)
+}
+
+func TestOperationsNotSupported(t *testing.T) {
+	fake := &fakeIPMITool{}
+	ctx, client := newTestClient(t, fake)
+
+	assert.IsType(t, ErrOperationNotSupported{}, client.EjectVirtualMedia(ctx))
+	assert.IsType(t, ErrOperationNotSupported{}, client.SetVirtualMedia(ctx, "http://localhost/debian.iso"))
+	assert.IsType(t, ErrOperationNotSupported{}, client.SetBootSourceByType(ctx))
+	_, err := client.HardwareInventory(ctx)
+	assert.IsType(t, ErrOperationNotSupported{}, err)
+	_, err = client.ConsoleURL(ctx)
+	assert.IsType(t, ErrOperationNotSupported{}, err)
+	_, err = client.Events(ctx)
+	assert.IsType(t, ErrOperationNotSupported{}, err)
+	_, err = client.SecureBoot(ctx)
+	assert.IsType(t, ErrOperationNotSupported{}, err)
+	assert.IsType(t, ErrOperationNotSupported{}, client.SetSecureBoot(ctx, true))
+	assert.IsType(t, ErrOperationNotSupported{}, client.Ping(ctx))
+	assert.IsType(t, ErrOperationNotSupported{}, client.VerifyVirtualMedia(ctx))
+	_, err = client.BIOSAttributes(ctx)
+	assert.IsType(t, ErrOperationNotSupported{}, err)
+	assert.IsType(t, ErrOperationNotSupported{}, client.SetBIOSAttributes(ctx, map[string]interface{}{}))
+	assert.Empty(t, fake.commands)
+}
+
+func TestConsoleLog(t *testing.T) {
+	_, client := newTestClient(t, &fakeIPMITool{})
+
+	var streamed []string
+	client.StreamCommand = func(_ context.Context, env []string, w io.Writer, args ...string) ([]byte, error) {
+		streamed = args
+		_, err := io.WriteString(w, "Booting from CD\n")
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, client.ConsoleLog(context.Background(), buf))
+	assert.Equal(t, "Booting from CD\n", buf.String())
+	assert.Equal(t, []string{"sol", "activate"}, streamed[len(streamed)-2:])
+
+	client.StreamCommand = func(context.Context, []string, io.Writer, ...string) ([]byte, error) {
+		return []byte("Info: SOL payload already active on another session\n"), errors.New("exit status 1")
+	}
+	assert.Equal(t, ErrIPMITool{
+		Command: "sol activate",
+		Output:  "Info: SOL payload already active on another session",
+		Err:     errors.New("exit status 1"),
+	}, client.ConsoleLog(context.Background(), buf))
+}
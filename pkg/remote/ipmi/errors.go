@@ -0,0 +1,64 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+
+	aerror "opendev.org/airship/airshipctl/pkg/errors"
+)
+
+// ErrIPMITool describes a failed ipmitool invocation.
+type ErrIPMITool struct {
+	aerror.AirshipError
+	Command string
+	Output  string
+	Err     error
+}
+
+func (e ErrIPMITool) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("ipmitool %s failed: %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("ipmitool %s failed: %v: %s", e.Command, e.Err, e.Output)
+}
+
+// ErrInvalidAddress describes a BMC address that can't be used with IPMI.
+type ErrInvalidAddress struct {
+	Address string
+}
+
+func (e ErrInvalidAddress) Error() string {
+	return fmt.Sprintf("invalid IPMI address %q, expected ipmi://host[:port]", e.Address)
+}
+
+// ErrOperationNotSupported is returned for operations, such as virtual media handling, that IPMI doesn't provide.
+type ErrOperationNotSupported struct {
+	Operation string
+}
+
+func (e ErrOperationNotSupported) Error() string {
+	return fmt.Sprintf("unable to %s: operation is not supported by IPMI", e.Operation)
+}
+
+// ErrOperationRetriesExceeded raised if number of operation retries exceeded
+type ErrOperationRetriesExceeded struct {
+	What    string
+	Retries int
+}
+
+func (e ErrOperationRetriesExceeded) Error() string {
+	return fmt.Sprintf("Unable to %s. Maximum retries (%d) exceeded.", e.What, e.Retries)
+}
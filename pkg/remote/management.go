@@ -17,26 +17,60 @@ package remote
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+	"opendev.org/airship/airshipctl/pkg/util"
 )
 
+// redfishSessionCacheFile is the file of the airshipctl config directory that keeps Redfish sessions between commands.
+const redfishSessionCacheFile = "redfish-sessions.json"
+
 // Client is a set of functions that clients created for out-of-band power management and control should implement. The
 // functions within client are used by power management commands and remote direct functionality.
 type Client interface {
+	BIOSAttributes(context.Context) (map[string]interface{}, error)
+	ConsoleLog(ctx context.Context, w io.Writer) error
+	ConsoleURL(context.Context) (string, error)
+	CreateRAIDVolume(ctx context.Context, controller string, volume raid.Volume) error
+	DeleteRAIDVolume(ctx context.Context, controller string, volumeID string) error
 	EjectVirtualMedia(context.Context) error
+	Events(context.Context) ([]events.Event, error)
+	HardwareInventory(context.Context) (hardware.Inventory, error)
 	NodeID() string
+	Ping(context.Context) error
+	RAIDVolumes(ctx context.Context, controller string) ([]raid.Volume, error)
 	RebootSystem(context.Context) error
+	SecureBoot(context.Context) (boot.SecureBoot, error)
+	SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) error
+	SetBootSource(ctx context.Context, source boot.Source, persistent bool) error
 	SetBootSourceByType(context.Context) error
+	SetSecureBoot(ctx context.Context, enabled bool) error
 	SystemPowerOff(context.Context) error
 	SystemPowerOn(context.Context) error
 	SystemPowerStatus(context.Context) (power.Status, error)
+	VerifyCredentials(context.Context) error
+	VerifyVirtualMedia(context.Context) error
 
 	// TODO(drewwalters96): This function is tightly coupled to Redfish. It should be combined with the
 	// SetBootSource operation and removed from the client interface.
@@ -49,6 +83,8 @@ type Client interface {
 type Manager struct {
 	Config config.ManagementConfiguration
 	Hosts  []baremetalHost
+
+	credentials *credentialResolver
 }
 
 // baremetalHost is an airshipctl representation of a baremetal host, defined by a baremetal host document, that embeds
@@ -67,9 +103,23 @@ type HostSelector func(*Manager, config.ManagementConfiguration, document.Bundle
 
 // ByLabel adds all hosts to a manager whose documents match a supplied label selector.
 func ByLabel(label string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByLabel(label))
+}
+
+// ByNamespace adds all hosts to a manager whose documents are in the supplied namespace.
+func ByNamespace(namespace string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByNamespace(namespace))
+}
+
+// All adds all hosts to a manager.
+func All() HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind))
+}
+
+// bySelector adds all hosts to a manager whose documents match selector.
+func bySelector(selector document.Selector) HostSelector {
 	return func(a *Manager, mgmtCfg config.ManagementConfiguration, docBundle document.Bundle) error {
-		selector := document.NewSelector().ByKind(document.BareMetalHostKind).ByLabel(label)
-		docs, err := docBundle.Select(selector)
+		docs, err := selectHostDocuments(docBundle, selector)
 		if err != nil {
 			return err
 		}
@@ -80,7 +130,7 @@ func ByLabel(label string) HostSelector {
 
 		var matchingHosts []baremetalHost
 		for _, doc := range docs {
-			host, err := newBaremetalHost(mgmtCfg, doc, docBundle)
+			host, err := newBaremetalHost(mgmtCfg, doc, docBundle, a.credentials)
 			if err != nil {
 				return err
 			}
@@ -103,7 +153,7 @@ func ByName(name string) HostSelector {
 			return err
 		}
 
-		host, err := newBaremetalHost(mgmtCfg, doc, docBundle)
+		host, err := newBaremetalHost(mgmtCfg, doc, docBundle, a.credentials)
 		if err != nil {
 			return err
 		}
@@ -114,9 +164,41 @@ func ByName(name string) HostSelector {
 	}
 }
 
+// selectHostDocuments returns the documents of docBundle that match selector. Documents without a namespace match
+// every namespace of a document selector, they are considered to be in the default namespace here instead.
+func selectHostDocuments(docBundle document.Bundle, selector document.Selector) ([]document.Document, error) {
+	docs, err := docBundle.Select(selector)
+	if err != nil || selector.Namespace == "" {
+		return docs, err
+	}
+
+	var namespaced []document.Document
+	for _, doc := range docs {
+		namespace := doc.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+
+		if namespace == selector.Namespace {
+			namespaced = append(namespaced, doc)
+		}
+	}
+
+	return namespaced, nil
+}
+
 // NewManager provides a manager that exposes the capability to perform remote direct functionality and other
-// out-of-band management on multiple hosts.
+// out-of-band management on multiple hosts. BMC credentials Secrets that aren't part of the documents are read from
+// the cluster of the current context.
 func NewManager(settings *environment.AirshipCTLSettings, phase string, hosts ...HostSelector) (*Manager, error) {
+	return newManager(settings, phase, client.DefaultClient, hosts...)
+}
+
+// newManager provides a manager that uses clients created by factory to read BMC credentials from the cluster.
+func newManager(settings *environment.AirshipCTLSettings,
+	phase string,
+	factory client.Factory,
+	hosts ...HostSelector) (*Manager, error) {
 	managementCfg, err := settings.Config.CurrentContextManagementConfig()
 	if err != nil {
 		return nil, err
@@ -126,19 +208,15 @@ func NewManager(settings *environment.AirshipCTLSettings, phase string, hosts ..
 		return nil, err
 	}
 
-	entrypoint, err := settings.Config.CurrentContextEntryPoint(phase)
-	if err != nil {
-		return nil, err
-	}
-
-	docBundle, err := document.NewBundleByPath(entrypoint)
+	docBundle, err := currentContextBundle(settings, phase)
 	if err != nil {
 		return nil, err
 	}
 
 	manager := &Manager{
-		Config: *managementCfg,
-		Hosts:  []baremetalHost{},
+		Config:      *managementCfg,
+		Hosts:       []baremetalHost{},
+		credentials: newCredentialResolver(settings, factory),
 	}
 
 	// Each function in hosts modifies the list of hosts for the new manager based on selection criteria provided
@@ -156,11 +234,201 @@ func NewManager(settings *environment.AirshipCTLSettings, phase string, hosts ..
 	return manager, nil
 }
 
+// HostAction is an action performed on a single host by RunAction.
+type HostAction func(ctx context.Context, client Client) error
+
+// HostResult is the outcome of an action performed on a host.
+type HostResult struct {
+	HostName string
+	Err      error
+	Duration time.Duration
+}
+
+// hostResultReport is a HostResult as written in OutputJSON and OutputYAML formats.
+type hostResultReport struct {
+	Host            string  `json:"host"`
+	Action          string  `json:"action"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// RunAction performs action on all hosts of the manager, on at most maxWorkers hosts at a time, or on all hosts at
+// once if maxWorkers is not positive. A failure on one host doesn't stop the action on the others; the results are
+// returned in the order of the hosts.
+func (m *Manager) RunAction(maxWorkers int, action HostAction) []HostResult {
+	results := make([]HostResult, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		start := time.Now()
+		err := action(host.Context, host.Client)
+		results[i] = HostResult{HostName: host.HostName, Err: err, Duration: time.Since(start)}
+	})
+	return results
+}
+
+// PrintResults writes the outcomes of the action described by action to w in the given format, see OutputTable,
+// OutputJSON and OutputYAML.
+func PrintResults(w io.Writer, action string, results []HostResult, format string) error {
+	reports := make([]hostResultReport, len(results))
+	for i, result := range results {
+		reports[i] = hostResultReport{
+			Host:            result.HostName,
+			Action:          action,
+			Success:         result.Err == nil,
+			DurationSeconds: float64(result.Duration.Round(time.Millisecond).Milliseconds()) / 1000,
+		}
+		if result.Err != nil {
+			reports[i].Error = result.Err.Error()
+		}
+	}
+
+	if format != OutputTable {
+		return writeReports(w, reports, format)
+	}
+
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "HOST\tACTION\tRESULT\tDURATION\tDETAILS")
+	for i, report := range reports {
+		outcome := "succeeded"
+		if !report.Success {
+			outcome = "failed"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", report.Host, report.Action, outcome,
+			results[i].Duration.Round(time.Millisecond), report.Error)
+	}
+	return tw.Flush()
+}
+
+// HostPowerStatus is the power status of a host, or the error that prevented retrieving it.
+type HostPowerStatus struct {
+	HostName string
+	Status   power.Status
+	Err      error
+}
+
+// PowerStatus retrieves the power status of all hosts of the manager, querying at most maxWorkers BMCs at a time, see
+// RunAction. The statuses are returned in the order of the hosts.
+func (m *Manager) PowerStatus(maxWorkers int) []HostPowerStatus {
+	statuses := make([]HostPowerStatus, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		status, err := host.SystemPowerStatus(host.Context)
+		statuses[i] = HostPowerStatus{HostName: host.HostName, Status: status, Err: err}
+	})
+	return statuses
+}
+
+// hostPowerStatusReport is a HostPowerStatus as written in OutputJSON and OutputYAML formats.
+type hostPowerStatusReport struct {
+	Host        string `json:"host"`
+	PowerStatus string `json:"powerStatus,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// PrintPowerStatus writes the power statuses of hosts to w in the given format, see OutputTable, OutputJSON and
+// OutputYAML.
+func PrintPowerStatus(w io.Writer, statuses []HostPowerStatus, format string) error {
+	reports := make([]hostPowerStatusReport, len(statuses))
+	for i, status := range statuses {
+		reports[i] = hostPowerStatusReport{Host: status.HostName}
+		if status.Err != nil {
+			reports[i].Error = status.Err.Error()
+			continue
+		}
+		reports[i].PowerStatus = status.Status.String()
+	}
+
+	if format != OutputTable {
+		return writeReports(w, reports, format)
+	}
+
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "HOST\tPOWER STATUS\tDETAILS")
+	for _, report := range reports {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", report.Host, report.PowerStatus, report.Error)
+	}
+	return tw.Flush()
+}
+
+// writeReports writes reports to w in OutputJSON or OutputYAML format.
+func writeReports(w io.Writer, reports interface{}, format string) error {
+	switch format {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(reports)
+	case OutputYAML:
+		data, err := yaml.Marshal(reports)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: []string{OutputTable, OutputJSON, OutputYAML}}
+	}
+}
+
+// forEachHost calls f with the index of every host of the manager and the host, using at most maxWorkers goroutines.
+func (m *Manager) forEachHost(maxWorkers int, f func(i int, host baremetalHost)) {
+	if maxWorkers <= 0 || maxWorkers > len(m.Hosts) {
+		maxWorkers = len(m.Hosts)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i, m.Hosts[i])
+			}
+		}()
+	}
+
+	for i := range m.Hosts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// CurrentContextBMCCredentials returns the BMC username and password of the baremetal host named hostName in the
+// documents of the given phase for the current context. If the documents don't contain the Secret referenced by the
+// host, it is read from the cluster of the current context.
+func CurrentContextBMCCredentials(settings *environment.AirshipCTLSettings,
+	phase string,
+	hostName string) (username string, password string, err error) {
+	docBundle, err := currentContextBundle(settings, phase)
+	if err != nil {
+		return "", "", err
+	}
+
+	selector := document.NewSelector().ByKind(document.BareMetalHostKind).ByName(hostName)
+	doc, err := docBundle.SelectOne(selector)
+	if err != nil {
+		return "", "", err
+	}
+
+	return newCredentialResolver(settings, client.DefaultClient).resolve(doc, docBundle)
+}
+
+// currentContextBundle builds the document bundle of the given phase for the current context.
+func currentContextBundle(settings *environment.AirshipCTLSettings, phase string) (document.Bundle, error) {
+	entrypoint, err := settings.CurrentContextEntryPoint(phase)
+	if err != nil {
+		return nil, err
+	}
+
+	return document.NewBundleByPath(entrypoint)
+}
+
 // newBaremetalHost creates a representation of a baremetal host that is configured to perform management actions by
 // invoking its client methods (provided by the remote.Client interface).
 func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 	hostDoc document.Document,
-	docBundle document.Bundle) (baremetalHost, error) {
+	docBundle document.Bundle,
+	credentials *credentialResolver) (baremetalHost, error) {
 	var host baremetalHost
 
 	address, err := document.GetBMHBMCAddress(hostDoc)
@@ -168,7 +436,7 @@ func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 		return host, err
 	}
 
-	username, password, err := document.GetBMHBMCCredentials(hostDoc, docBundle)
+	username, password, err := credentials.resolve(hostDoc, docBundle)
 	if err != nil {
 		return host, err
 	}
@@ -184,7 +452,8 @@ func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 			username,
 			password,
 			mgmtCfg.SystemActionRetries,
-			mgmtCfg.SystemRebootDelay)
+			mgmtCfg.SystemRebootDelay,
+			redfishClientOptions(mgmtCfg)...)
 
 		if err != nil {
 			return host, err
@@ -200,6 +469,21 @@ func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 			username,
 			password,
 			mgmtCfg.SystemActionRetries,
+			mgmtCfg.SystemRebootDelay,
+			redfishClientOptions(mgmtCfg)...)
+
+		if err != nil {
+			return host, err
+		}
+
+		host = baremetalHost{client, ctx, address, hostDoc.GetName(), username, password}
+	case ipmi.ClientType:
+		log.Debug("Remote type: IPMI")
+		ctx, client, err := ipmi.NewClient(
+			address,
+			username,
+			password,
+			mgmtCfg.SystemActionRetries,
 			mgmtCfg.SystemRebootDelay)
 
 		if err != nil {
@@ -214,6 +498,26 @@ func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 	return host, nil
 }
 
+// redfishClientOptions returns the options of Redfish clients for the TLS, retry and session settings of a
+// management configuration.
+func redfishClientOptions(mgmtCfg config.ManagementConfiguration) []redfish.ClientOption {
+	opts := []redfish.ClientOption{
+		redfish.WithRequestRetries(mgmtCfg.RequestRetries, time.Duration(mgmtCfg.RequestRetryDelay)*time.Second),
+	}
+	if mgmtCfg.CertificateAuthority != "" {
+		opts = append(opts, redfish.WithCertificateAuthority(mgmtCfg.CertificateAuthority))
+	}
+	if mgmtCfg.ClientCertificate != "" {
+		opts = append(opts, redfish.WithClientCertificate(mgmtCfg.ClientCertificate, mgmtCfg.ClientKey))
+	}
+	if mgmtCfg.SessionCacheTTL > 0 {
+		opts = append(opts, redfish.WithSessionCache(
+			filepath.Join(environment.AirshipConfigDir(), redfishSessionCacheFile),
+			time.Duration(mgmtCfg.SessionCacheTTL)*time.Second))
+	}
+	return opts
+}
+
 // reconcileHosts produces the intersection of two baremetal host arrays.
 func reconcileHosts(existingHosts []baremetalHost, newHosts ...baremetalHost) []baremetalHost {
 	if len(existingHosts) == 0 {
@@ -15,18 +15,26 @@
 package remote
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 	"opendev.org/airship/airshipctl/testutil"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
 )
 
 type Configuration func(*environment.AirshipCTLSettings)
@@ -152,6 +160,51 @@ func TestNewManagerRedfishDell(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewManagerIPMI(t *testing.T) {
+	cfg := &config.ManagementConfiguration{Type: ipmi.ClientType}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("ipmi"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, ByLabel(document.EphemeralHostSelector))
+	require.NoError(t, err)
+	require.Len(t, manager.Hosts, 1)
+	assert.Equal(t, "192.168.111.1:6230", manager.Hosts[0].NodeID())
+}
+
+func TestNewManagerIPMIRedfishAddress(t *testing.T) {
+	cfg := &config.ManagementConfiguration{Type: ipmi.ClientType}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("base"))
+
+	_, err := NewManager(settings, config.BootstrapPhase, ByLabel(document.EphemeralHostSelector))
+	assert.IsType(t, ipmi.ErrInvalidAddress{}, err)
+}
+
+func TestNewManagerByNamespace(t *testing.T) {
+	cfg := &config.ManagementConfiguration{Type: ipmi.ClientType}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("ipmi"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, ByNamespace("metal3"))
+	require.NoError(t, err)
+	require.Len(t, manager.Hosts, 1)
+	assert.Equal(t, "master-0", manager.Hosts[0].HostName)
+
+	_, err = NewManager(settings, config.BootstrapPhase, ByNamespace("default"))
+	assert.IsType(t, document.ErrDocNotFound{}, err)
+}
+
+func TestNewManagerAll(t *testing.T) {
+	cfg := &config.ManagementConfiguration{Type: ipmi.ClientType}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("ipmi"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, All())
+	require.NoError(t, err)
+	assert.Len(t, manager.Hosts, 1)
+
+	// All hosts need BMC credentials to be managed
+	settings = initSettings(t, withTestDataPath("base"))
+	_, err = NewManager(settings, config.BootstrapPhase, All())
+	assert.Error(t, err)
+}
+
 func TestNewManagerUnknownRemoteType(t *testing.T) {
 	badCfg := &config.ManagementConfiguration{Type: "bad-remote-type"}
 	settings := initSettings(t, withManagementConfig(badCfg), withTestDataPath("base"))
@@ -180,3 +233,157 @@ func TestNewManagerBadPhase(t *testing.T) {
 	_, err := NewManager(settings, "bad-phase", ByLabel(document.EphemeralHostSelector))
 	assert.Error(t, err)
 }
+
+func TestManagerPowerStatus(t *testing.T) {
+	var hosts []baremetalHost
+	for _, name := range []string{"node01", "node02", "node03"} {
+		ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+		require.NoError(t, err)
+
+		switch name {
+		case "node02":
+			rMock.On("SystemPowerStatus", ctx).Return(power.StatusUnknown, redfish.ErrRedfishClient{})
+		default:
+			rMock.On("SystemPowerStatus", ctx).Return(power.StatusOn, nil)
+		}
+
+		hosts = append(hosts, baremetalHost{rMock, ctx, redfishURL, name, username, password})
+	}
+
+	m := &Manager{Hosts: hosts}
+	assert.Equal(t, []HostPowerStatus{
+		{HostName: "node01", Status: power.StatusOn},
+		{HostName: "node02", Status: power.StatusUnknown, Err: redfish.ErrRedfishClient{}},
+		{HostName: "node03", Status: power.StatusOn},
+	}, m.PowerStatus(2))
+}
+
+func TestManagerRunAction(t *testing.T) {
+	var hosts []baremetalHost
+	for i := 0; i < 10; i++ {
+		hosts = append(hosts, baremetalHost{HostName: fmt.Sprintf("node%02d", i), Context: context.Background()})
+	}
+	m := &Manager{Hosts: hosts}
+
+	for _, maxWorkers := range []int{0, 1, 3, 20} {
+		var running, maxRunning int32
+		results := m.RunAction(maxWorkers, func(ctx context.Context, client Client) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				old := atomic.LoadInt32(&maxRunning)
+				if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+
+		require.Len(t, results, len(hosts))
+		for i, result := range results {
+			assert.Equal(t, hosts[i].HostName, result.HostName)
+			assert.NoError(t, result.Err)
+			assert.GreaterOrEqual(t, int64(result.Duration), int64(10*time.Millisecond))
+		}
+		if maxWorkers > 0 {
+			assert.LessOrEqual(t, maxRunning, int32(maxWorkers))
+		}
+	}
+
+	// A failure on one host doesn't stop the action on the others
+	var calls int32
+	results := m.RunAction(2, func(ctx context.Context, client Client) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return redfish.ErrRedfishClient{}
+		}
+		return nil
+	})
+	assert.Equal(t, int32(len(hosts)), calls)
+
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+}
+
+func TestPrintResults(t *testing.T) {
+	results := []HostResult{
+		{HostName: "node01", Duration: 1234567 * time.Microsecond},
+		{HostName: "node02", Err: redfish.ErrRedfishClient{Message: "Unauthorized."}, Duration: 20 * time.Millisecond},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintResults(buf, "power off", results, OutputTable))
+	assert.Equal(t, `HOST     ACTION      RESULT      DURATION   DETAILS
+node01   power off   succeeded   1.235s     
+node02   power off   failed      20ms       redfish client encountered an error: Unauthorized.
+`, buf.String())
+
+	expected := `[
+		{"host": "node01", "action": "power off", "success": true, "durationSeconds": 1.235},
+		{"host": "node02", "action": "power off", "success": false, "durationSeconds": 0.02,
+			"error": "redfish client encountered an error: Unauthorized."}
+	]`
+	buf.Reset()
+	require.NoError(t, PrintResults(buf, "power off", results, OutputJSON))
+	assert.JSONEq(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintResults(buf, "power off", results, OutputYAML))
+	data, err := yaml.YAMLToJSON(buf.Bytes())
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "csv", Formats: []string{OutputTable, OutputJSON, OutputYAML}},
+		PrintResults(buf, "power off", results, "csv"))
+}
+
+func TestPrintPowerStatus(t *testing.T) {
+	statuses := []HostPowerStatus{
+		{HostName: "node01", Status: power.StatusOn},
+		{HostName: "node02", Err: redfish.ErrRedfishClient{Message: "Unauthorized."}},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintPowerStatus(buf, statuses, OutputTable))
+	assert.Equal(t, `HOST     POWER STATUS   DETAILS
+node01   ON             
+node02                  redfish client encountered an error: Unauthorized.
+`, buf.String())
+
+	expected := `[
+		{"host": "node01", "powerStatus": "ON"},
+		{"host": "node02", "error": "redfish client encountered an error: Unauthorized."}
+	]`
+	buf.Reset()
+	require.NoError(t, PrintPowerStatus(buf, statuses, OutputJSON))
+	assert.JSONEq(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintPowerStatus(buf, statuses, OutputYAML))
+	data, err := yaml.YAMLToJSON(buf.Bytes())
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "csv", Formats: []string{OutputTable, OutputJSON, OutputYAML}},
+		PrintPowerStatus(buf, statuses, "csv"))
+}
+
+func TestCurrentContextBMCCredentials(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	username, password, err := CurrentContextBMCCredentials(settings, config.BootstrapPhase, "master-0")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "password", password)
+
+	_, _, err = CurrentContextBMCCredentials(settings, config.BootstrapPhase, "does-not-exist")
+	assert.Error(t, err)
+
+	_, _, err = CurrentContextBMCCredentials(settings, "bad-phase", "master-0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"net/url"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// AttachISO returns an action that inserts the ISO image served at isoURL into the virtual CD of a host and makes the
+// host boot from it once, on its next boot. The host is not rebooted.
+func AttachISO(isoURL string) (HostAction, error) {
+	u, err := url.Parse(isoURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidISOURL{URL: isoURL}
+	}
+
+	return func(ctx context.Context, client Client) error {
+		log.Debugf("Inserting ISO '%s' into the virtual CD of node '%s'.", isoURL, client.NodeID())
+		if err := client.SetVirtualMedia(ctx, isoURL); err != nil {
+			return err
+		}
+
+		return client.SetBootSourceByType(ctx)
+	}, nil
+}
@@ -0,0 +1,60 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestAttachISO(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+	defer rMock.AssertExpectations(t)
+
+	rMock.On("NodeID").Return(systemID)
+	rMock.On("SetVirtualMedia", ctx, isoURL).Times(1).Return(nil)
+	rMock.On("SetBootSourceByType", ctx).Times(1).Return(nil)
+
+	attachISO, err := AttachISO(isoURL)
+	require.NoError(t, err)
+	assert.NoError(t, attachISO(ctx, rMock))
+}
+
+func TestAttachISOSetVirtualMediaError(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+	defer rMock.AssertExpectations(t)
+
+	expectedErr := redfish.ErrRedfishClient{Message: "unable to insert virtual media"}
+	rMock.On("NodeID").Return(systemID)
+	rMock.On("SetVirtualMedia", ctx, isoURL).Times(1).Return(expectedErr)
+
+	attachISO, err := AttachISO(isoURL)
+	require.NoError(t, err)
+	assert.Equal(t, expectedErr, attachISO(ctx, rMock))
+}
+
+func TestAttachISOInvalidURL(t *testing.T) {
+	for _, u := range []string{"/srv/images/ephemeral.iso", "ftp://localhost/ephemeral.iso", "http://", "%zz"} {
+		_, err := AttachISO(u)
+		assert.Equal(t, ErrInvalidISOURL{URL: u}, err)
+	}
+}
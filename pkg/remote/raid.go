@@ -0,0 +1,231 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// RAIDOptions configures how RAID configs are applied by Manager.ApplyRAIDConfigs.
+type RAIDOptions struct {
+	// Prune deletes the volumes of a controller that the config of its host doesn't declare.
+	Prune bool
+	// DryRun only reports the volumes that would be created and deleted.
+	DryRun bool
+}
+
+// HostRAIDResult is the outcome of applying a RAID config to a host.
+type HostRAIDResult struct {
+	HostName string
+	// Created and Deleted are the names of the volumes created and deleted, in the order of the config and of the
+	// controller respectively.
+	Created []string
+	Deleted []string
+	// Skipped is set for hosts without RAID config.
+	Skipped bool
+	Err     error
+}
+
+// hostRAIDReport is a HostRAIDResult as written in OutputJSON and OutputYAML formats. Result is one of "changed",
+// "up to date", "skipped" and "failed"; volumes listed by a failed host were changed before the failure.
+type hostRAIDReport struct {
+	Host    string   `json:"host"`
+	Result  string   `json:"result"`
+	DryRun  bool     `json:"dryRun,omitempty"`
+	Created []string `json:"created,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// RAIDConfigs returns the RAID configs defined by the RAIDConfig documents of the given phase for the current
+// context, by the name of the host they apply to. A RAIDConfig document holds a raid.Config in its spec, e.g.
+//
+//	apiVersion: airshipit.org/v1alpha1
+//	kind: RAIDConfig
+//	metadata:
+//	  name: node01-raid
+//	spec:
+//	  host: node01
+//	  controller: RAID.Integrated.1-1
+//	  volumes:
+//	    - name: os
+//	      raidType: RAID1
+//	      drives:
+//	        - Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1
+//	        - Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1
+func RAIDConfigs(settings *environment.AirshipCTLSettings, phase string) (map[string]raid.Config, error) {
+	docBundle, err := currentContextBundle(settings, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := docBundle.Select(document.NewSelector().ByGvk(document.BaseAirshipSelector, "v1alpha1",
+		document.RAIDConfigKind))
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]raid.Config, len(docs))
+	for _, doc := range docs {
+		obj := struct {
+			Spec raid.Config `json:"spec"`
+		}{}
+		if err = doc.ToObject(&obj); err != nil {
+			return nil, err
+		}
+
+		config := obj.Spec
+		if _, ok := configs[config.Host]; ok {
+			return nil, ErrDuplicateRAIDConfig{Host: config.Host}
+		}
+		if err = config.Validate(); err != nil {
+			return nil, err
+		}
+		configs[config.Host] = config
+	}
+
+	return configs, nil
+}
+
+// ApplyRAIDConfigs creates the volumes declared by configs, by host name, on the hosts of the manager, on at most
+// maxWorkers hosts at a time, see RunAction. Volumes that already exist with the same RAID type and drives are left
+// alone, so applying a config again changes nothing. A volume existing with other RAID type or drives fails the host
+// before any change is made to it. Hosts without config are skipped. The results are returned in the order of the
+// hosts.
+func (m *Manager) ApplyRAIDConfigs(maxWorkers int, configs map[string]raid.Config, opts RAIDOptions) []HostRAIDResult {
+	results := make([]HostRAIDResult, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		config, ok := configs[host.HostName]
+		if !ok {
+			results[i] = HostRAIDResult{HostName: host.HostName, Skipped: true}
+			return
+		}
+
+		results[i] = applyRAIDConfig(host.Context, host.Client, config, opts)
+		results[i].HostName = host.HostName
+	})
+	return results
+}
+
+// PrintRAIDResults writes the outcomes of applying RAID configs with opts to w in the given format, see OutputTable,
+// OutputJSON and OutputYAML.
+func PrintRAIDResults(w io.Writer, results []HostRAIDResult, opts RAIDOptions, format string) error {
+	reports := make([]hostRAIDReport, len(results))
+	for i, result := range results {
+		report := hostRAIDReport{
+			Host:    result.HostName,
+			DryRun:  opts.DryRun,
+			Created: result.Created,
+			Deleted: result.Deleted,
+		}
+		switch {
+		case result.Err != nil:
+			report.Result, report.Error = "failed", result.Err.Error()
+		case result.Skipped:
+			report.Result = "skipped"
+		case len(result.Created)+len(result.Deleted) == 0:
+			report.Result = "up to date"
+		default:
+			report.Result = "changed"
+		}
+		reports[i] = report
+	}
+
+	if format != OutputTable {
+		return writeReports(w, reports, format)
+	}
+
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "HOST\tRESULT\tCREATED\tDELETED\tDETAILS")
+	for _, report := range reports {
+		result := report.Result
+		if report.DryRun {
+			result += " (dry run)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", report.Host, result, strings.Join(report.Created, ","),
+			strings.Join(report.Deleted, ","), report.Error)
+	}
+	return tw.Flush()
+}
+
+// applyRAIDConfig deletes the volumes pruned from the controller of a host and creates the missing ones. Volumes are
+// deleted first so that their drives can be used by the created ones.
+func applyRAIDConfig(ctx context.Context, client Client, config raid.Config, opts RAIDOptions) HostRAIDResult {
+	result := HostRAIDResult{}
+	current, err := client.RAIDVolumes(ctx, config.Controller)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	existing := make(map[string]raid.Volume, len(current))
+	for _, volume := range current {
+		existing[volume.Name] = volume
+	}
+
+	var create []raid.Volume
+	declared := make(map[string]bool, len(config.Volumes))
+	for _, volume := range config.Volumes {
+		declared[volume.Name] = true
+		found, ok := existing[volume.Name]
+		switch {
+		case !ok:
+			create = append(create, volume)
+		case !found.Matches(volume):
+			result.Err = ErrRAIDVolumeMismatch{Volume: volume.Name, Current: found, Desired: volume}
+			return result
+		}
+	}
+
+	var prune []raid.Volume
+	if opts.Prune {
+		for _, volume := range current {
+			if !declared[volume.Name] {
+				prune = append(prune, volume)
+			}
+		}
+	}
+
+	for _, volume := range prune {
+		if !opts.DryRun {
+			log.Debugf("Deleting RAID volume '%s' of node '%s'.", volume.Name, client.NodeID())
+			if result.Err = client.DeleteRAIDVolume(ctx, config.Controller, volume.ID); result.Err != nil {
+				return result
+			}
+		}
+		result.Deleted = append(result.Deleted, volume.Name)
+	}
+
+	for _, volume := range create {
+		if !opts.DryRun {
+			log.Debugf("Creating RAID volume '%s' on node '%s'.", volume.Name, client.NodeID())
+			if result.Err = client.CreateRAIDVolume(ctx, config.Controller, volume); result.Err != nil {
+				return result
+			}
+		}
+		result.Created = append(result.Created, volume.Name)
+	}
+
+	return result
+}
@@ -0,0 +1,33 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package raid
+
+import (
+	"fmt"
+)
+
+// ErrInvalidVolume is returned for a volume of a RAID config that can't be created.
+type ErrInvalidVolume struct {
+	Host   string
+	Volume string
+	Reason string
+}
+
+func (e ErrInvalidVolume) Error() string {
+	if e.Volume == "" {
+		return fmt.Sprintf("invalid RAID volume of host %s: %s", e.Host, e.Reason)
+	}
+	return fmt.Sprintf("invalid RAID volume %s of host %s: %s", e.Volume, e.Host, e.Reason)
+}
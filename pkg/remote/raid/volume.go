@@ -0,0 +1,83 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package raid describes the RAID volumes of baremetal hosts.
+package raid
+
+import (
+	"sort"
+	"strings"
+)
+
+// Config declares the RAID volumes of a host.
+type Config struct {
+	// Host is the name of the BareMetalHost document of the host
+	Host string `json:"host"`
+	// Controller is the ID of the storage controller the volumes are created on, the first controller of the host
+	// if empty
+	Controller string   `json:"controller,omitempty"`
+	Volumes    []Volume `json:"volumes"`
+}
+
+// Volume is a RAID volume of a storage controller.
+type Volume struct {
+	// ID is assigned by the controller to existing volumes
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	RAIDType string `json:"raidType"`
+	// Drives are the IDs of the drives the volume spans
+	Drives []string `json:"drives"`
+	// CapacityBytes of the volume, the controller uses the capacity of the drives if 0
+	CapacityBytes int64 `json:"capacityBytes,omitempty"`
+}
+
+// Validate checks that the config declares every volume with a unique name, a RAID type and drives.
+func (c Config) Validate() error {
+	names := make(map[string]bool, len(c.Volumes))
+	for _, v := range c.Volumes {
+		switch {
+		case v.Name == "":
+			return ErrInvalidVolume{Host: c.Host, Reason: "name is missing"}
+		case names[v.Name]:
+			return ErrInvalidVolume{Host: c.Host, Volume: v.Name, Reason: "name is not unique"}
+		case v.RAIDType == "":
+			return ErrInvalidVolume{Host: c.Host, Volume: v.Name, Reason: "raidType is missing"}
+		case len(v.Drives) == 0:
+			return ErrInvalidVolume{Host: c.Host, Volume: v.Name, Reason: "drives are missing"}
+		}
+		names[v.Name] = true
+	}
+	return nil
+}
+
+// Matches reports whether the volumes have the same RAID type and span the same drives.
+func (v Volume) Matches(other Volume) bool {
+	return strings.EqualFold(v.RAIDType, other.RAIDType) && sameDrives(v.Drives, other.Drives)
+}
+
+func sameDrives(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package raid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		volumes     []raid.Volume
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			volumes: []raid.Volume{
+				{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}},
+				{Name: "data", RAIDType: "RAID0", Drives: []string{"Disk.Bay.2"}},
+			},
+		},
+		{
+			name:        "missing-name",
+			volumes:     []raid.Volume{{RAIDType: "RAID0", Drives: []string{"Disk.Bay.0"}}},
+			expectedErr: raid.ErrInvalidVolume{Host: "node01", Reason: "name is missing"},
+		},
+		{
+			name: "duplicate-name",
+			volumes: []raid.Volume{
+				{Name: "os", RAIDType: "RAID0", Drives: []string{"Disk.Bay.0"}},
+				{Name: "os", RAIDType: "RAID0", Drives: []string{"Disk.Bay.1"}},
+			},
+			expectedErr: raid.ErrInvalidVolume{Host: "node01", Volume: "os", Reason: "name is not unique"},
+		},
+		{
+			name:        "missing-raid-type",
+			volumes:     []raid.Volume{{Name: "os", Drives: []string{"Disk.Bay.0"}}},
+			expectedErr: raid.ErrInvalidVolume{Host: "node01", Volume: "os", Reason: "raidType is missing"},
+		},
+		{
+			name:        "missing-drives",
+			volumes:     []raid.Volume{{Name: "os", RAIDType: "RAID0"}},
+			expectedErr: raid.ErrInvalidVolume{Host: "node01", Volume: "os", Reason: "drives are missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			config := raid.Config{Host: "node01", Volumes: tt.volumes}
+			assert.Equal(t, tt.expectedErr, config.Validate())
+		})
+	}
+}
+
+func TestVolumeMatches(t *testing.T) {
+	volume := raid.Volume{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}}
+
+	assert.True(t, volume.Matches(raid.Volume{ID: "Disk.Virtual.0", Name: "os", RAIDType: "raid1",
+		Drives: []string{"Disk.Bay.1", "Disk.Bay.0"}}))
+	assert.False(t, volume.Matches(raid.Volume{RAIDType: "RAID0", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}}))
+	assert.False(t, volume.Matches(raid.Volume{RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.2"}}))
+	assert.False(t, volume.Matches(raid.Volume{RAIDType: "RAID1", Drives: []string{"Disk.Bay.0"}}))
+	// The drives of the volume are left untouched
+	assert.Equal(t, []string{"Disk.Bay.0", "Disk.Bay.1"}, volume.Drives)
+}
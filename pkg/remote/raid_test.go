@@ -0,0 +1,181 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestRAIDConfigs(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("raid"))
+
+	configs, err := RAIDConfigs(settings, config.BootstrapPhase)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]raid.Config{
+		"master-0": {
+			Host:       "master-0",
+			Controller: "RAID.Integrated.1-1",
+			Volumes: []raid.Volume{
+				{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}},
+				{
+					Name:          "data",
+					RAIDType:      "RAID5",
+					Drives:        []string{"Disk.Bay.2", "Disk.Bay.3", "Disk.Bay.4"},
+					CapacityBytes: 1099511627776,
+				},
+			},
+		},
+	}, configs)
+
+	configs, err = RAIDConfigs(initSettings(t, withTestDataPath("base")), config.BootstrapPhase)
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+}
+
+func TestManagerApplyRAIDConfigs(t *testing.T) {
+	const controller = "RAID.Integrated.1-1"
+	osVolume := raid.Volume{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}}
+	dataVolume := raid.Volume{Name: "data", RAIDType: "RAID0", Drives: []string{"Disk.Bay.2"}}
+	desired := raid.Config{Host: "node01", Controller: controller, Volumes: []raid.Volume{osVolume, dataVolume}}
+
+	existingOS := raid.Volume{ID: "Disk.Virtual.0", Name: "os", RAIDType: "RAID1",
+		Drives: []string{"Disk.Bay.1", "Disk.Bay.0"}}
+	scratch := raid.Volume{ID: "Disk.Virtual.1", Name: "scratch", RAIDType: "RAID0", Drives: []string{"Disk.Bay.3"}}
+
+	tests := []struct {
+		name     string
+		opts     RAIDOptions
+		mocks    func(m *redfishutils.MockClient)
+		expected HostRAIDResult
+	}{
+		{
+			name: "create-missing",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("RAIDVolumes", mock.Anything, controller).Once().
+					Return([]raid.Volume{existingOS, scratch}, nil)
+				m.On("CreateRAIDVolume", mock.Anything, controller, dataVolume).Once().Return(nil)
+			},
+			expected: HostRAIDResult{Created: []string{"data"}},
+		},
+		{
+			name: "already-applied",
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("RAIDVolumes", mock.Anything, controller).Once().Return([]raid.Volume{
+					existingOS,
+					{ID: "Disk.Virtual.2", Name: "data", RAIDType: "raid0", Drives: []string{"Disk.Bay.2"}},
+				}, nil)
+			},
+			expected: HostRAIDResult{},
+		},
+		{
+			name: "prune",
+			opts: RAIDOptions{Prune: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("RAIDVolumes", mock.Anything, controller).Once().
+					Return([]raid.Volume{existingOS, scratch}, nil)
+				m.On("DeleteRAIDVolume", mock.Anything, controller, "Disk.Virtual.1").Once().Return(nil)
+				m.On("CreateRAIDVolume", mock.Anything, controller, dataVolume).Once().Return(nil)
+			},
+			expected: HostRAIDResult{Created: []string{"data"}, Deleted: []string{"scratch"}},
+		},
+		{
+			name: "dry-run",
+			opts: RAIDOptions{Prune: true, DryRun: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("RAIDVolumes", mock.Anything, controller).Once().Return([]raid.Volume{scratch}, nil)
+			},
+			expected: HostRAIDResult{Created: []string{"os", "data"}, Deleted: []string{"scratch"}},
+		},
+		{
+			name: "mismatch",
+			opts: RAIDOptions{Prune: true},
+			mocks: func(m *redfishutils.MockClient) {
+				m.On("RAIDVolumes", mock.Anything, controller).Once().Return([]raid.Volume{
+					{ID: "Disk.Virtual.0", Name: "os", RAIDType: "RAID0", Drives: []string{"Disk.Bay.0"}},
+					scratch,
+				}, nil)
+			},
+			expected: HostRAIDResult{Err: ErrRAIDVolumeMismatch{
+				Volume:  "os",
+				Current: raid.Volume{ID: "Disk.Virtual.0", Name: "os", RAIDType: "RAID0", Drives: []string{"Disk.Bay.0"}},
+				Desired: osVolume,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+			require.NoError(t, err)
+			defer rMock.AssertExpectations(t)
+
+			rMock.On("NodeID").Maybe().Return(systemID)
+			tt.mocks(rMock)
+
+			m := &Manager{Hosts: []baremetalHost{
+				{rMock, ctx, redfishURL, "node01", username, password},
+				{rMock, ctx, redfishURL, "node02", username, password},
+			}}
+
+			expected := tt.expected
+			expected.HostName = "node01"
+			assert.Equal(t, []HostRAIDResult{expected, {HostName: "node02", Skipped: true}},
+				m.ApplyRAIDConfigs(1, map[string]raid.Config{"node01": desired}, tt.opts))
+		})
+	}
+}
+
+func TestPrintRAIDResults(t *testing.T) {
+	results := []HostRAIDResult{
+		{HostName: "node01", Created: []string{"os", "data"}, Deleted: []string{"scratch"}},
+		{HostName: "node02", Skipped: true},
+		{HostName: "node03", Err: redfish.ErrRedfishClient{Message: "Unauthorized."}},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintRAIDResults(buf, results, RAIDOptions{DryRun: true}, OutputTable))
+	assert.Equal(t, `HOST     RESULT              CREATED   DELETED   DETAILS
+node01   changed (dry run)   os,data   scratch   
+node02   skipped (dry run)                       
+node03   failed (dry run)                        redfish client encountered an error: Unauthorized.
+`, buf.String())
+
+	expected := `[
+		{"host": "node01", "result": "changed", "created": ["os", "data"], "deleted": ["scratch"]},
+		{"host": "node02", "result": "skipped"},
+		{"host": "node03", "result": "failed", "error": "redfish client encountered an error: Unauthorized."}
+	]`
+	buf.Reset()
+	require.NoError(t, PrintRAIDResults(buf, results, RAIDOptions{}, OutputJSON))
+	assert.JSONEq(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintRAIDResults(buf, results, RAIDOptions{}, OutputYAML))
+	data, err := yaml.YAMLToJSON(buf.Bytes())
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+}
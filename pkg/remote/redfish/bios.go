@@ -0,0 +1,143 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	endpointBIOS = "/redfish/v1/Systems/%s/Bios"
+
+	// applyTimeOnReset makes the BMC apply pending settings on the next reset of the host. BMCs that support it,
+	// e.g. iDRAC, only schedule the job applying the settings when asked to.
+	applyTimeOnReset = "OnReset"
+)
+
+type biosResource struct {
+	Attributes map[string]interface{}
+	Settings   struct {
+		SettingsObject      odataRef
+		SupportedApplyTimes []string
+	} `json:"@Redfish.Settings"`
+}
+
+// BIOSAttributes retrieves the current BIOS attributes of the host.
+func (c *Client) BIOSAttributes(ctx context.Context) (map[string]interface{}, error) {
+	bios, err := c.getBIOS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bios.Attributes, nil
+}
+
+// SetBIOSAttributes stages attributes in the pending BIOS settings of the host. The BIOS applies them on the next
+// boot of the host, which is not triggered.
+func (c *Client) SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) error {
+	bios, err := c.getBIOS(ctx)
+	if err != nil {
+		return err
+	}
+
+	settingsPath := bios.Settings.SettingsObject.ID
+	if settingsPath == "" {
+		settingsPath = fmt.Sprintf(endpointBIOS, c.nodeID) + "/Settings"
+	}
+
+	body := map[string]interface{}{"Attributes": attributes}
+	for _, applyTime := range bios.Settings.SupportedApplyTimes {
+		if applyTime == applyTimeOnReset {
+			body["@Redfish.SettingsApplyTime"] = map[string]string{"ApplyTime": applyTimeOnReset}
+		}
+	}
+
+	return c.sendResource(ctx, http.MethodPatch, settingsPath, body)
+}
+
+func (c *Client) getBIOS(ctx context.Context) (biosResource, error) {
+	var bios biosResource
+	found, err := c.getResource(ctx, fmt.Sprintf(endpointBIOS, c.nodeID), &bios)
+	if err != nil {
+		return bios, err
+	}
+	if !found {
+		return bios, ErrRedfishClient{Message: fmt.Sprintf("BIOS of system[%s] not found.", c.nodeID)}
+	}
+	return bios, nil
+}
+
+// requestVerbs describe the requests made by sendResource in error messages.
+var requestVerbs = map[string]string{
+	http.MethodPatch:  "update",
+	http.MethodPost:   "create",
+	http.MethodDelete: "delete",
+}
+
+// sendResource sends a request with body, unless it is nil, to the Redfish resource at path.
+func (c *Client) sendResource(ctx context.Context, method string, path string, body interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	verb := requestVerbs[method]
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return ErrRedfishClient{Message: fmt.Sprintf("Unable to %s %s. %v", verb, path, err)}
+	}
+	defer httpResp.Body.Close()
+
+	switch httpResp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	}
+
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err == nil {
+		var redfishErr redfishErrorResponse
+		if json.Unmarshal(respBody, &redfishErr) == nil && len(redfishErr.Error.ExtendedInfo) > 0 {
+			return ErrRedfishClient{
+				Message: fmt.Sprintf("Unable to %s %s. %s", verb, path, redfishErr.Error.ExtendedInfo[0].Message),
+			}
+		}
+	}
+	return ErrRedfishClient{
+		Message: fmt.Sprintf("Unable to %s %s. BMC responded '%s'.", verb, path, httpResp.Status),
+	}
+}
+
+// redfishErrorResponse is the error body of failed Redfish requests.
+type redfishErrorResponse struct {
+	Error struct {
+		ExtendedInfo []struct {
+			Message string
+		} `json:"@Message.ExtendedInfo"`
+	} `json:"error"`
+}
@@ -0,0 +1,136 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const biosPath = "/redfish/v1/Systems/System.Embedded.1/Bios"
+
+// fakeBIOS serves the BIOS resource in GET responses and records the bodies of PATCH requests.
+type fakeBIOS struct {
+	fakeBMC
+	patchStatus int
+	patched     map[string]map[string]interface{}
+}
+
+func (b *fakeBIOS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		b.fakeBMC.ServeHTTP(w, r)
+		return
+	}
+
+	body := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.patched[r.URL.Path] = body
+	w.WriteHeader(b.patchStatus)
+	if b.patchStatus == http.StatusBadRequest {
+		_, _ = w.Write([]byte(`{"error": {"@Message.ExtendedInfo": [{"Message": "ProcCores is read-only."}]}}`))
+	}
+}
+
+func newFakeBIOS(bios string, patchStatus int) *fakeBIOS {
+	return &fakeBIOS{
+		fakeBMC:     fakeBMC{biosPath: bios},
+		patchStatus: patchStatus,
+		patched:     make(map[string]map[string]interface{}),
+	}
+}
+
+func TestBIOSAttributes(t *testing.T) {
+	bmc := newFakeBIOS(`{"Attributes": {"ProcVirtualization": "Enabled", "ProcCores": 16}}`, http.StatusOK)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+
+	attributes, err := client.BIOSAttributes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ProcVirtualization": "Enabled", "ProcCores": float64(16)}, attributes)
+
+	srv, client, ctx = newTestBMCClient(t, fakeBMC{})
+	defer srv.Close()
+
+	_, err = client.BIOSAttributes(ctx)
+	assert.IsType(t, ErrRedfishClient{}, err)
+}
+
+func TestSetBIOSAttributes(t *testing.T) {
+	attributes := map[string]interface{}{"ProcVirtualization": "Enabled"}
+
+	tests := []struct {
+		name         string
+		bios         string
+		patchStatus  int
+		expectedPath string
+		expectedBody map[string]interface{}
+		expectErr    bool
+	}{
+		{
+			name:         "default-settings-object",
+			bios:         `{"Attributes": {}}`,
+			patchStatus:  http.StatusOK,
+			expectedPath: biosPath + "/Settings",
+			expectedBody: map[string]interface{}{"Attributes": attributes},
+		},
+		{
+			name: "apply-on-reset",
+			bios: `{"Attributes": {}, "@Redfish.Settings": {
+				"SettingsObject": {"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Bios/Pending"},
+				"SupportedApplyTimes": ["Immediate", "OnReset"]
+			}}`,
+			patchStatus:  http.StatusAccepted,
+			expectedPath: biosPath + "/Pending",
+			expectedBody: map[string]interface{}{
+				"Attributes":                 attributes,
+				"@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "OnReset"},
+			},
+		},
+		{
+			name:         "rejected",
+			bios:         `{"Attributes": {}}`,
+			patchStatus:  http.StatusBadRequest,
+			expectedPath: biosPath + "/Settings",
+			expectedBody: map[string]interface{}{"Attributes": attributes},
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := newFakeBIOS(tt.bios, tt.patchStatus)
+			srv, client, ctx := newTestBMCClient(t, bmc)
+			defer srv.Close()
+
+			err := client.SetBIOSAttributes(ctx, attributes)
+			if tt.expectErr {
+				assert.Equal(t, ErrRedfishClient{
+					Message: "Unable to update " + tt.expectedPath + ". ProcCores is read-only.",
+				}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, map[string]map[string]interface{}{tt.expectedPath: tt.expectedBody}, bmc.patched)
+		})
+	}
+}
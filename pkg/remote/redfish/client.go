@@ -15,7 +15,9 @@ package redfish
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -24,6 +26,7 @@ import (
 	redfishClient "opendev.org/airship/go-redfish/client"
 
 	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 )
 
@@ -168,6 +171,7 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 			/* set boot source */
 			systemReq := redfishClient.ComputerSystem{}
 			systemReq.Boot.BootSourceOverrideTarget = bootSource
+			systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE
 			_, httpResp, err := c.RedfishAPI.SetSystem(ctx, c.nodeID, systemReq)
 			if err = ScreenRedfishError(httpResp, err); err != nil {
 				return err
@@ -181,6 +185,57 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	return ErrRedfishClient{Message: fmt.Sprintf("failed to set system[%s] boot source", c.nodeID)}
 }
 
+// redfishBootSources maps boot sources to Redfish boot override targets
+var redfishBootSources = map[boot.Source]redfishClient.BootSource{
+	boot.SourceCD:   redfishClient.BOOTSOURCE_CD,
+	boot.SourceDisk: redfishClient.BOOTSOURCE_HDD,
+	boot.SourcePXE:  redfishClient.BOOTSOURCE_PXE,
+}
+
+// SetBootSource makes the host boot from source. Unless persistent is true, the source is only used for the next
+// boot, after which the host returns to its default boot order.
+func (c *Client) SetBootSource(ctx context.Context, source boot.Source, persistent bool) error {
+	target, ok := redfishBootSources[source]
+	if !ok {
+		return boot.ErrUnknownSource{Source: string(source)}
+	}
+
+	system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
+	if err = ScreenRedfishError(httpResp, err); err != nil {
+		return err
+	}
+
+	// BMCs that don't advertise the allowable values are expected to reject unsupported targets themselves
+	allowableValues := system.Boot.BootSourceOverrideTargetRedfishAllowableValues
+	if len(allowableValues) > 0 && !containsBootSource(allowableValues, target) {
+		return ErrRedfishClient{Message: fmt.Sprintf("system[%s] can't boot from %s", c.nodeID, source)}
+	}
+
+	log.Debugf("Setting boot device of node '%s' to '%s', persistent: %t.", c.nodeID, target, persistent)
+	systemReq := redfishClient.ComputerSystem{}
+	systemReq.Boot.BootSourceOverrideTarget = target
+	systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE
+	if persistent {
+		systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS
+	}
+	_, httpResp, err = c.RedfishAPI.SetSystem(ctx, c.nodeID, systemReq)
+	if err = ScreenRedfishError(httpResp, err); err != nil {
+		return err
+	}
+
+	log.Debug("Successfully set boot device.")
+	return nil
+}
+
+func containsBootSource(sources []redfishClient.BootSource, source redfishClient.BootSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
 // SetVirtualMedia injects a virtual media device to an established virtual media ID. This assumes that isoPath is
 // accessible to the redfish server and virtualMedia device is either of type CD or DVD.
 func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
@@ -262,6 +317,82 @@ func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
 	}
 }
 
+// clientOptions are the settings of a client created by NewClient that a ClientOption customizes.
+type clientOptions struct {
+	transport        *http.Transport
+	sessionCacheFile string
+	sessionCacheTTL  time.Duration
+	requestRetries   int
+	requestDelay     time.Duration
+}
+
+// ClientOption customizes a client created by NewClient.
+type ClientOption func(opts *clientOptions) error
+
+// WithCertificateAuthority makes the client trust BMC certificates signed by the CAs in the PEM file caFile, in
+// addition to the CAs of the system.
+func WithCertificateAuthority(caFile string) ClientOption {
+	return func(opts *clientOptions) error {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return ErrInvalidCertificates{File: caFile}
+		}
+
+		tlsConfig(opts.transport).RootCAs = pool
+		return nil
+	}
+}
+
+// WithClientCertificate makes the client authenticate to BMCs with the certificate and key in the PEM files certFile
+// and keyFile.
+func WithClientCertificate(certFile string, keyFile string) ClientOption {
+	return func(opts *clientOptions) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+
+		tlsConfig(opts.transport).Certificates = []tls.Certificate{cert}
+		return nil
+	}
+}
+
+// WithRequestRetries makes the client retry requests that fail with transient errors, e.g. while the BMC resets, at
+// most retries times after waiting for delay.
+func WithRequestRetries(retries int, delay time.Duration) ClientOption {
+	return func(opts *clientOptions) error {
+		opts.requestRetries = retries
+		opts.requestDelay = delay
+		return nil
+	}
+}
+
+// WithSessionCache keeps the Redfish sessions of the client in file for ttl, so that later commands reuse them
+// instead of logging in again. The file holds session tokens, it is only readable by its owner.
+func WithSessionCache(file string, ttl time.Duration) ClientOption {
+	return func(opts *clientOptions) error {
+		opts.sessionCacheFile = file
+		opts.sessionCacheTTL = ttl
+		return nil
+	}
+}
+
+// tlsConfig returns the TLS configuration of transport, creating it if needed.
+func tlsConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport.TLSClientConfig
+}
+
 // NewClient returns a client with the capability to make Redfish requests.
 func NewClient(redfishURL string,
 	insecure bool,
@@ -269,7 +400,8 @@ func NewClient(redfishURL string,
 	username string,
 	password string,
 	systemActionRetries int,
-	systemRebootDelay int) (context.Context, *Client, error) {
+	systemRebootDelay int,
+	opts ...ClientOption) (context.Context, *Client, error) {
 	var ctx context.Context
 	if username != "" && password != "" {
 		ctx = context.WithValue(
@@ -304,17 +436,31 @@ func NewClient(redfishURL string,
 	transport := defaultTransportCopy.Clone()
 
 	if insecure {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec
-		}
+		tlsConfig(transport).InsecureSkipVerify = true //nolint:gosec
 	}
 
 	if !useProxy {
 		transport.Proxy = nil
 	}
 
+	options := &clientOptions{transport: transport}
+	for _, opt := range opts {
+		if err = opt(options); err != nil {
+			return ctx, nil, err
+		}
+	}
+
 	cfg.HTTPClient = &http.Client{
-		Transport: transport,
+		Transport: &sessionTransport{
+			base: &retryTransport{
+				base:    transport,
+				retries: options.requestRetries,
+				delay:   options.requestDelay,
+			},
+			store:     sessions,
+			cacheFile: options.sessionCacheFile,
+			cacheTTL:  options.sessionCacheTTL,
+		},
 	}
 
 	// Retrieve system ID from end of Redfish URL
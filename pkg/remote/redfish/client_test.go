@@ -16,7 +16,13 @@ package redfish
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -27,6 +33,7 @@ import (
 	redfishMocks "opendev.org/airship/go-redfish/api/mocks"
 	redfishClient "opendev.org/airship/go-redfish/client"
 
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
 )
@@ -82,6 +89,78 @@ func TestNewClientEmptyRedfishURL(t *testing.T) {
 	_, _, err := NewClient("", false, false, "", "", systemActionRetries, systemRebootDelay)
 	assert.Error(t, err)
 }
+func TestNewClientCertificateAuthority(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tempDir, err := ioutil.TempDir("", "redfish-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	caFile := filepath.Join(tempDir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+	_, err = client.RedfishCFG.HTTPClient.Get(srv.URL) //nolint:bodyclose
+	assert.Error(t, err)
+
+	_, client, err = NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay,
+		WithCertificateAuthority(caFile))
+	require.NoError(t, err)
+	resp, err := client.RedfishCFG.HTTPClient.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	invalidFile := filepath.Join(tempDir, "invalid.pem")
+	require.NoError(t, ioutil.WriteFile(invalidFile, []byte("not a certificate"), 0600))
+	_, _, err = NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay,
+		WithCertificateAuthority(invalidFile))
+	assert.Equal(t, ErrInvalidCertificates{File: invalidFile}, err)
+
+	_, _, err = NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay,
+		WithCertificateAuthority(filepath.Join(tempDir, "missing.pem")))
+	assert.Error(t, err)
+}
+
+func TestNewClientClientCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tempDir, err := ioutil.TempDir("", "redfish-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// Reuse the key pair of the test server as client certificate
+	serverCert := srv.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(tempDir, "client.pem")
+	keyFile := filepath.Join(tempDir, "client-key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Certificate[0]}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600))
+
+	_, client, err := NewClient(redfishURL, true, false, "", "", systemActionRetries, systemRebootDelay,
+		WithClientCertificate(certFile, keyFile))
+	require.NoError(t, err)
+
+	sessions, ok := client.RedfishCFG.HTTPClient.Transport.(*sessionTransport)
+	require.True(t, ok)
+	retries, ok := sessions.base.(*retryTransport)
+	require.True(t, ok)
+	transport, ok := retries.base.(*http.Transport)
+	require.True(t, ok)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Len(t, transport.TLSClientConfig.Certificates, 1)
+
+	_, _, err = NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay,
+		WithClientCertificate(certFile, certFile))
+	assert.Error(t, err)
+}
+
 func TestEjectVirtualMedia(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
@@ -352,6 +431,32 @@ func TestSetBootSourceByTypeSetSystemError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSetBootSourceByTypeOnce(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	assert.NoError(t, err)
+
+	client.nodeID = nodeID
+
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"CD"}), httpResp, nil)
+	m.On("SetSystem", ctx, client.nodeID, mock.MatchedBy(func(req redfishClient.ComputerSystem) bool {
+		return req.Boot.BootSourceOverrideTarget == redfishClient.BOOTSOURCE_CD &&
+			req.Boot.BootSourceOverrideEnabled == redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE
+	})).Times(1).Return(redfishClient.ComputerSystem{}, httpResp, nil)
+
+	// Replace normal API client with mocked API client
+	client.RedfishAPI = m
+
+	assert.NoError(t, client.SetBootSourceByType(ctx))
+}
+
 func TestSetBootSourceByTypeBootSourceUnavailable(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
@@ -384,6 +489,111 @@ func TestSetBootSourceByTypeBootSourceUnavailable(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestSetBootSource(t *testing.T) {
+	tests := []struct {
+		source     boot.Source
+		persistent bool
+		target     redfishClient.BootSource
+		enabled    redfishClient.BootSourceOverrideEnabled
+	}{
+		{
+			source:  boot.SourceCD,
+			target:  redfishClient.BOOTSOURCE_CD,
+			enabled: redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE,
+		},
+		{
+			source:     boot.SourceDisk,
+			persistent: true,
+			target:     redfishClient.BOOTSOURCE_HDD,
+			enabled:    redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS,
+		},
+		{
+			source:  boot.SourcePXE,
+			target:  redfishClient.BOOTSOURCE_PXE,
+			enabled: redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.source), func(t *testing.T) {
+			m := &redfishMocks.RedfishAPI{}
+			defer m.AssertExpectations(t)
+
+			ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+			require.NoError(t, err)
+
+			client.nodeID = nodeID
+
+			httpResp := &http.Response{StatusCode: 200}
+			m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+			m.On("SetSystem", ctx, client.nodeID, mock.MatchedBy(func(req redfishClient.ComputerSystem) bool {
+				return req.Boot.BootSourceOverrideTarget == tt.target &&
+					req.Boot.BootSourceOverrideEnabled == tt.enabled
+			})).Times(1).Return(redfishClient.ComputerSystem{}, httpResp, nil)
+
+			// Replace normal API client with mocked API client
+			client.RedfishAPI = m
+
+			assert.NoError(t, client.SetBootSource(ctx, tt.source, tt.persistent))
+		})
+	}
+}
+
+func TestSetBootSourceUnknownSource(t *testing.T) {
+	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+
+	err = client.SetBootSource(ctx, boot.Source("floppy"), false)
+	assert.Equal(t, boot.ErrUnknownSource{Source: "floppy"}, err)
+}
+
+func TestSetBootSourceUnavailable(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+
+	client.nodeID = nodeID
+
+	system := testutil.GetTestSystem()
+	system.Boot.BootSourceOverrideTargetRedfishAllowableValues = []redfishClient.BootSource{
+		redfishClient.BOOTSOURCE_HDD,
+	}
+
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(system, httpResp, nil)
+
+	// Replace normal API client with mocked API client
+	client.RedfishAPI = m
+
+	err = client.SetBootSource(ctx, boot.SourcePXE, false)
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+}
+
+func TestSetBootSourceSetSystemError(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+
+	client.nodeID = nodeID
+
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("SetSystem", ctx, client.nodeID, mock.Anything).Times(1).Return(
+		redfishClient.ComputerSystem{}, &http.Response{StatusCode: 401}, redfishClient.GenericOpenAPIError{})
+
+	// Replace normal API client with mocked API client
+	client.RedfishAPI = m
+
+	err = client.SetBootSource(ctx, boot.SourceDisk, true)
+	assert.Error(t, err)
+}
+
 func TestSetVirtualMediaEjectExistingMedia(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
@@ -0,0 +1,123 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	endpointLogServices = "/redfish/v1/Systems/%s/LogServices"
+
+	// hostLoggerID is the log service in which OpenBMC based BMCs buffer the serial console output of the host.
+	hostLoggerID = "HostLogger"
+)
+
+// consolePaths are the pages of the web UI of BMCs that open the graphical console of the host, by the OEM
+// extension of the manager resource identifying the BMC.
+var consolePaths = map[string]string{
+	// iDRAC redirects to the virtual console once logged in
+	"Dell": "/console",
+	// Standalone HTML5 integrated remote console of iLO 5
+	"Hpe": "/irc.html",
+}
+
+type managerResource struct {
+	Oem              map[string]interface{}
+	GraphicalConsole struct {
+		ServiceEnabled *bool
+	}
+}
+
+type logEntryCollection struct {
+	Members []struct {
+		ID       string `json:"@odata.id"`
+		Created  string
+		Severity string
+		Message  string
+	}
+	NextLink string `json:"Members@odata.nextLink"`
+}
+
+// ConsoleLog writes the serial console output buffered by the BMC to w. Only BMCs that buffer the console in a
+// HostLogger log service, such as OpenBMC, provide it.
+func (c *Client) ConsoleLog(ctx context.Context, w io.Writer) error {
+	var hostLogger string
+	err := c.forEachMember(ctx, fmt.Sprintf(endpointLogServices, c.nodeID), func(path string) error {
+		if strings.HasSuffix(path, "/"+hostLoggerID) {
+			hostLogger = path
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if hostLogger == "" {
+		return ErrConsoleLogNotSupported{NodeID: c.nodeID}
+	}
+
+	for path := hostLogger + "/Entries"; path != ""; {
+		var entries logEntryCollection
+		if _, err = c.getResource(ctx, path, &entries); err != nil {
+			return err
+		}
+
+		for _, entry := range entries.Members {
+			line := entry.Message
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			if _, err = io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		path = entries.NextLink
+	}
+
+	return nil
+}
+
+// ConsoleURL returns the URL of the graphical console (KVM) of the host in the web UI of its BMC. iDRAC and iLO, told
+// apart by the OEM extensions of the manager of the system, have a page for it; the home page of the web UI of other
+// BMCs is returned.
+func (c *Client) ConsoleURL(ctx context.Context) (string, error) {
+	var system systemResource
+	found, err := c.getResource(ctx, fmt.Sprintf(endpointSystem, c.nodeID), &system)
+	if err != nil {
+		return "", err
+	}
+	if !found || len(system.Links.ManagedBy) == 0 {
+		return "", ErrRedfishClient{Message: fmt.Sprintf("Unable to find manager for node '%s'.", c.nodeID)}
+	}
+
+	var manager managerResource
+	if _, err = c.getResource(ctx, system.Links.ManagedBy[0].ID, &manager); err != nil {
+		return "", err
+	}
+	if enabled := manager.GraphicalConsole.ServiceEnabled; enabled != nil && !*enabled {
+		return "", ErrGraphicalConsoleDisabled{NodeID: c.nodeID}
+	}
+
+	consolePath := "/"
+	for oem := range manager.Oem {
+		if path, ok := consolePaths[oem]; ok {
+			consolePath = path
+		}
+	}
+	return c.RedfishCFG.BasePath + consolePath, nil
+}
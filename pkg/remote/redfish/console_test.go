@@ -0,0 +1,106 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleLog(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{
+		"/redfish/v1/Systems/System.Embedded.1/LogServices": `{"Members": [
+			{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/LogServices/EventLog"},
+			{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/LogServices/HostLogger"}
+		]}`,
+		"/redfish/v1/Systems/System.Embedded.1/LogServices/HostLogger/Entries": `{
+			"Members": [{"Message": "Booting from CD"}, {"Message": "Loading kernel\n"}],
+			"Members@odata.nextLink": "/redfish/v1/Systems/System.Embedded.1/LogServices/HostLogger/Entries/2"
+		}`,
+		"/redfish/v1/Systems/System.Embedded.1/LogServices/HostLogger/Entries/2": `{
+			"Members": [{"Message": "Kernel panic"}]
+		}`,
+	})
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, client.ConsoleLog(ctx, buf))
+	assert.Equal(t, "Booting from CD\nLoading kernel\nKernel panic\n", buf.String())
+}
+
+func TestConsoleLogNotSupported(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{
+		"/redfish/v1/Systems/System.Embedded.1/LogServices": `{"Members": [
+			{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/LogServices/Sel"}
+		]}`,
+	})
+	defer srv.Close()
+
+	assert.Equal(t, ErrConsoleLogNotSupported{NodeID: "System.Embedded.1"}, client.ConsoleLog(ctx, &bytes.Buffer{}))
+}
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		manager     string
+		expectedURL string
+		expectedErr error
+	}{
+		{
+			name:        "idrac",
+			manager:     `{"Oem": {"Dell": {}}, "GraphicalConsole": {"ServiceEnabled": true}}`,
+			expectedURL: "/console",
+		},
+		{
+			name:        "ilo",
+			manager:     `{"Oem": {"Hpe": {}}}`,
+			expectedURL: "/irc.html",
+		},
+		{
+			name:        "generic",
+			manager:     `{"Oem": {"OpenBmc": {}}}`,
+			expectedURL: "/",
+		},
+		{
+			name:        "disabled",
+			manager:     `{"GraphicalConsole": {"ServiceEnabled": false}}`,
+			expectedErr: ErrGraphicalConsoleDisabled{NodeID: "System.Embedded.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client, ctx := newTestBMCClient(t, fakeBMC{
+				"/redfish/v1/Systems/System.Embedded.1": `{"Links": {"ManagedBy": [
+					{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1"}
+				]}}`,
+				"/redfish/v1/Managers/iDRAC.Embedded.1": tt.manager,
+			})
+			defer srv.Close()
+
+			url, err := client.ConsoleURL(ctx)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, srv.URL+tt.expectedURL, url)
+		})
+	}
+}
@@ -39,6 +39,15 @@ func (e ErrRedfishMissingConfig) Error() string {
 	return "missing configuration: " + e.What
 }
 
+// ErrInvalidCertificates is returned when a certificate authority file doesn't contain PEM encoded certificates.
+type ErrInvalidCertificates struct {
+	File string
+}
+
+func (e ErrInvalidCertificates) Error() string {
+	return fmt.Sprintf("no PEM encoded certificates found in %s", e.File)
+}
+
 // ErrOperationRetriesExceeded raised if number of operation retries exceeded
 type ErrOperationRetriesExceeded struct {
 	What    string
@@ -58,3 +67,30 @@ type ErrUnrecognizedRedfishResponse struct {
 func (e ErrUnrecognizedRedfishResponse) Error() string {
 	return fmt.Sprintf("Unable to decode Redfish response. Key '%s' is missing or has unknown format.", e.Key)
 }
+
+// ErrConsoleLogNotSupported is returned when the BMC of a system doesn't buffer its serial console output.
+type ErrConsoleLogNotSupported struct {
+	NodeID string
+}
+
+func (e ErrConsoleLogNotSupported) Error() string {
+	return fmt.Sprintf("the BMC of system[%s] doesn't provide a serial console log", e.NodeID)
+}
+
+// ErrGraphicalConsoleDisabled is returned when the graphical console service of the BMC of a system is disabled.
+type ErrGraphicalConsoleDisabled struct {
+	NodeID string
+}
+
+func (e ErrGraphicalConsoleDisabled) Error() string {
+	return fmt.Sprintf("the graphical console of the BMC of system[%s] is disabled", e.NodeID)
+}
+
+// ErrSecureBootNotSupported is returned when the BMC of a system doesn't provide its UEFI Secure Boot resource.
+type ErrSecureBootNotSupported struct {
+	NodeID string
+}
+
+func (e ErrSecureBootNotSupported) Error() string {
+	return fmt.Sprintf("the BMC of system[%s] doesn't support managing UEFI Secure Boot", e.NodeID)
+}
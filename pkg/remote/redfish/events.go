@@ -0,0 +1,148 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+)
+
+const endpointTasks = "/redfish/v1/TaskService/Tasks"
+
+// Task states after which a task did not complete its operation
+var failedTaskStates = map[string]bool{
+	"Cancelled": true,
+	"Exception": true,
+	"Killed":    true,
+}
+
+type taskResource struct {
+	ID         string `json:"@odata.id"`
+	Name       string
+	TaskState  string
+	TaskStatus string
+	StartTime  string
+	Messages   []struct {
+		Message string
+	}
+}
+
+// Events retrieves the entries of the event logs of the system and of its manager, e.g. the SEL, and the tasks of the
+// BMC. The serial console buffered in the HostLogger log service isn't an event log and is left out. Redfish event
+// subscriptions need the BMC to reach the subscriber, the logs are polled instead.
+func (c *Client) Events(ctx context.Context) ([]events.Event, error) {
+	var system systemResource
+	found, err := c.getResource(ctx, fmt.Sprintf(endpointSystem, c.nodeID), &system)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrRedfishClient{Message: fmt.Sprintf("System[%s] not found.", c.nodeID)}
+	}
+
+	logServices := []string{fmt.Sprintf(endpointLogServices, c.nodeID)}
+	for _, manager := range system.Links.ManagedBy {
+		logServices = append(logServices, manager.ID+"/LogServices")
+	}
+
+	var result []events.Event
+	for _, logServicesPath := range logServices {
+		err = c.forEachMember(ctx, logServicesPath, func(path string) error {
+			if strings.HasSuffix(path, "/"+hostLoggerID) {
+				return nil
+			}
+			logEvents, err := c.logEvents(ctx, path+"/Entries")
+			result = append(result, logEvents...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.forEachMember(ctx, endpointTasks, func(path string) error {
+		var task taskResource
+		found, err := c.getResource(ctx, path, &task)
+		if found {
+			result = append(result, taskEvent(task))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// logEvents returns the entries of the log at path, following the pages of the collection.
+func (c *Client) logEvents(ctx context.Context, path string) ([]events.Event, error) {
+	var result []events.Event
+	for path != "" {
+		var entries logEntryCollection
+		if _, err := c.getResource(ctx, path, &entries); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries.Members {
+			result = append(result, events.Event{
+				ID:       entry.ID,
+				Time:     parseTime(entry.Created),
+				Severity: events.Severity(entry.Severity),
+				Message:  strings.TrimSpace(entry.Message),
+			})
+		}
+		path = entries.NextLink
+	}
+	return result, nil
+}
+
+// taskEvent describes the state of a task as an event, critical if the task failed.
+func taskEvent(task taskResource) events.Event {
+	event := events.Event{
+		ID:       task.ID,
+		Time:     parseTime(task.StartTime),
+		Severity: events.SeverityOK,
+		Message:  fmt.Sprintf("Task '%s' is %s", task.Name, task.TaskState),
+	}
+
+	switch {
+	case failedTaskStates[task.TaskState]:
+		event.Severity = events.SeverityCritical
+	case task.TaskStatus == string(events.SeverityWarning):
+		event.Severity = events.SeverityWarning
+	}
+	var messages []string
+	for _, message := range task.Messages {
+		messages = append(messages, message.Message)
+	}
+	if len(messages) > 0 {
+		event.Message += ": " + strings.Join(messages, "; ")
+	}
+	return event
+}
+
+// parseTime parses a Redfish timestamp, timestamps some BMCs leave empty or malformed are zero.
+func parseTime(timestamp string) time.Time {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
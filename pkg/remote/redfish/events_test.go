@@ -0,0 +1,93 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+)
+
+func TestEvents(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{
+		"/redfish/v1/Systems/System.Embedded.1": `{"Links": {"ManagedBy": [
+			{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1"}
+		]}}`,
+		"/redfish/v1/Systems/System.Embedded.1/LogServices": `{"Members": [
+			{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/LogServices/HostLogger"}
+		]}`,
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices": `{"Members": [
+			{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel"}
+		]}`,
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries": `{
+			"Members": [{"@odata.id": "/Sel/Entries/1", "Created": "2020-06-01T10:00:00Z", "Severity": "OK",
+				"Message": "The system booted. "}],
+			"Members@odata.nextLink": "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/2"
+		}`,
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/2": `{
+			"Members": [{"@odata.id": "/Sel/Entries/2", "Created": "", "Severity": "Critical",
+				"Message": "No bootable device."}]
+		}`,
+		"/redfish/v1/TaskService/Tasks": `{"Members": [
+			{"@odata.id": "/redfish/v1/TaskService/Tasks/JID_1"},
+			{"@odata.id": "/redfish/v1/TaskService/Tasks/JID_2"}
+		]}`,
+		"/redfish/v1/TaskService/Tasks/JID_1": `{"@odata.id": "/redfish/v1/TaskService/Tasks/JID_1",
+			"Name": "Configure: RAID.Integrated.1-1", "TaskState": "Exception", "TaskStatus": "Critical",
+			"StartTime": "2020-06-01T10:05:00Z", "Messages": [{"Message": "Job failed."}, {"Message": "Disk busy."}]}`,
+		"/redfish/v1/TaskService/Tasks/JID_2": `{"@odata.id": "/redfish/v1/TaskService/Tasks/JID_2",
+			"Name": "Export: Server Configuration Profile", "TaskState": "Completed", "TaskStatus": "Warning"}`,
+	})
+	defer srv.Close()
+
+	result, err := client.Events(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []events.Event{
+		{
+			ID:       "/Sel/Entries/1",
+			Time:     time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+			Severity: events.SeverityOK,
+			Message:  "The system booted.",
+		},
+		{
+			ID:       "/Sel/Entries/2",
+			Severity: events.SeverityCritical,
+			Message:  "No bootable device.",
+		},
+		{
+			ID:       "/redfish/v1/TaskService/Tasks/JID_1",
+			Time:     time.Date(2020, 6, 1, 10, 5, 0, 0, time.UTC),
+			Severity: events.SeverityCritical,
+			Message:  "Task 'Configure: RAID.Integrated.1-1' is Exception: Job failed.; Disk busy.",
+		},
+		{
+			ID:       "/redfish/v1/TaskService/Tasks/JID_2",
+			Severity: events.SeverityWarning,
+			Message:  "Task 'Export: Server Configuration Profile' is Completed",
+		},
+	}, result)
+}
+
+func TestEventsSystemNotFound(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{})
+	defer srv.Close()
+
+	_, err := client.Events(ctx)
+	assert.Equal(t, ErrRedfishClient{Message: "System[System.Embedded.1] not found."}, err)
+}
@@ -0,0 +1,67 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const endpointServiceRoot = "/redfish/v1"
+
+// Ping checks that the BMC answers Redfish requests. The service root is requested without credentials, which BMCs
+// serve to anyone.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, c.RedfishCFG.BasePath+endpointServiceRoot, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", headerUserAgent)
+
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return ErrRedfishClient{Message: fmt.Sprintf("Unable to reach BMC at %s. %v", c.RedfishCFG.BasePath, err)}
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return ErrRedfishClient{
+			Message: fmt.Sprintf("Redfish service root of BMC responded '%s'.", httpResp.Status),
+		}
+	}
+	return nil
+}
+
+// VerifyCredentials checks that the BMC accepts the credentials of the client by retrieving the system.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	var system systemResource
+	found, err := c.getResource(ctx, fmt.Sprintf(endpointSystem, c.nodeID), &system)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrRedfishClient{Message: fmt.Sprintf("System[%s] not found.", c.nodeID)}
+	}
+	return nil
+}
+
+// VerifyVirtualMedia checks that the manager of the system has virtual media that can insert an ISO as CD or DVD.
+func (c *Client) VerifyVirtualMedia(ctx context.Context) error {
+	_, _, err := GetVirtualMediaID(ctx, c.RedfishAPI, c.nodeID)
+	return err
+}
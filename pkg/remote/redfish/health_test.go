@@ -0,0 +1,80 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	redfishMocks "opendev.org/airship/go-redfish/api/mocks"
+
+	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
+)
+
+func TestPing(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The service root is served without credentials
+		if _, _, ok := r.BasicAuth(); ok || r.URL.Path != endpointServiceRoot {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"RedfishVersion": "1.6.0"}`))
+	}))
+	assert.NoError(t, client.Ping(ctx))
+
+	srv.Close()
+	assert.IsType(t, ErrRedfishClient{}, client.Ping(ctx))
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, sessionTestResources)
+	defer srv.Close()
+	assert.NoError(t, client.VerifyCredentials(ctx))
+
+	ctx, client = newSessionTestClient(t, srv, "wrong")
+	assert.Equal(t, ErrRedfishClient{
+		Message: "Unable to get /redfish/v1/Systems/System.Embedded.1. BMC responded '401 Unauthorized'.",
+	}, client.VerifyCredentials(ctx))
+}
+
+func TestVerifyVirtualMedia(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	assert.NoError(t, err)
+	client.nodeID = nodeID
+	client.RedfishAPI = m
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Floppy", "Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Floppy").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"Floppy"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"CD"}), httpResp, nil)
+	assert.NoError(t, client.VerifyVirtualMedia(ctx))
+
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Floppy"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Floppy").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"Floppy"}), httpResp, nil)
+	assert.IsType(t, ErrRedfishClient{}, client.VerifyVirtualMedia(ctx))
+}
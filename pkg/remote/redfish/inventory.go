@@ -0,0 +1,217 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+)
+
+// The inventory resources are not covered by go-redfish and are read with plain requests instead.
+const (
+	endpointSystem             = "/redfish/v1/Systems/%s"
+	endpointEthernetInterfaces = "/redfish/v1/Systems/%s/EthernetInterfaces"
+	endpointStorage            = "/redfish/v1/Systems/%s/Storage"
+	endpointFirmwareInventory  = "/redfish/v1/UpdateService/FirmwareInventory"
+)
+
+type odataRef struct {
+	ID string `json:"@odata.id"`
+}
+
+type resourceCollection struct {
+	Members []odataRef
+}
+
+type systemResource struct {
+	Manufacturer string
+	Model        string
+	SerialNumber string
+	BiosVersion  string
+	Links        struct {
+		ManagedBy []odataRef
+	}
+}
+
+type firmwareResource struct {
+	ID      string `json:"Id"`
+	Name    string
+	Version string
+}
+
+type ethernetInterfaceResource struct {
+	ID         string `json:"Id"`
+	Name       string
+	MACAddress string
+	SpeedMbps  int
+}
+
+type storageResource struct {
+	Drives  []odataRef
+	Volumes odataRef
+}
+
+type driveResource struct {
+	ID            string `json:"Id"`
+	Name          string
+	Model         string
+	SerialNumber  string
+	MediaType     string
+	CapacityBytes int64
+	Revision      string
+}
+
+// HardwareInventory retrieves the firmware versions, NICs and disks of the host. Collections the BMC doesn't
+// provide, e.g. the firmware inventory of older Redfish versions, are reported empty.
+func (c *Client) HardwareInventory(ctx context.Context) (hardware.Inventory, error) {
+	inventory := hardware.Inventory{
+		Firmware: []hardware.Firmware{},
+		NICs:     []hardware.NIC{},
+		Disks:    []hardware.Disk{},
+	}
+
+	var system systemResource
+	systemPath := fmt.Sprintf(endpointSystem, c.nodeID)
+	found, err := c.getResource(ctx, systemPath, &system)
+	if err != nil {
+		return inventory, err
+	}
+	if !found {
+		return inventory, ErrRedfishClient{Message: fmt.Sprintf("System[%s] not found.", c.nodeID)}
+	}
+	inventory.Manufacturer = system.Manufacturer
+	inventory.Model = system.Model
+	inventory.SerialNumber = system.SerialNumber
+	inventory.BIOSVersion = system.BiosVersion
+
+	err = c.forEachMember(ctx, endpointFirmwareInventory, func(path string) error {
+		var firmware firmwareResource
+		if _, err := c.getResource(ctx, path, &firmware); err != nil {
+			return err
+		}
+		inventory.Firmware = append(inventory.Firmware, hardware.Firmware(firmware))
+		return nil
+	})
+	if err != nil {
+		return inventory, err
+	}
+
+	err = c.forEachMember(ctx, fmt.Sprintf(endpointEthernetInterfaces, c.nodeID), func(path string) error {
+		var nic ethernetInterfaceResource
+		if _, err := c.getResource(ctx, path, &nic); err != nil {
+			return err
+		}
+		inventory.NICs = append(inventory.NICs, hardware.NIC(nic))
+		return nil
+	})
+	if err != nil {
+		return inventory, err
+	}
+
+	err = c.forEachMember(ctx, fmt.Sprintf(endpointStorage, c.nodeID), func(path string) error {
+		var storage storageResource
+		if _, err := c.getResource(ctx, path, &storage); err != nil {
+			return err
+		}
+		for _, ref := range storage.Drives {
+			var drive driveResource
+			if _, err := c.getResource(ctx, ref.ID, &drive); err != nil {
+				return err
+			}
+			inventory.Disks = append(inventory.Disks, hardware.Disk(drive))
+		}
+		return nil
+	})
+
+	return inventory, err
+}
+
+// forEachMember calls f with the path of every member of the collection at path. A collection the BMC doesn't
+// provide has no members.
+func (c *Client) forEachMember(ctx context.Context, path string, f func(path string) error) error {
+	var collection resourceCollection
+	found, err := c.getResource(ctx, path, &collection)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Debugf("BMC of node '%s' doesn't provide '%s', skipping it.", c.nodeID, path)
+		return nil
+	}
+
+	for _, member := range collection.Members {
+		if err = f(member.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getResource decodes the Redfish resource at path into v. found is false if the BMC doesn't provide the resource.
+func (c *Client) getResource(ctx context.Context, path string, v interface{}) (found bool, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return false, err
+	}
+
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return false, ErrRedfishClient{Message: fmt.Sprintf("Unable to get %s. %v", path, err)}
+	}
+	defer httpResp.Body.Close()
+
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, ErrRedfishClient{
+			Message: fmt.Sprintf("Unable to get %s. BMC responded '%s'.", path, httpResp.Status),
+		}
+	}
+
+	if err = json.NewDecoder(httpResp.Body).Decode(v); err != nil {
+		return false, ErrRedfishClient{Message: fmt.Sprintf("Unable to get %s. Malformed BMC response.", path)}
+	}
+	return true, nil
+}
+
+// newRequest builds a request to the Redfish resource at path, authenticated with the credentials stored in ctx.
+func (c *Client) newRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.RedfishCFG.BasePath+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", headerUserAgent)
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	if auth, ok := ctx.Value(redfishClient.ContextBasicAuth).(redfishClient.BasicAuth); ok {
+		req.SetBasicAuth(auth.UserName, auth.Password)
+	}
+
+	return req, nil
+}
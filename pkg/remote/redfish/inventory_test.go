@@ -0,0 +1,156 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
+)
+
+// fakeBMC serves the Redfish resources in its map, keyed by path, to requests authenticated as admin.
+type fakeBMC map[string]string
+
+func (b fakeBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "password" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, ok := b[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+}
+
+var testBMCResources = fakeBMC{
+	"/redfish/v1/Systems/System.Embedded.1": `{
+		"Manufacturer": "Dell Inc.", "Model": "PowerEdge R640", "SerialNumber": "CN123", "BiosVersion": "2.8.1"
+	}`,
+	"/redfish/v1/UpdateService/FirmwareInventory": `{"Members": [
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Installed-25227-4.20"}
+	]}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/Installed-25227-4.20": `{
+		"Id": "Installed-25227-4.20", "Name": "Integrated Dell Remote Access Controller", "Version": "4.20.20.20"
+	}`,
+	"/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces": `{"Members": [
+		{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces/NIC.Integrated.1-1-1"}
+	]}`,
+	"/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces/NIC.Integrated.1-1-1": `{
+		"Id": "NIC.Integrated.1-1-1", "Name": "System Ethernet Interface", "MACAddress": "E4:43:4B:0A:1B:2C",
+		"SpeedMbps": 10000
+	}`,
+	"/redfish/v1/Systems/System.Embedded.1/Storage": `{"Members": [
+		{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1"}
+	]}`,
+	"/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1": `{"Drives": [
+		{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Storage/Drives/Disk.Bay.0"}
+	]}`,
+	"/redfish/v1/Systems/System.Embedded.1/Storage/Drives/Disk.Bay.0": `{
+		"Id": "Disk.Bay.0", "Name": "SSD 0", "Model": "MZ7KH480HAHQ0D3", "SerialNumber": "S45PNA0M", "MediaType": "SSD",
+		"CapacityBytes": 480103981056, "Revision": "HG58"
+	}`,
+}
+
+// newTestBMCClient returns a client of the system System.Embedded.1 of a test server running handler.
+func newTestBMCClient(t *testing.T, handler http.Handler) (*httptest.Server, *Client, context.Context) {
+	srv := httptest.NewServer(handler)
+
+	ctx, client, err := NewClient("redfish+"+srv.URL+"/redfish/v1/Systems/System.Embedded.1", false, false,
+		"admin", "password", systemActionRetries, systemRebootDelay)
+	require.NoError(t, err)
+
+	return srv, client, ctx
+}
+
+func TestHardwareInventory(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, testBMCResources)
+	defer srv.Close()
+
+	actual, err := client.HardwareInventory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, hardware.Inventory{
+		Manufacturer: "Dell Inc.",
+		Model:        "PowerEdge R640",
+		SerialNumber: "CN123",
+		BIOSVersion:  "2.8.1",
+		Firmware: []hardware.Firmware{
+			{ID: "Installed-25227-4.20", Name: "Integrated Dell Remote Access Controller", Version: "4.20.20.20"},
+		},
+		NICs: []hardware.NIC{
+			{ID: "NIC.Integrated.1-1-1", Name: "System Ethernet Interface", MACAddress: "E4:43:4B:0A:1B:2C",
+				SpeedMbps: 10000},
+		},
+		Disks: []hardware.Disk{
+			{ID: "Disk.Bay.0", Name: "SSD 0", Model: "MZ7KH480HAHQ0D3", SerialNumber: "S45PNA0M", MediaType: "SSD",
+				CapacityBytes: 480103981056, Revision: "HG58"},
+		},
+	}, actual)
+}
+
+func TestHardwareInventoryMissingCollections(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{
+		"/redfish/v1/Systems/System.Embedded.1": `{"Model": "PowerEdge R640"}`,
+	})
+	defer srv.Close()
+
+	actual, err := client.HardwareInventory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, hardware.Inventory{
+		Model:    "PowerEdge R640",
+		Firmware: []hardware.Firmware{},
+		NICs:     []hardware.NIC{},
+		Disks:    []hardware.Disk{},
+	}, actual)
+}
+
+func TestHardwareInventoryErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		bmc  fakeBMC
+	}{
+		{
+			name: "system-not-found",
+			bmc:  fakeBMC{},
+		},
+		{
+			name: "malformed-response",
+			bmc: fakeBMC{
+				"/redfish/v1/Systems/System.Embedded.1":       `{}`,
+				"/redfish/v1/UpdateService/FirmwareInventory": `{"Members": "none"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client, ctx := newTestBMCClient(t, tt.bmc)
+			defer srv.Close()
+
+			_, err := client.HardwareInventory(ctx)
+			assert.IsType(t, ErrRedfishClient{}, err)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+)
+
+const (
+	applyTimeImmediate = "Immediate"
+)
+
+type volumeResource struct {
+	ID            string `json:"Id"`
+	Name          string
+	RAIDType      string
+	CapacityBytes int64
+	Links         struct {
+		Drives []odataRef
+	}
+}
+
+type volumeCollection struct {
+	ApplyTimeSupport struct {
+		SupportedValues []string
+	} `json:"@Redfish.OperationApplyTimeSupport"`
+}
+
+// storageController is a storage controller of the system and the path of its resource.
+type storageController struct {
+	path string
+	storageResource
+}
+
+// volumesPath returns the path of the volume collection of the controller.
+func (s storageController) volumesPath() string {
+	if s.Volumes.ID != "" {
+		return s.Volumes.ID
+	}
+	return s.path + "/Volumes"
+}
+
+// RAIDVolumes retrieves the volumes of the storage controller with the given ID, or of the first controller of the
+// host if it is empty.
+func (c *Client) RAIDVolumes(ctx context.Context, controller string) ([]raid.Volume, error) {
+	storage, err := c.storageController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []raid.Volume{}
+	err = c.forEachMember(ctx, storage.volumesPath(), func(volumePath string) error {
+		var volume volumeResource
+		if _, err := c.getResource(ctx, volumePath, &volume); err != nil {
+			return err
+		}
+
+		drives := make([]string, len(volume.Links.Drives))
+		for i, drive := range volume.Links.Drives {
+			drives[i] = path.Base(drive.ID)
+		}
+		volumes = append(volumes, raid.Volume{
+			ID:            volume.ID,
+			Name:          volume.Name,
+			RAIDType:      volume.RAIDType,
+			Drives:        drives,
+			CapacityBytes: volume.CapacityBytes,
+		})
+		return nil
+	})
+
+	return volumes, err
+}
+
+// CreateRAIDVolume creates volume on the storage controller with the given ID, or on the first controller of the
+// host if it is empty. BMCs that can't create volumes right away, e.g. iDRAC, create them on the next reset of the
+// host.
+func (c *Client) CreateRAIDVolume(ctx context.Context, controller string, volume raid.Volume) error {
+	storage, err := c.storageController(ctx, controller)
+	if err != nil {
+		return err
+	}
+
+	drivePaths := make(map[string]string, len(storage.Drives))
+	for _, drive := range storage.Drives {
+		drivePaths[path.Base(drive.ID)] = drive.ID
+	}
+
+	drives := make([]odataRef, len(volume.Drives))
+	var unknown []string
+	for i, id := range volume.Drives {
+		drivePath, ok := drivePaths[id]
+		if !ok {
+			unknown = append(unknown, id)
+		}
+		drives[i] = odataRef{ID: drivePath}
+	}
+	if len(unknown) > 0 {
+		return ErrRedfishClient{Message: fmt.Sprintf("Drives %s not found on storage controller %s.",
+			strings.Join(unknown, ", "), path.Base(storage.path))}
+	}
+
+	body := map[string]interface{}{
+		"Name":     volume.Name,
+		"RAIDType": volume.RAIDType,
+		"Links":    map[string]interface{}{"Drives": drives},
+	}
+	if volume.CapacityBytes > 0 {
+		body["CapacityBytes"] = volume.CapacityBytes
+	}
+	if applyTime := c.volumeApplyTime(ctx, storage); applyTime != "" {
+		body["@Redfish.OperationApplyTime"] = applyTime
+	}
+
+	return c.sendResource(ctx, http.MethodPost, storage.volumesPath(), body)
+}
+
+// DeleteRAIDVolume deletes the volume with the given ID from the storage controller with the given ID, or from the
+// first controller of the host if it is empty.
+func (c *Client) DeleteRAIDVolume(ctx context.Context, controller string, volumeID string) error {
+	storage, err := c.storageController(ctx, controller)
+	if err != nil {
+		return err
+	}
+
+	return c.sendResource(ctx, http.MethodDelete, storage.volumesPath()+"/"+volumeID, nil)
+}
+
+// storageController retrieves the storage controller with the given ID, or the first controller of the host if it is
+// empty.
+func (c *Client) storageController(ctx context.Context, controller string) (storageController, error) {
+	storage := storageController{}
+	if controller == "" {
+		err := c.forEachMember(ctx, fmt.Sprintf(endpointStorage, c.nodeID), func(controllerPath string) error {
+			if storage.path == "" {
+				storage.path = controllerPath
+			}
+			return nil
+		})
+		if err != nil {
+			return storage, err
+		}
+		if storage.path == "" {
+			return storage, ErrRedfishClient{Message: fmt.Sprintf("System[%s] has no storage controller.", c.nodeID)}
+		}
+	} else {
+		storage.path = fmt.Sprintf(endpointStorage, c.nodeID) + "/" + controller
+	}
+
+	found, err := c.getResource(ctx, storage.path, &storage.storageResource)
+	if err != nil {
+		return storage, err
+	}
+	if !found {
+		return storage, ErrRedfishClient{
+			Message: fmt.Sprintf("Storage controller %s of system[%s] not found.", path.Base(storage.path), c.nodeID),
+		}
+	}
+	return storage, nil
+}
+
+// volumeApplyTime returns when the controller is asked to create volumes, right away if it supports it. It is empty
+// if the BMC doesn't advertise the apply times it supports.
+func (c *Client) volumeApplyTime(ctx context.Context, storage storageController) string {
+	var collection volumeCollection
+	if _, err := c.getResource(ctx, storage.volumesPath(), &collection); err != nil {
+		return ""
+	}
+
+	applyTime := ""
+	for _, value := range collection.ApplyTimeSupport.SupportedValues {
+		switch value {
+		case applyTimeImmediate:
+			return applyTimeImmediate
+		case applyTimeOnReset:
+			applyTime = applyTimeOnReset
+		}
+	}
+	return applyTime
+}
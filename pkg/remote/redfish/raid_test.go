@@ -0,0 +1,190 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
+)
+
+const (
+	storagePath = "/redfish/v1/Systems/System.Embedded.1/Storage"
+	perc        = storagePath + "/RAID.Integrated.1-1"
+	percVolumes = perc + "/Volumes"
+)
+
+// fakeStorage serves storage resources in GET responses and records the bodies of POST requests to them and the
+// paths of DELETE requests.
+type fakeStorage struct {
+	fakeBMC
+	posted  map[string]map[string]interface{}
+	deleted []string
+}
+
+func (s *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if _, ok := s.fakeBMC[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.posted[r.URL.Path] = body
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodDelete:
+		s.deleted = append(s.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.fakeBMC.ServeHTTP(w, r)
+	}
+}
+
+func newFakeStorage(volumes string) *fakeStorage {
+	return &fakeStorage{
+		fakeBMC: fakeBMC{
+			storagePath: `{"Members": [{"@odata.id": "` + perc + `"}]}`,
+			perc: `{
+				"Drives": [
+					{"@odata.id": "` + perc + `/Drives/Disk.Bay.0"},
+					{"@odata.id": "` + perc + `/Drives/Disk.Bay.1"}
+				],
+				"Volumes": {"@odata.id": "` + percVolumes + `"}
+			}`,
+			percVolumes: volumes,
+			percVolumes + "/Disk.Virtual.0": `{
+				"Id": "Disk.Virtual.0", "Name": "os", "RAIDType": "RAID1", "CapacityBytes": 239970746368,
+				"Links": {"Drives": [
+					{"@odata.id": "` + perc + `/Drives/Disk.Bay.0"},
+					{"@odata.id": "` + perc + `/Drives/Disk.Bay.1"}
+				]}
+			}`,
+		},
+		posted: make(map[string]map[string]interface{}),
+	}
+}
+
+func TestRAIDVolumes(t *testing.T) {
+	bmc := newFakeStorage(`{"Members": [{"@odata.id": "` + percVolumes + `/Disk.Virtual.0"}]}`)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+
+	expected := []raid.Volume{{
+		ID:            "Disk.Virtual.0",
+		Name:          "os",
+		RAIDType:      "RAID1",
+		Drives:        []string{"Disk.Bay.0", "Disk.Bay.1"},
+		CapacityBytes: 239970746368,
+	}}
+	for _, controller := range []string{"", "RAID.Integrated.1-1"} {
+		volumes, err := client.RAIDVolumes(ctx, controller)
+		require.NoError(t, err)
+		assert.Equal(t, expected, volumes)
+	}
+
+	_, err := client.RAIDVolumes(ctx, "AHCI.Slot.1-1")
+	assert.IsType(t, ErrRedfishClient{}, err)
+}
+
+func TestCreateRAIDVolume(t *testing.T) {
+	volume := raid.Volume{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0", "Disk.Bay.1"}}
+	drives := []interface{}{
+		map[string]interface{}{"@odata.id": perc + "/Drives/Disk.Bay.0"},
+		map[string]interface{}{"@odata.id": perc + "/Drives/Disk.Bay.1"},
+	}
+
+	tests := []struct {
+		name         string
+		volumes      string
+		expectedBody map[string]interface{}
+	}{
+		{
+			name:    "apply-time-not-advertised",
+			volumes: `{"Members": []}`,
+			expectedBody: map[string]interface{}{
+				"Name":     "os",
+				"RAIDType": "RAID1",
+				"Links":    map[string]interface{}{"Drives": drives},
+			},
+		},
+		{
+			name: "apply-on-reset",
+			volumes: `{"Members": [], "@Redfish.OperationApplyTimeSupport": {
+				"SupportedValues": ["OnReset"]
+			}}`,
+			expectedBody: map[string]interface{}{
+				"Name":                        "os",
+				"RAIDType":                    "RAID1",
+				"Links":                       map[string]interface{}{"Drives": drives},
+				"@Redfish.OperationApplyTime": "OnReset",
+			},
+		},
+		{
+			name: "apply-immediately",
+			volumes: `{"Members": [], "@Redfish.OperationApplyTimeSupport": {
+				"SupportedValues": ["OnReset", "Immediate"]
+			}}`,
+			expectedBody: map[string]interface{}{
+				"Name":                        "os",
+				"RAIDType":                    "RAID1",
+				"Links":                       map[string]interface{}{"Drives": drives},
+				"@Redfish.OperationApplyTime": "Immediate",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := newFakeStorage(tt.volumes)
+			srv, client, ctx := newTestBMCClient(t, bmc)
+			defer srv.Close()
+
+			require.NoError(t, client.CreateRAIDVolume(ctx, "", volume))
+			assert.Equal(t, map[string]map[string]interface{}{percVolumes: tt.expectedBody}, bmc.posted)
+		})
+	}
+}
+
+func TestCreateRAIDVolumeUnknownDrives(t *testing.T) {
+	bmc := newFakeStorage(`{"Members": []}`)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+
+	err := client.CreateRAIDVolume(ctx, "RAID.Integrated.1-1",
+		raid.Volume{Name: "data", RAIDType: "RAID0", Drives: []string{"Disk.Bay.0", "Disk.Bay.7"}})
+	assert.Equal(t, ErrRedfishClient{
+		Message: "Drives Disk.Bay.7 not found on storage controller RAID.Integrated.1-1.",
+	}, err)
+	assert.Empty(t, bmc.posted)
+}
+
+func TestDeleteRAIDVolume(t *testing.T) {
+	bmc := newFakeStorage(`{"Members": []}`)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+
+	require.NoError(t, client.DeleteRAIDVolume(ctx, "RAID.Integrated.1-1", "Disk.Virtual.0"))
+	assert.Equal(t, []string{percVolumes + "/Disk.Virtual.0"}, bmc.deleted)
+}
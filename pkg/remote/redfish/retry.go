@@ -0,0 +1,105 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// retryTransport retries the requests that fail with the errors BMCs commonly return for a while when they reset or
+// are busy: 5xx responses and refused or dropped connections. POST requests, which could create resources twice, are
+// only retried when they weren't processed, i.e. on 503 responses and refused connections.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	// delay between attempts, unless the BMC asks for another one with a Retry-After header
+	delay time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.retries || !isTransient(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := t.delay
+		if resp != nil {
+			if retryAfter := retryAfterDelay(resp); retryAfter > 0 {
+				delay = retryAfter
+			}
+			// Drain the body for the connection to be reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			log.Debugf("%s %s: BMC responded '%s', retrying in %s.", req.Method, req.URL.Path, resp.Status, delay)
+		} else {
+			log.Debugf("%s %s: %v, retrying in %s.", req.Method, req.URL.Path, err, delay)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isTransient reports whether the response or error of req may not happen again if req is retried.
+func isTransient(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		opErr, ok := err.(*net.OpError)
+		return req.Method != http.MethodPost || (ok && opErr.Op == "dial")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return req.Method != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// retryAfterDelay returns the delay in the Retry-After header of resp given in seconds, 0 if there is none.
+func retryAfterDelay(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
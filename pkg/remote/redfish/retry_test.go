@@ -0,0 +1,146 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyBMC fails the first requests with the given statuses, then echoes the body of the request.
+type flakyBMC struct {
+	statuses []int
+	bodies   []string
+}
+
+func (b *flakyBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.bodies = append(b.bodies, string(body))
+
+	if len(b.statuses) > 0 {
+		w.WriteHeader(b.statuses[0])
+		b.statuses = b.statuses[1:]
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		statuses       []int
+		expectedStatus int
+		expectedTries  int
+	}{
+		{
+			name:           "reset-bmc",
+			method:         http.MethodGet,
+			statuses:       []int{http.StatusInternalServerError, http.StatusServiceUnavailable},
+			expectedStatus: http.StatusOK,
+			expectedTries:  3,
+		},
+		{
+			name:           "retries-exhausted",
+			method:         http.MethodPatch,
+			statuses:       []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedStatus: http.StatusBadGateway,
+			expectedTries:  3,
+		},
+		{
+			name:           "post-not-processed",
+			method:         http.MethodPost,
+			statuses:       []int{http.StatusServiceUnavailable},
+			expectedStatus: http.StatusOK,
+			expectedTries:  2,
+		},
+		{
+			name:           "post-maybe-processed",
+			method:         http.MethodPost,
+			statuses:       []int{http.StatusInternalServerError},
+			expectedStatus: http.StatusInternalServerError,
+			expectedTries:  1,
+		},
+		{
+			name:           "client-error",
+			method:         http.MethodGet,
+			statuses:       []int{http.StatusNotFound},
+			expectedStatus: http.StatusNotFound,
+			expectedTries:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := &flakyBMC{statuses: tt.statuses}
+			srv := httptest.NewServer(bmc)
+			defer srv.Close()
+
+			transport := &retryTransport{base: http.DefaultTransport, retries: 2}
+			req, err := http.NewRequest(tt.method, srv.URL, bytes.NewBufferString(`{"Name": "os"}`))
+			require.NoError(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			require.Len(t, bmc.bodies, tt.expectedTries)
+			// The body is sent again with every attempt
+			for _, body := range bmc.bodies {
+				assert.Equal(t, `{"Name": "os"}`, body)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	get, err := http.NewRequest(http.MethodGet, "https://bmc", nil)
+	require.NoError(t, err)
+	post, err := http.NewRequest(http.MethodPost, "https://bmc", nil)
+	require.NoError(t, err)
+
+	refused := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	reset := &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}
+	assert.True(t, isTransient(get, nil, refused))
+	assert.True(t, isTransient(get, nil, reset))
+	assert.True(t, isTransient(post, nil, refused))
+	assert.False(t, isTransient(post, nil, reset))
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Zero(t, retryAfterDelay(resp))
+
+	resp.Header.Set("Retry-After", "30")
+	assert.Equal(t, 30*time.Second, retryAfterDelay(resp))
+
+	resp.Header.Set("Retry-After", "Wed, 21 Oct 2026 07:28:00 GMT")
+	assert.Zero(t, retryAfterDelay(resp))
+}
@@ -0,0 +1,58 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+const endpointSecureBoot = "/redfish/v1/Systems/%s/SecureBoot"
+
+type secureBootResource struct {
+	SecureBootEnable      bool
+	SecureBootCurrentBoot string
+	SecureBootMode        string
+}
+
+// SecureBoot retrieves the UEFI Secure Boot state of the host.
+func (c *Client) SecureBoot(ctx context.Context) (boot.SecureBoot, error) {
+	var secureBoot secureBootResource
+	found, err := c.getResource(ctx, fmt.Sprintf(endpointSecureBoot, c.nodeID), &secureBoot)
+	if err != nil {
+		return boot.SecureBoot{}, err
+	}
+	if !found {
+		return boot.SecureBoot{}, ErrSecureBootNotSupported{NodeID: c.nodeID}
+	}
+
+	return boot.SecureBoot{
+		Enabled: secureBoot.SecureBootEnable,
+		Active:  secureBoot.SecureBootCurrentBoot == "Enabled",
+		Mode:    secureBoot.SecureBootMode,
+	}, nil
+}
+
+// SetSecureBoot enables or disables UEFI Secure Boot for the next boot of the host, which is not triggered.
+func (c *Client) SetSecureBoot(ctx context.Context, enabled bool) error {
+	if _, err := c.SecureBoot(ctx); err != nil {
+		return err
+	}
+	return c.sendResource(ctx, http.MethodPatch, fmt.Sprintf(endpointSecureBoot, c.nodeID),
+		map[string]bool{"SecureBootEnable": enabled})
+}
@@ -0,0 +1,63 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+const secureBootPath = "/redfish/v1/Systems/System.Embedded.1/SecureBoot"
+
+func TestSecureBoot(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{
+		secureBootPath: `{"SecureBootEnable": true, "SecureBootCurrentBoot": "Disabled", "SecureBootMode": "UserMode"}`,
+	})
+	defer srv.Close()
+
+	state, err := client.SecureBoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, boot.SecureBoot{Enabled: true, Mode: "UserMode"}, state)
+	assert.True(t, state.Pending())
+}
+
+func TestSecureBootNotSupported(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, fakeBMC{})
+	defer srv.Close()
+
+	_, err := client.SecureBoot(ctx)
+	assert.Equal(t, ErrSecureBootNotSupported{NodeID: "System.Embedded.1"}, err)
+	assert.Equal(t, ErrSecureBootNotSupported{NodeID: "System.Embedded.1"}, client.SetSecureBoot(ctx, true))
+}
+
+func TestSetSecureBoot(t *testing.T) {
+	bmc := &fakeBIOS{
+		fakeBMC:     fakeBMC{secureBootPath: `{"SecureBootEnable": false, "SecureBootCurrentBoot": "Disabled"}`},
+		patchStatus: http.StatusNoContent,
+		patched:     make(map[string]map[string]interface{}),
+	}
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+
+	require.NoError(t, client.SetSecureBoot(ctx, true))
+	assert.Equal(t, map[string]map[string]interface{}{
+		secureBootPath: {"SecureBootEnable": true},
+	}, bmc.patched)
+}
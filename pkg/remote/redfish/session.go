@@ -0,0 +1,354 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	endpointSessions = "/redfish/v1/SessionService/Sessions"
+	headerAuthToken  = "X-Auth-Token"
+
+	sessionLogoutTimeout = 10 * time.Second
+	// sessionCacheLockTimeout bounds the wait for another process updating the session cache file
+	sessionCacheLockTimeout = 5 * time.Second
+)
+
+// session is an authenticated Redfish session. Its token authenticates requests in place of the credentials of the
+// user until the session is deleted at Location or times out.
+type session struct {
+	Token    string `json:"token"`
+	Location string `json:"location"`
+	// Expires is set for sessions kept in a session cache file, which are left open for later commands.
+	Expires time.Time `json:"expires"`
+}
+
+// sessionEntry holds the session of a user of a BMC. Its lock serializes logins, so that concurrent requests to the
+// same BMC share one session.
+type sessionEntry struct {
+	sync.Mutex
+	session *session
+	// transport the session was created with, to log out of it
+	transport   http.RoundTripper
+	unsupported bool
+}
+
+// sessionStore holds the sessions of the process by BMC and user.
+type sessionStore struct {
+	mu      sync.Mutex
+	entries map[string]*sessionEntry
+}
+
+// sessions are shared by all the clients of the process, hosts often share a BMC.
+var sessions = &sessionStore{entries: make(map[string]*sessionEntry)}
+
+// sessionFileLock serializes the updates of session cache files by the clients of the process, other processes are
+// kept out by the lock file of the cache file.
+var sessionFileLock sync.Mutex
+
+func (s *sessionStore) entry(key string) *sessionEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &sessionEntry{}
+		s.entries[key] = entry
+	}
+	return entry
+}
+
+// CloseSessions logs out of the Redfish sessions opened by the clients of the process, except those kept in a session
+// cache file for later commands. BMCs limit the number of open sessions, which otherwise stay open until they time
+// out.
+func CloseSessions() {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+
+	for _, entry := range sessions.entries {
+		entry.Lock()
+		if s := entry.session; s != nil && s.Expires.IsZero() && s.Location != "" {
+			logout(entry.transport, s)
+		}
+		entry.session = nil
+		entry.Unlock()
+	}
+}
+
+func logout(transport http.RoundTripper, s *session) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLogoutTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodDelete, s.Location, nil)
+	if err != nil {
+		log.Debugf("Unable to log out of Redfish session %s: %v", s.Location, err)
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(headerAuthToken, s.Token)
+	req.Header.Set("User-Agent", headerUserAgent)
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		log.Debugf("Unable to log out of Redfish session %s: %v", s.Location, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// sessionTransport authenticates the requests that carry the credentials of a user with a Redfish session instead.
+// The session is created by the first request to a BMC and reused by the following ones, as BMCs that count logins
+// lock users out after too many of them. Requests to BMCs without session service keep their credentials.
+type sessionTransport struct {
+	base  http.RoundTripper
+	store *sessionStore
+
+	// cacheFile keeps sessions for cacheTTL to be reused by later commands, unless it is empty.
+	cacheFile string
+	cacheTTL  time.Duration
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	username, password, ok := req.BasicAuth()
+	if !ok || req.URL.Path == endpointSessions {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.Scheme + "://" + req.URL.Host + "/" + username
+	entry := t.store.entry(key)
+	for attempt := 0; ; attempt++ {
+		s, resp, err := t.session(req, entry, key, username, password)
+		if resp != nil || err != nil {
+			return resp, err
+		}
+		if s == nil {
+			return t.base.RoundTrip(req)
+		}
+
+		authReq := req.Clone(req.Context())
+		authReq.Header.Del("Authorization")
+		authReq.Header.Set(headerAuthToken, s.Token)
+		if attempt > 0 && req.GetBody != nil {
+			if authReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = t.base.RoundTrip(authReq)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || attempt > 0 ||
+			(req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		// The session timed out or was deleted, e.g. by a reset of the BMC, log in again
+		resp.Body.Close()
+		t.invalidate(entry, key, s)
+	}
+}
+
+// session returns the session of entry, logging in if there is none. It returns neither session nor response for
+// BMCs without session service, and the response of the BMC if it rejects the credentials.
+func (t *sessionTransport) session(req *http.Request, entry *sessionEntry, key string, username string,
+	password string) (*session, *http.Response, error) {
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.unsupported {
+		return nil, nil, nil
+	}
+	if entry.session != nil {
+		return entry.session, nil, nil
+	}
+	if s := t.cachedSession(key); s != nil {
+		entry.session, entry.transport = s, t.base
+		return s, nil, nil
+	}
+
+	s, resp, unsupported, err := t.login(req, username, password)
+	switch {
+	case resp != nil || err != nil:
+		return nil, resp, err
+	case s == nil:
+		// Only BMCs without session service keep using credentials, the next request tries to log in again after
+		// other failures
+		entry.unsupported = unsupported
+		return nil, nil, nil
+	}
+
+	entry.session, entry.transport = s, t.base
+	t.cacheSession(key, s)
+	return s, nil, nil
+}
+
+// login creates a session of the user on the BMC req is sent to. It returns the response of the BMC if it rejects
+// the credentials, and no session if it can't create one, reporting whether the BMC has no session service.
+func (t *sessionTransport) login(req *http.Request, username string, password string) (*session, *http.Response,
+	bool, error) {
+	body, err := json.Marshal(map[string]string{"UserName": username, "Password": password})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	sessionsURL := *req.URL
+	sessionsURL.Path, sessionsURL.RawQuery = endpointSessions, ""
+	loginReq, err := http.NewRequest(http.MethodPost, sessionsURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	loginReq = loginReq.WithContext(req.Context())
+	loginReq.Header.Set("Accept", "application/json")
+	loginReq.Header.Set("Content-Type", "application/json")
+	loginReq.Header.Set("User-Agent", headerUserAgent)
+	// The credentials are in the body, BMCs without session service then tell the collection doesn't exist rather
+	// than ask for them
+	loginReq.SetBasicAuth(username, password)
+
+	resp, err := t.base.RoundTrip(loginReq)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, resp, false, nil
+	}
+	defer resp.Body.Close()
+
+	token := resp.Header.Get(headerAuthToken)
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed ||
+		resp.StatusCode == http.StatusNotImplemented:
+		log.Debugf("BMC %s doesn't support Redfish sessions, responded '%s'.", sessionsURL.Host, resp.Status)
+		return nil, nil, true, nil
+	case resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK:
+		log.Debugf("Unable to create a Redfish session on BMC %s, responded '%s'.", sessionsURL.Host, resp.Status)
+		return nil, nil, false, nil
+	case token == "":
+		log.Debugf("BMC %s created a Redfish session without token.", sessionsURL.Host)
+		return nil, nil, true, nil
+	}
+
+	s := &session{Token: token}
+	if location, err := sessionsURL.Parse(resp.Header.Get("Location")); err == nil && location.Path != "" {
+		s.Location = location.String()
+	}
+	return s, nil, false, nil
+}
+
+// invalidate forgets the session s of entry, unless another request already replaced it.
+func (t *sessionTransport) invalidate(entry *sessionEntry, key string, s *session) {
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.session == s {
+		entry.session = nil
+		t.updateSessionFile(func(cached map[string]session) {
+			delete(cached, key)
+		})
+	}
+}
+
+// cachedSession returns the session of the session cache file for key, if it didn't expire.
+func (t *sessionTransport) cachedSession(key string) *session {
+	if t.cacheFile == "" {
+		return nil
+	}
+
+	sessionFileLock.Lock()
+	defer sessionFileLock.Unlock()
+
+	s, ok := readSessionFile(t.cacheFile)[key]
+	if !ok || time.Now().After(s.Expires) {
+		return nil
+	}
+	return &s
+}
+
+// cacheSession keeps s in the session cache file for key, with an expiry time.
+func (t *sessionTransport) cacheSession(key string, s *session) {
+	if t.cacheFile == "" {
+		return
+	}
+
+	s.Expires = time.Now().Add(t.cacheTTL)
+	t.updateSessionFile(func(cached map[string]session) {
+		cached[key] = *s
+	})
+}
+
+// updateSessionFile applies update to the sessions of the session cache file, dropping the expired ones. The file
+// is locked against other processes while it is updated and replaced atomically, so that readers never see a
+// partial file. The cache is best effort, failures to write it are only logged.
+func (t *sessionTransport) updateSessionFile(update func(cached map[string]session)) {
+	if t.cacheFile == "" {
+		return
+	}
+
+	sessionFileLock.Lock()
+	defer sessionFileLock.Unlock()
+
+	if err := t.writeSessionFile(update); err != nil {
+		log.Debugf("Unable to update Redfish session cache %s: %v", t.cacheFile, err)
+	}
+}
+
+func (t *sessionTransport) writeSessionFile(update func(cached map[string]session)) error {
+	if err := os.MkdirAll(filepath.Dir(t.cacheFile), 0700); err != nil {
+		return err
+	}
+	unlock, err := util.LockFile(t.cacheFile, sessionCacheLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock() //nolint:errcheck
+
+	cached := readSessionFile(t.cacheFile)
+	for key, s := range cached {
+		if time.Now().After(s.Expires) {
+			delete(cached, key)
+		}
+	}
+	update(cached)
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(t.cacheFile, data, 0600)
+}
+
+// readSessionFile returns the sessions of a session cache file by key. A missing or corrupted file holds no sessions.
+func readSessionFile(file string) map[string]session {
+	cached := make(map[string]session)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return cached
+	}
+	if err = json.Unmarshal(data, &cached); err != nil {
+		log.Debugf("Ignoring malformed Redfish session cache %s: %v", file, err)
+		return make(map[string]session)
+	}
+	return cached
+}
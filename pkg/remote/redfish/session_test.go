@@ -0,0 +1,254 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionBMC serves the resources of fakeBMC to requests authenticated with a session token, and creates and
+// deletes sessions.
+type fakeSessionBMC struct {
+	fakeBMC
+
+	mu         sync.Mutex
+	logins     int
+	tokens     map[string]bool
+	basicAuths int
+	// loginStatus is the status of the responses to logins if set
+	loginStatus int
+}
+
+func newFakeSessionBMC(resources fakeBMC) *fakeSessionBMC {
+	return &fakeSessionBMC{fakeBMC: resources, tokens: make(map[string]bool)}
+}
+
+func (b *fakeSessionBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == endpointSessions && b.loginStatus != 0:
+		w.WriteHeader(b.loginStatus)
+	case r.Method == http.MethodPost && r.URL.Path == endpointSessions:
+		var creds struct{ UserName, Password string }
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil || creds.Password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b.logins++
+		token := fmt.Sprintf("token-%d", b.logins)
+		b.tokens[token] = true
+		w.Header().Set(headerAuthToken, token)
+		w.Header().Set("Location", fmt.Sprintf("%s/%d", endpointSessions, b.logins))
+		w.WriteHeader(http.StatusCreated)
+	case r.Header.Get(headerAuthToken) != "":
+		token := r.Header.Get(headerAuthToken)
+		if !b.tokens[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(b.tokens, token)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		r.SetBasicAuth("admin", "password")
+		b.fakeBMC.ServeHTTP(w, r)
+	default:
+		b.basicAuths++
+		b.fakeBMC.ServeHTTP(w, r)
+	}
+}
+
+// expire deletes all sessions, as a reset of the BMC does.
+func (b *fakeSessionBMC) expire() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = make(map[string]bool)
+}
+
+var sessionTestResources = fakeBMC{
+	"/redfish/v1/Systems/System.Embedded.1": `{"Model": "PowerEdge R640"}`,
+}
+
+// newSessionTestClient returns a client of the BMC served by srv, authenticated as admin with password.
+func newSessionTestClient(t *testing.T, srv *httptest.Server, password string,
+	opts ...ClientOption) (context.Context, *Client) {
+	ctx, client, err := NewClient("redfish+"+srv.URL+"/redfish/v1/Systems/System.Embedded.1", false, false,
+		"admin", password, systemActionRetries, systemRebootDelay, opts...)
+	require.NoError(t, err)
+	return ctx, client
+}
+
+func TestSessionReuse(t *testing.T) {
+	bmc := newFakeSessionBMC(sessionTestResources)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+	otherCtx, otherClient := newSessionTestClient(t, srv, "password")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := client.HardwareInventory(ctx)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := otherClient.HardwareInventory(otherCtx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, bmc.logins)
+	assert.Zero(t, bmc.basicAuths)
+	assert.Len(t, bmc.tokens, 1)
+
+	CloseSessions()
+	assert.Empty(t, bmc.tokens)
+}
+
+func TestSessionExpired(t *testing.T) {
+	bmc := newFakeSessionBMC(sessionTestResources)
+	srv, client, ctx := newTestBMCClient(t, bmc)
+	defer srv.Close()
+	defer CloseSessions()
+
+	_, err := client.HardwareInventory(ctx)
+	require.NoError(t, err)
+	bmc.expire()
+
+	// The body of the request is sent again with the new session
+	bmc.fakeBMC = fakeBMC{"/redfish/v1/Systems/System.Embedded.1/Bios/Settings": `{}`}
+	require.NoError(t, client.sendResource(ctx, http.MethodPatch, biosPath+"/Settings",
+		map[string]interface{}{"Attributes": map[string]interface{}{}}))
+	assert.Equal(t, 2, bmc.logins)
+	assert.Zero(t, bmc.basicAuths)
+}
+
+func TestSessionNotSupported(t *testing.T) {
+	srv, client, ctx := newTestBMCClient(t, sessionTestResources)
+	defer srv.Close()
+
+	inventory, err := client.HardwareInventory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "PowerEdge R640", inventory.Model)
+}
+
+func TestSessionLoginFailed(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		unsupported bool
+	}{
+		{name: "server error", status: http.StatusInternalServerError},
+		{name: "not found", status: http.StatusNotFound, unsupported: true},
+		{name: "method not allowed", status: http.StatusMethodNotAllowed, unsupported: true},
+		{name: "not implemented", status: http.StatusNotImplemented, unsupported: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := newFakeSessionBMC(sessionTestResources)
+			bmc.loginStatus = tt.status
+			srv, client, ctx := newTestBMCClient(t, bmc)
+			defer srv.Close()
+			defer CloseSessions()
+
+			_, err := client.HardwareInventory(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, bmc.basicAuths)
+
+			// Only BMCs without session service aren't asked for a session again
+			bmc.loginStatus = 0
+			_, err = client.HardwareInventory(ctx)
+			require.NoError(t, err)
+			if tt.unsupported {
+				assert.Zero(t, bmc.logins)
+				assert.Equal(t, 2, bmc.basicAuths)
+			} else {
+				assert.Equal(t, 1, bmc.logins)
+				assert.Equal(t, 1, bmc.basicAuths)
+			}
+		})
+	}
+}
+
+func TestSessionRejected(t *testing.T) {
+	bmc := newFakeSessionBMC(sessionTestResources)
+	srv := httptest.NewServer(bmc)
+	defer srv.Close()
+
+	ctx, client := newSessionTestClient(t, srv, "wrong")
+	_, err := client.HardwareInventory(ctx)
+	assert.Equal(t, ErrRedfishClient{
+		Message: "Unable to get /redfish/v1/Systems/System.Embedded.1. BMC responded '401 Unauthorized'.",
+	}, err)
+	assert.Zero(t, bmc.logins)
+	assert.Zero(t, bmc.basicAuths)
+}
+
+func TestSessionCache(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "redfish-sessions")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	cacheFile := filepath.Join(tempDir, "sessions.json")
+
+	bmc := newFakeSessionBMC(sessionTestResources)
+	srv := httptest.NewServer(bmc)
+	defer srv.Close()
+
+	newClient := func() {
+		ctx, client := newSessionTestClient(t, srv, "password", WithSessionCache(cacheFile, time.Hour))
+		_, err := client.HardwareInventory(ctx)
+		require.NoError(t, err)
+
+		// Sessions kept in the cache are left open
+		CloseSessions()
+	}
+	newClient()
+	newClient()
+	assert.Equal(t, 1, bmc.logins)
+	assert.Len(t, bmc.tokens, 1)
+
+	info, err := os.Stat(cacheFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	files, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "the lock and temporary files are removed")
+
+	// A session that timed out is replaced in the cache
+	bmc.expire()
+	newClient()
+	newClient()
+	assert.Equal(t, 2, bmc.logins)
+}
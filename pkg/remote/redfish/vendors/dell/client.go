@@ -19,13 +19,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	redfishAPI "opendev.org/airship/go-redfish/api"
 	redfishClient "opendev.org/airship/go-redfish/client"
 
 	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 )
 
@@ -33,14 +36,16 @@ const (
 	// ClientType is used by other packages as the identifier of the Redfish client.
 	ClientType           = "redfish-dell"
 	endpointImportSysCFG = "%s/redfish/v1/Managers/%s/Actions/Oem/EID_674_Manager.ImportSystemConfiguration"
-	vCDBootRequestBody   = `{
+	endpointJob          = "%s/redfish/v1/Managers/%s/Jobs/%s"
+	// vCDBootRequestBody is formatted with the value of the BootOnce attribute, "Enabled" or "Disabled"
+	vCDBootRequestBody = `{
 	    "ShareParameters": {
 	        "Target": "ALL"
 	    },
 	    "ShutdownType": "NoReboot",
 	    "ImportBuffer": "<SystemConfiguration>
 	                       <Component FQDD=\"iDRAC.Embedded.1\">
-	                         <Attribute Name=\"ServerBoot.1#BootOnce\">Enabled</Attribute>
+	                         <Attribute Name=\"ServerBoot.1#BootOnce\">%s</Attribute>
 	                         <Attribute Name=\"ServerBoot.1#FirstBootDevice\">VCD-DVD</Attribute>
 	                       </Component>
 	                     </SystemConfiguration>"
@@ -70,8 +75,35 @@ type iDRACAPIExtendedInfo struct {
 	Resolution string `json:"Resolution,omitempty"`
 }
 
+// iDRAC job states that end a job, see the DellJob schema
+const (
+	jobStateCompleted           = "Completed"
+	jobStateCompletedWithErrors = "CompletedWithErrors"
+	jobStateFailed              = "Failed"
+)
+
+type iDRACJob struct {
+	ID       string `json:"Id"`
+	JobState string `json:"JobState"`
+	Message  string `json:"Message"`
+}
+
+// SetBootSource makes the host boot from source. Booting from a virtual CD is an iDRAC specific operation, all other
+// sources are set with the standard Redfish API.
+func (c *Client) SetBootSource(ctx context.Context, source boot.Source, persistent bool) error {
+	if source != boot.SourceCD {
+		return c.Client.SetBootSource(ctx, source, persistent)
+	}
+	return c.setVirtualCDBoot(ctx, !persistent)
+}
+
 // SetBootSourceByType sets the boot source of the ephemeral node to a virtual CD, "VCD-DVD".
 func (c *Client) SetBootSourceByType(ctx context.Context) error {
+	return c.setVirtualCDBoot(ctx, true)
+}
+
+// setVirtualCDBoot makes the host boot from its virtual CD, only for the next boot if once is true.
+func (c *Client) setVirtualCDBoot(ctx context.Context, once bool) error {
 	log.Debug("Setting boot device to 'VCD-DVD'.")
 	managerID, err := redfish.GetManagerID(ctx, c.RedfishAPI, c.NodeID())
 	if err != nil {
@@ -83,19 +115,22 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	// actions API. The request is made below using the same HTTP client used by the Redfish API and exposed by the
 	// standard airshipctl Redfish client. Only iDRAC 9 >= 3.3 is supports this endpoint.
 	url := fmt.Sprintf(endpointImportSysCFG, c.RedfishCFG.BasePath, managerID)
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(vCDBootRequestBody))
+	bootOnce := "Disabled"
+	if once {
+		bootOnce = "Enabled"
+	}
+	req, err := newIDRACRequest(ctx, http.MethodPost, url,
+		bytes.NewBufferString(fmt.Sprintf(vCDBootRequestBody, bootOnce)))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-
-	if auth, ok := ctx.Value(redfishClient.ContextBasicAuth).(redfishClient.BasicAuth); ok {
-		req.SetBasicAuth(auth.UserName, auth.Password)
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to set boot device. %v", err)}
 	}
+	defer httpResp.Body.Close()
 
-	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
 	if httpResp.StatusCode != http.StatusAccepted {
 		body, ok := ioutil.ReadAll(httpResp.Body)
 		if ok != nil {
@@ -105,22 +140,106 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 
 		var iDRACResp iDRACAPIRespErr
 		ok = json.Unmarshal(body, &iDRACResp)
-		if ok != nil {
+		if ok != nil || len(iDRACResp.Err.ExtendedInfo) == 0 {
 			log.Debugf("Malformed iDRAC response: %s", body)
 			return redfish.ErrRedfishClient{Message: "Unable to set boot device. Malformed iDrac response."}
 		}
 
 		return redfish.ErrRedfishClient{
-			Message: fmt.Sprintf("Unable to set boot device. %s", iDRACResp.Err.ExtendedInfo[0]),
+			Message: fmt.Sprintf("Unable to set boot device. %s", iDRACResp.Err.ExtendedInfo[0].Message),
 		}
-	} else if err != nil {
-		return redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to set boot device. %v", err)}
+	}
+
+	// The configuration is applied by an iDRAC job, the boot device isn't set before the job is done. Subsequent
+	// configuration imports are rejected while the job is still queued.
+	if err = c.waitForJob(ctx, managerID, httpResp.Header.Get("Location")); err != nil {
+		return err
 	}
 
 	log.Debug("Successfully set boot device.")
+	return nil
+}
+
+// waitForJob polls the iDRAC job referenced by location, the URI of the job or of its task, until it finishes.
+func (c *Client) waitForJob(ctx context.Context, managerID string, location string) error {
+	if location == "" {
+		log.Debug("iDRAC didn't return a job location, not waiting for the job to finish.")
+		return nil
+	}
+
+	jobID := redfish.GetResourceIDFromURL(location)
+	url := fmt.Sprintf(endpointJob, c.RedfishCFG.BasePath, managerID, jobID)
+	log.Debugf("Waiting for iDRAC job '%s' to finish.", jobID)
+
+	for retry := 0; retry <= c.SystemActionRetries(); retry++ {
+		job, err := c.getJob(ctx, url)
+		if err != nil {
+			return err
+		}
+
+		switch job.JobState {
+		case jobStateCompleted:
+			log.Debugf("iDRAC job '%s' completed.", jobID)
+			return nil
+		case jobStateCompletedWithErrors, jobStateFailed:
+			return redfish.ErrRedfishClient{
+				Message: fmt.Sprintf("iDRAC job %s finished in state %s: %s", jobID, job.JobState, job.Message),
+			}
+		}
+
+		log.Debugf("iDRAC job '%s' is in state '%s'.", jobID, job.JobState)
+		c.Sleep(time.Duration(c.SystemRebootDelay()) * time.Second)
+	}
+
+	return redfish.ErrOperationRetriesExceeded{
+		What:    fmt.Sprintf("wait for iDRAC job %s", jobID),
+		Retries: c.SystemActionRetries(),
+	}
+}
+
+// getJob retrieves the iDRAC job at url.
+func (c *Client) getJob(ctx context.Context, url string) (iDRACJob, error) {
+	var job iDRACJob
+
+	req, err := newIDRACRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return job, err
+	}
+
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return job, redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to get iDRAC job. %v", err)}
+	}
 	defer httpResp.Body.Close()
 
-	return nil
+	if httpResp.StatusCode != http.StatusOK {
+		return job, redfish.ErrRedfishClient{
+			Message: fmt.Sprintf("Unable to get iDRAC job. Unexpected response status %s.", httpResp.Status),
+		}
+	}
+
+	if err = json.NewDecoder(httpResp.Body).Decode(&job); err != nil {
+		return job, redfish.ErrRedfishClient{Message: "Unable to get iDRAC job. Malformed iDRAC response."}
+	}
+
+	return job, nil
+}
+
+// newIDRACRequest builds a request to the iDRAC API authenticated with the credentials stored in ctx.
+func newIDRACRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+
+	if auth, ok := ctx.Value(redfishClient.ContextBasicAuth).(redfishClient.BasicAuth); ok {
+		req.SetBasicAuth(auth.UserName, auth.Password)
+	}
+
+	return req, nil
 }
 
 // NewClient returns a client with the capability to make Redfish requests.
@@ -130,9 +249,10 @@ func NewClient(redfishURL string,
 	username string,
 	password string,
 	systemActionRetries int,
-	systemRebootDelay int) (context.Context, *Client, error) {
+	systemRebootDelay int,
+	opts ...redfish.ClientOption) (context.Context, *Client, error) {
 	ctx, genericClient, err := redfish.NewClient(redfishURL, insecure, useProxy, username, password,
-		systemActionRetries, systemRebootDelay)
+		systemActionRetries, systemRebootDelay, opts...)
 	if err != nil {
 		return ctx, nil, err
 	}
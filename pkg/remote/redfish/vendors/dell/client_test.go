@@ -15,13 +15,22 @@
 package dell
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	redfishMocks "opendev.org/airship/go-redfish/api/mocks"
 	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
 )
 
 const (
@@ -43,6 +52,7 @@ func TestNewClientDefaultValues(t *testing.T) {
 	assert.Equal(t, c.SystemRebootDelay(), sysRebDel)
 	assert.NoError(t, err)
 }
+
 func TestSetBootSourceByTypeGetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
@@ -60,3 +70,108 @@ func TestSetBootSourceByTypeGetSystemError(t *testing.T) {
 	err = client.SetBootSourceByType(ctx)
 	assert.Error(t, err)
 }
+
+// fakeIDRAC serves the iDRAC configuration import and job endpoints. The job reports the given states in order.
+type fakeIDRAC struct {
+	importStatus int
+	location     string
+	jobStates    []string
+	jobPolls     int
+}
+
+func (f *fakeIDRAC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case fmt.Sprintf("/redfish/v1/Managers/%s/Actions/Oem/EID_674_Manager.ImportSystemConfiguration",
+		testutil.ManagerID):
+		if f.location != "" {
+			w.Header().Set("Location", f.location)
+		}
+		w.WriteHeader(f.importStatus)
+		if f.importStatus != http.StatusAccepted {
+			fmt.Fprint(w, `{"error":{"@Message.ExtendedInfo":[{"Message":"A job is already running."}]}}`)
+		}
+	case fmt.Sprintf("/redfish/v1/Managers/%s/Jobs/JID_001", testutil.ManagerID):
+		state := f.jobStates[f.jobPolls]
+		if f.jobPolls < len(f.jobStates)-1 {
+			f.jobPolls++
+		}
+		fmt.Fprintf(w, `{"Id":"JID_001","JobState":%q,"Message":"Job state %s"}`, state, state)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestIDRACClient(t *testing.T, iDRAC *fakeIDRAC, retries int) (context.Context, *Client, func()) {
+	t.Helper()
+
+	srv := httptest.NewServer(iDRAC)
+	ctx, client, err := NewClient("redfish+"+srv.URL+"/redfish/v1/Systems/System.Embedded.1", false, false,
+		"username", "password", retries, systemRebootDelay)
+	require.NoError(t, err)
+
+	m := &redfishMocks.RedfishAPI{}
+	m.On("GetSystem", ctx, client.NodeID()).Return(testutil.GetTestSystem(), &http.Response{StatusCode: 200}, nil)
+	client.RedfishAPI = m
+	client.Sleep = func(_ time.Duration) {}
+
+	return ctx, client, srv.Close
+}
+
+func TestSetBootSourceByTypeWaitsForJob(t *testing.T) {
+	iDRAC := &fakeIDRAC{
+		importStatus: http.StatusAccepted,
+		location:     "/redfish/v1/TaskService/Tasks/JID_001",
+		jobStates:    []string{"Scheduled", "Running", "Completed"},
+	}
+	ctx, client, cleanup := newTestIDRACClient(t, iDRAC, 5)
+	defer cleanup()
+
+	require.NoError(t, client.SetBootSourceByType(ctx))
+	assert.Equal(t, 2, iDRAC.jobPolls)
+}
+
+func TestSetBootSourceJobFailed(t *testing.T) {
+	iDRAC := &fakeIDRAC{
+		importStatus: http.StatusAccepted,
+		location:     "/redfish/v1/TaskService/Tasks/JID_001",
+		jobStates:    []string{"Running", "Failed"},
+	}
+	ctx, client, cleanup := newTestIDRACClient(t, iDRAC, 5)
+	defer cleanup()
+
+	err := client.SetBootSource(ctx, boot.SourceCD, true)
+	assert.Equal(t, redfish.ErrRedfishClient{
+		Message: "iDRAC job JID_001 finished in state Failed: Job state Failed",
+	}, err)
+}
+
+func TestSetBootSourceJobRetriesExceeded(t *testing.T) {
+	iDRAC := &fakeIDRAC{
+		importStatus: http.StatusAccepted,
+		location:     "/redfish/v1/TaskService/Tasks/JID_001",
+		jobStates:    []string{"Running"},
+	}
+	ctx, client, cleanup := newTestIDRACClient(t, iDRAC, 1)
+	defer cleanup()
+
+	err := client.SetBootSourceByType(ctx)
+	assert.Equal(t, redfish.ErrOperationRetriesExceeded{What: "wait for iDRAC job JID_001", Retries: 1}, err)
+}
+
+func TestSetBootSourceNoJobLocation(t *testing.T) {
+	iDRAC := &fakeIDRAC{importStatus: http.StatusAccepted}
+	ctx, client, cleanup := newTestIDRACClient(t, iDRAC, 1)
+	defer cleanup()
+
+	assert.NoError(t, client.SetBootSourceByType(ctx))
+	assert.Equal(t, 0, iDRAC.jobPolls)
+}
+
+func TestSetBootSourceImportRejected(t *testing.T) {
+	iDRAC := &fakeIDRAC{importStatus: http.StatusBadRequest}
+	ctx, client, cleanup := newTestIDRACClient(t, iDRAC, 1)
+	defer cleanup()
+
+	err := client.SetBootSourceByType(ctx)
+	assert.Equal(t, redfish.ErrRedfishClient{Message: "Unable to set boot device. A job is already running."}, err)
+}
@@ -56,13 +56,12 @@ func (b baremetalHost) DoRemoteDirect(settings *environment.AirshipCTLSettings)
 		return ErrMissingBootstrapInfoOption{What: "isoURL"}
 	}
 
-	err = b.SetVirtualMedia(b.Context, remoteConfig.IsoURL)
+	attachISO, err := AttachISO(remoteConfig.IsoURL)
 	if err != nil {
 		return err
 	}
 
-	err = b.SetBootSourceByType(b.Context)
-	if err != nil {
+	if err = attachISO(b.Context, b.Client); err != nil {
 		return err
 	}
 
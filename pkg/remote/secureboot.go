@@ -0,0 +1,130 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// HostSecureBoot is the Secure Boot state of a host, or the error that prevented retrieving it.
+type HostSecureBoot struct {
+	HostName string
+	State    boot.SecureBoot
+	Err      error
+}
+
+// SecureBootStatus retrieves the Secure Boot state of all hosts of the manager, querying at most maxWorkers BMCs at a
+// time, see RunAction. The states are returned in the order of the hosts.
+func (m *Manager) SecureBootStatus(maxWorkers int) []HostSecureBoot {
+	states := make([]HostSecureBoot, len(m.Hosts))
+	m.forEachHost(maxWorkers, func(i int, host baremetalHost) {
+		state, err := host.SecureBoot(host.Context)
+		states[i] = HostSecureBoot{HostName: host.HostName, State: state, Err: err}
+	})
+	return states
+}
+
+// SetSecureBoot returns an action that enables or disables Secure Boot on a host. Hosts already in that state are
+// left alone. Secure Boot changes take effect on the next boot: with reboot, hosts that are powered on and whose
+// change is pending are rebooted, hosts that are powered off apply it once powered on.
+func SetSecureBoot(enabled bool, reboot bool) HostAction {
+	return func(ctx context.Context, client Client) error {
+		state, err := client.SecureBoot(ctx)
+		if err != nil {
+			return err
+		}
+
+		if state.Enabled != enabled {
+			log.Debugf("Setting Secure Boot of node '%s' to %s.", client.NodeID(), secureBootState(enabled))
+			if err = client.SetSecureBoot(ctx, enabled); err != nil {
+				return err
+			}
+		}
+
+		// The change may also be pending from an earlier run that didn't reboot the host
+		if !reboot || state.Active == enabled {
+			return nil
+		}
+
+		status, err := client.SystemPowerStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if status != power.StatusOn {
+			log.Debugf("Node '%s' is powered off, Secure Boot changes apply once it is powered on.", client.NodeID())
+			return nil
+		}
+		return client.RebootSystem(ctx)
+	}
+}
+
+// secureBootState describes whether Secure Boot is enabled.
+func secureBootState(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// PrintSecureBoot writes the Secure Boot states of hosts to w in the given format, see OutputTable and OutputJSON.
+func PrintSecureBoot(w io.Writer, states []HostSecureBoot, format string) error {
+	switch format {
+	case OutputTable:
+		tw := util.NewTabWriter(w)
+		fmt.Fprintln(tw, "HOST\tSECURE BOOT\tCURRENT BOOT\tMODE\tDETAILS")
+		for _, host := range states {
+			if host.Err != nil {
+				fmt.Fprintf(tw, "%s\t\t\t\t%v\n", host.HostName, host.Err)
+				continue
+			}
+
+			details := ""
+			if host.State.Pending() {
+				details = "reboot pending"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", host.HostName, secureBootState(host.State.Enabled),
+				secureBootState(host.State.Active), host.State.Mode, details)
+		}
+		return tw.Flush()
+	case OutputJSON:
+		type report struct {
+			Host string `json:"host"`
+			*boot.SecureBoot
+			Error string `json:"error,omitempty"`
+		}
+		reports := make([]report, len(states))
+		for i := range states {
+			reports[i] = report{Host: states[i].HostName}
+			if states[i].Err != nil {
+				reports[i].Error = states[i].Err.Error()
+				continue
+			}
+			reports[i].SecureBoot = &states[i].State
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(reports)
+	default:
+		return ErrInvalidOutputFormat{Format: format, Formats: []string{OutputTable, OutputJSON}}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+func TestSetSecureBoot(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       boot.SecureBoot
+		reboot      bool
+		powerStatus power.Status
+		expectSet   bool
+		expectBoot  bool
+	}{
+		{
+			name:   "already-enabled",
+			state:  boot.SecureBoot{Enabled: true, Active: true},
+			reboot: true,
+		},
+		{
+			name:      "without-reboot",
+			state:     boot.SecureBoot{},
+			expectSet: true,
+		},
+		{
+			name:        "reboot-powered-on",
+			state:       boot.SecureBoot{},
+			reboot:      true,
+			powerStatus: power.StatusOn,
+			expectSet:   true,
+			expectBoot:  true,
+		},
+		{
+			name:        "reboot-powered-off",
+			state:       boot.SecureBoot{},
+			reboot:      true,
+			powerStatus: power.StatusOff,
+			expectSet:   true,
+		},
+		{
+			name:        "pending",
+			state:       boot.SecureBoot{Enabled: true},
+			reboot:      true,
+			powerStatus: power.StatusOn,
+			expectBoot:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+			require.NoError(t, err)
+
+			rMock.On("NodeID").Maybe().Return(systemID)
+			rMock.On("SecureBoot", ctx).Once().Return(tt.state, nil)
+			if tt.expectSet {
+				rMock.On("SetSecureBoot", ctx, true).Once().Return(nil)
+			}
+			if tt.powerStatus != power.StatusUnknown {
+				rMock.On("SystemPowerStatus", ctx).Once().Return(tt.powerStatus, nil)
+			}
+			if tt.expectBoot {
+				rMock.On("RebootSystem", ctx).Once().Return(nil)
+			}
+
+			assert.NoError(t, SetSecureBoot(true, tt.reboot)(ctx, rMock))
+			rMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestManagerSecureBootStatus(t *testing.T) {
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+	rMock.On("SecureBoot", ctx).Once().Return(boot.SecureBoot{Enabled: true, Mode: "UserMode"}, nil)
+
+	m := &Manager{Hosts: []baremetalHost{{rMock, ctx, redfishURL, "node01", username, password}}}
+	assert.Equal(t, []HostSecureBoot{
+		{HostName: "node01", State: boot.SecureBoot{Enabled: true, Mode: "UserMode"}},
+	}, m.SecureBootStatus(1))
+}
+
+func TestPrintSecureBoot(t *testing.T) {
+	states := []HostSecureBoot{
+		{HostName: "node01", State: boot.SecureBoot{Enabled: true, Active: true, Mode: "UserMode"}},
+		{HostName: "node02", State: boot.SecureBoot{Enabled: true, Mode: "UserMode"}},
+		{HostName: "node03", Err: redfish.ErrSecureBootNotSupported{NodeID: "System.1"}},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintSecureBoot(buf, states, OutputTable))
+	assert.Equal(t, `HOST     SECURE BOOT   CURRENT BOOT   MODE       DETAILS
+node01   enabled       enabled        UserMode   
+node02   enabled       disabled       UserMode   reboot pending
+node03                                           the BMC of system[System.1] doesn't support managing UEFI Secure Boot
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintSecureBoot(buf, states, OutputJSON))
+	assert.JSONEq(t, `[
+		{"host": "node01", "enabled": true, "active": true, "mode": "UserMode"},
+		{"host": "node02", "enabled": true, "active": false, "mode": "UserMode"},
+		{"host": "node03", "error": "the BMC of system[System.1] doesn't support managing UEFI Secure Boot"}
+	]`, buf.String())
+
+	assert.Equal(t, ErrInvalidOutputFormat{Format: "csv", Formats: []string{OutputTable, OutputJSON}},
+		PrintSecureBoot(buf, states, "csv"))
+}
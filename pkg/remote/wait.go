@@ -0,0 +1,118 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+const (
+	// DefaultProvisionTimeout is how long to wait for a host to be provisioned unless configured otherwise.
+	DefaultProvisionTimeout = 30 * time.Minute
+
+	// defaultProvisionPollInterval is how often the state of a host is checked while waiting for it.
+	defaultProvisionPollInterval = 10 * time.Second
+)
+
+// ProvisionWaiter waits for hosts booted by remote direct to join the cluster of the current context as Ready
+// nodes. The API server of that cluster usually runs on the host itself, so failing to reach it is treated as the
+// host still booting.
+type ProvisionWaiter struct {
+	Client       kubernetes.Interface
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Out receives a line every time the state of the host changes
+	Out io.Writer
+	// Check, if set, is called on every poll and aborts the wait with the error it returns, e.g. EventMonitor.Poll
+	Check func() error
+}
+
+// NewProvisionWaiter returns a ProvisionWaiter that reports progress to stdout.
+func NewProvisionWaiter(clientSet kubernetes.Interface, timeout time.Duration) *ProvisionWaiter {
+	return &ProvisionWaiter{
+		Client:       clientSet,
+		Timeout:      timeout,
+		PollInterval: defaultProvisionPollInterval,
+		Out:          os.Stdout,
+	}
+}
+
+// WaitForNode blocks until the node named nodeName is Ready, or returns ErrProvisionTimeout once the timeout of
+// the waiter expired.
+func (w *ProvisionWaiter) WaitForNode(nodeName string) error {
+	start := time.Now()
+	var last string
+	for {
+		state, ready, err := w.nodeState(nodeName)
+		if err != nil {
+			return err
+		}
+
+		elapsed := time.Since(start)
+		if state != last {
+			fmt.Fprintf(w.Out, "[%s] Node '%s': %s\n", elapsed.Round(time.Second), nodeName, state)
+			last = state
+		}
+
+		if w.Check != nil && !ready {
+			if err = w.Check(); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case ready:
+			return nil
+		case elapsed >= w.Timeout:
+			return ErrProvisionTimeout{Node: nodeName, Timeout: w.Timeout, State: state}
+		}
+		time.Sleep(w.PollInterval)
+	}
+}
+
+// nodeState describes the state of a node and reports whether it is Ready.
+func (w *ProvisionWaiter) nodeState(nodeName string) (string, bool, error) {
+	node, err := w.Client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return "waiting for the node to join the cluster", false, nil
+	case err != nil && client.IsTransient(err):
+		return "waiting for the API server to be reachable", false, nil
+	case err != nil:
+		return "", false, err
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != corev1.NodeReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return "node is ready", true, nil
+		}
+		return fmt.Sprintf("node is not ready: %s", condition.Message), false, nil
+	}
+
+	return "node is not ready yet", false, nil
+}
@@ -0,0 +1,110 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func node(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready, Message: "kubelet is starting"},
+			},
+		},
+	}
+}
+
+func TestWaitForNode(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+
+	// The API server is unreachable at first, then the node joins the cluster and becomes ready
+	gets := 0
+	clientSet.PrependReactor("get", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		switch gets {
+		case 1:
+			return true, nil, syscall.ECONNREFUSED
+		case 2:
+			return false, nil, nil
+		case 3:
+			return true, node("node01", corev1.ConditionFalse), nil
+		default:
+			return true, node("node01", corev1.ConditionTrue), nil
+		}
+	})
+
+	out := &bytes.Buffer{}
+	waiter := NewProvisionWaiter(clientSet, time.Minute)
+	waiter.PollInterval = time.Millisecond
+	waiter.Out = out
+
+	assert.NoError(t, waiter.WaitForNode("node01"))
+	assert.Equal(t, 4, gets)
+	for _, state := range []string{
+		"waiting for the API server to be reachable",
+		"waiting for the node to join the cluster",
+		"node is not ready: kubelet is starting",
+		"node is ready",
+	} {
+		assert.Contains(t, out.String(), "Node 'node01': "+state)
+	}
+}
+
+func TestWaitForNodeTimeout(t *testing.T) {
+	waiter := NewProvisionWaiter(fake.NewSimpleClientset(node("node01", corev1.ConditionFalse)), 0)
+	waiter.Out = &bytes.Buffer{}
+
+	err := waiter.WaitForNode("node01")
+	assert.Equal(t, ErrProvisionTimeout{
+		Node:  "node01",
+		State: "node is not ready: kubelet is starting",
+	}, err)
+}
+
+func TestWaitForNodeCheckFailed(t *testing.T) {
+	expectedErr := ErrBMCEventFailure{Host: "node01", Message: "No bootable device."}
+	waiter := NewProvisionWaiter(fake.NewSimpleClientset(), time.Minute)
+	waiter.Out = &bytes.Buffer{}
+	waiter.Check = func() error { return expectedErr }
+
+	assert.Equal(t, expectedErr, waiter.WaitForNode("node01"))
+}
+
+func TestWaitForNodeError(t *testing.T) {
+	expectedErr := errors.New("forbidden")
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("get", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, expectedErr
+	})
+
+	waiter := NewProvisionWaiter(clientSet, time.Minute)
+	waiter.Out = &bytes.Buffer{}
+
+	assert.Equal(t, expectedErr, waiter.WaitForNode("node01"))
+}
@@ -0,0 +1,112 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// encryptedHeader prefixes the data encrypted with a passphrase
+	encryptedHeader = "AIRSHIPCTL-BACKUP-ENC-V1\n"
+
+	saltSize = 16
+	keySize  = 32
+)
+
+// Encrypt encrypts data with an AES-256 key derived from passphrase. The
+// result consists of a header, the salt, the nonce and the ciphertext.
+func Encrypt(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := passphraseCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(encryptedHeader), salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, data, nil), nil
+}
+
+// IsEncrypted reports whether data was encrypted by Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedHeader))
+}
+
+// Decrypt reverses Encrypt. An ErrDecryptionFailed is returned if data
+// wasn't encrypted by Encrypt or passphrase is wrong.
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, ErrDecryptionFailed{Reason: "data is not encrypted"}
+	}
+	data = data[len(encryptedHeader):]
+	if len(data) < saltSize {
+		return nil, ErrDecryptionFailed{Reason: "encrypted data is truncated"}
+	}
+	aead, err := passphraseCipher(passphrase, data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecryptionFailed{Reason: "encrypted data is truncated"}
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed{Reason: "wrong passphrase or corrupted data"}
+	}
+	return plain, nil
+}
+
+// ReadPassphraseFile returns the first line of path, or an empty string if
+// path is empty
+func ReadPassphraseFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.SplitN(string(data), "\n", 2)[0], nil
+}
+
+// passphraseCipher derives an AES-256 key from passphrase and salt and
+// returns an AEAD cipher using it
+func passphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
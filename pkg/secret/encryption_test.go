@@ -0,0 +1,63 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package secret_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	data := []byte("etcd snapshot")
+	encrypted, err := secret.Encrypt(data, "passphrase")
+	require.NoError(t, err)
+	assert.True(t, secret.IsEncrypted(encrypted))
+	assert.False(t, secret.IsEncrypted(data))
+	assert.NotContains(t, string(encrypted), string(data))
+
+	decrypted, err := secret.Decrypt(encrypted, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = secret.Decrypt(encrypted, "wrong")
+	assert.Equal(t, secret.ErrDecryptionFailed{Reason: "wrong passphrase or corrupted data"}, err)
+	_, err = secret.Decrypt(encrypted[:30], "passphrase")
+	assert.Equal(t, secret.ErrDecryptionFailed{Reason: "encrypted data is truncated"}, err)
+	_, err = secret.Decrypt(data, "passphrase")
+	assert.Equal(t, secret.ErrDecryptionFailed{Reason: "data is not encrypted"}, err)
+}
+
+func TestReadPassphraseFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airship-passphrase")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "passphrase")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first line\nsecond line\n"), 0600))
+
+	passphrase, err := secret.ReadPassphraseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first line", passphrase)
+
+	passphrase, err = secret.ReadPassphraseFile("")
+	require.NoError(t, err)
+	assert.Empty(t, passphrase)
+}
@@ -0,0 +1,30 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// ErrFileLocked is returned when a lock on a file can't be acquired in time
+type ErrFileLocked struct {
+	Path     string
+	LockPath string
+}
+
+func (e ErrFileLocked) Error() string {
+	return fmt.Sprintf("Timed out waiting for lock on %s. If no other airshipctl process is running, remove %s.",
+		e.Path, e.LockPath)
+}
@@ -0,0 +1,134 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// LockFileSuffix is appended to the name of a file to get the name of its lock file
+	LockFileSuffix = ".lock"
+
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// LockFile acquires an advisory lock on filename by exclusively creating
+// filename.lock next to it, the same convention used by kubectl for kubeconfig
+// files. If the lock is held by someone else, LockFile retries until timeout
+// expires. A lock file left behind by a process which no longer exists is
+// removed. The returned function releases the lock.
+func LockFile(filename string, timeout time.Duration) (func() error, error) {
+	lockPath := filename + LockFileSuffix
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			// Record the owner so that stale locks can be detected
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath) //nolint:errcheck
+				return nil, err
+			}
+			return func() error { return os.Remove(lockPath) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if removeStaleLock(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrFileLocked{Path: filename, LockPath: lockPath}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// removeStaleLock removes the lock file at lockPath if the process recorded in
+// it doesn't exist anymore, e.g. because it was killed while holding the lock.
+// Lock files without an owner, such as the ones of kubectl, are kept. It
+// returns whether the lock file was removed.
+func removeStaleLock(lockPath string) bool {
+	pid, ok := lockOwner(lockPath)
+	if !ok || syscall.Kill(pid, 0) != syscall.ESRCH {
+		return false
+	}
+	// Keep the lock if another process replaced the stale one in between
+	if owner, ok := lockOwner(lockPath); !ok || owner != pid {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
+
+// lockOwner returns the pid recorded in the lock file at lockPath
+func lockOwner(lockPath string) (int, bool) {
+	data, err := ioutil.ReadFile(lockPath)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid, err == nil && pid > 0
+}
+
+// WriteFileAtomic writes data to filename so that readers observe either the
+// old or the new content, never a partially written file. The data is first
+// written to a temporary file in the same directory which is then renamed
+// over filename.
+func WriteFileAtomic(filename string, data []byte, mode os.FileMode) error {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	// Cleanup is a no-op once the file has been renamed
+	defer os.Remove(tmpName) //nolint:errcheck
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+}
@@ -0,0 +1,102 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util_test
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestLockFile(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "test-lock")
+	defer cleanup(t)
+
+	testFile := filepath.Join(testDir, "config")
+
+	unlock, err := util.LockFile(testFile, time.Second)
+	require.NoError(t, err)
+	assert.FileExists(t, testFile+util.LockFileSuffix)
+
+	// A second lock must time out while the first one is held
+	_, err = util.LockFile(testFile, 0)
+	assert.Equal(t, util.ErrFileLocked{Path: testFile, LockPath: testFile + util.LockFileSuffix}, err)
+
+	require.NoError(t, unlock())
+	_, err = os.Stat(testFile + util.LockFileSuffix)
+	assert.True(t, os.IsNotExist(err))
+
+	unlock, err = util.LockFile(testFile, 0)
+	require.NoError(t, err)
+	assert.NoError(t, unlock())
+}
+
+func TestLockFileStale(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "test-lock")
+	defer cleanup(t)
+
+	testFile := filepath.Join(testDir, "config")
+	lockPath := testFile + util.LockFileSuffix
+
+	// The lock of a process which doesn't exist is taken over
+	require.NoError(t, ioutil.WriteFile(lockPath, []byte(strconv.Itoa(math.MaxInt32)), 0600))
+	unlock, err := util.LockFile(testFile, 0)
+	require.NoError(t, err)
+	owner, err := ioutil.ReadFile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid()), string(owner))
+	require.NoError(t, unlock())
+
+	// A lock without owner is kept
+	require.NoError(t, ioutil.WriteFile(lockPath, nil, 0600))
+	_, err = util.LockFile(testFile, 0)
+	assert.Equal(t, util.ErrFileLocked{Path: testFile, LockPath: lockPath}, err)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	testDir, cleanup := testutil.TempDir(t, "test-atomic")
+	defer cleanup(t)
+
+	testFile := filepath.Join(testDir, "config")
+	require.NoError(t, ioutil.WriteFile(testFile, []byte("old content"), 0644))
+
+	require.NoError(t, util.WriteFileAtomic(testFile, []byte("new content"), 0600))
+
+	data, err := ioutil.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	info, err := os.Stat(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// No temporary files should be left behind
+	files, err := ioutil.ReadDir(testDir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	err = util.WriteFileAtomic(filepath.Join(testDir, "NonExistentDir", "config"), []byte{}, 0600)
+	assert.Error(t, err)
+}
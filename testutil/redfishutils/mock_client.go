@@ -14,11 +14,16 @@ package redfishutils
 
 import (
 	"context"
+	"io"
 
 	"github.com/stretchr/testify/mock"
 	redfishClient "opendev.org/airship/go-redfish/client"
 
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/events"
+	"opendev.org/airship/airshipctl/pkg/remote/hardware"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/raid"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 )
 
@@ -41,6 +46,72 @@ func (m *MockClient) NodeID() string {
 	return args.String(0)
 }
 
+// BIOSAttributes provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("BIOSAttributes").Return(<return values>)
+//
+//         attributes, err := client.BIOSAttributes(<args>)
+func (m *MockClient) BIOSAttributes(ctx context.Context) (map[string]interface{}, error) {
+	args := m.Called(ctx)
+	attributes, _ := args.Get(0).(map[string]interface{})
+	return attributes, args.Error(1)
+}
+
+// ConsoleLog provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("ConsoleLog").Return(<return values>)
+//
+//         err := client.ConsoleLog(<args>)
+func (m *MockClient) ConsoleLog(ctx context.Context, w io.Writer) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+// ConsoleURL provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("ConsoleURL").Return(<return values>)
+//
+//         url, err := client.ConsoleURL(<args>)
+func (m *MockClient) ConsoleURL(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+// CreateRAIDVolume provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("CreateRAIDVolume").Return(<return values>)
+//
+//         err := client.CreateRAIDVolume(<args>)
+func (m *MockClient) CreateRAIDVolume(ctx context.Context, controller string, volume raid.Volume) error {
+	args := m.Called(ctx, controller, volume)
+	return args.Error(0)
+}
+
+// DeleteRAIDVolume provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("DeleteRAIDVolume").Return(<return values>)
+//
+//         err := client.DeleteRAIDVolume(<args>)
+func (m *MockClient) DeleteRAIDVolume(ctx context.Context, controller string, volumeID string) error {
+	args := m.Called(ctx, controller, volumeID)
+	return args.Error(0)
+}
+
 // EjectVirtualMedia provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client
 // settings.
@@ -55,6 +126,47 @@ func (m *MockClient) EjectVirtualMedia(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// Events provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("Events").Return(<return values>)
+//
+//         events, err := client.Events(<args>)
+func (m *MockClient) Events(ctx context.Context) ([]events.Event, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]events.Event)
+	return result, args.Error(1)
+}
+
+// Ping provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("Ping").Return(<return values>)
+//
+//         err := client.Ping(<args>)
+func (m *MockClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// RAIDVolumes provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("RAIDVolumes").Return(<return values>)
+//
+//         volumes, err := client.RAIDVolumes(<args>)
+func (m *MockClient) RAIDVolumes(ctx context.Context, controller string) ([]raid.Volume, error) {
+	args := m.Called(ctx, controller)
+	volumes, _ := args.Get(0).([]raid.Volume)
+	return volumes, args.Error(1)
+}
+
 // RebootSystem provides a stubbed method that can be mocked to test functions that use the Redfish client without
 // making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -68,6 +180,59 @@ func (m *MockClient) RebootSystem(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// SecureBoot provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SecureBoot").Return(<return values>)
+//
+//         state, err := client.SecureBoot(<args>)
+func (m *MockClient) SecureBoot(ctx context.Context) (boot.SecureBoot, error) {
+	args := m.Called(ctx)
+	state, _ := args.Get(0).(boot.SecureBoot)
+	return state, args.Error(1)
+}
+
+// SetBIOSAttributes provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SetBIOSAttributes").Return(<return values>)
+//
+//         err := client.SetBIOSAttributes(<args>)
+func (m *MockClient) SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) error {
+	args := m.Called(ctx, attributes)
+	return args.Error(0)
+}
+
+// SetBootSource provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SetBootSource").Return(<return values>)
+//
+//         err := client.SetBootSource(<args>)
+func (m *MockClient) SetBootSource(ctx context.Context, source boot.Source, persistent bool) error {
+	args := m.Called(ctx, source, persistent)
+	return args.Error(0)
+}
+
+// HardwareInventory provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("HardwareInventory").Return(<return values>)
+//
+//         inventory, err := client.HardwareInventory(<args>)
+func (m *MockClient) HardwareInventory(ctx context.Context) (hardware.Inventory, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(hardware.Inventory), args.Error(1)
+}
+
 // SetBootSourceByType provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -81,6 +246,19 @@ func (m *MockClient) SetBootSourceByType(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// SetSecureBoot provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SetSecureBoot").Return(<return values>)
+//
+//         err := client.SetSecureBoot(<args>)
+func (m *MockClient) SetSecureBoot(ctx context.Context, enabled bool) error {
+	args := m.Called(ctx, enabled)
+	return args.Error(0)
+}
+
 // SetVirtualMedia provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -138,6 +316,32 @@ func (m *MockClient) SystemPowerStatus(ctx context.Context) (power.Status, error
 	return powerStatus, args.Error(1)
 }
 
+// VerifyCredentials provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("VerifyCredentials").Return(<return values>)
+//
+//         err := client.VerifyCredentials(<args>)
+func (m *MockClient) VerifyCredentials(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// VerifyVirtualMedia provides a stubbed method that can be mocked to test functions that use the
+// Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("VerifyVirtualMedia").Return(<return values>)
+//
+//         err := client.VerifyVirtualMedia(<args>)
+func (m *MockClient) VerifyVirtualMedia(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 // NewClient returns a mocked Redfish client in order to test functions that use the Redfish client without making any
 // Redfish API calls.
 func NewClient(redfishURL string, insecure bool, useProxy bool, username string,
@@ -16,7 +16,8 @@ import (
 // SetupTestFs help manufacture a fake file system for testing purposes. It
 // will iterate over the files in fixtureDir, which is a directory relative
 // to the tests themselves, and will write each of those files (preserving
-// names) to an in-memory file system and return that fs
+// names) to an in-memory file system and return that fs. Subdirectories are
+// skipped, they hold the fixtures of other tests.
 func SetupTestFs(t *testing.T, fixtureDir string) document.FileSystem {
 	t.Helper()
 
@@ -25,6 +26,9 @@ func SetupTestFs(t *testing.T, fixtureDir string) document.FileSystem {
 	files, err := ioutil.ReadDir(fixtureDir)
 	require.NoErrorf(t, err, "Failed to read fixture directory %s", fixtureDir)
 	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
 		fileName := file.Name()
 		filePath := filepath.Join(fixtureDir, fileName)
 
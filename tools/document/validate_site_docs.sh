@@ -75,6 +75,8 @@ kind: Config
 managementConfiguration:
   default:
     insecure: true
+    requestRetries: 3
+    requestRetryDelay: 10
     systemActionRetries: 30
     systemRebootDelay: 30
     type: redfish